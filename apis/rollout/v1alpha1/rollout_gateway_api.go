@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 )
 
 type HTTPRouteMatch struct {
@@ -59,3 +60,25 @@ type HTTPRouteRule struct {
 	// Filter defines a filter for the canary service.
 	Filter HTTPRouteFilter `json:"filter,omitempty"`
 }
+
+type GRPCRouteMatch struct {
+	// Method specifies a gRPC request service/method matcher. If this field is
+	// not specified, all services and methods will match.
+	//
+	// +optional
+	Method *gatewayapiv1alpha2.GRPCMethodMatch `json:"method,omitempty"`
+	// Headers specifies gRPC request header matchers. Multiple match values are
+	// ANDed together, meaning, a request MUST match all the specified headers
+	// to select the route.
+	//
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	// +kubebuilder:validation:MaxItems=16
+	Headers []gatewayapiv1alpha2.GRPCHeaderMatch `json:"headers,omitempty"`
+}
+
+type GRPCRouteRule struct {
+	// Matches define conditions used for matching the incoming gRPC requests to canary service.
+	Matches []GRPCRouteMatch `json:"matches,omitempty"`
+}
@@ -213,6 +213,10 @@ const (
 	// RolloutStepPending indicates that the step is pending.
 	RolloutStepPending RolloutStepState = "Pending"
 
+	// RolloutStepPreRunHook indicates that the step is in the pre-run hook, which
+	// gates the rolloutRun before any workload or traffic is changed.
+	RolloutStepPreRunHook RolloutStepState = RolloutStepState(PreRunHook)
+
 	// RolloutStepPreCanaryStepHook indicates that the step is in the pre-canary hook.
 	RolloutStepPreCanaryStepHook RolloutStepState = RolloutStepState(PreCanaryStepHook)
 
@@ -228,11 +232,16 @@ const (
 	// RolloutStepPostBatchStepHook indicates that the step is in the post-batch hook.
 	RolloutStepPostBatchStepHook RolloutStepState = RolloutStepState(PostBatchStepHook)
 
+	// RolloutStepHolding indicates that the canary step is held after running until
+	// it is promoted or ended, only used if holdAtCanary is set.
+	RolloutStepHolding RolloutStepState = "Holding"
+
 	// RolloutStepSucceeded indicates that the step is completed.
 	RolloutStepSucceeded RolloutStepState = "Succeeded"
 
 	// RolloutStepResourceRecycling indicates that the step is recycling resources.
-	// In Canary strategy, it occurs after the user confirms (Paused).
+	// In Canary strategy, it occurs after the user confirms (Paused), or right after
+	// the PostCanaryStepHook if pauseAfter is false.
 	// In Batch strategy, it occurs before the PreBatchStepHook.
 	RolloutStepResourceRecycling RolloutStepState = "ResourceRecycling"
 )
@@ -39,6 +39,27 @@ type RolloutWebhook struct {
 	// allowed values are Ignore or Fail. Defaults to Ignore.
 	// +optional
 	FailurePolicy FailurePolicyType `json:"failurePolicy,omitempty"`
+	// UnreachablePolicy defines how the failures caused by unreachable webhook endpoint
+	// (e.g. DNS resolution failure, connection refused) are handled, other failures are
+	// still handled by FailurePolicy. Fail means the rolloutRun is terminated, Ignore means
+	// the webhook is treated as passed with a warning event. If not set, unreachable
+	// failures are handled by FailurePolicy.
+	// +optional
+	UnreachablePolicy FailurePolicyType `json:"unreachablePolicy,omitempty"`
+	// TargetMode defines how the webhook is called with multiple targets in a step.
+	// Step means it is called once with all targets of the step, Target means it is
+	// called once for each target with only the target in review. Target mode is only
+	// supported by step hooks. Defaults to Step.
+	// +optional
+	TargetMode WebhookTargetMode `json:"targetMode,omitempty"`
+	// TargetAggregation defines how the results of each target are aggregated in Target
+	// mode. AllPass means the step waits until all targets pass, a failing target holds
+	// the step as in Step mode. PerTarget means a failing target is rejected on its own:
+	// it is excluded from the rest of the rolloutRun, e.g. its canary is recycled and its
+	// workload is not upgraded in later batches, while the passing targets proceed.
+	// Defaults to AllPass.
+	// +optional
+	TargetAggregation WebhookTargetAggregation `json:"targetAggregation,omitempty"`
 	// Properties provide additional data for webhook.
 	// +optional
 	Properties map[string]string `json:"properties,omitempty"`
@@ -58,6 +79,28 @@ const (
 	Fail FailurePolicyType = "Fail"
 )
 
+// WebhookTargetMode specifies how a webhook is called with multiple targets.
+// +kubebuilder:validation:Enum=Step;Target
+type WebhookTargetMode string
+
+const (
+	// WebhookPerStep means the webhook is called once with all targets of step.
+	WebhookPerStep WebhookTargetMode = "Step"
+	// WebhookPerTarget means the webhook is called once for each target of step.
+	WebhookPerTarget WebhookTargetMode = "Target"
+)
+
+// WebhookTargetAggregation specifies how the results of each target are aggregated.
+// +kubebuilder:validation:Enum=AllPass;PerTarget
+type WebhookTargetAggregation string
+
+const (
+	// WebhookAllPass means all targets are required to pass.
+	WebhookAllPass WebhookTargetAggregation = "AllPass"
+	// WebhookPerTargetGating means each target is gated by its own result.
+	WebhookPerTargetGating WebhookTargetAggregation = "PerTarget"
+)
+
 // WebhookClientConfig contains the information to make a TLS
 // connection with the webhook
 type WebhookClientConfig struct {
@@ -93,6 +136,14 @@ type WebhookClientConfig struct {
 	// +optional
 	CABundle []byte `json:"caBundle,omitempty" protobuf:"bytes,2,opt,name=caBundle"`
 
+	// ClientCertSecretName is the name of Secret in the namespace of rollout holding
+	// the client certificate for mutual TLS with the webhook. The Secret contains the
+	// PEM encoded certificate and key in `tls.crt` and `tls.key`, and optionally a CA
+	// bundle in `ca.crt` which takes precedence over caBundle. Changes of the Secret
+	// are reloaded by the running webhook.
+	// +optional
+	ClientCertSecretName string `json:"clientCertSecretName,omitempty"`
+
 	// TimeoutSeconds specifies the timeout for this webhook. After the timeout passes,
 	// the webhook call will be ignored or the API call will fail based on the
 	// failure policy.
@@ -152,6 +203,12 @@ type RolloutWebhookReviewSpec struct {
 type RolloutWebhookReviewCanary struct {
 	// Targets contains the list of rollout run step targets
 	Targets []RolloutRunStepTarget `json:"targets,omitempty"`
+	// State is the canary step state transitioned to, only set in notification
+	// +optional
+	State RolloutStepState `json:"state,omitempty"`
+	// Completion is the outcome of canary, only set in CanaryCompletedHook
+	// +optional
+	Completion *CanaryCompletionStatus `json:"completion,omitempty"`
 	// Properties stores custom parameters from the webhook to be passed to the server side
 	Properties map[string]string `json:"properties,omitempty"`
 }
@@ -169,10 +226,36 @@ type RolloutWebhookReviewBatch struct {
 type HookType string
 
 const (
+	// PreRunHook is called before anything of the rolloutRun is changed. If it
+	// fails, the rolloutRun is canceled instead of waiting for retry.
+	PreRunHook         HookType = "PreRunHook"
 	PreCanaryStepHook  HookType = "PreCanaryStepHook"
 	PostCanaryStepHook HookType = "PostCanaryStepHook"
 	PreBatchStepHook   HookType = "PreBatchStepHook"
 	PostBatchStepHook  HookType = "PostBatchStepHook"
+	// CanaryCompletedHook is called once canary is recycled or failed with a
+	// terminal error. Its failures never block the rolloutRun, they are
+	// ignored after retried failureThreshold times.
+	CanaryCompletedHook HookType = "CanaryCompletedHook"
+	// CanaryTrafficReadyHook is called once canary traffic is forked and
+	// verified, before canary is analyzed, so that integrators can enable
+	// features for the canary cohort while its traffic is live. Blue/green
+	// canary calls it after traffic is cut over.
+	CanaryTrafficReadyHook HookType = "CanaryTrafficReadyHook"
+	// CanaryTrafficRevertHook is called in recycle before any traffic of canary
+	// is restored, drained or reverted, only if CanaryTrafficReadyHook has been
+	// called, so that integrators can disable what is enabled for the canary
+	// cohort.
+	CanaryTrafficRevertHook HookType = "CanaryTrafficRevertHook"
+	// CanaryNotificationHook is the hook type of canary notification, it is only
+	// used in the review payload and can not be set in webhooks.
+	CanaryNotificationHook HookType = "CanaryNotification"
+	// StepGuardHook is the hook type of webhook guards of step, it is only used
+	// in the review payload and can not be set in webhooks.
+	StepGuardHook HookType = "StepGuard"
+	// CanaryWarmUpHook is the hook type of the warm-up webhook of canary, it is
+	// only used in the review payload and can not be set in webhooks.
+	CanaryWarmUpHook HookType = "CanaryWarmUp"
 )
 
 type RolloutWebhookReviewStatus struct {
@@ -0,0 +1,133 @@
+// Copyright 2024 The KusionStack Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// parameterReference matches a ${var} reference to RolloutRun parameters.
+var parameterReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// parameterResolver substitutes parameter references and records the
+// referenced parameters.
+type parameterResolver struct {
+	params  map[string]string
+	used    map[string]string
+	missing map[string]bool
+}
+
+func (r *parameterResolver) resolve(s string) string {
+	return parameterReference.ReplaceAllStringFunc(s, func(ref string) string {
+		name := parameterReference.FindStringSubmatch(ref)[1]
+		value, ok := r.params[name]
+		if !ok {
+			r.missing[name] = true
+			return ref
+		}
+		r.used[name] = value
+		return value
+	})
+}
+
+func (r *parameterResolver) resolveMap(m map[string]string) {
+	for k, v := range m {
+		m[k] = r.resolve(v)
+	}
+}
+
+func (r *parameterResolver) resolveMetadataPatch(patch *MetadataPatch) {
+	if patch == nil {
+		return
+	}
+	r.resolveMap(patch.Labels)
+	r.resolveMap(patch.Annotations)
+}
+
+// resolveReplicas substitutes the string replicas, the replicas are converted
+// to integer if the resolved value is an integer.
+func (r *parameterResolver) resolveReplicas(replicas *intstr.IntOrString) {
+	if replicas.Type != intstr.String {
+		return
+	}
+	value := r.resolve(replicas.StrVal)
+	if n, err := strconv.Atoi(value); err == nil {
+		*replicas = intstr.FromInt(n)
+	} else {
+		*replicas = intstr.FromString(value)
+	}
+}
+
+func (r *parameterResolver) resolveTargets(targets []RolloutRunStepTarget) {
+	for i := range targets {
+		r.resolveReplicas(&targets[i].Replicas)
+	}
+}
+
+// ResolveParameters returns a copy of spec in which the ${var} references are
+// substituted by params. The substituted fields are:
+//   - url and properties of webhooks
+//   - replicas and properties of canary and batch steps
+//   - replicas of canary variants
+//   - podTemplateMetadataPatch and objectMetadataPatch of canary
+//   - url and properties of canary notifications
+//
+// The values of referenced parameters and the sorted names of the missing ones
+// are also returned, the references to missing parameters are left as they are.
+func (spec *RolloutRunSpec) ResolveParameters(params map[string]string) (*RolloutRunSpec, map[string]string, []string) {
+	r := &parameterResolver{
+		params:  params,
+		used:    map[string]string{},
+		missing: map[string]bool{},
+	}
+
+	resolved := spec.DeepCopy()
+	for i := range resolved.Webhooks {
+		webhook := &resolved.Webhooks[i]
+		webhook.ClientConfig.URL = r.resolve(webhook.ClientConfig.URL)
+		r.resolveMap(webhook.Properties)
+	}
+	if canary := resolved.Canary; canary != nil {
+		r.resolveTargets(canary.Targets)
+		for i := range canary.Variants {
+			r.resolveReplicas(&canary.Variants[i].Replicas)
+		}
+		r.resolveMap(canary.Properties)
+		r.resolveMetadataPatch(canary.PodTemplateMetadataPatch)
+		r.resolveMetadataPatch(canary.ObjectMetadataPatch)
+		for i := range canary.Notifications {
+			notification := &canary.Notifications[i]
+			notification.ClientConfig.URL = r.resolve(notification.ClientConfig.URL)
+			r.resolveMap(notification.Properties)
+		}
+	}
+	if batch := resolved.Batch; batch != nil {
+		for i := range batch.Batches {
+			r.resolveTargets(batch.Batches[i].Targets)
+			r.resolveMap(batch.Batches[i].Properties)
+		}
+	}
+
+	missing := make([]string, 0, len(r.missing))
+	for name := range r.missing {
+		missing = append(missing, name)
+	}
+	sort.Strings(missing)
+	return resolved, r.used, missing
+}
@@ -15,6 +15,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -29,6 +30,7 @@ import (
 // +kubebuilder:printcolumn:name="OWNER",type="string",JSONPath=".metadata.ownerReferences[0].name"
 // +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
 // +kubebuilder:printcolumn:name="Canary State",type="string",JSONPath=".status.canaryStatus.state"
+// +kubebuilder:printcolumn:name="Canary Waiting",type="string",JSONPath=".status.canaryStatus.waitingReason"
 // +kubebuilder:printcolumn:name="Batch Index",type="string",JSONPath=".status.batchStatus.currentBatchIndex"
 // +kubebuilder:printcolumn:name="Batch State",type="string",JSONPath=".status.batchStatus.currentBatchState"
 // +kubebuilder:printcolumn:name="Error",type="string",JSONPath=".status.error.code"
@@ -70,6 +72,19 @@ type RolloutRunSpec struct {
 	// Batch Strategy
 	// +optional
 	Batch *RolloutRunBatchStrategy `json:"batch,omitempty"`
+
+	// Parameters are the variables substituted into the ${var} references in spec
+	// before execution, e.g. replicas of targets and url of webhooks. All referenced
+	// variables must be provided. The resolved values are recorded in status and
+	// kept for the rest of the execution.
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// Cancel cancels the rolloutRun without deleting it. The canary in progress
+	// is recycled as a rollback before the rolloutRun is canceled. Once observed,
+	// the cancellation can not be revoked by unsetting it.
+	// +optional
+	Cancel bool `json:"cancel,omitempty"`
 }
 
 type RolloutRunBatchStrategy struct {
@@ -96,16 +111,39 @@ type RolloutRunStep struct {
 	// Properties contains additional information for step
 	// +optional
 	Properties map[string]string `json:"properties,omitempty"`
+
+	// Guards are the invariants that must hold for the whole duration of the step.
+	// They are checked on every reconcile while the step is in progress, from its
+	// pre step hook to its post step hook, and the rolloutRun fails once any of
+	// them is violated.
+	// +optional
+	Guards []StepGuard `json:"guards,omitempty"`
 }
 
 type RolloutRunCanaryStrategy struct {
 	// desired target replicas
 	Targets []RolloutRunStepTarget `json:"targets"`
 
+	// Strategy is the release strategy of canary, defaults to Canary. With
+	// BlueGreen, canary is created at the full size of stable and receives no
+	// traffic while it is validated, then all traffic is switched to it at once
+	// after the post canary step hook, and switched back at once on rollback.
+	// On success, the old stable is torn down by updating it to the canary
+	// revision before canary is recycled, while canary serves all traffic.
+	// +optional
+	Strategy CanaryStrategyType `json:"strategy,omitempty"`
+
 	// traffic strategy
 	// +optional
 	Traffic *TrafficStrategy `json:"traffic,omitempty"`
 
+	// Variants are the canary variants tested simultaneously, each variant has
+	// its own canary workloads and backend, and receives the percent of traffic
+	// allocation of the same name. Canary replicas and podTemplateMetadataPatch
+	// are overridden by the ones of variant.
+	// +optional
+	Variants []CanaryVariant `json:"variants,omitempty"`
+
 	// Properties contains additional information for step
 	// +optional
 	Properties map[string]string `json:"properties,omitempty"`
@@ -113,6 +151,256 @@ type RolloutRunCanaryStrategy struct {
 	// PodTemplateMetadataPatch defines a patch for workload podTemplate metadata.
 	// +optional
 	PodTemplateMetadataPatch *MetadataPatch `json:"podTemplateMetadataPatch,omitempty"`
+
+	// ObjectMetadataPatch defines a patch for the metadata of canary workload object.
+	// The builtin rollout labels and annotations can not be overridden.
+	// +optional
+	ObjectMetadataPatch *MetadataPatch `json:"objectMetadataPatch,omitempty"`
+
+	// PodPlacementPatch defines a patch for the placement of canary pods, e.g. to
+	// spread canary pods across nodes or zones apart from stable pods. It is merged
+	// into the pod template of canary workloads, separately from the metadata patch.
+	// +optional
+	PodPlacementPatch *PodPlacementPatch `json:"podPlacementPatch,omitempty"`
+
+	// PromotionWindows defines the time windows in which the canary is allowed to be promoted.
+	// If not set, the canary can be promoted at any time.
+	// +optional
+	PromotionWindows *PromotionWindows `json:"promotionWindows,omitempty"`
+
+	// Analysis defines the metrics to check after canary traffic is routed.
+	// The canary fails if any metric is out of its threshold.
+	// +optional
+	Analysis *CanaryAnalysis `json:"analysis,omitempty"`
+
+	// PauseAfter indicates whether to pause the rollout after the post canary step hook
+	// succeeds. If false, the canary flows straight into recycling without manual resume.
+	// Defaults to true, or false if pauseBefore is set.
+	// +optional
+	PauseAfter *bool `json:"pauseAfter,omitempty"`
+
+	// PauseBefore lists the phases of canary to pause before, e.g. [forkCanary,
+	// promotion] to approve the traffic shift and the promotion while everything
+	// else runs automatically. Each phase pauses once until it is resumed, and
+	// the phase paused before is recorded in status.
+	// +optional
+	PauseBefore []CanaryPausePhase `json:"pauseBefore,omitempty"`
+
+	// HoldAtCanary keeps the canary running after its traffic is routed and analyzed,
+	// e.g. for long-lived experiments. The step stays in Holding state, where canary
+	// readiness and traffic keep being reconciled, until the hold is ended by the
+	// manual command promote, which continues the rollout, or end, which recycles the
+	// canary and cancels the rolloutRun without upgrading stable.
+	// +optional
+	HoldAtCanary bool `json:"holdAtCanary,omitempty"`
+
+	// RecycleOrder defines the order of operations when recycling canary resources.
+	// It must contain each of RevertCanaryTraffic, DeleteCanaryResource and
+	// RevertStableTraffic exactly once, and RevertCanaryTraffic must come before
+	// DeleteCanaryResource.
+	// Defaults to [RevertCanaryTraffic, DeleteCanaryResource, RevertStableTraffic].
+	// +optional
+	RecycleOrder []CanaryRecycleOperation `json:"recycleOrder,omitempty"`
+
+	// RetentionSeconds keeps the canary resources for a while after its traffic
+	// is reverted in recycle, e.g. for forensics, instead of deleting them at
+	// once. The deletion is deferred after all traffic operations of recycle,
+	// and the retention can be cut short by the end-retention command. It can
+	// not be set with ordinals.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RetentionSeconds *int32 `json:"retentionSeconds,omitempty"`
+
+	// CrashLoopCheck defines when the canary fails fast if its pods are crash looping,
+	// instead of waiting for the canary to be ready.
+	// +optional
+	CrashLoopCheck *CanaryCrashLoopCheck `json:"crashLoopCheck,omitempty"`
+
+	// ImagePullCheck enables a pre-flight before canary is scaled, which verifies the
+	// images of canary can be pulled by a short-lived pod with the image pull secrets
+	// and service account of canary, so that a bad image fails the canary in seconds
+	// instead of leaving canary pods in ImagePullBackOff.
+	// +optional
+	ImagePullCheck *CanaryImagePullCheck `json:"imagePullCheck,omitempty"`
+
+	// HealthCheckGracePeriodSeconds is the period after canary starts to be checked
+	// for readiness, during which crash looping pods and failed analysis are logged
+	// but do not fail the canary. After it, failures are handled as usual.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	HealthCheckGracePeriodSeconds *int32 `json:"healthCheckGracePeriodSeconds,omitempty"`
+
+	// ReadinessStabilization requires the ready percentage of canary replicas to
+	// stay at or above a threshold for a window before canary is considered
+	// ready, so that flapping pods do not pass the readiness check. If not set,
+	// canary is ready once all its replicas are ready.
+	// +optional
+	ReadinessStabilization *CanaryReadinessStabilization `json:"readinessStabilization,omitempty"`
+
+	// ReadinessExpression is a CEL expression deciding whether a canary workload is
+	// ready, instead of all its replicas being updated and available. It can refer
+	// to `status`, the replicas summary of canary workload such as
+	// status.updatedAvailableReplicas, and `object`, the canary workload itself,
+	// and must return a bool. It is only evaluated once the canary workload status
+	// is up to date.
+	// +optional
+	ReadinessExpression string `json:"readinessExpression,omitempty"`
+
+	// DegradedClusterGracePeriodSeconds enables the degraded mode of canary. If set, a
+	// cluster whose canary targets can not be found for longer than this period is marked
+	// degraded and skipped, and the canary proceeds in the other clusters. The skipped
+	// clusters are reconciled again once they are reachable.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	DegradedClusterGracePeriodSeconds *int32 `json:"degradedClusterGracePeriodSeconds,omitempty"`
+
+	// ReplicasFollowTrafficWeight keeps the canary replicas of each target not less
+	// than the traffic weight percent of stable replicas, e.g. a canary receiving
+	// 50% traffic has at least 50% of stable replicas. It only works if traffic
+	// weight is set.
+	// +optional
+	ReplicasFollowTrafficWeight bool `json:"replicasFollowTrafficWeight,omitempty"`
+
+	// MaxActiveDuration limits how long the canary can stay active, including the
+	// time spent paused after the post canary step hook. The configured action is
+	// taken once it is exceeded.
+	// +optional
+	MaxActiveDuration *CanaryMaxActiveDuration `json:"maxActiveDuration,omitempty"`
+
+	// Notifications are notification-only webhooks called asynchronously on each
+	// canary step state transition. Their responses and failures never block or
+	// fail the canary, the delivery results are only recorded in status.
+	// +optional
+	Notifications []CanaryNotification `json:"notifications,omitempty"`
+
+	// Bake defines an active/idle schedule of canary after it is ready. In each
+	// active window the canary is scaled up, receives traffic and is analyzed at
+	// the end of the window. Between active windows the canary traffic is reverted
+	// before the canary is scaled down to idle replicas, and it is forked again only
+	// after the canary is scaled up and ready, so no traffic is routed to a scaled
+	// down canary. The analysis runs only in active windows instead of once after
+	// traffic is routed.
+	// +optional
+	Bake *CanaryBake `json:"bake,omitempty"`
+
+	// StableMinAvailable is the minimum available replicas of each stable workload,
+	// as an absolute number or a percentage of stable replicas, required before
+	// canary traffic is routed. The canary waits with StableUnhealthy reason until
+	// stable recovers, so traffic is never shifted while stable is degraded.
+	// +optional
+	StableMinAvailable *intstr.IntOrString `json:"stableMinAvailable,omitempty"`
+
+	// StuckDeletion defines how to handle a canary workload which is still
+	// terminating a while after it is deleted in recycle, e.g. blocked by a
+	// finalizer or PVC. If not set, recycle waits until it is removed.
+	// +optional
+	StuckDeletion *CanaryStuckDeletion `json:"stuckDeletion,omitempty"`
+
+	// DriftDetection defines when a canary workload is considered drifted, i.e.
+	// it is updated by the rolloutRun in consecutive reconciles because another
+	// controller keeps mutating it, and the action taken on it. Drift is
+	// detected with the defaults if not set.
+	// +optional
+	DriftDetection *CanaryDriftDetection `json:"driftDetection,omitempty"`
+
+	// AutoRollback rolls back the canary failed with a terminal error and
+	// retries it automatically, within a budget of rollbacks per time window.
+	// Once the budget is exhausted, the rolloutRun is paused until it is reset
+	// by the reset-rollback-budget command.
+	// +optional
+	AutoRollback *CanaryAutoRollback `json:"autoRollback,omitempty"`
+
+	// Ordinals are the ordinals of StatefulSet pods updated to the canary
+	// template in place, instead of creating canary workloads, e.g. when
+	// ordinals map to shards. StatefulSet updates pods from the largest ordinal
+	// by partition, so they must be the last ordinals of each target, e.g. [3, 4]
+	// for the last two pods of 5 replicas. They are rolled back to the stable
+	// template in recycle.
+	// +optional
+	Ordinals []int32 `json:"ordinals,omitempty"`
+
+	// UpdateStrategy defines how the pods of canary workloads are turned over
+	// when canary workloads are updated to a new revision during the run. If not
+	// set, the update strategy copied from stable workload is used.
+	// +optional
+	UpdateStrategy *CanaryUpdateStrategy `json:"updateStrategy,omitempty"`
+
+	// ScaleUpStep is the number or percent of canary replicas added in each
+	// increment, the percent is of the final canary replicas of each target.
+	// Canary is scaled up increment by increment, each one is added after the
+	// previous one is ready, and traffic is forked to canary after the final
+	// increment is ready. With ordinals, each increment updates that many more
+	// ordinals in place. If not set, all canary replicas are created at once.
+	// +optional
+	ScaleUpStep *intstr.IntOrString `json:"scaleUpStep,omitempty"`
+
+	// WarmUp defines the warm-up of canary pods after they are ready and before
+	// traffic is forked to canary, so that cold pods are primed by synthetic
+	// requests instead of real traffic. If not set, traffic is forked once canary
+	// is ready.
+	// +optional
+	WarmUp *CanaryWarmUp `json:"warmUp,omitempty"`
+
+	// PreconditionRefs are the external objects that must report ready before
+	// traffic is forked to canary, e.g. a FeatureFlag or a DatabaseMigration.
+	// They are checked after canary is ready, and the rolloutRun fails if any of
+	// them is not met in time.
+	// +optional
+	PreconditionRefs []CanaryPreconditionRef `json:"preconditionRefs,omitempty"`
+
+	// Prerequisites are the external objects that must report ready before canary
+	// workloads are created, e.g. a database migration Job or a feature flag. They
+	// are checked when canary is initialized, the ones not met yet are recorded
+	// in status, and the rolloutRun fails if any of them is not met in time.
+	// +optional
+	Prerequisites []CanaryPreconditionRef `json:"prerequisites,omitempty"`
+
+	// Guards are the invariants that must hold for the whole duration of the step.
+	// They are checked on every reconcile while the step is in progress, from its
+	// pre step hook to its post step hook, and the rolloutRun fails once any of
+	// them is violated.
+	// +optional
+	Guards []StepGuard `json:"guards,omitempty"`
+
+	// States are the canary step states to go through, in the order of
+	// DefaultCanaryStepStates. Pending, Running, ResourceRecycling and Succeeded
+	// are required, since canary can be rolled back at any time, e.g. by cancel.
+	// The hook states can be left out if there is no webhook of their hook type,
+	// e.g. PreCanaryStepHook if there is no pre canary hook.
+	// Defaults to DefaultCanaryStepStates.
+	// +optional
+	States []RolloutStepState `json:"states,omitempty"`
+}
+
+// DefaultCanaryStepStates are all the states of canary step in order, the
+// implicit initial state None is not included.
+var DefaultCanaryStepStates = []RolloutStepState{
+	RolloutStepPreRunHook,
+	RolloutStepPending,
+	RolloutStepPreCanaryStepHook,
+	RolloutStepRunning,
+	RolloutStepPostCanaryStepHook,
+	RolloutStepResourceRecycling,
+	RolloutStepSucceeded,
+}
+
+// RequiredCanaryStepStates are the canary step states that can not be left out.
+var RequiredCanaryStepStates = []RolloutStepState{
+	RolloutStepPending,
+	RolloutStepRunning,
+	RolloutStepResourceRecycling,
+	RolloutStepSucceeded,
+}
+
+// CanaryStepHookStates are the canary step states calling the webhooks of
+// their hook type.
+var CanaryStepHookStates = map[RolloutStepState]HookType{
+	RolloutStepPreRunHook:         PreRunHook,
+	RolloutStepPreCanaryStepHook:  PreCanaryStepHook,
+	RolloutStepPostCanaryStepHook: PostCanaryStepHook,
 }
 
 type RolloutRunStepTarget struct {
@@ -120,6 +408,48 @@ type RolloutRunStepTarget struct {
 
 	// Replicas is the replicas of the rollout task, which represents the number of pods to be upgraded
 	Replicas intstr.IntOrString `json:"replicas"`
+
+	// ReadinessTimeoutSeconds is the maximum time to wait for the canary of this
+	// target to be ready. If not set, wait until it is ready. Only used in canary.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ReadinessTimeoutSeconds *int32 `json:"readinessTimeoutSeconds,omitempty"`
+
+	// CanaryNamespace is the namespace in which the canary workload of this target
+	// is created, e.g. for network policy isolation. It must exist before canary
+	// starts and allow the namespace of stable workload by annotation
+	// rollout.kusionstack.io/cross-namespace-allowed-from. If not set, canary is
+	// created in the namespace of stable workload. Only used in canary.
+	// +optional
+	CanaryNamespace string `json:"canaryNamespace,omitempty"`
+
+	// ConfigOverrides substitute the ConfigMaps and Secrets referenced by the pod
+	// template of the canary workload of this target. The alternate objects must
+	// exist in the canary namespace and are left untouched in recycle. Only used
+	// in canary.
+	// +optional
+	ConfigOverrides []CanaryConfigOverride `json:"configOverrides,omitempty"`
+
+	// InitContainerOverrides are merged into the init containers of the pod
+	// template of the canary workload of this target by name. Only used in
+	// canary.
+	// +optional
+	InitContainerOverrides []corev1.Container `json:"initContainerOverrides,omitempty"`
+
+	// OwnerReferences are added to the owner references of the canary workload of
+	// this target. The RolloutRun is always added as an owner if the canary
+	// workload is in the same cluster and namespace, so that it is garbage
+	// collected with the RolloutRun, but recycle still deletes it explicitly.
+	// Only used in canary.
+	// +optional
+	OwnerReferences []metav1.OwnerReference `json:"ownerReferences,omitempty"`
+
+	// PodDisruptionBudget creates a PodDisruptionBudget selecting the pods of the
+	// canary workload of this target, it is deleted in recycle. Only used in
+	// canary.
+	// +optional
+	PodDisruptionBudget *CanaryPodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
 }
 
 type RolloutRunStatus struct {
@@ -144,6 +474,29 @@ type RolloutRunStatus struct {
 	// TargetStatuses describes the referenced workloads status
 	// +optional
 	TargetStatuses []RolloutWorkloadStatus `json:"targetStatuses,omitempty"`
+	// ResolvedParameters records the values of parameters referenced in spec
+	// +optional
+	ResolvedParameters map[string]string `json:"resolvedParameters,omitempty"`
+	// RejectedTargets are the targets rejected by webhooks with PerTarget aggregation,
+	// they are excluded from the rest of rolloutRun.
+	// +optional
+	RejectedTargets []RejectedTargetStatus `json:"rejectedTargets,omitempty"`
+	// Cancellation records the cancellation requested by spec.cancel
+	// +optional
+	Cancellation *RolloutRunCancellationStatus `json:"cancellation,omitempty"`
+}
+
+type RolloutRunCancellationStatus struct {
+	// RequestedBy is the user requesting the cancellation, read from the
+	// manual-command-by annotation set along with spec.cancel
+	// +optional
+	RequestedBy string `json:"requestedBy,omitempty"`
+	// RequestTime is the time the cancellation is observed
+	// +optional
+	RequestTime *metav1.Time `json:"requestTime,omitempty"`
+	// Message describes how the cancellation is handled
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 type RolloutRunBatchStatus struct {
@@ -193,6 +546,705 @@ type RolloutRunStepStatus struct {
 	// Webhooks contains webhook status
 	// +optional
 	Webhooks []RolloutWebhookStatus `json:"webhooks,omitempty"`
+	// SessionDrain records the drain window of sticky sessions, only used in canary
+	// +optional
+	SessionDrain *SessionDrainStatus `json:"sessionDrain,omitempty"`
+	// Retention records the retention window of canary resources in recycle,
+	// only used in canary
+	// +optional
+	Retention *CanaryRetentionStatus `json:"retention,omitempty"`
+	// Hold records the hold of canary, only used in canary with holdAtCanary
+	// +optional
+	Hold *CanaryHoldStatus `json:"hold,omitempty"`
+	// FastPromotion records the canary manually fast-promoted by promote-now
+	// command, only used in canary
+	// +optional
+	FastPromotion *CanaryFastPromotionStatus `json:"fastPromotion,omitempty"`
+	// GuardViolation records the guard of step which is violated
+	// +optional
+	GuardViolation *StepGuardViolation `json:"guardViolation,omitempty"`
+	// ImagePullCheck records the pre-flight checking canary images are pullable, only used in canary
+	// +optional
+	ImagePullCheck *ImagePullCheckStatus `json:"imagePullCheck,omitempty"`
+	// TrafficProbe records the canary traffic verify probe, only used in canary
+	// +optional
+	TrafficProbe *TrafficProbeStatus `json:"trafficProbe,omitempty"`
+	// TargetReadiness records the readiness deadline of each target, only used in canary
+	// +optional
+	TargetReadiness []TargetReadinessStatus `json:"targetReadiness,omitempty"`
+	// TargetStates reports the state of each target in the creation and
+	// readiness phases of canary, so that a slow target does not hide the
+	// progress of the others. The step still waits until all targets are
+	// created and ready, only used in canary
+	// +optional
+	TargetStates []CanaryTargetState `json:"targetStates,omitempty"`
+	// ReadinessStabilization records the progress of the readiness stabilization
+	// window, only used in canary
+	// +optional
+	ReadinessStabilization *CanaryReadinessStabilizationStatus `json:"readinessStabilization,omitempty"`
+	// HealthCheckStartTime is the time when canary started to be checked for readiness,
+	// the health check grace period is measured from it, only used in canary
+	// +optional
+	HealthCheckStartTime *metav1.Time `json:"healthCheckStartTime,omitempty"`
+	// PodFailures records the canary pods that can never become ready without
+	// a change of canary, e.g. their images can not be pulled or they are crash
+	// looping, only used in canary
+	// +optional
+	PodFailures []CanaryPodFailure `json:"podFailures,omitempty"`
+	// RevertRamp records the progress of returning canary traffic to stable, only used in canary
+	// +optional
+	RevertRamp *RevertRampStatus `json:"revertRamp,omitempty"`
+	// PausedTraffic records the canary weight reduced during the pause, only used in canary
+	// +optional
+	PausedTraffic *PausedTrafficStatus `json:"pausedTraffic,omitempty"`
+	// ReplicaCoupling records how the canary replicas are calculated from traffic weight, only used in canary
+	// +optional
+	ReplicaCoupling []ReplicaCouplingStatus `json:"replicaCoupling,omitempty"`
+	// UnreachableClusters records the clusters whose targets can not be found, only used in canary
+	// +optional
+	UnreachableClusters []UnreachableClusterStatus `json:"unreachableClusters,omitempty"`
+	// ActiveDeadline records the deadline of canary max active duration, only used in canary
+	// +optional
+	ActiveDeadline *CanaryActiveDeadlineStatus `json:"activeDeadline,omitempty"`
+	// PausedBefore is the phase of pauseBefore that canary is paused before,
+	// empty if it is not paused by pauseBefore, only used in canary
+	// +optional
+	PausedBefore CanaryPausePhase `json:"pausedBefore,omitempty"`
+	// PauseCheckpoints records the pauses of pauseBefore in the step, only used
+	// in canary
+	// +optional
+	PauseCheckpoints []CanaryPauseCheckpoint `json:"pauseCheckpoints,omitempty"`
+	// AutoContinue indicates that the step continues automatically without
+	// pausing after the post step hook, only used in canary
+	// +optional
+	AutoContinue bool `json:"autoContinue,omitempty"`
+	// CanaryReplicas is the aggregated replicas of all canary workloads, only used in canary
+	// +optional
+	CanaryReplicas *CanaryReplicasStatus `json:"canaryReplicas,omitempty"`
+	// Notifications records the last delivery of each canary notification, only used in canary
+	// +optional
+	Notifications []NotificationStatus `json:"notifications,omitempty"`
+	// RecycleVerification records the verification of canary recycle, only used in canary
+	// +optional
+	RecycleVerification *RecycleVerificationStatus `json:"recycleVerification,omitempty"`
+	// Bake records the progress of canary bake schedule, only used in canary
+	// +optional
+	Bake *CanaryBakeStatus `json:"bake,omitempty"`
+	// StuckDeletions records the canary workloads stuck terminating in recycle and
+	// the action taken on them, only used in canary
+	// +optional
+	StuckDeletions []CanaryStuckDeletionStatus `json:"stuckDeletions,omitempty"`
+	// Drift records the consecutive updates of canary workloads and whether
+	// they are considered drifted, only used in canary
+	// +optional
+	Drift *CanaryDriftStatus `json:"drift,omitempty"`
+	// Rollbacks records the automatic rollbacks of failed canary counted
+	// against the budget, it is kept across retries, only used in canary
+	// +optional
+	Rollbacks *CanaryRollbackStatus `json:"rollbacks,omitempty"`
+	// ResourceFootprint records the resources requested by canary compared with
+	// stable while canary is live, only used in canary
+	// +optional
+	ResourceFootprint *CanaryResourceFootprint `json:"resourceFootprint,omitempty"`
+	// TrafficVerifications records how the last traffic change is verified and
+	// the result of each method, only used in canary
+	// +optional
+	TrafficVerifications []TrafficVerificationStatus `json:"trafficVerifications,omitempty"`
+	// ScaleUp records the increment of each target while canary is scaled up by
+	// scaleUpStep, only used in canary
+	// +optional
+	ScaleUp []CanaryScaleUpStatus `json:"scaleUp,omitempty"`
+	// WarmUp records the warm-up of canary before traffic is forked, only used
+	// in canary
+	// +optional
+	WarmUp *CanaryWarmUpStatus `json:"warmUp,omitempty"`
+	// Preconditions records the check of preconditionRefs before traffic is
+	// forked, only used in canary
+	// +optional
+	Preconditions *CanaryPreconditionsStatus `json:"preconditions,omitempty"`
+	// Prerequisites records the check of prerequisites before canary workloads
+	// are created, only used in canary
+	// +optional
+	Prerequisites *CanaryPrerequisitesStatus `json:"prerequisites,omitempty"`
+	// TrafficHooks records the delivery of CanaryTrafficReadyHook and
+	// CanaryTrafficRevertHook, only used in canary
+	// +optional
+	TrafficHooks *CanaryTrafficHooksStatus `json:"trafficHooks,omitempty"`
+	// TrafficUndoLog records the forwarding of BackendRoutings before they are
+	// changed by forkStable and forkCanary, the reverts restore the recorded
+	// forwarding instead of recomputing it, only used in canary
+	// +optional
+	TrafficUndoLog []TrafficUndoEntry `json:"trafficUndoLog,omitempty"`
+	// TrafficCeiling records the canary weight requested by traffic strategy
+	// if it is clamped to the maximum weight of controller, only used in canary
+	// +optional
+	TrafficCeiling *TrafficCeilingStatus `json:"trafficCeiling,omitempty"`
+	// TrafficSplit records the canary split observed by splitProbe compared
+	// with the expected canary weight, only used in canary
+	// +optional
+	TrafficSplit *TrafficSplitStatus `json:"trafficSplit,omitempty"`
+	// Completion records the outcome of canary and the delivery of CanaryCompletedHook,
+	// only used in canary
+	// +optional
+	Completion *CanaryCompletionStatus `json:"completion,omitempty"`
+	// Phases summarizes the result of each canary lifecycle phase, keyed by
+	// prehook, canary and posthook, it is updated with the state of canary,
+	// only used in canary
+	// +optional
+	Phases map[string]CanaryPhaseResult `json:"phases,omitempty"`
+	// WaitingReason describes what the step is waiting on in the last reconcile,
+	// empty if it is not waiting, only used in canary
+	// +optional
+	WaitingReason StepWaitingReason `json:"waitingReason,omitempty"`
+}
+
+// StepGuardViolation records a violated guard and what is observed.
+type StepGuardViolation struct {
+	// Name is the name of violated guard.
+	Name string `json:"name"`
+	// Value is the observed value of metric guard.
+	// +optional
+	Value string `json:"value,omitempty"`
+	// Message describes the violation.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Time is when the violation is observed.
+	// +optional
+	Time *metav1.Time `json:"time,omitempty"`
+}
+
+// StepWaitingReason describes what a step is waiting on.
+// +kubebuilder:validation:Enum=WaitingWebhook;WaitingReplicas;WaitingTraffic;WaitingImagePull;WaitingWarmUp;WaitingEndpoints;WaitingPreconditions;Paused;StableUnhealthy;GloballyPaused;RollbackBudgetExhausted;ReadinessExpressionFailed;WaitingPrerequisites;TrafficSplitMismatch;CanaryRetained;WaitingForWindow
+type StepWaitingReason string
+
+const (
+	// StepWaitingWebhook means the step is waiting for webhooks to complete.
+	StepWaitingWebhook StepWaitingReason = "WaitingWebhook"
+	// StepWaitingReplicas means the step is waiting for canary replicas to be ready.
+	StepWaitingReplicas StepWaitingReason = "WaitingReplicas"
+	// StepWaitingTraffic means the step is waiting for traffic routing to be programmed.
+	StepWaitingTraffic StepWaitingReason = "WaitingTraffic"
+	// StepWaitingImagePull means the step is waiting for the images of canary to
+	// be checked pullable.
+	StepWaitingImagePull StepWaitingReason = "WaitingImagePull"
+	// StepWaitingWarmUp means the step is waiting for canary to be warmed up
+	// before traffic is forked to it.
+	StepWaitingWarmUp StepWaitingReason = "WaitingWarmUp"
+	// StepWaitingEndpoints means the step is waiting for ready canary pods to be
+	// in the ready endpoints of Service backends.
+	StepWaitingEndpoints StepWaitingReason = "WaitingEndpoints"
+	// StepWaitingPreconditions means the step is waiting for the external objects
+	// of preconditionRefs to report ready.
+	StepWaitingPreconditions StepWaitingReason = "WaitingPreconditions"
+	// StepWaitingPrerequisites means the step is waiting for the external objects
+	// of prerequisites to report ready before canary workloads are created.
+	StepWaitingPrerequisites StepWaitingReason = "WaitingPrerequisites"
+	// StepTrafficSplitMismatch means the split of canary traffic observed by
+	// splitProbe is out of the tolerance of canary weight.
+	StepTrafficSplitMismatch StepWaitingReason = "TrafficSplitMismatch"
+	// StepCanaryRetained means the traffic of canary is reverted in recycle,
+	// and canary resources are retained until the retention window ends.
+	StepCanaryRetained StepWaitingReason = "CanaryRetained"
+	// StepWaitingForWindow means canary is waiting for an allowed window of
+	// promotionWindows to be promoted.
+	StepWaitingForWindow StepWaitingReason = "WaitingForWindow"
+	// StepPaused means the step is paused and waiting to be resumed.
+	StepPaused StepWaitingReason = "Paused"
+	// StepStableUnhealthy means the step is waiting for stable to be available
+	// enough before canary traffic is routed.
+	StepStableUnhealthy StepWaitingReason = "StableUnhealthy"
+	// StepGloballyPaused means all canaries are frozen by the global pause of
+	// controller, the step holds its current state until the pause is cleared.
+	StepGloballyPaused StepWaitingReason = "GloballyPaused"
+	// StepRollbackBudgetExhausted means the failed canary is not rolled back and
+	// retried automatically as the budget of autoRollback is exhausted, the
+	// step waits for the budget to be reset.
+	StepRollbackBudgetExhausted StepWaitingReason = "RollbackBudgetExhausted"
+	// StepReadinessExpressionFailed means the readiness expression of canary
+	// fails to be evaluated against the canary workload, which is considered
+	// not ready until the expression succeeds.
+	StepReadinessExpressionFailed StepWaitingReason = "ReadinessExpressionFailed"
+)
+
+type CanaryRollbackStatus struct {
+	// Times are the times of rollbacks within the window of autoRollback
+	Times []metav1.Time `json:"times,omitempty"`
+	// RollingBack indicates that the failed canary is being recycled before it
+	// is retried
+	RollingBack bool `json:"rollingBack,omitempty"`
+	// LastError is the error failing the canary which is rolled back last
+	// +optional
+	LastError *CodeReasonMessage `json:"lastError,omitempty"`
+	// Exhausted indicates that the budget is exhausted, the failed canary is
+	// not rolled back until the budget is reset
+	Exhausted bool `json:"exhausted,omitempty"`
+}
+
+type CanaryBakeStatus struct {
+	// Window is the index of current active window, starting from 1
+	Window int32 `json:"window"`
+	// Idle indicates that the canary is scaled down after the active window
+	Idle bool `json:"idle,omitempty"`
+	// EndTime is the time when the current active or idle window ends, it is
+	// not set until the canary traffic is routed in the active window
+	// +optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+}
+
+type CanaryResourceFootprint struct {
+	// Canary is the total cpu and memory requested by the desired replicas of all
+	// canary workloads, which is the additional resources used by canary
+	// +optional
+	Canary corev1.ResourceList `json:"canary,omitempty"`
+	// Stable is the total cpu and memory requested by the desired replicas of all
+	// stable workloads of canary targets
+	// +optional
+	Stable corev1.ResourceList `json:"stable,omitempty"`
+}
+
+type CanaryStuckDeletionStatus struct {
+	CrossClusterObjectNameReference `json:",inline"`
+	// DeletionTime is the deletion timestamp of canary workload
+	DeletionTime *metav1.Time `json:"deletionTime,omitempty"`
+	// TimeoutSeconds is the timeout after which the canary workload was considered stuck
+	TimeoutSeconds int32 `json:"timeoutSeconds"`
+	// Action is the action taken on the stuck canary workload
+	Action CanaryStuckDeletionAction `json:"action"`
+	// ActionTime is the time when the action was taken
+	ActionTime *metav1.Time `json:"actionTime,omitempty"`
+	// Finalizers are the finalizers removed by ForceDelete action
+	// +optional
+	Finalizers []string `json:"finalizers,omitempty"`
+}
+
+// CanaryDriftStatus records the consecutive updates of canary workloads.
+type CanaryDriftStatus struct {
+	// ConsecutiveUpdates is the number of consecutive reconciles in which
+	// canary workloads are updated
+	ConsecutiveUpdates int32 `json:"consecutiveUpdates,omitempty"`
+	// Fields are the fields of canary workloads updated in the last reconcile,
+	// prefixed with the workload, e.g. cluster-a/demo .spec.replicas
+	// +optional
+	Fields []string `json:"fields,omitempty"`
+	// DetectedTime is the time when the drift is detected, it is not set until
+	// consecutiveUpdates reaches the threshold
+	// +optional
+	DetectedTime *metav1.Time `json:"detectedTime,omitempty"`
+}
+
+// CanaryOutcome is the final outcome of canary.
+// +kubebuilder:validation:Enum=Succeeded;Failed
+type CanaryOutcome string
+
+const (
+	// CanarySucceeded means canary is recycled and the rollout is promoted.
+	CanarySucceeded CanaryOutcome = "Succeeded"
+	// CanaryFailed means canary is rolled back or failed with a terminal error.
+	CanaryFailed CanaryOutcome = "Failed"
+)
+
+const (
+	// CanaryLifecyclePreHook is the phase of pre canary step hook.
+	CanaryLifecyclePreHook = "prehook"
+	// CanaryLifecycleCanary is the phase of canary running, including holding.
+	CanaryLifecycleCanary = "canary"
+	// CanaryLifecyclePostHook is the phase of post canary step hook.
+	CanaryLifecyclePostHook = "posthook"
+)
+
+// CanaryPhaseResult is the result of a canary lifecycle phase.
+type CanaryPhaseResult string
+
+const (
+	CanaryPhasePending   CanaryPhaseResult = "Pending"
+	CanaryPhaseRunning   CanaryPhaseResult = "Running"
+	CanaryPhaseSucceeded CanaryPhaseResult = "Succeeded"
+	CanaryPhaseFailed    CanaryPhaseResult = "Failed"
+	// CanaryPhaseSkipped means the phase is left out of canary states.
+	CanaryPhaseSkipped CanaryPhaseResult = "Skipped"
+)
+
+type CanaryCompletionStatus struct {
+	// Outcome is the final outcome of canary
+	Outcome CanaryOutcome `json:"outcome,omitempty"`
+	// Revision is the updated revision of canary targets
+	// +optional
+	Revision string `json:"revision,omitempty"`
+	// Result is the final code, reason and message of canary
+	// +optional
+	Result *CodeReasonMessage `json:"result,omitempty"`
+	// Time is the time when canary completed
+	// +optional
+	Time *metav1.Time `json:"time,omitempty"`
+	// Notified indicates that all CanaryCompletedHook webhooks are finished,
+	// their failures are ignored
+	// +optional
+	Notified bool `json:"notified,omitempty"`
+}
+
+// CanaryTrafficHooksStatus records the delivery of canary traffic hooks. It is
+// set once CanaryTrafficReadyHook is started, which indicates that the revert
+// hook is owed in recycle.
+// TrafficOperation is a traffic operation recorded in undo log.
+// +kubebuilder:validation:Enum=ForkStable;ForkCanary
+type TrafficOperation string
+
+const (
+	TrafficForkStable TrafficOperation = "ForkStable"
+	TrafficForkCanary TrafficOperation = "ForkCanary"
+)
+
+type TrafficUndoEntry struct {
+	// Operation is the traffic operation changing the BackendRouting
+	Operation TrafficOperation `json:"operation"`
+	// Routing is the name of BackendRouting
+	Routing string `json:"routing"`
+	// Forwarding is the forwarding of BackendRouting before the operation, nil
+	// means it is not forwarded
+	// +optional
+	Forwarding *BackendForwarding `json:"forwarding,omitempty"`
+	// Reverted indicates that the recorded forwarding is restored, the entry is
+	// recorded again if the operation is done again
+	// +optional
+	Reverted bool `json:"reverted,omitempty"`
+	// Time is the time when the entry is recorded
+	Time metav1.Time `json:"time"`
+}
+
+type CanaryTrafficHooksStatus struct {
+	// ReadyNotified indicates that all CanaryTrafficReadyHook webhooks are
+	// finished after canary traffic is forked
+	// +optional
+	ReadyNotified bool `json:"readyNotified,omitempty"`
+	// RevertNotified indicates that all CanaryTrafficRevertHook webhooks are
+	// finished before canary traffic is reverted
+	// +optional
+	RevertNotified bool `json:"revertNotified,omitempty"`
+}
+
+type RecycleVerificationStatus struct {
+	// StartTime is the time when the first verification was done
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// Message is the result of the last verification
+	Message string `json:"message,omitempty"`
+}
+
+type NotificationStatus struct {
+	// Name is the name of notification
+	Name string `json:"name"`
+	// State is the canary step state notified
+	State RolloutStepState `json:"state,omitempty"`
+	// Delivered indicates whether the notification is accepted by receiver
+	Delivered bool `json:"delivered"`
+	// Message is the reason of delivery failure
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Time is the time when the delivery finished
+	Time *metav1.Time `json:"time,omitempty"`
+}
+
+type CanaryReplicasStatus struct {
+	// summary of replicas of all canary workloads
+	RolloutReplicasSummary `json:",inline"`
+	// NotReadyTargets are the targets whose canary workload is not ready yet
+	// +optional
+	NotReadyTargets []CrossClusterObjectNameReference `json:"notReadyTargets,omitempty"`
+}
+
+type TrafficCeilingStatus struct {
+	// RequestedWeight is the canary weight requested by traffic strategy
+	RequestedWeight int32 `json:"requestedWeight"`
+	// MaxWeight is the maximum canary weight the traffic is clamped to
+	MaxWeight int32 `json:"maxWeight"`
+	// Message is a human readable explanation of the clamping
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type TrafficSplitStatus struct {
+	// ExpectedPercent is the canary weight the split is compared with
+	ExpectedPercent int32 `json:"expectedPercent"`
+	// ObservedPercent is the percent of probe requests served by canary
+	ObservedPercent int32 `json:"observedPercent"`
+	// CanaryRequests is the number of probe requests served by canary
+	CanaryRequests int32 `json:"canaryRequests"`
+	// TotalRequests is the number of probe requests answered in the batch
+	TotalRequests int32 `json:"totalRequests"`
+	// LastProbeTime is the time when the last batch was sent
+	// +optional
+	LastProbeTime *metav1.Time `json:"lastProbeTime,omitempty"`
+	// Message is the result of the last batch
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type CanaryScaleUpStatus struct {
+	CrossClusterObjectNameReference `json:",inline"`
+	// Replicas is the canary replicas of the current increment
+	Replicas int32 `json:"replicas"`
+	// FinalReplicas is the canary replicas after the final increment
+	FinalReplicas int32 `json:"finalReplicas"`
+}
+
+type CanaryWarmUpStatus struct {
+	// StartTime is the time when warm-up started
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// SucceededRequests is the number of warm-up requests succeeded
+	SucceededRequests int32 `json:"succeededRequests,omitempty"`
+	// FailedRequests is the number of warm-up requests failed
+	FailedRequests int32 `json:"failedRequests,omitempty"`
+	// RequestsSent indicates that warm-up requests have been sent
+	RequestsSent bool `json:"requestsSent,omitempty"`
+	// WebhookPassed indicates that the warm-up webhook has responded OK
+	WebhookPassed bool `json:"webhookPassed,omitempty"`
+	// CompletionTime is the time when warm-up completed
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Message is the human readable message of warm-up progress
+	Message string `json:"message,omitempty"`
+}
+
+// CanaryPauseCheckpoint records a pause before a phase of canary.
+type CanaryPauseCheckpoint struct {
+	// Phase is the phase paused before
+	Phase CanaryPausePhase `json:"phase"`
+	// PauseTime is the time when canary is paused
+	PauseTime *metav1.Time `json:"pauseTime,omitempty"`
+	// ResumeTime is the time when canary is resumed to run the phase
+	ResumeTime *metav1.Time `json:"resumeTime,omitempty"`
+}
+
+type CanaryPreconditionsStatus struct {
+	// StartTime is the time when preconditions started to be checked
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// Met indicates that all preconditions are met, they are not checked again
+	// in the step
+	Met bool `json:"met,omitempty"`
+	// Message is the human readable message of the first precondition not met
+	Message string `json:"message,omitempty"`
+}
+
+type CanaryPrerequisitesStatus struct {
+	// StartTime is the time when prerequisites started to be checked
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// Met indicates that all prerequisites are met, they are not checked again
+	// in the step
+	Met bool `json:"met,omitempty"`
+	// Outstanding are the prerequisites not met in the last check
+	Outstanding []CanaryOutstandingPrerequisite `json:"outstanding,omitempty"`
+}
+
+type CanaryOutstandingPrerequisite struct {
+	CrossClusterObjectReference `json:",inline"`
+	// Message is the human readable message of what is observed
+	Message string `json:"message,omitempty"`
+}
+
+type SessionDrainStatus struct {
+	// StartTime is the time when canary stopped receiving new sessions
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// EndTime is the time when the drain window ends
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+}
+
+type CanaryRetentionStatus struct {
+	// StartTime is the time when canary started to be retained
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// EndTime is the time when canary resources are deleted
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+	// EndedBy is who cut the retention short by the end-retention command
+	// +optional
+	EndedBy string `json:"endedBy,omitempty"`
+}
+
+type CanaryReadinessStabilizationStatus struct {
+	// ReadyPercent is the last observed percentage of ready canary replicas
+	ReadyPercent int32 `json:"readyPercent"`
+	// WindowStartTime is the time since when the ready percentage has stayed at
+	// or above the threshold, nil if it is below
+	WindowStartTime *metav1.Time `json:"windowStartTime,omitempty"`
+	// Stabilized indicates that the ready percentage stayed at or above the
+	// threshold for the whole window
+	Stabilized bool `json:"stabilized,omitempty"`
+}
+
+type CanaryPodFailure struct {
+	// Cluster is the cluster of pod
+	// +optional
+	Cluster string `json:"cluster,omitempty"`
+	// Pod is the name of pod
+	Pod string `json:"pod"`
+	// Container is the name of failing container
+	// +optional
+	Container string `json:"container,omitempty"`
+	// Reason is the reason of failure, e.g. ImagePullBackOff or CrashLoopBackOff
+	Reason string `json:"reason"`
+	// Message is the details of failure reported by kubelet
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type TargetReadinessStatus struct {
+	CrossClusterObjectNameReference `json:",inline"`
+	// WaitStartTime is the time when it started waiting for the target to be ready
+	WaitStartTime *metav1.Time `json:"waitStartTime,omitempty"`
+	// Deadline is the time after which the target is considered timed out
+	Deadline *metav1.Time `json:"deadline,omitempty"`
+}
+
+// CanaryTargetPhase is the state of a target in the creation and readiness
+// phases of canary.
+type CanaryTargetPhase string
+
+const (
+	// CanaryTargetCreating means the canary workload of target is being created
+	// or updated.
+	CanaryTargetCreating CanaryTargetPhase = "Creating"
+	// CanaryTargetWaitingReady means the canary workload of target is waiting
+	// to be ready.
+	CanaryTargetWaitingReady CanaryTargetPhase = "WaitingReady"
+	// CanaryTargetReady means the canary workload of target is ready, and the
+	// target waits for the other targets before the shared phases.
+	CanaryTargetReady CanaryTargetPhase = "Ready"
+	// CanaryTargetTimedOut means the canary workload of target is not ready
+	// before its readiness deadline.
+	CanaryTargetTimedOut CanaryTargetPhase = "TimedOut"
+)
+
+type CanaryTargetState struct {
+	CrossClusterObjectNameReference `json:",inline"`
+	// Phase is the state of target
+	// +kubebuilder:validation:Enum=Creating;WaitingReady;Ready;TimedOut
+	Phase CanaryTargetPhase `json:"phase"`
+	// LastTransitionTime is the time when the target entered the phase
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Message is the details of phase
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type ReplicaCouplingStatus struct {
+	CrossClusterObjectNameReference `json:",inline"`
+	// Weight is the canary traffic weight
+	Weight int32 `json:"weight"`
+	// StableReplicas is the replicas of stable workload
+	StableReplicas int32 `json:"stableReplicas"`
+	// RequestedReplicas is the canary replicas calculated from target replicas
+	RequestedReplicas int32 `json:"requestedReplicas"`
+	// Replicas is the final canary replicas, the greater of requested replicas and
+	// weight percent of stable replicas
+	Replicas int32 `json:"replicas"`
+}
+
+type UnreachableClusterStatus struct {
+	// Cluster is the name of the unreachable cluster
+	Cluster string `json:"cluster"`
+	// Since is the time when the cluster was first found unreachable
+	Since *metav1.Time `json:"since,omitempty"`
+	// Degraded indicates that the grace period is exceeded and the cluster is skipped
+	Degraded bool `json:"degraded,omitempty"`
+	// Message is a human readable message indicating why the cluster is unreachable
+	Message string `json:"message,omitempty"`
+}
+
+type CanaryActiveDeadlineStatus struct {
+	// StartTime is the time when the canary was initialized
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// Deadline is the time after which the action is taken
+	Deadline *metav1.Time `json:"deadline,omitempty"`
+	// Warned indicates that the warning event before the deadline is emitted
+	Warned bool `json:"warned,omitempty"`
+	// Action is the action taken after the deadline, empty if not taken yet
+	Action CanaryMaxActiveAction `json:"action,omitempty"`
+	// Message is a human readable message indicating why the action is taken
+	Message string `json:"message,omitempty"`
+}
+
+type RevertRampStatus struct {
+	// Step is the current step of the revert ramp, starting from 1
+	Step int32 `json:"step,omitempty"`
+	// Weight is the canary weight of the current step
+	Weight int32 `json:"weight,omitempty"`
+	// LastStepTime is the time when the current step started
+	LastStepTime *metav1.Time `json:"lastStepTime,omitempty"`
+}
+
+type PausedTrafficStatus struct {
+	// PrePauseWeight is the canary weight before the pause, it is restored when
+	// canary is resumed
+	PrePauseWeight int32 `json:"prePauseWeight"`
+	// Weight is the reduced canary weight during the pause
+	Weight int32 `json:"weight"`
+}
+
+type CanaryHoldStatus struct {
+	// StartTime is the time when canary started holding
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// Action is the action ending the hold, empty if canary is still holding
+	Action CanaryHoldAction `json:"action,omitempty"`
+}
+
+// CanaryHoldAction is the action ending the hold of canary.
+// +kubebuilder:validation:Enum=Promote;End
+type CanaryHoldAction string
+
+const (
+	// CanaryHoldPromote continues the rollout after canary.
+	CanaryHoldPromote CanaryHoldAction = "Promote"
+	// CanaryHoldEnd recycles the canary and cancels the rolloutRun.
+	CanaryHoldEnd CanaryHoldAction = "End"
+)
+
+type CanaryFastPromotionStatus struct {
+	// Operator is who issued the promote-now command, empty if unknown
+	Operator string `json:"operator,omitempty"`
+	// Time is the time when the canary was fast-promoted
+	Time *metav1.Time `json:"time,omitempty"`
+	// FromState is the state of canary when it was fast-promoted
+	FromState RolloutStepState `json:"fromState,omitempty"`
+}
+
+type ImagePullCheckStatus struct {
+	// StartTime is the time when the check pods were created
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// Finished indicates that the check is finished, it is not run again
+	Finished bool `json:"finished,omitempty"`
+	// Message is the result of the check
+	Message string `json:"message,omitempty"`
+}
+
+type TrafficProbeStatus struct {
+	// StartTime is the time when the first probe was sent
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// ConsecutiveSuccesses is the number of consecutive successful probes
+	ConsecutiveSuccesses int32 `json:"consecutiveSuccesses,omitempty"`
+	// Message is the result of the last probe
+	Message string `json:"message,omitempty"`
+}
+
+// TrafficVerificationMethod is how a traffic change is verified.
+type TrafficVerificationMethod string
+
+const (
+	// TrafficVerificationPropagation polls the fields of routes by propagationChecks.
+	TrafficVerificationPropagation TrafficVerificationMethod = "PropagationCheck"
+	// TrafficVerificationProbe sends a synthetic request through canary route by verifyProbe.
+	TrafficVerificationProbe TrafficVerificationMethod = "Probe"
+	// TrafficVerificationSplitProbe counts the canary responses of a batch of requests by splitProbe.
+	TrafficVerificationSplitProbe TrafficVerificationMethod = "SplitProbe"
+)
+
+type TrafficVerificationStatus struct {
+	// Method is how the traffic change is verified
+	Method TrafficVerificationMethod `json:"method"`
+	// Operation is the traffic change verified, e.g. forkCanary
+	Operation string `json:"operation,omitempty"`
+	// Verified indicates that the traffic change has taken effect
+	Verified bool `json:"verified,omitempty"`
+	// StartTime is the time when the verification of operation started
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// Message is the result of the last verification
+	Message string `json:"message,omitempty"`
 }
 
 type RolloutWebhookStatus struct {
@@ -206,6 +1258,32 @@ type RolloutWebhookStatus struct {
 	CodeReasonMessage `json:",inline"`
 	// Failure count
 	FailureCount int32 `json:"failureCount,omitempty"`
+	// PayloadHash is the hash of webhook review payload, a completed result is
+	// reused without invoking the webhook again if the payload is not changed.
+	PayloadHash string `json:"payloadHash,omitempty"`
+	// TargetVerdicts records the verdict of each target if the webhook is called
+	// per target, keyed by the target reference.
+	// +optional
+	TargetVerdicts map[string]WebhookTargetVerdict `json:"targetVerdicts,omitempty"`
+}
+
+// WebhookTargetVerdict is the verdict of a target given by a webhook called per target.
+type WebhookTargetVerdict string
+
+const (
+	WebhookTargetPassed WebhookTargetVerdict = "Passed"
+	WebhookTargetFailed WebhookTargetVerdict = "Failed"
+)
+
+// RejectedTargetStatus is a target rejected by a webhook with PerTarget aggregation.
+type RejectedTargetStatus struct {
+	CrossClusterObjectNameReference `json:",inline"`
+	// HookType is the type of webhook rejecting the target
+	HookType HookType `json:"hookType,omitempty"`
+	// Webhook is the name of webhook rejecting the target
+	Webhook string `json:"webhook,omitempty"`
+	// Message is the result of webhook
+	Message string `json:"message,omitempty"`
 }
 
 // RolloutWebhookState indicates current state of webhook webhook.
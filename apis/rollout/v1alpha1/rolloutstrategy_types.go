@@ -17,6 +17,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -105,16 +106,39 @@ type RolloutStep struct {
 	// Properties contains additional information for step
 	// +optional
 	Properties map[string]string `json:"properties,omitempty"`
+
+	// Guards are the invariants that must hold for the whole duration of the step.
+	// They are checked on every reconcile while the step is in progress, from its
+	// pre step hook to its post step hook, and the rolloutRun fails once any of
+	// them is violated.
+	// +optional
+	Guards []StepGuard `json:"guards,omitempty"`
 }
 
 type CanaryStrategy struct {
 	// Replicas is the replicas of the rollout task, which represents the number of pods to be upgraded
 	Replicas intstr.IntOrString `json:"replicas"`
 
+	// Strategy is the release strategy of canary, defaults to Canary. With
+	// BlueGreen, canary is created at the full size of stable and receives no
+	// traffic while it is validated, then all traffic is switched to it at once
+	// after the post canary step hook, and switched back at once on rollback.
+	// On success, the old stable is torn down by updating it to the canary
+	// revision before canary is recycled, while canary serves all traffic.
+	// +optional
+	Strategy CanaryStrategyType `json:"strategy,omitempty"`
+
 	// traffic strategy
 	// +optional
 	Traffic *TrafficStrategy `json:"traffic,omitempty"`
 
+	// Variants are the canary variants tested simultaneously, each variant has
+	// its own canary workloads and backend, and receives the percent of traffic
+	// allocation of the same name. Canary replicas and podTemplateMetadataPatch
+	// are overridden by the ones of variant.
+	// +optional
+	Variants []CanaryVariant `json:"variants,omitempty"`
+
 	// Match defines condition used for matching resource cross clusterset
 	// +optional
 	Match *ResourceMatch `json:"matchTargets,omitempty"`
@@ -126,4 +150,763 @@ type CanaryStrategy struct {
 	// PodTemplateMetadataPatch defines a patch for workload podTemplate metadata.
 	// +optional
 	PodTemplateMetadataPatch *MetadataPatch `json:"podTemplateMetadataPatch,omitempty"`
+
+	// ObjectMetadataPatch defines a patch for the metadata of canary workload object.
+	// The builtin rollout labels and annotations can not be overridden.
+	// +optional
+	ObjectMetadataPatch *MetadataPatch `json:"objectMetadataPatch,omitempty"`
+
+	// PodPlacementPatch defines a patch for the placement of canary pods, e.g. to
+	// spread canary pods across nodes or zones apart from stable pods. It is merged
+	// into the pod template of canary workloads, separately from the metadata patch.
+	// +optional
+	PodPlacementPatch *PodPlacementPatch `json:"podPlacementPatch,omitempty"`
+
+	// PromotionWindows defines the time windows in which the canary is allowed to be promoted.
+	// If not set, the canary can be promoted at any time.
+	// +optional
+	PromotionWindows *PromotionWindows `json:"promotionWindows,omitempty"`
+
+	// Analysis defines the metrics to check after canary traffic is routed.
+	// The canary fails if any metric is out of its threshold.
+	// +optional
+	Analysis *CanaryAnalysis `json:"analysis,omitempty"`
+
+	// PauseAfter indicates whether to pause the rollout after the post canary step hook
+	// succeeds. If false, the canary flows straight into recycling without manual resume.
+	// Defaults to true, or false if pauseBefore is set.
+	// +optional
+	PauseAfter *bool `json:"pauseAfter,omitempty"`
+
+	// PauseBefore lists the phases of canary to pause before, e.g. [forkCanary,
+	// promotion] to approve the traffic shift and the promotion while everything
+	// else runs automatically. Each phase pauses once until it is resumed, and
+	// the phase paused before is recorded in status.
+	// +optional
+	PauseBefore []CanaryPausePhase `json:"pauseBefore,omitempty"`
+
+	// HoldAtCanary keeps the canary running after its traffic is routed and analyzed,
+	// e.g. for long-lived experiments. The step stays in Holding state, where canary
+	// readiness and traffic keep being reconciled, until the hold is ended by the
+	// manual command promote, which continues the rollout, or end, which recycles the
+	// canary and cancels the rolloutRun without upgrading stable.
+	// +optional
+	HoldAtCanary bool `json:"holdAtCanary,omitempty"`
+
+	// RecycleOrder defines the order of operations when recycling canary resources.
+	// It must contain each of RevertCanaryTraffic, DeleteCanaryResource and
+	// RevertStableTraffic exactly once, and RevertCanaryTraffic must come before
+	// DeleteCanaryResource.
+	// Defaults to [RevertCanaryTraffic, DeleteCanaryResource, RevertStableTraffic].
+	// +optional
+	RecycleOrder []CanaryRecycleOperation `json:"recycleOrder,omitempty"`
+
+	// RetentionSeconds keeps the canary resources for a while after its traffic
+	// is reverted in recycle, e.g. for forensics, instead of deleting them at
+	// once. The deletion is deferred after all traffic operations of recycle,
+	// and the retention can be cut short by the end-retention command. It can
+	// not be set with ordinals.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RetentionSeconds *int32 `json:"retentionSeconds,omitempty"`
+
+	// ReadinessTimeoutSeconds is the maximum time to wait for the canary of each
+	// target to be ready. If not set, wait until they are ready.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ReadinessTimeoutSeconds *int32 `json:"readinessTimeoutSeconds,omitempty"`
+
+	// CanaryNamespace is the namespace in which the canary workloads are created,
+	// e.g. for network policy isolation. It must exist before canary starts and
+	// allow the namespace of stable workload by annotation
+	// rollout.kusionstack.io/cross-namespace-allowed-from. If not set, canary is
+	// created in the namespace of stable workload.
+	// +optional
+	CanaryNamespace string `json:"canaryNamespace,omitempty"`
+
+	// ConfigOverrides substitute the ConfigMaps and Secrets referenced by the pod
+	// template of canary workloads, so that canary mounts its own config without
+	// mutating the config of stable. The alternate objects are not managed by
+	// rollout, they must exist in the canary namespace before canary starts and
+	// are left untouched in recycle.
+	// +optional
+	ConfigOverrides []CanaryConfigOverride `json:"configOverrides,omitempty"`
+
+	// InitContainerOverrides are merged into the init containers of the pod
+	// template of canary workloads by name: an override replaces the init
+	// container of the same name, others are appended in order. They are not
+	// used with ordinals, and are deleted with canary workloads in recycle.
+	// +optional
+	InitContainerOverrides []corev1.Container `json:"initContainerOverrides,omitempty"`
+
+	// OwnerReferences are added to the owner references of canary workloads, in
+	// addition to the RolloutRun, e.g. for cost tooling. The owners must be in the
+	// cluster and namespace of canary workloads, otherwise the garbage collector
+	// deletes the canary workloads. They are not used with ordinals.
+	// +optional
+	OwnerReferences []metav1.OwnerReference `json:"ownerReferences,omitempty"`
+
+	// PodDisruptionBudget creates a PodDisruptionBudget selecting the pods of each
+	// canary workload, so that canary keeps available during node drains while it
+	// serves traffic. It is deleted with canary workloads in recycle. It is not
+	// used with ordinals.
+	// +optional
+	PodDisruptionBudget *CanaryPodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
+
+	// CrashLoopCheck defines when the canary fails fast if its pods are crash looping,
+	// instead of waiting for the canary to be ready.
+	// +optional
+	CrashLoopCheck *CanaryCrashLoopCheck `json:"crashLoopCheck,omitempty"`
+
+	// ImagePullCheck enables a pre-flight before canary is scaled, which verifies the
+	// images of canary can be pulled by a short-lived pod with the image pull secrets
+	// and service account of canary, so that a bad image fails the canary in seconds
+	// instead of leaving canary pods in ImagePullBackOff.
+	// +optional
+	ImagePullCheck *CanaryImagePullCheck `json:"imagePullCheck,omitempty"`
+
+	// HealthCheckGracePeriodSeconds is the period after canary starts to be checked
+	// for readiness, during which crash looping pods and failed analysis are logged
+	// but do not fail the canary. After it, failures are handled as usual.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	HealthCheckGracePeriodSeconds *int32 `json:"healthCheckGracePeriodSeconds,omitempty"`
+
+	// ReadinessStabilization requires the ready percentage of canary replicas to
+	// stay at or above a threshold for a window before canary is considered
+	// ready, so that flapping pods do not pass the readiness check. If not set,
+	// canary is ready once all its replicas are ready.
+	// +optional
+	ReadinessStabilization *CanaryReadinessStabilization `json:"readinessStabilization,omitempty"`
+
+	// ReadinessExpression is a CEL expression deciding whether a canary workload is
+	// ready, instead of all its replicas being updated and available. It can refer
+	// to `status`, the replicas summary of canary workload such as
+	// status.updatedAvailableReplicas, and `object`, the canary workload itself,
+	// and must return a bool. It is only evaluated once the canary workload status
+	// is up to date.
+	// +optional
+	ReadinessExpression string `json:"readinessExpression,omitempty"`
+
+	// DegradedClusterGracePeriodSeconds enables the degraded mode of canary. If set, a
+	// cluster whose canary targets can not be found for longer than this period is marked
+	// degraded and skipped, and the canary proceeds in the other clusters. The skipped
+	// clusters are reconciled again once they are reachable.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	DegradedClusterGracePeriodSeconds *int32 `json:"degradedClusterGracePeriodSeconds,omitempty"`
+
+	// ReplicasFollowTrafficWeight keeps the canary replicas of each target not less
+	// than the traffic weight percent of stable replicas, e.g. a canary receiving
+	// 50% traffic has at least 50% of stable replicas. It only works if traffic
+	// weight is set.
+	// +optional
+	ReplicasFollowTrafficWeight bool `json:"replicasFollowTrafficWeight,omitempty"`
+
+	// MaxActiveDuration limits how long the canary can stay active, including the
+	// time spent paused after the post canary step hook. The configured action is
+	// taken once it is exceeded.
+	// +optional
+	MaxActiveDuration *CanaryMaxActiveDuration `json:"maxActiveDuration,omitempty"`
+
+	// Notifications are notification-only webhooks called asynchronously on each
+	// canary step state transition. Their responses and failures never block or
+	// fail the canary, the delivery results are only recorded in status.
+	// +optional
+	Notifications []CanaryNotification `json:"notifications,omitempty"`
+
+	// Bake defines an active/idle schedule of canary after it is ready. In each
+	// active window the canary is scaled up, receives traffic and is analyzed at
+	// the end of the window. Between active windows the canary traffic is reverted
+	// before the canary is scaled down to idle replicas, and it is forked again only
+	// after the canary is scaled up and ready, so no traffic is routed to a scaled
+	// down canary. The analysis runs only in active windows instead of once after
+	// traffic is routed.
+	// +optional
+	Bake *CanaryBake `json:"bake,omitempty"`
+
+	// StableMinAvailable is the minimum available replicas of each stable workload,
+	// as an absolute number or a percentage of stable replicas, required before
+	// canary traffic is routed. The canary waits with StableUnhealthy reason until
+	// stable recovers, so traffic is never shifted while stable is degraded.
+	// +optional
+	StableMinAvailable *intstr.IntOrString `json:"stableMinAvailable,omitempty"`
+
+	// StuckDeletion defines how to handle a canary workload which is still
+	// terminating a while after it is deleted in recycle, e.g. blocked by a
+	// finalizer or PVC. If not set, recycle waits until it is removed.
+	// +optional
+	StuckDeletion *CanaryStuckDeletion `json:"stuckDeletion,omitempty"`
+
+	// DriftDetection defines when a canary workload is considered drifted, i.e.
+	// it is updated by the rolloutRun in consecutive reconciles because another
+	// controller keeps mutating it, and the action taken on it. Drift is
+	// detected with the defaults if not set.
+	// +optional
+	DriftDetection *CanaryDriftDetection `json:"driftDetection,omitempty"`
+
+	// AutoRollback rolls back the canary failed with a terminal error and
+	// retries it automatically, within a budget of rollbacks per time window.
+	// Once the budget is exhausted, the rolloutRun is paused until it is reset
+	// by the reset-rollback-budget command.
+	// +optional
+	AutoRollback *CanaryAutoRollback `json:"autoRollback,omitempty"`
+
+	// Ordinals are the ordinals of StatefulSet pods updated to the canary
+	// template in place, instead of creating canary workloads, e.g. when
+	// ordinals map to shards. StatefulSet updates pods from the largest ordinal
+	// by partition, so they must be the last ordinals of each target, e.g. [3, 4]
+	// for the last two pods of 5 replicas. They are rolled back to the stable
+	// template in recycle.
+	// +optional
+	Ordinals []int32 `json:"ordinals,omitempty"`
+
+	// UpdateStrategy defines how the pods of canary workloads are turned over
+	// when canary workloads are updated to a new revision during the run. If not
+	// set, the update strategy copied from stable workload is used.
+	// +optional
+	UpdateStrategy *CanaryUpdateStrategy `json:"updateStrategy,omitempty"`
+
+	// ScaleUpStep is the number or percent of canary replicas added in each
+	// increment, the percent is of the final canary replicas of each target.
+	// Canary is scaled up increment by increment, each one is added after the
+	// previous one is ready, and traffic is forked to canary after the final
+	// increment is ready. With ordinals, each increment updates that many more
+	// ordinals in place. If not set, all canary replicas are created at once.
+	// +optional
+	ScaleUpStep *intstr.IntOrString `json:"scaleUpStep,omitempty"`
+
+	// WarmUp defines the warm-up of canary pods after they are ready and before
+	// traffic is forked to canary, so that cold pods are primed by synthetic
+	// requests instead of real traffic. If not set, traffic is forked once canary
+	// is ready.
+	// +optional
+	WarmUp *CanaryWarmUp `json:"warmUp,omitempty"`
+
+	// PreconditionRefs are the external objects that must report ready before
+	// traffic is forked to canary, e.g. a FeatureFlag or a DatabaseMigration.
+	// They are checked after canary is ready, and the rolloutRun fails if any of
+	// them is not met in time.
+	// +optional
+	PreconditionRefs []CanaryPreconditionRef `json:"preconditionRefs,omitempty"`
+
+	// Prerequisites are the external objects that must report ready before canary
+	// workloads are created, e.g. a database migration Job or a feature flag. They
+	// are checked when canary is initialized, the ones not met yet are recorded
+	// in status, and the rolloutRun fails if any of them is not met in time.
+	// +optional
+	Prerequisites []CanaryPreconditionRef `json:"prerequisites,omitempty"`
+
+	// Guards are the invariants that must hold for the whole duration of the step.
+	// They are checked on every reconcile while the step is in progress, from its
+	// pre step hook to its post step hook, and the rolloutRun fails once any of
+	// them is violated.
+	// +optional
+	Guards []StepGuard `json:"guards,omitempty"`
 }
+
+// CanaryRecycleOperation is an operation performed when recycling canary resources.
+// +kubebuilder:validation:Enum=RevertCanaryTraffic;DeleteCanaryResource;RevertStableTraffic
+type CanaryRecycleOperation string
+
+const (
+	// RevertCanaryTraffic reverts the traffic routed to canary.
+	RevertCanaryTraffic CanaryRecycleOperation = "RevertCanaryTraffic"
+	// DeleteCanaryResource deletes the canary workloads.
+	DeleteCanaryResource CanaryRecycleOperation = "DeleteCanaryResource"
+	// RevertStableTraffic reverts the forked stable traffic.
+	RevertStableTraffic CanaryRecycleOperation = "RevertStableTraffic"
+	// PromoteStableRevision updates all stable pods to the canary revision. It
+	// can not be set in recycleOrder, a succeeded blue/green canary performs it
+	// first to tear down the old stable while canary serves all traffic.
+	PromoteStableRevision CanaryRecycleOperation = "PromoteStableRevision"
+)
+
+// CanaryPausePhase is a phase of canary that can be paused before.
+// +kubebuilder:validation:Enum=createCanary;forkCanary;promotion
+type CanaryPausePhase string
+
+const (
+	// CanaryPauseBeforeCreateCanary pauses before canary workloads are created.
+	CanaryPauseBeforeCreateCanary CanaryPausePhase = "createCanary"
+	// CanaryPauseBeforeForkCanary pauses before traffic is forked to a ready canary.
+	CanaryPauseBeforeForkCanary CanaryPausePhase = "forkCanary"
+	// CanaryPauseBeforePromotion pauses before canary is recycled to continue the
+	// rollout, it is not paused on rollback.
+	CanaryPauseBeforePromotion CanaryPausePhase = "promotion"
+)
+
+// CanaryStrategyType is the release strategy of canary.
+// +kubebuilder:validation:Enum=Canary;BlueGreen
+type CanaryStrategyType string
+
+const (
+	// CanaryStrategyCanary routes a part of traffic to canary while it is validated.
+	CanaryStrategyCanary CanaryStrategyType = "Canary"
+	// CanaryStrategyBlueGreen validates a full size canary without traffic, and
+	// switches all traffic to it at once.
+	CanaryStrategyBlueGreen CanaryStrategyType = "BlueGreen"
+)
+
+// DefaultCanaryRecycleOrder is the default order of canary recycle operations.
+var DefaultCanaryRecycleOrder = []CanaryRecycleOperation{
+	RevertCanaryTraffic,
+	DeleteCanaryResource,
+	RevertStableTraffic,
+}
+
+// CanaryAnalysis defines the metrics used to analyze canary.
+type CanaryAnalysis struct {
+	// Metrics is the list of metrics to check.
+	Metrics []AnalysisMetric `json:"metrics"`
+}
+
+// CanaryBake defines the active/idle schedule of canary bake.
+type CanaryBake struct {
+	// Windows is the number of active windows, the canary step finishes after
+	// the analysis of the last active window passes.
+	// +kubebuilder:validation:Minimum=1
+	Windows int32 `json:"windows"`
+	// ActiveSeconds is the duration of each active window, counted from the
+	// canary traffic is routed.
+	// +kubebuilder:validation:Minimum=1
+	ActiveSeconds int32 `json:"activeSeconds"`
+	// IdleSeconds is the duration the canary stays scaled down between active windows.
+	// +kubebuilder:validation:Minimum=1
+	IdleSeconds int32 `json:"idleSeconds"`
+	// IdleReplicas is the replicas of each canary workload between active windows.
+	// Defaults to 0.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	IdleReplicas *int32 `json:"idleReplicas,omitempty"`
+}
+
+// CanaryCrashLoopCheck defines the thresholds of canary pods crash looping.
+type CanaryCrashLoopCheck struct {
+	// RestartThreshold is the restart count of any container at which the pod is
+	// regarded as crash looping. A pod in CrashLoopBackOff is always regarded as
+	// crash looping. Defaults to 3.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RestartThreshold *int32 `json:"restartThreshold,omitempty"`
+	// PodThreshold is the number of crash looping pods in a canary target at which
+	// the canary fails. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PodThreshold *int32 `json:"podThreshold,omitempty"`
+}
+
+// CanaryImagePullCheck defines the pre-flight checking canary images are pullable.
+type CanaryImagePullCheck struct {
+	// TimeoutSeconds is the maximum time to wait for the images to be pulled. If
+	// it is exceeded, e.g. the check pod can not be scheduled, the check is given
+	// up and canary proceeds. Defaults to 120.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// CanaryUpdateStrategyType is the type of canary update strategy.
+// +kubebuilder:validation:Enum=RollingUpdate;Recreate
+type CanaryUpdateStrategyType string
+
+const (
+	// CanaryRollingUpdate replaces the outdated canary pods gradually.
+	CanaryRollingUpdate CanaryUpdateStrategyType = "RollingUpdate"
+	// CanaryRecreate deletes all outdated canary pods at once.
+	CanaryRecreate CanaryUpdateStrategyType = "Recreate"
+)
+
+// CanaryUpdateStrategy defines how the pods of canary workloads are turned over.
+type CanaryUpdateStrategy struct {
+	// Type is the type of update strategy. Defaults to RollingUpdate.
+	// +optional
+	Type CanaryUpdateStrategyType `json:"type,omitempty"`
+	// RollingUpdate is the parameters of RollingUpdate, only used if type is RollingUpdate.
+	// +optional
+	RollingUpdate *CanaryRollingUpdateStrategy `json:"rollingUpdate,omitempty"`
+}
+
+type CanaryRollingUpdateStrategy struct {
+	// MaxUnavailable is the maximum number of canary pods that can be unavailable
+	// during the update, in number or percentage of canary replicas. Defaults to 1.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+	// MaxSurge is the maximum number of canary pods that can be scheduled above
+	// the canary replicas during the update, in number or percentage of canary
+	// replicas. Defaults to 0.
+	// +optional
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+}
+
+// CanaryMaxActiveAction is the action taken when canary exceeds its max active duration.
+// +kubebuilder:validation:Enum=Promote;Rollback
+type CanaryMaxActiveAction string
+
+const (
+	// CanaryMaxActivePromote continues the canary without pausing, a paused canary
+	// is resumed.
+	CanaryMaxActivePromote CanaryMaxActiveAction = "Promote"
+	// CanaryMaxActiveRollback recycles the canary and cancels the rollout run.
+	CanaryMaxActiveRollback CanaryMaxActiveAction = "Rollback"
+)
+
+// CanaryStuckDeletionAction is the action taken on a canary workload stuck terminating.
+// +kubebuilder:validation:Enum=ForceDelete;Fail
+type CanaryStuckDeletionAction string
+
+const (
+	// CanaryStuckDeletionForceDelete removes the remaining finalizers of the canary
+	// workload so that it is removed, and recycle proceeds.
+	CanaryStuckDeletionForceDelete CanaryStuckDeletionAction = "ForceDelete"
+	// CanaryStuckDeletionFail fails the canary to escalate to manual cleanup.
+	CanaryStuckDeletionFail CanaryStuckDeletionAction = "Fail"
+)
+
+// CanaryStuckDeletion defines when a terminating canary workload is considered
+// stuck and the action taken on it.
+type CanaryStuckDeletion struct {
+	// TimeoutSeconds is how long the canary workload can stay terminating, counted
+	// from its deletion timestamp. It is extended to the termination grace period
+	// of canary pods if that is longer, so draining pods are never cut short.
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds int32 `json:"timeoutSeconds"`
+	// Action is the action taken when the timeout is exceeded.
+	Action CanaryStuckDeletionAction `json:"action"`
+}
+
+// CanaryVariant is a canary variant tested along with other variants.
+type CanaryVariant struct {
+	// Name is the name of variant, it must be one of the variants of traffic
+	// allocation. It is suffixed to the names of canary workloads and backends
+	// of variant.
+	// +kubebuilder:validation:MaxLength=20
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+	// Replicas is the replicas of canary workload of variant for each target.
+	Replicas intstr.IntOrString `json:"replicas"`
+	// PodTemplateMetadataPatch is merged into the pod template metadata patch of
+	// canary for the workloads of variant.
+	// +optional
+	PodTemplateMetadataPatch *MetadataPatch `json:"podTemplateMetadataPatch,omitempty"`
+}
+
+// CanaryDriftAction is the action taken on a drifted canary workload.
+// +kubebuilder:validation:Enum=Warn;Fail
+type CanaryDriftAction string
+
+const (
+	// CanaryDriftWarn records the drift and emits a warning event, canary keeps
+	// waiting for the workload to settle.
+	CanaryDriftWarn CanaryDriftAction = "Warn"
+	// CanaryDriftFail fails the canary to escalate the conflicting controller.
+	CanaryDriftFail CanaryDriftAction = "Fail"
+)
+
+// CanaryDriftDetection defines when a canary workload is considered drifted
+// and the action taken on it.
+type CanaryDriftDetection struct {
+	// Threshold is the number of consecutive reconciles in which canary
+	// workloads are updated before drift is detected. Defaults to 5.
+	// +kubebuilder:validation:Minimum=2
+	// +optional
+	Threshold int32 `json:"threshold,omitempty"`
+	// Action is the action taken when drift is detected. Defaults to Warn.
+	// +optional
+	Action CanaryDriftAction `json:"action,omitempty"`
+}
+
+// CanaryAutoRollback defines the budget of rolling back and retrying the
+// failed canary automatically.
+type CanaryAutoRollback struct {
+	// MaxRollbacks is the number of rollbacks allowed within the window.
+	// +kubebuilder:validation:Minimum=1
+	MaxRollbacks int32 `json:"maxRollbacks"`
+	// WindowSeconds is the sliding time window in which rollbacks are counted.
+	// Defaults to 3600.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	WindowSeconds *int32 `json:"windowSeconds,omitempty"`
+}
+
+// CanaryReadinessStabilization defines when the readiness of canary is stable.
+type CanaryReadinessStabilization struct {
+	// ReadyPercent is the minimum percentage of ready replicas in all canary
+	// replicas. Defaults to 100.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	ReadyPercent int32 `json:"readyPercent,omitempty"`
+	// WindowSeconds is the duration the ready percentage must stay at or above
+	// ReadyPercent continuously, the window restarts whenever it dips below.
+	//
+	// +kubebuilder:validation:Minimum=1
+	WindowSeconds int32 `json:"windowSeconds"`
+}
+
+// CanaryWarmUp defines how canary pods are warmed up before traffic is forked.
+// Warm-up requests are sent first, then the webhook is called until it responds
+// OK, and the warm-up is completed after at least DurationSeconds since it
+// started.
+type CanaryWarmUp struct {
+	// DurationSeconds is the minimum duration of warm-up, counted from the time
+	// canary is ready.
+	// +optional
+	DurationSeconds int32 `json:"durationSeconds,omitempty"`
+	// Requests are the synthetic HTTP requests sent to each ready canary pod.
+	// +optional
+	Requests *CanaryWarmUpRequests `json:"requests,omitempty"`
+	// Webhook is called to warm up canary, e.g. by replaying recorded traffic.
+	// It is called again until it responds OK.
+	// +optional
+	Webhook *WebhookClientConfig `json:"webhook,omitempty"`
+	// TimeoutSeconds bounds the whole warm-up, the rolloutRun fails if warm-up
+	// is not completed in time. Defaults to 300 seconds.
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// CanaryWarmUpRequests are HTTP GET requests sent to the IP of canary pods.
+type CanaryWarmUpRequests struct {
+	// Port is the container port requests are sent to.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+	// Path is the HTTP path of requests.
+	// +optional
+	Path string `json:"path,omitempty"`
+	// Headers are the HTTP headers of requests.
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+	// Count is the number of requests sent to each pod, defaults to 1.
+	// +optional
+	Count int32 `json:"count,omitempty"`
+	// TimeoutSeconds is the timeout of each request, defaults to 5 seconds.
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// CanaryPreconditionRef checks a field of an external object until it has the
+// expected value. The controller must be granted to get the object.
+type CanaryPreconditionRef struct {
+	// CrossClusterObjectReference is the object to check, the namespace defaults
+	// to the namespace of rolloutRun.
+	CrossClusterObjectReference `json:",inline"`
+	// FieldPath is the JSONPath of the field in object, e.g.
+	// {.status.conditions[?(@.type=="Ready")].status}.
+	FieldPath string `json:"fieldPath"`
+	// Value is the expected value of the field. Defaults to "True".
+	// +optional
+	Value string `json:"value,omitempty"`
+	// TimeoutSeconds is the period to keep polling before the canary fails.
+	// Defaults to 300.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// CanaryConfigKind is the kind of config substituted in canary pod template.
+// +kubebuilder:validation:Enum=ConfigMap;Secret
+type CanaryConfigKind string
+
+const (
+	CanaryConfigMap    CanaryConfigKind = "ConfigMap"
+	CanaryConfigSecret CanaryConfigKind = "Secret"
+)
+
+// CanaryConfigOverride substitutes a config referenced by the pod template of
+// stable with an alternate one in canary.
+type CanaryConfigOverride struct {
+	// Kind is the kind of config.
+	Kind CanaryConfigKind `json:"kind"`
+	// Name is the name of config referenced by the pod template of stable, in
+	// volumes, envFrom and env of containers.
+	Name string `json:"name"`
+	// CanaryName is the name of alternate config referenced by canary instead.
+	CanaryName string `json:"canaryName"`
+}
+
+// CanaryPodDisruptionBudget is the disruption budget of canary pods, only one
+// of MinAvailable and MaxUnavailable can be set. It must allow at least one
+// canary pod to be evicted, otherwise node drains are blocked.
+type CanaryPodDisruptionBudget struct {
+	// MinAvailable is the number or percentage of canary pods which must be
+	// available after an eviction.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+	// MaxUnavailable is the number or percentage of canary pods which can be
+	// unavailable after an eviction.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// AllowedDisruptions returns the number of canary pods which can be evicted
+// when all the replicas of canary are available, percentages are rounded up
+// as PodDisruptionBudget does.
+func (b *CanaryPodDisruptionBudget) AllowedDisruptions(replicas int32) (int32, error) {
+	if b.MaxUnavailable != nil {
+		maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(b.MaxUnavailable, int(replicas), true)
+		if err != nil {
+			return 0, err
+		}
+		return min(int32(maxUnavailable), replicas), nil
+	}
+	if b.MinAvailable != nil {
+		minAvailable, err := intstr.GetScaledValueFromIntOrPercent(b.MinAvailable, int(replicas), true)
+		if err != nil {
+			return 0, err
+		}
+		return max(replicas-int32(minAvailable), 0), nil
+	}
+	return replicas, nil
+}
+
+// PodPlacementPatch is a patch for the placement of canary pods. Canary pods
+// carry the builtin labels rollout.kusionstack.io/canary=true and
+// pod.rollout.kusionstack.io/revision=canary, while stable pods carry
+// pod.rollout.kusionstack.io/revision=base, so they can be used in label
+// selectors of the patch to tell canary pods from stable pods, e.g. a pod
+// anti-affinity term selecting revision=base keeps canary pods away from stable
+// pods, and a spread constraint selecting revision=canary spreads canary pods
+// among themselves. A selector without them counts both canary and stable pods.
+type PodPlacementPatch struct {
+	// Affinity is merged into the affinity of canary pod template. The terms of
+	// pod affinity, pod anti-affinity and preferred node affinity are added to the
+	// existing ones, and the required node selector term is merged into each
+	// existing term, so the patch only narrows where canary pods can be placed.
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// TopologySpreadConstraints are added to the constraints of canary pod
+	// template, replacing the existing constraint with the same topologyKey.
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+}
+
+// CanaryMaxActiveDuration defines how long the canary can stay active.
+type CanaryNotification struct {
+	// Name is the identity of notification.
+	Name string `json:"name"`
+	// ClientConfig defines how to deliver the notification, periodSeconds is ignored
+	// since the notification is delivered only once for each transition.
+	ClientConfig WebhookClientConfig `json:"clientConfig"`
+	// Properties provide additional data for notification.
+	// +optional
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type CanaryMaxActiveDuration struct {
+	// Seconds is the max duration the canary can stay active, counted from the
+	// canary is initialized.
+	// +kubebuilder:validation:Minimum=1
+	Seconds int32 `json:"seconds"`
+	// Action is the action taken when the duration is exceeded.
+	Action CanaryMaxActiveAction `json:"action"`
+	// WarningSeconds is the lead time before the deadline at which a warning event
+	// is emitted. If not set, no warning is emitted.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	WarningSeconds *int32 `json:"warningSeconds,omitempty"`
+}
+
+// AnalysisMetric defines a metric query and its threshold.
+type AnalysisMetric struct {
+	// Name is the name of metric.
+	Name string `json:"name"`
+	// Provider is the metric backend to query.
+	Provider AnalysisProvider `json:"provider"`
+	// Query is the query in the language of provider. For prometheus, it is a
+	// Go template rendered with {{.CanarySelector}} and {{.StableSelector}}, the
+	// label matchers of canary and stable pods without braces, and {{.Window}},
+	// the time window of query, e.g.
+	// sum(rate(http_requests_total{code=~"5..",{{.CanarySelector}}}[{{.Window}}])).
+	Query string `json:"query"`
+	// Window is the time window rendered as {{.Window}} in query. Defaults to 5m.
+	// +optional
+	Window *metav1.Duration `json:"window,omitempty"`
+	// Min is the lower bound of metric value, inclusive, e.g. "0.99".
+	// +optional
+	Min *string `json:"min,omitempty"`
+	// Max is the upper bound of metric value, inclusive, e.g. "500".
+	// +optional
+	Max *string `json:"max,omitempty"`
+}
+
+// StepGuard is an invariant of step, e.g. the error rate of stable stays below a
+// threshold. Exactly one of metric and webhook must be set.
+type StepGuard struct {
+	// Name is the identity of guard.
+	Name string `json:"name"`
+	// Metric is queried on each check, the guard is violated if its value is out
+	// of min and max. Failed queries are retried in the next check.
+	// +optional
+	Metric *GuardMetric `json:"metric,omitempty"`
+	// Webhook is called with the review of StepGuard hook type on each check, the
+	// guard is violated if it responds with the Error code. Unreachable webhooks
+	// are retried in the next check.
+	// +optional
+	Webhook *WebhookClientConfig `json:"webhook,omitempty"`
+}
+
+// GuardMetric is a metric query with the threshold it must stay within.
+type GuardMetric struct {
+	// Provider is the metric backend to query.
+	Provider AnalysisProvider `json:"provider"`
+	// Query is the query in the language of provider.
+	Query string `json:"query"`
+	// Min is the lower bound of metric value, inclusive, e.g. "0.99".
+	// +optional
+	Min *string `json:"min,omitempty"`
+	// Max is the upper bound of metric value, inclusive, e.g. "0.01".
+	// +optional
+	Max *string `json:"max,omitempty"`
+}
+
+// AnalysisProvider defines the metric backend.
+type AnalysisProvider struct {
+	// Name is the name of provider, e.g. prometheus, datadog.
+	Name string `json:"name"`
+	// Address is the address of provider API, e.g. http://prometheus.monitoring:9090.
+	// Required by prometheus, datadog defaults to https://api.datadoghq.com.
+	// Datadog address must be https, and the credentials of controller are only
+	// sent to the Datadog sites allowed by the operator.
+	// +optional
+	Address string `json:"address,omitempty"`
+}
+
+// PromotionWindows defines when a canary is allowed to be promoted.
+type PromotionWindows struct {
+	// TimeZone is the IANA time zone name used to evaluate the windows, e.g. "Asia/Shanghai".
+	// Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Windows is the list of allowed time windows. The canary can be promoted
+	// if the current time is in any of them.
+	Windows []TimeWindow `json:"windows"`
+}
+
+// TimeWindow defines a daily time range.
+type TimeWindow struct {
+	// Days is the list of days the window applies to.
+	// If empty, the window applies to every day.
+	// +optional
+	Days []Weekday `json:"days,omitempty"`
+
+	// Start is the start time of the window in 24-hour format "HH:MM", inclusive.
+	Start string `json:"start"`
+
+	// End is the end time of the window in 24-hour format "HH:MM", exclusive.
+	// If End is not after Start, the window ends at End on the next day.
+	End string `json:"end"`
+}
+
+// Weekday is the abbreviated name of a day of the week.
+// +kubebuilder:validation:Enum=Sun;Mon;Tue;Wed;Thu;Fri;Sat
+type Weekday string
@@ -31,6 +31,19 @@ type ResourceMatch struct {
 type CrossClusterObjectReference struct {
 	ObjectTypeRef                   `json:",inline"`
 	CrossClusterObjectNameReference `json:",inline"`
+	// Namespace is the namespace of the object, defaults to the namespace of
+	// the referrer.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// NamespaceOr returns the namespace of the object, or the given namespace of
+// the referrer if it is not set.
+func (r CrossClusterObjectReference) NamespaceOr(namespace string) string {
+	if len(r.Namespace) > 0 {
+		return r.Namespace
+	}
+	return namespace
 }
 
 type ObjectTypeRef struct {
@@ -88,9 +101,23 @@ type CodeReasonMessage struct {
 
 // Error implements error.
 func (c *CodeReasonMessage) Error() string {
+	if c == nil {
+		return "<nil>"
+	}
 	return fmt.Sprintf("err: code=%q, reason=%q, message=%q", c.Code, c.Reason, c.Message)
 }
 
+// Is reports whether target is a CodeReasonMessage with the same code and
+// reason, the empty fields of target match any value. It allows errors to be
+// classified by errors.Is(err, &CodeReasonMessage{Reason: reason}).
+func (c *CodeReasonMessage) Is(target error) bool {
+	t, ok := target.(*CodeReasonMessage)
+	if !ok || c == nil || t == nil {
+		return false
+	}
+	return (len(t.Code) == 0 || t.Code == c.Code) && (len(t.Reason) == 0 || t.Reason == c.Reason)
+}
+
 // MetadataPatch is a patch for metadata
 type MetadataPatch struct {
 	// Annotations are additional metadata that can be included.
@@ -91,6 +91,13 @@ type BackendRef struct {
 
 	// Name is the name of the referent.
 	Name string `json:"name"`
+
+	// Namespace is the namespace of the referent if it is not in the namespace
+	// of TrafficTopology. The namespace must allow the namespace of
+	// TrafficTopology by annotation rollout.kusionstack.io/cross-namespace-allowed-from.
+	//
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
 }
 
 type RouteRef struct {
@@ -111,6 +118,13 @@ type RouteRef struct {
 	Kind *string `json:"kind,omitempty"`
 	// Name is the name of the custom route.
 	Name string `json:"name"`
+	// Namespace is the namespace of the route if it is not in the namespace of
+	// TrafficTopology, e.g. the route of a shared gateway. The namespace must
+	// allow the namespace of TrafficTopology by annotation
+	// rollout.kusionstack.io/cross-namespace-allowed-from.
+	//
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
 }
 
 type TrafficTopologyStatus struct {
@@ -179,6 +193,32 @@ type BackendRoutingSpec struct {
 type BackendForwarding struct {
 	Stable StableBackendRule `json:"stable,omitempty"`
 	Canary CanaryBackendRule `json:"canary,omitempty"`
+	// Variants are the canary backends of variants, traffic is split between
+	// stable and them by their weights. It is used instead of canary.
+	// +optional
+	Variants []CanaryVariantBackendRule `json:"variants,omitempty"`
+}
+
+// HasCanary returns true if any traffic is forwarded to canary or its variants.
+func (f *BackendForwarding) HasCanary() bool {
+	return f != nil && (len(f.Canary.Name) > 0 || len(f.Variants) > 0)
+}
+
+type CanaryVariantBackendRule struct {
+	// Variant is the name of canary variant
+	Variant string `json:"variant"`
+	// the temporary canary backend service name of variant, generally it is
+	// the {originServiceName}-canary-{variant}
+	Name string `json:"name"`
+	// Namespace is the namespace of canary pods if they are not in the namespace
+	// of backend, the traffic provider must route canary backend across namespaces.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Weight is the percentage of traffic routed to the variant.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Weight int32 `json:"weight"`
 }
 
 type StableBackendRule struct {
@@ -188,8 +228,15 @@ type StableBackendRule struct {
 
 type CanaryBackendRule struct {
 	// the temporary canary backend service name, generally it is the {originServiceName}-canary
-	Name            string `json:"name,omitempty"`
+	Name string `json:"name,omitempty"`
+	// Namespace is the namespace of canary pods if they are not in the namespace
+	// of backend, the traffic provider must route canary backend across namespaces.
+	// +optional
+	Namespace       string `json:"namespace,omitempty"`
 	TrafficStrategy `json:",inline"`
+	// Draining indicates that the canary backend stops receiving new sessions,
+	// only the existing sticky sessions are still routed to it.
+	Draining bool `json:"draining,omitempty"`
 }
 
 type TrafficStrategy struct {
@@ -199,6 +246,235 @@ type TrafficStrategy struct {
 	// +kubebuilder:validation:Maximum=100
 	Weight   *int32         `json:"weight,omitempty"`
 	HTTPRule *HTTPRouteRule `json:"http,omitempty"`
+	// GRPCRule routes the matched gRPC requests to canary. It is only supported
+	// by gRPC routes (e.g. Gateway API GRPCRoute), and ignored by HTTP routes.
+	GRPCRule *GRPCRouteRule `json:"grpc,omitempty"`
+	// SessionDrain defines how to drain the existing sticky sessions on canary
+	// before canary is deleted. It requires the route to support session affinity,
+	// the default cookie affinity is implied if sessionAffinity is not set.
+	// It only works in canary.
+	SessionDrain *SessionDrainStrategy `json:"sessionDrain,omitempty"`
+	// VerifyProbe defines a synthetic HTTP request sent through the canary route
+	// after the route is ready, to verify canary traffic is actually routed.
+	// It only works in canary.
+	VerifyProbe *TrafficVerifyProbe `json:"verifyProbe,omitempty"`
+	// SplitProbe sends a batch of labeled requests after canary traffic is
+	// verified, and counts the requests served by canary to check that the
+	// observed split matches the canary weight. It requires weight and only
+	// works in canary.
+	// +optional
+	SplitProbe *TrafficSplitProbe `json:"splitProbe,omitempty"`
+	// PropagationChecks verify that a traffic change has taken effect on the
+	// routes of each kind, beyond BackendRouting being ready which only means
+	// the routes are accepted. Each check polls a field of the routes of its
+	// kind after every traffic change. It only works in canary.
+	// +optional
+	PropagationChecks []TrafficPropagationCheck `json:"propagationChecks,omitempty"`
+	// RevertRamp defines how to return the canary weight to stable gradually
+	// before canary traffic is reverted. It requires weight to be set.
+	// It only works in canary.
+	RevertRamp *TrafficRevertRamp `json:"revertRamp,omitempty"`
+	// PausePolicy defines the canary traffic while canary is paused after the
+	// post canary step hook. The current weight is held if not set. It requires
+	// weight to be set. It only works in canary.
+	PausePolicy *TrafficPausePolicy `json:"pausePolicy,omitempty"`
+	// SessionAffinity keeps the requests of a session on the same backend, so that
+	// a user who lands on canary stays on it until canary traffic is reverted.
+	// It is supported by nginx Ingress, other routes (e.g. MSE Ingress) fail to
+	// add the canary route. It only works in canary.
+	SessionAffinity *TrafficSessionAffinity `json:"sessionAffinity,omitempty"`
+	// Allocation splits traffic between experiment variants and stable in
+	// percentages, the sum of all variants and stable must be 100. It is
+	// translated to the canary weight, so it cannot be used with weight.
+	// If it has more than one variant, canary variants must be set to the
+	// same variants. It only works in canary.
+	Allocation *TrafficAllocation `json:"allocation,omitempty"`
+	// Ports are the names of backend Service ports whose traffic is split to
+	// canary, the traffic of other ports, e.g. gRPC or metrics, is kept on
+	// stable. All ports are split if not set. It can not be used with
+	// allocation. It only works in canary.
+	// +optional
+	Ports []string `json:"ports,omitempty"`
+}
+
+// CanaryWeight returns the weight of canary traffic, it is the weight if set,
+// otherwise the total percentage of variants in allocation.
+func (t *TrafficStrategy) CanaryWeight() *int32 {
+	if t.Weight != nil || t.Allocation == nil {
+		return t.Weight
+	}
+	weight := t.Allocation.CanaryPercent()
+	return &weight
+}
+
+type TrafficAllocation struct {
+	// Variants are the experiment variants served by canary.
+	Variants []TrafficVariant `json:"variants"`
+	// StablePercent is the percentage of traffic the stable pods should receive.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	StablePercent int32 `json:"stablePercent"`
+}
+
+type TrafficVariant struct {
+	// Name is the unique name of variant.
+	Name string `json:"name"`
+	// Percent is the percentage of traffic this variant should receive.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Percent int32 `json:"percent"`
+}
+
+// CanaryPercent returns the total percentage of traffic of all variants.
+func (a *TrafficAllocation) CanaryPercent() int32 {
+	var sum int32
+	for _, v := range a.Variants {
+		sum += v.Percent
+	}
+	return sum
+}
+
+// SessionAffinityType is the sticky session mechanism of route.
+// +kubebuilder:validation:Enum=Cookie
+type SessionAffinityType string
+
+const (
+	// CookieSessionAffinity pins a session to a backend by an affinity cookie.
+	CookieSessionAffinity SessionAffinityType = "Cookie"
+)
+
+type TrafficSessionAffinity struct {
+	// Type is the sticky session mechanism, only Cookie is supported, since
+	// the routes pick canary or stable for each request by weight, a session
+	// can only be pinned by a cookie set on the first response.
+	Type SessionAffinityType `json:"type"`
+	// CookieName is the name of affinity cookie.
+	// Defaults to "rollout-canary".
+	// +optional
+	CookieName string `json:"cookieName,omitempty"`
+}
+
+type TrafficPausePolicy struct {
+	// ObservationWeight is the reduced canary weight during the pause, the
+	// intended weight is restored when canary is resumed, before it is recycled.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	ObservationWeight int32 `json:"observationWeight"`
+}
+
+type TrafficRevertRamp struct {
+	// Steps is the number of increments to return traffic to stable, the canary
+	// weight is decreased evenly in each step and the last step reverts canary.
+	//
+	// +kubebuilder:validation:Minimum=2
+	Steps int32 `json:"steps"`
+	// IntervalSeconds is the period to hold each step. Defaults to 30.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	IntervalSeconds int32 `json:"intervalSeconds,omitempty"`
+}
+
+type TrafficVerifyProbe struct {
+	// URL is the address to send the probe request to, it should be routed to
+	// canary by the canary traffic rule, e.g. http://gateway.example.com/healthz.
+	URL string `json:"url"`
+	// Host overrides the Host header of the probe request.
+	// +optional
+	Host string `json:"host,omitempty"`
+	// Headers are added to the probe request, e.g. the headers matched by canary http rule.
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+	// ExpectedStatus is the expected status code of the probe response.
+	// Defaults to any 2xx status code.
+	// +optional
+	ExpectedStatus *int32 `json:"expectedStatus,omitempty"`
+	// TimeoutSeconds is the timeout of each probe request. Defaults to 5.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+	// BudgetSeconds is the total period to keep retrying the probe before the
+	// canary fails. Defaults to 60.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	BudgetSeconds int32 `json:"budgetSeconds,omitempty"`
+	// SuccessThreshold is the number of consecutive successful probes for the
+	// canary traffic to be verified. Defaults to 1.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	SuccessThreshold int32 `json:"successThreshold,omitempty"`
+}
+
+type TrafficSplitProbe struct {
+	// URL is the address to send the probe requests to, it should be routed
+	// by the weighted canary traffic rule, e.g. http://gateway.example.com/version.
+	URL string `json:"url"`
+	// Host overrides the Host header of the probe requests.
+	// +optional
+	Host string `json:"host,omitempty"`
+	// Headers are added to the probe requests.
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+	// CanaryHeader is the name of the response header set by the application
+	// to tell the request is served by canary.
+	CanaryHeader string `json:"canaryHeader"`
+	// CanaryHeaderValue is the expected value of CanaryHeader on the responses
+	// of canary. If not set, any non empty value means canary.
+	// +optional
+	CanaryHeaderValue string `json:"canaryHeaderValue,omitempty"`
+	// Requests is the number of requests sent in a batch. Defaults to 100.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Requests int32 `json:"requests,omitempty"`
+	// TolerancePercent is the allowed difference in percent between the
+	// observed canary split and the canary weight. Defaults to 5.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	TolerancePercent *int32 `json:"tolerancePercent,omitempty"`
+	// TimeoutSeconds is the timeout of each probe request. Defaults to 5.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// TrafficPropagationCheck polls a field of routes until it has the expected
+// value, e.g. the Programmed condition of Gateway API routes or the load
+// balancer status of Ingress, since route providers report propagation
+// differently.
+type TrafficPropagationCheck struct {
+	// ObjectTypeRef is the kind of routes to check, e.g. networking.k8s.io/v1 Ingress.
+	ObjectTypeRef `json:",inline"`
+	// FieldPath is the JSONPath of the field in route, e.g.
+	// {.status.conditions[?(@.type=="Programmed")].status}.
+	FieldPath string `json:"fieldPath"`
+	// Value is the expected value of the field. If not set, the field must be
+	// present and not empty.
+	// +optional
+	Value string `json:"value,omitempty"`
+	// TimeoutSeconds is the period to keep polling after a traffic change
+	// before the canary fails. Defaults to 60.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+type SessionDrainStrategy struct {
+	// Seconds is the period to wait for existing sticky sessions to drain after
+	// canary stops receiving new sessions.
+	//
+	// +kubebuilder:validation:Minimum=1
+	Seconds int32 `json:"seconds"`
 }
 
 type BackendRoutingStatus struct {
@@ -219,11 +495,22 @@ type BackendStatuses struct {
 	Stable BackendStatus `json:"stable,omitempty"`
 	// Canary backend status
 	Canary BackendStatus `json:"canary,omitempty"`
+	// Variants are the statuses of canary backends of variants
+	// +optional
+	Variants []BackendStatus `json:"variants,omitempty"`
 }
 
 type BackendStatus struct {
 	// Name is the name of the referent.
 	Name string `json:"name"`
+	// Namespace is the namespace of the canary backend if it is created out of
+	// the namespace of origin backend. It is only used by canary backends.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Ports are the names of backend ports whose traffic is routed to the
+	// canary backend, empty means all ports. It is only used by canary backend.
+	// +optional
+	Ports []string `json:"ports,omitempty"`
 	// Conditions represents the current condition of an backend.
 	Conditions BackendConditions `json:"conditions,omitempty"`
 }
@@ -17,8 +17,12 @@
 package validation
 
 import (
+	"fmt"
+	"slices"
+
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	appsvalidation "k8s.io/kubernetes/pkg/apis/apps/validation"
 
@@ -35,6 +39,12 @@ func ValidateRolloutRun(obj *rolloutv1alpha1.RolloutRun) field.ErrorList {
 func ValidateRolloutRunSpec(spec *rolloutv1alpha1.RolloutRunSpec, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 
+	// validate the spec with parameters substituted
+	spec, _, missing := spec.ResolveParameters(spec.Parameters)
+	for _, name := range missing {
+		allErrs = append(allErrs, field.Required(fldPath.Child("parameters").Key(name), "parameter is referenced but not provided"))
+	}
+
 	allErrs = append(allErrs, ValidateWebhooks(spec.Webhooks, fldPath.Child("webhooks"))...)
 
 	if spec.Canary != nil && spec.Batch == nil {
@@ -42,6 +52,7 @@ func ValidateRolloutRunSpec(spec *rolloutv1alpha1.RolloutRunSpec, fldPath *field
 	}
 
 	allErrs = append(allErrs, ValidateRolloutRunCanaryStrategy(spec.Canary, fldPath.Child("canary"))...)
+	allErrs = append(allErrs, validateCanaryStepHookStates(spec.Canary, spec.Webhooks, fldPath.Child("canary", "states"))...)
 	allErrs = append(allErrs, ValidateRolloutRunBatchStrategy(spec.Batch, fldPath.Child("batch"))...)
 
 	return allErrs
@@ -56,11 +67,146 @@ func ValidateRolloutRunCanaryStrategy(canary *rolloutv1alpha1.RolloutRunCanarySt
 
 	// validate targets
 	allErrs = append(allErrs, validateRolloutRunStepTargets(canary.Targets, fldPath.Child("targets"))...)
+	for i, target := range canary.Targets {
+		if target.ReadinessTimeoutSeconds != nil && *target.ReadinessTimeoutSeconds <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("targets").Index(i).Child("readinessTimeoutSeconds"), *target.ReadinessTimeoutSeconds, "must be greater than 0"))
+		}
+		if canary.Strategy == rolloutv1alpha1.CanaryStrategyBlueGreen && target.Replicas != blueGreenReplicas {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("targets").Index(i).Child("replicas"), target.Replicas.String(), "must be 100% with blue/green"))
+		}
+		allErrs = append(allErrs, validateCanaryNamespace(target.CanaryNamespace, fldPath.Child("targets").Index(i).Child("canaryNamespace"))...)
+		allErrs = append(allErrs, validateCanaryConfigOverrides(target.ConfigOverrides, canary.Ordinals, fldPath.Child("targets").Index(i).Child("configOverrides"))...)
+		allErrs = append(allErrs, validateCanaryInitContainerOverrides(target.InitContainerOverrides, canary.Ordinals, fldPath.Child("targets").Index(i).Child("initContainerOverrides"))...)
+		allErrs = append(allErrs, validateCanaryOwnerReferences(target.OwnerReferences, fldPath.Child("targets").Index(i).Child("ownerReferences"))...)
+		allErrs = append(allErrs, validateCanaryPodDisruptionBudget(target.PodDisruptionBudget, target.Replicas, canary.Ordinals, fldPath.Child("targets").Index(i).Child("podDisruptionBudget"))...)
+	}
 	// validate pod template metadata path
 	allErrs = append(allErrs, validatePodTemplatePatch(canary.PodTemplateMetadataPatch, fldPath.Child("podTemplateMetadataPath"))...)
+	// validate object metadata patch
+	allErrs = append(allErrs, validateObjectMetadataPatch(canary.ObjectMetadataPatch, fldPath.Child("objectMetadataPatch"))...)
 	// validate traffic
 	allErrs = append(allErrs, validateTrafficStrategy(canary.Traffic, fldPath.Child("traffic"))...)
+	// validate promotion windows
+	allErrs = append(allErrs, validatePromotionWindows(canary.PromotionWindows, fldPath.Child("promotionWindows"))...)
+	// validate analysis
+	allErrs = append(allErrs, validateCanaryAnalysis(canary.Analysis, fldPath.Child("analysis"))...)
+	// validate recycle order
+	allErrs = append(allErrs, validateCanaryRecycleOrder(canary.RecycleOrder, fldPath.Child("recycleOrder"))...)
+	allErrs = append(allErrs, validateCanaryRetention(canary.RetentionSeconds, canary.Ordinals, fldPath.Child("retentionSeconds"))...)
+	// validate replicas following traffic weight
+	allErrs = append(allErrs, validateReplicasFollowTrafficWeight(canary.ReplicasFollowTrafficWeight, canary.Traffic, fldPath.Child("replicasFollowTrafficWeight"))...)
+	// validate max active duration
+	allErrs = append(allErrs, validateCanaryMaxActiveDuration(canary.MaxActiveDuration, fldPath.Child("maxActiveDuration"))...)
+	allErrs = append(allErrs, validateCanaryNotifications(canary.Notifications, fldPath.Child("notifications"))...)
+	// validate bake schedule
+	allErrs = append(allErrs, validateCanaryBake(canary.Bake, fldPath.Child("bake"))...)
+	// validate stable min available
+	allErrs = append(allErrs, validateStableMinAvailable(canary.StableMinAvailable, fldPath.Child("stableMinAvailable"))...)
+	// validate stuck deletion
+	allErrs = append(allErrs, validateCanaryStuckDeletion(canary.StuckDeletion, fldPath.Child("stuckDeletion"))...)
+	// validate drift detection
+	allErrs = append(allErrs, validateCanaryDriftDetection(canary.DriftDetection, fldPath.Child("driftDetection"))...)
+	// validate auto rollback
+	allErrs = append(allErrs, validateCanaryAutoRollback(canary.AutoRollback, fldPath.Child("autoRollback"))...)
+	allErrs = append(allErrs, validateCanaryImagePullCheck(canary.ImagePullCheck, fldPath.Child("imagePullCheck"))...)
+	// validate ordinals
+	allErrs = append(allErrs, validateCanaryOrdinals(canary.Ordinals, canary.Bake, canary.ReplicasFollowTrafficWeight, fldPath.Child("ordinals"))...)
+	// validate pause before
+	allErrs = append(allErrs, validateCanaryPauseBefore(canary.PauseBefore, fldPath.Child("pauseBefore"))...)
+	// validate strategy type
+	allErrs = append(allErrs, validateCanaryStrategyType(canary.Strategy, canary.Traffic, canary.Ordinals, canary.Bake, canary.ReplicasFollowTrafficWeight, fldPath.Child("strategy"))...)
+	// validate variants
+	allErrs = append(allErrs, validateCanaryVariants(canary.Variants, canary.Traffic, canary.Strategy, canary.Ordinals, canary.ReplicasFollowTrafficWeight, canary.ScaleUpStep, fldPath.Child("variants"))...)
+	allErrs = append(allErrs, validateCanaryUpdateStrategy(canary.UpdateStrategy, canary.Ordinals, fldPath.Child("updateStrategy"))...)
+	// validate pod placement patch
+	allErrs = append(allErrs, validatePodPlacementPatch(canary.PodPlacementPatch, canary.Ordinals, fldPath.Child("podPlacementPatch"))...)
+	// validate guards
+	allErrs = append(allErrs, validateStepGuards(canary.Guards, fldPath.Child("guards"))...)
+	// validate scale up step
+	allErrs = append(allErrs, validateCanaryScaleUpStep(canary.ScaleUpStep, fldPath.Child("scaleUpStep"))...)
+	// validate warm up
+	allErrs = append(allErrs, validateCanaryWarmUp(canary.WarmUp, fldPath.Child("warmUp"))...)
+	// validate readiness stabilization
+	allErrs = append(allErrs, validateCanaryReadinessStabilization(canary.ReadinessStabilization, fldPath.Child("readinessStabilization"))...)
+	allErrs = append(allErrs, validateCanaryReadinessExpression(canary.ReadinessExpression, fldPath.Child("readinessExpression"))...)
+	// validate precondition refs
+	allErrs = append(allErrs, validateCanaryPreconditionRefs(canary.PreconditionRefs, fldPath.Child("preconditionRefs"))...)
+	allErrs = append(allErrs, validateCanaryPreconditionRefs(canary.Prerequisites, fldPath.Child("prerequisites"))...)
+	// validate step states
+	allErrs = append(allErrs, validateCanaryStepStates(canary, fldPath.Child("states"))...)
+
+	return allErrs
+}
+
+// validateCanaryStepStates checks that states form a linear path from the
+// initial state to Succeeded, which is a subsequence of DefaultCanaryStepStates
+// containing all the required states.
+func validateCanaryStepStates(canary *rolloutv1alpha1.RolloutRunCanaryStrategy, fldPath *field.Path) field.ErrorList {
+	states := canary.States
+	if len(states) == 0 {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+
+	order := map[rolloutv1alpha1.RolloutStepState]int{}
+	supported := make([]string, 0, len(rolloutv1alpha1.DefaultCanaryStepStates))
+	for i, state := range rolloutv1alpha1.DefaultCanaryStepStates {
+		order[state] = i
+		supported = append(supported, string(state))
+	}
+
+	included := sets.NewString()
+	last := -1
+	for i, state := range states {
+		idx, ok := order[state]
+		if !ok {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Index(i), state, supported))
+			continue
+		}
+		if included.Has(string(state)) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), state))
+			continue
+		}
+		if idx < last {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), state, fmt.Sprintf("must be in the order of %v", supported)))
+		}
+		included.Insert(string(state))
+		last = idx
+	}
+
+	for _, state := range rolloutv1alpha1.RequiredCanaryStepStates {
+		if !included.Has(string(state)) {
+			allErrs = append(allErrs, field.Required(fldPath, fmt.Sprintf("must contain %s", state)))
+		}
+	}
+
+	// traffic of blue/green is switched after post canary step hook
+	if !included.Has(string(rolloutv1alpha1.RolloutStepPostCanaryStepHook)) && canary.Strategy == rolloutv1alpha1.CanaryStrategyBlueGreen {
+		allErrs = append(allErrs, field.Invalid(fldPath, states, fmt.Sprintf("must contain %s with blue/green", rolloutv1alpha1.RolloutStepPostCanaryStepHook)))
+	}
+	return allErrs
+}
 
+// validateCanaryStepHookStates checks that the hook states are not left out of
+// canary states if there are webhooks of their hook type, otherwise the
+// webhooks are skipped silently.
+func validateCanaryStepHookStates(canary *rolloutv1alpha1.RolloutRunCanaryStrategy, webhooks []rolloutv1alpha1.RolloutWebhook, fldPath *field.Path) field.ErrorList {
+	if canary == nil || len(canary.States) == 0 {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+	for _, state := range rolloutv1alpha1.DefaultCanaryStepStates {
+		hookType, ok := rolloutv1alpha1.CanaryStepHookStates[state]
+		if !ok || slices.Contains(canary.States, state) {
+			continue
+		}
+		for _, webhook := range webhooks {
+			if slices.Contains(webhook.HookTypes, hookType) {
+				allErrs = append(allErrs, field.Invalid(fldPath, canary.States, fmt.Sprintf("must contain %s since webhook %s is called at %s", state, webhook.Name, hookType)))
+				break
+			}
+		}
+	}
 	return allErrs
 }
 
@@ -86,8 +232,30 @@ func ValidateRolloutRunBatchStrategy(batch *rolloutv1alpha1.RolloutRunBatchStrat
 func validateRolloutRunStep(step *rolloutv1alpha1.RolloutRunStep, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 	allErrs = append(allErrs, validateRolloutRunStepTargets(step.Targets, fldPath.Child("targets"))...)
+	for i, target := range step.Targets {
+		if target.ReadinessTimeoutSeconds != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("targets").Index(i).Child("readinessTimeoutSeconds"), "readiness timeout is only supported in canary"))
+		}
+		if len(target.CanaryNamespace) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("targets").Index(i).Child("canaryNamespace"), "canary namespace is only supported in canary"))
+		}
+		if len(target.ConfigOverrides) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("targets").Index(i).Child("configOverrides"), "config overrides are only supported in canary"))
+		}
+		if len(target.InitContainerOverrides) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("targets").Index(i).Child("initContainerOverrides"), "init container overrides are only supported in canary"))
+		}
+		if len(target.OwnerReferences) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("targets").Index(i).Child("ownerReferences"), "owner references are only supported in canary"))
+		}
+		if target.PodDisruptionBudget != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("targets").Index(i).Child("podDisruptionBudget"), "pod disruption budget is only supported in canary"))
+		}
+	}
 	// validate traffic
-	allErrs = append(allErrs, validateTrafficStrategy(step.Traffic, fldPath.Child("traffic"))...)
+	allErrs = append(allErrs, validateStepTrafficStrategy(step.Traffic, fldPath.Child("traffic"))...)
+	// validate guards
+	allErrs = append(allErrs, validateStepGuards(step.Guards, fldPath.Child("guards"))...)
 	return allErrs
 }
 
@@ -135,6 +303,10 @@ func ValidateRolloutRunUpdate(newObj, oldObj *rolloutv1alpha1.RolloutRun) field.
 		if !apiequality.Semantic.DeepEqual(newObj.Spec.Canary.PodTemplateMetadataPatch, oldObj.Spec.Canary.PodTemplateMetadataPatch) {
 			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec").Child("canary").Child("podTemplateMetadataPatch"), "podTemplateMetadataPatch is immutable"))
 		}
+		// pod placement patch is merged into canary pod template, it can not be reverted
+		if !apiequality.Semantic.DeepEqual(newObj.Spec.Canary.PodPlacementPatch, oldObj.Spec.Canary.PodPlacementPatch) {
+			allErrs = append(allErrs, field.Forbidden(field.NewPath("spec").Child("canary").Child("podPlacementPatch"), "podPlacementPatch is immutable"))
+		}
 
 		// check orthers immutable fields according to current state
 		beforeRunning := true
@@ -19,9 +19,11 @@ package validation
 import (
 	"testing"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
+	gatewayapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
 )
@@ -147,6 +149,18 @@ func TestValidateRolloutRun(t *testing.T) {
 			wantErr: true,
 			errLen:  3,
 		},
+		{
+			name: "webhook target mode on non-step hook, aggregation without target mode",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Webhooks[0].TargetMode = rolloutv1alpha1.WebhookPerTarget
+				obj.Spec.Webhooks[0].HookTypes = append(obj.Spec.Webhooks[0].HookTypes, rolloutv1alpha1.PreRunHook)
+				obj.Spec.Webhooks[1].TargetAggregation = rolloutv1alpha1.WebhookPerTargetGating
+				return obj
+			}(),
+			wantErr: true,
+			errLen:  2,
+		},
 		{
 			name: "set canary with out batch",
 			obj: func() *rolloutv1alpha1.RolloutRun {
@@ -234,6 +248,674 @@ func TestValidateRolloutRun(t *testing.T) {
 			wantErr: true,
 			errLen:  2,
 		},
+		{
+			name: "session drain in batch",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				drain := &rolloutv1alpha1.SessionDrainStrategy{Seconds: 60}
+				obj.Spec.Canary.Traffic = validTraffic.DeepCopy()
+				obj.Spec.Canary.Traffic.SessionDrain = drain
+				obj.Spec.Batch.Batches[0].Traffic = validTraffic.DeepCopy()
+				obj.Spec.Batch.Batches[0].Traffic.SessionDrain = drain
+				return obj
+			}(),
+			wantErr: true,
+			errLen:  1,
+		},
+		{
+			name: "session affinity in batch",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				affinity := &rolloutv1alpha1.TrafficSessionAffinity{Type: rolloutv1alpha1.CookieSessionAffinity}
+				obj.Spec.Canary.Traffic = validTraffic.DeepCopy()
+				obj.Spec.Canary.Traffic.SessionAffinity = affinity
+				obj.Spec.Batch.Batches[0].Traffic = validTraffic.DeepCopy()
+				obj.Spec.Batch.Batches[0].Traffic.SessionAffinity = affinity
+				return obj
+			}(),
+			wantErr: true,
+			errLen:  1,
+		},
+		{
+			name: "unsupported session affinity type",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.Traffic = validTraffic.DeepCopy()
+				obj.Spec.Canary.Traffic.SessionAffinity = &rolloutv1alpha1.TrafficSessionAffinity{
+					Type: "ConsistentHash",
+				}
+				return obj
+			}(),
+			wantErr: true,
+			errLen:  1,
+		},
+		{
+			name: "invalid promotion windows",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.PromotionWindows = &rolloutv1alpha1.PromotionWindows{
+					TimeZone: "Invalid/Zone",
+					Windows: []rolloutv1alpha1.TimeWindow{
+						{Start: "9:00am", End: "18:00"},
+					},
+				}
+				return obj
+			}(),
+			wantErr: true,
+			errLen:  2,
+		},
+		{
+			name: "resolved parameters",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Parameters = map[string]string{"host": "example.com", "replicas": "2"}
+				obj.Spec.Webhooks[0].ClientConfig.URL = "http://${host}/hook"
+				obj.Spec.Canary.Targets[0].Replicas = intstr.FromString("${replicas}")
+				return obj
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "missing parameters",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Webhooks[0].ClientConfig.URL = "http://${host}/hook"
+				obj.Spec.Canary.Targets[0].Replicas = intstr.FromString("${replicas}")
+				return obj
+			}(),
+			wantErr: true,
+			// missing host and replicas, invalid url and replicas
+			errLen: 4,
+		},
+		{
+			name: "canary states without hooks",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.States = []rolloutv1alpha1.RolloutStepState{
+					rolloutv1alpha1.RolloutStepPending,
+					rolloutv1alpha1.RolloutStepRunning,
+					rolloutv1alpha1.RolloutStepResourceRecycling,
+					rolloutv1alpha1.RolloutStepSucceeded,
+				}
+				return obj
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "canary states without resource recycling",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.States = []rolloutv1alpha1.RolloutStepState{
+					rolloutv1alpha1.RolloutStepPending,
+					rolloutv1alpha1.RolloutStepRunning,
+					rolloutv1alpha1.RolloutStepSucceeded,
+				}
+				return obj
+			}(),
+			wantErr: true,
+			errLen:  1,
+		},
+		{
+			name: "canary states without hooks of webhooks",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Webhooks = []rolloutv1alpha1.RolloutWebhook{
+					{
+						Name:         "wh-1",
+						HookTypes:    []rolloutv1alpha1.HookType{rolloutv1alpha1.PreCanaryStepHook, rolloutv1alpha1.PostCanaryStepHook},
+						ClientConfig: rolloutv1alpha1.WebhookClientConfig{URL: "http://hook.example.com"},
+					},
+				}
+				obj.Spec.Canary.States = []rolloutv1alpha1.RolloutStepState{
+					rolloutv1alpha1.RolloutStepPending,
+					rolloutv1alpha1.RolloutStepRunning,
+					rolloutv1alpha1.RolloutStepResourceRecycling,
+					rolloutv1alpha1.RolloutStepSucceeded,
+				}
+				return obj
+			}(),
+			wantErr: true,
+			// PreCanaryStepHook and PostCanaryStepHook required by wh-1
+			errLen: 2,
+		},
+		{
+			name: "invalid canary states",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.States = []rolloutv1alpha1.RolloutStepState{
+					rolloutv1alpha1.RolloutStepRunning,
+					rolloutv1alpha1.RolloutStepPending,
+					rolloutv1alpha1.RolloutStepPending,
+					rolloutv1alpha1.RolloutStepPreBatchStepHook,
+				}
+				return obj
+			}(),
+			wantErr: true,
+			// out of order, duplicate, not supported, missing ResourceRecycling and Succeeded
+			errLen: 5,
+		},
+		{
+			name: "canary ordinals",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.Ordinals = []int32{3, 4}
+				return obj
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "invalid canary ordinals",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.Ordinals = []int32{-1, 4, 4}
+				obj.Spec.Canary.Bake = &rolloutv1alpha1.CanaryBake{Windows: 1, ActiveSeconds: 10, IdleSeconds: 10}
+				return obj
+			}(),
+			wantErr: true,
+			// negative, duplicate, forbidden with bake
+			errLen: 3,
+		},
+		{
+			name: "canary config overrides",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.Targets[0].ConfigOverrides = []rolloutv1alpha1.CanaryConfigOverride{
+					{Kind: rolloutv1alpha1.CanaryConfigMap, Name: "app-config", CanaryName: "app-config-canary"},
+					{Kind: rolloutv1alpha1.CanaryConfigSecret, Name: "app-config", CanaryName: "app-secret-canary"},
+				}
+				return obj
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "invalid canary config overrides",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.Ordinals = []int32{4}
+				obj.Spec.Canary.Targets[0].ConfigOverrides = []rolloutv1alpha1.CanaryConfigOverride{
+					{Kind: "Volume", Name: "app-config", CanaryName: "app-config-canary"},
+					{Kind: rolloutv1alpha1.CanaryConfigMap, Name: "app-config", CanaryName: "app-config"},
+				}
+				return obj
+			}(),
+			wantErr: true,
+			// forbidden with ordinals, kind not supported, same canary name
+			errLen: 3,
+		},
+		{
+			name: "invalid canary init container overrides",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.Targets[0].InitContainerOverrides = []corev1.Container{
+					{Name: "migrate", Image: "migrate:v1"},
+					{Name: "migrate", Image: "migrate:v2"},
+					{Name: "Setup"},
+				}
+				obj.Spec.Batch.Batches[0].Targets[0].InitContainerOverrides = []corev1.Container{
+					{Name: "migrate", Image: "migrate:v1"},
+				}
+				return obj
+			}(),
+			wantErr: true,
+			// duplicate name, invalid name, image required, forbidden in batch
+			errLen: 4,
+		},
+		{
+			name: "invalid canary precondition refs",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.PreconditionRefs = []rolloutv1alpha1.CanaryPreconditionRef{
+					{
+						CrossClusterObjectReference: rolloutv1alpha1.CrossClusterObjectReference{
+							ObjectTypeRef:                   rolloutv1alpha1.ObjectTypeRef{APIVersion: "flags.example.com/v1", Kind: "FeatureFlag"},
+							CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Name: "new-checkout"},
+						},
+						FieldPath: `{.status.conditions[?(@.type=="Ready")].status}`,
+					},
+					{
+						FieldPath:      "{.status",
+						TimeoutSeconds: -1,
+					},
+				}
+				return obj
+			}(),
+			wantErr: true,
+			// kind required, name required, invalid field path, negative timeout
+			errLen: 4,
+		},
+		{
+			name: "invalid canary traffic verify probe",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.Traffic = &rolloutv1alpha1.TrafficStrategy{
+					VerifyProbe: &rolloutv1alpha1.TrafficVerifyProbe{
+						URL:              "http://gateway.example.com/healthz",
+						SuccessThreshold: -1,
+					},
+				}
+				return obj
+			}(),
+			wantErr: true,
+			errLen:  1,
+		},
+		{
+			name: "canary blue/green",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.Strategy = rolloutv1alpha1.CanaryStrategyBlueGreen
+				obj.Spec.Canary.Targets[0].Replicas = intstr.FromString("100%")
+				obj.Spec.Canary.Targets[1].Replicas = intstr.FromString("100%")
+				obj.Spec.Canary.Traffic = &rolloutv1alpha1.TrafficStrategy{Weight: ptr.To[int32](100)}
+				return obj
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "invalid canary blue/green",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.Strategy = rolloutv1alpha1.CanaryStrategyBlueGreen
+				obj.Spec.Canary.Targets[1].Replicas = intstr.FromString("100%")
+				obj.Spec.Canary.Traffic = &rolloutv1alpha1.TrafficStrategy{
+					Weight:     ptr.To[int32](50),
+					RevertRamp: &rolloutv1alpha1.TrafficRevertRamp{Steps: 2},
+				}
+				obj.Spec.Canary.States = []rolloutv1alpha1.RolloutStepState{
+					rolloutv1alpha1.RolloutStepPending,
+					rolloutv1alpha1.RolloutStepRunning,
+					rolloutv1alpha1.RolloutStepResourceRecycling,
+					rolloutv1alpha1.RolloutStepSucceeded,
+				}
+				return obj
+			}(),
+			wantErr: true,
+			// target replicas not 100%, weight not 100, revert ramp forbidden,
+			// post canary step hook required
+			errLen: 4,
+		},
+		{
+			name: "invalid canary pause before",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.PauseBefore = []rolloutv1alpha1.CanaryPausePhase{
+					rolloutv1alpha1.CanaryPauseBeforeForkCanary,
+					"analysis",
+					rolloutv1alpha1.CanaryPauseBeforeForkCanary,
+				}
+				return obj
+			}(),
+			wantErr: true,
+			// not supported, duplicate
+			errLen: 2,
+		},
+		{
+			name: "invalid canary readiness stabilization",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.ReadinessStabilization = &rolloutv1alpha1.CanaryReadinessStabilization{ReadyPercent: 120}
+				return obj
+			}(),
+			wantErr: true,
+			// ready percent out of range, window required
+			errLen: 2,
+		},
+		{
+			name: "invalid canary drift detection",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.DriftDetection = &rolloutv1alpha1.CanaryDriftDetection{Threshold: 1, Action: "Ignore"}
+				return obj
+			}(),
+			wantErr: true,
+			// threshold too small, action not supported
+			errLen: 2,
+		},
+		{
+			name: "invalid canary auto rollback",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.AutoRollback = &rolloutv1alpha1.CanaryAutoRollback{WindowSeconds: ptr.To[int32](0)}
+				return obj
+			}(),
+			wantErr: true,
+			// max rollbacks and window must be positive
+			errLen: 2,
+		},
+		{
+			name: "unsatisfiable canary pod disruption budget",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.Targets[0].Replicas = intstr.FromInt(2)
+				obj.Spec.Canary.Targets[0].PodDisruptionBudget = &rolloutv1alpha1.CanaryPodDisruptionBudget{
+					MinAvailable: ptr.To(intstr.FromInt(2)),
+				}
+				obj.Spec.Canary.Targets[1].PodDisruptionBudget = &rolloutv1alpha1.CanaryPodDisruptionBudget{}
+				return obj
+			}(),
+			wantErr: true,
+			// no pod can be evicted, one of minAvailable and maxUnavailable is required
+			errLen: 2,
+		},
+		{
+			name: "canary readiness expression",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.ReadinessExpression = "status.updatedAvailableReplicas >= 1"
+				return obj
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "invalid canary readiness expression",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.ReadinessExpression = "status.updatedAvailableReplicas >="
+				return obj
+			}(),
+			wantErr: true,
+			// syntax error
+			errLen: 1,
+		},
+		{
+			name: "invalid canary prerequisites",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.Prerequisites = []rolloutv1alpha1.CanaryPreconditionRef{{
+					CrossClusterObjectReference: rolloutv1alpha1.CrossClusterObjectReference{
+						ObjectTypeRef: rolloutv1alpha1.ObjectTypeRef{APIVersion: "batch/v1", Kind: "Job"},
+					},
+					FieldPath: "{.status.succeeded",
+				}}
+				return obj
+			}(),
+			wantErr: true,
+			// name required, invalid field path
+			errLen: 2,
+		},
+		{
+			name: "canary traffic ports",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.Traffic = &rolloutv1alpha1.TrafficStrategy{
+					Weight: ptr.To[int32](20),
+					Ports:  []string{"http"},
+				}
+				return obj
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "invalid canary traffic ports",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.Traffic = &rolloutv1alpha1.TrafficStrategy{
+					Weight: ptr.To[int32](20),
+					Ports:  []string{"http", "", "http"},
+				}
+				return obj
+			}(),
+			wantErr: true,
+			// empty name, duplicate name
+			errLen: 2,
+		},
+		{
+			name: "canary traffic split probe",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.Traffic = &rolloutv1alpha1.TrafficStrategy{
+					Weight: ptr.To[int32](20),
+					SplitProbe: &rolloutv1alpha1.TrafficSplitProbe{
+						URL:          "http://gateway.example.com/version",
+						CanaryHeader: "X-Canary",
+					},
+				}
+				return obj
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "invalid canary traffic split probe",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.Traffic = &rolloutv1alpha1.TrafficStrategy{
+					HTTPRule: &rolloutv1alpha1.HTTPRouteRule{},
+					SplitProbe: &rolloutv1alpha1.TrafficSplitProbe{
+						URL:              "/version",
+						TolerancePercent: ptr.To[int32](101),
+					},
+				}
+				return obj
+			}(),
+			wantErr: true,
+			// weight required, invalid url, canary header required, invalid tolerance
+			errLen: 4,
+		},
+		{
+			name: "canary retention",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.RetentionSeconds = ptr.To[int32](1800)
+				return obj
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "invalid canary retention",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.RetentionSeconds = ptr.To[int32](0)
+				obj.Spec.Canary.Ordinals = []int32{0}
+				return obj
+			}(),
+			wantErr: true,
+			// invalid seconds, forbidden with ordinals
+			errLen: 2,
+		},
+		{
+			name: "canary grpc rule",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.Traffic = &rolloutv1alpha1.TrafficStrategy{
+					GRPCRule: &rolloutv1alpha1.GRPCRouteRule{
+						Matches: []rolloutv1alpha1.GRPCRouteMatch{{
+							Method: &gatewayapiv1alpha2.GRPCMethodMatch{Service: ptr.To("demo.Greeter"), Method: ptr.To("SayHello")},
+						}},
+					},
+				}
+				return obj
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "invalid canary grpc rule",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.Traffic = &rolloutv1alpha1.TrafficStrategy{
+					Weight: ptr.To[int32](10),
+					GRPCRule: &rolloutv1alpha1.GRPCRouteRule{
+						Matches: []rolloutv1alpha1.GRPCRouteMatch{{}, {Method: &gatewayapiv1alpha2.GRPCMethodMatch{}}},
+					},
+				}
+				return obj
+			}(),
+			wantErr: true,
+			// forbidden with weight, empty match, empty method
+			errLen: 3,
+		},
+		{
+			name: "invalid canary image pull check timeout",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.ImagePullCheck = &rolloutv1alpha1.CanaryImagePullCheck{TimeoutSeconds: ptr.To[int32](0)}
+				return obj
+			}(),
+			wantErr: true,
+			errLen:  1,
+		},
+		{
+			name: "invalid canary update strategy",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.UpdateStrategy = &rolloutv1alpha1.CanaryUpdateStrategy{
+					RollingUpdate: &rolloutv1alpha1.CanaryRollingUpdateStrategy{
+						MaxUnavailable: ptr.To(intstr.FromInt(0)),
+						MaxSurge:       ptr.To(intstr.FromString("120%")),
+					},
+				}
+				return obj
+			}(),
+			wantErr: true,
+			// max surge is more than 100%
+			errLen: 1,
+		},
+		{
+			name: "invalid canary pod placement patch",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.PodPlacementPatch = &rolloutv1alpha1.PodPlacementPatch{
+					Affinity: &corev1.Affinity{
+						PodAntiAffinity: &corev1.PodAntiAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{
+								LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}},
+							}},
+						},
+					},
+					TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+						{MaxSkew: 1, TopologyKey: "zone", WhenUnsatisfiable: corev1.DoNotSchedule},
+						{MaxSkew: 0, TopologyKey: "zone", WhenUnsatisfiable: "Never"},
+					},
+				}
+				return obj
+			}(),
+			wantErr: true,
+			// empty topologyKey, zero maxSkew, duplicate topologyKey and unsupported whenUnsatisfiable
+			errLen: 4,
+		},
+		{
+			name: "invalid canary analysis query template",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.Analysis = &rolloutv1alpha1.CanaryAnalysis{
+					Metrics: []rolloutv1alpha1.AnalysisMetric{
+						{
+							Name:     "error-rate",
+							Provider: rolloutv1alpha1.AnalysisProvider{Name: "prometheus"},
+							Query:    `sum(rate(http_errors_total{ {{.CanaryPods}} }[{{.Window}}]))`,
+							Max:      ptr.To("0.01"),
+						},
+						{
+							Name:     "latency",
+							Provider: rolloutv1alpha1.AnalysisProvider{Name: "prometheus"},
+							Query:    `histogram_quantile(0.99, {{.StableSelector}`,
+							Max:      ptr.To("500"),
+						},
+					},
+				}
+				return obj
+			}(),
+			wantErr: true,
+			// unknown variable, unclosed action
+			errLen: 2,
+		},
+		{
+			name: "invalid canary owner references",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.Targets[0].OwnerReferences = []metav1.OwnerReference{
+					{APIVersion: "cost.example.com/v1", Kind: "CostCenter", Name: "team-a", UID: "uid-cost", Controller: ptr.To(true)},
+					{APIVersion: "cost.example.com/v1", Kind: "CostCenter", Name: "team-b", UID: "uid-cost"},
+					{APIVersion: "cost.example.com/v1", Kind: "CostCenter"},
+				}
+				return obj
+			}(),
+			wantErr: true,
+			// controller, duplicate uid, missing name and uid
+			errLen: 4,
+		},
+		{
+			name: "invalid canary guards",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.Guards = []rolloutv1alpha1.StepGuard{
+					{
+						Name: "error-rate",
+						Metric: &rolloutv1alpha1.GuardMetric{
+							Provider: rolloutv1alpha1.AnalysisProvider{Name: "prometheus"},
+							Query:    "error_rate",
+							Max:      ptr.To("x"),
+						},
+					},
+					{
+						Name:    "error-rate",
+						Webhook: &rolloutv1alpha1.WebhookClientConfig{URL: "https://guard.example.com"},
+					},
+					{
+						Name: "both",
+					},
+				}
+				return obj
+			}(),
+			wantErr: true,
+			// invalid max, duplicate name and neither metric nor webhook
+			errLen: 3,
+		},
+		{
+			name: "invalid canary scale up step",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.ScaleUpStep = ptr.To(intstr.FromString("0%"))
+				return obj
+			}(),
+			wantErr: true,
+			// zero step
+			errLen: 1,
+		},
+		{
+			name: "invalid canary traffic pause policy",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.Traffic = &rolloutv1alpha1.TrafficStrategy{
+					Weight:      ptr.To[int32](10),
+					PausePolicy: &rolloutv1alpha1.TrafficPausePolicy{ObservationWeight: 10},
+				}
+				return obj
+			}(),
+			wantErr: true,
+			// observation weight is not less than weight
+			errLen: 1,
+		},
+		{
+			name: "invalid canary traffic propagation checks",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.Traffic = &rolloutv1alpha1.TrafficStrategy{
+					Weight: ptr.To[int32](10),
+					PropagationChecks: []rolloutv1alpha1.TrafficPropagationCheck{
+						{FieldPath: "{.status.loadBalancer.ingress[0].ip}"},
+						{ObjectTypeRef: rolloutv1alpha1.ObjectTypeRef{Kind: "Ingress"}, FieldPath: "{.status"},
+					},
+				}
+				return obj
+			}(),
+			wantErr: true,
+			// missing kind, invalid field path
+			errLen: 2,
+		},
+		{
+			name: "invalid canary warm up",
+			obj: func() *rolloutv1alpha1.RolloutRun {
+				obj := validRolloutRun.DeepCopy()
+				obj.Spec.Canary.WarmUp = &rolloutv1alpha1.CanaryWarmUp{
+					DurationSeconds: 60,
+					TimeoutSeconds:  30,
+					Requests:        &rolloutv1alpha1.CanaryWarmUpRequests{Path: "warm"},
+				}
+				return obj
+			}(),
+			wantErr: true,
+			// duration not less than timeout, invalid port, invalid path
+			errLen: 3,
+		},
 	}
 	for i := range tests {
 		tt := tests[i]
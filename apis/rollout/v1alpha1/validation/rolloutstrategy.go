@@ -17,13 +17,28 @@
 package validation
 
 import (
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	corev1 "k8s.io/api/core/v1"
 	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	webhookutil "k8s.io/apiserver/pkg/util/webhook"
+	"k8s.io/client-go/util/jsonpath"
 	appsvalidation "k8s.io/kubernetes/pkg/apis/apps/validation"
 
+	rolloutapi "kusionstack.io/rollout/apis/rollout"
 	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
 )
 
@@ -64,7 +79,8 @@ func ValidateRolloutStep(step *rolloutv1alpha1.RolloutStep, fldPath *field.Path)
 	allErrs := field.ErrorList{}
 	allErrs = append(allErrs, appsvalidation.ValidatePositiveIntOrPercent(step.Replicas, fldPath.Child("replicas"))...)
 	allErrs = append(allErrs, ValidateResourceMatch(step.Match, fldPath.Child("matchTargets"))...)
-	allErrs = append(allErrs, validateTrafficStrategy(step.Traffic, fldPath.Child("traffic"))...)
+	allErrs = append(allErrs, validateStepTrafficStrategy(step.Traffic, fldPath.Child("traffic"))...)
+	allErrs = append(allErrs, validateStepGuards(step.Guards, fldPath.Child("guards"))...)
 
 	return allErrs
 }
@@ -79,8 +95,775 @@ func ValidateCanaryStrategy(strategy *rolloutv1alpha1.CanaryStrategy, fldPath *f
 	allErrs = append(allErrs, appsvalidation.ValidatePositiveIntOrPercent(strategy.Replicas, fldPath.Child("replicas"))...)
 	allErrs = append(allErrs, ValidateResourceMatch(strategy.Match, fldPath.Child("matchTargets"))...)
 	allErrs = append(allErrs, validatePodTemplatePatch(strategy.PodTemplateMetadataPatch, fldPath.Child("patch"))...)
+	allErrs = append(allErrs, validateObjectMetadataPatch(strategy.ObjectMetadataPatch, fldPath.Child("objectMetadataPatch"))...)
 	allErrs = append(allErrs, validateTrafficStrategy(strategy.Traffic, fldPath.Child("traffic"))...)
+	allErrs = append(allErrs, validatePromotionWindows(strategy.PromotionWindows, fldPath.Child("promotionWindows"))...)
+	allErrs = append(allErrs, validateCanaryAnalysis(strategy.Analysis, fldPath.Child("analysis"))...)
+	allErrs = append(allErrs, validateCanaryRecycleOrder(strategy.RecycleOrder, fldPath.Child("recycleOrder"))...)
+	allErrs = append(allErrs, validateCanaryRetention(strategy.RetentionSeconds, strategy.Ordinals, fldPath.Child("retentionSeconds"))...)
+	allErrs = append(allErrs, validateReplicasFollowTrafficWeight(strategy.ReplicasFollowTrafficWeight, strategy.Traffic, fldPath.Child("replicasFollowTrafficWeight"))...)
+	allErrs = append(allErrs, validateCanaryMaxActiveDuration(strategy.MaxActiveDuration, fldPath.Child("maxActiveDuration"))...)
+	allErrs = append(allErrs, validateCanaryNotifications(strategy.Notifications, fldPath.Child("notifications"))...)
+	allErrs = append(allErrs, validateCanaryBake(strategy.Bake, fldPath.Child("bake"))...)
+	allErrs = append(allErrs, validateStableMinAvailable(strategy.StableMinAvailable, fldPath.Child("stableMinAvailable"))...)
+	allErrs = append(allErrs, validateCanaryStuckDeletion(strategy.StuckDeletion, fldPath.Child("stuckDeletion"))...)
+	allErrs = append(allErrs, validateCanaryDriftDetection(strategy.DriftDetection, fldPath.Child("driftDetection"))...)
+	allErrs = append(allErrs, validateCanaryAutoRollback(strategy.AutoRollback, fldPath.Child("autoRollback"))...)
+	allErrs = append(allErrs, validateCanaryImagePullCheck(strategy.ImagePullCheck, fldPath.Child("imagePullCheck"))...)
+	allErrs = append(allErrs, validateCanaryOrdinals(strategy.Ordinals, strategy.Bake, strategy.ReplicasFollowTrafficWeight, fldPath.Child("ordinals"))...)
+	allErrs = append(allErrs, validateCanaryPauseBefore(strategy.PauseBefore, fldPath.Child("pauseBefore"))...)
+	allErrs = append(allErrs, validateCanaryStrategyType(strategy.Strategy, strategy.Traffic, strategy.Ordinals, strategy.Bake, strategy.ReplicasFollowTrafficWeight, fldPath.Child("strategy"))...)
+	allErrs = append(allErrs, validateCanaryVariants(strategy.Variants, strategy.Traffic, strategy.Strategy, strategy.Ordinals, strategy.ReplicasFollowTrafficWeight, strategy.ScaleUpStep, fldPath.Child("variants"))...)
+	if strategy.Strategy == rolloutv1alpha1.CanaryStrategyBlueGreen && strategy.Replicas != blueGreenReplicas {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("replicas"), strategy.Replicas.String(), "must be 100% with blue/green"))
+	}
+	allErrs = append(allErrs, validateCanaryUpdateStrategy(strategy.UpdateStrategy, strategy.Ordinals, fldPath.Child("updateStrategy"))...)
+	allErrs = append(allErrs, validatePodPlacementPatch(strategy.PodPlacementPatch, strategy.Ordinals, fldPath.Child("podPlacementPatch"))...)
+	allErrs = append(allErrs, validateCanaryNamespace(strategy.CanaryNamespace, fldPath.Child("canaryNamespace"))...)
+	allErrs = append(allErrs, validateCanaryConfigOverrides(strategy.ConfigOverrides, strategy.Ordinals, fldPath.Child("configOverrides"))...)
+	allErrs = append(allErrs, validateCanaryInitContainerOverrides(strategy.InitContainerOverrides, strategy.Ordinals, fldPath.Child("initContainerOverrides"))...)
+	allErrs = append(allErrs, validateStepGuards(strategy.Guards, fldPath.Child("guards"))...)
+	allErrs = append(allErrs, validateCanaryScaleUpStep(strategy.ScaleUpStep, fldPath.Child("scaleUpStep"))...)
+	allErrs = append(allErrs, validateCanaryWarmUp(strategy.WarmUp, fldPath.Child("warmUp"))...)
+	allErrs = append(allErrs, validateCanaryReadinessStabilization(strategy.ReadinessStabilization, fldPath.Child("readinessStabilization"))...)
+	allErrs = append(allErrs, validateCanaryReadinessExpression(strategy.ReadinessExpression, fldPath.Child("readinessExpression"))...)
+	allErrs = append(allErrs, validateCanaryPreconditionRefs(strategy.PreconditionRefs, fldPath.Child("preconditionRefs"))...)
+	allErrs = append(allErrs, validateCanaryPreconditionRefs(strategy.Prerequisites, fldPath.Child("prerequisites"))...)
+	allErrs = append(allErrs, validateCanaryOwnerReferences(strategy.OwnerReferences, fldPath.Child("ownerReferences"))...)
+	allErrs = append(allErrs, validateCanaryPodDisruptionBudget(strategy.PodDisruptionBudget, strategy.Replicas, strategy.Ordinals, fldPath.Child("podDisruptionBudget"))...)
+	if strategy.ReadinessTimeoutSeconds != nil && *strategy.ReadinessTimeoutSeconds <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("readinessTimeoutSeconds"), *strategy.ReadinessTimeoutSeconds, "must be greater than 0"))
+	}
+
+	return allErrs
+}
+
+func validateReplicasFollowTrafficWeight(follow bool, traffic *rolloutv1alpha1.TrafficStrategy, fldPath *field.Path) field.ErrorList {
+	if follow && (traffic == nil || traffic.CanaryWeight() == nil) {
+		return field.ErrorList{field.Forbidden(fldPath, "replicas can only follow traffic weight if weight is set")}
+	}
+	return nil
+}
+
+func validateCanaryMaxActiveDuration(duration *rolloutv1alpha1.CanaryMaxActiveDuration, fldPath *field.Path) field.ErrorList {
+	if duration == nil {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+	if duration.Seconds <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("seconds"), duration.Seconds, "must be greater than 0"))
+	}
+	switch duration.Action {
+	case rolloutv1alpha1.CanaryMaxActivePromote, rolloutv1alpha1.CanaryMaxActiveRollback:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("action"), duration.Action,
+			[]string{string(rolloutv1alpha1.CanaryMaxActivePromote), string(rolloutv1alpha1.CanaryMaxActiveRollback)}))
+	}
+	if duration.WarningSeconds != nil && (*duration.WarningSeconds <= 0 || *duration.WarningSeconds >= duration.Seconds) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("warningSeconds"), *duration.WarningSeconds, "must be greater than 0 and less than seconds"))
+	}
+	return allErrs
+}
+
+func validateCanaryImagePullCheck(check *rolloutv1alpha1.CanaryImagePullCheck, fldPath *field.Path) field.ErrorList {
+	if check == nil || check.TimeoutSeconds == nil {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+	if *check.TimeoutSeconds <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeoutSeconds"), *check.TimeoutSeconds, "must be greater than 0"))
+	}
+	return allErrs
+}
+
+func validateCanaryStuckDeletion(stuck *rolloutv1alpha1.CanaryStuckDeletion, fldPath *field.Path) field.ErrorList {
+	if stuck == nil {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+	if stuck.TimeoutSeconds <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeoutSeconds"), stuck.TimeoutSeconds, "must be greater than 0"))
+	}
+	switch stuck.Action {
+	case rolloutv1alpha1.CanaryStuckDeletionForceDelete, rolloutv1alpha1.CanaryStuckDeletionFail:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("action"), stuck.Action,
+			[]string{string(rolloutv1alpha1.CanaryStuckDeletionForceDelete), string(rolloutv1alpha1.CanaryStuckDeletionFail)}))
+	}
+	return allErrs
+}
+
+func validateCanaryDriftDetection(drift *rolloutv1alpha1.CanaryDriftDetection, fldPath *field.Path) field.ErrorList {
+	if drift == nil {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+	if drift.Threshold != 0 && drift.Threshold < 2 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("threshold"), drift.Threshold, "must be greater than 1"))
+	}
+	switch drift.Action {
+	case "", rolloutv1alpha1.CanaryDriftWarn, rolloutv1alpha1.CanaryDriftFail:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("action"), drift.Action,
+			[]string{string(rolloutv1alpha1.CanaryDriftWarn), string(rolloutv1alpha1.CanaryDriftFail)}))
+	}
+	return allErrs
+}
+
+func validateCanaryAutoRollback(rollback *rolloutv1alpha1.CanaryAutoRollback, fldPath *field.Path) field.ErrorList {
+	if rollback == nil {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+	if rollback.MaxRollbacks < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxRollbacks"), rollback.MaxRollbacks, "must be greater than 0"))
+	}
+	if rollback.WindowSeconds != nil && *rollback.WindowSeconds < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("windowSeconds"), *rollback.WindowSeconds, "must be greater than 0"))
+	}
+	return allErrs
+}
+
+var supportedCanaryPausePhases = []string{
+	string(rolloutv1alpha1.CanaryPauseBeforeCreateCanary),
+	string(rolloutv1alpha1.CanaryPauseBeforeForkCanary),
+	string(rolloutv1alpha1.CanaryPauseBeforePromotion),
+}
+
+func validateCanaryPauseBefore(phases []rolloutv1alpha1.CanaryPausePhase, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	seen := sets.NewString()
+	for i, phase := range phases {
+		if !sets.NewString(supportedCanaryPausePhases...).Has(string(phase)) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Index(i), phase, supportedCanaryPausePhases))
+			continue
+		}
+		if seen.Has(string(phase)) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), phase))
+		}
+		seen.Insert(string(phase))
+	}
+	return allErrs
+}
+
+// blueGreenReplicas is the replicas of blue/green canary, the full size of stable.
+var blueGreenReplicas = intstr.FromString("100%")
+
+// validateCanaryStrategyType validates the release strategy of canary. The
+// traffic of blue/green is switched at once, so it must be routed with full
+// weight and can not be reduced in pause or ramped down in recycle, and canary
+// replicas must not be changed by ordinals, bake or traffic weight.
+func validateCanaryStrategyType(strategyType rolloutv1alpha1.CanaryStrategyType, traffic *rolloutv1alpha1.TrafficStrategy, ordinals []int32, bake *rolloutv1alpha1.CanaryBake, replicasFollowTrafficWeight bool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch strategyType {
+	case "", rolloutv1alpha1.CanaryStrategyCanary:
+		return nil
+	case rolloutv1alpha1.CanaryStrategyBlueGreen:
+	default:
+		return append(allErrs, field.NotSupported(fldPath, strategyType, []string{string(rolloutv1alpha1.CanaryStrategyCanary), string(rolloutv1alpha1.CanaryStrategyBlueGreen)}))
+	}
+
+	if traffic == nil {
+		allErrs = append(allErrs, field.Required(fldPath, "blue/green requires traffic"))
+	} else {
+		if weight := traffic.CanaryWeight(); weight == nil || *weight != 100 {
+			allErrs = append(allErrs, field.Invalid(fldPath, strategyType, "blue/green requires traffic weight 100"))
+		}
+		if traffic.PausePolicy != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath, "blue/green can not be set with traffic pause policy"))
+		}
+		if traffic.RevertRamp != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath, "blue/green can not be set with traffic revert ramp"))
+		}
+	}
+	if len(ordinals) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "blue/green can not be set with ordinals"))
+	}
+	if bake != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "blue/green can not be set with bake"))
+	}
+	if replicasFollowTrafficWeight {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "blue/green can not be set with replicasFollowTrafficWeight"))
+	}
+	return allErrs
+}
+
+// maxCanaryVariantNameLength limits the name of variant suffixed to the names
+// of canary workloads and backends.
+const maxCanaryVariantNameLength = 20
+
+// validateCanaryVariants validates the variants of canary, they must be the
+// variants of traffic allocation. The features which scale a single canary
+// workload or change a single canary weight are not supported with them.
+func validateCanaryVariants(variants []rolloutv1alpha1.CanaryVariant, traffic *rolloutv1alpha1.TrafficStrategy, strategyType rolloutv1alpha1.CanaryStrategyType,
+	ordinals []int32, replicasFollowTrafficWeight bool, scaleUpStep *intstr.IntOrString, fldPath *field.Path,
+) field.ErrorList {
+	if len(variants) == 0 {
+		// without variants, a single canary would serve the sum of all variants
+		if traffic != nil && traffic.Allocation != nil && len(traffic.Allocation.Variants) > 1 {
+			return field.ErrorList{field.Required(fldPath, "variants are required if traffic allocation has more than one variant")}
+		}
+		return nil
+	}
+	allErrs := field.ErrorList{}
+
+	names := sets.NewString()
+	for i, variant := range variants {
+		idxPath := fldPath.Index(i)
+		for _, msg := range utilvalidation.IsDNS1123Label(variant.Name) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("name"), variant.Name, msg))
+		}
+		if len(variant.Name) > maxCanaryVariantNameLength {
+			allErrs = append(allErrs, field.TooLong(idxPath.Child("name"), variant.Name, maxCanaryVariantNameLength))
+		}
+		if names.Has(variant.Name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), variant.Name))
+		}
+		names.Insert(variant.Name)
+		allErrs = append(allErrs, appsvalidation.ValidatePositiveIntOrPercent(variant.Replicas, idxPath.Child("replicas"))...)
+		allErrs = append(allErrs, validatePodTemplatePatch(variant.PodTemplateMetadataPatch, idxPath.Child("podTemplateMetadataPatch"))...)
+	}
+
+	if traffic == nil || traffic.Allocation == nil {
+		allErrs = append(allErrs, field.Required(fldPath, "variants require traffic allocation"))
+	} else {
+		allocated := sets.NewString()
+		for _, variant := range traffic.Allocation.Variants {
+			allocated.Insert(variant.Name)
+		}
+		if !allocated.Equal(names) {
+			allErrs = append(allErrs, field.Invalid(fldPath, names.List(),
+				fmt.Sprintf("must be the variants of traffic allocation %v", allocated.List())))
+		}
+		if traffic.SessionDrain != nil || traffic.SessionAffinity != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath, "variants can not be set with traffic session drain or affinity"))
+		}
+		if traffic.PausePolicy != nil || traffic.RevertRamp != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath, "variants can not be set with traffic pause policy or revert ramp"))
+		}
+	}
+	if strategyType == rolloutv1alpha1.CanaryStrategyBlueGreen {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "variants can not be set with blue/green"))
+	}
+	if len(ordinals) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "variants can not be set with ordinals"))
+	}
+	if replicasFollowTrafficWeight {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "variants can not be set with replicasFollowTrafficWeight"))
+	}
+	if scaleUpStep != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "variants can not be set with scaleUpStep"))
+	}
+	return allErrs
+}
+
+// validateCanaryOrdinals validates the ordinals of pods updated in place, the
+// features scaling canary replicas are not supported with them.
+func validateCanaryOrdinals(ordinals []int32, bake *rolloutv1alpha1.CanaryBake, replicasFollowTrafficWeight bool, fldPath *field.Path) field.ErrorList {
+	if len(ordinals) == 0 {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+	seen := sets.NewInt32()
+	for i, ordinal := range ordinals {
+		if ordinal < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), ordinal, "must be greater than or equal to 0"))
+		}
+		if seen.Has(ordinal) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), ordinal))
+		}
+		seen.Insert(ordinal)
+	}
+	if bake != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "ordinals can not be set with bake"))
+	}
+	if replicasFollowTrafficWeight {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "ordinals can not be set with replicasFollowTrafficWeight"))
+	}
+	return allErrs
+}
+
+// validateCanaryUpdateStrategy validates the update strategy of canary
+// workloads, it can not be set with ordinals which create no canary workload.
+func validateCanaryUpdateStrategy(strategy *rolloutv1alpha1.CanaryUpdateStrategy, ordinals []int32, fldPath *field.Path) field.ErrorList {
+	if strategy == nil {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+	if len(ordinals) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "update strategy can not be set with ordinals"))
+	}
+	switch strategy.Type {
+	case "", rolloutv1alpha1.CanaryRollingUpdate:
+	case rolloutv1alpha1.CanaryRecreate:
+		if strategy.RollingUpdate != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("rollingUpdate"), "rolling update can not be set with Recreate"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("type"), strategy.Type,
+			[]string{string(rolloutv1alpha1.CanaryRollingUpdate), string(rolloutv1alpha1.CanaryRecreate)}))
+	}
+
+	rolling := strategy.RollingUpdate
+	if rolling == nil {
+		return allErrs
+	}
+	isZero := func(value *intstr.IntOrString) bool {
+		if value == nil {
+			return false
+		}
+		scaled, err := intstr.GetScaledValueFromIntOrPercent(value, 100, true)
+		return err == nil && scaled == 0
+	}
+	validateIntOrPercent := func(value *intstr.IntOrString, path *field.Path) {
+		if value == nil {
+			return
+		}
+		allErrs = append(allErrs, appsvalidation.ValidatePositiveIntOrPercent(*value, path)...)
+		allErrs = append(allErrs, appsvalidation.IsNotMoreThan100Percent(*value, path)...)
+	}
+	validateIntOrPercent(rolling.MaxUnavailable, fldPath.Child("rollingUpdate", "maxUnavailable"))
+	validateIntOrPercent(rolling.MaxSurge, fldPath.Child("rollingUpdate", "maxSurge"))
+	if isZero(rolling.MaxUnavailable) && (rolling.MaxSurge == nil || isZero(rolling.MaxSurge)) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("rollingUpdate", "maxUnavailable"), rolling.MaxUnavailable.String(), "may not be 0 when maxSurge is 0"))
+	}
+	return allErrs
+}
+
+func validateCanaryBake(bake *rolloutv1alpha1.CanaryBake, fldPath *field.Path) field.ErrorList {
+	if bake == nil {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+	if bake.Windows <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("windows"), bake.Windows, "must be greater than 0"))
+	}
+	if bake.ActiveSeconds <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("activeSeconds"), bake.ActiveSeconds, "must be greater than 0"))
+	}
+	if bake.IdleSeconds <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("idleSeconds"), bake.IdleSeconds, "must be greater than 0"))
+	}
+	if bake.IdleReplicas != nil && *bake.IdleReplicas < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("idleReplicas"), *bake.IdleReplicas, "must be greater than or equal to 0"))
+	}
+	return allErrs
+}
+
+// validateCanaryOwnerReferences checks the owner references of canary workloads,
+// which can not be the controller, since canary workloads are managed by rollout.
+func validateCanaryOwnerReferences(refs []metav1.OwnerReference, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	uids := sets.NewString()
+	for i, ref := range refs {
+		idxPath := fldPath.Index(i)
+		if len(ref.APIVersion) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("apiVersion"), "apiVersion is required"))
+		}
+		if len(ref.Kind) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("kind"), "kind is required"))
+		}
+		if len(ref.Name) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "name is required"))
+		}
+		if len(ref.UID) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("uid"), "uid is required"))
+		} else if uids.Has(string(ref.UID)) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("uid"), ref.UID))
+		} else {
+			uids.Insert(string(ref.UID))
+		}
+		if ref.Controller != nil && *ref.Controller {
+			allErrs = append(allErrs, field.Forbidden(idxPath.Child("controller"), "canary workloads can not be controlled by other owners"))
+		}
+	}
+	return allErrs
+}
+
+func validateCanaryPreconditionRefs(refs []rolloutv1alpha1.CanaryPreconditionRef, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, ref := range refs {
+		idxPath := fldPath.Index(i)
+		if len(ref.Kind) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("kind"), "kind is required"))
+		}
+		if len(ref.Name) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "name is required"))
+		}
+		if len(ref.FieldPath) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("fieldPath"), "field path is required"))
+		} else if err := jsonpath.New(ref.Kind).Parse(ref.FieldPath); err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("fieldPath"), ref.FieldPath, fmt.Sprintf("must be a valid JSONPath: %v", err)))
+		}
+		if ref.TimeoutSeconds < 0 {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("timeoutSeconds"), ref.TimeoutSeconds, "must be greater than 0"))
+		}
+	}
+	return allErrs
+}
 
+func validateCanaryReadinessStabilization(stabilization *rolloutv1alpha1.CanaryReadinessStabilization, fldPath *field.Path) field.ErrorList {
+	if stabilization == nil {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+	if stabilization.ReadyPercent < 0 || stabilization.ReadyPercent > 100 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("readyPercent"), stabilization.ReadyPercent, "must be between 1 and 100"))
+	}
+	if stabilization.WindowSeconds <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("windowSeconds"), stabilization.WindowSeconds, "must be greater than 0"))
+	}
+	return allErrs
+}
+
+// CanaryReadinessExpressionEnv returns the CEL environment of canary readiness
+// expressions, in which `status` and `object` are declared as dynamic values.
+func CanaryReadinessExpressionEnv() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("status", cel.DynType),
+		cel.Variable("object", cel.DynType),
+	)
+}
+
+func validateCanaryReadinessExpression(expression string, fldPath *field.Path) field.ErrorList {
+	if len(expression) == 0 {
+		return nil
+	}
+	env, err := CanaryReadinessExpressionEnv()
+	if err != nil {
+		return field.ErrorList{field.InternalError(fldPath, err)}
+	}
+	if _, issues := env.Compile(expression); issues != nil && issues.Err() != nil {
+		return field.ErrorList{field.Invalid(fldPath, expression, fmt.Sprintf("failed to compile: %v", issues.Err()))}
+	}
+	return nil
+}
+
+func validateCanaryWarmUp(warmUp *rolloutv1alpha1.CanaryWarmUp, fldPath *field.Path) field.ErrorList {
+	if warmUp == nil {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+	if warmUp.DurationSeconds == 0 && warmUp.Requests == nil && warmUp.Webhook == nil {
+		allErrs = append(allErrs, field.Required(fldPath, "at least one of durationSeconds, requests and webhook is required"))
+	}
+	if warmUp.DurationSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("durationSeconds"), warmUp.DurationSeconds, "must be greater than or equal to 0"))
+	}
+	if warmUp.TimeoutSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeoutSeconds"), warmUp.TimeoutSeconds, "must be greater than or equal to 0"))
+	} else if warmUp.TimeoutSeconds > 0 && warmUp.DurationSeconds >= warmUp.TimeoutSeconds {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("durationSeconds"), warmUp.DurationSeconds, "must be less than timeoutSeconds"))
+	}
+	if requests := warmUp.Requests; requests != nil {
+		requestsPath := fldPath.Child("requests")
+		for _, msg := range utilvalidation.IsValidPortNum(int(requests.Port)) {
+			allErrs = append(allErrs, field.Invalid(requestsPath.Child("port"), requests.Port, msg))
+		}
+		if len(requests.Path) > 0 && !strings.HasPrefix(requests.Path, "/") {
+			allErrs = append(allErrs, field.Invalid(requestsPath.Child("path"), requests.Path, "must start with '/'"))
+		}
+		if requests.Count < 0 {
+			allErrs = append(allErrs, field.Invalid(requestsPath.Child("count"), requests.Count, "must be greater than or equal to 0"))
+		}
+		if requests.TimeoutSeconds < 0 {
+			allErrs = append(allErrs, field.Invalid(requestsPath.Child("timeoutSeconds"), requests.TimeoutSeconds, "must be greater than or equal to 0"))
+		}
+	}
+	if warmUp.Webhook != nil {
+		allErrs = append(allErrs, webhookutil.ValidateWebhookURL(fldPath.Child("webhook", "url"), warmUp.Webhook.URL, false)...)
+	}
+	return allErrs
+}
+
+func validateCanaryScaleUpStep(step *intstr.IntOrString, fldPath *field.Path) field.ErrorList {
+	if step == nil {
+		return nil
+	}
+	allErrs := appsvalidation.ValidatePositiveIntOrPercent(*step, fldPath)
+	allErrs = append(allErrs, appsvalidation.IsNotMoreThan100Percent(*step, fldPath)...)
+	if scaled, err := intstr.GetScaledValueFromIntOrPercent(step, 100, true); err == nil && scaled == 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath, step.String(), "must be greater than 0"))
+	}
+	return allErrs
+}
+
+func validateStableMinAvailable(minAvailable *intstr.IntOrString, fldPath *field.Path) field.ErrorList {
+	if minAvailable == nil {
+		return nil
+	}
+	allErrs := appsvalidation.ValidatePositiveIntOrPercent(*minAvailable, fldPath)
+	allErrs = append(allErrs, appsvalidation.IsNotMoreThan100Percent(*minAvailable, fldPath)...)
+	return allErrs
+}
+
+func validateCanaryNamespace(namespace string, fldPath *field.Path) field.ErrorList {
+	if len(namespace) == 0 {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+	for _, msg := range apimachineryvalidation.ValidateNamespaceName(namespace, false) {
+		allErrs = append(allErrs, field.Invalid(fldPath, namespace, msg))
+	}
+	return allErrs
+}
+
+// validateCanaryConfigOverrides validates the configs substituted in canary pod
+// template, they can not be used with ordinals which update stable pods in place.
+func validateCanaryRetention(seconds *int32, ordinals []int32, fldPath *field.Path) field.ErrorList {
+	if seconds == nil {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+	if *seconds <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath, *seconds, "must be greater than 0"))
+	}
+	if len(ordinals) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "retention can not be set with ordinals"))
+	}
+	return allErrs
+}
+
+func validateCanaryConfigOverrides(overrides []rolloutv1alpha1.CanaryConfigOverride, ordinals []int32, fldPath *field.Path) field.ErrorList {
+	if len(overrides) == 0 {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+	if len(ordinals) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "config overrides can not be set with ordinals"))
+	}
+	seen := sets.NewString()
+	for i, override := range overrides {
+		idxPath := fldPath.Index(i)
+		switch override.Kind {
+		case rolloutv1alpha1.CanaryConfigMap, rolloutv1alpha1.CanaryConfigSecret:
+		default:
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("kind"), override.Kind, []string{string(rolloutv1alpha1.CanaryConfigMap), string(rolloutv1alpha1.CanaryConfigSecret)}))
+		}
+		for _, msg := range apimachineryvalidation.NameIsDNSSubdomain(override.Name, false) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("name"), override.Name, msg))
+		}
+		for _, msg := range apimachineryvalidation.NameIsDNSSubdomain(override.CanaryName, false) {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("canaryName"), override.CanaryName, msg))
+		}
+		if override.Name == override.CanaryName {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("canaryName"), override.CanaryName, "must be different from name"))
+		}
+		key := string(override.Kind) + "/" + override.Name
+		if seen.Has(key) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), override.Name))
+		}
+		seen.Insert(key)
+	}
+	return allErrs
+}
+
+// validateCanaryPodDisruptionBudget validates the disruption budget of canary
+// pods. If canary replicas is a number, the budget must allow at least one of
+// them to be evicted, the percentage of replicas is checked when canary is
+// created.
+func validateCanaryPodDisruptionBudget(pdb *rolloutv1alpha1.CanaryPodDisruptionBudget, replicas intstr.IntOrString, ordinals []int32, fldPath *field.Path) field.ErrorList {
+	if pdb == nil {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+	if len(ordinals) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "pod disruption budget can not be set with ordinals"))
+	}
+	if pdb.MinAvailable != nil && pdb.MaxUnavailable != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "minAvailable and maxUnavailable cannot be specified together"))
+	} else if pdb.MinAvailable == nil && pdb.MaxUnavailable == nil {
+		allErrs = append(allErrs, field.Required(fldPath, "one of minAvailable and maxUnavailable is required"))
+	}
+	if pdb.MinAvailable != nil {
+		allErrs = append(allErrs, appsvalidation.ValidatePositiveIntOrPercent(*pdb.MinAvailable, fldPath.Child("minAvailable"))...)
+		allErrs = append(allErrs, appsvalidation.IsNotMoreThan100Percent(*pdb.MinAvailable, fldPath.Child("minAvailable"))...)
+	}
+	if pdb.MaxUnavailable != nil {
+		allErrs = append(allErrs, appsvalidation.ValidatePositiveIntOrPercent(*pdb.MaxUnavailable, fldPath.Child("maxUnavailable"))...)
+		allErrs = append(allErrs, appsvalidation.IsNotMoreThan100Percent(*pdb.MaxUnavailable, fldPath.Child("maxUnavailable"))...)
+	}
+	if len(allErrs) > 0 || replicas.Type != intstr.Int {
+		return allErrs
+	}
+	budgetPath, budget := fldPath.Child("minAvailable"), pdb.MinAvailable
+	if pdb.MaxUnavailable != nil {
+		budgetPath, budget = fldPath.Child("maxUnavailable"), pdb.MaxUnavailable
+	}
+	disruptions, err := pdb.AllowedDisruptions(replicas.IntVal)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(budgetPath, budget.String(), err.Error()))
+	} else if disruptions == 0 {
+		allErrs = append(allErrs, field.Invalid(budgetPath, budget.String(), fmt.Sprintf("must allow at least one of %d canary replicas to be evicted", replicas.IntVal)))
+	}
+	return allErrs
+}
+
+// validateCanaryInitContainerOverrides validates the init containers merged
+// into canary pod template, they are merged by name so that names must be
+// unique.
+func validateCanaryInitContainerOverrides(containers []corev1.Container, ordinals []int32, fldPath *field.Path) field.ErrorList {
+	if len(containers) == 0 {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+	if len(ordinals) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "init container overrides can not be set with ordinals"))
+	}
+	names := sets.NewString()
+	for i, container := range containers {
+		idxPath := fldPath.Index(i)
+		if len(container.Name) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "name is required"))
+		} else {
+			for _, msg := range utilvalidation.IsDNS1123Label(container.Name) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("name"), container.Name, msg))
+			}
+			if names.Has(container.Name) {
+				allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), container.Name))
+			}
+			names.Insert(container.Name)
+		}
+		if len(container.Image) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("image"), "image is required"))
+		}
+	}
+	return allErrs
+}
+
+// validatePodPlacementPatch validates the pod placement patch of canary, the
+// required node affinity can only have one term so that it is merged into
+// each existing term.
+func validatePodPlacementPatch(patch *rolloutv1alpha1.PodPlacementPatch, ordinals []int32, fldPath *field.Path) field.ErrorList {
+	if patch == nil {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+	if len(ordinals) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "pod placement patch can not be set with ordinals"))
+	}
+
+	if affinity := patch.Affinity; affinity != nil {
+		affinityPath := fldPath.Child("affinity")
+		if na := affinity.NodeAffinity; na != nil && na.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+			terms := na.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+			if len(terms) != 1 {
+				allErrs = append(allErrs, field.Invalid(affinityPath.Child("nodeAffinity", "requiredDuringSchedulingIgnoredDuringExecution", "nodeSelectorTerms"), len(terms), "must contain exactly one term"))
+			}
+		}
+		validateTerms := func(required []corev1.PodAffinityTerm, preferred []corev1.WeightedPodAffinityTerm, termsPath *field.Path) {
+			for i := range required {
+				allErrs = append(allErrs, validatePodAffinityTerm(&required[i], termsPath.Child("requiredDuringSchedulingIgnoredDuringExecution").Index(i))...)
+			}
+			for i := range preferred {
+				allErrs = append(allErrs, validatePodAffinityTerm(&preferred[i].PodAffinityTerm, termsPath.Child("preferredDuringSchedulingIgnoredDuringExecution").Index(i).Child("podAffinityTerm"))...)
+			}
+		}
+		if pa := affinity.PodAffinity; pa != nil {
+			validateTerms(pa.RequiredDuringSchedulingIgnoredDuringExecution, pa.PreferredDuringSchedulingIgnoredDuringExecution, affinityPath.Child("podAffinity"))
+		}
+		if paa := affinity.PodAntiAffinity; paa != nil {
+			validateTerms(paa.RequiredDuringSchedulingIgnoredDuringExecution, paa.PreferredDuringSchedulingIgnoredDuringExecution, affinityPath.Child("podAntiAffinity"))
+		}
+	}
+
+	topologyKeys := sets.NewString()
+	for i, constraint := range patch.TopologySpreadConstraints {
+		idxPath := fldPath.Child("topologySpreadConstraints").Index(i)
+		if constraint.MaxSkew < 1 {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("maxSkew"), constraint.MaxSkew, "must be greater than 0"))
+		}
+		if len(constraint.TopologyKey) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("topologyKey"), "can not be empty"))
+		} else if topologyKeys.Has(constraint.TopologyKey) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("topologyKey"), constraint.TopologyKey))
+		} else {
+			topologyKeys.Insert(constraint.TopologyKey)
+		}
+		switch constraint.WhenUnsatisfiable {
+		case corev1.DoNotSchedule, corev1.ScheduleAnyway:
+		default:
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("whenUnsatisfiable"), constraint.WhenUnsatisfiable, []string{string(corev1.DoNotSchedule), string(corev1.ScheduleAnyway)}))
+		}
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(constraint.LabelSelector, idxPath.Child("labelSelector"))...)
+	}
+	return allErrs
+}
+
+func validatePodAffinityTerm(term *corev1.PodAffinityTerm, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(term.TopologyKey) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("topologyKey"), "can not be empty"))
+	}
+	allErrs = append(allErrs, metav1validation.ValidateLabelSelector(term.LabelSelector, fldPath.Child("labelSelector"))...)
+	return allErrs
+}
+
+func validateCanaryNotifications(notifications []rolloutv1alpha1.CanaryNotification, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := sets.NewString()
+	for i, notification := range notifications {
+		idxPath := fldPath.Index(i)
+		if len(notification.Name) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "name is required"))
+		} else if names.Has(notification.Name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), notification.Name))
+		} else {
+			names.Insert(notification.Name)
+		}
+		allErrs = append(allErrs, webhookutil.ValidateWebhookURL(idxPath.Child("clientConfig", "url"), notification.ClientConfig.URL, false)...)
+	}
+	return allErrs
+}
+
+// validateStepGuards validates the guards of step, each guard must be either a
+// metric query with threshold or a webhook.
+func validateStepGuards(guards []rolloutv1alpha1.StepGuard, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := sets.NewString()
+	for i, guard := range guards {
+		idxPath := fldPath.Index(i)
+		if len(guard.Name) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "name is required"))
+		} else if names.Has(guard.Name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), guard.Name))
+		} else {
+			names.Insert(guard.Name)
+		}
+
+		if (guard.Metric == nil) == (guard.Webhook == nil) {
+			allErrs = append(allErrs, field.Invalid(idxPath, guard.Name, "exactly one of metric and webhook must be set"))
+			continue
+		}
+		if guard.Webhook != nil {
+			allErrs = append(allErrs, webhookutil.ValidateWebhookURL(idxPath.Child("webhook", "url"), guard.Webhook.URL, false)...)
+			continue
+		}
+
+		metric := guard.Metric
+		metricPath := idxPath.Child("metric")
+		if len(metric.Provider.Name) == 0 {
+			allErrs = append(allErrs, field.Required(metricPath.Child("provider", "name"), "provider name is required"))
+		}
+		if len(metric.Query) == 0 {
+			allErrs = append(allErrs, field.Required(metricPath.Child("query"), "query is required"))
+		}
+		if metric.Min == nil && metric.Max == nil {
+			allErrs = append(allErrs, field.Required(metricPath, "at least one of min and max is required"))
+		}
+		if metric.Min != nil {
+			if _, err := strconv.ParseFloat(*metric.Min, 64); err != nil {
+				allErrs = append(allErrs, field.Invalid(metricPath.Child("min"), *metric.Min, "must be a number"))
+			}
+		}
+		if metric.Max != nil {
+			if _, err := strconv.ParseFloat(*metric.Max, 64); err != nil {
+				allErrs = append(allErrs, field.Invalid(metricPath.Child("max"), *metric.Max, "must be a number"))
+			}
+		}
+	}
 	return allErrs
 }
 
@@ -102,6 +885,42 @@ func validatePodTemplatePatch(patch *rolloutv1alpha1.MetadataPatch, fldPath *fie
 	return allErrs
 }
 
+// reservedCanaryLabels and reservedCanaryAnnotations are maintained by rollout
+// controller and can not be overridden by ObjectMetadataPatch.
+var (
+	reservedCanaryLabels = sets.NewString(
+		rolloutapi.LabelControlledBy,
+		rolloutapi.LabelWorkload,
+		rolloutapi.LabelCanary,
+		rolloutapi.LabelPodRevision,
+	)
+	reservedCanaryAnnotations = sets.NewString(
+		rolloutapi.AnnoManualCommandKey,
+		rolloutapi.AnnoRolloutTrigger,
+		rolloutapi.AnnoRolloutProgressingInfo,
+	)
+)
+
+func validateObjectMetadataPatch(patch *rolloutv1alpha1.MetadataPatch, fldPath *field.Path) field.ErrorList {
+	if patch == nil {
+		return nil
+	}
+
+	allErrs := validatePodTemplatePatch(patch, fldPath)
+
+	for key := range patch.Labels {
+		if reservedCanaryLabels.Has(key) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("labels").Key(key), "builtin rollout label can not be overridden"))
+		}
+	}
+	for key := range patch.Annotations {
+		if reservedCanaryAnnotations.Has(key) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("annotations").Key(key), "builtin rollout annotation can not be overridden"))
+		}
+	}
+	return allErrs
+}
+
 func ValidateWebhooks(webhooks []rolloutv1alpha1.RolloutWebhook, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -130,7 +949,44 @@ func ValidateRolloutWebhook(webhook *rolloutv1alpha1.RolloutWebhook, fldPath *fi
 	}
 
 	allErrs = append(allErrs, webhookutil.ValidateWebhookURL(fldPath.Child("url"), webhook.ClientConfig.URL, false)...)
+	allErrs = append(allErrs, validateWebhookTargetMode(webhook, fldPath)...)
+
+	return allErrs
+}
+
+// stepHookTypes are the hook types called in a step with targets.
+var stepHookTypes = sets.NewString(
+	string(rolloutv1alpha1.PreCanaryStepHook),
+	string(rolloutv1alpha1.PostCanaryStepHook),
+	string(rolloutv1alpha1.PreBatchStepHook),
+	string(rolloutv1alpha1.PostBatchStepHook),
+)
+
+func validateWebhookTargetMode(webhook *rolloutv1alpha1.RolloutWebhook, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch webhook.TargetMode {
+	case "", rolloutv1alpha1.WebhookPerStep:
+		if len(webhook.TargetAggregation) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("targetAggregation"), "targetAggregation is only supported in Target mode"))
+		}
+		return allErrs
+	case rolloutv1alpha1.WebhookPerTarget:
+	default:
+		return append(allErrs, field.NotSupported(fldPath.Child("targetMode"), webhook.TargetMode,
+			[]string{string(rolloutv1alpha1.WebhookPerStep), string(rolloutv1alpha1.WebhookPerTarget)}))
+	}
 
+	for i, hookType := range webhook.HookTypes {
+		if !stepHookTypes.Has(string(hookType)) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("hookTypes").Index(i), hookType, "Target mode is only supported by step hooks"))
+		}
+	}
+	switch webhook.TargetAggregation {
+	case "", rolloutv1alpha1.WebhookAllPass, rolloutv1alpha1.WebhookPerTargetGating:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("targetAggregation"), webhook.TargetAggregation,
+			[]string{string(rolloutv1alpha1.WebhookAllPass), string(rolloutv1alpha1.WebhookPerTargetGating)}))
+	}
 	return allErrs
 }
 
@@ -143,5 +999,387 @@ func validateTrafficStrategy(traffic *rolloutv1alpha1.TrafficStrategy, fldPath *
 	if traffic.Weight != nil && (traffic.HTTPRule != nil && len(traffic.HTTPRule.Matches) > 0) {
 		allErrs = append(allErrs, field.Forbidden(fldPath, "weight and http rule matches cannot be specified together"))
 	}
+	if traffic.SessionDrain != nil && traffic.SessionDrain.Seconds <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("sessionDrain", "seconds"), traffic.SessionDrain.Seconds, "must be greater than 0"))
+	}
+	allErrs = append(allErrs, validateGRPCRouteRule(traffic, fldPath.Child("grpc"))...)
+	allErrs = append(allErrs, validateTrafficVerifyProbe(traffic.VerifyProbe, fldPath.Child("verifyProbe"))...)
+	allErrs = append(allErrs, validateTrafficSplitProbe(traffic, fldPath.Child("splitProbe"))...)
+	allErrs = append(allErrs, validateTrafficPropagationChecks(traffic.PropagationChecks, fldPath.Child("propagationChecks"))...)
+	allErrs = append(allErrs, validateTrafficSessionAffinity(traffic.SessionAffinity, fldPath.Child("sessionAffinity"))...)
+	allErrs = append(allErrs, validateTrafficAllocation(traffic, fldPath.Child("allocation"))...)
+	allErrs = append(allErrs, validateTrafficPorts(traffic, fldPath.Child("ports"))...)
+	if traffic.PausePolicy != nil {
+		policyPath := fldPath.Child("pausePolicy")
+		if weight := traffic.CanaryWeight(); weight == nil {
+			allErrs = append(allErrs, field.Forbidden(policyPath, "pause policy requires weight or allocation"))
+		} else if traffic.PausePolicy.ObservationWeight >= *weight {
+			allErrs = append(allErrs, field.Invalid(policyPath.Child("observationWeight"), traffic.PausePolicy.ObservationWeight, "must be less than the canary weight"))
+		}
+	}
+	if traffic.RevertRamp != nil {
+		rampPath := fldPath.Child("revertRamp")
+		if traffic.CanaryWeight() == nil {
+			allErrs = append(allErrs, field.Forbidden(rampPath, "revert ramp requires weight or allocation"))
+		}
+		if traffic.SessionDrain != nil {
+			allErrs = append(allErrs, field.Forbidden(rampPath, "revert ramp and session drain cannot be specified together"))
+		}
+		if traffic.RevertRamp.Steps < 2 {
+			allErrs = append(allErrs, field.Invalid(rampPath.Child("steps"), traffic.RevertRamp.Steps, "must be greater than 1"))
+		}
+		if traffic.RevertRamp.IntervalSeconds < 0 {
+			allErrs = append(allErrs, field.Invalid(rampPath.Child("intervalSeconds"), traffic.RevertRamp.IntervalSeconds, "must be greater than 0"))
+		}
+	}
+	return allErrs
+}
+
+func validateGRPCRouteRule(traffic *rolloutv1alpha1.TrafficStrategy, fldPath *field.Path) field.ErrorList {
+	rule := traffic.GRPCRule
+	if rule == nil {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+
+	if len(rule.Matches) == 0 {
+		return append(allErrs, field.Required(fldPath.Child("matches"), "at least one match is required"))
+	}
+	if traffic.CanaryWeight() != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "weight and grpc rule matches cannot be specified together"))
+	}
+	if traffic.SessionAffinity != nil || traffic.SessionDrain != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "session affinity is not supported by grpc routes"))
+	}
+	for i, match := range rule.Matches {
+		matchPath := fldPath.Child("matches").Index(i)
+		if match.Method == nil && len(match.Headers) == 0 {
+			allErrs = append(allErrs, field.Required(matchPath, "one of method or headers must be specified"))
+			continue
+		}
+		if match.Method != nil && match.Method.Service == nil && match.Method.Method == nil {
+			allErrs = append(allErrs, field.Required(matchPath.Child("method"), "one or both of service or method must be specified"))
+		}
+	}
+	return allErrs
+}
+
+func validateTrafficPorts(traffic *rolloutv1alpha1.TrafficStrategy, fldPath *field.Path) field.ErrorList {
+	if len(traffic.Ports) == 0 {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+
+	if traffic.Allocation != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "ports and allocation cannot be specified together"))
+	}
+	names := sets.NewString()
+	for i, name := range traffic.Ports {
+		if len(name) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i), "port name is required"))
+			continue
+		}
+		if names.Has(name) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), name))
+		}
+		names.Insert(name)
+	}
+	return allErrs
+}
+
+func validateTrafficAllocation(traffic *rolloutv1alpha1.TrafficStrategy, fldPath *field.Path) field.ErrorList {
+	allocation := traffic.Allocation
+	if allocation == nil {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+
+	if traffic.Weight != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "allocation and weight cannot be specified together"))
+	}
+	if traffic.HTTPRule != nil && len(traffic.HTTPRule.Matches) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "allocation and http rule matches cannot be specified together"))
+	}
+	if len(allocation.Variants) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("variants"), "must have at least one variant"))
+	}
+	if allocation.StablePercent < 0 || allocation.StablePercent > 100 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("stablePercent"), allocation.StablePercent, "must be between 0 and 100"))
+	}
+
+	names := sets.NewString()
+	for i, variant := range allocation.Variants {
+		idxPath := fldPath.Child("variants").Index(i)
+		if len(variant.Name) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "name is required"))
+		} else if names.Has(variant.Name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), variant.Name))
+		} else {
+			names.Insert(variant.Name)
+		}
+		if variant.Percent < 0 || variant.Percent > 100 {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("percent"), variant.Percent, "must be between 0 and 100"))
+		}
+	}
+	if len(allErrs) > 0 {
+		return allErrs
+	}
+
+	if sum := allocation.CanaryPercent() + allocation.StablePercent; sum != 100 {
+		allErrs = append(allErrs, field.Invalid(fldPath, sum,
+			fmt.Sprintf("the sum of variants (%d%%) and stable (%d%%) must be 100%%", allocation.CanaryPercent(), allocation.StablePercent)))
+	}
+	return allErrs
+}
+
+func validateTrafficVerifyProbe(probe *rolloutv1alpha1.TrafficVerifyProbe, fldPath *field.Path) field.ErrorList {
+	if probe == nil {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+
+	if u, err := url.Parse(probe.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("url"), probe.URL, "must be an absolute http or https url"))
+	}
+	if probe.ExpectedStatus != nil && (*probe.ExpectedStatus < 100 || *probe.ExpectedStatus > 599) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("expectedStatus"), *probe.ExpectedStatus, "must be a valid http status code"))
+	}
+	if probe.TimeoutSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeoutSeconds"), probe.TimeoutSeconds, "must be greater than 0"))
+	}
+	if probe.BudgetSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("budgetSeconds"), probe.BudgetSeconds, "must be greater than 0"))
+	}
+	if probe.SuccessThreshold < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("successThreshold"), probe.SuccessThreshold, "must be greater than 0"))
+	}
+	return allErrs
+}
+
+func validateTrafficSplitProbe(traffic *rolloutv1alpha1.TrafficStrategy, fldPath *field.Path) field.ErrorList {
+	probe := traffic.SplitProbe
+	if probe == nil {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+
+	if traffic.CanaryWeight() == nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "split probe requires weight or allocation"))
+	}
+	if u, err := url.Parse(probe.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("url"), probe.URL, "must be an absolute http or https url"))
+	}
+	if len(probe.CanaryHeader) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("canaryHeader"), "canary header is required"))
+	}
+	if probe.Requests < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("requests"), probe.Requests, "must be greater than 0"))
+	}
+	if probe.TolerancePercent != nil && (*probe.TolerancePercent < 0 || *probe.TolerancePercent > 100) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("tolerancePercent"), *probe.TolerancePercent, "must be between 0 and 100"))
+	}
+	if probe.TimeoutSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeoutSeconds"), probe.TimeoutSeconds, "must be greater than 0"))
+	}
+	return allErrs
+}
+
+func validateTrafficPropagationChecks(checks []rolloutv1alpha1.TrafficPropagationCheck, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, check := range checks {
+		idxPath := fldPath.Index(i)
+		if len(check.Kind) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("kind"), "kind is required"))
+		}
+		if len(check.FieldPath) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("fieldPath"), "field path is required"))
+		} else if err := jsonpath.New(check.Kind).Parse(check.FieldPath); err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("fieldPath"), check.FieldPath, fmt.Sprintf("must be a valid JSONPath: %v", err)))
+		}
+		if check.TimeoutSeconds < 0 {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("timeoutSeconds"), check.TimeoutSeconds, "must be greater than 0"))
+		}
+	}
+	return allErrs
+}
+
+func validateTrafficSessionAffinity(affinity *rolloutv1alpha1.TrafficSessionAffinity, fldPath *field.Path) field.ErrorList {
+	if affinity == nil {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+
+	if affinity.Type != rolloutv1alpha1.CookieSessionAffinity {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("type"), affinity.Type, []string{
+			string(rolloutv1alpha1.CookieSessionAffinity),
+		}))
+	}
+	return allErrs
+}
+
+func validateStepTrafficStrategy(traffic *rolloutv1alpha1.TrafficStrategy, fldPath *field.Path) field.ErrorList {
+	if traffic == nil {
+		return nil
+	}
+	allErrs := validateTrafficStrategy(traffic, fldPath)
+	if traffic.SessionDrain != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("sessionDrain"), "session drain is only supported in canary"))
+	}
+	if traffic.VerifyProbe != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("verifyProbe"), "verify probe is only supported in canary"))
+	}
+	if traffic.SplitProbe != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("splitProbe"), "split probe is only supported in canary"))
+	}
+	if len(traffic.PropagationChecks) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("propagationChecks"), "propagation checks are only supported in canary"))
+	}
+	if traffic.RevertRamp != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("revertRamp"), "revert ramp is only supported in canary"))
+	}
+	if traffic.PausePolicy != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("pausePolicy"), "pause policy is only supported in canary"))
+	}
+	if traffic.SessionAffinity != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("sessionAffinity"), "session affinity is only supported in canary"))
+	}
+	if len(traffic.Ports) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("ports"), "ports are only supported in canary"))
+	}
+	if traffic.Allocation != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("allocation"), "allocation is only supported in canary"))
+	}
+	return allErrs
+}
+
+func validateCanaryAnalysis(analysis *rolloutv1alpha1.CanaryAnalysis, fldPath *field.Path) field.ErrorList {
+	if analysis == nil {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+
+	if len(analysis.Metrics) == 0 {
+		return append(allErrs, field.Required(fldPath.Child("metrics"), "must have at least one metric"))
+	}
+
+	names := sets.NewString()
+	for i, metric := range analysis.Metrics {
+		idxPath := fldPath.Child("metrics").Index(i)
+		if len(metric.Name) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "name is required"))
+		} else if names.Has(metric.Name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), metric.Name))
+		}
+		names.Insert(metric.Name)
+		if len(metric.Provider.Name) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("provider", "name"), "provider name is required"))
+		}
+		if len(metric.Query) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("query"), "query is required"))
+		} else if err := validateQueryTemplate(metric.Query); err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("query"), metric.Query, fmt.Sprintf("must be a valid query template: %v", err)))
+		}
+		if metric.Window != nil && metric.Window.Duration < time.Second {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("window"), metric.Window.Duration.String(), "must be at least 1s"))
+		}
+		if metric.Min == nil && metric.Max == nil {
+			allErrs = append(allErrs, field.Required(idxPath, "at least one of min and max is required"))
+		}
+		if metric.Min != nil {
+			if _, err := strconv.ParseFloat(*metric.Min, 64); err != nil {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("min"), *metric.Min, "must be a number"))
+			}
+		}
+		if metric.Max != nil {
+			if _, err := strconv.ParseFloat(*metric.Max, 64); err != nil {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("max"), *metric.Max, "must be a number"))
+			}
+		}
+	}
+	return allErrs
+}
+
+// queryTemplateVars are the variables rendered in the query templates of
+// metrics by analysis providers.
+var queryTemplateVars = map[string]string{
+	"CanarySelector": `namespace="default",pod=~"canary-.*"`,
+	"StableSelector": `namespace="default",pod=~"stable-.*"`,
+	"Window":         "300s",
+}
+
+// validateQueryTemplate checks that query can be rendered, which only refers to
+// the known variables.
+func validateQueryTemplate(query string) error {
+	tmpl, err := template.New("query").Option("missingkey=error").Parse(query)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(io.Discard, queryTemplateVars)
+}
+
+func validatePromotionWindows(windows *rolloutv1alpha1.PromotionWindows, fldPath *field.Path) field.ErrorList {
+	if windows == nil {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+
+	if len(windows.TimeZone) > 0 {
+		if _, err := time.LoadLocation(windows.TimeZone); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("timeZone"), windows.TimeZone, err.Error()))
+		}
+	}
+
+	if len(windows.Windows) == 0 {
+		return append(allErrs, field.Required(fldPath.Child("windows"), "must have at least one window"))
+	}
+
+	for i, w := range windows.Windows {
+		idxPath := fldPath.Child("windows").Index(i)
+		if _, err := time.Parse("15:04", w.Start); err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("start"), w.Start, "must be in HH:MM format"))
+		}
+		if _, err := time.Parse("15:04", w.End); err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("end"), w.End, "must be in HH:MM format"))
+		}
+	}
+	return allErrs
+}
+
+func validateCanaryRecycleOrder(order []rolloutv1alpha1.CanaryRecycleOperation, fldPath *field.Path) field.ErrorList {
+	if len(order) == 0 {
+		return nil
+	}
+	allErrs := field.ErrorList{}
+
+	index := map[rolloutv1alpha1.CanaryRecycleOperation]int{}
+	for i, op := range order {
+		switch op {
+		case rolloutv1alpha1.RevertCanaryTraffic, rolloutv1alpha1.DeleteCanaryResource, rolloutv1alpha1.RevertStableTraffic:
+		default:
+			allErrs = append(allErrs, field.NotSupported(fldPath.Index(i), op, []string{
+				string(rolloutv1alpha1.RevertCanaryTraffic),
+				string(rolloutv1alpha1.DeleteCanaryResource),
+				string(rolloutv1alpha1.RevertStableTraffic),
+			}))
+			continue
+		}
+		if _, ok := index[op]; ok {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), op))
+			continue
+		}
+		index[op] = i
+	}
+
+	for _, op := range rolloutv1alpha1.DefaultCanaryRecycleOrder {
+		if _, ok := index[op]; !ok {
+			allErrs = append(allErrs, field.Required(fldPath, fmt.Sprintf("must contain %s", op)))
+		}
+	}
+
+	revertCanary, ok1 := index[rolloutv1alpha1.RevertCanaryTraffic]
+	deleteCanary, ok2 := index[rolloutv1alpha1.DeleteCanaryResource]
+	if ok1 && ok2 && deleteCanary < revertCanary {
+		allErrs = append(allErrs, field.Invalid(fldPath, order, "RevertCanaryTraffic must come before DeleteCanaryResource"))
+	}
 	return allErrs
 }
@@ -135,6 +135,246 @@ func TestValidateRolloutStrategy(t *testing.T) {
 			wantErr: true,
 			errLen:  2,
 		},
+		{
+			name: "valid verify probe",
+			obj: func() *rolloutv1alpha1.RolloutStrategy {
+				obj := validStratgy.DeepCopy()
+				obj.Canary.Traffic = &rolloutv1alpha1.TrafficStrategy{
+					Weight: ptr.To[int32](10),
+					VerifyProbe: &rolloutv1alpha1.TrafficVerifyProbe{
+						URL:            "http://gateway.example.com/healthz",
+						ExpectedStatus: ptr.To[int32](204),
+					},
+				}
+				return obj
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "invalid verify probe",
+			obj: func() *rolloutv1alpha1.RolloutStrategy {
+				obj := validStratgy.DeepCopy()
+				obj.Canary.Traffic = &rolloutv1alpha1.TrafficStrategy{
+					Weight: ptr.To[int32](10),
+					VerifyProbe: &rolloutv1alpha1.TrafficVerifyProbe{
+						URL:            "gateway/healthz",
+						ExpectedStatus: ptr.To[int32](1000),
+					},
+				}
+				obj.Batch.Batches[0].Traffic = &rolloutv1alpha1.TrafficStrategy{
+					Weight: ptr.To[int32](10),
+					VerifyProbe: &rolloutv1alpha1.TrafficVerifyProbe{
+						URL: "http://gateway.example.com/healthz",
+					},
+				}
+				return obj
+			}(),
+			wantErr: true,
+			errLen:  3,
+		},
+		{
+			name: "object metadata patch overrides builtin keys",
+			obj: func() *rolloutv1alpha1.RolloutStrategy {
+				obj := validStratgy.DeepCopy()
+				obj.Canary.ObjectMetadataPatch = &rolloutv1alpha1.MetadataPatch{
+					Labels: map[string]string{
+						"cost-center":                   "infra",
+						"rollout.kusionstack.io/canary": "false",
+					},
+					Annotations: map[string]string{
+						"owner": "team-a",
+						"rollout.kusionstack.io/progressing-info": "{}",
+					},
+				}
+				return obj
+			}(),
+			wantErr: true,
+			errLen:  2,
+		},
+		{
+			name: "valid recycle order",
+			obj: func() *rolloutv1alpha1.RolloutStrategy {
+				obj := validStratgy.DeepCopy()
+				obj.Canary.RecycleOrder = []rolloutv1alpha1.CanaryRecycleOperation{
+					rolloutv1alpha1.RevertStableTraffic,
+					rolloutv1alpha1.RevertCanaryTraffic,
+					rolloutv1alpha1.DeleteCanaryResource,
+				}
+				return obj
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "delete canary before reverting canary traffic",
+			obj: func() *rolloutv1alpha1.RolloutStrategy {
+				obj := validStratgy.DeepCopy()
+				obj.Canary.RecycleOrder = []rolloutv1alpha1.CanaryRecycleOperation{
+					rolloutv1alpha1.DeleteCanaryResource,
+					rolloutv1alpha1.RevertCanaryTraffic,
+					rolloutv1alpha1.RevertStableTraffic,
+				}
+				return obj
+			}(),
+			wantErr: true,
+			errLen:  1,
+		},
+		{
+			name: "duplicated and missing recycle operations",
+			obj: func() *rolloutv1alpha1.RolloutStrategy {
+				obj := validStratgy.DeepCopy()
+				obj.Canary.RecycleOrder = []rolloutv1alpha1.CanaryRecycleOperation{
+					rolloutv1alpha1.RevertCanaryTraffic,
+					rolloutv1alpha1.RevertCanaryTraffic,
+					rolloutv1alpha1.DeleteCanaryResource,
+				}
+				return obj
+			}(),
+			wantErr: true,
+			errLen:  2,
+		},
+		{
+			name: "valid traffic allocation",
+			obj: func() *rolloutv1alpha1.RolloutStrategy {
+				obj := validStratgy.DeepCopy()
+				obj.Canary.Traffic = &rolloutv1alpha1.TrafficStrategy{
+					Allocation: &rolloutv1alpha1.TrafficAllocation{
+						Variants: []rolloutv1alpha1.TrafficVariant{
+							{Name: "a", Percent: 20},
+						},
+						StablePercent: 80,
+					},
+				}
+				return obj
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "traffic allocation with variants but no canary variants",
+			obj: func() *rolloutv1alpha1.RolloutStrategy {
+				obj := validStratgy.DeepCopy()
+				obj.Canary.Traffic = &rolloutv1alpha1.TrafficStrategy{
+					Allocation: &rolloutv1alpha1.TrafficAllocation{
+						Variants: []rolloutv1alpha1.TrafficVariant{
+							{Name: "a", Percent: 10},
+							{Name: "b", Percent: 10},
+						},
+						StablePercent: 80,
+					},
+				}
+				return obj
+			}(),
+			wantErr: true,
+			errLen:  1,
+		},
+		{
+			name: "traffic allocation does not sum to 100",
+			obj: func() *rolloutv1alpha1.RolloutStrategy {
+				obj := validStratgy.DeepCopy()
+				obj.Canary.Traffic = &rolloutv1alpha1.TrafficStrategy{
+					Allocation: &rolloutv1alpha1.TrafficAllocation{
+						Variants: []rolloutv1alpha1.TrafficVariant{
+							{Name: "a", Percent: 30},
+						},
+						StablePercent: 80,
+					},
+				}
+				return obj
+			}(),
+			wantErr: true,
+			errLen:  1,
+		},
+		{
+			name: "traffic allocation with weight and duplicated variants",
+			obj: func() *rolloutv1alpha1.RolloutStrategy {
+				obj := validStratgy.DeepCopy()
+				obj.Canary.Traffic = &rolloutv1alpha1.TrafficStrategy{
+					Weight: ptr.To[int32](20),
+					Allocation: &rolloutv1alpha1.TrafficAllocation{
+						Variants: []rolloutv1alpha1.TrafficVariant{
+							{Name: "a", Percent: 10},
+							{Name: "a", Percent: 10},
+						},
+						StablePercent: 80,
+					},
+				}
+				return obj
+			}(),
+			wantErr: true,
+			// weight, duplicate, canary variants required
+			errLen: 3,
+		},
+		{
+			name: "valid canary variants",
+			obj: func() *rolloutv1alpha1.RolloutStrategy {
+				obj := validStratgy.DeepCopy()
+				obj.Canary.Traffic = &rolloutv1alpha1.TrafficStrategy{
+					Allocation: &rolloutv1alpha1.TrafficAllocation{
+						Variants: []rolloutv1alpha1.TrafficVariant{
+							{Name: "b", Percent: 20},
+							{Name: "c", Percent: 10},
+						},
+						StablePercent: 70,
+					},
+				}
+				obj.Canary.Variants = []rolloutv1alpha1.CanaryVariant{
+					{Name: "b", Replicas: intstr.FromInt(1)},
+					{Name: "c", Replicas: intstr.FromString("10%")},
+				}
+				return obj
+			}(),
+			wantErr: false,
+		},
+		{
+			name: "canary variants not in traffic allocation",
+			obj: func() *rolloutv1alpha1.RolloutStrategy {
+				obj := validStratgy.DeepCopy()
+				obj.Canary.Traffic = &rolloutv1alpha1.TrafficStrategy{
+					Allocation: &rolloutv1alpha1.TrafficAllocation{
+						Variants: []rolloutv1alpha1.TrafficVariant{
+							{Name: "b", Percent: 20},
+							{Name: "c", Percent: 10},
+						},
+						StablePercent: 70,
+					},
+				}
+				obj.Canary.Variants = []rolloutv1alpha1.CanaryVariant{
+					{Name: "b", Replicas: intstr.FromInt(1)},
+					{Name: "b", Replicas: intstr.FromInt(1)},
+				}
+				return obj
+			}(),
+			wantErr: true,
+			// duplicate, variant c is missing
+			errLen: 2,
+		},
+		{
+			name: "duplicated canary notifications and invalid url",
+			obj: func() *rolloutv1alpha1.RolloutStrategy {
+				obj := validStratgy.DeepCopy()
+				obj.Canary.Notifications = []rolloutv1alpha1.CanaryNotification{
+					{Name: "slack", ClientConfig: rolloutv1alpha1.WebhookClientConfig{URL: "https://slack.example.com/hook"}},
+					{Name: "slack", ClientConfig: rolloutv1alpha1.WebhookClientConfig{URL: "not a url"}},
+				}
+				return obj
+			}(),
+			wantErr: true,
+			errLen:  2,
+		},
+		{
+			name: "invalid canary bake",
+			obj: func() *rolloutv1alpha1.RolloutStrategy {
+				obj := validStratgy.DeepCopy()
+				obj.Canary.Bake = &rolloutv1alpha1.CanaryBake{
+					Windows:       0,
+					ActiveSeconds: 60,
+					IdleSeconds:   0,
+					IdleReplicas:  ptr.To[int32](-1),
+				}
+				return obj
+			}(),
+			wantErr: true,
+			errLen:  3,
+		},
 	}
 	for i := range tests {
 		tt := tests[i]
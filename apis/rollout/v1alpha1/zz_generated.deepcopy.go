@@ -19,12 +19,60 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/gateway-api/apis/v1"
+	"sigs.k8s.io/gateway-api/apis/v1alpha2"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalysisMetric) DeepCopyInto(out *AnalysisMetric) {
+	*out = *in
+	out.Provider = in.Provider
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Min != nil {
+		in, out := &in.Min, &out.Min
+		*out = new(string)
+		**out = **in
+	}
+	if in.Max != nil {
+		in, out := &in.Max, &out.Max
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalysisMetric.
+func (in *AnalysisMetric) DeepCopy() *AnalysisMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalysisMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalysisProvider) DeepCopyInto(out *AnalysisProvider) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalysisProvider.
+func (in *AnalysisProvider) DeepCopy() *AnalysisProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalysisProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackendConditions) DeepCopyInto(out *BackendConditions) {
 	*out = *in
@@ -55,6 +103,11 @@ func (in *BackendForwarding) DeepCopyInto(out *BackendForwarding) {
 	*out = *in
 	out.Stable = in.Stable
 	in.Canary.DeepCopyInto(&out.Canary)
+	if in.Variants != nil {
+		in, out := &in.Variants, &out.Variants
+		*out = make([]CanaryVariantBackendRule, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendForwarding.
@@ -217,6 +270,11 @@ func (in *BackendRoutingStatus) DeepCopy() *BackendRoutingStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackendStatus) DeepCopyInto(out *BackendStatus) {
 	*out = *in
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.Conditions.DeepCopyInto(&out.Conditions)
 }
 
@@ -236,6 +294,13 @@ func (in *BackendStatuses) DeepCopyInto(out *BackendStatuses) {
 	in.Origin.DeepCopyInto(&out.Origin)
 	in.Stable.DeepCopyInto(&out.Stable)
 	in.Canary.DeepCopyInto(&out.Canary)
+	if in.Variants != nil {
+		in, out := &in.Variants, &out.Variants
+		*out = make([]BackendStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendStatuses.
@@ -291,714 +356,2400 @@ func (in *BatchStrategy) DeepCopy() *BatchStrategy {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CanaryBackendRule) DeepCopyInto(out *CanaryBackendRule) {
+func (in *CanaryActiveDeadlineStatus) DeepCopyInto(out *CanaryActiveDeadlineStatus) {
 	*out = *in
-	in.TrafficStrategy.DeepCopyInto(&out.TrafficStrategy)
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Deadline != nil {
+		in, out := &in.Deadline, &out.Deadline
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryBackendRule.
-func (in *CanaryBackendRule) DeepCopy() *CanaryBackendRule {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryActiveDeadlineStatus.
+func (in *CanaryActiveDeadlineStatus) DeepCopy() *CanaryActiveDeadlineStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(CanaryBackendRule)
+	out := new(CanaryActiveDeadlineStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CanaryProgressingInfo) DeepCopyInto(out *CanaryProgressingInfo) {
+func (in *CanaryAnalysis) DeepCopyInto(out *CanaryAnalysis) {
 	*out = *in
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]AnalysisMetric, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryProgressingInfo.
-func (in *CanaryProgressingInfo) DeepCopy() *CanaryProgressingInfo {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryAnalysis.
+func (in *CanaryAnalysis) DeepCopy() *CanaryAnalysis {
 	if in == nil {
 		return nil
 	}
-	out := new(CanaryProgressingInfo)
+	out := new(CanaryAnalysis)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CanaryStrategy) DeepCopyInto(out *CanaryStrategy) {
+func (in *CanaryAutoRollback) DeepCopyInto(out *CanaryAutoRollback) {
 	*out = *in
-	out.Replicas = in.Replicas
-	if in.Traffic != nil {
-		in, out := &in.Traffic, &out.Traffic
-		*out = new(TrafficStrategy)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Match != nil {
-		in, out := &in.Match, &out.Match
-		*out = new(ResourceMatch)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Properties != nil {
-		in, out := &in.Properties, &out.Properties
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.PodTemplateMetadataPatch != nil {
-		in, out := &in.PodTemplateMetadataPatch, &out.PodTemplateMetadataPatch
-		*out = new(MetadataPatch)
-		(*in).DeepCopyInto(*out)
+	if in.WindowSeconds != nil {
+		in, out := &in.WindowSeconds, &out.WindowSeconds
+		*out = new(int32)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStrategy.
-func (in *CanaryStrategy) DeepCopy() *CanaryStrategy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryAutoRollback.
+func (in *CanaryAutoRollback) DeepCopy() *CanaryAutoRollback {
 	if in == nil {
 		return nil
 	}
-	out := new(CanaryStrategy)
+	out := new(CanaryAutoRollback)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CodeReasonMessage) DeepCopyInto(out *CodeReasonMessage) {
+func (in *CanaryBackendRule) DeepCopyInto(out *CanaryBackendRule) {
 	*out = *in
+	in.TrafficStrategy.DeepCopyInto(&out.TrafficStrategy)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CodeReasonMessage.
-func (in *CodeReasonMessage) DeepCopy() *CodeReasonMessage {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryBackendRule.
+func (in *CanaryBackendRule) DeepCopy() *CanaryBackendRule {
 	if in == nil {
 		return nil
 	}
-	out := new(CodeReasonMessage)
+	out := new(CanaryBackendRule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Condition) DeepCopyInto(out *Condition) {
+func (in *CanaryBake) DeepCopyInto(out *CanaryBake) {
 	*out = *in
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
-	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	if in.IdleReplicas != nil {
+		in, out := &in.IdleReplicas, &out.IdleReplicas
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
-func (in *Condition) DeepCopy() *Condition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryBake.
+func (in *CanaryBake) DeepCopy() *CanaryBake {
 	if in == nil {
 		return nil
 	}
-	out := new(Condition)
+	out := new(CanaryBake)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CrossClusterObjectNameReference) DeepCopyInto(out *CrossClusterObjectNameReference) {
+func (in *CanaryBakeStatus) DeepCopyInto(out *CanaryBakeStatus) {
 	*out = *in
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrossClusterObjectNameReference.
-func (in *CrossClusterObjectNameReference) DeepCopy() *CrossClusterObjectNameReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryBakeStatus.
+func (in *CanaryBakeStatus) DeepCopy() *CanaryBakeStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(CrossClusterObjectNameReference)
+	out := new(CanaryBakeStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CrossClusterObjectReference) DeepCopyInto(out *CrossClusterObjectReference) {
+func (in *CanaryCompletionStatus) DeepCopyInto(out *CanaryCompletionStatus) {
 	*out = *in
-	out.ObjectTypeRef = in.ObjectTypeRef
-	out.CrossClusterObjectNameReference = in.CrossClusterObjectNameReference
+	if in.Result != nil {
+		in, out := &in.Result, &out.Result
+		*out = new(CodeReasonMessage)
+		**out = **in
+	}
+	if in.Time != nil {
+		in, out := &in.Time, &out.Time
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrossClusterObjectReference.
-func (in *CrossClusterObjectReference) DeepCopy() *CrossClusterObjectReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryCompletionStatus.
+func (in *CanaryCompletionStatus) DeepCopy() *CanaryCompletionStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(CrossClusterObjectReference)
+	out := new(CanaryCompletionStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HTTPRouteFilter) DeepCopyInto(out *HTTPRouteFilter) {
+func (in *CanaryConfigOverride) DeepCopyInto(out *CanaryConfigOverride) {
 	*out = *in
-	if in.RequestHeaderModifier != nil {
-		in, out := &in.RequestHeaderModifier, &out.RequestHeaderModifier
-		*out = new(v1.HTTPHeaderFilter)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRouteFilter.
-func (in *HTTPRouteFilter) DeepCopy() *HTTPRouteFilter {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryConfigOverride.
+func (in *CanaryConfigOverride) DeepCopy() *CanaryConfigOverride {
 	if in == nil {
 		return nil
 	}
-	out := new(HTTPRouteFilter)
+	out := new(CanaryConfigOverride)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HTTPRouteMatch) DeepCopyInto(out *HTTPRouteMatch) {
+func (in *CanaryCrashLoopCheck) DeepCopyInto(out *CanaryCrashLoopCheck) {
 	*out = *in
-	if in.Headers != nil {
-		in, out := &in.Headers, &out.Headers
-		*out = make([]v1.HTTPHeaderMatch, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.RestartThreshold != nil {
+		in, out := &in.RestartThreshold, &out.RestartThreshold
+		*out = new(int32)
+		**out = **in
 	}
-	if in.QueryParams != nil {
-		in, out := &in.QueryParams, &out.QueryParams
-		*out = make([]v1.HTTPQueryParamMatch, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.PodThreshold != nil {
+		in, out := &in.PodThreshold, &out.PodThreshold
+		*out = new(int32)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRouteMatch.
-func (in *HTTPRouteMatch) DeepCopy() *HTTPRouteMatch {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryCrashLoopCheck.
+func (in *CanaryCrashLoopCheck) DeepCopy() *CanaryCrashLoopCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(HTTPRouteMatch)
+	out := new(CanaryCrashLoopCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HTTPRouteRule) DeepCopyInto(out *HTTPRouteRule) {
+func (in *CanaryDriftDetection) DeepCopyInto(out *CanaryDriftDetection) {
 	*out = *in
-	if in.Matches != nil {
-		in, out := &in.Matches, &out.Matches
-		*out = make([]HTTPRouteMatch, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	in.Filter.DeepCopyInto(&out.Filter)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRouteRule.
-func (in *HTTPRouteRule) DeepCopy() *HTTPRouteRule {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryDriftDetection.
+func (in *CanaryDriftDetection) DeepCopy() *CanaryDriftDetection {
 	if in == nil {
 		return nil
 	}
-	out := new(HTTPRouteRule)
+	out := new(CanaryDriftDetection)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MetadataPatch) DeepCopyInto(out *MetadataPatch) {
+func (in *CanaryDriftStatus) DeepCopyInto(out *CanaryDriftStatus) {
 	*out = *in
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.DetectedTime != nil {
+		in, out := &in.DetectedTime, &out.DetectedTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataPatch.
-func (in *MetadataPatch) DeepCopy() *MetadataPatch {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryDriftStatus.
+func (in *CanaryDriftStatus) DeepCopy() *CanaryDriftStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MetadataPatch)
+	out := new(CanaryDriftStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ObjectTypeRef) DeepCopyInto(out *ObjectTypeRef) {
+func (in *CanaryFastPromotionStatus) DeepCopyInto(out *CanaryFastPromotionStatus) {
 	*out = *in
+	if in.Time != nil {
+		in, out := &in.Time, &out.Time
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectTypeRef.
-func (in *ObjectTypeRef) DeepCopy() *ObjectTypeRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryFastPromotionStatus.
+func (in *CanaryFastPromotionStatus) DeepCopy() *CanaryFastPromotionStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ObjectTypeRef)
+	out := new(CanaryFastPromotionStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ProgressingInfo) DeepCopyInto(out *ProgressingInfo) {
+func (in *CanaryHoldStatus) DeepCopyInto(out *CanaryHoldStatus) {
 	*out = *in
-	if in.Canary != nil {
-		in, out := &in.Canary, &out.Canary
-		*out = new(CanaryProgressingInfo)
-		**out = **in
-	}
-	if in.Batch != nil {
-		in, out := &in.Batch, &out.Batch
-		*out = new(BatchProgressingInfo)
-		**out = **in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProgressingInfo.
-func (in *ProgressingInfo) DeepCopy() *ProgressingInfo {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryHoldStatus.
+func (in *CanaryHoldStatus) DeepCopy() *CanaryHoldStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ProgressingInfo)
+	out := new(CanaryHoldStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceMatch) DeepCopyInto(out *ResourceMatch) {
+func (in *CanaryImagePullCheck) DeepCopyInto(out *CanaryImagePullCheck) {
 	*out = *in
-	if in.Selector != nil {
-		in, out := &in.Selector, &out.Selector
-		*out = new(metav1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Names != nil {
-		in, out := &in.Names, &out.Names
-		*out = make([]CrossClusterObjectNameReference, len(*in))
-		copy(*out, *in)
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceMatch.
-func (in *ResourceMatch) DeepCopy() *ResourceMatch {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryImagePullCheck.
+func (in *CanaryImagePullCheck) DeepCopy() *CanaryImagePullCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceMatch)
+	out := new(CanaryImagePullCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Rollout) DeepCopyInto(out *Rollout) {
+func (in *CanaryMaxActiveDuration) DeepCopyInto(out *CanaryMaxActiveDuration) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	if in.WarningSeconds != nil {
+		in, out := &in.WarningSeconds, &out.WarningSeconds
+		*out = new(int32)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Rollout.
-func (in *Rollout) DeepCopy() *Rollout {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryMaxActiveDuration.
+func (in *CanaryMaxActiveDuration) DeepCopy() *CanaryMaxActiveDuration {
 	if in == nil {
 		return nil
 	}
-	out := new(Rollout)
+	out := new(CanaryMaxActiveDuration)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Rollout) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryNotification) DeepCopyInto(out *CanaryNotification) {
+	*out = *in
+	in.ClientConfig.DeepCopyInto(&out.ClientConfig)
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
-	return nil
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryNotification.
+func (in *CanaryNotification) DeepCopy() *CanaryNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryNotification)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutBatchStatus) DeepCopyInto(out *RolloutBatchStatus) {
+func (in *CanaryOutstandingPrerequisite) DeepCopyInto(out *CanaryOutstandingPrerequisite) {
 	*out = *in
+	out.CrossClusterObjectReference = in.CrossClusterObjectReference
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutBatchStatus.
-func (in *RolloutBatchStatus) DeepCopy() *RolloutBatchStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryOutstandingPrerequisite.
+func (in *CanaryOutstandingPrerequisite) DeepCopy() *CanaryOutstandingPrerequisite {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutBatchStatus)
+	out := new(CanaryOutstandingPrerequisite)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutList) DeepCopyInto(out *RolloutList) {
+func (in *CanaryPauseCheckpoint) DeepCopyInto(out *CanaryPauseCheckpoint) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Rollout, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.PauseTime != nil {
+		in, out := &in.PauseTime, &out.PauseTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ResumeTime != nil {
+		in, out := &in.ResumeTime, &out.ResumeTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutList.
-func (in *RolloutList) DeepCopy() *RolloutList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryPauseCheckpoint.
+func (in *CanaryPauseCheckpoint) DeepCopy() *CanaryPauseCheckpoint {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutList)
+	out := new(CanaryPauseCheckpoint)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RolloutList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutReplicasSummary) DeepCopyInto(out *RolloutReplicasSummary) {
+func (in *CanaryPodDisruptionBudget) DeepCopyInto(out *CanaryPodDisruptionBudget) {
 	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutReplicasSummary.
-func (in *RolloutReplicasSummary) DeepCopy() *RolloutReplicasSummary {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryPodDisruptionBudget.
+func (in *CanaryPodDisruptionBudget) DeepCopy() *CanaryPodDisruptionBudget {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutReplicasSummary)
+	out := new(CanaryPodDisruptionBudget)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutRun) DeepCopyInto(out *RolloutRun) {
+func (in *CanaryPodFailure) DeepCopyInto(out *CanaryPodFailure) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutRun.
-func (in *RolloutRun) DeepCopy() *RolloutRun {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryPodFailure.
+func (in *CanaryPodFailure) DeepCopy() *CanaryPodFailure {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutRun)
+	out := new(CanaryPodFailure)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RolloutRun) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutRunBatchStatus) DeepCopyInto(out *RolloutRunBatchStatus) {
+func (in *CanaryPreconditionRef) DeepCopyInto(out *CanaryPreconditionRef) {
 	*out = *in
-	out.RolloutBatchStatus = in.RolloutBatchStatus
-	if in.Records != nil {
-		in, out := &in.Records, &out.Records
-		*out = make([]RolloutRunStepStatus, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	out.CrossClusterObjectReference = in.CrossClusterObjectReference
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutRunBatchStatus.
-func (in *RolloutRunBatchStatus) DeepCopy() *RolloutRunBatchStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryPreconditionRef.
+func (in *CanaryPreconditionRef) DeepCopy() *CanaryPreconditionRef {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutRunBatchStatus)
+	out := new(CanaryPreconditionRef)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutRunBatchStrategy) DeepCopyInto(out *RolloutRunBatchStrategy) {
+func (in *CanaryPreconditionsStatus) DeepCopyInto(out *CanaryPreconditionsStatus) {
 	*out = *in
-	if in.Batches != nil {
-		in, out := &in.Batches, &out.Batches
-		*out = make([]RolloutRunStep, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Toleration != nil {
-		in, out := &in.Toleration, &out.Toleration
-		*out = new(TolerationStrategy)
-		(*in).DeepCopyInto(*out)
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutRunBatchStrategy.
-func (in *RolloutRunBatchStrategy) DeepCopy() *RolloutRunBatchStrategy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryPreconditionsStatus.
+func (in *CanaryPreconditionsStatus) DeepCopy() *CanaryPreconditionsStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutRunBatchStrategy)
+	out := new(CanaryPreconditionsStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutRunCanaryStrategy) DeepCopyInto(out *RolloutRunCanaryStrategy) {
+func (in *CanaryPrerequisitesStatus) DeepCopyInto(out *CanaryPrerequisitesStatus) {
 	*out = *in
-	if in.Targets != nil {
-		in, out := &in.Targets, &out.Targets
-		*out = make([]RolloutRunStepTarget, len(*in))
-		copy(*out, *in)
-	}
-	if in.Traffic != nil {
-		in, out := &in.Traffic, &out.Traffic
-		*out = new(TrafficStrategy)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Properties != nil {
-		in, out := &in.Properties, &out.Properties
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
 	}
-	if in.PodTemplateMetadataPatch != nil {
-		in, out := &in.PodTemplateMetadataPatch, &out.PodTemplateMetadataPatch
-		*out = new(MetadataPatch)
-		(*in).DeepCopyInto(*out)
+	if in.Outstanding != nil {
+		in, out := &in.Outstanding, &out.Outstanding
+		*out = make([]CanaryOutstandingPrerequisite, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutRunCanaryStrategy.
-func (in *RolloutRunCanaryStrategy) DeepCopy() *RolloutRunCanaryStrategy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryPrerequisitesStatus.
+func (in *CanaryPrerequisitesStatus) DeepCopy() *CanaryPrerequisitesStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutRunCanaryStrategy)
+	out := new(CanaryPrerequisitesStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutRunList) DeepCopyInto(out *RolloutRunList) {
+func (in *CanaryProgressingInfo) DeepCopyInto(out *CanaryProgressingInfo) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]RolloutRun, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutRunList.
-func (in *RolloutRunList) DeepCopy() *RolloutRunList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryProgressingInfo.
+func (in *CanaryProgressingInfo) DeepCopy() *CanaryProgressingInfo {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutRunList)
+	out := new(CanaryProgressingInfo)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RolloutRunList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryReadinessStabilization) DeepCopyInto(out *CanaryReadinessStabilization) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryReadinessStabilization.
+func (in *CanaryReadinessStabilization) DeepCopy() *CanaryReadinessStabilization {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(CanaryReadinessStabilization)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutRunSpec) DeepCopyInto(out *RolloutRunSpec) {
+func (in *CanaryReadinessStabilizationStatus) DeepCopyInto(out *CanaryReadinessStabilizationStatus) {
 	*out = *in
-	out.TargetType = in.TargetType
-	if in.Webhooks != nil {
-		in, out := &in.Webhooks, &out.Webhooks
-		*out = make([]RolloutWebhook, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.TrafficTopologyRefs != nil {
-		in, out := &in.TrafficTopologyRefs, &out.TrafficTopologyRefs
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Canary != nil {
-		in, out := &in.Canary, &out.Canary
-		*out = new(RolloutRunCanaryStrategy)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Batch != nil {
-		in, out := &in.Batch, &out.Batch
-		*out = new(RolloutRunBatchStrategy)
-		(*in).DeepCopyInto(*out)
+	if in.WindowStartTime != nil {
+		in, out := &in.WindowStartTime, &out.WindowStartTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutRunSpec.
-func (in *RolloutRunSpec) DeepCopy() *RolloutRunSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryReadinessStabilizationStatus.
+func (in *CanaryReadinessStabilizationStatus) DeepCopy() *CanaryReadinessStabilizationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutRunSpec)
+	out := new(CanaryReadinessStabilizationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutRunStatus) DeepCopyInto(out *RolloutRunStatus) {
+func (in *CanaryReplicasStatus) DeepCopyInto(out *CanaryReplicasStatus) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.LastUpdateTime != nil {
-		in, out := &in.LastUpdateTime, &out.LastUpdateTime
-		*out = (*in).DeepCopy()
-	}
-	if in.Error != nil {
-		in, out := &in.Error, &out.Error
-		*out = new(CodeReasonMessage)
-		**out = **in
-	}
-	if in.CanaryStatus != nil {
-		in, out := &in.CanaryStatus, &out.CanaryStatus
-		*out = new(RolloutRunStepStatus)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.BatchStatus != nil {
-		in, out := &in.BatchStatus, &out.BatchStatus
-		*out = new(RolloutRunBatchStatus)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.TargetStatuses != nil {
-		in, out := &in.TargetStatuses, &out.TargetStatuses
-		*out = make([]RolloutWorkloadStatus, len(*in))
+	out.RolloutReplicasSummary = in.RolloutReplicasSummary
+	if in.NotReadyTargets != nil {
+		in, out := &in.NotReadyTargets, &out.NotReadyTargets
+		*out = make([]CrossClusterObjectNameReference, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutRunStatus.
-func (in *RolloutRunStatus) DeepCopy() *RolloutRunStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryReplicasStatus.
+func (in *CanaryReplicasStatus) DeepCopy() *CanaryReplicasStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutRunStatus)
+	out := new(CanaryReplicasStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutRunStep) DeepCopyInto(out *RolloutRunStep) {
+func (in *CanaryResourceFootprint) DeepCopyInto(out *CanaryResourceFootprint) {
 	*out = *in
-	if in.Targets != nil {
-		in, out := &in.Targets, &out.Targets
-		*out = make([]RolloutRunStepTarget, len(*in))
-		copy(*out, *in)
-	}
-	if in.Traffic != nil {
-		in, out := &in.Traffic, &out.Traffic
-		*out = new(TrafficStrategy)
-		(*in).DeepCopyInto(*out)
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
 	}
-	if in.Properties != nil {
-		in, out := &in.Properties, &out.Properties
-		*out = make(map[string]string, len(*in))
+	if in.Stable != nil {
+		in, out := &in.Stable, &out.Stable
+		*out = make(corev1.ResourceList, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			(*out)[key] = val.DeepCopy()
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutRunStep.
-func (in *RolloutRunStep) DeepCopy() *RolloutRunStep {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryResourceFootprint.
+func (in *CanaryResourceFootprint) DeepCopy() *CanaryResourceFootprint {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutRunStep)
+	out := new(CanaryResourceFootprint)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutRunStepStatus) DeepCopyInto(out *RolloutRunStepStatus) {
+func (in *CanaryRetentionStatus) DeepCopyInto(out *CanaryRetentionStatus) {
 	*out = *in
-	if in.Index != nil {
-		in, out := &in.Index, &out.Index
-		*out = new(int32)
-		**out = **in
-	}
 	if in.StartTime != nil {
 		in, out := &in.StartTime, &out.StartTime
 		*out = (*in).DeepCopy()
 	}
-	if in.FinishTime != nil {
-		in, out := &in.FinishTime, &out.FinishTime
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
 		*out = (*in).DeepCopy()
 	}
-	if in.Targets != nil {
-		in, out := &in.Targets, &out.Targets
-		*out = make([]RolloutWorkloadStatus, len(*in))
-		copy(*out, *in)
-	}
-	if in.Webhooks != nil {
-		in, out := &in.Webhooks, &out.Webhooks
-		*out = make([]RolloutWebhookStatus, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutRunStepStatus.
-func (in *RolloutRunStepStatus) DeepCopy() *RolloutRunStepStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryRetentionStatus.
+func (in *CanaryRetentionStatus) DeepCopy() *CanaryRetentionStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutRunStepStatus)
+	out := new(CanaryRetentionStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutRunStepTarget) DeepCopyInto(out *RolloutRunStepTarget) {
+func (in *CanaryRollbackStatus) DeepCopyInto(out *CanaryRollbackStatus) {
 	*out = *in
-	out.CrossClusterObjectNameReference = in.CrossClusterObjectNameReference
-	out.Replicas = in.Replicas
+	if in.Times != nil {
+		in, out := &in.Times, &out.Times
+		*out = make([]metav1.Time, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastError != nil {
+		in, out := &in.LastError, &out.LastError
+		*out = new(CodeReasonMessage)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutRunStepTarget.
-func (in *RolloutRunStepTarget) DeepCopy() *RolloutRunStepTarget {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryRollbackStatus.
+func (in *CanaryRollbackStatus) DeepCopy() *CanaryRollbackStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutRunStepTarget)
+	out := new(CanaryRollbackStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutSpec) DeepCopyInto(out *RolloutSpec) {
+func (in *CanaryRollingUpdateStrategy) DeepCopyInto(out *CanaryRollingUpdateStrategy) {
 	*out = *in
-	if in.HistoryLimit != nil {
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryRollingUpdateStrategy.
+func (in *CanaryRollingUpdateStrategy) DeepCopy() *CanaryRollingUpdateStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryRollingUpdateStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryScaleUpStatus) DeepCopyInto(out *CanaryScaleUpStatus) {
+	*out = *in
+	out.CrossClusterObjectNameReference = in.CrossClusterObjectNameReference
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryScaleUpStatus.
+func (in *CanaryScaleUpStatus) DeepCopy() *CanaryScaleUpStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryScaleUpStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryStrategy) DeepCopyInto(out *CanaryStrategy) {
+	*out = *in
+	out.Replicas = in.Replicas
+	if in.Traffic != nil {
+		in, out := &in.Traffic, &out.Traffic
+		*out = new(TrafficStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Variants != nil {
+		in, out := &in.Variants, &out.Variants
+		*out = make([]CanaryVariant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Match != nil {
+		in, out := &in.Match, &out.Match
+		*out = new(ResourceMatch)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PodTemplateMetadataPatch != nil {
+		in, out := &in.PodTemplateMetadataPatch, &out.PodTemplateMetadataPatch
+		*out = new(MetadataPatch)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ObjectMetadataPatch != nil {
+		in, out := &in.ObjectMetadataPatch, &out.ObjectMetadataPatch
+		*out = new(MetadataPatch)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodPlacementPatch != nil {
+		in, out := &in.PodPlacementPatch, &out.PodPlacementPatch
+		*out = new(PodPlacementPatch)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PromotionWindows != nil {
+		in, out := &in.PromotionWindows, &out.PromotionWindows
+		*out = new(PromotionWindows)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Analysis != nil {
+		in, out := &in.Analysis, &out.Analysis
+		*out = new(CanaryAnalysis)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PauseAfter != nil {
+		in, out := &in.PauseAfter, &out.PauseAfter
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PauseBefore != nil {
+		in, out := &in.PauseBefore, &out.PauseBefore
+		*out = make([]CanaryPausePhase, len(*in))
+		copy(*out, *in)
+	}
+	if in.RecycleOrder != nil {
+		in, out := &in.RecycleOrder, &out.RecycleOrder
+		*out = make([]CanaryRecycleOperation, len(*in))
+		copy(*out, *in)
+	}
+	if in.RetentionSeconds != nil {
+		in, out := &in.RetentionSeconds, &out.RetentionSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ReadinessTimeoutSeconds != nil {
+		in, out := &in.ReadinessTimeoutSeconds, &out.ReadinessTimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ConfigOverrides != nil {
+		in, out := &in.ConfigOverrides, &out.ConfigOverrides
+		*out = make([]CanaryConfigOverride, len(*in))
+		copy(*out, *in)
+	}
+	if in.InitContainerOverrides != nil {
+		in, out := &in.InitContainerOverrides, &out.InitContainerOverrides
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.OwnerReferences != nil {
+		in, out := &in.OwnerReferences, &out.OwnerReferences
+		*out = make([]metav1.OwnerReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(CanaryPodDisruptionBudget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CrashLoopCheck != nil {
+		in, out := &in.CrashLoopCheck, &out.CrashLoopCheck
+		*out = new(CanaryCrashLoopCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImagePullCheck != nil {
+		in, out := &in.ImagePullCheck, &out.ImagePullCheck
+		*out = new(CanaryImagePullCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthCheckGracePeriodSeconds != nil {
+		in, out := &in.HealthCheckGracePeriodSeconds, &out.HealthCheckGracePeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ReadinessStabilization != nil {
+		in, out := &in.ReadinessStabilization, &out.ReadinessStabilization
+		*out = new(CanaryReadinessStabilization)
+		**out = **in
+	}
+	if in.DegradedClusterGracePeriodSeconds != nil {
+		in, out := &in.DegradedClusterGracePeriodSeconds, &out.DegradedClusterGracePeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxActiveDuration != nil {
+		in, out := &in.MaxActiveDuration, &out.MaxActiveDuration
+		*out = new(CanaryMaxActiveDuration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = make([]CanaryNotification, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Bake != nil {
+		in, out := &in.Bake, &out.Bake
+		*out = new(CanaryBake)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StableMinAvailable != nil {
+		in, out := &in.StableMinAvailable, &out.StableMinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.StuckDeletion != nil {
+		in, out := &in.StuckDeletion, &out.StuckDeletion
+		*out = new(CanaryStuckDeletion)
+		**out = **in
+	}
+	if in.DriftDetection != nil {
+		in, out := &in.DriftDetection, &out.DriftDetection
+		*out = new(CanaryDriftDetection)
+		**out = **in
+	}
+	if in.AutoRollback != nil {
+		in, out := &in.AutoRollback, &out.AutoRollback
+		*out = new(CanaryAutoRollback)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Ordinals != nil {
+		in, out := &in.Ordinals, &out.Ordinals
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(CanaryUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScaleUpStep != nil {
+		in, out := &in.ScaleUpStep, &out.ScaleUpStep
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.WarmUp != nil {
+		in, out := &in.WarmUp, &out.WarmUp
+		*out = new(CanaryWarmUp)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreconditionRefs != nil {
+		in, out := &in.PreconditionRefs, &out.PreconditionRefs
+		*out = make([]CanaryPreconditionRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.Prerequisites != nil {
+		in, out := &in.Prerequisites, &out.Prerequisites
+		*out = make([]CanaryPreconditionRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.Guards != nil {
+		in, out := &in.Guards, &out.Guards
+		*out = make([]StepGuard, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStrategy.
+func (in *CanaryStrategy) DeepCopy() *CanaryStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryStuckDeletion) DeepCopyInto(out *CanaryStuckDeletion) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStuckDeletion.
+func (in *CanaryStuckDeletion) DeepCopy() *CanaryStuckDeletion {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryStuckDeletion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryStuckDeletionStatus) DeepCopyInto(out *CanaryStuckDeletionStatus) {
+	*out = *in
+	out.CrossClusterObjectNameReference = in.CrossClusterObjectNameReference
+	if in.DeletionTime != nil {
+		in, out := &in.DeletionTime, &out.DeletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ActionTime != nil {
+		in, out := &in.ActionTime, &out.ActionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Finalizers != nil {
+		in, out := &in.Finalizers, &out.Finalizers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStuckDeletionStatus.
+func (in *CanaryStuckDeletionStatus) DeepCopy() *CanaryStuckDeletionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryStuckDeletionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryTargetState) DeepCopyInto(out *CanaryTargetState) {
+	*out = *in
+	out.CrossClusterObjectNameReference = in.CrossClusterObjectNameReference
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryTargetState.
+func (in *CanaryTargetState) DeepCopy() *CanaryTargetState {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryTargetState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryTrafficHooksStatus) DeepCopyInto(out *CanaryTrafficHooksStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryTrafficHooksStatus.
+func (in *CanaryTrafficHooksStatus) DeepCopy() *CanaryTrafficHooksStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryTrafficHooksStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryUpdateStrategy) DeepCopyInto(out *CanaryUpdateStrategy) {
+	*out = *in
+	if in.RollingUpdate != nil {
+		in, out := &in.RollingUpdate, &out.RollingUpdate
+		*out = new(CanaryRollingUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryUpdateStrategy.
+func (in *CanaryUpdateStrategy) DeepCopy() *CanaryUpdateStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryUpdateStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryVariant) DeepCopyInto(out *CanaryVariant) {
+	*out = *in
+	out.Replicas = in.Replicas
+	if in.PodTemplateMetadataPatch != nil {
+		in, out := &in.PodTemplateMetadataPatch, &out.PodTemplateMetadataPatch
+		*out = new(MetadataPatch)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryVariant.
+func (in *CanaryVariant) DeepCopy() *CanaryVariant {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryVariant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryVariantBackendRule) DeepCopyInto(out *CanaryVariantBackendRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryVariantBackendRule.
+func (in *CanaryVariantBackendRule) DeepCopy() *CanaryVariantBackendRule {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryVariantBackendRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryWarmUp) DeepCopyInto(out *CanaryWarmUp) {
+	*out = *in
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = new(CanaryWarmUpRequests)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(WebhookClientConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryWarmUp.
+func (in *CanaryWarmUp) DeepCopy() *CanaryWarmUp {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryWarmUp)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryWarmUpRequests) DeepCopyInto(out *CanaryWarmUpRequests) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryWarmUpRequests.
+func (in *CanaryWarmUpRequests) DeepCopy() *CanaryWarmUpRequests {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryWarmUpRequests)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryWarmUpStatus) DeepCopyInto(out *CanaryWarmUpStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryWarmUpStatus.
+func (in *CanaryWarmUpStatus) DeepCopy() *CanaryWarmUpStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryWarmUpStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CodeReasonMessage) DeepCopyInto(out *CodeReasonMessage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CodeReasonMessage.
+func (in *CodeReasonMessage) DeepCopy() *CodeReasonMessage {
+	if in == nil {
+		return nil
+	}
+	out := new(CodeReasonMessage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Condition) DeepCopyInto(out *Condition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Condition.
+func (in *Condition) DeepCopy() *Condition {
+	if in == nil {
+		return nil
+	}
+	out := new(Condition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrossClusterObjectNameReference) DeepCopyInto(out *CrossClusterObjectNameReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrossClusterObjectNameReference.
+func (in *CrossClusterObjectNameReference) DeepCopy() *CrossClusterObjectNameReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CrossClusterObjectNameReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrossClusterObjectReference) DeepCopyInto(out *CrossClusterObjectReference) {
+	*out = *in
+	out.ObjectTypeRef = in.ObjectTypeRef
+	out.CrossClusterObjectNameReference = in.CrossClusterObjectNameReference
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrossClusterObjectReference.
+func (in *CrossClusterObjectReference) DeepCopy() *CrossClusterObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CrossClusterObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GRPCRouteMatch) DeepCopyInto(out *GRPCRouteMatch) {
+	*out = *in
+	if in.Method != nil {
+		in, out := &in.Method, &out.Method
+		*out = new(v1alpha2.GRPCMethodMatch)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]v1alpha2.GRPCHeaderMatch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GRPCRouteMatch.
+func (in *GRPCRouteMatch) DeepCopy() *GRPCRouteMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(GRPCRouteMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GRPCRouteRule) DeepCopyInto(out *GRPCRouteRule) {
+	*out = *in
+	if in.Matches != nil {
+		in, out := &in.Matches, &out.Matches
+		*out = make([]GRPCRouteMatch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GRPCRouteRule.
+func (in *GRPCRouteRule) DeepCopy() *GRPCRouteRule {
+	if in == nil {
+		return nil
+	}
+	out := new(GRPCRouteRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuardMetric) DeepCopyInto(out *GuardMetric) {
+	*out = *in
+	out.Provider = in.Provider
+	if in.Min != nil {
+		in, out := &in.Min, &out.Min
+		*out = new(string)
+		**out = **in
+	}
+	if in.Max != nil {
+		in, out := &in.Max, &out.Max
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuardMetric.
+func (in *GuardMetric) DeepCopy() *GuardMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(GuardMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRouteFilter) DeepCopyInto(out *HTTPRouteFilter) {
+	*out = *in
+	if in.RequestHeaderModifier != nil {
+		in, out := &in.RequestHeaderModifier, &out.RequestHeaderModifier
+		*out = new(v1.HTTPHeaderFilter)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRouteFilter.
+func (in *HTTPRouteFilter) DeepCopy() *HTTPRouteFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRouteFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRouteMatch) DeepCopyInto(out *HTTPRouteMatch) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]v1.HTTPHeaderMatch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.QueryParams != nil {
+		in, out := &in.QueryParams, &out.QueryParams
+		*out = make([]v1.HTTPQueryParamMatch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRouteMatch.
+func (in *HTTPRouteMatch) DeepCopy() *HTTPRouteMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRouteMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRouteRule) DeepCopyInto(out *HTTPRouteRule) {
+	*out = *in
+	if in.Matches != nil {
+		in, out := &in.Matches, &out.Matches
+		*out = make([]HTTPRouteMatch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Filter.DeepCopyInto(&out.Filter)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRouteRule.
+func (in *HTTPRouteRule) DeepCopy() *HTTPRouteRule {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRouteRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePullCheckStatus) DeepCopyInto(out *ImagePullCheckStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePullCheckStatus.
+func (in *ImagePullCheckStatus) DeepCopy() *ImagePullCheckStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePullCheckStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataPatch) DeepCopyInto(out *MetadataPatch) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataPatch.
+func (in *MetadataPatch) DeepCopy() *MetadataPatch {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataPatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationStatus) DeepCopyInto(out *NotificationStatus) {
+	*out = *in
+	if in.Time != nil {
+		in, out := &in.Time, &out.Time
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationStatus.
+func (in *NotificationStatus) DeepCopy() *NotificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectTypeRef) DeepCopyInto(out *ObjectTypeRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectTypeRef.
+func (in *ObjectTypeRef) DeepCopy() *ObjectTypeRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectTypeRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PausedTrafficStatus) DeepCopyInto(out *PausedTrafficStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PausedTrafficStatus.
+func (in *PausedTrafficStatus) DeepCopy() *PausedTrafficStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PausedTrafficStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodPlacementPatch) DeepCopyInto(out *PodPlacementPatch) {
+	*out = *in
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodPlacementPatch.
+func (in *PodPlacementPatch) DeepCopy() *PodPlacementPatch {
+	if in == nil {
+		return nil
+	}
+	out := new(PodPlacementPatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProgressingInfo) DeepCopyInto(out *ProgressingInfo) {
+	*out = *in
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryProgressingInfo)
+		**out = **in
+	}
+	if in.Batch != nil {
+		in, out := &in.Batch, &out.Batch
+		*out = new(BatchProgressingInfo)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProgressingInfo.
+func (in *ProgressingInfo) DeepCopy() *ProgressingInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(ProgressingInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotionWindows) DeepCopyInto(out *PromotionWindows) {
+	*out = *in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]TimeWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotionWindows.
+func (in *PromotionWindows) DeepCopy() *PromotionWindows {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotionWindows)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecycleVerificationStatus) DeepCopyInto(out *RecycleVerificationStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecycleVerificationStatus.
+func (in *RecycleVerificationStatus) DeepCopy() *RecycleVerificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RecycleVerificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RejectedTargetStatus) DeepCopyInto(out *RejectedTargetStatus) {
+	*out = *in
+	out.CrossClusterObjectNameReference = in.CrossClusterObjectNameReference
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RejectedTargetStatus.
+func (in *RejectedTargetStatus) DeepCopy() *RejectedTargetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RejectedTargetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaCouplingStatus) DeepCopyInto(out *ReplicaCouplingStatus) {
+	*out = *in
+	out.CrossClusterObjectNameReference = in.CrossClusterObjectNameReference
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaCouplingStatus.
+func (in *ReplicaCouplingStatus) DeepCopy() *ReplicaCouplingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaCouplingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceMatch) DeepCopyInto(out *ResourceMatch) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]CrossClusterObjectNameReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceMatch.
+func (in *ResourceMatch) DeepCopy() *ResourceMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RevertRampStatus) DeepCopyInto(out *RevertRampStatus) {
+	*out = *in
+	if in.LastStepTime != nil {
+		in, out := &in.LastStepTime, &out.LastStepTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevertRampStatus.
+func (in *RevertRampStatus) DeepCopy() *RevertRampStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RevertRampStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Rollout) DeepCopyInto(out *Rollout) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Rollout.
+func (in *Rollout) DeepCopy() *Rollout {
+	if in == nil {
+		return nil
+	}
+	out := new(Rollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Rollout) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutBatchStatus) DeepCopyInto(out *RolloutBatchStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutBatchStatus.
+func (in *RolloutBatchStatus) DeepCopy() *RolloutBatchStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutBatchStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutList) DeepCopyInto(out *RolloutList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Rollout, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutList.
+func (in *RolloutList) DeepCopy() *RolloutList {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RolloutList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutReplicasSummary) DeepCopyInto(out *RolloutReplicasSummary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutReplicasSummary.
+func (in *RolloutReplicasSummary) DeepCopy() *RolloutReplicasSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutReplicasSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutRun) DeepCopyInto(out *RolloutRun) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutRun.
+func (in *RolloutRun) DeepCopy() *RolloutRun {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RolloutRun) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutRunBatchStatus) DeepCopyInto(out *RolloutRunBatchStatus) {
+	*out = *in
+	out.RolloutBatchStatus = in.RolloutBatchStatus
+	if in.Records != nil {
+		in, out := &in.Records, &out.Records
+		*out = make([]RolloutRunStepStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutRunBatchStatus.
+func (in *RolloutRunBatchStatus) DeepCopy() *RolloutRunBatchStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutRunBatchStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutRunBatchStrategy) DeepCopyInto(out *RolloutRunBatchStrategy) {
+	*out = *in
+	if in.Batches != nil {
+		in, out := &in.Batches, &out.Batches
+		*out = make([]RolloutRunStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Toleration != nil {
+		in, out := &in.Toleration, &out.Toleration
+		*out = new(TolerationStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutRunBatchStrategy.
+func (in *RolloutRunBatchStrategy) DeepCopy() *RolloutRunBatchStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutRunBatchStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutRunCanaryStrategy) DeepCopyInto(out *RolloutRunCanaryStrategy) {
+	*out = *in
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]RolloutRunStepTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Traffic != nil {
+		in, out := &in.Traffic, &out.Traffic
+		*out = new(TrafficStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Variants != nil {
+		in, out := &in.Variants, &out.Variants
+		*out = make([]CanaryVariant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PodTemplateMetadataPatch != nil {
+		in, out := &in.PodTemplateMetadataPatch, &out.PodTemplateMetadataPatch
+		*out = new(MetadataPatch)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ObjectMetadataPatch != nil {
+		in, out := &in.ObjectMetadataPatch, &out.ObjectMetadataPatch
+		*out = new(MetadataPatch)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodPlacementPatch != nil {
+		in, out := &in.PodPlacementPatch, &out.PodPlacementPatch
+		*out = new(PodPlacementPatch)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PromotionWindows != nil {
+		in, out := &in.PromotionWindows, &out.PromotionWindows
+		*out = new(PromotionWindows)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Analysis != nil {
+		in, out := &in.Analysis, &out.Analysis
+		*out = new(CanaryAnalysis)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PauseAfter != nil {
+		in, out := &in.PauseAfter, &out.PauseAfter
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PauseBefore != nil {
+		in, out := &in.PauseBefore, &out.PauseBefore
+		*out = make([]CanaryPausePhase, len(*in))
+		copy(*out, *in)
+	}
+	if in.RecycleOrder != nil {
+		in, out := &in.RecycleOrder, &out.RecycleOrder
+		*out = make([]CanaryRecycleOperation, len(*in))
+		copy(*out, *in)
+	}
+	if in.RetentionSeconds != nil {
+		in, out := &in.RetentionSeconds, &out.RetentionSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CrashLoopCheck != nil {
+		in, out := &in.CrashLoopCheck, &out.CrashLoopCheck
+		*out = new(CanaryCrashLoopCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImagePullCheck != nil {
+		in, out := &in.ImagePullCheck, &out.ImagePullCheck
+		*out = new(CanaryImagePullCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthCheckGracePeriodSeconds != nil {
+		in, out := &in.HealthCheckGracePeriodSeconds, &out.HealthCheckGracePeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ReadinessStabilization != nil {
+		in, out := &in.ReadinessStabilization, &out.ReadinessStabilization
+		*out = new(CanaryReadinessStabilization)
+		**out = **in
+	}
+	if in.DegradedClusterGracePeriodSeconds != nil {
+		in, out := &in.DegradedClusterGracePeriodSeconds, &out.DegradedClusterGracePeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxActiveDuration != nil {
+		in, out := &in.MaxActiveDuration, &out.MaxActiveDuration
+		*out = new(CanaryMaxActiveDuration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = make([]CanaryNotification, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Bake != nil {
+		in, out := &in.Bake, &out.Bake
+		*out = new(CanaryBake)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StableMinAvailable != nil {
+		in, out := &in.StableMinAvailable, &out.StableMinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.StuckDeletion != nil {
+		in, out := &in.StuckDeletion, &out.StuckDeletion
+		*out = new(CanaryStuckDeletion)
+		**out = **in
+	}
+	if in.DriftDetection != nil {
+		in, out := &in.DriftDetection, &out.DriftDetection
+		*out = new(CanaryDriftDetection)
+		**out = **in
+	}
+	if in.AutoRollback != nil {
+		in, out := &in.AutoRollback, &out.AutoRollback
+		*out = new(CanaryAutoRollback)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Ordinals != nil {
+		in, out := &in.Ordinals, &out.Ordinals
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(CanaryUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScaleUpStep != nil {
+		in, out := &in.ScaleUpStep, &out.ScaleUpStep
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.WarmUp != nil {
+		in, out := &in.WarmUp, &out.WarmUp
+		*out = new(CanaryWarmUp)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreconditionRefs != nil {
+		in, out := &in.PreconditionRefs, &out.PreconditionRefs
+		*out = make([]CanaryPreconditionRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.Prerequisites != nil {
+		in, out := &in.Prerequisites, &out.Prerequisites
+		*out = make([]CanaryPreconditionRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.Guards != nil {
+		in, out := &in.Guards, &out.Guards
+		*out = make([]StepGuard, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.States != nil {
+		in, out := &in.States, &out.States
+		*out = make([]RolloutStepState, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutRunCanaryStrategy.
+func (in *RolloutRunCanaryStrategy) DeepCopy() *RolloutRunCanaryStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutRunCanaryStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutRunCancellationStatus) DeepCopyInto(out *RolloutRunCancellationStatus) {
+	*out = *in
+	if in.RequestTime != nil {
+		in, out := &in.RequestTime, &out.RequestTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutRunCancellationStatus.
+func (in *RolloutRunCancellationStatus) DeepCopy() *RolloutRunCancellationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutRunCancellationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutRunList) DeepCopyInto(out *RolloutRunList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RolloutRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutRunList.
+func (in *RolloutRunList) DeepCopy() *RolloutRunList {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutRunList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RolloutRunList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutRunSpec) DeepCopyInto(out *RolloutRunSpec) {
+	*out = *in
+	out.TargetType = in.TargetType
+	if in.Webhooks != nil {
+		in, out := &in.Webhooks, &out.Webhooks
+		*out = make([]RolloutWebhook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TrafficTopologyRefs != nil {
+		in, out := &in.TrafficTopologyRefs, &out.TrafficTopologyRefs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(RolloutRunCanaryStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Batch != nil {
+		in, out := &in.Batch, &out.Batch
+		*out = new(RolloutRunBatchStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutRunSpec.
+func (in *RolloutRunSpec) DeepCopy() *RolloutRunSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutRunSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutRunStatus) DeepCopyInto(out *RolloutRunStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Error != nil {
+		in, out := &in.Error, &out.Error
+		*out = new(CodeReasonMessage)
+		**out = **in
+	}
+	if in.CanaryStatus != nil {
+		in, out := &in.CanaryStatus, &out.CanaryStatus
+		*out = new(RolloutRunStepStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BatchStatus != nil {
+		in, out := &in.BatchStatus, &out.BatchStatus
+		*out = new(RolloutRunBatchStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TargetStatuses != nil {
+		in, out := &in.TargetStatuses, &out.TargetStatuses
+		*out = make([]RolloutWorkloadStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResolvedParameters != nil {
+		in, out := &in.ResolvedParameters, &out.ResolvedParameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.RejectedTargets != nil {
+		in, out := &in.RejectedTargets, &out.RejectedTargets
+		*out = make([]RejectedTargetStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Cancellation != nil {
+		in, out := &in.Cancellation, &out.Cancellation
+		*out = new(RolloutRunCancellationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutRunStatus.
+func (in *RolloutRunStatus) DeepCopy() *RolloutRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutRunStep) DeepCopyInto(out *RolloutRunStep) {
+	*out = *in
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]RolloutRunStepTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Traffic != nil {
+		in, out := &in.Traffic, &out.Traffic
+		*out = new(TrafficStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Guards != nil {
+		in, out := &in.Guards, &out.Guards
+		*out = make([]StepGuard, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutRunStep.
+func (in *RolloutRunStep) DeepCopy() *RolloutRunStep {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutRunStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutRunStepStatus) DeepCopyInto(out *RolloutRunStepStatus) {
+	*out = *in
+	if in.Index != nil {
+		in, out := &in.Index, &out.Index
+		*out = new(int32)
+		**out = **in
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.FinishTime != nil {
+		in, out := &in.FinishTime, &out.FinishTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]RolloutWorkloadStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Webhooks != nil {
+		in, out := &in.Webhooks, &out.Webhooks
+		*out = make([]RolloutWebhookStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SessionDrain != nil {
+		in, out := &in.SessionDrain, &out.SessionDrain
+		*out = new(SessionDrainStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(CanaryRetentionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hold != nil {
+		in, out := &in.Hold, &out.Hold
+		*out = new(CanaryHoldStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FastPromotion != nil {
+		in, out := &in.FastPromotion, &out.FastPromotion
+		*out = new(CanaryFastPromotionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GuardViolation != nil {
+		in, out := &in.GuardViolation, &out.GuardViolation
+		*out = new(StepGuardViolation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImagePullCheck != nil {
+		in, out := &in.ImagePullCheck, &out.ImagePullCheck
+		*out = new(ImagePullCheckStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TrafficProbe != nil {
+		in, out := &in.TrafficProbe, &out.TrafficProbe
+		*out = new(TrafficProbeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TargetReadiness != nil {
+		in, out := &in.TargetReadiness, &out.TargetReadiness
+		*out = make([]TargetReadinessStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TargetStates != nil {
+		in, out := &in.TargetStates, &out.TargetStates
+		*out = make([]CanaryTargetState, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ReadinessStabilization != nil {
+		in, out := &in.ReadinessStabilization, &out.ReadinessStabilization
+		*out = new(CanaryReadinessStabilizationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthCheckStartTime != nil {
+		in, out := &in.HealthCheckStartTime, &out.HealthCheckStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PodFailures != nil {
+		in, out := &in.PodFailures, &out.PodFailures
+		*out = make([]CanaryPodFailure, len(*in))
+		copy(*out, *in)
+	}
+	if in.RevertRamp != nil {
+		in, out := &in.RevertRamp, &out.RevertRamp
+		*out = new(RevertRampStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PausedTraffic != nil {
+		in, out := &in.PausedTraffic, &out.PausedTraffic
+		*out = new(PausedTrafficStatus)
+		**out = **in
+	}
+	if in.ReplicaCoupling != nil {
+		in, out := &in.ReplicaCoupling, &out.ReplicaCoupling
+		*out = make([]ReplicaCouplingStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.UnreachableClusters != nil {
+		in, out := &in.UnreachableClusters, &out.UnreachableClusters
+		*out = make([]UnreachableClusterStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ActiveDeadline != nil {
+		in, out := &in.ActiveDeadline, &out.ActiveDeadline
+		*out = new(CanaryActiveDeadlineStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PauseCheckpoints != nil {
+		in, out := &in.PauseCheckpoints, &out.PauseCheckpoints
+		*out = make([]CanaryPauseCheckpoint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CanaryReplicas != nil {
+		in, out := &in.CanaryReplicas, &out.CanaryReplicas
+		*out = new(CanaryReplicasStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = make([]NotificationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RecycleVerification != nil {
+		in, out := &in.RecycleVerification, &out.RecycleVerification
+		*out = new(RecycleVerificationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Bake != nil {
+		in, out := &in.Bake, &out.Bake
+		*out = new(CanaryBakeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StuckDeletions != nil {
+		in, out := &in.StuckDeletions, &out.StuckDeletions
+		*out = make([]CanaryStuckDeletionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(CanaryDriftStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollbacks != nil {
+		in, out := &in.Rollbacks, &out.Rollbacks
+		*out = new(CanaryRollbackStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceFootprint != nil {
+		in, out := &in.ResourceFootprint, &out.ResourceFootprint
+		*out = new(CanaryResourceFootprint)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TrafficVerifications != nil {
+		in, out := &in.TrafficVerifications, &out.TrafficVerifications
+		*out = make([]TrafficVerificationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ScaleUp != nil {
+		in, out := &in.ScaleUp, &out.ScaleUp
+		*out = make([]CanaryScaleUpStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.WarmUp != nil {
+		in, out := &in.WarmUp, &out.WarmUp
+		*out = new(CanaryWarmUpStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Preconditions != nil {
+		in, out := &in.Preconditions, &out.Preconditions
+		*out = new(CanaryPreconditionsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Prerequisites != nil {
+		in, out := &in.Prerequisites, &out.Prerequisites
+		*out = new(CanaryPrerequisitesStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TrafficHooks != nil {
+		in, out := &in.TrafficHooks, &out.TrafficHooks
+		*out = new(CanaryTrafficHooksStatus)
+		**out = **in
+	}
+	if in.TrafficUndoLog != nil {
+		in, out := &in.TrafficUndoLog, &out.TrafficUndoLog
+		*out = make([]TrafficUndoEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TrafficCeiling != nil {
+		in, out := &in.TrafficCeiling, &out.TrafficCeiling
+		*out = new(TrafficCeilingStatus)
+		**out = **in
+	}
+	if in.TrafficSplit != nil {
+		in, out := &in.TrafficSplit, &out.TrafficSplit
+		*out = new(TrafficSplitStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Completion != nil {
+		in, out := &in.Completion, &out.Completion
+		*out = new(CanaryCompletionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Phases != nil {
+		in, out := &in.Phases, &out.Phases
+		*out = make(map[string]CanaryPhaseResult, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutRunStepStatus.
+func (in *RolloutRunStepStatus) DeepCopy() *RolloutRunStepStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutRunStepStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutRunStepTarget) DeepCopyInto(out *RolloutRunStepTarget) {
+	*out = *in
+	out.CrossClusterObjectNameReference = in.CrossClusterObjectNameReference
+	out.Replicas = in.Replicas
+	if in.ReadinessTimeoutSeconds != nil {
+		in, out := &in.ReadinessTimeoutSeconds, &out.ReadinessTimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ConfigOverrides != nil {
+		in, out := &in.ConfigOverrides, &out.ConfigOverrides
+		*out = make([]CanaryConfigOverride, len(*in))
+		copy(*out, *in)
+	}
+	if in.InitContainerOverrides != nil {
+		in, out := &in.InitContainerOverrides, &out.InitContainerOverrides
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.OwnerReferences != nil {
+		in, out := &in.OwnerReferences, &out.OwnerReferences
+		*out = make([]metav1.OwnerReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(CanaryPodDisruptionBudget)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutRunStepTarget.
+func (in *RolloutRunStepTarget) DeepCopy() *RolloutRunStepTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutRunStepTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutSpec) DeepCopyInto(out *RolloutSpec) {
+	*out = *in
+	if in.HistoryLimit != nil {
 		in, out := &in.HistoryLimit, &out.HistoryLimit
 		*out = new(int32)
 		**out = **in
@@ -1011,54 +2762,258 @@ func (in *RolloutSpec) DeepCopyInto(out *RolloutSpec) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutSpec.
-func (in *RolloutSpec) DeepCopy() *RolloutSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutSpec.
+func (in *RolloutSpec) DeepCopy() *RolloutSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStatus.
+func (in *RolloutStatus) DeepCopy() *RolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStep) DeepCopyInto(out *RolloutStep) {
+	*out = *in
+	out.Replicas = in.Replicas
+	if in.Traffic != nil {
+		in, out := &in.Traffic, &out.Traffic
+		*out = new(TrafficStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Match != nil {
+		in, out := &in.Match, &out.Match
+		*out = new(ResourceMatch)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Guards != nil {
+		in, out := &in.Guards, &out.Guards
+		*out = make([]StepGuard, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStep.
+func (in *RolloutStep) DeepCopy() *RolloutStep {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStrategy) DeepCopyInto(out *RolloutStrategy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Batch != nil {
+		in, out := &in.Batch, &out.Batch
+		*out = new(BatchStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Webhooks != nil {
+		in, out := &in.Webhooks, &out.Webhooks
+		*out = make([]RolloutWebhook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStrategy.
+func (in *RolloutStrategy) DeepCopy() *RolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RolloutStrategy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStrategyList) DeepCopyInto(out *RolloutStrategyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RolloutStrategy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStrategyList.
+func (in *RolloutStrategyList) DeepCopy() *RolloutStrategyList {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStrategyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RolloutStrategyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutWebhook) DeepCopyInto(out *RolloutWebhook) {
+	*out = *in
+	if in.HookTypes != nil {
+		in, out := &in.HookTypes, &out.HookTypes
+		*out = make([]HookType, len(*in))
+		copy(*out, *in)
+	}
+	in.ClientConfig.DeepCopyInto(&out.ClientConfig)
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Provider != nil {
+		in, out := &in.Provider, &out.Provider
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutWebhook.
+func (in *RolloutWebhook) DeepCopy() *RolloutWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutWebhookReview) DeepCopyInto(out *RolloutWebhookReview) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutWebhookReview.
+func (in *RolloutWebhookReview) DeepCopy() *RolloutWebhookReview {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutSpec)
+	out := new(RolloutWebhookReview)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RolloutWebhookReview) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
+func (in *RolloutWebhookReviewBatch) DeepCopyInto(out *RolloutWebhookReviewBatch) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]Condition, len(*in))
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]RolloutRunStepTarget, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.LastUpdateTime != nil {
-		in, out := &in.LastUpdateTime, &out.LastUpdateTime
-		*out = (*in).DeepCopy()
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStatus.
-func (in *RolloutStatus) DeepCopy() *RolloutStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutWebhookReviewBatch.
+func (in *RolloutWebhookReviewBatch) DeepCopy() *RolloutWebhookReviewBatch {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutStatus)
+	out := new(RolloutWebhookReviewBatch)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutStep) DeepCopyInto(out *RolloutStep) {
+func (in *RolloutWebhookReviewCanary) DeepCopyInto(out *RolloutWebhookReviewCanary) {
 	*out = *in
-	out.Replicas = in.Replicas
-	if in.Traffic != nil {
-		in, out := &in.Traffic, &out.Traffic
-		*out = new(TrafficStrategy)
-		(*in).DeepCopyInto(*out)
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]RolloutRunStepTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.Match != nil {
-		in, out := &in.Match, &out.Match
-		*out = new(ResourceMatch)
+	if in.Completion != nil {
+		in, out := &in.Completion, &out.Completion
+		*out = new(CanaryCompletionStatus)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.Properties != nil {
@@ -1070,362 +3025,468 @@ func (in *RolloutStep) DeepCopyInto(out *RolloutStep) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStep.
-func (in *RolloutStep) DeepCopy() *RolloutStep {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutWebhookReviewCanary.
+func (in *RolloutWebhookReviewCanary) DeepCopy() *RolloutWebhookReviewCanary {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutStep)
+	out := new(RolloutWebhookReviewCanary)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutStrategy) DeepCopyInto(out *RolloutStrategy) {
+func (in *RolloutWebhookReviewSpec) DeepCopyInto(out *RolloutWebhookReviewSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.TargetType = in.TargetType
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.Canary != nil {
 		in, out := &in.Canary, &out.Canary
-		*out = new(CanaryStrategy)
+		*out = new(RolloutWebhookReviewCanary)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.Batch != nil {
 		in, out := &in.Batch, &out.Batch
-		*out = new(BatchStrategy)
+		*out = new(RolloutWebhookReviewBatch)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Webhooks != nil {
-		in, out := &in.Webhooks, &out.Webhooks
-		*out = make([]RolloutWebhook, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStrategy.
-func (in *RolloutStrategy) DeepCopy() *RolloutStrategy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutWebhookReviewSpec.
+func (in *RolloutWebhookReviewSpec) DeepCopy() *RolloutWebhookReviewSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutStrategy)
+	out := new(RolloutWebhookReviewSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RolloutStrategy) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutWebhookReviewStatus) DeepCopyInto(out *RolloutWebhookReviewStatus) {
+	*out = *in
+	out.CodeReasonMessage = in.CodeReasonMessage
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutWebhookReviewStatus.
+func (in *RolloutWebhookReviewStatus) DeepCopy() *RolloutWebhookReviewStatus {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(RolloutWebhookReviewStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutStrategyList) DeepCopyInto(out *RolloutStrategyList) {
+func (in *RolloutWebhookStatus) DeepCopyInto(out *RolloutWebhookStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]RolloutStrategy, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+	out.CodeReasonMessage = in.CodeReasonMessage
+	if in.TargetVerdicts != nil {
+		in, out := &in.TargetVerdicts, &out.TargetVerdicts
+		*out = make(map[string]WebhookTargetVerdict, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStrategyList.
-func (in *RolloutStrategyList) DeepCopy() *RolloutStrategyList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutWebhookStatus.
+func (in *RolloutWebhookStatus) DeepCopy() *RolloutWebhookStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutStrategyList)
+	out := new(RolloutWebhookStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RolloutStrategyList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutWorkloadStatus) DeepCopyInto(out *RolloutWorkloadStatus) {
+	*out = *in
+	out.RolloutReplicasSummary = in.RolloutReplicasSummary
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutWorkloadStatus.
+func (in *RolloutWorkloadStatus) DeepCopy() *RolloutWorkloadStatus {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(RolloutWorkloadStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutWebhook) DeepCopyInto(out *RolloutWebhook) {
+func (in *RouteRef) DeepCopyInto(out *RouteRef) {
 	*out = *in
-	if in.HookTypes != nil {
-		in, out := &in.HookTypes, &out.HookTypes
-		*out = make([]HookType, len(*in))
-		copy(*out, *in)
-	}
-	in.ClientConfig.DeepCopyInto(&out.ClientConfig)
-	if in.Properties != nil {
-		in, out := &in.Properties, &out.Properties
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.APIVersion != nil {
+		in, out := &in.APIVersion, &out.APIVersion
+		*out = new(string)
+		**out = **in
 	}
-	if in.Provider != nil {
-		in, out := &in.Provider, &out.Provider
+	if in.Kind != nil {
+		in, out := &in.Kind, &out.Kind
 		*out = new(string)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutWebhook.
-func (in *RolloutWebhook) DeepCopy() *RolloutWebhook {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteRef.
+func (in *RouteRef) DeepCopy() *RouteRef {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionDrainStatus) DeepCopyInto(out *SessionDrainStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionDrainStatus.
+func (in *SessionDrainStatus) DeepCopy() *SessionDrainStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionDrainStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionDrainStrategy) DeepCopyInto(out *SessionDrainStrategy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionDrainStrategy.
+func (in *SessionDrainStrategy) DeepCopy() *SessionDrainStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionDrainStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StableBackendRule) DeepCopyInto(out *StableBackendRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StableBackendRule.
+func (in *StableBackendRule) DeepCopy() *StableBackendRule {
+	if in == nil {
+		return nil
+	}
+	out := new(StableBackendRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepGuard) DeepCopyInto(out *StepGuard) {
+	*out = *in
+	if in.Metric != nil {
+		in, out := &in.Metric, &out.Metric
+		*out = new(GuardMetric)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(WebhookClientConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepGuard.
+func (in *StepGuard) DeepCopy() *StepGuard {
+	if in == nil {
+		return nil
+	}
+	out := new(StepGuard)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepGuardViolation) DeepCopyInto(out *StepGuardViolation) {
+	*out = *in
+	if in.Time != nil {
+		in, out := &in.Time, &out.Time
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepGuardViolation.
+func (in *StepGuardViolation) DeepCopy() *StepGuardViolation {
+	if in == nil {
+		return nil
+	}
+	out := new(StepGuardViolation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetReadinessStatus) DeepCopyInto(out *TargetReadinessStatus) {
+	*out = *in
+	out.CrossClusterObjectNameReference = in.CrossClusterObjectNameReference
+	if in.WaitStartTime != nil {
+		in, out := &in.WaitStartTime, &out.WaitStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Deadline != nil {
+		in, out := &in.Deadline, &out.Deadline
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetReadinessStatus.
+func (in *TargetReadinessStatus) DeepCopy() *TargetReadinessStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutWebhook)
+	out := new(TargetReadinessStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutWebhookReview) DeepCopyInto(out *RolloutWebhookReview) {
+func (in *TimeWindow) DeepCopyInto(out *TimeWindow) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]Weekday, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutWebhookReview.
-func (in *RolloutWebhookReview) DeepCopy() *RolloutWebhookReview {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeWindow.
+func (in *TimeWindow) DeepCopy() *TimeWindow {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutWebhookReview)
+	out := new(TimeWindow)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RolloutWebhookReview) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TolerationStrategy) DeepCopyInto(out *TolerationStrategy) {
+	*out = *in
+	if in.WorkloadFailureThreshold != nil {
+		in, out := &in.WorkloadFailureThreshold, &out.WorkloadFailureThreshold
+		*out = new(intstr.IntOrString)
+		**out = **in
 	}
-	return nil
+	if in.TaskFailureThreshold != nil {
+		in, out := &in.TaskFailureThreshold, &out.TaskFailureThreshold
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TolerationStrategy.
+func (in *TolerationStrategy) DeepCopy() *TolerationStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(TolerationStrategy)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutWebhookReviewBatch) DeepCopyInto(out *RolloutWebhookReviewBatch) {
+func (in *TopologyInfo) DeepCopyInto(out *TopologyInfo) {
 	*out = *in
-	if in.Targets != nil {
-		in, out := &in.Targets, &out.Targets
-		*out = make([]RolloutRunStepTarget, len(*in))
-		copy(*out, *in)
-	}
-	if in.Properties != nil {
-		in, out := &in.Properties, &out.Properties
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
+	out.WorkloadRef = in.WorkloadRef
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutWebhookReviewBatch.
-func (in *RolloutWebhookReviewBatch) DeepCopy() *RolloutWebhookReviewBatch {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyInfo.
+func (in *TopologyInfo) DeepCopy() *TopologyInfo {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutWebhookReviewBatch)
+	out := new(TopologyInfo)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutWebhookReviewCanary) DeepCopyInto(out *RolloutWebhookReviewCanary) {
+func (in *TrafficAllocation) DeepCopyInto(out *TrafficAllocation) {
 	*out = *in
-	if in.Targets != nil {
-		in, out := &in.Targets, &out.Targets
-		*out = make([]RolloutRunStepTarget, len(*in))
+	if in.Variants != nil {
+		in, out := &in.Variants, &out.Variants
+		*out = make([]TrafficVariant, len(*in))
 		copy(*out, *in)
 	}
-	if in.Properties != nil {
-		in, out := &in.Properties, &out.Properties
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutWebhookReviewCanary.
-func (in *RolloutWebhookReviewCanary) DeepCopy() *RolloutWebhookReviewCanary {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficAllocation.
+func (in *TrafficAllocation) DeepCopy() *TrafficAllocation {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutWebhookReviewCanary)
+	out := new(TrafficAllocation)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutWebhookReviewSpec) DeepCopyInto(out *RolloutWebhookReviewSpec) {
+func (in *TrafficCeilingStatus) DeepCopyInto(out *TrafficCeilingStatus) {
 	*out = *in
-	out.TargetType = in.TargetType
-	if in.Properties != nil {
-		in, out := &in.Properties, &out.Properties
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.Canary != nil {
-		in, out := &in.Canary, &out.Canary
-		*out = new(RolloutWebhookReviewCanary)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Batch != nil {
-		in, out := &in.Batch, &out.Batch
-		*out = new(RolloutWebhookReviewBatch)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutWebhookReviewSpec.
-func (in *RolloutWebhookReviewSpec) DeepCopy() *RolloutWebhookReviewSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficCeilingStatus.
+func (in *TrafficCeilingStatus) DeepCopy() *TrafficCeilingStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutWebhookReviewSpec)
+	out := new(TrafficCeilingStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutWebhookReviewStatus) DeepCopyInto(out *RolloutWebhookReviewStatus) {
+func (in *TrafficPausePolicy) DeepCopyInto(out *TrafficPausePolicy) {
 	*out = *in
-	out.CodeReasonMessage = in.CodeReasonMessage
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutWebhookReviewStatus.
-func (in *RolloutWebhookReviewStatus) DeepCopy() *RolloutWebhookReviewStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficPausePolicy.
+func (in *TrafficPausePolicy) DeepCopy() *TrafficPausePolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutWebhookReviewStatus)
+	out := new(TrafficPausePolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutWebhookStatus) DeepCopyInto(out *RolloutWebhookStatus) {
+func (in *TrafficProbeStatus) DeepCopyInto(out *TrafficProbeStatus) {
 	*out = *in
-	out.CodeReasonMessage = in.CodeReasonMessage
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutWebhookStatus.
-func (in *RolloutWebhookStatus) DeepCopy() *RolloutWebhookStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficProbeStatus.
+func (in *TrafficProbeStatus) DeepCopy() *TrafficProbeStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutWebhookStatus)
+	out := new(TrafficProbeStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RolloutWorkloadStatus) DeepCopyInto(out *RolloutWorkloadStatus) {
+func (in *TrafficPropagationCheck) DeepCopyInto(out *TrafficPropagationCheck) {
 	*out = *in
-	out.RolloutReplicasSummary = in.RolloutReplicasSummary
+	out.ObjectTypeRef = in.ObjectTypeRef
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutWorkloadStatus.
-func (in *RolloutWorkloadStatus) DeepCopy() *RolloutWorkloadStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficPropagationCheck.
+func (in *TrafficPropagationCheck) DeepCopy() *TrafficPropagationCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(RolloutWorkloadStatus)
+	out := new(TrafficPropagationCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RouteRef) DeepCopyInto(out *RouteRef) {
+func (in *TrafficRevertRamp) DeepCopyInto(out *TrafficRevertRamp) {
 	*out = *in
-	if in.APIVersion != nil {
-		in, out := &in.APIVersion, &out.APIVersion
-		*out = new(string)
-		**out = **in
-	}
-	if in.Kind != nil {
-		in, out := &in.Kind, &out.Kind
-		*out = new(string)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteRef.
-func (in *RouteRef) DeepCopy() *RouteRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficRevertRamp.
+func (in *TrafficRevertRamp) DeepCopy() *TrafficRevertRamp {
 	if in == nil {
 		return nil
 	}
-	out := new(RouteRef)
+	out := new(TrafficRevertRamp)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StableBackendRule) DeepCopyInto(out *StableBackendRule) {
+func (in *TrafficSessionAffinity) DeepCopyInto(out *TrafficSessionAffinity) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StableBackendRule.
-func (in *StableBackendRule) DeepCopy() *StableBackendRule {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficSessionAffinity.
+func (in *TrafficSessionAffinity) DeepCopy() *TrafficSessionAffinity {
 	if in == nil {
 		return nil
 	}
-	out := new(StableBackendRule)
+	out := new(TrafficSessionAffinity)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TolerationStrategy) DeepCopyInto(out *TolerationStrategy) {
+func (in *TrafficSplitProbe) DeepCopyInto(out *TrafficSplitProbe) {
 	*out = *in
-	if in.WorkloadFailureThreshold != nil {
-		in, out := &in.WorkloadFailureThreshold, &out.WorkloadFailureThreshold
-		*out = new(intstr.IntOrString)
-		**out = **in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
-	if in.TaskFailureThreshold != nil {
-		in, out := &in.TaskFailureThreshold, &out.TaskFailureThreshold
-		*out = new(intstr.IntOrString)
+	if in.TolerancePercent != nil {
+		in, out := &in.TolerancePercent, &out.TolerancePercent
+		*out = new(int32)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TolerationStrategy.
-func (in *TolerationStrategy) DeepCopy() *TolerationStrategy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficSplitProbe.
+func (in *TrafficSplitProbe) DeepCopy() *TrafficSplitProbe {
 	if in == nil {
 		return nil
 	}
-	out := new(TolerationStrategy)
+	out := new(TrafficSplitProbe)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TopologyInfo) DeepCopyInto(out *TopologyInfo) {
+func (in *TrafficSplitStatus) DeepCopyInto(out *TrafficSplitStatus) {
 	*out = *in
-	out.WorkloadRef = in.WorkloadRef
+	if in.LastProbeTime != nil {
+		in, out := &in.LastProbeTime, &out.LastProbeTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyInfo.
-func (in *TopologyInfo) DeepCopy() *TopologyInfo {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficSplitStatus.
+func (in *TrafficSplitStatus) DeepCopy() *TrafficSplitStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(TopologyInfo)
+	out := new(TrafficSplitStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1443,6 +3504,56 @@ func (in *TrafficStrategy) DeepCopyInto(out *TrafficStrategy) {
 		*out = new(HTTPRouteRule)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GRPCRule != nil {
+		in, out := &in.GRPCRule, &out.GRPCRule
+		*out = new(GRPCRouteRule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SessionDrain != nil {
+		in, out := &in.SessionDrain, &out.SessionDrain
+		*out = new(SessionDrainStrategy)
+		**out = **in
+	}
+	if in.VerifyProbe != nil {
+		in, out := &in.VerifyProbe, &out.VerifyProbe
+		*out = new(TrafficVerifyProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SplitProbe != nil {
+		in, out := &in.SplitProbe, &out.SplitProbe
+		*out = new(TrafficSplitProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PropagationChecks != nil {
+		in, out := &in.PropagationChecks, &out.PropagationChecks
+		*out = make([]TrafficPropagationCheck, len(*in))
+		copy(*out, *in)
+	}
+	if in.RevertRamp != nil {
+		in, out := &in.RevertRamp, &out.RevertRamp
+		*out = new(TrafficRevertRamp)
+		**out = **in
+	}
+	if in.PausePolicy != nil {
+		in, out := &in.PausePolicy, &out.PausePolicy
+		*out = new(TrafficPausePolicy)
+		**out = **in
+	}
+	if in.SessionAffinity != nil {
+		in, out := &in.SessionAffinity, &out.SessionAffinity
+		*out = new(TrafficSessionAffinity)
+		**out = **in
+	}
+	if in.Allocation != nil {
+		in, out := &in.Allocation, &out.Allocation
+		*out = new(TrafficAllocation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficStrategy.
@@ -1565,6 +3676,107 @@ func (in *TrafficTopologyStatus) DeepCopy() *TrafficTopologyStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficUndoEntry) DeepCopyInto(out *TrafficUndoEntry) {
+	*out = *in
+	if in.Forwarding != nil {
+		in, out := &in.Forwarding, &out.Forwarding
+		*out = new(BackendForwarding)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficUndoEntry.
+func (in *TrafficUndoEntry) DeepCopy() *TrafficUndoEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficUndoEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficVariant) DeepCopyInto(out *TrafficVariant) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficVariant.
+func (in *TrafficVariant) DeepCopy() *TrafficVariant {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficVariant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficVerificationStatus) DeepCopyInto(out *TrafficVerificationStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficVerificationStatus.
+func (in *TrafficVerificationStatus) DeepCopy() *TrafficVerificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficVerificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficVerifyProbe) DeepCopyInto(out *TrafficVerifyProbe) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExpectedStatus != nil {
+		in, out := &in.ExpectedStatus, &out.ExpectedStatus
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficVerifyProbe.
+func (in *TrafficVerifyProbe) DeepCopy() *TrafficVerifyProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficVerifyProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnreachableClusterStatus) DeepCopyInto(out *UnreachableClusterStatus) {
+	*out = *in
+	if in.Since != nil {
+		in, out := &in.Since, &out.Since
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnreachableClusterStatus.
+func (in *UnreachableClusterStatus) DeepCopy() *UnreachableClusterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UnreachableClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WebhookClientConfig) DeepCopyInto(out *WebhookClientConfig) {
 	*out = *in
@@ -24,6 +24,28 @@ const (
 	AnnoManualCommandSkip     = "skip"
 	AnnoManualCommandPause    = "pause"
 	AnnoManualCommandCancel   = "cancel"
+	// AnnoManualCommandRestartStep restarts the failed step from its entry state
+	AnnoManualCommandRestartStep = "restart-step"
+	// AnnoManualCommandPromote ends the hold of canary and continues the rollout
+	AnnoManualCommandPromote = "promote"
+	// AnnoManualCommandEnd ends the hold of canary, recycles the canary and
+	// cancels the rolloutRun
+	AnnoManualCommandEnd = "end"
+	// AnnoManualCommandPromoteNow promotes the serving canary immediately,
+	// skipping the remaining soak, pauses and promotion windows. Canary is
+	// still recycled with its traffic reverted.
+	AnnoManualCommandPromoteNow = "promote-now"
+	// AnnoManualCommandResetRollbackBudget resets the rollback budget of canary
+	// autoRollback, the failed canary paused by the exhausted budget is rolled
+	// back and retried again
+	AnnoManualCommandResetRollbackBudget = "reset-rollback-budget"
+	// AnnoManualCommandEndRetention ends the retention window of canary in
+	// recycle, the retained canary resources are deleted at once
+	AnnoManualCommandEndRetention = "end-retention"
+	// AnnoManualCommandBy is set with the manual command by the client issuing it,
+	// it records who issued the command in audit records. It is also read along
+	// with spec.cancel of RolloutRun to record who requested the cancellation.
+	AnnoManualCommandBy = "rollout.kusionstack.io/manual-command-by"
 
 	AnnoRolloutTrigger = "rollout.kusionstack.io/trigger"
 
@@ -33,4 +55,29 @@ const (
 
 	// AnnoPodRolloutProgressingInfos contains a slice of progressing infos on a pod.
 	AnnoPodRolloutProgressingInfos = "rollout.kusionstack.io/pod-progressing-infos"
+
+	// AnnoLogVerbosity is set in RolloutRun to raise the log verbosity of this
+	// rolloutRun without changing the global log level. The value is a V-level
+	// from 0 to 10.
+	AnnoLogVerbosity = "rollout.kusionstack.io/log-verbosity"
+
+	// AnnoCanaryExcludedBy is set on PodDisruptionBudget whose selector is changed
+	// to exclude canary pods. The value is a comma separated list of the stable
+	// workload names in canary, the selector is restored after all of them are
+	// recycled.
+	AnnoCanaryExcludedBy = "rollout.kusionstack.io/canary-excluded-by"
+
+	// AnnoTraceParent contains a W3C traceparent. It can be set in RolloutRun to
+	// join an existing trace, and it is set in RolloutWebhookReview sent to
+	// webhooks, which is also sent as the traceparent header.
+	AnnoTraceParent = "rollout.kusionstack.io/traceparent"
+
+	// AnnoTraceID is set in events of RolloutRun to correlate them with the trace.
+	AnnoTraceID = "rollout.kusionstack.io/trace-id"
+
+	// AnnoCrossNamespaceAllowedFrom is set on namespace to allow the resources
+	// in other namespaces to reference it, e.g. the traffic backends and routes
+	// in the namespace of a shared gateway. The value is a comma separated list
+	// of the referrer namespaces, "*" allows all namespaces.
+	AnnoCrossNamespaceAllowedFrom = "rollout.kusionstack.io/cross-namespace-allowed-from"
 )
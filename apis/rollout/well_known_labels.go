@@ -25,8 +25,21 @@ const (
 const (
 	// This label will be added to canary workload and pods.
 	LabelCanary = "rollout.kusionstack.io/canary"
+	// This label is added to the short-lived pods checking canary images are
+	// pullable, the value is the name of rolloutRun.
+	LabelImagePullCheck = "rollout.kusionstack.io/image-pull-check"
 	// This label indicates the revision of pods controlled by workload.
 	LabelPodRevision            = "pod.rollout.kusionstack.io/revision"
 	LabelValuePodRevisionBase   = "base"
 	LabelValuePodRevisionCanary = "canary"
+	// These labels are added to canary pods to join their telemetry with the
+	// exact run, the values are the names of rollout and rolloutRun, and the
+	// step, which is always canary.
+	LabelRolloutName     = "rollout.kusionstack.io/rollout"
+	LabelRolloutRunName  = "rollout.kusionstack.io/rollout-run"
+	LabelRolloutStep     = "rollout.kusionstack.io/step"
+	LabelValueStepCanary = "canary"
+	// This label is added to the pods of canary variant, the value is the name
+	// of variant. The canary backend of variant selects pods by it.
+	LabelCanaryVariant = "rollout.kusionstack.io/canary-variant"
 )
@@ -17,12 +17,22 @@
 package options
 
 import (
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/spf13/pflag"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/cache"
+	ctrl "sigs.k8s.io/controller-runtime"
 
 	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/analysis"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/audit"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/executor"
 )
 
 var GroupKindConcurrency = map[string]int{
@@ -44,9 +54,28 @@ type ControllerOptions struct {
 
 	GroupKindConcurrency map[string]int
 	CacheSyncTimeout     time.Duration
+
+	RetryDefaultInterval     time.Duration
+	RetryImmediatelyInterval time.Duration
+	TrafficResyncInterval    time.Duration
+
+	CanaryInheritedMetadataKeys []string
+	CanaryMaxTrafficWeight      int32
+
+	GlobalPauseConfigMap string
+
+	AuditLogPath       string
+	AuditLogBufferSize int
+	// AuditSink is created from AuditLogPath in Complete.
+	AuditSink audit.Sink
+
+	ReconcileMetricsDroppedLabels []string
+
+	DatadogAllowedSites []string
 }
 
 func NewControllerOptions() *ControllerOptions {
+	retry := executor.DefaultRetryOptions()
 	return &ControllerOptions{
 		LeaderElect:             true,
 		LeaderElectionNamespace: "kusionstack-rollout",
@@ -55,6 +84,16 @@ func NewControllerOptions() *ControllerOptions {
 		MaxConcurrentWorkers:    10,
 		GroupKindConcurrency:    GroupKindConcurrency,
 		CacheSyncTimeout:        10 * time.Minute,
+
+		RetryDefaultInterval:     retry.Default,
+		RetryImmediatelyInterval: retry.Immediately,
+		TrafficResyncInterval:    retry.TrafficResync,
+
+		GlobalPauseConfigMap: "kusionstack-rollout/rollout-global-pause",
+
+		AuditLogBufferSize: audit.DefaultBufferSize,
+
+		DatadogAllowedSites: analysis.DefaultDatadogSites,
 	}
 }
 
@@ -69,14 +108,84 @@ func (o *ControllerOptions) BindFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&o.MaxConcurrentWorkers, "max-concurrent-workers", o.MaxConcurrentWorkers, "The number of concurrent workers for the controller.")
 	fs.StringToIntVar(&o.GroupKindConcurrency, "group-kind-concurrency", o.GroupKindConcurrency, "The number of concurrent workers for each controller group kind. The key is expected to be consistent in form with GroupKind.String()")
 	fs.DurationVar(&o.CacheSyncTimeout, "cache-sync-timeout", o.CacheSyncTimeout, "The time limit set to wait for syncing caches.")
+	fs.DurationVar(&o.RetryDefaultInterval, "retry-default-interval", o.RetryDefaultInterval, "The interval to requeue RolloutRun when a step is waiting for something.")
+	fs.DurationVar(&o.RetryImmediatelyInterval, "retry-immediately-interval", o.RetryImmediatelyInterval, "The interval to requeue RolloutRun when a step wants to continue immediately, 0 means requeue without delay.")
+	fs.DurationVar(&o.TrafficResyncInterval, "traffic-resync-interval", o.TrafficResyncInterval, "The interval to re-verify the forked canary traffic rules while canary is active, 0 means no resync.")
+	fs.StringSliceVar(&o.CanaryInheritedMetadataKeys, "canary-inherited-metadata-keys", o.CanaryInheritedMetadataKeys, "The keys of RolloutRun labels and annotations inherited by canary pods, e.g. team,cost-center. They never override the canary pod template metadata patch and builtin canary labels.")
+	fs.Int32Var(&o.CanaryMaxTrafficWeight, "canary-max-traffic-weight", o.CanaryMaxTrafficWeight, "The maximum percentage of traffic routed to canaries, larger weights of RolloutRuns are clamped to it. 0 means unlimited.")
+	fs.StringVar(&o.GlobalPauseConfigMap, "global-pause-configmap", o.GlobalPauseConfigMap, "The namespace/name of ConfigMap freezing all in-progress canaries when its data paused is true, e.g. during a cluster-wide incident. Empty means global pause is disabled.")
+	fs.StringVar(&o.AuditLogPath, "audit-log-path", o.AuditLogPath, "The file to append RolloutRun audit records to as JSON lines, \"-\" means stdout. Empty means audit is disabled.")
+	fs.IntVar(&o.AuditLogBufferSize, "audit-log-buffer-size", o.AuditLogBufferSize, "The number of RolloutRun audit records buffered before they are written, records are dropped if the buffer is full.")
+	fs.StringSliceVar(&o.DatadogAllowedSites, "datadog-allowed-sites", o.DatadogAllowedSites, "The hosts of Datadog sites the controller credentials are sent to, other addresses of Datadog analysis are queried without credentials.")
+	fs.StringSliceVar(&o.ReconcileMetricsDroppedLabels, "reconcile-metrics-dropped-labels", o.ReconcileMetricsDroppedLabels, "The labels dropped from RolloutRun reconcile metrics to bound their cardinality, one of namespace and name, e.g. name aggregates RolloutRuns by namespace.")
+}
+
+// RetryOptions returns the RolloutRun executor retry options.
+func (o *ControllerOptions) RetryOptions() executor.RetryOptions {
+	return executor.RetryOptions{
+		Default:       o.RetryDefaultInterval,
+		Immediately:   o.RetryImmediatelyInterval,
+		TrafficResync: o.TrafficResyncInterval,
+	}
+}
+
+// RolloutRunOptions returns the RolloutRun reconciler options.
+func (o *ControllerOptions) RolloutRunOptions() rolloutrun.ReconcilerOptions {
+	return rolloutrun.ReconcilerOptions{
+		RetryOptions:                o.RetryOptions(),
+		MetricsDroppedLabels:        o.ReconcileMetricsDroppedLabels,
+		CanaryInheritedMetadataKeys: o.CanaryInheritedMetadataKeys,
+		CanaryMaxTrafficWeight:      o.CanaryMaxTrafficWeight,
+		GlobalPauseConfigMap:        o.GlobalPauseConfigMapKey(),
+		AuditSink:                   o.AuditSink,
+	}
+}
+
+// GlobalPauseConfigMapKey returns the key of global pause ConfigMap.
+func (o *ControllerOptions) GlobalPauseConfigMapKey() types.NamespacedName {
+	namespace, name, _ := cache.SplitMetaNamespaceKey(o.GlobalPauseConfigMap)
+	return types.NamespacedName{Namespace: namespace, Name: name}
 }
 
 // Validate implements suboptions.
 func (o *ControllerOptions) Validate() []error {
-	return nil
+	var errs []error
+	if err := o.RetryOptions().Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	for _, key := range o.CanaryInheritedMetadataKeys {
+		for _, msg := range validation.IsQualifiedName(key) {
+			errs = append(errs, fmt.Errorf("invalid canary inherited metadata key %q: %s", key, msg))
+		}
+	}
+	if o.CanaryMaxTrafficWeight < 0 || o.CanaryMaxTrafficWeight > 100 {
+		errs = append(errs, fmt.Errorf("invalid canary max traffic weight %d: must be between 0 and 100", o.CanaryMaxTrafficWeight))
+	}
+	if len(o.GlobalPauseConfigMap) > 0 {
+		key := o.GlobalPauseConfigMapKey()
+		if len(key.Namespace) == 0 || len(key.Name) == 0 {
+			errs = append(errs, fmt.Errorf("invalid global pause configmap %q: must be in the form of namespace/name", o.GlobalPauseConfigMap))
+		}
+	}
+	for _, label := range o.ReconcileMetricsDroppedLabels {
+		if !slices.Contains(rolloutrun.ReconcileMetricsLabels, label) {
+			errs = append(errs, fmt.Errorf("invalid reconcile metrics dropped label %q: must be one of %v", label, rolloutrun.ReconcileMetricsLabels))
+		}
+	}
+	if o.AuditLogBufferSize <= 0 {
+		errs = append(errs, fmt.Errorf("invalid audit log buffer size %d: must be greater than 0", o.AuditLogBufferSize))
+	}
+	return errs
 }
 
 // Complete implements suboptions.
 func (o *ControllerOptions) Complete() error {
+	if len(o.AuditLogPath) > 0 {
+		sink, err := audit.NewFileSink(o.AuditLogPath)
+		if err != nil {
+			return err
+		}
+		o.AuditSink = audit.NewAsyncSink(sink, o.AuditLogBufferSize, ctrl.Log.WithName("audit"))
+	}
 	return nil
 }
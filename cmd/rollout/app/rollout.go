@@ -20,6 +20,7 @@ import (
 
 	"github.com/spf13/cobra"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
@@ -31,9 +32,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	configv1alpha1 "sigs.k8s.io/controller-runtime/pkg/config/v1alpha1"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"kusionstack.io/rollout/cmd/rollout/app/options"
+	"kusionstack.io/rollout/pkg/analysis"
 	"kusionstack.io/rollout/pkg/controllers/initializers"
+	"kusionstack.io/rollout/pkg/controllers/registry"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun"
 	"kusionstack.io/rollout/pkg/utils/cli"
 	"kusionstack.io/rollout/pkg/webhook"
 )
@@ -61,6 +66,11 @@ func NewRolloutCommand(opt *options.Options) *cobra.Command {
 		},
 	}
 
+	// options of rolloutRun controller are resolved after flags are parsed
+	utilruntime.Must(initializers.Controllers.Add(rolloutrun.ControllerName, func(mgr manager.Manager) (bool, error) {
+		return rolloutrun.InitFuncWithOptions(registry.Workloads, opt.Controller.RolloutRunOptions())(mgr)
+	}))
+
 	cli.AddFlagsAndUsage(cmd, opt.Flags(initializers.Controllers, webhook.Initializer))
 
 	return cmd
@@ -151,6 +161,8 @@ func Run(opt *options.Options) error {
 		return err
 	}
 
+	analysis.Providers.Register(analysis.ProviderDatadog, analysis.NewDatadogProvider(analysis.DefaultHTTPClient, opt.Controller.DatadogAllowedSites))
+
 	err = initializers.Controllers.SetupWithManager(mgr)
 	if err != nil {
 		setupLog.Error(err, "failed to setup controller initializers")
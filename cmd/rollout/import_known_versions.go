@@ -18,6 +18,7 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	operatingv1alpha1 "kusionstack.io/kube-api/apps/v1alpha1"
+	gatewayapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
 )
@@ -25,5 +26,6 @@ import (
 func init() {
 	utilruntime.Must(rolloutv1alpha1.AddToScheme(clientgoscheme.Scheme))
 	utilruntime.Must(operatingv1alpha1.AddToScheme(clientgoscheme.Scheme))
+	utilruntime.Must(gatewayapiv1alpha2.AddToScheme(clientgoscheme.Scheme))
 	//+kubebuilder:scaffold:scheme
 }
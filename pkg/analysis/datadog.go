@@ -0,0 +1,144 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+const (
+	ProviderDatadog = "datadog"
+
+	// EnvDatadogAPIKey and EnvDatadogAppKey are the environment variables of
+	// controller holding the datadog credentials.
+	EnvDatadogAPIKey = "DD_API_KEY"
+	EnvDatadogAppKey = "DD_APP_KEY"
+
+	defaultDatadogAddress = "https://api.datadoghq.com"
+	// datadogQueryWindow is the time range to query, the last point is used.
+	datadogQueryWindow = 5 * time.Minute
+)
+
+// DefaultDatadogSites are the hosts of Datadog sites the credentials are sent to.
+var DefaultDatadogSites = []string{
+	"api.datadoghq.com",
+	"api.us3.datadoghq.com",
+	"api.us5.datadoghq.com",
+	"api.datadoghq.eu",
+	"api.ap1.datadoghq.com",
+	"api.ddog-gov.com",
+}
+
+type datadogProvider struct {
+	thresholdComparator
+	client *http.Client
+	now    func() time.Time
+	// sites are the hosts allowed to receive the credentials of controller,
+	// other addresses set by RolloutRun are queried without credentials.
+	sites []string
+}
+
+// NewDatadogProvider returns a provider querying Datadog metrics API, the
+// credentials of controller are only sent to the given sites.
+func NewDatadogProvider(client *http.Client, sites []string) AnalysisProvider {
+	return &datadogProvider{client: client, now: time.Now, sites: sites}
+}
+
+type datadogResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Series []struct {
+		// Pointlist is a list of [<unix_millis>, <value>]
+		Pointlist [][]*float64 `json:"pointlist"`
+	} `json:"series"`
+}
+
+func (p *datadogProvider) Query(ctx context.Context, metric *rolloutv1alpha1.AnalysisMetric) (float64, error) {
+	address := metric.Provider.Address
+	if len(address) == 0 {
+		address = defaultDatadogAddress
+	}
+
+	parsed, err := url.Parse(address)
+	if err != nil {
+		return 0, p.error("DatadogInvalidConfig", err)
+	}
+	if parsed.Scheme != "https" || len(parsed.Host) == 0 {
+		return 0, p.error("DatadogInvalidConfig", fmt.Errorf("address %q must be an absolute https url", address))
+	}
+
+	now := p.now()
+	params := url.Values{
+		"query": []string{metric.Query},
+		"from":  []string{strconv.FormatInt(now.Add(-datadogQueryWindow).Unix(), 10)},
+		"to":    []string{strconv.FormatInt(now.Unix(), 10)},
+	}
+	u := strings.TrimSuffix(address, "/") + "/api/v1/query?" + params.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return 0, p.error("DatadogInvalidConfig", err)
+	}
+	// the address is set by RolloutRun, never leak the credentials to a
+	// server not trusted by the operator
+	if slices.Contains(p.sites, parsed.Host) {
+		req.Header.Set("DD-API-KEY", os.Getenv(EnvDatadogAPIKey))
+		req.Header.Set("DD-APPLICATION-KEY", os.Getenv(EnvDatadogAppKey))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, p.error("DatadogQueryFailed", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return 0, p.error("DatadogUnauthorized", fmt.Errorf("status code %d", resp.StatusCode))
+	}
+
+	var result datadogResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, p.error("DatadogInvalidResponse", fmt.Errorf("status code %d, %w", resp.StatusCode, err))
+	}
+	if result.Status != "ok" {
+		return 0, p.error("DatadogQueryFailed", fmt.Errorf("status %s, error: %s", result.Status, result.Error))
+	}
+	if len(result.Series) == 0 || len(result.Series[0].Pointlist) == 0 {
+		return 0, p.error("DatadogNoData", fmt.Errorf("query %q returns no data", metric.Query))
+	}
+
+	points := result.Series[0].Pointlist
+	last := points[len(points)-1]
+	if len(last) != 2 || last[1] == nil {
+		return 0, p.error("DatadogNoData", fmt.Errorf("query %q returns no value in last point", metric.Query))
+	}
+	return *last[1], nil
+}
+
+func (p *datadogProvider) error(reason string, err error) error {
+	return &ProviderError{Provider: ProviderDatadog, Reason: reason, Err: err}
+}
@@ -0,0 +1,129 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+func Test_datadogProvider_Query(t *testing.T) {
+	t.Setenv(EnvDatadogAPIKey, "api-key")
+	t.Setenv(EnvDatadogAppKey, "app-key")
+
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		want       float64
+		wantReason string
+	}{
+		{
+			name:   "last point",
+			status: http.StatusOK,
+			body:   `{"status":"ok","series":[{"pointlist":[[1700000000000,1.5],[1700000060000,2.5]]}]}`,
+			want:   2.5,
+		},
+		{
+			name:       "no data",
+			status:     http.StatusOK,
+			body:       `{"status":"ok","series":[]}`,
+			wantReason: "DatadogNoData",
+		},
+		{
+			name:       "unauthorized",
+			status:     http.StatusForbidden,
+			body:       `{"errors":["Forbidden"]}`,
+			wantReason: "DatadogUnauthorized",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/api/v1/query", r.URL.Path)
+				assert.Equal(t, "avg:latency{service:foo}", r.URL.Query().Get("query"))
+				assert.Equal(t, "1699999700", r.URL.Query().Get("from"))
+				assert.Equal(t, "api-key", r.Header.Get("DD-API-KEY"))
+				assert.Equal(t, "app-key", r.Header.Get("DD-APPLICATION-KEY"))
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			p := &datadogProvider{
+				client: server.Client(),
+				now:    func() time.Time { return time.Unix(1700000000, 0) },
+				sites:  []string{strings.TrimPrefix(server.URL, "https://")},
+			}
+			got, err := p.Query(context.Background(), &rolloutv1alpha1.AnalysisMetric{
+				Name:     "latency",
+				Provider: rolloutv1alpha1.AnalysisProvider{Name: ProviderDatadog, Address: server.URL},
+				Query:    "avg:latency{service:foo}",
+			})
+			if len(tt.wantReason) > 0 {
+				var perr *ProviderError
+				if assert.True(t, errors.As(err, &perr)) {
+					assert.Equal(t, tt.wantReason, perr.Reason)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_datadogProvider_Query_Credentials(t *testing.T) {
+	t.Setenv(EnvDatadogAPIKey, "api-key")
+	t.Setenv(EnvDatadogAppKey, "app-key")
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the address is not an allowed site, no credentials are sent
+		assert.Empty(t, r.Header.Get("DD-API-KEY"))
+		assert.Empty(t, r.Header.Get("DD-APPLICATION-KEY"))
+		fmt.Fprint(w, `{"status":"ok","series":[{"pointlist":[[1700000000000,1.5]]}]}`)
+	}))
+	defer server.Close()
+
+	p := &datadogProvider{client: server.Client(), now: time.Now, sites: DefaultDatadogSites}
+	metric := &rolloutv1alpha1.AnalysisMetric{
+		Name:     "latency",
+		Provider: rolloutv1alpha1.AnalysisProvider{Name: ProviderDatadog, Address: server.URL},
+		Query:    "avg:latency{service:foo}",
+	}
+	got, err := p.Query(context.Background(), metric)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, got)
+
+	// non https address is rejected
+	metric.Provider.Address = strings.Replace(server.URL, "https://", "http://", 1)
+	_, err = p.Query(context.Background(), metric)
+	var perr *ProviderError
+	if assert.True(t, errors.As(err, &perr)) {
+		assert.Equal(t, "DatadogInvalidConfig", perr.Reason)
+	}
+}
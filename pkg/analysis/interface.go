@@ -0,0 +1,92 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/genericregistry"
+)
+
+// AnalysisProvider queries metrics from a metric backend.
+type AnalysisProvider interface {
+	// Query returns the current value of metric.
+	Query(ctx context.Context, metric *rolloutv1alpha1.AnalysisMetric) (float64, error)
+	// CompareThreshold returns true if value is within the threshold of metric.
+	CompareThreshold(value float64, metric *rolloutv1alpha1.AnalysisMetric) (bool, error)
+}
+
+// Providers contains all analysis providers indexed by name.
+var Providers = genericregistry.New[string, AnalysisProvider]()
+
+const defaultQueryTimeout = 10 * time.Second
+
+// DefaultHTTPClient is the http client used by the builtin providers.
+var DefaultHTTPClient = &http.Client{Timeout: defaultQueryTimeout}
+
+func init() {
+	Providers.Register(ProviderPrometheus, NewPrometheusProvider(DefaultHTTPClient))
+	Providers.Register(ProviderDatadog, NewDatadogProvider(DefaultHTTPClient, DefaultDatadogSites))
+}
+
+// ProviderError is returned by provider when it fails to query metric.
+type ProviderError struct {
+	// Provider is the name of provider.
+	Provider string
+	// Reason is a provider specific reason in CamelCase, e.g. PrometheusQueryFailed.
+	Reason string
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Reason, e.Err)
+}
+
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+// thresholdComparator implements CompareThreshold by the min and max of metric.
+type thresholdComparator struct{}
+
+func (thresholdComparator) CompareThreshold(value float64, metric *rolloutv1alpha1.AnalysisMetric) (bool, error) {
+	if metric.Min != nil {
+		minValue, err := strconv.ParseFloat(*metric.Min, 64)
+		if err != nil {
+			return false, fmt.Errorf("invalid min %q of metric %s: %w", *metric.Min, metric.Name, err)
+		}
+		if value < minValue {
+			return false, nil
+		}
+	}
+	if metric.Max != nil {
+		maxValue, err := strconv.ParseFloat(*metric.Max, 64)
+		if err != nil {
+			return false, fmt.Errorf("invalid max %q of metric %s: %w", *metric.Max, metric.Name, err)
+		}
+		if value > maxValue {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,121 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+const ProviderPrometheus = "prometheus"
+
+type prometheusProvider struct {
+	thresholdComparator
+	client *http.Client
+}
+
+// NewPrometheusProvider returns a provider querying Prometheus HTTP API.
+func NewPrometheusProvider(client *http.Client) AnalysisProvider {
+	return &prometheusProvider{client: client}
+}
+
+type prometheusResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+type prometheusSample struct {
+	Value []interface{} `json:"value"`
+}
+
+func (p *prometheusProvider) Query(ctx context.Context, metric *rolloutv1alpha1.AnalysisMetric) (float64, error) {
+	if len(metric.Provider.Address) == 0 {
+		return 0, p.error("PrometheusInvalidConfig", fmt.Errorf("address is required"))
+	}
+
+	query, err := RenderQuery(metric, QueryVarsFrom(ctx))
+	if err != nil {
+		return 0, p.error(ReasonInvalidQueryTemplate, err)
+	}
+
+	u := strings.TrimSuffix(metric.Provider.Address, "/") + "/api/v1/query?" + url.Values{"query": []string{query}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return 0, p.error("PrometheusInvalidConfig", err)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, p.error("PrometheusQueryFailed", err)
+	}
+	defer resp.Body.Close()
+
+	var result prometheusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, p.error("PrometheusInvalidResponse", fmt.Errorf("status code %d, %w", resp.StatusCode, err))
+	}
+	if result.Status != "success" {
+		return 0, p.error("PrometheusQueryFailed", fmt.Errorf("status %s, error: %s", result.Status, result.Error))
+	}
+
+	var value []interface{}
+	switch result.Data.ResultType {
+	case "scalar":
+		if err := json.Unmarshal(result.Data.Result, &value); err != nil {
+			return 0, p.error("PrometheusInvalidResponse", err)
+		}
+	case "vector":
+		var samples []prometheusSample
+		if err := json.Unmarshal(result.Data.Result, &samples); err != nil {
+			return 0, p.error("PrometheusInvalidResponse", err)
+		}
+		if len(samples) == 0 {
+			return 0, p.error("PrometheusNoData", fmt.Errorf("query %q returns empty vector", query))
+		}
+		value = samples[0].Value
+	default:
+		return 0, p.error("PrometheusInvalidResponse", fmt.Errorf("unsupported result type %q", result.Data.ResultType))
+	}
+
+	// value is [<unix_time>, "<sample_value>"]
+	if len(value) != 2 {
+		return 0, p.error("PrometheusInvalidResponse", fmt.Errorf("invalid sample %v", value))
+	}
+	s, ok := value[1].(string)
+	if !ok {
+		return 0, p.error("PrometheusInvalidResponse", fmt.Errorf("invalid sample value %v", value[1]))
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, p.error("PrometheusInvalidResponse", err)
+	}
+	return v, nil
+}
+
+func (p *prometheusProvider) error(reason string, err error) error {
+	return &ProviderError{Provider: ProviderPrometheus, Reason: reason, Err: err}
+}
@@ -0,0 +1,133 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+func Test_prometheusProvider_Query(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		want       float64
+		wantReason string
+	}{
+		{
+			name: "vector",
+			body: `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"0.995"]}]}}`,
+			want: 0.995,
+		},
+		{
+			name: "scalar",
+			body: `{"status":"success","data":{"resultType":"scalar","result":[1700000000,"12"]}}`,
+			want: 12,
+		},
+		{
+			name:       "empty vector",
+			body:       `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+			wantReason: "PrometheusNoData",
+		},
+		{
+			name:       "query error",
+			body:       `{"status":"error","error":"parse error"}`,
+			wantReason: "PrometheusQueryFailed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/api/v1/query", r.URL.Path)
+				assert.Equal(t, "up", r.URL.Query().Get("query"))
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			p := NewPrometheusProvider(server.Client())
+			got, err := p.Query(context.Background(), &rolloutv1alpha1.AnalysisMetric{
+				Name:     "up",
+				Provider: rolloutv1alpha1.AnalysisProvider{Name: ProviderPrometheus, Address: server.URL},
+				Query:    "up",
+			})
+			if len(tt.wantReason) > 0 {
+				var perr *ProviderError
+				if assert.True(t, errors.As(err, &perr)) {
+					assert.Equal(t, tt.wantReason, perr.Reason)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_prometheusProvider_QueryTemplate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, `sum(rate(http_errors_total{code="500",pod=~"foo-canary-.*"}[60s]))`, r.URL.Query().Get("query"))
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"scalar","result":[1700000000,"0.001"]}}`)
+	}))
+	defer server.Close()
+
+	p := NewPrometheusProvider(server.Client())
+	metric := &rolloutv1alpha1.AnalysisMetric{
+		Name:     "error-rate",
+		Provider: rolloutv1alpha1.AnalysisProvider{Name: ProviderPrometheus, Address: server.URL},
+		Query:    `sum(rate(http_errors_total{code="500",{{.CanarySelector}}}[{{.Window}}]))`,
+		Window:   &metav1.Duration{Duration: time.Minute},
+	}
+	ctx := WithQueryVars(context.Background(), QueryVars{CanarySelector: `pod=~"foo-canary-.*"`})
+	got, err := p.Query(ctx, metric)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.001, got)
+
+	// unknown variable is reported as invalid template instead of failed query
+	metric.Query = `sum(rate(http_errors_total{ {{.CanaryPods}} }[{{.Window}}]))`
+	_, err = p.Query(ctx, metric)
+	var perr *ProviderError
+	if assert.True(t, errors.As(err, &perr)) {
+		assert.Equal(t, ReasonInvalidQueryTemplate, perr.Reason)
+	}
+}
+
+func Test_thresholdComparator(t *testing.T) {
+	metric := &rolloutv1alpha1.AnalysisMetric{Name: "success-rate", Min: ptr.To("0.99"), Max: ptr.To("1")}
+	c := thresholdComparator{}
+
+	ok, err := c.CompareThreshold(0.995, metric)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = c.CompareThreshold(0.98, metric)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = c.CompareThreshold(0.98, &rolloutv1alpha1.AnalysisMetric{Name: "bad", Max: ptr.To("x")})
+	assert.Error(t, err)
+}
@@ -0,0 +1,86 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package analysis
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"text/template"
+	"time"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+// ReasonInvalidQueryTemplate is the reason of ProviderError when the query of
+// metric can not be rendered, which is a misconfiguration instead of a failed
+// query.
+const ReasonInvalidQueryTemplate = "InvalidQueryTemplate"
+
+const defaultQueryWindow = 5 * time.Minute
+
+// QueryVars are the variables of the selectors rendered in templated queries.
+type QueryVars struct {
+	// CanarySelector is the label matchers of canary pods without braces, e.g.
+	// namespace="default",pod=~"foo-canary-.*".
+	CanarySelector string
+	// StableSelector is the label matchers of stable pods without braces.
+	StableSelector string
+}
+
+// queryData is the data rendered in templated queries.
+type queryData struct {
+	QueryVars
+	// Window is the time window of query in seconds, e.g. 300s.
+	Window string
+}
+
+type queryVarsKey struct{}
+
+// WithQueryVars returns a copy of ctx carrying vars rendered in the queries of
+// metrics.
+func WithQueryVars(ctx context.Context, vars QueryVars) context.Context {
+	return context.WithValue(ctx, queryVarsKey{}, vars)
+}
+
+// QueryVarsFrom returns the vars carried by ctx, or empty vars if not set.
+func QueryVarsFrom(ctx context.Context) QueryVars {
+	vars, _ := ctx.Value(queryVarsKey{}).(QueryVars)
+	return vars
+}
+
+// RenderQuery renders the query of metric as a template with vars and the
+// window of metric. Queries without template actions are returned as is.
+func RenderQuery(metric *rolloutv1alpha1.AnalysisMetric, vars QueryVars) (string, error) {
+	tmpl, err := template.New(metric.Name).Option("missingkey=error").Parse(metric.Query)
+	if err != nil {
+		return "", err
+	}
+	window := defaultQueryWindow
+	if metric.Window != nil {
+		window = metric.Window.Duration
+	}
+	data := queryData{
+		QueryVars: vars,
+		Window:    fmt.Sprintf("%ds", int64(window.Seconds())),
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -26,6 +26,9 @@ type IBackend interface {
 	// todo: discussion: maybe Fork can be replaced by Create/Delete, and using Create/Delete to check if ready or deleted
 	ForkStable(stableName string) client.Object
 	ForkCanary(canaryName string) client.Object
+	// ForkCanaryVariant returns the canary backend of variant, which only
+	// selects the canary pods of variant.
+	ForkCanaryVariant(canaryName, variant string) client.Object
 }
 
 type Store interface {
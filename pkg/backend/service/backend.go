@@ -48,6 +48,18 @@ func (s *serviceBackend) ForkCanary(canaryName string) client.Object {
 	return canaryBackend
 }
 
+func (s *serviceBackend) ForkCanaryVariant(canaryName, variant string) client.Object {
+	canaryBackend := s.ForkCanary(canaryName).(*corev1.Service)
+	// the selector is shared with origin backend, copy it before adding variant
+	selector := make(map[string]string, len(canaryBackend.Spec.Selector)+1)
+	for k, v := range canaryBackend.Spec.Selector {
+		selector[k] = v
+	}
+	selector[rollout.LabelCanaryVariant] = variant
+	canaryBackend.Spec.Selector = selector
+	return canaryBackend
+}
+
 func (s *serviceBackend) ForkStable(stableName string) client.Object {
 	stableBackend := &corev1.Service{}
 	stableBackend.Name = stableName
@@ -19,7 +19,9 @@ import (
 	"fmt"
 	"reflect"
 
+	"github.com/samber/lo"
 	"go.uber.org/multierr"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -74,6 +76,7 @@ func (b *BackendRoutingReconciler) SetupWithManager(mgr manager.Manager) error {
 //+kubebuilder:rbac:groups=rollout.kusionstack.io,resources=backendroutings/finalizers,verbs=update;patch
 //+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="networking.k8s.io",resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="gateway.networking.k8s.io",resources=grpcroutes,verbs=get;list;watch;update;patch
 
 func (b *BackendRoutingReconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
 	br := &v1alpha1.BackendRouting{}
@@ -115,7 +118,7 @@ func (b *BackendRoutingReconciler) reconcileTerminatingBackendRouting(_ context.
 func (b *BackendRoutingReconciler) reconcileInClusterWithoutForwarding(ctx context.Context, br *v1alpha1.BackendRouting) (reconcile.Result, error) {
 	backendsStatuses := br.Status.Backends
 	// todo: discussion: if canary backend still exist, should we clean it?
-	if backendsStatuses.Canary.Name != "" {
+	if len(canaryBackendStatuses(&backendsStatuses)) > 0 {
 		return reconcile.Result{}, fmt.Errorf("canary backend still exist without forwarding spec")
 	}
 
@@ -241,6 +244,7 @@ func (b *BackendRoutingReconciler) reconcileInClusterWithoutForwarding(ctx conte
 						Cluster: curRoute.Cluster,
 						Name:    curRoute.Name,
 					},
+					Namespace: curRoute.Namespace,
 				},
 				Synced: true,
 			}
@@ -263,12 +267,12 @@ func (b *BackendRoutingReconciler) reconcileInClusterWithoutForwarding(ctx conte
 }
 
 func (b *BackendRoutingReconciler) reconcileInClusterWithForwarding(ctx context.Context, br *v1alpha1.BackendRouting) (reconcile.Result, error) {
-	if br.Spec.Forwarding.Canary.Name != "" {
+	if br.Spec.Forwarding.HasCanary() {
 		return reconcile.Result{}, b.ensureCanaryAdd(ctx, br)
 	} else {
 		// no canary, which means only has stable
 		// if status has canary, delete it and update route
-		if br.Status.Backends.Canary.Name != "" {
+		if len(canaryBackendStatuses(&br.Status.Backends)) > 0 {
 			return reconcile.Result{}, b.ensureCanaryRemove(ctx, br)
 		} else {
 			needUpdateStatus := false
@@ -359,7 +363,8 @@ func (b *BackendRoutingReconciler) ensureCanaryRemove(ctx context.Context, br *v
 	}
 	phase := br.Status.Phase
 
-	if backendsStatuses.Canary.Conditions.Terminating == nil || !*backendsStatuses.Canary.Conditions.Terminating {
+	canaryStatuses := canaryBackendStatuses(&backendsStatuses)
+	if !lo.EveryBy(canaryStatuses, func(status *v1alpha1.BackendStatus) bool { return ptr.Deref(status.Conditions.Terminating, false) }) {
 		// delete canary route
 		var routeCanaryRemoveErr []error
 		for idx, routeSpec := range br.Spec.Routes {
@@ -383,34 +388,30 @@ func (b *BackendRoutingReconciler) ensureCanaryRemove(ctx context.Context, br *v
 			return b.handleErr(ctx, br, backendsStatuses, routesStatuses, phase, v1alpha1.RouteUpgrading, multierr.Combine(routeCanaryRemoveErr...))
 		}
 
-		// delete canary backend
-		canaryBackend, err := b.getBackend(ctx, br, backendsStatuses.Canary.Name)
-		if err != nil {
-			if errors.IsNotFound(err) {
+		// delete canary backends
+		phase = v1alpha1.Ready
+		for _, status := range canaryStatuses {
+			canaryBackend, err := b.getCanaryBackend(ctx, br, status.Namespace, status.Name)
+			if err != nil {
+				if !errors.IsNotFound(err) {
+					return err
+				}
 				// already deleted
-				conditionTrue := true
-				conditionFalse := false
-				backendsStatuses.Canary.Conditions.Terminating = &conditionTrue
-				backendsStatuses.Canary.Conditions.Ready = &conditionFalse
-				phase = v1alpha1.Ready
-				needUpdateStatus = true
 			} else {
-				return err
-			}
-		} else {
-			if canaryBackend.GetBackendObject().GetDeletionTimestamp() == nil {
-				err = b.Client.Delete(clusterinfo.WithCluster(ctx, br.Spec.Backend.Cluster), canaryBackend.GetBackendObject())
-				if err != nil {
-					return err
+				if canaryBackend.GetBackendObject().GetDeletionTimestamp() == nil {
+					err = b.Client.Delete(clusterinfo.WithCluster(ctx, br.Spec.Backend.Cluster), canaryBackend.GetBackendObject())
+					if err != nil {
+						return err
+					}
 				}
+				phase = v1alpha1.RouteUpgrading
 			}
 			conditionTrue := true
 			conditionFalse := false
-			backendsStatuses.Canary.Conditions.Terminating = &conditionTrue
-			backendsStatuses.Canary.Conditions.Ready = &conditionFalse
-			phase = v1alpha1.RouteUpgrading
-			needUpdateStatus = true
+			status.Conditions.Terminating = &conditionTrue
+			status.Conditions.Ready = &conditionFalse
 		}
+		needUpdateStatus = true
 	} else {
 		// check canary route deleted
 		var routeCanaryRemoveErr []error
@@ -435,12 +436,15 @@ func (b *BackendRoutingReconciler) ensureCanaryRemove(ctx context.Context, br *v
 			return b.handleErr(ctx, br, backendsStatuses, routesStatuses, phase, v1alpha1.RouteUpgrading, multierr.Combine(routeCanaryRemoveErr...))
 		}
 
-		// check canary backend deleted
-		_, err := b.getBackend(ctx, br, backendsStatuses.Canary.Name)
-		if !errors.IsNotFound(err) {
-			return b.handleErr(ctx, br, backendsStatuses, routesStatuses, phase, v1alpha1.RouteUpgrading, fmt.Errorf("canary backend not deleted yet"))
+		// check canary backends deleted
+		for _, status := range canaryStatuses {
+			_, err := b.getCanaryBackend(ctx, br, status.Namespace, status.Name)
+			if !errors.IsNotFound(err) {
+				return b.handleErr(ctx, br, backendsStatuses, routesStatuses, phase, v1alpha1.RouteUpgrading, fmt.Errorf("canary backend %s not deleted yet", status.Name))
+			}
 		}
 		backendsStatuses.Canary = v1alpha1.BackendStatus{}
+		backendsStatuses.Variants = nil
 		needUpdateStatus = true
 	}
 
@@ -465,27 +469,41 @@ func (b *BackendRoutingReconciler) ensureCanaryAdd(ctx context.Context, br *v1al
 	// todo: discussion
 	// should we check origin & stable here?
 	// check canary backend and route
-	_, err := b.getBackend(ctx, br, br.Spec.Forwarding.Canary.Name)
-	if err != nil {
-		if !errors.IsNotFound(err) {
-			return b.handleErr(ctx, br, backendsStatuses, routesStatuses, phase, v1alpha1.RouteUpgrading, err)
-		}
-		// canary backend not exist, create canary backend, get origin backend first
-		originBackend, err := b.getBackend(ctx, br, br.Spec.Backend.Name)
+	if canary := br.Spec.Forwarding.Canary; len(canary.Name) > 0 {
+		err := b.ensureCanaryBackend(ctx, br, canary.Name, canary.Namespace, "")
 		if err != nil {
 			return b.handleErr(ctx, br, backendsStatuses, routesStatuses, phase, v1alpha1.RouteUpgrading, err)
 		}
-
-		canaryForked := originBackend.ForkCanary(br.Spec.Forwarding.Canary.Name)
-		err = b.Client.Create(clusterinfo.WithCluster(ctx, br.Spec.Backend.Cluster), canaryForked)
+		if backendsStatuses.Canary.Name != canary.Name || backendsStatuses.Canary.Namespace != canary.Namespace ||
+			!ptr.Deref(backendsStatuses.Canary.Conditions.Ready, false) {
+			backendsStatuses.Canary.Name = canary.Name
+			backendsStatuses.Canary.Namespace = canary.Namespace
+			conditionTrue := true
+			backendsStatuses.Canary.Conditions.Ready = &conditionTrue
+			needUpdateStatus = true
+		}
+	}
+	canaryPorts, err := b.canaryPorts(ctx, br)
+	if err != nil {
+		return b.handleErr(ctx, br, backendsStatuses, routesStatuses, phase, v1alpha1.RouteUpgrading, err)
+	}
+	variantStatuses := make([]v1alpha1.BackendStatus, 0, len(br.Spec.Forwarding.Variants))
+	for _, variant := range br.Spec.Forwarding.Variants {
+		err := b.ensureCanaryBackend(ctx, br, variant.Name, variant.Namespace, variant.Variant)
 		if err != nil {
 			return b.handleErr(ctx, br, backendsStatuses, routesStatuses, phase, v1alpha1.RouteUpgrading, err)
 		}
+		variantStatuses = append(variantStatuses, v1alpha1.BackendStatus{
+			Name:       variant.Name,
+			Namespace:  variant.Namespace,
+			Conditions: v1alpha1.BackendConditions{Ready: ptr.To(true)},
+		})
 	}
-	if backendsStatuses.Canary.Name != br.Spec.Forwarding.Canary.Name || !ptr.Deref(backendsStatuses.Canary.Conditions.Ready, false) {
-		backendsStatuses.Canary.Name = br.Spec.Forwarding.Canary.Name
-		conditionTrue := true
-		backendsStatuses.Canary.Conditions.Ready = &conditionTrue
+	if len(variantStatuses) == 0 {
+		variantStatuses = nil
+	}
+	if !reflect.DeepEqual(backendsStatuses.Variants, variantStatuses) {
+		backendsStatuses.Variants = variantStatuses
 		needUpdateStatus = true
 	}
 
@@ -495,7 +513,20 @@ func (b *BackendRoutingReconciler) ensureCanaryAdd(ctx context.Context, br *v1al
 		if err != nil {
 			return b.handleErr(ctx, br, backendsStatuses, routesStatuses, phase, v1alpha1.RouteUpgrading, err)
 		}
-		err = iRoute.AddCanaryRoute(ctx, br.Spec.Forwarding)
+		if len(canaryPorts) > 0 {
+			if scoped, ok := iRoute.(route.PortScoped); ok {
+				scoped.SetCanaryPorts(canaryPorts)
+			} else {
+				err = fmt.Errorf("%w: %s %s", route.ErrPortsUnsupported, routeSpec.Kind, routeSpec.Name)
+			}
+		}
+		if err == nil {
+			err = iRoute.AddCanaryRoute(ctx, br.Spec.Forwarding)
+		}
+		if checker, ok := iRoute.(route.StatusChecker); ok && err == nil {
+			// the canary route is not synced until it is accepted by the route controller
+			err = checker.CheckStatus()
+		}
 		if err != nil {
 			if routesStatuses[idx].Synced {
 				routesStatuses[idx].Synced = false
@@ -514,6 +545,13 @@ func (b *BackendRoutingReconciler) ensureCanaryAdd(ctx context.Context, br *v1al
 		return b.handleErr(ctx, br, backendsStatuses, routesStatuses, phase, v1alpha1.RouteUpgrading, multierr.Combine(routeCanaryCreateErr...))
 	}
 
+	// record the ports after all routes are synced, so that the ports of canary
+	// are not observed until the traffic of them is split
+	if len(br.Spec.Forwarding.Canary.Name) > 0 && !reflect.DeepEqual(backendsStatuses.Canary.Ports, br.Spec.Forwarding.Canary.Ports) {
+		backendsStatuses.Canary.Ports = br.Spec.Forwarding.Canary.Ports
+		needUpdateStatus = true
+	}
+
 	if phase != v1alpha1.Ready {
 		phase = v1alpha1.Ready
 		needUpdateStatus = true
@@ -526,6 +564,72 @@ func (b *BackendRoutingReconciler) ensureCanaryAdd(ctx context.Context, br *v1al
 	return nil
 }
 
+// ensureCanaryBackend creates the canary backend forked from origin backend if
+// it does not exist, the backend of variant only selects the pods of variant.
+// If namespace is set, the canary backend is created in it to select the
+// canary pods there.
+func (b *BackendRoutingReconciler) ensureCanaryBackend(ctx context.Context, br *v1alpha1.BackendRouting, name, namespace, variant string) error {
+	_, err := b.getCanaryBackend(ctx, br, namespace, name)
+	if err == nil || !errors.IsNotFound(err) {
+		return err
+	}
+	// canary backend not exist, create canary backend, get origin backend first
+	originBackend, err := b.getBackend(ctx, br, br.Spec.Backend.Name)
+	if err != nil {
+		return err
+	}
+
+	canaryForked := originBackend.ForkCanary(name)
+	if len(variant) > 0 {
+		canaryForked = originBackend.ForkCanaryVariant(name, variant)
+	}
+	if len(namespace) > 0 {
+		canaryForked.SetNamespace(namespace)
+	}
+	return b.Client.Create(clusterinfo.WithCluster(ctx, br.Spec.Backend.Cluster), canaryForked)
+}
+
+// canaryPorts returns the ports of origin Service whose traffic is split to
+// canary, nil means the traffic of all ports is split.
+func (b *BackendRoutingReconciler) canaryPorts(ctx context.Context, br *v1alpha1.BackendRouting) ([]corev1.ServicePort, error) {
+	names := br.Spec.Forwarding.Canary.Ports
+	if len(names) == 0 || len(br.Spec.Forwarding.Canary.Name) == 0 {
+		return nil, nil
+	}
+	originBackend, err := b.getBackend(ctx, br, br.Spec.Backend.Name)
+	if err != nil {
+		return nil, err
+	}
+	svc, ok := originBackend.GetBackendObject().(*corev1.Service)
+	if !ok {
+		return nil, fmt.Errorf("%w: backend %s %s is not a Service", route.ErrPortsUnsupported, br.Spec.Backend.Kind, br.Spec.Backend.Name)
+	}
+	ports := make([]corev1.ServicePort, 0, len(names))
+	for _, name := range names {
+		port, found := lo.Find(svc.Spec.Ports, func(port corev1.ServicePort) bool {
+			return port.Name == name
+		})
+		if !found {
+			return nil, fmt.Errorf("port %q is not found in Service %s/%s", name, svc.Namespace, svc.Name)
+		}
+		ports = append(ports, port)
+	}
+	return ports, nil
+}
+
+// canaryBackendStatuses returns the statuses of canary backend and the backends
+// of variants recorded in statuses.
+func canaryBackendStatuses(statuses *v1alpha1.BackendStatuses) []*v1alpha1.BackendStatus {
+	result := make([]*v1alpha1.BackendStatus, 0, len(statuses.Variants)+1)
+	if len(statuses.Canary.Name) > 0 {
+		result = append(result, &statuses.Canary)
+	}
+	for i := range statuses.Variants {
+		result = append(result, &statuses.Variants[i])
+	}
+	return result
+}
+
 func (b *BackendRoutingReconciler) handleErr(ctx context.Context, br *v1alpha1.BackendRouting, backendsStatuses v1alpha1.BackendStatuses,
 	routesStatuses []v1alpha1.BackendRouteStatus, phase, desiredPhase v1alpha1.BackendRoutingPhase, err error,
 ) error {
@@ -542,7 +646,20 @@ func (b *BackendRoutingReconciler) getBackend(ctx context.Context, br *v1alpha1.
 		return nil, err
 	}
 
-	return backendStore.Get(ctx, br.Spec.Backend.Cluster, br.Namespace, backendName)
+	return backendStore.Get(ctx, br.Spec.Backend.Cluster, br.Spec.Backend.NamespaceOr(br.Namespace), backendName)
+}
+
+// getCanaryBackend returns the canary backend in namespace, empty namespace
+// means the namespace of origin backend.
+func (b *BackendRoutingReconciler) getCanaryBackend(ctx context.Context, br *v1alpha1.BackendRouting, namespace, backendName string) (backend.IBackend, error) {
+	if len(namespace) == 0 {
+		return b.getBackend(ctx, br, backendName)
+	}
+	backendStore, err := b.backendRegistry.Get(schema.FromAPIVersionAndKind(br.Spec.Backend.APIVersion, br.Spec.Backend.Kind))
+	if err != nil {
+		return nil, err
+	}
+	return backendStore.Get(ctx, br.Spec.Backend.Cluster, namespace, backendName)
 }
 
 func (b *BackendRoutingReconciler) getRoute(ctx context.Context, namespace string, routeInfo v1alpha1.CrossClusterObjectReference) (route.IRoute, error) {
@@ -551,7 +668,7 @@ func (b *BackendRoutingReconciler) getRoute(ctx context.Context, namespace strin
 		return nil, err
 	}
 
-	return routeStore.Get(ctx, routeInfo.Cluster, namespace, routeInfo.Name)
+	return routeStore.Get(ctx, routeInfo.Cluster, routeInfo.NamespaceOr(namespace), routeInfo.Name)
 }
 
 func (b *BackendRoutingReconciler) updateBackendRoutingStatus(ctx context.Context, br *v1alpha1.BackendRouting,
@@ -20,13 +20,12 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 
 	"kusionstack.io/rollout/pkg/controllers/rollout"
-	"kusionstack.io/rollout/pkg/controllers/rolloutrun"
 )
 
 func init() {
 	// init rollout controller
 	utilruntime.Must(Controllers.Add(rollout.ControllerName, rollout.InitFunc))
 
-	// init rolloutRun controller
-	utilruntime.Must(Controllers.Add(rolloutrun.ControllerName, rolloutrun.InitFunc))
+	// rolloutRun controller is added by the command with its options, see
+	// rolloutrun.InitFuncWithOptions
 }
@@ -22,6 +22,7 @@ import (
 
 	"kusionstack.io/rollout/pkg/genericregistry"
 	"kusionstack.io/rollout/pkg/route"
+	"kusionstack.io/rollout/pkg/route/grpcroute"
 	"kusionstack.io/rollout/pkg/route/ingress"
 )
 
@@ -41,5 +42,6 @@ func NewRouteRegistry() RouteRegistry {
 
 func InitRouteRegistry(mgr manager.Manager) (bool, error) {
 	Routes.Register(ingress.GVK, ingress.NewStorage(mgr))
+	Routes.Register(grpcroute.GVK, grpcroute.NewStorage(mgr))
 	return true, nil
 }
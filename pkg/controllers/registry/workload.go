@@ -18,6 +18,8 @@ package registry
 
 import (
 	"context"
+	"fmt"
+	"sync"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -56,6 +58,58 @@ func InitWorkloadRegistry(mgr manager.Manager) (bool, error) {
 	Workloads.Register(collaset.GVK, collaset.New())
 	Workloads.Register(poddecoration.GVK, poddecoration.New())
 	Workloads.Register(statefulset.GVK, statefulset.New())
+
+	return initWorkloadPlugins(mgr, Workloads)
+}
+
+// WorkloadPluginFactory builds the workload accessor of a custom workload.
+type WorkloadPluginFactory func(mgr manager.Manager) (workload.Accessor, error)
+
+var (
+	pluginLock      sync.Mutex
+	workloadPlugins = map[schema.GroupVersionKind]WorkloadPluginFactory{}
+)
+
+// RegisterWorkloadPlugin registers a custom workload kind. It allows external
+// packages to extend rollout with their own workloads without patching this
+// repository. It must be called before the controller manager is initialized,
+// generally in the init function of the plugin package.
+//
+// The accessor built by factory must implement workload.Accessor, and it can
+// optionally implement workload.CanaryReleaseControl, workload.BatchReleaseControl
+// and workload.PodControl to support canary release, batch release and pod
+// related features. The executor resolves the accessor by the GVK of rollout
+// targets, and skips canary release if workload.CanaryReleaseControl is not
+// implemented. See the interface docs in package workload for the contract.
+//
+// It panics if the GVK is registered twice.
+func RegisterWorkloadPlugin(gvk schema.GroupVersionKind, factory WorkloadPluginFactory) {
+	pluginLock.Lock()
+	defer pluginLock.Unlock()
+
+	if _, ok := workloadPlugins[gvk]; ok {
+		panic(fmt.Sprintf("workload plugin %s is already registered", gvk.String()))
+	}
+	workloadPlugins[gvk] = factory
+}
+
+func initWorkloadPlugins(mgr manager.Manager, registry WorkloadRegistry) (bool, error) {
+	pluginLock.Lock()
+	defer pluginLock.Unlock()
+
+	for gvk, factory := range workloadPlugins {
+		if _, err := registry.Get(gvk); err == nil {
+			return false, fmt.Errorf("workload plugin %s conflicts with a registered workload", gvk.String())
+		}
+		accessor, err := factory(mgr)
+		if err != nil {
+			return false, fmt.Errorf("failed to build workload plugin %s: %w", gvk.String(), err)
+		}
+		if accessor.GroupVersionKind() != gvk {
+			return false, fmt.Errorf("workload plugin %s returns accessor of a different kind %s", gvk.String(), accessor.GroupVersionKind().String())
+		}
+		registry.Register(gvk, accessor)
+	}
 	return true, nil
 }
 
@@ -31,6 +31,7 @@ import (
 	operatingv1alpha1 "kusionstack.io/kube-api/apps/v1alpha1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"kusionstack.io/rollout/pkg/workload"
 	"kusionstack.io/rollout/pkg/workload/collaset"
@@ -336,3 +337,29 @@ func Test_registryImpl_GetOwnersOf(t *testing.T) {
 		})
 	}
 }
+
+func Test_initWorkloadPlugins(t *testing.T) {
+	defer func() {
+		workloadPlugins = map[schema.GroupVersionKind]WorkloadPluginFactory{}
+	}()
+
+	RegisterWorkloadPlugin(daemonsetGVK, func(_ manager.Manager) (workload.Accessor, error) {
+		return newFakeWorkloadAccessor(daemonsetGVK, podGVK), nil
+	})
+	assert.Panics(t, func() {
+		RegisterWorkloadPlugin(daemonsetGVK, nil)
+	})
+
+	r := NewWorkloadRegistry()
+	ok, err := initWorkloadPlugins(nil, r)
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	accessor, err := r.Get(daemonsetGVK)
+	assert.NoError(t, err)
+	assert.Equal(t, daemonsetGVK, accessor.GroupVersionKind())
+
+	// conflicts with registered workload
+	ok, err = initWorkloadPlugins(nil, r)
+	assert.False(t, ok)
+	assert.Error(t, err)
+}
@@ -604,6 +604,11 @@ func (r *RolloutReconciler) handleRunManualCommand(ctx context.Context, obj *rol
 			run.Annotations = make(map[string]string)
 		}
 		run.Annotations[rollout.AnnoManualCommandKey] = command
+		if operator, ok := utils.GetMapValue(obj.Annotations, rollout.AnnoManualCommandBy); ok {
+			run.Annotations[rollout.AnnoManualCommandBy] = operator
+		} else {
+			delete(run.Annotations, rollout.AnnoManualCommandBy)
+		}
 		return nil
 	})
 	return err
@@ -613,6 +618,7 @@ func (r *RolloutReconciler) cleanupAnnotation(ctx context.Context, obj *rolloutv
 	// delete manual command annotations from rollout
 	_, err := utils.UpdateOnConflict(clusterinfo.WithCluster(ctx, clusterinfo.Fed), r.Client, r.Client, obj, func() error {
 		delete(obj.Annotations, rollout.AnnoManualCommandKey)
+		delete(obj.Annotations, rollout.AnnoManualCommandBy)
 		delete(obj.Annotations, rollout.AnnoRolloutTrigger)
 		if features.DefaultFeatureGate.Enabled(features.OneTimeStrategy) {
 			delete(obj.Annotations, ontimestrategy.AnnoOneTimeStrategy)
@@ -113,16 +113,54 @@ func constructRolloutRunCanary(strategy *rolloutv1alpha1.CanaryStrategy, workloa
 				Cluster: info.ClusterName,
 				Name:    info.Name,
 			},
-			Replicas: strategy.Replicas,
+			Replicas:                strategy.Replicas,
+			ReadinessTimeoutSeconds: strategy.ReadinessTimeoutSeconds,
+			CanaryNamespace:         strategy.CanaryNamespace,
+			ConfigOverrides:         strategy.ConfigOverrides,
+			InitContainerOverrides:  strategy.InitContainerOverrides,
+			OwnerReferences:         strategy.OwnerReferences,
+			PodDisruptionBudget:     strategy.PodDisruptionBudget,
 		}
 		targets = append(targets, target)
 	}
 
 	step := &rolloutv1alpha1.RolloutRunCanaryStrategy{
-		Targets:                  targets,
-		Traffic:                  strategy.Traffic,
-		Properties:               strategy.Properties,
-		PodTemplateMetadataPatch: strategy.PodTemplateMetadataPatch,
+		Targets:                           targets,
+		Strategy:                          strategy.Strategy,
+		Traffic:                           strategy.Traffic,
+		Variants:                          strategy.Variants,
+		Properties:                        strategy.Properties,
+		PodTemplateMetadataPatch:          strategy.PodTemplateMetadataPatch,
+		ObjectMetadataPatch:               strategy.ObjectMetadataPatch,
+		PodPlacementPatch:                 strategy.PodPlacementPatch,
+		PromotionWindows:                  strategy.PromotionWindows,
+		Analysis:                          strategy.Analysis,
+		PauseAfter:                        strategy.PauseAfter,
+		PauseBefore:                       strategy.PauseBefore,
+		HoldAtCanary:                      strategy.HoldAtCanary,
+		RecycleOrder:                      strategy.RecycleOrder,
+		RetentionSeconds:                  strategy.RetentionSeconds,
+		CrashLoopCheck:                    strategy.CrashLoopCheck,
+		ImagePullCheck:                    strategy.ImagePullCheck,
+		DegradedClusterGracePeriodSeconds: strategy.DegradedClusterGracePeriodSeconds,
+		HealthCheckGracePeriodSeconds:     strategy.HealthCheckGracePeriodSeconds,
+		ReadinessStabilization:            strategy.ReadinessStabilization,
+		ReadinessExpression:               strategy.ReadinessExpression,
+		ReplicasFollowTrafficWeight:       strategy.ReplicasFollowTrafficWeight,
+		MaxActiveDuration:                 strategy.MaxActiveDuration,
+		Notifications:                     strategy.Notifications,
+		Bake:                              strategy.Bake,
+		StableMinAvailable:                strategy.StableMinAvailable,
+		StuckDeletion:                     strategy.StuckDeletion,
+		DriftDetection:                    strategy.DriftDetection,
+		AutoRollback:                      strategy.AutoRollback,
+		Ordinals:                          strategy.Ordinals,
+		UpdateStrategy:                    strategy.UpdateStrategy,
+		ScaleUpStep:                       strategy.ScaleUpStep,
+		WarmUp:                            strategy.WarmUp,
+		PreconditionRefs:                  strategy.PreconditionRefs,
+		Prerequisites:                     strategy.Prerequisites,
+		Guards:                            strategy.Guards,
 	}
 	return step
 }
@@ -157,6 +195,7 @@ func constructRolloutRunBatches(strategy *rolloutv1alpha1.BatchStrategy, workloa
 		step.Breakpoint = b.Breakpoint
 		step.Properties = b.Properties
 		step.Traffic = b.Traffic
+		step.Guards = b.Guards
 		result = append(result, step)
 	}
 	return result
@@ -0,0 +1,142 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rolloutrun
+
+import (
+	"time"
+
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/audit"
+)
+
+// auditRecords returns the audit records of the changes from oldStatus to
+// newStatus in a reconcile, command and operator are the manual command
+// applied in the reconcile and who issued it.
+func auditRecords(obj *rolloutv1alpha1.RolloutRun, ownerName string, oldStatus, newStatus *rolloutv1alpha1.RolloutRunStatus, command, operator string, now time.Time) []*audit.Record {
+	newRecord := func(recordType audit.RecordType) *audit.Record {
+		return &audit.Record{
+			Time:          now,
+			Type:          recordType,
+			Namespace:     obj.Namespace,
+			Name:          obj.Name,
+			UID:           string(obj.UID),
+			Rollout:       ownerName,
+			Phase:         newStatus.Phase,
+			Step:          progressStep(newStatus),
+			TrafficWeight: appliedTrafficWeight(obj, newStatus),
+			Error:         newStatus.Error,
+		}
+	}
+
+	records := make([]*audit.Record, 0)
+	if len(command) > 0 {
+		record := newRecord(audit.RecordManualCommand)
+		record.Command = command
+		record.Operator = operator
+		records = append(records, record)
+	}
+
+	oldWebhooks := map[string]rolloutv1alpha1.RolloutWebhookStatus{}
+	for _, w := range stepWebhooks(oldStatus) {
+		oldWebhooks[string(w.HookType)+"/"+w.Name] = w
+	}
+	for _, w := range stepWebhooks(newStatus) {
+		if w.State != rolloutv1alpha1.WebhookCompleted {
+			continue
+		}
+		if old, ok := oldWebhooks[string(w.HookType)+"/"+w.Name]; ok && old.State == rolloutv1alpha1.WebhookCompleted {
+			continue
+		}
+		record := newRecord(audit.RecordWebhookVerdict)
+		record.Webhook = &audit.WebhookVerdict{
+			Name:     w.Name,
+			HookType: w.HookType,
+			Code:     w.Code,
+			Reason:   w.Reason,
+			Message:  w.Message,
+		}
+		records = append(records, record)
+	}
+
+	if prevStep, step := progressStep(oldStatus), progressStep(newStatus); prevStep != step {
+		record := newRecord(audit.RecordStepTransition)
+		record.PreviousStep = prevStep
+		records = append(records, record)
+	}
+
+	if outcome := outcomeOf(newStatus); len(outcome) > 0 && outcome != outcomeOf(oldStatus) {
+		record := newRecord(audit.RecordOutcome)
+		record.Outcome = outcome
+		records = append(records, record)
+	}
+	return records
+}
+
+// stepWebhooks returns the webhook statuses of the current step.
+func stepWebhooks(status *rolloutv1alpha1.RolloutRunStatus) []rolloutv1alpha1.RolloutWebhookStatus {
+	if status.BatchStatus != nil && len(status.BatchStatus.CurrentBatchState) > 0 {
+		index := int(status.BatchStatus.CurrentBatchIndex)
+		if index < len(status.BatchStatus.Records) {
+			return status.BatchStatus.Records[index].Webhooks
+		}
+		return nil
+	}
+	if status.CanaryStatus != nil {
+		return status.CanaryStatus.Webhooks
+	}
+	return nil
+}
+
+// appliedTrafficWeight returns the canary traffic weight of the current step,
+// the reduced weights of paused traffic and revert ramp take precedence over
+// the weight in spec.
+func appliedTrafficWeight(obj *rolloutv1alpha1.RolloutRun, status *rolloutv1alpha1.RolloutRunStatus) *int32 {
+	if status.BatchStatus != nil && len(status.BatchStatus.CurrentBatchState) > 0 {
+		if obj.Spec.Batch == nil {
+			return nil
+		}
+		index := int(status.BatchStatus.CurrentBatchIndex)
+		if index >= len(obj.Spec.Batch.Batches) || obj.Spec.Batch.Batches[index].Traffic == nil {
+			return nil
+		}
+		return obj.Spec.Batch.Batches[index].Traffic.Weight
+	}
+	if status.CanaryStatus == nil || obj.Spec.Canary == nil || obj.Spec.Canary.Traffic == nil {
+		return nil
+	}
+	if status.CanaryStatus.PausedTraffic != nil {
+		return ptr.To(status.CanaryStatus.PausedTraffic.Weight)
+	}
+	if status.CanaryStatus.RevertRamp != nil {
+		return ptr.To(status.CanaryStatus.RevertRamp.Weight)
+	}
+	return obj.Spec.Canary.Traffic.Weight
+}
+
+func outcomeOf(status *rolloutv1alpha1.RolloutRunStatus) audit.Outcome {
+	switch {
+	case status.Phase == rolloutv1alpha1.RolloutRunPhaseSucceeded:
+		return audit.OutcomeSucceeded
+	case status.Phase == rolloutv1alpha1.RolloutRunPhaseCanceled:
+		return audit.OutcomeCanceled
+	case status.Error != nil:
+		return audit.OutcomeFailed
+	}
+	return ""
+}
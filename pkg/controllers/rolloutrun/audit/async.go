@@ -0,0 +1,136 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// DefaultBufferSize is the number of records buffered by AsyncSink.
+const DefaultBufferSize = 1024
+
+var droppedRecords = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "rolloutrun_audit_records_dropped_total",
+	Help: "Number of RolloutRun audit records dropped because the audit sink is too slow.",
+})
+
+func init() {
+	metrics.Registry.MustRegister(droppedRecords)
+}
+
+// AsyncSink buffers records and writes them to the underlying sink in
+// background, so that a slow sink never blocks reconciliation. Records are
+// dropped and counted in metric rolloutrun_audit_records_dropped_total if the
+// buffer is full.
+//
+// AsyncSink is a manager Runnable, it drains the buffered records into the
+// underlying sink when the manager stops.
+type AsyncSink struct {
+	sink    Sink
+	logger  logr.Logger
+	records chan *Record
+	done    chan struct{}
+
+	lock   sync.RWMutex
+	closed bool
+}
+
+// NewAsyncSink returns an AsyncSink of sink and starts writing in background.
+func NewAsyncSink(sink Sink, bufferSize int, logger logr.Logger) *AsyncSink {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	s := &AsyncSink{
+		sink:    sink,
+		logger:  logger,
+		records: make(chan *Record, bufferSize),
+		done:    make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Write enqueues the record without blocking. Records written after the sink
+// is closed are dropped.
+func (s *AsyncSink) Write(record *Record) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if s.closed {
+		droppedRecords.Inc()
+		s.logger.Info("audit sink is closed, drop record", "rolloutRun", record.Namespace+"/"+record.Name, "type", record.Type)
+		return nil
+	}
+	select {
+	case s.records <- record:
+	default:
+		droppedRecords.Inc()
+		s.logger.Info("audit sink is too slow, drop record", "rolloutRun", record.Namespace+"/"+record.Name, "type", record.Type)
+	}
+	return nil
+}
+
+// Start implements manager.Runnable, it closes the sink when ctx is done.
+func (s *AsyncSink) Start(ctx context.Context) error {
+	<-ctx.Done()
+	return s.Close()
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, the sink is
+// closed on shutdown whether or not the manager is the leader.
+func (s *AsyncSink) NeedLeaderElection() bool {
+	return false
+}
+
+// Close stops accepting records, waits until the buffered records are written
+// and closes the underlying sink if it is an io.Closer.
+func (s *AsyncSink) Close() error {
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.records)
+	s.lock.Unlock()
+
+	<-s.done
+	if closer, ok := s.sink.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (s *AsyncSink) run() {
+	defer close(s.done)
+	for record := range s.records {
+		s.write(record)
+	}
+}
+
+func (s *AsyncSink) write(record *Record) {
+	defer runtime.HandleCrash()
+	if err := s.sink.Write(record); err != nil {
+		s.logger.Error(err, "failed to write audit record", "rolloutRun", record.Namespace+"/"+record.Name, "type", record.Type)
+	}
+}
@@ -0,0 +1,98 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package audit exports the decisions and outcomes of RolloutRuns to audit
+// sinks, e.g. a file of JSON lines, so that an immutable record of rollouts is
+// kept outside the cluster.
+package audit
+
+import (
+	"time"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+// RecordType is the type of audit record.
+type RecordType string
+
+const (
+	// RecordManualCommand is recorded when a manual command is applied to rolloutRun.
+	RecordManualCommand RecordType = "ManualCommand"
+	// RecordStepTransition is recorded when rolloutRun moves to another step state.
+	RecordStepTransition RecordType = "StepTransition"
+	// RecordWebhookVerdict is recorded when a webhook completes with a verdict.
+	RecordWebhookVerdict RecordType = "WebhookVerdict"
+	// RecordOutcome is recorded when rolloutRun succeeds, fails or is canceled.
+	RecordOutcome RecordType = "Outcome"
+)
+
+// Outcome is the terminal outcome of rolloutRun.
+type Outcome string
+
+const (
+	OutcomeSucceeded Outcome = "Succeeded"
+	OutcomeFailed    Outcome = "Failed"
+	OutcomeCanceled  Outcome = "Canceled"
+)
+
+// Record is an audit record of rolloutRun.
+type Record struct {
+	// Time is when the record is observed.
+	Time time.Time `json:"time"`
+	// Type is the type of record.
+	Type RecordType `json:"type"`
+	// Namespace, Name and UID identify the rolloutRun.
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	UID       string `json:"uid"`
+	// Rollout is the name of rollout owning the rolloutRun.
+	Rollout string `json:"rollout,omitempty"`
+	// Phase is the phase of rolloutRun.
+	Phase rolloutv1alpha1.RolloutRunPhase `json:"phase,omitempty"`
+	// Step is the current step, e.g. canary/Running or batch-1/Running.
+	Step string `json:"step,omitempty"`
+	// PreviousStep is the step before transition, only set in StepTransition.
+	PreviousStep string `json:"previousStep,omitempty"`
+	// TrafficWeight is the canary traffic weight applied in the current step.
+	TrafficWeight *int32 `json:"trafficWeight,omitempty"`
+	// Command is the manual command, only set in ManualCommand.
+	Command string `json:"command,omitempty"`
+	// Operator is who issued the manual command, it is taken from annotation
+	// rollout.kusionstack.io/manual-command-by.
+	Operator string `json:"operator,omitempty"`
+	// Webhook is the verdict of webhook, only set in WebhookVerdict.
+	Webhook *WebhookVerdict `json:"webhook,omitempty"`
+	// Outcome is the terminal outcome, only set in Outcome.
+	Outcome Outcome `json:"outcome,omitempty"`
+	// Error is the error of rolloutRun.
+	Error *rolloutv1alpha1.CodeReasonMessage `json:"error,omitempty"`
+}
+
+// WebhookVerdict is the result of a completed webhook.
+type WebhookVerdict struct {
+	Name     string                   `json:"name"`
+	HookType rolloutv1alpha1.HookType `json:"hookType"`
+	Code     string                   `json:"code"`
+	Reason   string                   `json:"reason,omitempty"`
+	Message  string                   `json:"message,omitempty"`
+}
+
+// Sink receives audit records. Sinks are called from a single goroutine of
+// AsyncSink and never block reconciliation, they should return an error instead
+// of retrying forever.
+type Sink interface {
+	Write(record *Record) error
+}
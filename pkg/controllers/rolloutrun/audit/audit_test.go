@@ -0,0 +1,124 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_jsonLinesSink(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sink := NewJSONLinesSink(buf)
+	assert.NoError(t, sink.Write(&Record{Type: RecordOutcome, Name: "run-1", Outcome: OutcomeSucceeded}))
+	assert.NoError(t, sink.Write(&Record{Type: RecordManualCommand, Name: "run-2", Command: "pause"}))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if assert.Len(t, lines, 2) {
+		record := Record{}
+		assert.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+		assert.Equal(t, "run-2", record.Name)
+		assert.Equal(t, "pause", record.Command)
+	}
+}
+
+type blockingSink struct {
+	lock    sync.Mutex
+	release chan struct{}
+	written []string
+}
+
+func (s *blockingSink) Write(record *Record) error {
+	<-s.release
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.written = append(s.written, record.Name)
+	return nil
+}
+
+func Test_AsyncSink(t *testing.T) {
+	sink := &blockingSink{release: make(chan struct{})}
+	async := NewAsyncSink(sink, 1, logr.Discard())
+
+	// the slow sink never blocks the writer, records beyond the buffer are dropped
+	done := make(chan struct{})
+	go func() {
+		for _, name := range []string{"run-1", "run-2", "run-3", "run-4"} {
+			assert.NoError(t, async.Write(&Record{Name: name}))
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("write is blocked by slow sink")
+	}
+
+	close(sink.release)
+	assert.Eventually(t, func() bool {
+		sink.lock.Lock()
+		defer sink.lock.Unlock()
+		return len(sink.written) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	sink.lock.Lock()
+	assert.LessOrEqual(t, len(sink.written), 2)
+	assert.Equal(t, "run-1", sink.written[0])
+	sink.lock.Unlock()
+}
+
+type closingSink struct {
+	written []string
+	closed  bool
+}
+
+func (s *closingSink) Write(record *Record) error {
+	s.written = append(s.written, record.Name)
+	return nil
+}
+
+func (s *closingSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func Test_AsyncSink_Start(t *testing.T) {
+	sink := &closingSink{}
+	async := NewAsyncSink(sink, 10, logr.Discard())
+	for _, name := range []string{"run-1", "run-2", "run-3"} {
+		assert.NoError(t, async.Write(&Record{Name: name}))
+	}
+
+	// the buffered records are drained when manager stops
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.NoError(t, async.Start(ctx))
+	assert.Equal(t, []string{"run-1", "run-2", "run-3"}, sink.written)
+	assert.True(t, sink.closed)
+
+	// records are dropped after closed
+	assert.NoError(t, async.Write(&Record{Name: "run-4"}))
+	assert.NoError(t, async.Close())
+	assert.Len(t, sink.written, 3)
+}
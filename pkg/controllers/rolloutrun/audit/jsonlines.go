@@ -0,0 +1,68 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// StdoutPath is the path of file sink writing to stdout.
+const StdoutPath = "-"
+
+type jsonLinesSink struct {
+	lock    sync.Mutex
+	encoder *json.Encoder
+	closer  io.Closer
+}
+
+// NewJSONLinesSink returns a sink writing each record as a line of JSON to w.
+func NewJSONLinesSink(w io.Writer) Sink {
+	return &jsonLinesSink{encoder: json.NewEncoder(w)}
+}
+
+func (s *jsonLinesSink) Write(record *Record) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.encoder.Encode(record)
+}
+
+// Close closes the file of sink, it does nothing for other writers.
+func (s *jsonLinesSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// NewFileSink returns a sink appending JSON lines to the file of path, or
+// writing to stdout if path is "-".
+func NewFileSink(path string) (Sink, error) {
+	if path == StdoutPath {
+		return NewJSONLinesSink(os.Stdout), nil
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit file %s: %w", path, err)
+	}
+	return &jsonLinesSink{encoder: json.NewEncoder(file), closer: file}, nil
+}
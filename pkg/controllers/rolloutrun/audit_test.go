@@ -0,0 +1,94 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rolloutrun
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/audit"
+)
+
+func Test_auditRecords(t *testing.T) {
+	now := time.Now()
+	obj := &rolloutv1alpha1.RolloutRun{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "run-1", UID: "uid-1"},
+		Spec: rolloutv1alpha1.RolloutRunSpec{
+			Canary: &rolloutv1alpha1.RolloutRunCanaryStrategy{
+				Traffic: &rolloutv1alpha1.TrafficStrategy{Weight: ptr.To[int32](20)},
+			},
+		},
+	}
+	oldStatus := &rolloutv1alpha1.RolloutRunStatus{
+		Phase: rolloutv1alpha1.RolloutRunPhaseProgressing,
+		CanaryStatus: &rolloutv1alpha1.RolloutRunStepStatus{
+			State: rolloutv1alpha1.RolloutStepPreCanaryStepHook,
+			Webhooks: []rolloutv1alpha1.RolloutWebhookStatus{
+				{Name: "approval", HookType: rolloutv1alpha1.PreCanaryStepHook, State: rolloutv1alpha1.WebhookRunning},
+			},
+		},
+	}
+	newStatus := oldStatus.DeepCopy()
+	newStatus.CanaryStatus.State = rolloutv1alpha1.RolloutStepRunning
+	newStatus.CanaryStatus.Webhooks[0].State = rolloutv1alpha1.WebhookCompleted
+	newStatus.CanaryStatus.Webhooks[0].CodeReasonMessage = rolloutv1alpha1.CodeReasonMessage{Code: rolloutv1alpha1.WebhookReviewCodeOK, Reason: "Approved"}
+
+	records := auditRecords(obj, "rollout-1", oldStatus, newStatus, "continue", "alice", now)
+	if assert.Len(t, records, 3) {
+		assert.Equal(t, audit.RecordManualCommand, records[0].Type)
+		assert.Equal(t, "continue", records[0].Command)
+		assert.Equal(t, "alice", records[0].Operator)
+		assert.Equal(t, "rollout-1", records[0].Rollout)
+
+		assert.Equal(t, audit.RecordWebhookVerdict, records[1].Type)
+		assert.Equal(t, &audit.WebhookVerdict{Name: "approval", HookType: rolloutv1alpha1.PreCanaryStepHook, Code: rolloutv1alpha1.WebhookReviewCodeOK, Reason: "Approved"}, records[1].Webhook)
+
+		assert.Equal(t, audit.RecordStepTransition, records[2].Type)
+		assert.Equal(t, "canary/PreCanaryStepHook", records[2].PreviousStep)
+		assert.Equal(t, "canary/Running", records[2].Step)
+		assert.Equal(t, ptr.To[int32](20), records[2].TrafficWeight)
+	}
+
+	// nothing changed
+	assert.Empty(t, auditRecords(obj, "rollout-1", newStatus, newStatus, "", "", now))
+
+	// failed with the reduced weight of paused traffic
+	failedStatus := newStatus.DeepCopy()
+	failedStatus.CanaryStatus.PausedTraffic = &rolloutv1alpha1.PausedTrafficStatus{PrePauseWeight: 20, Weight: 5}
+	failedStatus.Error = &rolloutv1alpha1.CodeReasonMessage{Code: "DoCanaryError", Reason: "AnalysisFailed"}
+	records = auditRecords(obj, "rollout-1", newStatus, failedStatus, "", "", now)
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, audit.RecordOutcome, records[0].Type)
+		assert.Equal(t, audit.OutcomeFailed, records[0].Outcome)
+		assert.Equal(t, failedStatus.Error, records[0].Error)
+		assert.Equal(t, ptr.To[int32](5), records[0].TrafficWeight)
+	}
+
+	// canceled after failure
+	canceledStatus := failedStatus.DeepCopy()
+	canceledStatus.Phase = rolloutv1alpha1.RolloutRunPhaseCanceled
+	records = auditRecords(obj, "rollout-1", failedStatus, canceledStatus, "cancel", "", now)
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, audit.RecordManualCommand, records[0].Type)
+		assert.Equal(t, audit.OutcomeCanceled, records[1].Outcome)
+	}
+}
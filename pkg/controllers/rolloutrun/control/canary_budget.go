@@ -0,0 +1,105 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package control
+
+import (
+	"context"
+	"fmt"
+
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"kusionstack.io/kube-utils/multicluster/clusterinfo"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+const ReasonUnsatisfiablePodDisruptionBudget = "UnsatisfiablePodDisruptionBudget"
+
+// WithPodDisruptionBudget returns a copy of control which creates a
+// PodDisruptionBudget for the pods of canary workloads.
+func (c *CanaryReleaseControl) WithPodDisruptionBudget(budget *v1alpha1.CanaryPodDisruptionBudget) *CanaryReleaseControl {
+	copied := *c
+	copied.budget = budget
+	return &copied
+}
+
+// ensurePodDisruptionBudget creates or updates the PodDisruptionBudget of canary
+// workload, it has the same name and namespace, and selects the pods by the
+// labels of canary pod template. It is owned by canary workload, so that it is
+// garbage collected even if recycle is skipped. A terminal error is returned if
+// the budget does not allow any canary pod to be evicted.
+func (c *CanaryReleaseControl) ensurePodDisruptionBudget(ctx context.Context, canary *workload.Info, replicas int32) error {
+	if c.budget == nil {
+		return nil
+	}
+	pc, ok := c.workload.(workload.PodControl)
+	if !ok {
+		return nil
+	}
+
+	disruptions, err := c.budget.AllowedDisruptions(replicas)
+	if err != nil {
+		return err
+	}
+	if disruptions == 0 {
+		return TerminalError(&v1alpha1.CodeReasonMessage{
+			Code:    "DoCanaryError",
+			Reason:  ReasonUnsatisfiablePodDisruptionBudget,
+			Message: fmt.Sprintf("pod disruption budget of canary %s does not allow any of %d replicas to be evicted", canary.Name, replicas),
+		})
+	}
+
+	template, err := pc.GetPodTemplate(canary.Object)
+	if err != nil {
+		return err
+	}
+	gvk := c.workload.GroupVersionKind()
+	owner := metav1.OwnerReference{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Name:       canary.Name,
+		UID:        canary.Object.GetUID(),
+	}
+
+	pdb := &policyv1.PodDisruptionBudget{}
+	pdb.Name = canary.Name
+	pdb.Namespace = canary.Namespace
+	_, err = controllerutil.CreateOrUpdate(ctx, c.client, pdb, func() error {
+		pdb.OwnerReferences = []metav1.OwnerReference{owner}
+		pdb.Spec.MinAvailable = c.budget.MinAvailable
+		pdb.Spec.MaxUnavailable = c.budget.MaxUnavailable
+		pdb.Spec.Selector = &metav1.LabelSelector{MatchLabels: template.Labels}
+		return nil
+	})
+	return err
+}
+
+// deletePodDisruptionBudget deletes the PodDisruptionBudget of the canary
+// workload of stable.
+func (c *CanaryReleaseControl) deletePodDisruptionBudget(ctx context.Context, stable *workload.Info) error {
+	if c.budget == nil {
+		return nil
+	}
+	pdb := &policyv1.PodDisruptionBudget{}
+	pdb.Name = c.getCanaryName(stable.Name)
+	pdb.Namespace = c.canaryNamespace(stable)
+	err := c.client.Delete(clusterinfo.WithCluster(ctx, stable.ClusterName), pdb)
+	return client.IgnoreNotFound(err)
+}
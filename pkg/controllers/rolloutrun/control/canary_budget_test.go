@@ -0,0 +1,71 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package control
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/workload/statefulset"
+)
+
+func Test_CanaryReleaseControl_PodDisruptionBudget(t *testing.T) {
+	stable := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "default"}}
+	stable.Spec.Replicas = ptr.To[int32](10)
+	stable.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "demo"}}
+	stable.Spec.Template.Labels = map[string]string{"app": "demo"}
+
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(stable).Build()
+	accessor := statefulset.New()
+	info, _ := accessor.GetInfo("", stable)
+	info.Status.Replicas = 10
+	patch := &rolloutv1alpha1.MetadataPatch{Labels: map[string]string{"canary": "true"}}
+	key := client.ObjectKey{Namespace: "default", Name: "demo-canary"}
+
+	// the budget blocks the eviction of the only canary pod
+	control := NewCanaryReleaseControl(accessor, c).WithPodDisruptionBudget(&rolloutv1alpha1.CanaryPodDisruptionBudget{
+		MinAvailable: ptr.To(intstr.FromString("50%")),
+	})
+	_, _, _, err := control.CreateOrUpdate(context.TODO(), info, intstr.FromInt(1), patch, nil)
+	assert.True(t, errors.Is(err, TerminalError(nil)))
+	assert.ErrorContains(t, err, ReasonUnsatisfiablePodDisruptionBudget)
+
+	_, canaryInfo, _, err := control.CreateOrUpdate(context.TODO(), info, intstr.FromString("20%"), patch, nil)
+	assert.NoError(t, err)
+	pdb := &policyv1.PodDisruptionBudget{}
+	assert.NoError(t, c.Get(context.TODO(), key, pdb))
+	assert.Equal(t, ptr.To(intstr.FromString("50%")), pdb.Spec.MinAvailable)
+	assert.Equal(t, map[string]string{"app": "demo", "canary": "true"}, pdb.Spec.Selector.MatchLabels)
+	assert.Equal(t, canaryInfo.Object.GetUID(), pdb.OwnerReferences[0].UID)
+
+	// the budget is deleted with canary workload
+	assert.NoError(t, control.Finalize(info))
+	assert.True(t, apierrors.IsNotFound(c.Get(context.TODO(), key, &policyv1.PodDisruptionBudget{})))
+}
@@ -0,0 +1,122 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package control
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/utils"
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+// WithConfigOverrides returns a copy of control which substitutes the configs
+// referenced by the pod template of canary workloads by overrides.
+func (c *CanaryReleaseControl) WithConfigOverrides(overrides []v1alpha1.CanaryConfigOverride) *CanaryReleaseControl {
+	copied := *c
+	copied.configOverrides = overrides
+	return &copied
+}
+
+// applyConfigOverrides substitutes the ConfigMaps and Secrets referenced by the
+// pod template of canary object, and returns the substituted fields.
+func (c *CanaryReleaseControl) applyConfigOverrides(canaryObj client.Object) ([]utils.FieldDiff, error) {
+	if len(c.configOverrides) == 0 {
+		return nil, nil
+	}
+	podControl, ok := c.workload.(workload.PodControl)
+	if !ok {
+		return nil, TerminalError(fmt.Errorf("config overrides are not supported by workload %s", c.workload.GroupVersionKind().Kind))
+	}
+	template, err := podControl.GetPodTemplate(canaryObj)
+	if err != nil {
+		return nil, err
+	}
+	return substituteConfigRefs(&template.Spec, c.configOverrides), nil
+}
+
+// substituteConfigRefs substitutes the config references in volumes, envFrom
+// and env of containers in pod spec.
+func substituteConfigRefs(spec *corev1.PodSpec, overrides []v1alpha1.CanaryConfigOverride) []utils.FieldDiff {
+	var diffs []utils.FieldDiff
+	substitute := func(kind v1alpha1.CanaryConfigKind, name *string, path string) {
+		for _, override := range overrides {
+			if override.Kind == kind && override.Name == *name {
+				diffs = append(diffs, utils.FieldDiff{Path: path, From: *name, To: override.CanaryName})
+				*name = override.CanaryName
+				return
+			}
+		}
+	}
+
+	for i := range spec.Volumes {
+		volume := &spec.Volumes[i]
+		path := fmt.Sprintf(".spec.template.spec.volumes[%d]", i)
+		if volume.ConfigMap != nil {
+			substitute(v1alpha1.CanaryConfigMap, &volume.ConfigMap.Name, path+".configMap.name")
+		}
+		if volume.Secret != nil {
+			substitute(v1alpha1.CanaryConfigSecret, &volume.Secret.SecretName, path+".secret.secretName")
+		}
+		if volume.Projected != nil {
+			for j := range volume.Projected.Sources {
+				source := &volume.Projected.Sources[j]
+				sourcePath := fmt.Sprintf("%s.projected.sources[%d]", path, j)
+				if source.ConfigMap != nil {
+					substitute(v1alpha1.CanaryConfigMap, &source.ConfigMap.Name, sourcePath+".configMap.name")
+				}
+				if source.Secret != nil {
+					substitute(v1alpha1.CanaryConfigSecret, &source.Secret.Name, sourcePath+".secret.name")
+				}
+			}
+		}
+	}
+
+	substituteContainers := func(containers []corev1.Container, field string) {
+		for i := range containers {
+			container := &containers[i]
+			path := fmt.Sprintf(".spec.template.spec.%s[%d]", field, i)
+			for j := range container.EnvFrom {
+				envFrom := &container.EnvFrom[j]
+				if envFrom.ConfigMapRef != nil {
+					substitute(v1alpha1.CanaryConfigMap, &envFrom.ConfigMapRef.Name, fmt.Sprintf("%s.envFrom[%d].configMapRef.name", path, j))
+				}
+				if envFrom.SecretRef != nil {
+					substitute(v1alpha1.CanaryConfigSecret, &envFrom.SecretRef.Name, fmt.Sprintf("%s.envFrom[%d].secretRef.name", path, j))
+				}
+			}
+			for j := range container.Env {
+				valueFrom := container.Env[j].ValueFrom
+				if valueFrom == nil {
+					continue
+				}
+				if valueFrom.ConfigMapKeyRef != nil {
+					substitute(v1alpha1.CanaryConfigMap, &valueFrom.ConfigMapKeyRef.Name, fmt.Sprintf("%s.env[%d].valueFrom.configMapKeyRef.name", path, j))
+				}
+				if valueFrom.SecretKeyRef != nil {
+					substitute(v1alpha1.CanaryConfigSecret, &valueFrom.SecretKeyRef.Name, fmt.Sprintf("%s.env[%d].valueFrom.secretKeyRef.name", path, j))
+				}
+			}
+		}
+	}
+	substituteContainers(spec.InitContainers, "initContainers")
+	substituteContainers(spec.Containers, "containers")
+	return diffs
+}
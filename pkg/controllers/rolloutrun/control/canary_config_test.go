@@ -0,0 +1,87 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package control
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/workload/statefulset"
+)
+
+func Test_CanaryReleaseControl_WithConfigOverrides(t *testing.T) {
+	stable := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "default"}}
+	stable.Spec.Replicas = ptr.To[int32](10)
+	stable.Spec.Template.Labels = map[string]string{"app": "demo"}
+	stable.Spec.Template.Spec = corev1.PodSpec{
+		Volumes: []corev1.Volume{
+			{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"}}}},
+			{Name: "tls", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "app-tls"}}},
+		},
+		Containers: []corev1.Container{{
+			Name: "main",
+			EnvFrom: []corev1.EnvFromSource{
+				{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"}}},
+				// secret of the same name is not substituted by ConfigMap override
+				{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"}}},
+			},
+		}},
+	}
+	stable.Status.Replicas = 10
+
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(stable).Build()
+	accessor := statefulset.New()
+	info, _ := accessor.GetInfo("", stable)
+	control := NewCanaryReleaseControl(accessor, c).WithConfigOverrides([]rolloutv1alpha1.CanaryConfigOverride{
+		{Kind: rolloutv1alpha1.CanaryConfigMap, Name: "app-config", CanaryName: "app-config-canary"},
+		{Kind: rolloutv1alpha1.CanaryConfigSecret, Name: "app-tls", CanaryName: "app-tls-canary"},
+	})
+
+	result, _, diff, err := control.CreateOrUpdate(context.TODO(), info, intstr.FromInt(1), nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, controllerutil.OperationResultCreated, result)
+	assert.Len(t, diff, 3)
+	assert.Equal(t, ".spec.template.spec.volumes[0].configMap.name", diff[0].Path)
+
+	canary := &appsv1.StatefulSet{}
+	assert.NoError(t, c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "demo-canary"}, canary))
+	spec := canary.Spec.Template.Spec
+	assert.Equal(t, "app-config-canary", spec.Volumes[0].ConfigMap.Name)
+	assert.Equal(t, "app-tls-canary", spec.Volumes[1].Secret.SecretName)
+	assert.Equal(t, "app-config-canary", spec.Containers[0].EnvFrom[0].ConfigMapRef.Name)
+	assert.Equal(t, "app-config", spec.Containers[0].EnvFrom[1].SecretRef.Name)
+
+	// stable is untouched
+	assert.Equal(t, "app-config", info.Object.(*appsv1.StatefulSet).Spec.Template.Spec.Volumes[0].ConfigMap.Name)
+
+	// substitution is idempotent
+	result, _, _, err = control.CreateOrUpdate(context.TODO(), info, intstr.FromInt(1), nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, controllerutil.OperationResultNone, result)
+}
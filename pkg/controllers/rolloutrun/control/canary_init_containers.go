@@ -0,0 +1,78 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package control
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"kusionstack.io/rollout/pkg/utils"
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+// WithInitContainerOverrides returns a copy of control which merges the
+// overrides into the init containers of canary pod template by name.
+func (c *CanaryReleaseControl) WithInitContainerOverrides(containers []corev1.Container) *CanaryReleaseControl {
+	copied := *c
+	copied.initContainers = containers
+	return &copied
+}
+
+// applyInitContainerOverrides merges the init container overrides into the pod
+// template of canary object, and returns the merged fields.
+func (c *CanaryReleaseControl) applyInitContainerOverrides(canaryObj client.Object) ([]utils.FieldDiff, error) {
+	if len(c.initContainers) == 0 {
+		return nil, nil
+	}
+	podControl, ok := c.workload.(workload.PodControl)
+	if !ok {
+		return nil, TerminalError(fmt.Errorf("init container overrides are not supported by workload %s", c.workload.GroupVersionKind().Kind))
+	}
+	template, err := podControl.GetPodTemplate(canaryObj)
+	if err != nil {
+		return nil, err
+	}
+	return mergeInitContainers(&template.Spec, c.initContainers), nil
+}
+
+// mergeInitContainers replaces the init containers in pod spec with the
+// overrides of the same name in place, and appends the others in order.
+func mergeInitContainers(spec *corev1.PodSpec, overrides []corev1.Container) []utils.FieldDiff {
+	var diffs []utils.FieldDiff
+	for _, override := range overrides {
+		found := false
+		for i := range spec.InitContainers {
+			if spec.InitContainers[i].Name != override.Name {
+				continue
+			}
+			found = true
+			if !equality.Semantic.DeepEqual(spec.InitContainers[i], override) {
+				diffs = append(diffs, utils.FieldDiff{Path: fmt.Sprintf(".spec.template.spec.initContainers[%d]", i), From: spec.InitContainers[i], To: override})
+				spec.InitContainers[i] = *override.DeepCopy()
+			}
+			break
+		}
+		if !found {
+			diffs = append(diffs, utils.FieldDiff{Path: fmt.Sprintf(".spec.template.spec.initContainers[%d]", len(spec.InitContainers)), To: override})
+			spec.InitContainers = append(spec.InitContainers, *override.DeepCopy())
+		}
+	}
+	return diffs
+}
@@ -0,0 +1,81 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package control
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"kusionstack.io/rollout/pkg/workload/statefulset"
+)
+
+func Test_CanaryReleaseControl_WithInitContainerOverrides(t *testing.T) {
+	stable := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "default"}}
+	stable.Spec.Replicas = ptr.To[int32](10)
+	stable.Spec.Template.Labels = map[string]string{"app": "demo"}
+	stable.Spec.Template.Spec = corev1.PodSpec{
+		InitContainers: []corev1.Container{
+			{Name: "setup", Image: "setup:v1"},
+			{Name: "wait", Image: "wait:v1"},
+		},
+		Containers: []corev1.Container{{Name: "main", Image: "main:v1"}},
+	}
+	stable.Status.Replicas = 10
+
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(stable).Build()
+	accessor := statefulset.New()
+	info, _ := accessor.GetInfo("", stable)
+	control := NewCanaryReleaseControl(accessor, c).WithInitContainerOverrides([]corev1.Container{
+		{Name: "wait", Image: "wait:v2"},
+		{Name: "migrate", Image: "migrate:v1"},
+	})
+
+	result, _, diff, err := control.CreateOrUpdate(context.TODO(), info, intstr.FromInt(1), nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, controllerutil.OperationResultCreated, result)
+	assert.Len(t, diff, 2)
+	assert.Equal(t, ".spec.template.spec.initContainers[1]", diff[0].Path)
+	assert.Equal(t, ".spec.template.spec.initContainers[2]", diff[1].Path)
+	assert.Nil(t, diff[1].From)
+
+	canary := &appsv1.StatefulSet{}
+	assert.NoError(t, c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "demo-canary"}, canary))
+	initContainers := canary.Spec.Template.Spec.InitContainers
+	assert.Len(t, initContainers, 3)
+	assert.Equal(t, "setup:v1", initContainers[0].Image)
+	assert.Equal(t, "wait:v2", initContainers[1].Image)
+	assert.Equal(t, "migrate", initContainers[2].Name)
+
+	// stable is untouched
+	assert.Len(t, info.Object.(*appsv1.StatefulSet).Spec.Template.Spec.InitContainers, 2)
+
+	// merging is idempotent
+	result, _, _, err = control.CreateOrUpdate(context.TODO(), info, intstr.FromInt(1), nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, controllerutil.OperationResultNone, result)
+}
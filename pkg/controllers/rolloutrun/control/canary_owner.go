@@ -0,0 +1,54 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package control
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// WithOwnerReferences returns a copy of control which adds owners to the owner
+// references of canary workloads.
+func (c *CanaryReleaseControl) WithOwnerReferences(owners []metav1.OwnerReference) *CanaryReleaseControl {
+	copied := *c
+	copied.owners = owners
+	return &copied
+}
+
+// applyOwnerReferences adds the owners to canary object, replacing the existing
+// references with the same UID. References added by others are kept, so it is
+// safe to apply it to an existing canary object again.
+func (c *CanaryReleaseControl) applyOwnerReferences(canaryObj client.Object) {
+	if len(c.owners) == 0 {
+		return
+	}
+	refs := canaryObj.GetOwnerReferences()
+	for _, owner := range c.owners {
+		found := false
+		for i := range refs {
+			if refs[i].UID == owner.UID {
+				refs[i] = owner
+				found = true
+				break
+			}
+		}
+		if !found {
+			refs = append(refs, owner)
+		}
+	}
+	canaryObj.SetOwnerReferences(refs)
+}
@@ -0,0 +1,44 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package control
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_applyOwnerReferences(t *testing.T) {
+	rolloutRun := metav1.OwnerReference{APIVersion: "rollout.kusionstack.io/v1alpha1", Kind: "RolloutRun", Name: "run-1", UID: "uid-run"}
+	costCenter := metav1.OwnerReference{APIVersion: "cost.example.com/v1", Kind: "CostCenter", Name: "team-a", UID: "uid-cost"}
+	other := metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "other", UID: "uid-other"}
+
+	obj := &appsv1.StatefulSet{}
+	obj.SetOwnerReferences([]metav1.OwnerReference{other})
+
+	c := (&CanaryReleaseControl{}).WithOwnerReferences([]metav1.OwnerReference{rolloutRun, costCenter})
+	c.applyOwnerReferences(obj)
+	assert.Equal(t, []metav1.OwnerReference{other, rolloutRun, costCenter}, obj.GetOwnerReferences())
+
+	// applying again replaces the references of the same owners
+	costCenter.Name = "team-b"
+	c = c.WithOwnerReferences([]metav1.OwnerReference{rolloutRun, costCenter})
+	c.applyOwnerReferences(obj)
+	assert.Equal(t, []metav1.OwnerReference{other, rolloutRun, costCenter}, obj.GetOwnerReferences())
+}
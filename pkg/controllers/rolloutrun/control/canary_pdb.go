@@ -0,0 +1,153 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package control
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"kusionstack.io/kube-utils/multicluster/clusterinfo"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rolloutapi "kusionstack.io/rollout/apis/rollout"
+	"kusionstack.io/rollout/pkg/utils"
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+// canaryExcludedRequirement excludes pods with canary label from a selector.
+var canaryExcludedRequirement = metav1.LabelSelectorRequirement{
+	Key:      rolloutapi.LabelCanary,
+	Operator: metav1.LabelSelectorOpDoesNotExist,
+}
+
+// excludeCanaryFromPDBs changes the selectors of PodDisruptionBudgets selecting
+// stable pods to exclude canary pods. Canary pods carry the same labels as stable
+// pods, so they are counted in the disruption budget of stable otherwise.
+//
+// HorizontalPodAutoscaler is not changed. It targets the stable workload by name
+// so canary workload is never scaled by it, but it still collects metrics from
+// pods by the selector of stable workload, which is immutable.
+func (c *CanaryReleaseControl) excludeCanaryFromPDBs(ctx context.Context, stable *workload.Info) error {
+	pdbs, err := c.stablePDBs(ctx, stable)
+	if err != nil {
+		return err
+	}
+	for i := range pdbs {
+		pdb := &pdbs[i]
+		owners := canaryExcludedBy(pdb)
+		if lo.Contains(owners, stable.Name) {
+			continue
+		}
+		if len(owners) == 0 && lo.ContainsBy(pdb.Spec.Selector.MatchExpressions, isCanaryExcludedRequirement) {
+			// canary pods are already excluded by user
+			continue
+		}
+		if len(owners) == 0 {
+			pdb.Spec.Selector.MatchExpressions = append(pdb.Spec.Selector.MatchExpressions, canaryExcludedRequirement)
+		}
+		setCanaryExcludedBy(pdb, append(owners, stable.Name))
+		if err := c.client.Update(clusterinfo.WithCluster(ctx, stable.ClusterName), pdb); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreCanaryFromPDBs restores the selectors changed by excludeCanaryFromPDBs
+// once no other stable workload selected by the PodDisruptionBudget is in canary.
+func (c *CanaryReleaseControl) restoreCanaryFromPDBs(ctx context.Context, stable *workload.Info) error {
+	pdbs, err := c.stablePDBs(ctx, stable)
+	if err != nil {
+		return err
+	}
+	for i := range pdbs {
+		pdb := &pdbs[i]
+		owners := canaryExcludedBy(pdb)
+		if !lo.Contains(owners, stable.Name) {
+			continue
+		}
+		owners = lo.Without(owners, stable.Name)
+		if len(owners) == 0 {
+			pdb.Spec.Selector.MatchExpressions = lo.Reject(pdb.Spec.Selector.MatchExpressions, func(r metav1.LabelSelectorRequirement, _ int) bool {
+				return isCanaryExcludedRequirement(r)
+			})
+			if len(pdb.Spec.Selector.MatchExpressions) == 0 {
+				pdb.Spec.Selector.MatchExpressions = nil
+			}
+		}
+		setCanaryExcludedBy(pdb, owners)
+		if err := c.client.Update(clusterinfo.WithCluster(ctx, stable.ClusterName), pdb); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stablePDBs returns the PodDisruptionBudgets selecting the pods of stable.
+func (c *CanaryReleaseControl) stablePDBs(ctx context.Context, stable *workload.Info) ([]policyv1.PodDisruptionBudget, error) {
+	pc, ok := c.workload.(workload.PodControl)
+	if !ok {
+		return nil, nil
+	}
+	template, err := pc.GetPodTemplate(stable.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	pdbs := &policyv1.PodDisruptionBudgetList{}
+	if err := c.client.List(clusterinfo.WithCluster(ctx, stable.ClusterName), pdbs, client.InNamespace(stable.Namespace)); err != nil {
+		return nil, err
+	}
+	return lo.Filter(pdbs.Items, func(pdb policyv1.PodDisruptionBudget, _ int) bool {
+		if pdb.Spec.Selector == nil {
+			return false
+		}
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			return false
+		}
+		return selector.Matches(labels.Set(template.Labels))
+	}), nil
+}
+
+func isCanaryExcludedRequirement(r metav1.LabelSelectorRequirement) bool {
+	return r.Key == canaryExcludedRequirement.Key && r.Operator == canaryExcludedRequirement.Operator
+}
+
+func canaryExcludedBy(pdb *policyv1.PodDisruptionBudget) []string {
+	value := pdb.Annotations[rolloutapi.AnnoCanaryExcludedBy]
+	if len(value) == 0 {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+func setCanaryExcludedBy(pdb *policyv1.PodDisruptionBudget, owners []string) {
+	utils.MutateAnnotations(pdb, func(annotations map[string]string) {
+		if len(owners) == 0 {
+			delete(annotations, rolloutapi.AnnoCanaryExcludedBy)
+			return
+		}
+		sort.Strings(owners)
+		annotations[rolloutapi.AnnoCanaryExcludedBy] = strings.Join(owners, ",")
+	})
+}
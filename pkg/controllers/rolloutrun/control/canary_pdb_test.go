@@ -0,0 +1,86 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package control
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	rolloutapi "kusionstack.io/rollout/apis/rollout"
+	"kusionstack.io/rollout/pkg/workload/statefulset"
+)
+
+func newTestPDB(name string, matchLabels map[string]string) *policyv1.PodDisruptionBudget {
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: matchLabels},
+		},
+	}
+}
+
+func Test_CanaryReleaseControl_PDBExclusion(t *testing.T) {
+	newStable := func(name string) *appsv1.StatefulSet {
+		sts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+		sts.Spec.Template.Labels = map[string]string{"app": "demo", "name": name}
+		return sts
+	}
+	stableA, stableB := newStable("a"), newStable("b")
+	shared := newTestPDB("shared", map[string]string{"app": "demo"})
+	other := newTestPDB("other", map[string]string{"app": "other"})
+
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(shared, other).Build()
+	accessor := statefulset.New()
+	control := NewCanaryReleaseControl(accessor, c)
+	infoA, _ := accessor.GetInfo("", stableA)
+	infoB, _ := accessor.GetInfo("", stableB)
+
+	get := func(name string) *policyv1.PodDisruptionBudget {
+		pdb := &policyv1.PodDisruptionBudget{}
+		assert.NoError(t, c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: name}, pdb))
+		return pdb
+	}
+
+	assert.NoError(t, control.excludeCanaryFromPDBs(context.TODO(), infoA))
+	assert.NoError(t, control.excludeCanaryFromPDBs(context.TODO(), infoB))
+	// exclusion is idempotent
+	assert.NoError(t, control.excludeCanaryFromPDBs(context.TODO(), infoA))
+
+	pdb := get("shared")
+	assert.Equal(t, "a,b", pdb.Annotations[rolloutapi.AnnoCanaryExcludedBy])
+	assert.Equal(t, []metav1.LabelSelectorRequirement{canaryExcludedRequirement}, pdb.Spec.Selector.MatchExpressions)
+	assert.Empty(t, get("other").Spec.Selector.MatchExpressions)
+
+	// selector is kept until all stables are recycled
+	assert.NoError(t, control.restoreCanaryFromPDBs(context.TODO(), infoA))
+	pdb = get("shared")
+	assert.Equal(t, "b", pdb.Annotations[rolloutapi.AnnoCanaryExcludedBy])
+	assert.Len(t, pdb.Spec.Selector.MatchExpressions, 1)
+
+	assert.NoError(t, control.restoreCanaryFromPDBs(context.TODO(), infoB))
+	pdb = get("shared")
+	assert.NotContains(t, pdb.Annotations, rolloutapi.AnnoCanaryExcludedBy)
+	assert.Empty(t, pdb.Spec.Selector.MatchExpressions)
+}
@@ -0,0 +1,137 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package control
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+// WithPodPlacement returns a copy of control which merges the placement patch
+// into the pod template of canary workloads.
+func (c *CanaryReleaseControl) WithPodPlacement(patch *v1alpha1.PodPlacementPatch) *CanaryReleaseControl {
+	copied := *c
+	copied.podPlacement = patch
+	return &copied
+}
+
+// applyPodPlacement merges the placement patch into the pod template of canary
+// object. The merge is idempotent, so it is safe to apply it to an existing
+// canary object again.
+func (c *CanaryReleaseControl) applyPodPlacement(canaryObj client.Object) error {
+	if c.podPlacement == nil {
+		return nil
+	}
+	podControl, ok := c.workload.(workload.PodControl)
+	if !ok {
+		return TerminalError(fmt.Errorf("pod placement patch is not supported by workload %s", c.workload.GroupVersionKind().Kind))
+	}
+	template, err := podControl.GetPodTemplate(canaryObj)
+	if err != nil {
+		return err
+	}
+	mergePodPlacement(&template.Spec, c.podPlacement)
+	return nil
+}
+
+// mergePodPlacement merges patch into spec, see PodPlacementPatch for details.
+func mergePodPlacement(spec *corev1.PodSpec, patch *v1alpha1.PodPlacementPatch) {
+	patch = patch.DeepCopy()
+	if patch.Affinity != nil {
+		if spec.Affinity == nil {
+			spec.Affinity = &corev1.Affinity{}
+		}
+		mergeNodeAffinity(spec.Affinity, patch.Affinity.NodeAffinity)
+		if pa := patch.Affinity.PodAffinity; pa != nil {
+			if spec.Affinity.PodAffinity == nil {
+				spec.Affinity.PodAffinity = &corev1.PodAffinity{}
+			}
+			target := spec.Affinity.PodAffinity
+			target.RequiredDuringSchedulingIgnoredDuringExecution = appendMissing(target.RequiredDuringSchedulingIgnoredDuringExecution, pa.RequiredDuringSchedulingIgnoredDuringExecution...)
+			target.PreferredDuringSchedulingIgnoredDuringExecution = appendMissing(target.PreferredDuringSchedulingIgnoredDuringExecution, pa.PreferredDuringSchedulingIgnoredDuringExecution...)
+		}
+		if paa := patch.Affinity.PodAntiAffinity; paa != nil {
+			if spec.Affinity.PodAntiAffinity == nil {
+				spec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+			}
+			target := spec.Affinity.PodAntiAffinity
+			target.RequiredDuringSchedulingIgnoredDuringExecution = appendMissing(target.RequiredDuringSchedulingIgnoredDuringExecution, paa.RequiredDuringSchedulingIgnoredDuringExecution...)
+			target.PreferredDuringSchedulingIgnoredDuringExecution = appendMissing(target.PreferredDuringSchedulingIgnoredDuringExecution, paa.PreferredDuringSchedulingIgnoredDuringExecution...)
+		}
+	}
+
+	for _, constraint := range patch.TopologySpreadConstraints {
+		replaced := false
+		for i := range spec.TopologySpreadConstraints {
+			if spec.TopologySpreadConstraints[i].TopologyKey == constraint.TopologyKey {
+				spec.TopologySpreadConstraints[i] = constraint
+				replaced = true
+			}
+		}
+		if !replaced {
+			spec.TopologySpreadConstraints = append(spec.TopologySpreadConstraints, constraint)
+		}
+	}
+}
+
+// mergeNodeAffinity merges the required node selector term of patch into each
+// existing term, and adds the preferred terms of patch.
+func mergeNodeAffinity(affinity *corev1.Affinity, patch *corev1.NodeAffinity) {
+	if patch == nil {
+		return
+	}
+	if affinity.NodeAffinity == nil {
+		affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	target := affinity.NodeAffinity
+	if required := patch.RequiredDuringSchedulingIgnoredDuringExecution; required != nil && len(required.NodeSelectorTerms) > 0 {
+		term := required.NodeSelectorTerms[0]
+		if target.RequiredDuringSchedulingIgnoredDuringExecution == nil || len(target.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms) == 0 {
+			target.RequiredDuringSchedulingIgnoredDuringExecution = required.DeepCopy()
+		} else {
+			terms := target.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+			for i := range terms {
+				terms[i].MatchExpressions = appendMissing(terms[i].MatchExpressions, term.MatchExpressions...)
+				terms[i].MatchFields = appendMissing(terms[i].MatchFields, term.MatchFields...)
+			}
+		}
+	}
+	target.PreferredDuringSchedulingIgnoredDuringExecution = appendMissing(target.PreferredDuringSchedulingIgnoredDuringExecution, patch.PreferredDuringSchedulingIgnoredDuringExecution...)
+}
+
+// appendMissing appends the items not semantically equal to any item of list.
+func appendMissing[T any](list []T, items ...T) []T {
+	for _, item := range items {
+		found := false
+		for i := range list {
+			if equality.Semantic.DeepEqual(list[i], item) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,95 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package control
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rolloutapi "kusionstack.io/rollout/apis/rollout"
+	"kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+func Test_mergePodPlacement(t *testing.T) {
+	zoneTerm := corev1.NodeSelectorRequirement{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a", "b"}}
+	poolTerm := corev1.NodeSelectorRequirement{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"canary"}}
+	awayFromStable := corev1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels:      map[string]string{"app": "test"},
+			MatchExpressions: []metav1.LabelSelectorRequirement{{Key: rolloutapi.LabelCanary, Operator: metav1.LabelSelectorOpDoesNotExist}},
+		},
+		TopologyKey: "kubernetes.io/hostname",
+	}
+	spec := &corev1.PodSpec{
+		Affinity: &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{
+						{MatchExpressions: []corev1.NodeSelectorRequirement{zoneTerm}},
+						{MatchFields: []corev1.NodeSelectorRequirement{{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"node-1"}}}},
+					},
+				},
+			},
+		},
+		TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+			{MaxSkew: 1, TopologyKey: "zone", WhenUnsatisfiable: corev1.ScheduleAnyway},
+		},
+	}
+	patch := &v1alpha1.PodPlacementPatch{
+		Affinity: &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{poolTerm}}},
+				},
+			},
+			PodAntiAffinity: &corev1.PodAntiAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{awayFromStable},
+			},
+		},
+		TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+			{MaxSkew: 1, TopologyKey: "zone", WhenUnsatisfiable: corev1.DoNotSchedule},
+			{MaxSkew: 2, TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: corev1.ScheduleAnyway},
+		},
+	}
+
+	mergePodPlacement(spec, patch)
+	merged := spec.DeepCopy()
+	// merge is idempotent
+	mergePodPlacement(spec, patch)
+	assert.Equal(t, merged, spec)
+
+	// required node selector term is merged into each existing term
+	terms := spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	assert.Len(t, terms, 2)
+	assert.Equal(t, []corev1.NodeSelectorRequirement{zoneTerm, poolTerm}, terms[0].MatchExpressions)
+	assert.Equal(t, []corev1.NodeSelectorRequirement{poolTerm}, terms[1].MatchExpressions)
+	assert.Len(t, terms[1].MatchFields, 1)
+
+	assert.Equal(t, []corev1.PodAffinityTerm{awayFromStable}, spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+	assert.Nil(t, spec.Affinity.PodAffinity)
+
+	// constraint of the same topologyKey is replaced
+	assert.Equal(t, patch.TopologySpreadConstraints, spec.TopologySpreadConstraints)
+
+	// pod without affinity gets the patch
+	empty := &corev1.PodSpec{}
+	mergePodPlacement(empty, patch)
+	assert.Equal(t, patch.Affinity, empty.Affinity)
+}
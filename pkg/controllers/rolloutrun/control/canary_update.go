@@ -0,0 +1,182 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package control
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+// WithUpdateStrategy returns a copy of control which turns over the pods of
+// canary workloads by strategy when they are updated to a new revision.
+func (c *CanaryReleaseControl) WithUpdateStrategy(strategy *v1alpha1.CanaryUpdateStrategy) *CanaryReleaseControl {
+	copied := *c
+	copied.updateStrategy = strategy
+	return &copied
+}
+
+// applyUpdateStrategy applies the update strategy to canary object, so that
+// its pods are only turned over by rollout.
+func (c *CanaryReleaseControl) applyUpdateStrategy(canaryObj client.Object) error {
+	if c.updateStrategy == nil {
+		return nil
+	}
+	control, ok := c.workload.(workload.CanaryUpdateStrategyControl)
+	if !ok {
+		return TerminalError(fmt.Errorf("canary update strategy is not supported by workload %s", c.workload.GroupVersionKind().Kind))
+	}
+	if _, ok := c.workload.(workload.PodControl); !ok {
+		return TerminalError(fmt.Errorf("canary update strategy is not supported by workload %s", c.workload.GroupVersionKind().Kind))
+	}
+	return control.ApplyCanaryUpdateStrategy(canaryObj, c.updateStrategy)
+}
+
+func (c *CanaryReleaseControl) rollingUpdate() *v1alpha1.CanaryRollingUpdateStrategy {
+	if c.updateStrategy == nil || c.updateStrategy.Type == v1alpha1.CanaryRecreate {
+		return nil
+	}
+	if c.updateStrategy.RollingUpdate == nil {
+		return &v1alpha1.CanaryRollingUpdateStrategy{}
+	}
+	return c.updateStrategy.RollingUpdate
+}
+
+// surgeReplicas returns the canary replicas with max surge if the existing
+// canary workload still has outdated pods.
+func (c *CanaryReleaseControl) surgeReplicas(ctx context.Context, cluster string, canaryObj client.Object, replicas int32) (int32, error) {
+	rolling := c.rollingUpdate()
+	if rolling == nil || rolling.MaxSurge == nil {
+		return replicas, nil
+	}
+	info, err := c.workload.GetInfo(cluster, canaryObj)
+	if err != nil {
+		return 0, err
+	}
+	outdated, _, err := c.outdatedPods(ctx, info)
+	if err != nil || len(outdated) == 0 {
+		return replicas, err
+	}
+	surge, err := intstr.GetScaledValueFromIntOrPercent(rolling.MaxSurge, int(replicas), true)
+	if err != nil {
+		return 0, err
+	}
+	return replicas + int32(surge), nil
+}
+
+// outdatedPods returns the canary pods not updated to the updated revision,
+// and the number of ready pods which are not being deleted.
+func (c *CanaryReleaseControl) outdatedPods(ctx context.Context, info *workload.Info) ([]*corev1.Pod, int32, error) {
+	if info.Generation != info.Status.ObservedGeneration || info.Status.StableRevision == info.Status.UpdatedRevision {
+		return nil, 0, nil
+	}
+	podControl := c.workload.(workload.PodControl)
+	selector, err := podControl.GetPodSelector(info.Object)
+	if err != nil {
+		return nil, 0, err
+	}
+	pods := &corev1.PodList{}
+	if err := c.client.List(ctx, pods, client.InNamespace(info.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, 0, err
+	}
+
+	var outdated []*corev1.Pod
+	ready := int32(0)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		if isPodReady(pod) {
+			ready++
+		}
+		updated, err := podControl.IsUpdatedPod(c.client, info.Object, pod)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !updated {
+			outdated = append(outdated, pod)
+		}
+	}
+	return outdated, ready, nil
+}
+
+// turnOverPods deletes the outdated canary pods by update strategy, the
+// workload recreates them from the updated revision. Recreate deletes all of
+// them at once, RollingUpdate keeps the ready pods no less than replicas minus
+// max unavailable. The canary info is marked as updating until all pods are
+// updated.
+func (c *CanaryReleaseControl) turnOverPods(ctx context.Context, info *workload.Info, replicas int32) error {
+	if c.updateStrategy == nil {
+		return nil
+	}
+	if info.Generation != info.Status.ObservedGeneration {
+		// the revisions in status are not up to date
+		info.Status.Updating = true
+		return nil
+	}
+	outdated, ready, err := c.outdatedPods(ctx, info)
+	if err != nil || len(outdated) == 0 {
+		return err
+	}
+	info.Status.Updating = true
+
+	budget := len(outdated)
+	if rolling := c.rollingUpdate(); rolling != nil {
+		maxUnavailable := 1
+		if rolling.MaxUnavailable != nil {
+			maxUnavailable, err = intstr.GetScaledValueFromIntOrPercent(rolling.MaxUnavailable, int(replicas), false)
+			if err != nil {
+				return err
+			}
+		}
+		budget = int(ready) - (int(replicas) - maxUnavailable)
+	}
+
+	// the pods not ready are deleted first, they do not reduce availability
+	sort.SliceStable(outdated, func(i, j int) bool {
+		return !isPodReady(outdated[i]) && isPodReady(outdated[j])
+	})
+	for _, pod := range outdated {
+		if isPodReady(pod) {
+			if budget <= 0 {
+				break
+			}
+			budget--
+		}
+		if err := c.client.Delete(ctx, pod); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
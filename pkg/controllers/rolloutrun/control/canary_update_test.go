@@ -0,0 +1,127 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package control
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/workload/statefulset"
+)
+
+func Test_CanaryReleaseControl_WithUpdateStrategy(t *testing.T) {
+	tests := []struct {
+		name         string
+		strategy     *rolloutv1alpha1.CanaryUpdateStrategy
+		wantReplicas int32
+		wantDeleted  int
+	}{
+		{
+			name:         "rolling update",
+			strategy:     &rolloutv1alpha1.CanaryUpdateStrategy{},
+			wantReplicas: 3,
+			wantDeleted:  1,
+		},
+		{
+			name: "rolling update with max surge",
+			strategy: &rolloutv1alpha1.CanaryUpdateStrategy{
+				RollingUpdate: &rolloutv1alpha1.CanaryRollingUpdateStrategy{
+					MaxUnavailable: ptr.To(intstr.FromInt(0)),
+					MaxSurge:       ptr.To(intstr.FromInt(1)),
+				},
+			},
+			// the outdated pods are kept until the surge pod is ready
+			wantReplicas: 4,
+			wantDeleted:  0,
+		},
+		{
+			name:         "recreate",
+			strategy:     &rolloutv1alpha1.CanaryUpdateStrategy{Type: rolloutv1alpha1.CanaryRecreate},
+			wantReplicas: 3,
+			wantDeleted:  3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stable := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "default"}}
+			stable.Spec.Replicas = ptr.To[int32](10)
+			stable.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "demo"}}
+			stable.Spec.Template.Labels = map[string]string{"app": "demo"}
+			stable.Status.Replicas = 10
+
+			c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(stable).Build()
+			accessor := statefulset.New()
+			info, _ := accessor.GetInfo("", stable)
+			control := NewCanaryReleaseControl(accessor, c).WithUpdateStrategy(tt.strategy)
+			patch := &rolloutv1alpha1.MetadataPatch{Labels: map[string]string{"canary": "true"}}
+
+			result, canaryInfo, _, err := control.CreateOrUpdate(context.TODO(), info, intstr.FromInt(3), patch, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, controllerutil.OperationResultCreated, result)
+			assert.False(t, canaryInfo.Status.Updating)
+
+			canary := &appsv1.StatefulSet{}
+			assert.NoError(t, c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "demo-canary"}, canary))
+			assert.Equal(t, appsv1.OnDeleteStatefulSetStrategyType, canary.Spec.UpdateStrategy.Type)
+
+			// canary is updated to a new revision, its pods are outdated
+			canary.Status.ObservedGeneration = canary.Generation
+			canary.Status.CurrentRevision = "v1"
+			canary.Status.UpdateRevision = "v2"
+			assert.NoError(t, c.Update(context.TODO(), canary))
+			for i := 0; i < 3; i++ {
+				pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("demo-canary-%d", i),
+					Namespace: "default",
+					Labels:    map[string]string{"app": "demo", "canary": "true", appsv1.ControllerRevisionHashLabelKey: "v1"},
+				}}
+				pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+				assert.NoError(t, c.Create(context.TODO(), pod))
+			}
+			// the pods of stable are not touched
+			stablePod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:      "demo-0",
+				Namespace: "default",
+				Labels:    map[string]string{"app": "demo", appsv1.ControllerRevisionHashLabelKey: "v0"},
+			}}
+			assert.NoError(t, c.Create(context.TODO(), stablePod))
+
+			_, canaryInfo, _, err = control.CreateOrUpdate(context.TODO(), info, intstr.FromInt(3), patch, nil)
+			assert.NoError(t, err)
+			assert.True(t, canaryInfo.Status.Updating)
+			assert.False(t, canaryInfo.CheckUpdatedReady(3))
+			assert.Equal(t, tt.wantReplicas, canaryInfo.Status.Replicas)
+
+			pods := &corev1.PodList{}
+			assert.NoError(t, c.List(context.TODO(), pods, client.InNamespace("default")))
+			assert.Len(t, pods.Items, 4-tt.wantDeleted)
+		})
+	}
+}
@@ -23,6 +23,8 @@ import (
 	"fmt"
 	"strings"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -35,6 +37,7 @@ import (
 	rolloutapi "kusionstack.io/rollout/apis/rollout"
 	"kusionstack.io/rollout/apis/rollout/v1alpha1"
 	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/features"
 	"kusionstack.io/rollout/pkg/utils"
 	"kusionstack.io/rollout/pkg/workload"
 )
@@ -98,10 +101,35 @@ func (c *BatchReleaseControl) Finalize(workload *workload.Info) error {
 	return err
 }
 
+const (
+	ReasonWorkloadAlreadyCanarying = "WorkloadAlreadyCanarying"
+
+	// CanaryFieldManager is the field manager name used to apply canary resources
+	CanaryFieldManager = "kusionstack-rollout-canary"
+)
+
 type CanaryReleaseControl struct {
 	workload workload.Accessor
 	control  workload.CanaryReleaseControl
 	client   client.Client
+	// namespace is the namespace of canary workload, empty means the namespace
+	// of stable workload.
+	namespace string
+	// variant is the canary variant of canary workload, empty means the canary
+	// workload is not a variant.
+	variant string
+	// configOverrides substitute the configs referenced by canary pod template.
+	configOverrides []v1alpha1.CanaryConfigOverride
+	// initContainers are merged into the init containers of canary pod template.
+	initContainers []corev1.Container
+	// updateStrategy turns over the pods of canary workloads.
+	updateStrategy *v1alpha1.CanaryUpdateStrategy
+	// podPlacement is merged into the pod template of canary workloads.
+	podPlacement *v1alpha1.PodPlacementPatch
+	// owners are added to the owner references of canary workloads.
+	owners []metav1.OwnerReference
+	// budget creates a PodDisruptionBudget for the pods of canary workloads.
+	budget *v1alpha1.CanaryPodDisruptionBudget
 }
 
 func NewCanaryReleaseControl(impl workload.Accessor, client client.Client) *CanaryReleaseControl {
@@ -112,12 +140,44 @@ func NewCanaryReleaseControl(impl workload.Accessor, client client.Client) *Cana
 	}
 }
 
+// InNamespace returns a copy of control which manages canary workloads in
+// namespace, empty namespace means the namespace of stable workload.
+func (c *CanaryReleaseControl) InNamespace(namespace string) *CanaryReleaseControl {
+	copied := *c
+	copied.namespace = namespace
+	return &copied
+}
+
+// WithVariant returns a copy of control which manages the canary workloads of
+// variant, they are named {stableName}-canary-{variant}.
+func (c *CanaryReleaseControl) WithVariant(variant string) *CanaryReleaseControl {
+	copied := *c
+	copied.variant = variant
+	return &copied
+}
+
+func (c *CanaryReleaseControl) canaryNamespace(stable *workload.Info) string {
+	if len(c.namespace) > 0 {
+		return c.namespace
+	}
+	return stable.Namespace
+}
+
 func (c *CanaryReleaseControl) Initialize(stable *workload.Info, ownerKind, ownerName, rolloutRun string) error {
 	// pre check
 	if err := c.control.CanaryPreCheck(stable.Object); err != nil {
 		return TerminalError(err)
 	}
 
+	// check if the workload is already in canary by another rolloutRun
+	if owner, ok := workload.GetCanaryOwner(stable.Object); ok && owner != rolloutRun {
+		return TerminalError(&rolloutv1alpha1.CodeReasonMessage{
+			Code:    "DoCanaryError",
+			Reason:  ReasonWorkloadAlreadyCanarying,
+			Message: fmt.Sprintf("workload %s is already in canary by RolloutRun %s", stable.Name, owner),
+		})
+	}
+
 	// add progressing annotation
 	info := rolloutv1alpha1.ProgressingInfo{
 		Kind:        ownerKind,
@@ -134,17 +194,25 @@ func (c *CanaryReleaseControl) Initialize(stable *workload.Info, ownerKind, owne
 		})
 		return nil
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	if features.DefaultFeatureGate.Enabled(features.CanaryPDBExclusion) {
+		return c.excludeCanaryFromPDBs(context.TODO(), stable)
+	}
+	return nil
 }
 
-func (c *CanaryReleaseControl) Finalize(stable *workload.Info) error {
-	canaryObj, err := c.getCanaryObject(stable.ClusterName, stable.Namespace, stable.Name)
-	if client.IgnoreNotFound(err) != nil {
+// Delete deletes the canary workload of stable and its PodDisruptionBudget, the
+// canary ownership of stable is kept until it is finalized.
+func (c *CanaryReleaseControl) Delete(stable *workload.Info) error {
+	if err := c.deletePodDisruptionBudget(context.TODO(), stable); err != nil {
 		return err
 	}
-	if apierrors.IsNotFound(err) {
-		return nil
+	canaryObj, err := c.getCanaryObject(stable.ClusterName, c.canaryNamespace(stable), stable.Name)
+	if err != nil {
+		return client.IgnoreNotFound(err)
 	}
 
 	err = utils.DeleteWithFinalizer(
@@ -153,12 +221,23 @@ func (c *CanaryReleaseControl) Finalize(stable *workload.Info) error {
 		canaryObj,
 		rolloutapi.FinalizerCanaryResourceProtection,
 	)
-	if client.IgnoreNotFound(err) != nil {
+	return client.IgnoreNotFound(err)
+}
+
+func (c *CanaryReleaseControl) Finalize(stable *workload.Info) error {
+	if err := c.Delete(stable); err != nil {
 		return err
 	}
 
-	// delete progressing annotation
-	_, err = stable.UpdateOnConflict(context.TODO(), c.client, func(obj client.Object) error {
+	if features.DefaultFeatureGate.Enabled(features.CanaryPDBExclusion) {
+		if err := c.restoreCanaryFromPDBs(context.TODO(), stable); err != nil {
+			return err
+		}
+	}
+
+	// delete progressing annotation to release the canary ownership, even if
+	// canary resource is already deleted
+	_, err := stable.UpdateOnConflict(context.TODO(), c.client, func(obj client.Object) error {
 		utils.MutateAnnotations(obj, func(annotations map[string]string) {
 			delete(annotations, rolloutapi.AnnoRolloutProgressingInfo)
 		})
@@ -167,56 +246,305 @@ func (c *CanaryReleaseControl) Finalize(stable *workload.Info) error {
 	return err
 }
 
-func (c *CanaryReleaseControl) CreateOrUpdate(ctx context.Context, stable *workload.Info, replicas intstr.IntOrString, podTemplatePatch *v1alpha1.MetadataPatch) (controllerutil.OperationResult, *workload.Info, error) {
+// IsFinalized returns true if the canary workload of stable is not found.
+func (c *CanaryReleaseControl) IsFinalized(stable *workload.Info) (bool, error) {
+	_, err := c.getCanaryObject(stable.ClusterName, c.canaryNamespace(stable), stable.Name)
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+	return false, err
+}
+
+// GetCanaryObject returns the canary workload object of stable.
+func (c *CanaryReleaseControl) GetCanaryObject(stable *workload.Info) (client.Object, error) {
+	return c.getCanaryObject(stable.ClusterName, c.canaryNamespace(stable), stable.Name)
+}
+
+// ForceFinalize removes all the remaining finalizers of the terminating canary
+// workload of stable, so that it is removed without waiting for them. The
+// removed finalizers are returned.
+func (c *CanaryReleaseControl) ForceFinalize(stable *workload.Info) ([]string, error) {
+	canaryObj, err := c.GetCanaryObject(stable)
+	if err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	if canaryObj.GetDeletionTimestamp() == nil {
+		return nil, fmt.Errorf("canary workload %s/%s is not being deleted", canaryObj.GetNamespace(), canaryObj.GetName())
+	}
+
+	var removed []string
+	ctx := clusterinfo.WithCluster(context.TODO(), stable.ClusterName)
+	_, err = utils.UpdateOnConflict(ctx, c.client, c.client, canaryObj, func() error {
+		removed = canaryObj.GetFinalizers()
+		canaryObj.SetFinalizers(nil)
+		return nil
+	})
+	if err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return removed, nil
+}
+
+// CreateOrUpdate creates or updates the canary workload of stable. If the canary
+// workload is updated, the fields changed by this update are also returned.
+// The objectPatch is applied to the metadata of canary workload object, and the
+// substituted config references are also returned as changed fields when the
+// canary workload is created. No request is sent if the existing canary
+// workload is already up to date. If an update strategy is set, the outdated
+// pods of existing canary workload are turned over by it, and the returned
+// canary info is marked as updating until all pods are updated.
+func (c *CanaryReleaseControl) CreateOrUpdate(ctx context.Context, stable *workload.Info, replicas intstr.IntOrString, podTemplatePatch, objectPatch *v1alpha1.MetadataPatch) (controllerutil.OperationResult, *workload.Info, []utils.FieldDiff, error) {
 	canaryObj, found, err := c.canaryObject(stable)
 	if err != nil {
-		return controllerutil.OperationResultNone, nil, err
+		return controllerutil.OperationResultNone, nil, nil, err
 	}
 
-	cluster := stable.ClusterName
-	ctx = clusterinfo.WithCluster(ctx, cluster)
+	ctx = clusterinfo.WithCluster(ctx, stable.ClusterName)
 
 	canaryReplicas, err := workload.CalculateUpdatedReplicas(&stable.Status.Replicas, replicas)
 	if err != nil {
-		return controllerutil.OperationResultNone, nil, err
+		return controllerutil.OperationResultNone, nil, nil, err
+	}
+	if !found {
+		result, canaryInfo, diff, err := c.createOrUpdate(ctx, stable, canaryObj, found, canaryReplicas, podTemplatePatch, objectPatch)
+		if err != nil {
+			return controllerutil.OperationResultNone, nil, nil, err
+		}
+		if err := c.ensurePodDisruptionBudget(ctx, canaryInfo, canaryReplicas); err != nil {
+			return controllerutil.OperationResultNone, nil, nil, err
+		}
+		return result, canaryInfo, diff, nil
+	}
+
+	surgedReplicas, err := c.surgeReplicas(ctx, stable.ClusterName, canaryObj, canaryReplicas)
+	if err != nil {
+		return controllerutil.OperationResultNone, nil, nil, err
+	}
+	result, canaryInfo, diff, err := c.createOrUpdate(ctx, stable, canaryObj, found, surgedReplicas, podTemplatePatch, objectPatch)
+	if err != nil {
+		return controllerutil.OperationResultNone, nil, nil, err
+	}
+	if err := c.turnOverPods(ctx, canaryInfo, canaryReplicas); err != nil {
+		return controllerutil.OperationResultNone, nil, nil, err
+	}
+	if err := c.ensurePodDisruptionBudget(ctx, canaryInfo, canaryReplicas); err != nil {
+		return controllerutil.OperationResultNone, nil, nil, err
+	}
+	return result, canaryInfo, diff, nil
+}
+
+func (c *CanaryReleaseControl) createOrUpdate(ctx context.Context, stable *workload.Info, canaryObj client.Object, found bool, canaryReplicas int32, podTemplatePatch, objectPatch *v1alpha1.MetadataPatch) (controllerutil.OperationResult, *workload.Info, []utils.FieldDiff, error) {
+	cluster := stable.ClusterName
+
+	if found && c.isUpToDate(canaryObj, canaryReplicas, podTemplatePatch, objectPatch) {
+		canaryInfo, err := c.workload.GetInfo(cluster, canaryObj)
+		if err != nil {
+			return controllerutil.OperationResultNone, nil, nil, err
+		}
+		return controllerutil.OperationResultNone, canaryInfo, nil, nil
+	}
+
+	if features.DefaultFeatureGate.Enabled(features.CanaryServerSideApply) {
+		return c.apply(ctx, stable, canaryObj, found, canaryReplicas, podTemplatePatch, objectPatch)
 	}
 
 	if !found {
 		// create
+		applyObjectMetadataPatch(canaryObj, objectPatch)
 		c.applyCanaryDefaults(canaryObj)
+		c.applyOwnerReferences(canaryObj)
 		c.control.Scale(canaryObj, canaryReplicas)              // nolint
 		c.control.ApplyCanaryPatch(canaryObj, podTemplatePatch) // nolint
-		err := c.client.Create(ctx, canaryObj)
+		diff, err := c.applyInitContainerOverrides(canaryObj)
 		if err != nil {
-			return controllerutil.OperationResultNone, nil, err
+			return controllerutil.OperationResultNone, nil, nil, err
+		}
+		overridden, err := c.applyConfigOverrides(canaryObj)
+		if err != nil {
+			return controllerutil.OperationResultNone, nil, nil, err
+		}
+		diff = append(diff, overridden...)
+		if err := c.applyUpdateStrategy(canaryObj); err != nil {
+			return controllerutil.OperationResultNone, nil, nil, err
+		}
+		if err := c.applyPodPlacement(canaryObj); err != nil {
+			return controllerutil.OperationResultNone, nil, nil, err
+		}
+		err = c.client.Create(ctx, canaryObj)
+		if err != nil {
+			return controllerutil.OperationResultNone, nil, nil, err
 		}
 		canaryInfo, err := c.workload.GetInfo(cluster, canaryObj)
 		if err != nil {
-			return controllerutil.OperationResultNone, nil, err
+			return controllerutil.OperationResultNone, nil, nil, err
 		}
-		return controllerutil.OperationResultCreated, canaryInfo, nil
+		return controllerutil.OperationResultCreated, canaryInfo, diff, nil
 	}
 
 	// update
+	var diff []utils.FieldDiff
 	updated, err := utils.UpdateOnConflict(ctx, c.client, c.client, canaryObj, func() error {
+		existing := canaryObj.DeepCopyObject()
+		applyObjectMetadataPatch(canaryObj, objectPatch)
 		c.applyCanaryDefaults(canaryObj)
+		c.applyOwnerReferences(canaryObj)
 		c.control.Scale(canaryObj, canaryReplicas) // nolint
+		if err := c.control.ApplyCanaryPatch(canaryObj, podTemplatePatch); err != nil {
+			return err
+		}
+		if _, err := c.applyInitContainerOverrides(canaryObj); err != nil {
+			return err
+		}
+		if _, err := c.applyConfigOverrides(canaryObj); err != nil {
+			return err
+		}
+		if err := c.applyUpdateStrategy(canaryObj); err != nil {
+			return err
+		}
+		if err := c.applyPodPlacement(canaryObj); err != nil {
+			return err
+		}
+		// diff is only used for debugging, ignore the error
+		diff, _ = utils.DiffObjects(existing, canaryObj)
 		return nil
 	})
 	if err != nil {
-		return controllerutil.OperationResultNone, nil, err
+		return controllerutil.OperationResultNone, nil, nil, err
 	}
 	canaryInfo, err := c.workload.GetInfo(cluster, canaryObj)
 	if err != nil {
-		return controllerutil.OperationResultNone, nil, err
+		return controllerutil.OperationResultNone, nil, nil, err
 	}
 	if !updated {
-		return controllerutil.OperationResultNone, canaryInfo, nil
+		return controllerutil.OperationResultNone, canaryInfo, nil, nil
 	}
-	return controllerutil.OperationResultUpdated, canaryInfo, nil
+	return controllerutil.OperationResultUpdated, canaryInfo, diff, nil
+}
+
+// isUpToDate returns true if the existing canary object already has the desired
+// replicas and patches. The desired values are applied to a copy of it and
+// compared semantically, so that patches equal in value but not in bytes do not
+// make a request.
+func (c *CanaryReleaseControl) isUpToDate(existing client.Object, replicas int32, podTemplatePatch, objectPatch *v1alpha1.MetadataPatch) bool {
+	desired, ok := existing.DeepCopyObject().(client.Object)
+	if !ok {
+		return false
+	}
+	applyObjectMetadataPatch(desired, objectPatch)
+	c.applyCanaryDefaults(desired)
+	c.applyOwnerReferences(desired)
+	if err := c.control.Scale(desired, replicas); err != nil {
+		return false
+	}
+	if err := c.control.ApplyCanaryPatch(desired, podTemplatePatch); err != nil {
+		return false
+	}
+	if _, err := c.applyInitContainerOverrides(desired); err != nil {
+		return false
+	}
+	if _, err := c.applyConfigOverrides(desired); err != nil {
+		return false
+	}
+	if err := c.applyUpdateStrategy(desired); err != nil {
+		return false
+	}
+	if err := c.applyPodPlacement(desired); err != nil {
+		return false
+	}
+	return equality.Semantic.DeepEqual(existing, desired)
+}
+
+// apply creates or updates the canary workload by server-side apply. The whole
+// desired canary object is applied every time with CanaryFieldManager, so the
+// ownership of canary fields is explicit and conflicts with other managers are
+// returned as errors instead of being overwritten.
+//
+// A canary created or updated by the client-side path before the gate was
+// enabled has its fields owned by the update manager of controller, applying
+// different values to them conflicts with the controller itself. So the first
+// apply on such canary forces the ownership to CanaryFieldManager, and later
+// applies return conflicts as usual.
+func (c *CanaryReleaseControl) apply(ctx context.Context, stable *workload.Info, existing client.Object, found bool, canaryReplicas int32, podTemplatePatch, objectPatch *v1alpha1.MetadataPatch) (controllerutil.OperationResult, *workload.Info, []utils.FieldDiff, error) {
+	desired, err := c.newCanaryObject(stable)
+	if err != nil {
+		return controllerutil.OperationResultNone, nil, nil, err
+	}
+	desired.GetObjectKind().SetGroupVersionKind(c.workload.GroupVersionKind())
+	applyObjectMetadataPatch(desired, objectPatch)
+	c.applyCanaryDefaults(desired)
+	c.applyOwnerReferences(desired)
+	if err := c.control.Scale(desired, canaryReplicas); err != nil {
+		return controllerutil.OperationResultNone, nil, nil, err
+	}
+	if err := c.control.ApplyCanaryPatch(desired, podTemplatePatch); err != nil {
+		return controllerutil.OperationResultNone, nil, nil, err
+	}
+	overridden, err := c.applyInitContainerOverrides(desired)
+	if err != nil {
+		return controllerutil.OperationResultNone, nil, nil, err
+	}
+	substituted, err := c.applyConfigOverrides(desired)
+	if err != nil {
+		return controllerutil.OperationResultNone, nil, nil, err
+	}
+	overridden = append(overridden, substituted...)
+	if err := c.applyUpdateStrategy(desired); err != nil {
+		return controllerutil.OperationResultNone, nil, nil, err
+	}
+	if err := c.applyPodPlacement(desired); err != nil {
+		return controllerutil.OperationResultNone, nil, nil, err
+	}
+
+	opts := []client.PatchOption{client.FieldOwner(CanaryFieldManager)}
+	if found && !isAppliedBy(existing, CanaryFieldManager) {
+		opts = append(opts, client.ForceOwnership)
+	}
+	err = c.client.Patch(ctx, desired, client.Apply, opts...)
+	if err != nil {
+		return controllerutil.OperationResultNone, nil, nil, err
+	}
+	canaryInfo, err := c.workload.GetInfo(stable.ClusterName, desired)
+	if err != nil {
+		return controllerutil.OperationResultNone, nil, nil, err
+	}
+
+	if !found {
+		return controllerutil.OperationResultCreated, canaryInfo, overridden, nil
+	}
+	if existing.GetResourceVersion() == desired.GetResourceVersion() {
+		return controllerutil.OperationResultNone, canaryInfo, nil, nil
+	}
+	return controllerutil.OperationResultUpdated, canaryInfo, diffCanaryObject(existing, desired), nil
+}
+
+// isAppliedBy returns true if obj has fields applied by manager.
+func isAppliedBy(obj client.Object, manager string) bool {
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Manager == manager && entry.Operation == metav1.ManagedFieldsOperationApply {
+			return true
+		}
+	}
+	return false
+}
+
+// diffCanaryObject returns the changed fields of canary object, ignoring the
+// metadata maintained by apiserver.
+func diffCanaryObject(old, new client.Object) []utils.FieldDiff {
+	objs := []client.Object{old.DeepCopyObject().(client.Object), new.DeepCopyObject().(client.Object)}
+	for _, obj := range objs {
+		obj.SetResourceVersion("")
+		obj.SetGeneration(0)
+		obj.SetManagedFields(nil)
+	}
+	// diff is only used for debugging, ignore the error
+	diff, _ := utils.DiffObjects(objs[0], objs[1])
+	return diff
 }
 
 func (c *CanaryReleaseControl) getCanaryName(stableName string) string {
+	if len(c.variant) > 0 {
+		return stableName + "-canary-" + c.variant
+	}
 	return stableName + "-canary"
 }
 
@@ -236,7 +564,7 @@ func (c *CanaryReleaseControl) getCanaryObject(cluster, namespace, name string)
 
 func (c *CanaryReleaseControl) canaryObject(stable *workload.Info) (client.Object, bool, error) {
 	// retrieve canary object
-	canaryObj, err := c.getCanaryObject(stable.ClusterName, stable.Namespace, stable.Name)
+	canaryObj, err := c.getCanaryObject(stable.ClusterName, c.canaryNamespace(stable), stable.Name)
 	if client.IgnoreNotFound(err) != nil {
 		return nil, false, err
 	}
@@ -244,30 +572,66 @@ func (c *CanaryReleaseControl) canaryObject(stable *workload.Info) (client.Objec
 	found := true
 	if apierrors.IsNotFound(err) {
 		found = false
-		// deepcopy object
-		var ok bool
-		canaryObj, ok = stable.Object.DeepCopyObject().(client.Object)
-		if !ok {
-			return nil, false, fmt.Errorf("object can not convert to client.Object")
+		canaryObj, err = c.newCanaryObject(stable)
+		if err != nil {
+			return nil, false, err
 		}
-
-		// cleanup stable metadata
-		canaryObj.SetUID(types.UID(""))
-		canaryObj.SetResourceVersion("")
-		canaryObj.SetSelfLink("")
-		canaryObj.SetGeneration(0)
-		canaryObj.SetCreationTimestamp(metav1.Time{})
-		canaryObj.SetDeletionTimestamp(nil)
-		canaryObj.SetOwnerReferences(nil)
-		canaryObj.SetFinalizers(nil)
-		canaryObj.SetManagedFields(nil)
-		// set canary metadata
-		canaryObj.SetName(c.getCanaryName(stable.Name))
 	}
 
 	return canaryObj, found, nil
 }
 
+// newCanaryObject returns a canary object copied from stable.
+func (c *CanaryReleaseControl) newCanaryObject(stable *workload.Info) (client.Object, error) {
+	// deepcopy object
+	canaryObj, ok := stable.Object.DeepCopyObject().(client.Object)
+	if !ok {
+		return nil, fmt.Errorf("object can not convert to client.Object")
+	}
+
+	// cleanup stable metadata
+	canaryObj.SetUID(types.UID(""))
+	canaryObj.SetResourceVersion("")
+	canaryObj.SetSelfLink("")
+	canaryObj.SetGeneration(0)
+	canaryObj.SetCreationTimestamp(metav1.Time{})
+	canaryObj.SetDeletionTimestamp(nil)
+	canaryObj.SetOwnerReferences(nil)
+	canaryObj.SetFinalizers(nil)
+	canaryObj.SetManagedFields(nil)
+	// the progressing info marks the canary ownership of stable, canary object
+	// should not carry it, otherwise it is left dangling after recycling
+	utils.MutateAnnotations(canaryObj, func(annotations map[string]string) {
+		delete(annotations, rolloutapi.AnnoRolloutProgressingInfo)
+	})
+	// set canary metadata
+	canaryObj.SetNamespace(c.canaryNamespace(stable))
+	canaryObj.SetName(c.getCanaryName(stable.Name))
+	return canaryObj, nil
+}
+
+// applyObjectMetadataPatch applies labels and annotations in patch to the
+// metadata of obj.
+func applyObjectMetadataPatch(obj client.Object, patch *v1alpha1.MetadataPatch) {
+	if patch == nil {
+		return
+	}
+	if len(patch.Labels) > 0 {
+		utils.MutateLabels(obj, func(labels map[string]string) {
+			for k, v := range patch.Labels {
+				labels[k] = v
+			}
+		})
+	}
+	if len(patch.Annotations) > 0 {
+		utils.MutateAnnotations(obj, func(annotations map[string]string) {
+			for k, v := range patch.Annotations {
+				annotations[k] = v
+			}
+		})
+	}
+}
+
 func (c *CanaryReleaseControl) applyCanaryDefaults(canaryObj client.Object) {
 	controllerutil.AddFinalizer(canaryObj, rolloutapi.FinalizerCanaryResourceProtection)
 	utils.MutateLabels(canaryObj, func(labels map[string]string) {
@@ -0,0 +1,350 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package control
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+
+	"kusionstack.io/rollout/pkg/features"
+	"kusionstack.io/rollout/pkg/workload"
+	"kusionstack.io/rollout/pkg/workload/statefulset"
+)
+
+func Test_CanaryReleaseControl_InNamespace(t *testing.T) {
+	stable := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "default"}}
+	stable.Spec.Replicas = ptr.To[int32](10)
+	stable.Spec.Template.Labels = map[string]string{"app": "demo"}
+
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(stable).Build()
+	accessor := statefulset.New()
+	info, _ := accessor.GetInfo("", stable)
+	control := NewCanaryReleaseControl(accessor, c).InNamespace("canary")
+
+	_, canaryInfo, _, err := control.CreateOrUpdate(context.TODO(), info, intstr.FromInt(1), nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "canary", canaryInfo.Namespace)
+	assert.NoError(t, c.Get(context.TODO(), client.ObjectKey{Namespace: "canary", Name: "demo-canary"}, &appsv1.StatefulSet{}))
+
+	// the control without namespace looks for canary in stable namespace
+	finalized, err := NewCanaryReleaseControl(accessor, c).IsFinalized(info)
+	assert.NoError(t, err)
+	assert.True(t, finalized)
+
+	finalized, err = control.IsFinalized(info)
+	assert.NoError(t, err)
+	assert.False(t, finalized)
+
+	assert.NoError(t, control.Finalize(info))
+	finalized, err = control.IsFinalized(info)
+	assert.NoError(t, err)
+	assert.True(t, finalized)
+}
+
+func Test_CanaryReleaseControl_WithVariant(t *testing.T) {
+	stable := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "default"}}
+	stable.Spec.Replicas = ptr.To[int32](10)
+	stable.Spec.Template.Labels = map[string]string{"app": "demo"}
+
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(stable).Build()
+	accessor := statefulset.New()
+	info, _ := accessor.GetInfo("", stable)
+	control := NewCanaryReleaseControl(accessor, c).WithVariant("b")
+
+	_, canaryInfo, _, err := control.CreateOrUpdate(context.TODO(), info, intstr.FromInt(1), nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "demo-canary-b", canaryInfo.Name)
+
+	// the control without variant looks for the canary without variant
+	finalized, err := NewCanaryReleaseControl(accessor, c).IsFinalized(info)
+	assert.NoError(t, err)
+	assert.True(t, finalized)
+
+	assert.NoError(t, control.Delete(info))
+	finalized, err = control.IsFinalized(info)
+	assert.NoError(t, err)
+	assert.True(t, finalized)
+}
+
+// writeCountingClient counts the write requests sent to apiserver.
+type writeCountingClient struct {
+	client.Client
+	writes int
+}
+
+func (c *writeCountingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	c.writes++
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *writeCountingClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	c.writes++
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *writeCountingClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	c.writes++
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func Test_CanaryReleaseControl_CreateOrUpdate_idempotent(t *testing.T) {
+	stable := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "default"}}
+	stable.Spec.Replicas = ptr.To[int32](10)
+	stable.Spec.Template.Labels = map[string]string{"app": "demo"}
+	stable.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "demo"}}
+	stable.Status.Replicas = 10
+
+	c := &writeCountingClient{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(stable).Build()}
+	accessor := statefulset.New()
+	info, _ := accessor.GetInfo("", stable)
+	control := NewCanaryReleaseControl(accessor, c)
+
+	podTemplatePatch := &rolloutv1alpha1.MetadataPatch{Labels: map[string]string{"canary": "true"}}
+	objectPatch := &rolloutv1alpha1.MetadataPatch{Annotations: map[string]string{"owner": "demo"}}
+
+	result, _, _, err := control.CreateOrUpdate(context.TODO(), info, intstr.FromInt(2), podTemplatePatch, objectPatch)
+	assert.NoError(t, err)
+	assert.Equal(t, controllerutil.OperationResultCreated, result)
+	assert.Equal(t, 1, c.writes)
+
+	// reconcile again with semantically equal patches
+	for _, patch := range []*rolloutv1alpha1.MetadataPatch{
+		podTemplatePatch,
+		{Labels: map[string]string{"canary": "true"}, Annotations: map[string]string{}},
+	} {
+		result, canaryInfo, diff, err := control.CreateOrUpdate(context.TODO(), info, intstr.FromString("20%"), patch, objectPatch)
+		assert.NoError(t, err)
+		assert.Equal(t, controllerutil.OperationResultNone, result)
+		assert.Empty(t, diff)
+		assert.Equal(t, "demo-canary", canaryInfo.Name)
+	}
+	assert.Equal(t, 1, c.writes)
+
+	// replicas changed
+	result, _, diff, err := control.CreateOrUpdate(context.TODO(), info, intstr.FromInt(3), podTemplatePatch, objectPatch)
+	assert.NoError(t, err)
+	assert.Equal(t, controllerutil.OperationResultUpdated, result)
+	assert.NotEmpty(t, diff)
+	assert.Equal(t, 2, c.writes)
+
+	// pod template patch changed
+	podTemplatePatch = &rolloutv1alpha1.MetadataPatch{Labels: map[string]string{"canary": "true", "track": "beta"}}
+	result, canaryInfo, diff, err := control.CreateOrUpdate(context.TODO(), info, intstr.FromInt(3), podTemplatePatch, objectPatch)
+	assert.NoError(t, err)
+	assert.Equal(t, controllerutil.OperationResultUpdated, result)
+	assert.NotEmpty(t, diff)
+	assert.Equal(t, 3, c.writes)
+	canary := &appsv1.StatefulSet{}
+	assert.NoError(t, c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: canaryInfo.Name}, canary))
+	assert.Equal(t, "beta", canary.Spec.Template.Labels["track"])
+
+	// the updated patch is up to date
+	result, _, _, err = control.CreateOrUpdate(context.TODO(), info, intstr.FromInt(3), podTemplatePatch, objectPatch)
+	assert.NoError(t, err)
+	assert.Equal(t, controllerutil.OperationResultNone, result)
+	assert.Equal(t, 3, c.writes)
+}
+
+// applyClient serves server-side apply patches, which are not supported by fake
+// client, by creating or updating the applied object, and records the options
+// of every apply.
+type applyClient struct {
+	client.Client
+	applies []*client.PatchOptions
+}
+
+func (c *applyClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if patch.Type() != types.ApplyPatchType {
+		return c.Client.Patch(ctx, obj, patch, opts...)
+	}
+	options := &client.PatchOptions{}
+	options.ApplyOptions(opts)
+	c.applies = append(c.applies, options)
+
+	obj.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: options.FieldManager, Operation: metav1.ManagedFieldsOperationApply}})
+	existing := obj.DeepCopyObject().(client.Object)
+	err := c.Client.Get(ctx, client.ObjectKeyFromObject(obj), existing)
+	if apierrors.IsNotFound(err) {
+		return c.Client.Create(ctx, obj)
+	}
+	if err != nil {
+		return err
+	}
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	return c.Client.Update(ctx, obj)
+}
+
+// failingCanaryPatchAccessor fails to apply canary patch.
+type failingCanaryPatchAccessor struct {
+	workload.Accessor
+}
+
+func (a failingCanaryPatchAccessor) CanaryPreCheck(obj client.Object) error {
+	return a.Accessor.(workload.CanaryReleaseControl).CanaryPreCheck(obj)
+}
+
+func (a failingCanaryPatchAccessor) Scale(obj client.Object, replicas int32) error {
+	return a.Accessor.(workload.CanaryReleaseControl).Scale(obj, replicas)
+}
+
+func (a failingCanaryPatchAccessor) ApplyCanaryPatch(client.Object, *rolloutv1alpha1.MetadataPatch) error {
+	return errors.New("invalid canary patch")
+}
+
+func Test_CanaryReleaseControl_CreateOrUpdate_ServerSideApply(t *testing.T) {
+	newStable := func() *appsv1.StatefulSet {
+		stable := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "default"}}
+		stable.Spec.Replicas = ptr.To[int32](10)
+		stable.Spec.Template.Labels = map[string]string{"app": "demo"}
+		stable.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "demo"}}
+		return stable
+	}
+	podTemplatePatch := &rolloutv1alpha1.MetadataPatch{Labels: map[string]string{"canary": "true"}}
+	accessor := statefulset.New()
+
+	t.Run("create and update by apply", func(t *testing.T) {
+		defer featuregatetesting.SetFeatureGateDuringTest(t, features.DefaultFeatureGate, features.CanaryServerSideApply, true)()
+
+		stable := newStable()
+		c := &applyClient{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(stable).Build()}
+		info, _ := accessor.GetInfo("", stable)
+		control := NewCanaryReleaseControl(accessor, c)
+
+		result, _, _, err := control.CreateOrUpdate(context.TODO(), info, intstr.FromInt(1), podTemplatePatch, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, controllerutil.OperationResultCreated, result)
+
+		result, canaryInfo, _, err := control.CreateOrUpdate(context.TODO(), info, intstr.FromInt(2), podTemplatePatch, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, controllerutil.OperationResultUpdated, result)
+
+		canary := &appsv1.StatefulSet{}
+		assert.NoError(t, c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: canaryInfo.Name}, canary))
+		assert.EqualValues(t, 2, *canary.Spec.Replicas)
+		assert.Equal(t, "true", canary.Spec.Template.Labels["canary"])
+
+		if assert.Len(t, c.applies, 2) {
+			for _, options := range c.applies {
+				assert.Equal(t, CanaryFieldManager, options.FieldManager)
+				// canary applied by CanaryFieldManager is not forced
+				assert.Nil(t, options.Force)
+			}
+		}
+	})
+
+	t.Run("take over canary created by client-side path", func(t *testing.T) {
+		stable := newStable()
+		c := &applyClient{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(stable).Build()}
+		info, _ := accessor.GetInfo("", stable)
+		control := NewCanaryReleaseControl(accessor, c)
+
+		result, _, _, err := control.CreateOrUpdate(context.TODO(), info, intstr.FromInt(1), podTemplatePatch, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, controllerutil.OperationResultCreated, result)
+		assert.Empty(t, c.applies)
+
+		defer featuregatetesting.SetFeatureGateDuringTest(t, features.DefaultFeatureGate, features.CanaryServerSideApply, true)()
+
+		for i, replicas := range []int{2, 3} {
+			result, _, _, err := control.CreateOrUpdate(context.TODO(), info, intstr.FromInt(replicas), podTemplatePatch, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, controllerutil.OperationResultUpdated, result)
+			if !assert.Len(t, c.applies, i+1) {
+				return
+			}
+			options := c.applies[i]
+			assert.Equal(t, CanaryFieldManager, options.FieldManager)
+			if i == 0 {
+				// the first apply forces the ownership of fields owned by update manager
+				assert.Equal(t, ptr.To(true), options.Force)
+			} else {
+				assert.Nil(t, options.Force)
+			}
+		}
+	})
+
+	t.Run("canary patch error", func(t *testing.T) {
+		defer featuregatetesting.SetFeatureGateDuringTest(t, features.DefaultFeatureGate, features.CanaryServerSideApply, true)()
+
+		stable := newStable()
+		c := &applyClient{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(stable).Build()}
+		info, _ := accessor.GetInfo("", stable)
+		control := NewCanaryReleaseControl(failingCanaryPatchAccessor{Accessor: accessor}, c)
+
+		_, _, _, err := control.CreateOrUpdate(context.TODO(), info, intstr.FromInt(1), podTemplatePatch, nil)
+		assert.ErrorContains(t, err, "invalid canary patch")
+		assert.Empty(t, c.applies)
+	})
+}
+
+func Test_CanaryReleaseControl_Initialize_Ownership(t *testing.T) {
+	stable := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "default"}}
+	stable.Spec.Replicas = ptr.To[int32](10)
+	stable.Spec.Template.Labels = map[string]string{"app": "demo"}
+
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(stable).Build()
+	accessor := statefulset.New()
+	control := NewCanaryReleaseControl(accessor, c)
+	getInfo := func() *workload.Info {
+		obj := &appsv1.StatefulSet{}
+		assert.NoError(t, c.Get(context.TODO(), client.ObjectKeyFromObject(stable), obj))
+		info, _ := accessor.GetInfo("", obj)
+		return info
+	}
+
+	assert.NoError(t, control.Initialize(getInfo(), "Rollout", "demo", "run-1"))
+	owner, ok := workload.GetCanaryOwner(getInfo().Object)
+	assert.True(t, ok)
+	assert.Equal(t, "run-1", owner)
+
+	// the same rolloutRun initializes again
+	assert.NoError(t, control.Initialize(getInfo(), "Rollout", "demo", "run-1"))
+
+	// another rolloutRun is rejected
+	err := control.Initialize(getInfo(), "Rollout", "demo", "run-2")
+	assert.True(t, errors.Is(err, TerminalError(nil)))
+	reason := &rolloutv1alpha1.CodeReasonMessage{}
+	if assert.ErrorAs(t, err, &reason) {
+		assert.Equal(t, ReasonWorkloadAlreadyCanarying, reason.Reason)
+	}
+	owner, _ = workload.GetCanaryOwner(getInfo().Object)
+	assert.Equal(t, "run-1", owner)
+
+	// finalize releases the ownership
+	assert.NoError(t, control.Finalize(getInfo()))
+	_, ok = workload.GetCanaryOwner(getInfo().Object)
+	assert.False(t, ok)
+	assert.NoError(t, control.Initialize(getInfo(), "Rollout", "demo", "run-2"))
+	owner, _ = workload.GetCanaryOwner(getInfo().Object)
+	assert.Equal(t, "run-2", owner)
+}
@@ -0,0 +1,145 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package control
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"kusionstack.io/kube-utils/multicluster/clusterinfo"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+// OrdinalCanaryReleaseControl updates pods of specific ordinals of the stable
+// workload in place as canary, instead of creating a canary workload.
+type OrdinalCanaryReleaseControl struct {
+	workload workload.Accessor
+	control  workload.OrdinalCanaryControl
+	client   client.Client
+}
+
+func NewOrdinalCanaryReleaseControl(impl workload.Accessor, client client.Client) *OrdinalCanaryReleaseControl {
+	return &OrdinalCanaryReleaseControl{
+		workload: impl,
+		control:  impl.(workload.OrdinalCanaryControl),
+		client:   client,
+	}
+}
+
+// Apply updates pods of ordinals of stable by partition. It returns whether
+// stable is changed, and a workload info representing the pods of ordinals,
+// whose replicas are the number of ordinals and ready replicas are reported
+// by CheckPartitionReady.
+func (c *OrdinalCanaryReleaseControl) Apply(stable *workload.Info, ordinals []int32) (bool, *workload.Info, error) {
+	// check ordinals on a copy first, they can not be fixed by retrying
+	if err := c.control.ApplyOrdinalPartition(stable.Object.DeepCopyObject().(client.Object), ordinals); err != nil {
+		return false, nil, TerminalError(err)
+	}
+	changed, err := stable.UpdateOnConflict(context.TODO(), c.client, func(obj client.Object) error {
+		return c.control.ApplyOrdinalPartition(obj, ordinals)
+	})
+	if err != nil {
+		return false, nil, err
+	}
+
+	ready, err := c.control.CheckPartitionReady(c.client, stable.Object, ordinals)
+	if err != nil {
+		return false, nil, err
+	}
+	canary := *stable
+	canary.Status = workload.InfoStatus{
+		ObservedGeneration:       stable.Status.ObservedGeneration,
+		StableRevision:           stable.Status.StableRevision,
+		UpdatedRevision:          stable.Status.UpdatedRevision,
+		Replicas:                 int32(len(ordinals)),
+		UpdatedReplicas:          ready,
+		UpdatedReadyReplicas:     ready,
+		UpdatedAvailableReplicas: ready,
+		AvailableReplicas:        ready,
+	}
+	return changed, &canary, nil
+}
+
+// Revert holds all pods of stable by partition, and deletes the updated pods
+// of ordinals so that they are recreated with the stable template.
+func (c *OrdinalCanaryReleaseControl) Revert(stable *workload.Info, ordinals []int32) error {
+	_, err := stable.UpdateOnConflict(context.TODO(), c.client, func(obj client.Object) error {
+		// no ordinals means no pod is updated
+		return c.control.ApplyOrdinalPartition(obj, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	updated, err := c.updatedPods(stable, ordinals)
+	if err != nil {
+		return err
+	}
+	ctx := clusterinfo.WithCluster(context.TODO(), stable.ClusterName)
+	for _, pod := range updated {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		if err := c.client.Delete(ctx, pod); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete canary pod %s: %w", pod.Name, err)
+		}
+	}
+	return nil
+}
+
+// IsReverted returns true if all pods of ordinals exist with the stable template.
+func (c *OrdinalCanaryReleaseControl) IsReverted(stable *workload.Info, ordinals []int32) (bool, error) {
+	pods, err := c.control.GetOrdinalPods(c.client, stable.Object, ordinals)
+	if err != nil {
+		return false, err
+	}
+	if len(pods) < len(ordinals) {
+		// deleted pods are not recreated yet
+		return false, nil
+	}
+	updated, err := c.updatedPods(stable, ordinals)
+	if err != nil {
+		return false, err
+	}
+	return len(updated) == 0, nil
+}
+
+// updatedPods returns the pods of ordinals which are updated or terminating.
+func (c *OrdinalCanaryReleaseControl) updatedPods(stable *workload.Info, ordinals []int32) ([]*corev1.Pod, error) {
+	podControl, ok := c.workload.(workload.PodControl)
+	if !ok {
+		return nil, fmt.Errorf("workload %s does not support pod control", stable.GroupVersionKind)
+	}
+	pods, err := c.control.GetOrdinalPods(c.client, stable.Object, ordinals)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*corev1.Pod, 0)
+	for _, pod := range pods {
+		updated, err := podControl.IsUpdatedPod(c.client, stable.Object, pod)
+		if err != nil {
+			return nil, err
+		}
+		if updated || pod.DeletionTimestamp != nil {
+			result = append(result, pod)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,105 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package control
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"kusionstack.io/rollout/pkg/workload/statefulset"
+)
+
+func newOrdinalPod(sts *appsv1.StatefulSet, ordinal int, revision string, ready bool) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%d", sts.Name, ordinal),
+			Namespace: sts.Namespace,
+			Labels:    map[string]string{"app": "demo", appsv1.ControllerRevisionHashLabelKey: revision},
+		},
+	}
+	if ready {
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+	}
+	return pod
+}
+
+func Test_OrdinalCanaryReleaseControl(t *testing.T) {
+	stable := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "default"}}
+	stable.Spec.Replicas = ptr.To[int32](5)
+	stable.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "demo"}}
+	stable.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+		Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: ptr.To[int32](5)},
+	}
+	stable.Status.CurrentRevision = "v1"
+	stable.Status.UpdateRevision = "v2"
+
+	objs := []client.Object{stable}
+	for i := 0; i < 5; i++ {
+		objs = append(objs, newOrdinalPod(stable, i, "v1", true))
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).Build()
+	accessor := statefulset.New()
+	info, _ := accessor.GetInfo("", stable)
+	control := NewOrdinalCanaryReleaseControl(accessor, c)
+
+	// ordinals not at the end can not be updated by partition
+	_, _, err := control.Apply(info, []int32{1, 2})
+	assert.True(t, errors.Is(err, TerminalError(nil)))
+
+	changed, canary, err := control.Apply(info, []int32{4, 3})
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.EqualValues(t, 2, canary.Status.Replicas)
+	assert.EqualValues(t, 0, canary.Status.UpdatedAvailableReplicas)
+	assert.EqualValues(t, 3, *info.Object.(*appsv1.StatefulSet).Spec.UpdateStrategy.RollingUpdate.Partition)
+
+	// pod 4 is updated and ready
+	assert.NoError(t, c.Update(context.TODO(), newOrdinalPod(stable, 4, "v2", true)))
+	changed, canary, err = control.Apply(info, []int32{3, 4})
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	assert.EqualValues(t, 1, canary.Status.UpdatedAvailableReplicas)
+
+	reverted, err := control.IsReverted(info, []int32{3, 4})
+	assert.NoError(t, err)
+	assert.False(t, reverted)
+
+	// revert holds all pods and deletes the updated one
+	assert.NoError(t, control.Revert(info, []int32{3, 4}))
+	assert.EqualValues(t, 5, *info.Object.(*appsv1.StatefulSet).Spec.UpdateStrategy.RollingUpdate.Partition)
+	reverted, err = control.IsReverted(info, []int32{3, 4})
+	assert.NoError(t, err)
+	assert.False(t, reverted, "deleted pod is not recreated yet")
+
+	// pod 4 is recreated with stable template
+	assert.NoError(t, c.Create(context.TODO(), newOrdinalPod(stable, 4, "v1", true)))
+	reverted, err = control.IsReverted(info, []int32{3, 4})
+	assert.NoError(t, err)
+	assert.True(t, reverted)
+}
@@ -21,11 +21,13 @@ import rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
 const (
 	StepNone               = rolloutv1alpha1.RolloutStepNone
 	StepPending            = rolloutv1alpha1.RolloutStepPending
+	StepPreRunHook         = rolloutv1alpha1.RolloutStepPreRunHook
 	StepPreCanaryStepHook  = rolloutv1alpha1.RolloutStepPreCanaryStepHook
 	StepPreBatchStepHook   = rolloutv1alpha1.RolloutStepPreBatchStepHook
 	StepRunning            = rolloutv1alpha1.RolloutStepRunning
 	StepPostCanaryStepHook = rolloutv1alpha1.RolloutStepPostCanaryStepHook
 	StepPostBatchStepHook  = rolloutv1alpha1.RolloutStepPostBatchStepHook
+	StepHolding            = rolloutv1alpha1.RolloutStepHolding
 	StepSucceeded          = rolloutv1alpha1.RolloutStepSucceeded
 	StepResourceRecycling  = rolloutv1alpha1.RolloutStepResourceRecycling
 )
@@ -31,12 +31,14 @@ import (
 type batchExecutor struct {
 	webhook      webhookExecutor
 	stateMachine *stepStateMachine
+	guards       *stepGuardChecker
 }
 
 func newBatchExecutor(webhook webhookExecutor) *batchExecutor {
 	e := &batchExecutor{
 		webhook:      webhook,
 		stateMachine: newStepStateMachine(),
+		guards:       newStepGuardChecker(),
 	}
 
 	e.stateMachine.add(StepNone, StepPending, e.doPausing)
@@ -62,7 +64,16 @@ func (e *batchExecutor) Do(ctx *ExecutorContext) (done bool, result ctrl.Result,
 		return true, ctrl.Result{Requeue: true}, nil
 	}
 
+	guards := ctx.RolloutRun.Spec.Batch.Batches[currentBatchIndex].Guards
+	if err := e.guards.check(ctx, guards, &newStatus.BatchStatus.Records[currentBatchIndex]); err != nil {
+		return false, ctrl.Result{}, err
+	}
+
 	stepDone, result, err := e.stateMachine.do(ctx, currentState)
+	if len(guards) > 0 && err == nil {
+		// guards are checked on every reconcile
+		result = requeueBefore(result, ctx.Retry.Default)
+	}
 	if err != nil {
 		return false, result, err
 	}
@@ -119,7 +130,11 @@ func (e *batchExecutor) doRecycle(ctx *ExecutorContext) (bool, time.Duration, er
 	}
 
 	if len(finalizeErrs) > 0 {
-		return false, retryDefault, utilerrors.NewAggregate(finalizeErrs)
+		err := utilerrors.NewAggregate(finalizeErrs)
+		return false, retryDefault, &codeReasonError{
+			crm: &rolloutv1alpha1.CodeReasonMessage{Code: "DoBatchError", Reason: "FailedFinalize", Message: err.Error()},
+			err: err,
+		}
 	}
 
 	return true, retryImmediately, nil
@@ -129,6 +144,15 @@ func (e *batchExecutor) doPausing(ctx *ExecutorContext) (bool, time.Duration, er
 	rolloutRunName := ctx.RolloutRun.Name
 	newStatus := ctx.NewStatus
 	currentBatchIndex := newStatus.BatchStatus.CurrentBatchIndex
+
+	if currentBatchIndex == 0 && ctx.RolloutRun.Spec.Canary == nil {
+		// without canary, the first batch is where the rolloutRun starts to
+		// change workloads, gate it by PreRunHook
+		done, retry, err := e.webhook.Do(ctx, rolloutv1alpha1.PreRunHook)
+		if !done {
+			return false, retry, err
+		}
+	}
 	currentBatch := ctx.RolloutRun.Spec.Batch.Batches[currentBatchIndex]
 
 	batchControl := control.NewBatchReleaseControl(ctx.Accessor, ctx.Client)
@@ -177,10 +201,13 @@ func (e *batchExecutor) doBatchUpgrading(ctx *ExecutorContext) (bool, time.Durat
 
 	batchControl := control.NewBatchReleaseControl(ctx.Accessor, ctx.Client)
 
+	// targets rejected by webhooks are not upgraded any more
+	targets := excludeRejectedTargets(ctx, currentBatch.Targets)
+
 	// upgrade partition
 	batchTargetStatuses := make([]rolloutv1alpha1.RolloutWorkloadStatus, 0)
 	workloadChanged := false
-	for _, item := range currentBatch.Targets {
+	for _, item := range targets {
 		wi := ctx.Workloads.Get(item.Cluster, item.Name)
 		if wi == nil {
 			return false, retryStop, newWorkloadNotFoundError(item.CrossClusterObjectNameReference)
@@ -209,7 +236,7 @@ func (e *batchExecutor) doBatchUpgrading(ctx *ExecutorContext) (bool, time.Durat
 	}
 
 	// all workloads are updated now, then check if they are ready
-	for _, item := range currentBatch.Targets {
+	for _, item := range targets {
 		// target will not be nil here
 		info := ctx.Workloads.Get(item.Cluster, item.Name)
 		status := info.APIStatus()
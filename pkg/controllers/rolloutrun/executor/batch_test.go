@@ -527,7 +527,7 @@ func Test_BatchExecutor_Do_Running(t *testing.T) {
 			},
 			assertResult: func(assert *assert.Assertions, done bool, result reconcile.Result, err error) {
 				assert.Nil(err)
-				assert.Equal(reconcile.Result{RequeueAfter: retryDefault}, result)
+				assert.Equal(reconcile.Result{RequeueAfter: defaultRetryInterval}, result)
 				assert.False(done)
 			},
 			assertStatus: func(assert *assert.Assertions, status *rolloutv1alpha1.RolloutRunStatus) {
@@ -570,7 +570,7 @@ func Test_BatchExecutor_Do_Running(t *testing.T) {
 			},
 			assertResult: func(assert *assert.Assertions, done bool, result reconcile.Result, err error) {
 				assert.Nil(err)
-				assert.Equal(reconcile.Result{RequeueAfter: retryDefault}, result)
+				assert.Equal(reconcile.Result{RequeueAfter: defaultRetryInterval}, result)
 				assert.False(done)
 			},
 			assertStatus: func(assert *assert.Assertions, status *rolloutv1alpha1.RolloutRunStatus) {
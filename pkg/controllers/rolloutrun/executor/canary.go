@@ -17,15 +17,27 @@
 package executor
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	rolloutapi "kusionstack.io/rollout/apis/rollout"
 	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/analysis"
 	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+	"kusionstack.io/rollout/pkg/features"
+	"kusionstack.io/rollout/pkg/genericregistry"
+	"kusionstack.io/rollout/pkg/utils"
 	"kusionstack.io/rollout/pkg/workload"
 )
 
@@ -38,27 +50,139 @@ func newDoCanaryError(reason, msg string) *rolloutv1alpha1.CodeReasonMessage {
 }
 
 type canaryExecutor struct {
-	webhook      webhookExecutor
-	stateMachine *stepStateMachine
+	webhook           webhookExecutor
+	prober            trafficProber
+	splitProber       trafficSplitProber
+	analysisProviders genericregistry.Registry[string, analysis.AnalysisProvider]
+	stateMachine      *stepStateMachine
+	stateProcesses    map[rolloutv1alpha1.RolloutStepState]stateProcess
+	notifier          *canaryNotifier
+	guards            *stepGuardChecker
+	warmer            *canaryWarmer
+	// inheritedMetadataKeys are the keys of rolloutRun labels and annotations
+	// inherited by canary pods.
+	inheritedMetadataKeys []string
+	// globalPause is the ConfigMap freezing all canaries if it is paused.
+	globalPause types.NamespacedName
+	// maxTrafficWeight is the maximum canary traffic weight, zero means unlimited.
+	maxTrafficWeight int32
 }
 
 func newCanaryExecutor(webhook webhookExecutor) *canaryExecutor {
 	e := &canaryExecutor{
-		webhook:      webhook,
-		stateMachine: newStepStateMachine(),
+		webhook:           webhook,
+		prober:            &httpTrafficProber{},
+		splitProber:       &httpTrafficSplitProber{},
+		analysisProviders: analysis.Providers,
+		notifier:          newCanaryNotifier(),
+		guards:            newStepGuardChecker(),
+		warmer:            newCanaryWarmer(),
 	}
 
-	e.stateMachine.add(StepNone, StepPending, skipStep)
-	e.stateMachine.add(StepPending, StepPreCanaryStepHook, e.doInit)
-	e.stateMachine.add(StepPreCanaryStepHook, StepRunning, e.doPreStepHook)
-	e.stateMachine.add(StepRunning, StepPostCanaryStepHook, e.doCanary)
-	e.stateMachine.add(StepPostCanaryStepHook, StepResourceRecycling, e.doPostStepHook)
-	e.stateMachine.add(StepResourceRecycling, StepSucceeded, e.doRecycle)
-	e.stateMachine.add(StepSucceeded, "", skipStep)
+	e.stateProcesses = map[rolloutv1alpha1.RolloutStepState]stateProcess{
+		StepNone:               skipStep,
+		StepPreRunHook:         e.doPreRunHook,
+		StepPending:            e.doInit,
+		StepPreCanaryStepHook:  e.doPreStepHook,
+		StepRunning:            e.doCanary,
+		StepHolding:            e.doHold,
+		StepPostCanaryStepHook: e.doPostStepHook,
+		StepResourceRecycling:  e.doRecycle,
+		StepSucceeded:          skipStep,
+	}
+	e.stateMachine = e.newStateMachine(rolloutv1alpha1.DefaultCanaryStepStates)
 
 	return e
 }
 
+// newStateMachine links the given states one after another, starting from
+// StepNone. The states are validated to be a linear path to StepSucceeded.
+func (e *canaryExecutor) newStateMachine(states []rolloutv1alpha1.RolloutStepState) *stepStateMachine {
+	m := newStepStateMachine()
+	current := StepNone
+	for _, next := range states {
+		m.add(current, next, e.stateProcesses[current])
+		current = next
+	}
+	m.add(current, "", e.stateProcesses[current])
+	return m
+}
+
+// stateMachineOf returns the state machine of the canary states declared in
+// rolloutRun, or the full one if not declared. The Holding state is inserted
+// after Running if canary is held.
+func (e *canaryExecutor) stateMachineOf(rolloutRun *rolloutv1alpha1.RolloutRun) *stepStateMachine {
+	canary := rolloutRun.Spec.Canary
+	states := canary.States
+	if len(states) == 0 && !canary.HoldAtCanary {
+		return e.stateMachine
+	}
+	if len(states) == 0 {
+		states = rolloutv1alpha1.DefaultCanaryStepStates
+	}
+	states = withRecyclingState(states)
+	if canary.HoldAtCanary {
+		states = withHoldingState(states)
+	}
+	return e.newStateMachine(states)
+}
+
+// withRecyclingState returns a copy of states with ResourceRecycling inserted
+// before Succeeded. It is required since canary can be rolled back at any time,
+// but rolloutRuns admitted before it was required may leave it out.
+func withRecyclingState(states []rolloutv1alpha1.RolloutStepState) []rolloutv1alpha1.RolloutStepState {
+	if lo.Contains(states, StepResourceRecycling) {
+		return states
+	}
+	result := make([]rolloutv1alpha1.RolloutStepState, 0, len(states)+1)
+	for _, state := range states {
+		if state == StepSucceeded {
+			result = append(result, StepResourceRecycling)
+		}
+		result = append(result, state)
+	}
+	return result
+}
+
+// rollbackStates are the canary states which are left to ResourceRecycling
+// without promotion, e.g. when canary is canceled, its hold is ended, or it is
+// rolled back by max active duration or auto rollback.
+var rollbackStates = []rolloutv1alpha1.RolloutStepState{
+	StepPreCanaryStepHook,
+	StepRunning,
+	StepHolding,
+	StepPostCanaryStepHook,
+}
+
+// stepGraph returns the graph of the state machine of rolloutRun, with the
+// rollback edges to ResourceRecycling, and the restart edge back to Pending
+// taken once the canary rolled back automatically is recycled.
+func (e *canaryExecutor) stepGraph(rolloutRun *rolloutv1alpha1.RolloutRun, current rolloutv1alpha1.RolloutStepState) StepGraph {
+	g := e.stateMachineOf(rolloutRun).graph(current)
+	if !lo.Contains(g.States, StepResourceRecycling) {
+		return g
+	}
+	for _, state := range g.States {
+		if lo.Contains(rollbackStates, state) {
+			g.Edges = append(g.Edges, StepTransition{From: state, To: StepResourceRecycling, Type: StepTransitionRollback})
+		}
+	}
+	if rolloutRun.Spec.Canary.AutoRollback != nil && lo.Contains(g.States, StepPending) {
+		g.Edges = append(g.Edges, StepTransition{From: StepResourceRecycling, To: StepPending, Type: StepTransitionRestart})
+	}
+	return g
+}
+
+// setupTrafficManager sets the traffic manager of ctx up for the canary
+// traffic of rolloutRun, it must be called before canary traffic is changed.
+func (e *canaryExecutor) setupTrafficManager(ctx *ExecutorContext) {
+	rolloutRun := ctx.RolloutRun
+	ctx.TrafficManager.With(ctx.GetCanaryLogger(), nonDegradedCanaryTargets(ctx), rolloutRun.Spec.Canary.Traffic)
+	ctx.TrafficManager.WithVariants(canaryVariantNames(rolloutRun))
+	ctx.TrafficManager.WithUndoLog(&ctx.NewStatus.CanaryStatus.TrafficUndoLog)
+	ctx.TrafficManager.WithMaxWeight(e.weightCeiling(rolloutRun))
+}
+
 func (e *canaryExecutor) Do(ctx *ExecutorContext) (done bool, result ctrl.Result, err error) {
 	if !ctx.inCanary() {
 		return true, ctrl.Result{Requeue: true}, nil
@@ -72,9 +196,52 @@ func (e *canaryExecutor) Do(ctx *ExecutorContext) (done bool, result ctrl.Result
 		return true, ctrl.Result{Requeue: true}, nil
 	}
 
-	ctx.TrafficManager.With(logger, ctx.RolloutRun.Spec.Canary.Targets, ctx.RolloutRun.Spec.Canary.Traffic)
+	paused, message, err := e.isGloballyPaused(ctx)
+	if err != nil {
+		return false, ctrl.Result{}, err
+	}
+	if paused {
+		// hold the current state until the global pause is cleared
+		logger.Info("canary is globally paused, hold current state", "message", message)
+		if ctx.NewStatus.CanaryStatus != nil {
+			ctx.NewStatus.CanaryStatus.WaitingReason = rolloutv1alpha1.StepGloballyPaused
+		}
+		return false, ctx.Retry.result(retryDefault), nil
+	}
+
+	e.setupTrafficManager(ctx)
+	e.recordTrafficCeiling(ctx)
+
+	next := e.checkActiveDeadline(ctx, time.Now())
+
+	if err := e.resyncTraffic(ctx); err != nil {
+		return false, ctrl.Result{}, err
+	}
+
+	// each wait site records what canary is waiting on in this reconcile
+	ctx.NewStatus.CanaryStatus.WaitingReason = ""
 
-	return e.stateMachine.do(ctx, ctx.NewStatus.CanaryStatus.State)
+	prevState := ctx.NewStatus.CanaryStatus.State
+	guards := ctx.RolloutRun.Spec.Canary.Guards
+	if err = e.guards.check(ctx, guards, ctx.NewStatus.CanaryStatus); err == nil {
+		done, result, err = e.stateMachineOf(ctx.RolloutRun).do(ctx, prevState)
+	}
+	if len(guards) > 0 && err == nil {
+		// guards are checked on every reconcile
+		result = requeueBefore(result, ctx.Retry.Default)
+	}
+	if errors.Is(err, control.TerminalError(nil)) {
+		if rollbackFailedCanary(ctx, time.Now()) {
+			done, result, err = false, ctrl.Result{Requeue: true}, nil
+		} else {
+			recordCanaryCompletion(ctx, rolloutv1alpha1.CanaryFailed, ctx.NewStatus.Error)
+		}
+	}
+	e.updateCanaryPhases(ctx)
+	if state := ctx.NewStatus.CanaryStatus.State; state != prevState {
+		e.notifier.notify(ctx, state)
+	}
+	return done, requeueBefore(result, next), err
 }
 
 func (e *canaryExecutor) isSupported(ctx *ExecutorContext) bool {
@@ -84,14 +251,71 @@ func (e *canaryExecutor) isSupported(ctx *ExecutorContext) bool {
 
 func (e *canaryExecutor) doInit(ctx *ExecutorContext) (bool, time.Duration, error) {
 	rolloutRun := ctx.RolloutRun
-	releaseControl := control.NewCanaryReleaseControl(ctx.Accessor, ctx.Client)
-	for _, item := range rolloutRun.Spec.Canary.Targets {
-		wi := ctx.Workloads.Get(item.Cluster, item.Name)
-		if wi == nil {
-			return false, retryStop, newWorkloadNotFoundError(item.CrossClusterObjectNameReference)
+
+	targets, wait, err := reachableCanaryTargets(ctx, time.Now())
+	if err != nil {
+		return false, retryStop, err
+	}
+	if wait {
+		return false, retryDefault, nil
+	}
+
+	if features.DefaultFeatureGate.Enabled(features.CanaryQuotaCheck) {
+		if err := checkCanaryQuota(ctx, targets); err != nil {
+			return false, retryStop, err
 		}
+	}
 
-		err := releaseControl.Initialize(wi, ctx.OwnerKind, ctx.OwnerName, rolloutRun.Name)
+	if err := checkCanaryNamespaces(ctx, targets); err != nil {
+		return false, retryStop, err
+	}
+	if err := checkCanaryConfigOverrides(ctx, targets); err != nil {
+		return false, retryStop, err
+	}
+	if err := checkCanaryInitContainerOverrides(ctx, targets); err != nil {
+		return false, retryStop, err
+	}
+	if err := checkWebhookClientCerts(ctx); err != nil {
+		return false, retryStop, err
+	}
+	if rolloutRun.Spec.Canary.Traffic != nil {
+		if err := checkTrafficNamespaces(ctx); err != nil {
+			return false, retryStop, err
+		}
+		if err := checkTrafficProtocol(ctx); err != nil {
+			return false, retryStop, err
+		}
+		if err := checkTrafficSessionAffinity(ctx); err != nil {
+			return false, retryStop, err
+		}
+		if err := checkTrafficPorts(ctx); err != nil {
+			return false, retryStop, err
+		}
+	}
+
+	if len(rolloutRun.Spec.Canary.Ordinals) > 0 {
+		if _, ok := ctx.Accessor.(workload.OrdinalCanaryControl); !ok {
+			return false, retryStop, control.TerminalError(newDoCanaryError(
+				"OrdinalsNotSupported",
+				fmt.Sprintf("canary ordinals are not supported by workload %s", ctx.Accessor.GroupVersionKind().Kind),
+			))
+		}
+	}
+
+	// canary workloads must not be created before their dependencies are ready
+	prerequisitesMet, retry, err := checkCanaryPrerequisites(ctx)
+	if !prerequisitesMet {
+		if err == nil {
+			ctx.NewStatus.CanaryStatus.WaitingReason = rolloutv1alpha1.StepWaitingPrerequisites
+		}
+		return false, retry, err
+	}
+
+	startActiveDeadline(ctx, time.Now())
+
+	releaseControl := control.NewCanaryReleaseControl(ctx.Accessor, ctx.Client)
+	for _, item := range targets {
+		err := releaseControl.Initialize(item.info, ctx.OwnerKind, ctx.OwnerName, rolloutRun.Name)
 		if err != nil {
 			return false, retryStop, err
 		}
@@ -100,19 +324,52 @@ func (e *canaryExecutor) doInit(ctx *ExecutorContext) (bool, time.Duration, erro
 	return true, retryDefault, nil
 }
 
+// doPreRunHook gates the rolloutRun before canary is initialized, so nothing
+// needs to be undone if it is rejected.
+func (e *canaryExecutor) doPreRunHook(ctx *ExecutorContext) (bool, time.Duration, error) {
+	return e.doWebhook(ctx, rolloutv1alpha1.PreRunHook)
+}
+
 func (e *canaryExecutor) doPreStepHook(ctx *ExecutorContext) (bool, time.Duration, error) {
-	return e.webhook.Do(ctx, rolloutv1alpha1.PreCanaryStepHook)
+	return e.doWebhook(ctx, rolloutv1alpha1.PreCanaryStepHook)
 }
 
 func (e *canaryExecutor) doPostStepHook(ctx *ExecutorContext) (bool, time.Duration, error) {
-	done, retry, err := e.webhook.Do(ctx, rolloutv1alpha1.PostCanaryStepHook)
+	done, retry := true, retryImmediately
+	var err error
+	if !isTrafficReadyHookStarted(ctx) {
+		done, retry, err = e.doWebhook(ctx, rolloutv1alpha1.PostCanaryStepHook)
+	}
 	if done {
-		ctx.Pause()
+		done, retry, err = e.cutOverBlueGreen(ctx)
+	}
+	if done {
+		// AutoContinue may be set if canary is promoted by max active duration
+		// the checkpoints of pauseBefore replace the pause after post step hook
+		pauseAfter := ptr.Deref(ctx.RolloutRun.Spec.Canary.PauseAfter, len(ctx.RolloutRun.Spec.Canary.PauseBefore) == 0)
+		if pauseAfter && !ctx.NewStatus.CanaryStatus.AutoContinue {
+			ctx.Pause()
+			ctx.NewStatus.CanaryStatus.WaitingReason = rolloutv1alpha1.StepPaused
+			pauseCanaryTraffic(ctx)
+		} else {
+			ctx.GetCanaryLogger().Info("canary is configured not to pause after post step hook, continue automatically")
+			ctx.NewStatus.CanaryStatus.AutoContinue = true
+		}
 	}
 	return done, retry, err
 }
 
-func (e *canaryExecutor) modifyTraffic(ctx *ExecutorContext, op string) (bool, time.Duration) {
+// doWebhook runs webhooks of hookType and records that canary is waiting on
+// them if they are still running.
+func (e *canaryExecutor) doWebhook(ctx *ExecutorContext, hookType rolloutv1alpha1.HookType) (bool, time.Duration, error) {
+	done, retry, err := e.webhook.Do(ctx, hookType)
+	if !done && err == nil {
+		ctx.NewStatus.CanaryStatus.WaitingReason = rolloutv1alpha1.StepWaitingWebhook
+	}
+	return done, retry, err
+}
+
+func (e *canaryExecutor) modifyTraffic(ctx *ExecutorContext, op string) (bool, time.Duration, error) {
 	logger := ctx.GetCanaryLogger()
 	rolloutRun := ctx.RolloutRun
 	opResult := controllerutil.OperationResultNone
@@ -127,17 +384,28 @@ func (e *canaryExecutor) modifyTraffic(ctx *ExecutorContext, op string) (bool, t
 			opResult, err = ctx.TrafficManager.ForkCanary()
 		case "revertStable":
 			opResult, err = ctx.TrafficManager.RevertStable()
+		case "drainCanary":
+			opResult, err = ctx.TrafficManager.DrainCanary()
+		case "reducePausedCanary":
+			opResult, err = ctx.TrafficManager.SetCanaryWeight(ctx.NewStatus.CanaryStatus.PausedTraffic.Weight)
+		case "restorePausedCanary":
+			opResult, err = ctx.TrafficManager.SetCanaryWeight(ctx.NewStatus.CanaryStatus.PausedTraffic.PrePauseWeight)
+		case "rampDownCanary":
+			opResult, err = ctx.TrafficManager.SetCanaryWeight(ctx.NewStatus.CanaryStatus.RevertRamp.Weight)
 		case "revertCanary":
 			opResult, err = ctx.TrafficManager.RevertCanary()
 		}
 		if err != nil {
 			logger.Error(err, "failed to modify traffic", "operation", op)
-			return false, retryDefault
+			ctx.NewStatus.CanaryStatus.WaitingReason = rolloutv1alpha1.StepWaitingTraffic
+			return false, retryDefault, nil
 		}
 		logger.Info("modify traffic routing", "operation", op, "result", opResult)
 	}
 	if opResult != controllerutil.OperationResultNone {
-		return false, retryDefault
+		resetTrafficVerifications(ctx.NewStatus.CanaryStatus, op)
+		ctx.NewStatus.CanaryStatus.WaitingReason = rolloutv1alpha1.StepWaitingTraffic
+		return false, retryDefault, nil
 	}
 
 	// 1.b. waiting for traffic
@@ -145,11 +413,35 @@ func (e *canaryExecutor) modifyTraffic(ctx *ExecutorContext, op string) (bool, t
 		ready := ctx.TrafficManager.CheckReady()
 		if !ready {
 			logger.Info("waiting for BackendRouting ready")
-			return false, retryDefault
+			ctx.NewStatus.CanaryStatus.WaitingReason = rolloutv1alpha1.StepWaitingTraffic
+			return false, retryDefault, nil
 		}
 	}
 
-	return true, retryImmediately
+	// 1.c. verify traffic change is propagated by routes
+	propagated, retry, err := e.verifyTrafficPropagation(ctx, op)
+	if !propagated {
+		if err == nil {
+			ctx.NewStatus.CanaryStatus.WaitingReason = rolloutv1alpha1.StepWaitingTraffic
+		}
+		return false, retry, err
+	}
+
+	// 1.d. verify canary traffic is actually routed
+	if op == "forkCanary" {
+		done, retry, err := e.verifyCanaryTraffic(ctx)
+		if !done {
+			if err == nil {
+				ctx.NewStatus.CanaryStatus.WaitingReason = rolloutv1alpha1.StepWaitingTraffic
+			}
+			return false, retry, err
+		}
+
+		// 1.e. verify the split of canary traffic matches canary weight
+		return e.verifyTrafficSplit(ctx)
+	}
+
+	return true, retryImmediately, nil
 }
 
 func (e *canaryExecutor) doCanary(ctx *ExecutorContext) (bool, time.Duration, error) {
@@ -157,106 +449,662 @@ func (e *canaryExecutor) doCanary(ctx *ExecutorContext) (bool, time.Duration, er
 	rolloutRun := ctx.RolloutRun
 
 	// 1. do traffic initialization
-	prepareDone, retry := e.modifyTraffic(ctx, "forkStable")
+	prepareDone, retry, err := e.modifyTraffic(ctx, "forkStable")
 	if !prepareDone {
-		return false, retry, nil
+		return false, retry, err
+	}
+
+	// canary traffic must be reverted before canary is scaled down, so that no
+	// traffic is routed to a canary without enough replicas
+	idle := isBakeIdle(ctx.NewStatus.CanaryStatus)
+	if idle {
+		revertDone, retry, err := e.modifyTraffic(ctx, "revertCanary")
+		if !revertDone {
+			return false, retry, err
+		}
 	}
 
 	// 2.a. do create canary resources
+	if !pauseBefore(ctx, rolloutv1alpha1.CanaryPauseBeforeCreateCanary) {
+		return false, retryDefault, nil
+	}
 	logger.Info("about to create canary resources and check")
-	canaryWorkloads := make([]*workload.Info, 0)
+	canaryWorkloads := make([]CanaryTargetInfo, 0)
 
-	patch := appendBuiltinPodTemplateMetadataPatch(rolloutRun.Spec.Canary.PodTemplateMetadataPatch)
+	patch, overridden := canaryPodTemplateMetadataPatch(rolloutRun, ctx.OwnerName, e.inheritedMetadataKeys)
 
-	changed := false
+	targets, wait, err := reachableCanaryTargets(ctx, time.Now())
+	if err != nil {
+		return false, retryStop, err
+	}
+	if wait {
+		return false, retryDefault, nil
+	}
+	// no canary is created for targets rejected before canary
+	targets = lo.Filter(targets, func(t canaryTarget, _ int) bool {
+		return !isRejectedTarget(ctx, t.CrossClusterObjectNameReference, rolloutv1alpha1.PreCanaryStepHook)
+	})
+
+	if features.DefaultFeatureGate.Enabled(features.CanaryDependencyCheck) {
+		if err := checkCanaryDependencies(ctx, targets); err != nil {
+			return false, retryStop, err
+		}
+	}
+
+	pulled, err := checkCanaryImagePull(ctx, targets)
+	if err != nil {
+		return false, retryStop, err
+	}
+	if !pulled {
+		ctx.NewStatus.CanaryStatus.WaitingReason = rolloutv1alpha1.StepWaitingImagePull
+		return false, retryDefault, nil
+	}
+
+	// targets whose canary workloads are changed in this reconcile, they are
+	// reported creating while the others are reported by their own readiness
+	changedTargets := make([]rolloutv1alpha1.CrossClusterObjectNameReference, 0)
+	driftFields := make([]string, 0)
 	releaseControl := control.NewCanaryReleaseControl(ctx.Accessor, ctx.Client)
+	ordinals := rolloutRun.Spec.Canary.Ordinals
+	scaleUpStep := rolloutRun.Spec.Canary.ScaleUpStep
 
-	for _, item := range rolloutRun.Spec.Canary.Targets {
-		wi := ctx.Workloads.Get(item.Cluster, item.Name)
-		if wi == nil {
-			return false, retryStop, newWorkloadNotFoundError(item.CrossClusterObjectNameReference)
+	for _, item := range targets {
+		wi := item.info
+		changed := false
+		if _, ok := workload.GetCanaryOwner(wi.Object); !ok {
+			// the cluster was skipped in initialization and is reachable again
+			if err := releaseControl.Initialize(wi, ctx.OwnerKind, ctx.OwnerName, rolloutRun.Name); err != nil {
+				return false, retryStop, err
+			}
 		}
 
-		result, canaryInfo, err := releaseControl.CreateOrUpdate(ctx.Context, wi, item.Replicas, patch)
-		if err != nil {
-			return false, retryStop, err
+		if len(ordinals) > 0 {
+			// pods of ordinals are updated in place, no canary workload is created
+			targetOrdinals := ordinals
+			if scaleUpStep != nil {
+				n, err := scaleUpIncrement(ctx, item.CrossClusterObjectNameReference, int32(len(ordinals)), *scaleUpStep)
+				if err != nil {
+					return false, retryStop, err
+				}
+				targetOrdinals = largestOrdinals(ordinals, n)
+			}
+			updated, canaryInfo, err := control.NewOrdinalCanaryReleaseControl(ctx.Accessor, ctx.Client).Apply(wi, targetOrdinals)
+			if err != nil {
+				return false, retryStop, err
+			}
+			if updated {
+				changed = true
+				logger.V(1).Info("canary ordinals changed", "workload", item.CrossClusterObjectNameReference, "ordinals", targetOrdinals)
+			}
+			if scaleUpStep != nil {
+				advanced, err := advanceScaleUp(ctx, item.CrossClusterObjectNameReference, canaryInfo, *scaleUpStep)
+				if err != nil {
+					return false, retryStop, err
+				}
+				changed = changed || advanced
+			}
+			if changed {
+				changedTargets = append(changedTargets, item.CrossClusterObjectNameReference)
+			}
+			canaryWorkloads = append(canaryWorkloads, CanaryTargetInfo{Target: item.RolloutRunStepTarget, Info: canaryInfo})
+			continue
 		}
 
-		if result != controllerutil.OperationResultNone {
-			changed = true
+		// each variant of canary is a separate canary workload
+		for _, variant := range canaryVariants(rolloutRun) {
+			replicas := variant.Replicas
+			if len(variant.Name) == 0 {
+				replicas, err = canaryReplicas(ctx, item.RolloutRunStepTarget, wi)
+				if err != nil {
+					return false, retryStop, err
+				}
+			}
+			if idle {
+				replicas = bakeIdleReplicas(rolloutRun.Spec.Canary.Bake)
+			} else if scaleUpStep != nil {
+				finalReplicas, err := workload.CalculateUpdatedReplicas(&wi.Status.Replicas, replicas)
+				if err != nil {
+					return false, retryStop, err
+				}
+				n, err := scaleUpIncrement(ctx, item.CrossClusterObjectNameReference, finalReplicas, *scaleUpStep)
+				if err != nil {
+					return false, retryStop, err
+				}
+				replicas = intstr.FromInt(int(n))
+			}
+
+			result, canaryInfo, diff, err := newCanaryReleaseControl(ctx, item, variant.Name).CreateOrUpdate(ctx.Context, wi, replicas, variantPodTemplatePatch(patch, variant), rolloutRun.Spec.Canary.ObjectMetadataPatch)
+			if err != nil {
+				return false, retryStop, err
+			}
+			canaryInfo, excluded, err := verifyCanaryRevision(ctx, ctx.Client, ctx.Accessor, canaryInfo)
+			if err != nil {
+				return false, retryStop, err
+			}
+			if excluded > 0 {
+				logger.Info("canary workload reports available replicas not on its updated revision, exclude them",
+					"workload", item.CrossClusterObjectNameReference,
+					"variant", variant.Name,
+					"revision", canaryInfo.Status.UpdatedRevision,
+					"excluded", excluded,
+				)
+			}
+
+			if result != controllerutil.OperationResultNone {
+				changed = true
+				logger.V(1).Info("canary resource changed", "workload", item.CrossClusterObjectNameReference, "variant", variant.Name, "result", result, "diff", diff)
+			}
+			if result == controllerutil.OperationResultUpdated {
+				driftFields = append(driftFields, driftedFields(item.CrossClusterObjectNameReference, diff)...)
+			}
+			if !idle && scaleUpStep != nil {
+				advanced, err := advanceScaleUp(ctx, item.CrossClusterObjectNameReference, canaryInfo, *scaleUpStep)
+				if err != nil {
+					return false, retryStop, err
+				}
+				changed = changed || advanced
+			}
+
+			canaryWorkloads = append(canaryWorkloads, CanaryTargetInfo{Target: item.RolloutRunStepTarget, Info: canaryInfo})
+		}
+		if changed {
+			changedTargets = append(changedTargets, item.CrossClusterObjectNameReference)
 		}
+	}
+	changed := len(changedTargets) > 0
+
+	// the footprint is only for capacity planning, it never blocks canary
+	footprint, err := canaryResourceFootprint(ctx.Accessor, targets, canaryWorkloads)
+	if err != nil {
+		logger.Error(err, "failed to calculate canary resource footprint")
+	} else {
+		ctx.NewStatus.CanaryStatus.ResourceFootprint = footprint
+	}
+
+	if err := detectCanaryDrift(ctx, driftFields, time.Now()); err != nil {
+		return false, retryStop, err
+	}
 
-		canaryWorkloads = append(canaryWorkloads, canaryInfo)
+	readiness, err := newCanaryReadiness(rolloutRun.Spec.Canary.ReadinessExpression)
+	if err != nil {
+		return false, retryStop, control.TerminalError(newDoCanaryError(
+			ReasonReadinessExpressionInvalid,
+			fmt.Sprintf("failed to compile readiness expression: %v", err),
+		))
 	}
+	summary := aggregateCanaryInfo(canaryWorkloads, readiness.ready)
+	ctx.NewStatus.CanaryStatus.CanaryReplicas = summary.APIStatus()
 
 	if changed {
+		if len(overridden) > 0 {
+			// warn only when canary workloads are changed instead of every reconcile
+			ctx.Recorder.Eventf(ctx.RolloutRun, corev1.EventTypeWarning, ReasonBuiltinLabelsOverridden,
+				"builtin labels %v of canary pods override the user defined or inherited ones", overridden)
+		}
+		recordCanaryTargetStates(ctx.NewStatus.CanaryStatus, targets, changedTargets, summary, nil, time.Now())
+		ctx.NewStatus.CanaryStatus.WaitingReason = rolloutv1alpha1.StepWaitingReplicas
 		return false, retryDefault, nil
 	}
 
 	// 2.b. waiting canary workload ready
-	for _, info := range canaryWorkloads {
-		if !info.CheckUpdatedReady(info.Status.Replicas) {
-			// ready
-			logger.Info("still waiting for canary target ready",
+	now := time.Now()
+	startHealthCheck(ctx, now)
+	waiting := false
+	timedOut := make([]rolloutv1alpha1.CrossClusterObjectNameReference, 0)
+	restartThreshold, podThreshold := crashLoopThresholds(rolloutRun.Spec.Canary.CrashLoopCheck)
+	if idle {
+		return e.waitBakeIdle(ctx)
+	}
+	podFailures := make([]rolloutv1alpha1.CanaryPodFailure, 0)
+	for _, item := range summary.NotReady {
+		info, target := item.Info, item.Target
+		var failures []rolloutv1alpha1.CanaryPodFailure
+		if len(ordinals) > 0 {
+			failures, err = failingOrdinalPods(ctx.Client, ctx.Accessor, info, ordinals, restartThreshold)
+		} else {
+			failures, err = failingCanaryPods(ctx, ctx.Client, ctx.Accessor, info, restartThreshold)
+		}
+		if err != nil {
+			return false, retryStop, err
+		}
+		podFailures = append(podFailures, failures...)
+		badImage := lo.Filter(failures, func(f rolloutv1alpha1.CanaryPodFailure, _ int) bool { return isBadImageFailure(f) })
+		crashLooping := lo.Filter(failures, func(f rolloutv1alpha1.CanaryPodFailure, _ int) bool { return !isBadImageFailure(f) })
+		if len(badImage) > 0 || len(crashLooping) >= int(podThreshold) {
+			if !inHealthCheckGracePeriod(ctx, now) {
+				recordPodFailures(ctx.NewStatus.CanaryStatus, podFailures)
+				if len(badImage) > 0 {
+					failure := badImage[0]
+					return false, retryStop, control.TerminalError(newDoCanaryError(
+						ReasonCanaryBadImage,
+						fmt.Sprintf("image of container %s in canary pods %v of target %s can not be pulled, reason: %s, message: %s",
+							failure.Container, podFailureNames(badImage), target.CrossClusterObjectNameReference, failure.Reason, failure.Message),
+					))
+				}
+				failure := crashLooping[0]
+				return false, retryStop, control.TerminalError(newDoCanaryError(
+					ReasonCanaryCrashLooping,
+					fmt.Sprintf("canary pods %v of target %s are crash looping, container %s of pod %s %s",
+						podFailureNames(crashLooping), target.CrossClusterObjectNameReference, failure.Container, failure.Pod, failure.Message),
+				))
+			}
+			logger.Info("canary pods are failing in health check grace period, tolerate it",
 				"cluster", info.ClusterName,
 				"name", info.Name,
-				"replicas", info.Status.Replicas,
-				"readyReplicas", info.Status.UpdatedAvailableReplicas,
+				"pods", podFailureNames(failures),
 			)
+		}
+		if rolloutRun.Spec.Canary.PodPlacementPatch != nil && len(ordinals) == 0 {
+			warnUnschedulableCanaryPods(ctx, info)
+		}
+		deadline := canaryReadinessDeadline(ctx.NewStatus.CanaryStatus, target, now)
+		if deadline != nil && now.After(deadline.Time) {
+			logger.Info("canary target readiness timed out",
+				"cluster", info.ClusterName,
+				"name", info.Name,
+				"deadline", deadline.Time,
+			)
+			timedOut = append(timedOut, target.CrossClusterObjectNameReference)
+			continue
+		}
+		logger.Info("still waiting for canary target ready",
+			"cluster", info.ClusterName,
+			"name", info.Name,
+			"replicas", info.Status.Replicas,
+			"readyReplicas", info.Status.UpdatedAvailableReplicas,
+			"updating", info.Status.Updating,
+		)
+		waiting = true
+	}
+	retry = retryDefault
+	if stabilization := rolloutRun.Spec.Canary.ReadinessStabilization; stabilization != nil {
+		// canary is ready by the stable ready percentage instead of all replicas
+		var stabilized bool
+		stabilized, retry = checkReadinessStabilization(ctx.NewStatus.CanaryStatus, stabilization, summary, now, ctx.Retry.Default)
+		waiting = !stabilized
+		if waiting {
+			logger.Info("waiting for canary ready percentage to be stabilized", "status", ctx.NewStatus.CanaryStatus.ReadinessStabilization)
+		}
+	}
+	recordPodFailures(ctx.NewStatus.CanaryStatus, podFailures)
+	recordCanaryTargetStates(ctx.NewStatus.CanaryStatus, targets, nil, summary, timedOut, now)
+	if waiting {
+		ctx.NewStatus.CanaryStatus.WaitingReason = rolloutv1alpha1.StepWaitingReplicas
+		if len(readiness.failures) > 0 {
+			ctx.NewStatus.CanaryStatus.WaitingReason = rolloutv1alpha1.StepReadinessExpressionFailed
+			ctx.Recorder.Eventf(ctx.RolloutRun, corev1.EventTypeWarning, ReasonReadinessExpressionFailed,
+				"failed to evaluate readiness expression of canary workloads: %v", readiness.failures)
+		}
+		return false, retry, nil
+	}
+	if len(timedOut) > 0 {
+		// reset timed out targets so that a manual retry starts a new wait
+		resetCanaryReadiness(ctx.NewStatus.CanaryStatus, timedOut)
+		return false, retryStop, control.TerminalError(newDoCanaryError(
+			"ReadinessTimeout",
+			fmt.Sprintf("canary targets %v are not ready before deadline", timedOut),
+		))
+	}
+
+	// 3.a. external preconditions must be met before canary receives traffic
+	preconditionsMet, retry, err := checkCanaryPreconditions(ctx)
+	if !preconditionsMet {
+		if err == nil {
+			ctx.NewStatus.CanaryStatus.WaitingReason = rolloutv1alpha1.StepWaitingPreconditions
+		}
+		return false, retry, err
+	}
+
+	// 3.b. stable must be able to absorb the remaining traffic
+	stableHealthy, retry, err := e.checkStableHealth(ctx, targets)
+	if !stableHealthy {
+		return false, retry, err
+	}
+
+	// 3.c. warm up canary before it receives real traffic
+	warmedUp, retry, err := e.warmer.warmUp(ctx, canaryWorkloads)
+	if !warmedUp {
+		if err == nil {
+			ctx.NewStatus.CanaryStatus.WaitingReason = rolloutv1alpha1.StepWaitingWarmUp
+		}
+		return false, retry, err
+	}
+
+	// 3.d. canary pods must be in rotation before traffic is shifted to them
+	if features.DefaultFeatureGate.Enabled(features.CanaryEndpointsCheck) {
+		outOfRotation, err := canaryPodsOutOfRotation(ctx)
+		if err != nil {
+			return false, retryStop, err
+		}
+		if len(outOfRotation) > 0 {
+			logger.Info("ready canary pods are not in service endpoints yet, check later", "pods", outOfRotation)
+			ctx.NewStatus.CanaryStatus.WaitingReason = rolloutv1alpha1.StepWaitingEndpoints
 			return false, retryDefault, nil
 		}
 	}
 
-	// 3 do canary traffic routing
-	trafficCanaryDone, retry := e.modifyTraffic(ctx, "forkCanary")
-	if !trafficCanaryDone {
-		return false, retry, nil
+	// 3.e. do canary traffic routing, blue/green canary is validated without
+	// traffic and cut over after post canary step hook
+	if !isBlueGreen(rolloutRun) {
+		if !pauseBefore(ctx, rolloutv1alpha1.CanaryPauseBeforeForkCanary) {
+			return false, retryDefault, nil
+		}
+		trafficCanaryDone, retry, err := e.modifyTraffic(ctx, "forkCanary")
+		if !trafficCanaryDone {
+			return false, retry, err
+		}
+		// 3.f. notify integrators that canary traffic is live
+		readyHookDone, retry, err := e.doTrafficReadyHook(ctx)
+		if !readyHookDone {
+			return false, retry, err
+		}
+	}
+
+	// 4. analyze canary metrics, in each active window if bake is scheduled
+	analysisDone, retry, err := e.bake(ctx)
+	if !analysisDone {
+		return false, retry, err
 	}
 
 	return true, retryImmediately, nil
 }
 
-func appendBuiltinPodTemplateMetadataPatch(patch *rolloutv1alpha1.MetadataPatch) *rolloutv1alpha1.MetadataPatch {
+// CanaryPodTemplateMetadataPatch returns the final podTemplate metadata patch
+// applied to canary workloads of rolloutRun owned by ownerName, which is the
+// user defined patch with labels and annotations of rolloutRun in inheritedKeys
+// and builtin canary labels. It has no side effects on rolloutRun, so it can be
+// used to preview the patch before running.
+func CanaryPodTemplateMetadataPatch(rolloutRun *rolloutv1alpha1.RolloutRun, ownerName string, inheritedKeys []string) *rolloutv1alpha1.MetadataPatch {
+	patch, _ := canaryPodTemplateMetadataPatch(rolloutRun, ownerName, inheritedKeys)
+	return patch
+}
+
+// canaryPodTemplateMetadataPatch returns the patch of CanaryPodTemplateMetadataPatch
+// and the user defined or inherited labels overridden by builtin ones.
+func canaryPodTemplateMetadataPatch(rolloutRun *rolloutv1alpha1.RolloutRun, ownerName string, inheritedKeys []string) (*rolloutv1alpha1.MetadataPatch, []string) {
+	var patch *rolloutv1alpha1.MetadataPatch
+	if rolloutRun.Spec.Canary != nil {
+		patch = rolloutRun.Spec.Canary.PodTemplateMetadataPatch
+	}
+	return appendBuiltinPodTemplateMetadataPatch(patch, rolloutRun, ownerName, inheritedKeys)
+}
+
+const ReasonBuiltinLabelsOverridden = "BuiltinLabelsOverridden"
+
+// builtinCanaryPodLabels returns the labels always set on canary pods, they
+// identify canary pods and the exact run creating them.
+func builtinCanaryPodLabels(rolloutRun *rolloutv1alpha1.RolloutRun, ownerName string) map[string]string {
+	return map[string]string{
+		rolloutapi.LabelCanary:         "true",
+		rolloutapi.LabelPodRevision:    rolloutapi.LabelValuePodRevisionCanary,
+		rolloutapi.LabelRolloutName:    utils.TruncateLabelValue(ownerName),
+		rolloutapi.LabelRolloutRunName: utils.TruncateLabelValue(rolloutRun.Name),
+		rolloutapi.LabelRolloutStep:    rolloutapi.LabelValueStepCanary,
+	}
+}
+
+// appendBuiltinPodTemplateMetadataPatch returns a copy of patch with inherited
+// metadata of rolloutRun and builtin canary labels. When keys collide, the user
+// defined patch overrides the inherited metadata, and the builtin canary labels
+// override both, the overridden keys with different values are returned.
+func appendBuiltinPodTemplateMetadataPatch(patch *rolloutv1alpha1.MetadataPatch, rolloutRun *rolloutv1alpha1.RolloutRun, ownerName string, inheritedKeys []string) (*rolloutv1alpha1.MetadataPatch, []string) {
 	if patch == nil {
 		patch = &rolloutv1alpha1.MetadataPatch{}
+	} else {
+		patch = patch.DeepCopy()
 	}
 
 	if patch.Labels == nil {
 		patch.Labels = map[string]string{}
 	}
 
-	patch.Labels[rolloutapi.LabelCanary] = "true"
-	patch.Labels[rolloutapi.LabelPodRevision] = "canary"
-	return patch
+	for _, key := range inheritedKeys {
+		if value, ok := rolloutRun.Labels[key]; ok {
+			if _, exists := patch.Labels[key]; !exists {
+				patch.Labels[key] = value
+			}
+		}
+		if value, ok := rolloutRun.Annotations[key]; ok {
+			if patch.Annotations == nil {
+				patch.Annotations = map[string]string{}
+			}
+			if _, exists := patch.Annotations[key]; !exists {
+				patch.Annotations[key] = value
+			}
+		}
+	}
+
+	overridden := make([]string, 0)
+	for key, value := range builtinCanaryPodLabels(rolloutRun, ownerName) {
+		if old, exists := patch.Labels[key]; exists && old != value {
+			overridden = append(overridden, key)
+		}
+		patch.Labels[key] = value
+	}
+	sort.Strings(overridden)
+	return patch, overridden
 }
 
 func (e *canaryExecutor) doRecycle(ctx *ExecutorContext) (bool, time.Duration, error) {
-	done, retry := e.modifyTraffic(ctx, "revertCanary")
+	rollback := isRolledBackByDeadline(ctx.NewStatus.CanaryStatus) || isCanaryHoldEnded(ctx.NewStatus.CanaryStatus) ||
+		isAutoRollingBack(ctx.NewStatus.CanaryStatus) || isCancelRequested(ctx)
+	// fast promotion skips the gates of promotion, but not the recycling
+	gated := !rollback && !isCanaryFastPromoted(ctx.NewStatus.CanaryStatus)
+
+	// hold the promotion until we are in an allowed window
+	inWindow, err := inPromotionWindows(ctx.RolloutRun.Spec.Canary.PromotionWindows, time.Now())
+	if err != nil {
+		return false, retryStop, control.TerminalError(wrapDoCanaryError("InvalidPromotionWindows", err.Error(), err))
+	}
+	if !inWindow && gated {
+		ctx.GetCanaryLogger().Info("canary promotion is out of allowed windows, waiting", "reason", ReasonWaitingForWindow)
+		ctx.NewStatus.CanaryStatus.WaitingReason = rolloutv1alpha1.StepWaitingForWindow
+		return false, retryDefault, nil
+	}
+	if ctx.NewStatus.CanaryStatus.WaitingReason == rolloutv1alpha1.StepWaitingForWindow {
+		// the window is open
+		ctx.NewStatus.CanaryStatus.WaitingReason = ""
+	}
+
+	if gated && !pauseBefore(ctx, rolloutv1alpha1.CanaryPauseBeforePromotion) {
+		return false, retryDefault, nil
+	}
+
+	// the revert hook precedes all traffic operations in recycle, as the
+	// reverse of the ready hook following the fork
+	done, retry, err := e.doTrafficRevertHook(ctx)
 	if !done {
-		return false, retry, nil
+		return false, retry, err
 	}
 
-	rolloutRun := ctx.RolloutRun
-	releaseControl := control.NewCanaryReleaseControl(ctx.Accessor, ctx.Client)
+	done, retry, err = e.restorePausedTraffic(ctx, rollback)
+	if !done {
+		return false, retry, err
+	}
+
+	done, retry, err = e.drainSessions(ctx)
+	if !done {
+		return false, retry, err
+	}
+
+	order := canaryRecycleOrder(ctx.RolloutRun.Spec.Canary)
+	if isBlueGreen(ctx.RolloutRun) && !rollback {
+		order = blueGreenRecycleOrder(order)
+	}
+	for _, op := range order {
+		switch op {
+		case rolloutv1alpha1.PromoteStableRevision:
+			done, retry, err = e.promoteBlueGreenStable(ctx)
+		case rolloutv1alpha1.RevertCanaryTraffic:
+			done, retry, err = e.rampDownCanary(ctx)
+			if done {
+				done, retry, err = e.modifyTraffic(ctx, "revertCanary")
+			}
+		case rolloutv1alpha1.RevertStableTraffic:
+			done, retry, err = e.modifyTraffic(ctx, "revertStable")
+		case rolloutv1alpha1.DeleteCanaryResource:
+			done, retry, err = e.retainCanary(ctx, time.Now())
+			if done {
+				done, retry, err = e.deleteCanaryResources(ctx)
+			}
+		default:
+			return false, retryStop, control.TerminalError(newDoCanaryError(
+				"InvalidRecycleOrder",
+				fmt.Sprintf("unknown canary recycle operation %q", op),
+			))
+		}
+		if !done {
+			return false, retry, err
+		}
+	}
+
+	done, retry, err = e.verifyRecycled(ctx)
+	if !done {
+		return false, retry, err
+	}
+
+	if isAutoRollingBack(ctx.NewStatus.CanaryStatus) && !isCancelRequested(ctx) {
+		// canary is recycled, retry it from the start
+		ctx.NewStatus.CanaryStatus.Rollbacks.RollingBack = false
+		ctx.RestartCurrentStep()
+		return false, retryImmediately, nil
+	}
 
-	for _, item := range rolloutRun.Spec.Canary.Targets {
-		wi := ctx.Workloads.Get(item.Cluster, item.Name)
-		if wi == nil {
-			return false, retryStop, newWorkloadNotFoundError(item.CrossClusterObjectNameReference)
+	outcome, result := rolloutv1alpha1.CanarySucceeded, (*rolloutv1alpha1.CodeReasonMessage)(nil)
+	if rollback {
+		outcome = rolloutv1alpha1.CanaryFailed
+		switch {
+		case isCancelRequested(ctx):
+			result = newDoCanaryError(ReasonRolloutRunCanceled, fmt.Sprintf("rolloutRun is canceled by %s", cancelRequestedBy(ctx.NewStatus.Cancellation)))
+		case isCanaryHoldEnded(ctx.NewStatus.CanaryStatus):
+			result = newDoCanaryError(ReasonCanaryHoldEnded, "canary hold is ended, canary is recycled without promotion")
+		default:
+			result = newDoCanaryError(ReasonCanaryDeadlineExceeded, ctx.NewStatus.CanaryStatus.ActiveDeadline.Message)
 		}
+	}
+	done, retry, err = e.doCompletedHook(ctx, outcome, result)
+	if !done {
+		return false, retry, err
+	}
+
+	if rollback {
+		// canary is recycled, do not continue to batch
+		ctx.NewStatus.Phase = rolloutv1alpha1.RolloutRunPhaseCanceling
+	}
+	return true, retryDefault, nil
+}
 
-		if err := releaseControl.Finalize(wi); err != nil {
-			return false, retryStop, newDoCanaryError(
+// canaryRecycleOrder returns the order of recycle operations, falling back to
+// the default one if not specified. The deletion of canary is deferred after
+// all traffic operations if canary is retained.
+func canaryRecycleOrder(canary *rolloutv1alpha1.RolloutRunCanaryStrategy) []rolloutv1alpha1.CanaryRecycleOperation {
+	order := canary.RecycleOrder
+	if len(order) == 0 {
+		order = rolloutv1alpha1.DefaultCanaryRecycleOrder
+	}
+	if canary.RetentionSeconds == nil {
+		return order
+	}
+	deferred := lo.Without(order, rolloutv1alpha1.DeleteCanaryResource)
+	return append(deferred, rolloutv1alpha1.DeleteCanaryResource)
+}
+
+// newCanaryReleaseControl returns the control of the canary workload of variant
+// of target item, configured by the canary strategy of rolloutRun, empty variant
+// means the canary workload is not a variant.
+func newCanaryReleaseControl(ctx *ExecutorContext, item canaryTarget, variant string) *control.CanaryReleaseControl {
+	canary := ctx.RolloutRun.Spec.Canary
+	return control.NewCanaryReleaseControl(ctx.Accessor, ctx.Client).
+		InNamespace(item.CanaryNamespace).
+		WithVariant(variant).
+		WithConfigOverrides(item.ConfigOverrides).
+		WithInitContainerOverrides(item.InitContainerOverrides).
+		WithUpdateStrategy(canary.UpdateStrategy).
+		WithPodPlacement(canary.PodPlacementPatch).
+		WithOwnerReferences(canaryOwnerReferences(ctx.RolloutRun, item)).
+		WithPodDisruptionBudget(item.PodDisruptionBudget)
+}
+
+func (e *canaryExecutor) deleteCanaryResources(ctx *ExecutorContext) (bool, time.Duration, error) {
+	targets, wait, err := reachableCanaryTargets(ctx, time.Now())
+	if err != nil {
+		return false, retryStop, err
+	}
+
+	// the check pods are left if canary is canceled during the check
+	if ctx.RolloutRun.Spec.Canary.ImagePullCheck != nil {
+		if err := deleteImagePullCheckPods(ctx, targets); err != nil {
+			return false, retryStop, wrapDoCanaryError(
+				"FailedFinalize",
+				fmt.Sprintf("failed to delete image pull check pods, err: %v", err),
+				err,
+			)
+		}
+	}
+
+	ordinals := ctx.RolloutRun.Spec.Canary.Ordinals
+	for _, item := range targets {
+		if len(ordinals) > 0 {
+			if err := control.NewOrdinalCanaryReleaseControl(ctx.Accessor, ctx.Client).Revert(item.info, ordinals); err != nil {
+				return false, retryStop, wrapDoCanaryError(
+					"FailedFinalize",
+					fmt.Sprintf("failed to revert canary ordinals %v for workload(%s), err: %v", ordinals, item.CrossClusterObjectNameReference, err),
+					err,
+				)
+			}
+		}
+		for _, variant := range canaryVariantNames(ctx.RolloutRun) {
+			if err := newCanaryReleaseControl(ctx, item, variant).Delete(item.info); err != nil {
+				return false, retryStop, wrapDoCanaryError(
+					"FailedFinalize",
+					fmt.Sprintf("failed to delete canary resource of variant %s for workload(%s), err: %v", variant, item.CrossClusterObjectNameReference, err),
+					err,
+				)
+			}
+		}
+		if err := newCanaryReleaseControl(ctx, item, "").Finalize(item.info); err != nil {
+			return false, retryStop, wrapDoCanaryError(
 				"FailedFinalize",
 				fmt.Sprintf("failed to delete canary resource for workload(%s), err: %v", item.CrossClusterObjectNameReference, err),
+				err,
 			)
 		}
 	}
 
-	done, retry = e.modifyTraffic(ctx, "revertStable")
+	if wait || len(ctx.NewStatus.CanaryStatus.UnreachableClusters) > 0 {
+		// canary resources may be left in unreachable clusters, they must be
+		// recycled after the clusters are reachable again
+		ctx.GetCanaryLogger().Info("waiting for unreachable clusters to recycle canary resources")
+		return false, retryDefault, nil
+	}
+	return true, retryImmediately, nil
+}
+
+// drainSessions stops routing new sessions to canary and waits for the existing
+// sticky sessions to drain before canary traffic is reverted.
+func (e *canaryExecutor) drainSessions(ctx *ExecutorContext) (bool, time.Duration, error) {
+	traffic := ctx.RolloutRun.Spec.Canary.Traffic
+	if traffic == nil || traffic.SessionDrain == nil {
+		return true, retryImmediately, nil
+	}
+
+	done, retry, err := e.modifyTraffic(ctx, "drainCanary")
 	if !done {
-		return false, retry, nil
+		return false, retry, err
 	}
 
-	return true, retryDefault, nil
+	status := ctx.NewStatus.CanaryStatus
+	if status.SessionDrain == nil {
+		now := metav1.Now()
+		status.SessionDrain = &rolloutv1alpha1.SessionDrainStatus{
+			StartTime: ptr.To(now),
+			EndTime:   ptr.To(metav1.NewTime(now.Add(time.Duration(traffic.SessionDrain.Seconds) * time.Second))),
+		}
+	}
+
+	if remaining := time.Until(status.SessionDrain.EndTime.Time); remaining > 0 {
+		ctx.GetCanaryLogger().Info("waiting for sticky sessions on canary to drain", "remaining", remaining.String())
+		return false, remaining, nil
+	}
+	return true, retryImmediately, nil
 }
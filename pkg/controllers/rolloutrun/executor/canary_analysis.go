@@ -0,0 +1,126 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/analysis"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+)
+
+// analyze checks the canary metrics by their providers, the canary fails if
+// any metric is out of threshold.
+func (e *canaryExecutor) analyze(ctx *ExecutorContext) (bool, time.Duration, error) {
+	spec := ctx.RolloutRun.Spec.Canary.Analysis
+	if spec == nil {
+		return true, retryImmediately, nil
+	}
+	logger := ctx.GetCanaryLogger()
+	vars := canaryQueryVars(ctx.RolloutRun)
+
+	for i := range spec.Metrics {
+		metric := &spec.Metrics[i]
+		provider, err := e.analysisProviders.Get(metric.Provider.Name)
+		if err != nil {
+			return false, retryStop, control.TerminalError(newDoCanaryError(
+				"UnknownAnalysisProvider",
+				fmt.Sprintf("analysis provider %q of metric %s is not found", metric.Provider.Name, metric.Name),
+			))
+		}
+
+		value, err := provider.Query(analysis.WithQueryVars(ctx, vars), metric)
+		if err != nil {
+			reason := "AnalysisQueryFailed"
+			var perr *analysis.ProviderError
+			if errors.As(err, &perr) {
+				reason = perr.Reason
+			}
+			if reason == analysis.ReasonInvalidQueryTemplate {
+				return false, retryStop, control.TerminalError(wrapDoCanaryError(reason, fmt.Sprintf("failed to render query of metric %s, err: %v", metric.Name, err), err))
+			}
+			return false, retryDefault, wrapDoCanaryError(reason, fmt.Sprintf("failed to query metric %s, err: %v", metric.Name, err), err)
+		}
+
+		ok, err := provider.CompareThreshold(value, metric)
+		if err != nil {
+			return false, retryStop, control.TerminalError(wrapDoCanaryError("InvalidAnalysisThreshold", err.Error(), err))
+		}
+		if !ok && inHealthCheckGracePeriod(ctx, time.Now()) {
+			logger.Info("canary metric failed analysis in health check grace period, retry later", "metric", metric.Name, "value", value)
+			return false, retryDefault, nil
+		}
+		if !ok {
+			return false, retryStop, control.TerminalError(newDoCanaryError(
+				"AnalysisFailed",
+				fmt.Sprintf("metric %s value %v is out of threshold [%s, %s]", metric.Name, value, ptrOrEmpty(metric.Min), ptrOrEmpty(metric.Max)),
+			))
+		}
+		logger.Info("canary metric passed analysis", "metric", metric.Name, "value", value)
+	}
+	return true, retryImmediately, nil
+}
+
+// canaryQueryVars returns the selectors of canary and stable pods rendered in
+// templated queries, by the namespace and pod labels of metrics. Canary pods are
+// the pods of canary workloads, or the pods of ordinals updated in place.
+func canaryQueryVars(rolloutRun *rolloutv1alpha1.RolloutRun) analysis.QueryVars {
+	canary := rolloutRun.Spec.Canary
+	stableNamespaces, canaryNamespaces := sets.NewString(), sets.NewString()
+	stablePods, canaryPods := sets.NewString(), sets.NewString()
+	for _, target := range canary.Targets {
+		name := regexp.QuoteMeta(target.Name)
+		stableNamespaces.Insert(rolloutRun.Namespace)
+		stablePods.Insert(name + "-.*")
+		if len(canary.Ordinals) > 0 {
+			canaryNamespaces.Insert(rolloutRun.Namespace)
+			for _, ordinal := range canary.Ordinals {
+				canaryPods.Insert(fmt.Sprintf("%s-%d", name, ordinal))
+			}
+			continue
+		}
+		namespace := target.CanaryNamespace
+		if len(namespace) == 0 {
+			namespace = rolloutRun.Namespace
+		}
+		canaryNamespaces.Insert(regexp.QuoteMeta(namespace))
+		canaryPods.Insert(name + "-canary-.*")
+	}
+
+	canaryPodsRegex := strconv.Quote(strings.Join(canaryPods.List(), "|"))
+	return analysis.QueryVars{
+		CanarySelector: fmt.Sprintf("namespace=~%s,pod=~%s",
+			strconv.Quote(strings.Join(canaryNamespaces.List(), "|")), canaryPodsRegex),
+		StableSelector: fmt.Sprintf("namespace=~%s,pod=~%s,pod!~%s",
+			strconv.Quote(strings.Join(stableNamespaces.List(), "|")), strconv.Quote(strings.Join(stablePods.List(), "|")), canaryPodsRegex),
+	}
+}
+
+func ptrOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,77 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/analysis"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+	"kusionstack.io/rollout/pkg/genericregistry"
+)
+
+func Test_canaryQueryVars(t *testing.T) {
+	rolloutRun := testRolloutRun.DeepCopy()
+	rolloutRun.Namespace = "default"
+	rolloutRun.Spec.Canary = &rolloutv1alpha1.RolloutRunCanaryStrategy{
+		Targets: []rolloutv1alpha1.RolloutRunStepTarget{
+			{CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "app.v1"}},
+			{CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-b", Name: "app.v1"}, CanaryNamespace: "canary"},
+		},
+	}
+	vars := canaryQueryVars(rolloutRun)
+	assert.Equal(t, `namespace=~"canary|default",pod=~"app\\.v1-canary-.*"`, vars.CanarySelector)
+	assert.Equal(t, `namespace=~"default",pod=~"app\\.v1-.*",pod!~"app\\.v1-canary-.*"`, vars.StableSelector)
+
+	rolloutRun.Spec.Canary.Ordinals = []int32{3, 4}
+	vars = canaryQueryVars(rolloutRun)
+	assert.Equal(t, `namespace=~"default",pod=~"app\\.v1-3|app\\.v1-4"`, vars.CanarySelector)
+	assert.Equal(t, `namespace=~"default",pod=~"app\\.v1-.*",pod!~"app\\.v1-3|app\\.v1-4"`, vars.StableSelector)
+}
+
+func Test_CanaryExecutor_analyze_invalidQueryTemplate(t *testing.T) {
+	rolloutRun := testRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary = &rolloutv1alpha1.RolloutRunCanaryStrategy{
+		Targets: unimportantTargets,
+		Analysis: &rolloutv1alpha1.CanaryAnalysis{
+			Metrics: []rolloutv1alpha1.AnalysisMetric{{
+				Name:     "error-rate",
+				Provider: rolloutv1alpha1.AnalysisProvider{Name: analysis.ProviderPrometheus, Address: "http://prometheus"},
+				Query:    `sum(rate(http_errors_total{ {{.CanaryPods}} }[{{.Window}}]))`,
+				Max:      ptr.To("0.01"),
+			}},
+		},
+	}
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{State: StepRunning}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+
+	e := newCanaryExecutor(newFakeWebhookExecutor())
+	e.analysisProviders = genericregistry.New[string, analysis.AnalysisProvider]()
+	e.analysisProviders.Register(analysis.ProviderPrometheus, analysis.NewPrometheusProvider(http.DefaultClient))
+
+	done, retry, err := e.analyze(ctx)
+	assert.False(t, done)
+	assert.Equal(t, retryStop, retry)
+	assert.True(t, errors.Is(err, control.TerminalError(nil)))
+	assert.ErrorContains(t, err, analysis.ReasonInvalidQueryTemplate)
+}
@@ -0,0 +1,93 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+// isBakeIdle returns true if the canary is scaled down between active windows.
+func isBakeIdle(status *rolloutv1alpha1.RolloutRunStepStatus) bool {
+	return status.Bake != nil && status.Bake.Idle
+}
+
+// bakeIdleReplicas returns the replicas of each canary workload between active windows.
+func bakeIdleReplicas(bake *rolloutv1alpha1.CanaryBake) intstr.IntOrString {
+	return intstr.FromInt(int(ptr.Deref(bake.IdleReplicas, 0)))
+}
+
+// bake runs the active window of canary after canary traffic is routed. The
+// analysis runs at the end of each active window, then the canary goes idle
+// until the next active window. Without bake schedule, the analysis runs
+// immediately.
+func (e *canaryExecutor) bake(ctx *ExecutorContext) (bool, time.Duration, error) {
+	spec := ctx.RolloutRun.Spec.Canary.Bake
+	if spec == nil {
+		return e.analyze(ctx)
+	}
+	logger := ctx.GetCanaryLogger()
+
+	status := ctx.NewStatus.CanaryStatus
+	if status.Bake == nil {
+		status.Bake = &rolloutv1alpha1.CanaryBakeStatus{Window: 1}
+	}
+	if status.Bake.EndTime == nil {
+		status.Bake.EndTime = ptr.To(metav1.NewTime(time.Now().Add(time.Duration(spec.ActiveSeconds) * time.Second)))
+	}
+	if remaining := time.Until(status.Bake.EndTime.Time); remaining > 0 {
+		logger.Info("canary is baking in active window", "window", status.Bake.Window, "remaining", remaining.String())
+		return false, remaining, nil
+	}
+
+	done, retry, err := e.analyze(ctx)
+	if !done {
+		return false, retry, err
+	}
+
+	if status.Bake.Window >= spec.Windows {
+		logger.Info("canary passed analysis of all active windows", "windows", spec.Windows)
+		return true, retryImmediately, nil
+	}
+
+	logger.Info("canary active window finished, scale it down until the next one", "window", status.Bake.Window)
+	status.Bake.Idle = true
+	status.Bake.EndTime = ptr.To(metav1.NewTime(time.Now().Add(time.Duration(spec.IdleSeconds) * time.Second)))
+	return false, retryImmediately, nil
+}
+
+// waitBakeIdle waits for the idle window to end, then starts the next active
+// window. The readiness, health check and traffic probe are reset so that the
+// scaled up canary is checked again before its traffic is routed.
+func (e *canaryExecutor) waitBakeIdle(ctx *ExecutorContext) (bool, time.Duration, error) {
+	status := ctx.NewStatus.CanaryStatus
+	if remaining := time.Until(status.Bake.EndTime.Time); remaining > 0 {
+		ctx.GetCanaryLogger().Info("canary is scaled down in idle window", "nextWindow", status.Bake.Window+1, "remaining", remaining.String())
+		return false, remaining, nil
+	}
+
+	status.Bake = &rolloutv1alpha1.CanaryBakeStatus{Window: status.Bake.Window + 1}
+	status.TargetReadiness = nil
+	status.HealthCheckStartTime = nil
+	status.TrafficProbe = nil
+	return false, retryImmediately, nil
+}
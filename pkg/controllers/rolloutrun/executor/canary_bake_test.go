@@ -0,0 +1,93 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+func Test_CanaryExecutor_bake(t *testing.T) {
+	rolloutRun := testRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary = &rolloutv1alpha1.RolloutRunCanaryStrategy{
+		Targets: unimportantTargets,
+		Bake: &rolloutv1alpha1.CanaryBake{
+			Windows:       2,
+			ActiveSeconds: 60,
+			IdleSeconds:   30,
+		},
+	}
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{
+		State: StepRunning,
+	}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+	status := ctx.NewStatus.CanaryStatus
+	past := ptr.To(metav1.NewTime(time.Now().Add(-time.Second)))
+
+	e := newCanaryExecutor(newFakeWebhookExecutor())
+
+	// first active window starts
+	done, retry, err := e.bake(ctx)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Greater(t, retry, 50*time.Second)
+	assert.EqualValues(t, 1, status.Bake.Window)
+	assert.False(t, isBakeIdle(status))
+
+	// active window ends, go idle
+	status.Bake.EndTime = past
+	done, retry, err = e.bake(ctx)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, retryImmediately, retry)
+	assert.True(t, isBakeIdle(status))
+	assert.Equal(t, intstr.FromInt(0), bakeIdleReplicas(rolloutRun.Spec.Canary.Bake))
+
+	// waiting in idle window
+	done, retry, err = e.waitBakeIdle(ctx)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Greater(t, retry, 20*time.Second)
+
+	// idle window ends, the next active window checks canary again
+	status.Bake.EndTime = past
+	status.HealthCheckStartTime = past
+	status.TrafficProbe = &rolloutv1alpha1.TrafficProbeStatus{StartTime: past}
+	_, _, err = e.waitBakeIdle(ctx)
+	assert.NoError(t, err)
+	status = ctx.NewStatus.CanaryStatus
+	assert.EqualValues(t, 2, status.Bake.Window)
+	assert.False(t, isBakeIdle(status))
+	assert.Nil(t, status.Bake.EndTime)
+	assert.Nil(t, status.HealthCheckStartTime)
+	assert.Nil(t, status.TrafficProbe)
+
+	// last active window finishes
+	_, _, err = e.bake(ctx)
+	assert.NoError(t, err)
+	status.Bake.EndTime = past
+	done, _, err = e.bake(ctx)
+	assert.NoError(t, err)
+	assert.True(t, done)
+}
@@ -0,0 +1,104 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+// isBlueGreen returns true if canary of rolloutRun is released in blue/green,
+// where canary receives no traffic until it is validated.
+func isBlueGreen(rolloutRun *rolloutv1alpha1.RolloutRun) bool {
+	return rolloutRun.Spec.Canary.Strategy == rolloutv1alpha1.CanaryStrategyBlueGreen
+}
+
+// cutOverBlueGreen switches all traffic to a validated blue/green canary at
+// once. The traffic weight of blue/green is validated to be 100, so it is the
+// same fork as canary, only deferred after the post canary step hook. It is
+// switched back at once by reverting canary traffic in recycle on rollback. The traffic ready
+// hook follows the cut over.
+func (e *canaryExecutor) cutOverBlueGreen(ctx *ExecutorContext) (bool, time.Duration, error) {
+	if !isBlueGreen(ctx.RolloutRun) {
+		return true, retryImmediately, nil
+	}
+	if !pauseBefore(ctx, rolloutv1alpha1.CanaryPauseBeforeForkCanary) {
+		return false, retryDefault, nil
+	}
+	ctx.GetCanaryLogger().Info("switch all traffic to blue/green canary")
+	done, retry, err := e.modifyTraffic(ctx, "forkCanary")
+	if !done {
+		return false, retry, err
+	}
+	return e.doTrafficReadyHook(ctx)
+}
+
+// blueGreenRecycleOrder returns the recycle order of a succeeded blue/green
+// canary. The old stable is torn down by promoting it to the canary revision
+// first, while the canary route still serves all traffic, so that reverting
+// canary traffic afterwards never routes traffic to the old revision.
+func blueGreenRecycleOrder(order []rolloutv1alpha1.CanaryRecycleOperation) []rolloutv1alpha1.CanaryRecycleOperation {
+	return append([]rolloutv1alpha1.CanaryRecycleOperation{rolloutv1alpha1.PromoteStableRevision}, order...)
+}
+
+// promoteBlueGreenStable updates all pods of stable targets to the canary
+// revision, and waits until they are ready.
+func (e *canaryExecutor) promoteBlueGreenStable(ctx *ExecutorContext) (bool, time.Duration, error) {
+	if _, ok := ctx.Accessor.(workload.BatchReleaseControl); !ok {
+		return false, retryStop, control.TerminalError(newDoCanaryError(
+			"BlueGreenPromotionUnsupported",
+			fmt.Sprintf("workload %s does not support updating stable pods", ctx.Accessor.GroupVersionKind().Kind),
+		))
+	}
+	targets, wait, err := reachableCanaryTargets(ctx, time.Now())
+	if err != nil {
+		return false, retryStop, err
+	}
+	if wait {
+		return false, retryDefault, nil
+	}
+
+	logger := ctx.GetCanaryLogger()
+	batchControl := control.NewBatchReleaseControl(ctx.Accessor, ctx.Client)
+	all := intstr.FromString("100%")
+	changed := false
+	for _, item := range targets {
+		updated, err := batchControl.UpdatePartition(item.info, all)
+		if err != nil {
+			return false, retryStop, err
+		}
+		changed = changed || updated
+	}
+	if changed {
+		logger.Info("promote stable to blue/green canary revision, wait for next check")
+		return false, retryDefault, nil
+	}
+
+	for _, item := range targets {
+		if !item.info.CheckUpdatedReady(item.info.Status.Replicas) {
+			logger.V(3).Info("still waiting for stable promoted", "target", item.CrossClusterObjectNameReference)
+			return false, retryDefault, nil
+		}
+	}
+	return true, retryImmediately, nil
+}
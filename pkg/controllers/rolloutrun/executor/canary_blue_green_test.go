@@ -0,0 +1,183 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"kusionstack.io/kube-utils/multicluster/clusterinfo"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/traffic"
+)
+
+func Test_CanaryExecutor_cutOverBlueGreen(t *testing.T) {
+	target := rolloutv1alpha1.RolloutRunStepTarget{
+		CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-1"},
+	}
+	rolloutRun := testRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary = &rolloutv1alpha1.RolloutRunCanaryStrategy{
+		Strategy: rolloutv1alpha1.CanaryStrategyBlueGreen,
+		Targets:  []rolloutv1alpha1.RolloutRunStepTarget{target},
+		Traffic:  &rolloutv1alpha1.TrafficStrategy{Weight: ptr.To[int32](100)},
+	}
+	rolloutRun.Status.Phase = rolloutv1alpha1.RolloutRunPhaseProgressing
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{
+		State: StepPostCanaryStepHook,
+	}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun, newFakeObject("cluster-a", "default", "test-1", 10, 0, 0))
+
+	routing := &rolloutv1alpha1.BackendRouting{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-1-ics", Namespace: "default"},
+		Spec: rolloutv1alpha1.BackendRoutingSpec{
+			TrafficType: rolloutv1alpha1.InClusterTrafficType,
+			Backend: rolloutv1alpha1.CrossClusterObjectReference{
+				ObjectTypeRef:                   rolloutv1alpha1.ObjectTypeRef{APIVersion: "v1", Kind: "Service"},
+				CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-svc"},
+			},
+			Forwarding: &rolloutv1alpha1.BackendForwarding{
+				Stable: rolloutv1alpha1.StableBackendRule{Name: "test-svc-stable"},
+			},
+		},
+	}
+	assert.NoError(t, ctx.Client.Create(ctx, routing))
+	topology := rolloutv1alpha1.TrafficTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Status: rolloutv1alpha1.TrafficTopologyStatus{
+			Topologies: []rolloutv1alpha1.TopologyInfo{{WorkloadRef: target.CrossClusterObjectNameReference, BackendRoutingName: routing.Name}},
+		},
+	}
+	m, err := traffic.NewManager(ctx.Client, newTestLogger(), []rolloutv1alpha1.TrafficTopology{topology})
+	assert.NoError(t, err)
+	m.With(newTestLogger(), rolloutRun.Spec.Canary.Targets, rolloutRun.Spec.Canary.Traffic)
+	ctx.TrafficManager = m
+
+	// the maximum traffic weight does not clamp the cut over
+	e := newCanaryExecutor(newFakeWebhookExecutor())
+	e.maxTrafficWeight = 20
+	m.WithMaxWeight(e.weightCeiling(ctx.RolloutRun))
+	e.recordTrafficCeiling(ctx)
+	assert.Nil(t, ctx.NewStatus.CanaryStatus.TrafficCeiling)
+
+	// all traffic is switched to canary before pause
+	done, retry, err := e.doPostStepHook(ctx)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, retryDefault, retry)
+	assert.Equal(t, rolloutv1alpha1.StepWaitingTraffic, ctx.NewStatus.CanaryStatus.WaitingReason)
+	assert.Equal(t, rolloutv1alpha1.RolloutRunPhaseProgressing, ctx.NewStatus.Phase)
+
+	got := &rolloutv1alpha1.BackendRouting{}
+	assert.NoError(t, ctx.Client.Get(clusterinfo.WithCluster(ctx, "cluster-a"), types.NamespacedName{Namespace: "default", Name: routing.Name}, got))
+	assert.Equal(t, "test-svc-canary", got.Spec.Forwarding.Canary.Name)
+	assert.Equal(t, ptr.To[int32](100), got.Spec.Forwarding.Canary.Weight)
+
+	// canary traffic is not touched in canary strategy
+	ctx.RolloutRun.Spec.Canary.Strategy = rolloutv1alpha1.CanaryStrategyCanary
+	done, _, err = e.cutOverBlueGreen(ctx)
+	assert.NoError(t, err)
+	assert.True(t, done)
+}
+
+func Test_CanaryExecutor_doRecycle_BlueGreen(t *testing.T) {
+	target := rolloutv1alpha1.RolloutRunStepTarget{
+		CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-1"},
+	}
+	rolloutRun := testRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary = &rolloutv1alpha1.RolloutRunCanaryStrategy{
+		Strategy: rolloutv1alpha1.CanaryStrategyBlueGreen,
+		Targets:  []rolloutv1alpha1.RolloutRunStepTarget{target},
+		Traffic:  &rolloutv1alpha1.TrafficStrategy{Weight: ptr.To[int32](100)},
+	}
+	rolloutRun.Status.Phase = rolloutv1alpha1.RolloutRunPhaseProgressing
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{
+		State: StepResourceRecycling,
+	}
+	routing := &rolloutv1alpha1.BackendRouting{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-1-ics", Namespace: "default"},
+		Spec: rolloutv1alpha1.BackendRoutingSpec{
+			TrafficType: rolloutv1alpha1.InClusterTrafficType,
+			Backend: rolloutv1alpha1.CrossClusterObjectReference{
+				ObjectTypeRef:                   rolloutv1alpha1.ObjectTypeRef{APIVersion: "v1", Kind: "Service"},
+				CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-svc"},
+			},
+			// all traffic is cut over to canary
+			Forwarding: &rolloutv1alpha1.BackendForwarding{
+				Stable: rolloutv1alpha1.StableBackendRule{Name: "test-svc-stable"},
+				Canary: rolloutv1alpha1.CanaryBackendRule{
+					Name:            "test-svc-canary",
+					TrafficStrategy: rolloutv1alpha1.TrafficStrategy{Weight: ptr.To[int32](100)},
+				},
+			},
+		},
+	}
+	topology := rolloutv1alpha1.TrafficTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Status: rolloutv1alpha1.TrafficTopologyStatus{
+			Topologies: []rolloutv1alpha1.TopologyInfo{{WorkloadRef: target.CrossClusterObjectNameReference, BackendRoutingName: routing.Name}},
+		},
+	}
+	newContext := func(stable *appsv1.StatefulSet) *ExecutorContext {
+		ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun.DeepCopy(), stable)
+		assert.NoError(t, ctx.Client.Create(ctx, routing.DeepCopy()))
+		m, err := traffic.NewManager(ctx.Client, newTestLogger(), []rolloutv1alpha1.TrafficTopology{topology})
+		assert.NoError(t, err)
+		m.With(newTestLogger(), ctx.RolloutRun.Spec.Canary.Targets, ctx.RolloutRun.Spec.Canary.Traffic)
+		ctx.TrafficManager = m
+		return ctx
+	}
+	getForwarding := func(ctx *ExecutorContext) *rolloutv1alpha1.BackendForwarding {
+		got := &rolloutv1alpha1.BackendRouting{}
+		assert.NoError(t, ctx.Client.Get(clusterinfo.WithCluster(ctx, "cluster-a"), types.NamespacedName{Namespace: "default", Name: routing.Name}, got))
+		return got.Spec.Forwarding
+	}
+	e := newCanaryExecutor(newFakeWebhookExecutor())
+
+	// the old stable is promoted while canary still serves all traffic
+	ctx := newContext(newFakeObject("cluster-a", "default", "test-1", 10, 0, 0))
+	done, _, err := e.doRecycle(ctx)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, "test-svc-canary", getForwarding(ctx).Canary.Name)
+	assert.Equal(t, ptr.To[int32](100), getForwarding(ctx).Canary.Weight)
+	stable := &appsv1.StatefulSet{}
+	assert.NoError(t, ctx.Client.Get(clusterinfo.WithCluster(ctx, "cluster-a"), types.NamespacedName{Namespace: "default", Name: "test-1"}, stable))
+	if stable.Spec.UpdateStrategy.RollingUpdate != nil {
+		assert.Equal(t, int32(0), ptr.Deref(stable.Spec.UpdateStrategy.RollingUpdate.Partition, 0))
+	}
+
+	// canary traffic is reverted to stable once stable is on the canary revision
+	ctx = newContext(newFakeObject("cluster-a", "default", "test-1", 10, 10, 10))
+	_, _, err = e.doRecycle(ctx)
+	assert.NoError(t, err)
+	assert.False(t, getForwarding(ctx).HasCanary())
+
+	// canary traffic is switched back at once on rollback, stable is not promoted
+	rolloutRun.Spec.Cancel = true
+	ctx = newContext(newFakeObject("cluster-a", "default", "test-1", 10, 0, 0))
+	ctx.NewStatus.Cancellation = &rolloutv1alpha1.RolloutRunCancellationStatus{RequestTime: ptr.To(metav1.Now())}
+	_, _, err = e.doRecycle(ctx)
+	assert.NoError(t, err)
+	assert.False(t, getForwarding(ctx).HasCanary())
+	assert.NoError(t, ctx.Client.Get(clusterinfo.WithCluster(ctx, "cluster-a"), types.NamespacedName{Namespace: "default", Name: "test-1"}, stable))
+	assert.Equal(t, ptr.To[int32](10), stable.Spec.UpdateStrategy.RollingUpdate.Partition)
+}
@@ -0,0 +1,93 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+const ReasonCanaryCompletedHookFailed = "CanaryCompletedHookFailed"
+
+// recordCanaryCompletion records the outcome of canary in status. It is
+// recorded only once until canary is resumed by a manual command.
+func recordCanaryCompletion(ctx *ExecutorContext, outcome rolloutv1alpha1.CanaryOutcome, result *rolloutv1alpha1.CodeReasonMessage) {
+	status := ctx.NewStatus.CanaryStatus
+	if status.Completion != nil {
+		return
+	}
+	status.Completion = &rolloutv1alpha1.CanaryCompletionStatus{
+		Outcome:  outcome,
+		Revision: canaryRevision(ctx),
+		Result:   result.DeepCopy(),
+		Time:     ptr.To(metav1.Now()),
+	}
+}
+
+// resetCanaryCompletion forgets the outcome of a failed canary which is resumed.
+func resetCanaryCompletion(ctx *ExecutorContext) {
+	if ctx.inCanary() {
+		ctx.NewStatus.CanaryStatus.Completion = nil
+	}
+}
+
+// canaryRevision returns the updated revision of the first canary target found.
+func canaryRevision(ctx *ExecutorContext) string {
+	for _, item := range ctx.RolloutRun.Spec.Canary.Targets {
+		info := ctx.Workloads.Get(item.Cluster, item.Name)
+		if info != nil && len(info.Status.UpdatedRevision) > 0 {
+			return info.Status.UpdatedRevision
+		}
+	}
+	return ""
+}
+
+// doCompletedHook records the outcome of canary and runs CanaryCompletedHook
+// webhooks. The webhook executor gives up a failed webhook after it is retried
+// failureThreshold times, so that it never blocks the completion.
+func (e *canaryExecutor) doCompletedHook(ctx *ExecutorContext, outcome rolloutv1alpha1.CanaryOutcome, result *rolloutv1alpha1.CodeReasonMessage) (bool, time.Duration, error) {
+	recordCanaryCompletion(ctx, outcome, result)
+	completion := ctx.NewStatus.CanaryStatus.Completion
+	if completion.Notified {
+		return true, retryImmediately, nil
+	}
+	done, retry, err := e.doWebhook(ctx, rolloutv1alpha1.CanaryCompletedHook)
+	if !done {
+		return false, retry, err
+	}
+	completion.Notified = true
+	return true, retryImmediately, nil
+}
+
+// notifyFailure runs CanaryCompletedHook webhooks for canary failed with a
+// terminal error, while rolloutRun stops on the error.
+func (e *canaryExecutor) notifyFailure(ctx *ExecutorContext) (ctrl.Result, error) {
+	completion := ctx.NewStatus.CanaryStatus.Completion
+	if completion == nil || completion.Notified {
+		return ctrl.Result{}, nil
+	}
+	done, retry, err := e.doCompletedHook(ctx, completion.Outcome, completion.Result)
+	if done || err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctx.Retry.result(retry), nil
+}
@@ -0,0 +1,113 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+type countingWebhookExecutor struct {
+	calls map[rolloutv1alpha1.HookType]int
+	done  bool
+}
+
+func (e *countingWebhookExecutor) Do(ctx *ExecutorContext, hookType rolloutv1alpha1.HookType) (bool, time.Duration, error) {
+	e.calls[hookType]++
+	return e.done, retryDefault, nil
+}
+
+func Test_canaryExecutor_doCompletedHook(t *testing.T) {
+	webhook := &countingWebhookExecutor{calls: map[rolloutv1alpha1.HookType]int{}}
+	e := newCanaryExecutor(webhook)
+
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary.Targets = []rolloutv1alpha1.RolloutRunStepTarget{{
+		CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-1"},
+	}}
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{State: StepResourceRecycling}
+	obj := newFakeObject("cluster-a", "default", "test-1", 10, 0, 0)
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun, obj)
+	ctx.Workloads.Get("cluster-a", "test-1").Status.UpdatedRevision = "rev-2"
+
+	// webhook is running
+	done, retry, err := e.doCompletedHook(ctx, rolloutv1alpha1.CanarySucceeded, nil)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, retryDefault, retry)
+	completion := ctx.NewStatus.CanaryStatus.Completion
+	if assert.NotNil(t, completion) {
+		assert.Equal(t, rolloutv1alpha1.CanarySucceeded, completion.Outcome)
+		assert.Equal(t, "rev-2", completion.Revision)
+		assert.Nil(t, completion.Result)
+		assert.False(t, completion.Notified)
+	}
+	assert.Equal(t, rolloutv1alpha1.StepWaitingWebhook, ctx.NewStatus.CanaryStatus.WaitingReason)
+
+	// outcome is recorded only once
+	webhook.done = true
+	done, _, err = e.doCompletedHook(ctx, rolloutv1alpha1.CanaryFailed, nil)
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, rolloutv1alpha1.CanarySucceeded, ctx.NewStatus.CanaryStatus.Completion.Outcome)
+	assert.True(t, ctx.NewStatus.CanaryStatus.Completion.Notified)
+
+	// notified completion does not invoke webhook again
+	done, _, err = e.doCompletedHook(ctx, rolloutv1alpha1.CanarySucceeded, nil)
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, 2, webhook.calls[rolloutv1alpha1.CanaryCompletedHook])
+}
+
+func Test_canaryExecutor_notifyFailure(t *testing.T) {
+	webhook := &countingWebhookExecutor{calls: map[rolloutv1alpha1.HookType]int{}}
+	e := newCanaryExecutor(webhook)
+
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{State: StepRunning}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+
+	// failure not recorded by canary is not notified
+	result, err := e.notifyFailure(ctx)
+	assert.NoError(t, err)
+	assert.False(t, result.Requeue)
+	assert.Zero(t, webhook.calls[rolloutv1alpha1.CanaryCompletedHook])
+
+	ctx.Fail(newDoCanaryError("TestFailed", "canary failed"))
+	recordCanaryCompletion(ctx, rolloutv1alpha1.CanaryFailed, ctx.NewStatus.Error)
+	result, err = e.notifyFailure(ctx)
+	assert.NoError(t, err)
+	assert.NotZero(t, result.RequeueAfter)
+	assert.Equal(t, 1, webhook.calls[rolloutv1alpha1.CanaryCompletedHook])
+	if assert.NotNil(t, ctx.NewStatus.CanaryStatus.Completion.Result) {
+		assert.Equal(t, "TestFailed", ctx.NewStatus.CanaryStatus.Completion.Result.Reason)
+	}
+
+	webhook.done = true
+	result, err = e.notifyFailure(ctx)
+	assert.NoError(t, err)
+	assert.False(t, result.Requeue)
+	assert.True(t, ctx.NewStatus.CanaryStatus.Completion.Notified)
+
+	// retried canary forgets the failure
+	resetCanaryCompletion(ctx)
+	assert.Nil(t, ctx.NewStatus.CanaryStatus.Completion)
+}
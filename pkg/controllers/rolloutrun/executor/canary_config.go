@@ -0,0 +1,60 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"kusionstack.io/kube-utils/multicluster/clusterinfo"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+)
+
+const ReasonCanaryConfigNotFound = "CanaryConfigNotFound"
+
+// checkCanaryConfigOverrides checks the alternate configs referenced by canary
+// of each target exist in its canary namespace. They are not created by
+// rollout, so canary pods would be stuck in creating without them.
+func checkCanaryConfigOverrides(ctx *ExecutorContext, targets []canaryTarget) error {
+	for _, item := range targets {
+		namespace, cluster := item.canaryNamespace(), item.info.ClusterName
+		clusterCtx := clusterinfo.WithCluster(ctx, cluster)
+
+		for _, override := range item.ConfigOverrides {
+			var obj client.Object = &corev1.ConfigMap{}
+			if override.Kind == rolloutv1alpha1.CanaryConfigSecret {
+				obj = &corev1.Secret{}
+			}
+			err := ctx.Client.Get(clusterCtx, types.NamespacedName{Namespace: namespace, Name: override.CanaryName}, obj)
+			if apierrors.IsNotFound(err) {
+				return control.TerminalError(newDoCanaryError(
+					ReasonCanaryConfigNotFound,
+					fmt.Sprintf("canary %s %s/%s of target %s is not found in cluster %q", override.Kind, namespace, override.CanaryName, item.CrossClusterObjectNameReference, cluster),
+				))
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
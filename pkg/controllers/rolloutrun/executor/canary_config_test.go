@@ -0,0 +1,58 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"kusionstack.io/kube-utils/multicluster/clusterinfo"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+)
+
+func Test_checkCanaryConfigOverrides(t *testing.T) {
+	stable := newFakeObject("cluster-a", "default", "test-1", 10, 0, 0)
+	config := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app-config-canary"}}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), testCanaryRolloutRun.DeepCopy(), stable)
+	assert.NoError(t, ctx.Client.Create(clusterinfo.WithCluster(ctx, "cluster-a"), config))
+	newTarget := func(overrides ...rolloutv1alpha1.CanaryConfigOverride) canaryTarget {
+		return canaryTarget{
+			RolloutRunStepTarget: rolloutv1alpha1.RolloutRunStepTarget{
+				CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-1"},
+				ConfigOverrides:                 overrides,
+			},
+			info: ctx.Workloads.ToSlice()[0],
+		}
+	}
+
+	assert.NoError(t, checkCanaryConfigOverrides(ctx, []canaryTarget{
+		newTarget(),
+		newTarget(rolloutv1alpha1.CanaryConfigOverride{Kind: rolloutv1alpha1.CanaryConfigMap, Name: "app-config", CanaryName: "app-config-canary"}),
+	}))
+
+	// a ConfigMap of the same name does not satisfy a Secret override
+	err := checkCanaryConfigOverrides(ctx, []canaryTarget{
+		newTarget(rolloutv1alpha1.CanaryConfigOverride{Kind: rolloutv1alpha1.CanaryConfigSecret, Name: "app-config", CanaryName: "app-config-canary"}),
+	})
+	assert.True(t, errors.Is(err, control.TerminalError(nil)))
+	assert.ErrorContains(t, err, ReasonCanaryConfigNotFound)
+}
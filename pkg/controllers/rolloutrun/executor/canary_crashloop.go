@@ -0,0 +1,199 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+	"kusionstack.io/kube-utils/multicluster/clusterinfo"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+const (
+	ReasonCanaryBadImage     = "CanaryBadImage"
+	ReasonCanaryCrashLooping = "CanaryCrashLooping"
+
+	defaultCrashLoopRestartThreshold = 3
+	defaultCrashLoopPodThreshold     = 1
+
+	reasonCrashLoopBackOff = "CrashLoopBackOff"
+
+	// maxRecordedPodFailures limits the pod failures recorded in status
+	maxRecordedPodFailures = 10
+)
+
+// badImageReasons are the waiting reasons of container meaning its image can
+// not be pulled even after retries. ErrImagePull is excluded since it is
+// reported for the first failure, which may be transient.
+var badImageReasons = sets.NewString(
+	"ImagePullBackOff",
+	"InvalidImageName",
+	"ErrImageNeverPull",
+)
+
+// crashLoopThresholds returns the restart and pod thresholds of check.
+func crashLoopThresholds(check *rolloutv1alpha1.CanaryCrashLoopCheck) (int32, int32) {
+	if check == nil {
+		return defaultCrashLoopRestartThreshold, defaultCrashLoopPodThreshold
+	}
+	return ptr.Deref(check.RestartThreshold, defaultCrashLoopRestartThreshold),
+		ptr.Deref(check.PodThreshold, defaultCrashLoopPodThreshold)
+}
+
+// isCrashLoopingPod returns true if any container of pod is in CrashLoopBackOff
+// or restarted at least restartThreshold times.
+func isCrashLoopingPod(pod *corev1.Pod, restartThreshold int32) bool {
+	statuses := make([]corev1.ContainerStatus, 0, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	for _, s := range statuses {
+		if s.State.Waiting != nil && s.State.Waiting.Reason == reasonCrashLoopBackOff {
+			return true
+		}
+		if s.RestartCount >= restartThreshold {
+			return true
+		}
+	}
+	return false
+}
+
+// podFailure returns the failure of pod if it can never become ready without
+// a change of canary, i.e. the image of a container can not be pulled or the
+// pod is crash looping, otherwise nil.
+func podFailure(cluster string, pod *corev1.Pod, restartThreshold int32) *rolloutv1alpha1.CanaryPodFailure {
+	statuses := make([]corev1.ContainerStatus, 0, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	for _, s := range statuses {
+		if s.State.Waiting != nil && badImageReasons.Has(s.State.Waiting.Reason) {
+			return &rolloutv1alpha1.CanaryPodFailure{
+				Cluster:   cluster,
+				Pod:       pod.Name,
+				Container: s.Name,
+				Reason:    s.State.Waiting.Reason,
+				Message:   s.State.Waiting.Message,
+			}
+		}
+	}
+	if !isCrashLoopingPod(pod, restartThreshold) {
+		return nil
+	}
+	for _, s := range statuses {
+		if (s.State.Waiting != nil && s.State.Waiting.Reason == reasonCrashLoopBackOff) || s.RestartCount >= restartThreshold {
+			failure := &rolloutv1alpha1.CanaryPodFailure{
+				Cluster:   cluster,
+				Pod:       pod.Name,
+				Container: s.Name,
+				Reason:    reasonCrashLoopBackOff,
+				Message:   fmt.Sprintf("restarted %d times", s.RestartCount),
+			}
+			if s.LastTerminationState.Terminated != nil {
+				terminated := s.LastTerminationState.Terminated
+				failure.Message = fmt.Sprintf("restarted %d times, last terminated with exit code %d, reason: %s",
+					s.RestartCount, terminated.ExitCode, terminated.Reason)
+			}
+			return failure
+		}
+	}
+	return nil
+}
+
+// isBadImageFailure returns true if the image of pod can not be pulled.
+func isBadImageFailure(failure rolloutv1alpha1.CanaryPodFailure) bool {
+	return badImageReasons.Has(failure.Reason)
+}
+
+// failingCanaryPods returns the failures of canary workload pods. It returns
+// nil if the workload does not support listing pods.
+func failingCanaryPods(ctx context.Context, c client.Client, accessor workload.Accessor, canary *workload.Info, restartThreshold int32) ([]rolloutv1alpha1.CanaryPodFailure, error) {
+	pods, err := listCanaryPods(ctx, c, accessor, canary)
+	if err != nil {
+		return nil, err
+	}
+
+	failures := make([]rolloutv1alpha1.CanaryPodFailure, 0)
+	for i := range pods {
+		if failure := podFailure(canary.ClusterName, &pods[i], restartThreshold); failure != nil {
+			failures = append(failures, *failure)
+		}
+	}
+	return failures, nil
+}
+
+// listCanaryPods returns the pods of canary workload. It returns nil if the
+// workload does not support listing pods.
+func listCanaryPods(ctx context.Context, c client.Client, accessor workload.Accessor, canary *workload.Info) ([]corev1.Pod, error) {
+	pc, ok := accessor.(workload.PodControl)
+	if !ok || canary.Object == nil {
+		return nil, nil
+	}
+	selector, err := pc.GetPodSelector(canary.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	pods := &corev1.PodList{}
+	err = c.List(clusterinfo.WithCluster(ctx, canary.ClusterName), pods, client.InNamespace(canary.Namespace), client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+// failingOrdinalPods returns the failures of pods of ordinals updated in place.
+func failingOrdinalPods(c client.Client, accessor workload.Accessor, stable *workload.Info, ordinals []int32, restartThreshold int32) ([]rolloutv1alpha1.CanaryPodFailure, error) {
+	pods, err := accessor.(workload.OrdinalCanaryControl).GetOrdinalPods(c, stable.Object, ordinals)
+	if err != nil {
+		return nil, err
+	}
+	failures := make([]rolloutv1alpha1.CanaryPodFailure, 0)
+	for _, pod := range pods {
+		if failure := podFailure(stable.ClusterName, pod, restartThreshold); failure != nil {
+			failures = append(failures, *failure)
+		}
+	}
+	return failures, nil
+}
+
+// podFailureNames returns the names of pods of failures.
+func podFailureNames(failures []rolloutv1alpha1.CanaryPodFailure) []string {
+	names := make([]string, 0, len(failures))
+	for _, failure := range failures {
+		names = append(names, failure.Pod)
+	}
+	return names
+}
+
+// recordPodFailures records the failures of canary pods in status, so that the
+// reason is visible before the canary fails.
+func recordPodFailures(status *rolloutv1alpha1.RolloutRunStepStatus, failures []rolloutv1alpha1.CanaryPodFailure) {
+	if len(failures) == 0 {
+		status.PodFailures = nil
+		return
+	}
+	if len(failures) > maxRecordedPodFailures {
+		failures = failures[:maxRecordedPodFailures]
+	}
+	status.PodFailures = failures
+}
@@ -0,0 +1,140 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+func Test_crashLoopThresholds(t *testing.T) {
+	restart, pod := crashLoopThresholds(nil)
+	assert.Equal(t, int32(defaultCrashLoopRestartThreshold), restart)
+	assert.Equal(t, int32(defaultCrashLoopPodThreshold), pod)
+
+	restart, pod = crashLoopThresholds(&rolloutv1alpha1.CanaryCrashLoopCheck{PodThreshold: ptr.To[int32](2)})
+	assert.Equal(t, int32(defaultCrashLoopRestartThreshold), restart)
+	assert.Equal(t, int32(2), pod)
+}
+
+func Test_isCrashLoopingPod(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []corev1.ContainerStatus
+		want     bool
+	}{
+		{
+			name: "running",
+			statuses: []corev1.ContainerStatus{
+				{State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}, RestartCount: 1},
+			},
+			want: false,
+		},
+		{
+			name: "crash loop back off",
+			statuses: []corev1.ContainerStatus{
+				{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+			},
+			want: true,
+		},
+		{
+			name: "restart count exceeds threshold",
+			statuses: []corev1.ContainerStatus{
+				{State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				{State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}, RestartCount: 3},
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: tt.statuses}}
+			assert.Equal(t, tt.want, isCrashLoopingPod(pod, 3))
+		})
+	}
+}
+
+func Test_podFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []corev1.ContainerStatus
+		want     *rolloutv1alpha1.CanaryPodFailure
+	}{
+		{
+			name: "running",
+			statuses: []corev1.ContainerStatus{
+				{Name: "app", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+			},
+		},
+		{
+			name: "first image pull error is tolerated",
+			statuses: []corev1.ContainerStatus{
+				{Name: "app", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ErrImagePull"}}},
+			},
+		},
+		{
+			name: "image pull back off",
+			statuses: []corev1.ContainerStatus{
+				{Name: "sidecar", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				{Name: "app", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image \"app:bad\""}}},
+			},
+			want: &rolloutv1alpha1.CanaryPodFailure{
+				Cluster: "cluster-a", Pod: "demo-canary-0", Container: "app", Reason: "ImagePullBackOff", Message: "Back-off pulling image \"app:bad\"",
+			},
+		},
+		{
+			name: "crash loop back off",
+			statuses: []corev1.ContainerStatus{
+				{
+					Name:                 "app",
+					State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}},
+					RestartCount:         2,
+				},
+			},
+			want: &rolloutv1alpha1.CanaryPodFailure{
+				Cluster: "cluster-a", Pod: "demo-canary-0", Container: "app", Reason: "CrashLoopBackOff",
+				Message: "restarted 2 times, last terminated with exit code 1, reason: Error",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: tt.statuses}}
+			pod.Name = "demo-canary-0"
+			assert.Equal(t, tt.want, podFailure("cluster-a", pod, 3))
+		})
+	}
+}
+
+func Test_recordPodFailures(t *testing.T) {
+	status := &rolloutv1alpha1.RolloutRunStepStatus{}
+	failures := make([]rolloutv1alpha1.CanaryPodFailure, 0)
+	for i := 0; i < maxRecordedPodFailures+2; i++ {
+		failures = append(failures, rolloutv1alpha1.CanaryPodFailure{Pod: "demo", Reason: "ImagePullBackOff"})
+	}
+	recordPodFailures(status, failures)
+	assert.Len(t, status.PodFailures, maxRecordedPodFailures)
+
+	recordPodFailures(status, nil)
+	assert.Nil(t, status.PodFailures)
+}
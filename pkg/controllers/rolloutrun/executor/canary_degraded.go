@@ -0,0 +1,136 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+const (
+	ReasonClusterDegraded  = "ClusterDegraded"
+	ReasonClusterRecovered = "ClusterRecovered"
+)
+
+// canaryTarget is a canary target with its stable workload.
+type canaryTarget struct {
+	rolloutv1alpha1.RolloutRunStepTarget
+	info *workload.Info
+}
+
+// canaryNamespace returns the namespace in which canary workload is created.
+func (t canaryTarget) canaryNamespace() string {
+	if len(t.CanaryNamespace) > 0 {
+		return t.CanaryNamespace
+	}
+	return t.info.Namespace
+}
+
+// reachableCanaryTargets returns the canary targets in reachable clusters.
+//
+// If degraded mode is disabled, any missing workload is an error. Otherwise the
+// clusters with missing workloads are recorded in status and their targets are
+// skipped. wait is true if any of them is still in the grace period, clusters
+// exceeding the grace period are marked degraded. A cluster is removed from
+// status once all of its targets are found again.
+func reachableCanaryTargets(ctx *ExecutorContext, now time.Time) (targets []canaryTarget, wait bool, err error) {
+	canary := ctx.RolloutRun.Spec.Canary
+	missing := map[string][]string{}
+	for _, item := range canary.Targets {
+		wi := ctx.Workloads.Get(item.Cluster, item.Name)
+		if wi == nil {
+			if canary.DegradedClusterGracePeriodSeconds == nil {
+				return nil, false, newWorkloadNotFoundError(item.CrossClusterObjectNameReference)
+			}
+			missing[item.Cluster] = append(missing[item.Cluster], item.Name)
+			continue
+		}
+		targets = append(targets, canaryTarget{RolloutRunStepTarget: item, info: wi})
+	}
+	targets = lo.Filter(targets, func(t canaryTarget, _ int) bool {
+		_, ok := missing[t.Cluster]
+		return !ok
+	})
+
+	logger := ctx.GetCanaryLogger()
+	status := ctx.NewStatus.CanaryStatus
+	status.UnreachableClusters = lo.Filter(status.UnreachableClusters, func(c rolloutv1alpha1.UnreachableClusterStatus, _ int) bool {
+		if _, ok := missing[c.Cluster]; ok {
+			return true
+		}
+		logger.Info("cluster is reachable again", "cluster", c.Cluster)
+		ctx.Recorder.Eventf(ctx.RolloutRun, corev1.EventTypeNormal, ReasonClusterRecovered, "cluster %s is reachable again", c.Cluster)
+		return false
+	})
+	if len(missing) == 0 {
+		return targets, false, nil
+	}
+
+	clusters := lo.Keys(missing)
+	sort.Strings(clusters)
+	grace := time.Duration(*canary.DegradedClusterGracePeriodSeconds) * time.Second
+	for _, cluster := range clusters {
+		_, index, found := lo.FindIndexOf(status.UnreachableClusters, func(c rolloutv1alpha1.UnreachableClusterStatus) bool {
+			return c.Cluster == cluster
+		})
+		if !found {
+			status.UnreachableClusters = append(status.UnreachableClusters, rolloutv1alpha1.UnreachableClusterStatus{
+				Cluster: cluster,
+				Since:   ptr.To(metav1.NewTime(now)),
+			})
+			index = len(status.UnreachableClusters) - 1
+		}
+		unreachable := &status.UnreachableClusters[index]
+		unreachable.Message = fmt.Sprintf("workloads %v not found", missing[cluster])
+
+		if !unreachable.Degraded && now.Sub(unreachable.Since.Time) >= grace {
+			unreachable.Degraded = true
+			logger.Info("cluster is unreachable beyond grace period, skip it", "cluster", cluster, "since", unreachable.Since.Time)
+			ctx.Recorder.Eventf(ctx.RolloutRun, corev1.EventTypeWarning, ReasonClusterDegraded, "cluster %s is degraded and skipped: %s", cluster, unreachable.Message)
+		}
+		if !unreachable.Degraded {
+			logger.Info("cluster is unreachable, waiting for grace period", "cluster", cluster, "since", unreachable.Since.Time)
+			wait = true
+		}
+	}
+	return targets, wait, nil
+}
+
+// nonDegradedCanaryTargets returns the canary targets excluding the ones in
+// degraded clusters and the ones rejected before canary, whose traffic is never
+// forked.
+func nonDegradedCanaryTargets(ctx *ExecutorContext) []rolloutv1alpha1.RolloutRunStepTarget {
+	targets := excludeRejectedTargets(ctx, ctx.RolloutRun.Spec.Canary.Targets, rolloutv1alpha1.PreCanaryStepHook)
+	status := ctx.NewStatus.CanaryStatus
+	if status == nil || len(status.UnreachableClusters) == 0 {
+		return targets
+	}
+	return lo.Filter(targets, func(t rolloutv1alpha1.RolloutRunStepTarget, _ int) bool {
+		return !lo.ContainsBy(status.UnreachableClusters, func(c rolloutv1alpha1.UnreachableClusterStatus) bool {
+			return c.Degraded && c.Cluster == t.Cluster
+		})
+	})
+}
@@ -0,0 +1,76 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+func Test_reachableCanaryTargets(t *testing.T) {
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary.Targets = []rolloutv1alpha1.RolloutRunStepTarget{
+		{CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-1"}},
+		{CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-b", Name: "test-2"}},
+	}
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun, newFakeObject("cluster-a", "default", "test-1", 10, 0, 0))
+
+	// degraded mode is disabled
+	_, _, err := reachableCanaryTargets(ctx, time.Now())
+	assert.Error(t, err)
+
+	rolloutRun.Spec.Canary.DegradedClusterGracePeriodSeconds = ptr.To[int32](60)
+	now := time.Now()
+
+	// in grace period
+	targets, wait, err := reachableCanaryTargets(ctx, now)
+	assert.NoError(t, err)
+	assert.True(t, wait)
+	if assert.Len(t, targets, 1) {
+		assert.Equal(t, "cluster-a", targets[0].Cluster)
+	}
+	if assert.Len(t, ctx.NewStatus.CanaryStatus.UnreachableClusters, 1) {
+		assert.Equal(t, "cluster-b", ctx.NewStatus.CanaryStatus.UnreachableClusters[0].Cluster)
+		assert.False(t, ctx.NewStatus.CanaryStatus.UnreachableClusters[0].Degraded)
+	}
+
+	// grace period exceeded
+	targets, wait, err = reachableCanaryTargets(ctx, now.Add(2*time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, wait)
+	assert.Len(t, targets, 1)
+	assert.True(t, ctx.NewStatus.CanaryStatus.UnreachableClusters[0].Degraded)
+	assert.Len(t, nonDegradedCanaryTargets(ctx), 1)
+
+	// cluster recovered
+	ctx = createTestExecutorContext(testRollout.DeepCopy(), rolloutRun,
+		newFakeObject("cluster-a", "default", "test-1", 10, 0, 0),
+		newFakeObject("cluster-b", "default", "test-2", 10, 0, 0),
+	)
+	ctx.NewStatus.CanaryStatus.UnreachableClusters = []rolloutv1alpha1.UnreachableClusterStatus{{Cluster: "cluster-b", Degraded: true}}
+	targets, wait, err = reachableCanaryTargets(ctx, now.Add(3*time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, wait)
+	assert.Len(t, targets, 2)
+	assert.Empty(t, ctx.NewStatus.CanaryStatus.UnreachableClusters)
+}
@@ -0,0 +1,142 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"kusionstack.io/kube-utils/multicluster/clusterinfo"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+const (
+	ReasonMissingDependency = "MissingDependency"
+)
+
+// podDependency is an object referenced by pod which must exist before the pod
+// can be started.
+type podDependency struct {
+	kind string
+	name string
+}
+
+// checkCanaryDependencies checks the ConfigMaps, Secrets and ServiceAccount
+// referenced by canary pods exist in the cluster of each target, otherwise canary
+// pods are stuck in Pending or ContainerCreating.
+func checkCanaryDependencies(ctx *ExecutorContext, targets []canaryTarget) error {
+	podControl, ok := ctx.Accessor.(workload.PodControl)
+	if !ok {
+		// we can not get pod template from workload
+		return nil
+	}
+
+	for _, item := range targets {
+		wi := item.info
+		namespace := item.canaryNamespace()
+		template, err := podControl.GetPodTemplate(wi.Object)
+		if err != nil {
+			return err
+		}
+		for _, dep := range podDependencies(&template.Spec) {
+			// only metadata is needed, do not read the data of secrets
+			obj := &metav1.PartialObjectMetadata{}
+			obj.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind(dep.kind))
+			err := ctx.Client.Get(clusterinfo.WithCluster(ctx, wi.ClusterName), client.ObjectKey{Namespace: namespace, Name: dep.name}, obj)
+			if apierrors.IsNotFound(err) {
+				return control.TerminalError(newDoCanaryError(
+					ReasonMissingDependency,
+					fmt.Sprintf("%s %s/%s referenced by canary pods of target %s is not found in cluster %q",
+						dep.kind, namespace, dep.name, item.CrossClusterObjectNameReference, wi.ClusterName),
+				))
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// podDependencies returns the non-optional ConfigMaps and Secrets referenced by
+// volumes and containers, and the ServiceAccount if it is set explicitly. The
+// image pull secrets are not included since a missing one does not block pulling
+// public images.
+func podDependencies(spec *corev1.PodSpec) []podDependency {
+	deps := []podDependency{}
+	seen := map[podDependency]bool{}
+	add := func(kind, name string, optional *bool) {
+		dep := podDependency{kind: kind, name: name}
+		if len(name) == 0 || ptr.Deref(optional, false) || seen[dep] {
+			return
+		}
+		seen[dep] = true
+		deps = append(deps, dep)
+	}
+
+	if len(spec.ServiceAccountName) > 0 {
+		add("ServiceAccount", spec.ServiceAccountName, nil)
+	}
+	for _, v := range spec.Volumes {
+		if v.ConfigMap != nil {
+			add("ConfigMap", v.ConfigMap.Name, v.ConfigMap.Optional)
+		}
+		if v.Secret != nil {
+			add("Secret", v.Secret.SecretName, v.Secret.Optional)
+		}
+		if v.Projected != nil {
+			for _, source := range v.Projected.Sources {
+				if source.ConfigMap != nil {
+					add("ConfigMap", source.ConfigMap.Name, source.ConfigMap.Optional)
+				}
+				if source.Secret != nil {
+					add("Secret", source.Secret.Name, source.Secret.Optional)
+				}
+			}
+		}
+	}
+
+	containers := append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, c := range containers {
+		for _, from := range c.EnvFrom {
+			if from.ConfigMapRef != nil {
+				add("ConfigMap", from.ConfigMapRef.Name, from.ConfigMapRef.Optional)
+			}
+			if from.SecretRef != nil {
+				add("Secret", from.SecretRef.Name, from.SecretRef.Optional)
+			}
+		}
+		for _, env := range c.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil {
+				add("ConfigMap", ref.Name, ref.Optional)
+			}
+			if ref := env.ValueFrom.SecretKeyRef; ref != nil {
+				add("Secret", ref.Name, ref.Optional)
+			}
+		}
+	}
+	return deps
+}
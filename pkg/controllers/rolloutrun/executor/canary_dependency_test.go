@@ -0,0 +1,86 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+)
+
+func Test_podDependencies(t *testing.T) {
+	spec := &corev1.PodSpec{
+		ServiceAccountName: "app",
+		ImagePullSecrets:   []corev1.LocalObjectReference{{Name: "registry"}},
+		Volumes: []corev1.Volume{
+			{VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "config"}}}},
+			{VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "optional", Optional: ptr.To(true)}}},
+			{VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{Sources: []corev1.VolumeProjection{
+				{Secret: &corev1.SecretProjection{LocalObjectReference: corev1.LocalObjectReference{Name: "cert"}}},
+			}}}},
+		},
+		InitContainers: []corev1.Container{
+			{EnvFrom: []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "init"}}}}},
+		},
+		Containers: []corev1.Container{
+			{
+				EnvFrom: []corev1.EnvFromSource{{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "config"}}}},
+				Env: []corev1.EnvVar{
+					{Name: "plain", Value: "value"},
+					{Name: "token", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "token"}, Key: "token"}}},
+				},
+			},
+		},
+	}
+	assert.Equal(t, []podDependency{
+		{kind: "ServiceAccount", name: "app"},
+		{kind: "ConfigMap", name: "config"},
+		{kind: "Secret", name: "cert"},
+		{kind: "Secret", name: "init"},
+		{kind: "Secret", name: "token"},
+	}, podDependencies(spec))
+}
+
+func Test_checkCanaryDependencies(t *testing.T) {
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary.Targets = []rolloutv1alpha1.RolloutRunStepTarget{
+		{CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-1"}},
+	}
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{}
+	stable := newFakeObject("cluster-a", "default", "test-1", 10, 0, 0)
+	stable.Spec.Template.Spec.Volumes = []corev1.Volume{
+		{VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "config"}}}},
+	}
+
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun, stable)
+	targets, _, err := reachableCanaryTargets(ctx, metav1.Now().Time)
+	assert.NoError(t, err)
+	err = checkCanaryDependencies(ctx, targets)
+	assert.True(t, errors.Is(err, control.TerminalError(nil)))
+	assert.ErrorContains(t, err, "ConfigMap default/config")
+
+	err = ctx.Client.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "config"}})
+	assert.NoError(t, err)
+	assert.NoError(t, checkCanaryDependencies(ctx, targets))
+}
@@ -0,0 +1,94 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+	"kusionstack.io/rollout/pkg/utils"
+)
+
+const (
+	ReasonCanaryDriftDetected = "CanaryDriftDetected"
+
+	defaultCanaryDriftThreshold = 5
+)
+
+// driftedFields returns the paths of diff prefixed with the workload, or the
+// workload alone if the diff is unknown.
+func driftedFields(ref rolloutv1alpha1.CrossClusterObjectNameReference, diff []utils.FieldDiff) []string {
+	if len(diff) == 0 {
+		return []string{fmt.Sprintf("%s/%s", ref.Cluster, ref.Name)}
+	}
+	fields := make([]string, 0, len(diff))
+	for _, d := range diff {
+		fields = append(fields, fmt.Sprintf("%s/%s %s", ref.Cluster, ref.Name, d.Path))
+	}
+	return fields
+}
+
+// detectCanaryDrift counts the consecutive reconciles in which canary
+// workloads are updated, with the fields updated in this one. A reconcile
+// without updates means canary workloads are settled, and the count starts
+// over. Once the count reaches the threshold, the drift is surfaced as a
+// warning event, or fails canary if the action is Fail.
+func detectCanaryDrift(ctx *ExecutorContext, fields []string, now time.Time) error {
+	status := ctx.NewStatus.CanaryStatus
+	if len(fields) == 0 {
+		status.Drift = nil
+		return nil
+	}
+	if status.Drift == nil {
+		status.Drift = &rolloutv1alpha1.CanaryDriftStatus{}
+	}
+	status.Drift.ConsecutiveUpdates++
+	status.Drift.Fields = fields
+
+	threshold, action := int32(defaultCanaryDriftThreshold), rolloutv1alpha1.CanaryDriftWarn
+	if detection := ctx.RolloutRun.Spec.Canary.DriftDetection; detection != nil {
+		if detection.Threshold > 0 {
+			threshold = detection.Threshold
+		}
+		if len(detection.Action) > 0 {
+			action = detection.Action
+		}
+	}
+	if status.Drift.ConsecutiveUpdates < threshold {
+		return nil
+	}
+
+	msg := fmt.Sprintf("canary workloads are updated in %d consecutive reconciles, they may be mutated by another controller, fields: %v",
+		status.Drift.ConsecutiveUpdates, fields)
+	if action == rolloutv1alpha1.CanaryDriftFail {
+		status.Drift.DetectedTime = ptr.To(metav1.NewTime(now))
+		return control.TerminalError(newDoCanaryError(ReasonCanaryDriftDetected, msg))
+	}
+	if status.Drift.DetectedTime == nil {
+		// warn once until the workloads are settled
+		status.Drift.DetectedTime = ptr.To(metav1.NewTime(now))
+		ctx.GetCanaryLogger().Info("canary drift detected", "fields", fields)
+		ctx.Recorder.Eventf(ctx.RolloutRun, corev1.EventTypeWarning, ReasonCanaryDriftDetected, "%s", msg)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+	"kusionstack.io/rollout/pkg/utils"
+)
+
+func Test_driftedFields(t *testing.T) {
+	ref := rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-1"}
+	assert.Equal(t, []string{"cluster-a/test-1"}, driftedFields(ref, nil))
+	assert.Equal(t, []string{"cluster-a/test-1 .spec.replicas"}, driftedFields(ref, []utils.FieldDiff{{Path: ".spec.replicas", From: 1, To: 2}}))
+}
+
+func Test_detectCanaryDrift(t *testing.T) {
+	fields := []string{"cluster-a/test-1 .spec.template.metadata.labels"}
+	tests := []struct {
+		name      string
+		detection *rolloutv1alpha1.CanaryDriftDetection
+		updates   int
+		wantErr   bool
+	}{
+		{
+			name:    "default threshold",
+			updates: defaultCanaryDriftThreshold,
+		},
+		{
+			name:      "custom threshold",
+			detection: &rolloutv1alpha1.CanaryDriftDetection{Threshold: 2},
+			updates:   2,
+		},
+		{
+			name:      "fail",
+			detection: &rolloutv1alpha1.CanaryDriftDetection{Threshold: 3, Action: rolloutv1alpha1.CanaryDriftFail},
+			updates:   3,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rolloutRun := testCanaryRolloutRun.DeepCopy()
+			rolloutRun.Spec.Canary.DriftDetection = tt.detection
+			rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{State: StepRunning}
+			ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+			ctx.Initialize()
+			now := time.Now()
+
+			for i := 1; i < tt.updates; i++ {
+				assert.NoError(t, detectCanaryDrift(ctx, fields, now))
+			}
+			drift := ctx.NewStatus.CanaryStatus.Drift
+			assert.EqualValues(t, tt.updates-1, drift.ConsecutiveUpdates)
+			assert.Nil(t, drift.DetectedTime)
+
+			err := detectCanaryDrift(ctx, fields, now)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, control.TerminalError(nil)))
+			} else {
+				assert.NoError(t, err)
+			}
+			drift = ctx.NewStatus.CanaryStatus.Drift
+			assert.EqualValues(t, tt.updates, drift.ConsecutiveUpdates)
+			assert.Equal(t, fields, drift.Fields)
+			assert.NotNil(t, drift.DetectedTime)
+
+			// settled workloads start over
+			assert.NoError(t, detectCanaryDrift(ctx, nil, now))
+			assert.Nil(t, ctx.NewStatus.CanaryStatus.Drift)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"kusionstack.io/kube-utils/multicluster/clusterinfo"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rolloutapi "kusionstack.io/rollout/apis/rollout"
+)
+
+// canaryPodsOutOfRotation returns the ready canary pods which are not yet in the
+// ready endpoints of the Service backends of traffic routings, e.g. because of
+// the lag of readiness gates. Canary pods are selected by the builtin canary
+// selector, which is the selector of the canary Service forked from backend.
+// Routings already forwarding traffic to canary are not checked.
+func canaryPodsOutOfRotation(ctx *ExecutorContext) ([]string, error) {
+	if ctx.TrafficManager == nil {
+		return nil, nil
+	}
+
+	result := make([]string, 0)
+	for _, routing := range ctx.TrafficManager.Routings() {
+		backend := routing.Spec.Backend
+		if backend.Kind != "Service" || backend.APIVersion != corev1.SchemeGroupVersion.String() {
+			continue
+		}
+		if routing.Spec.Forwarding.HasCanary() {
+			continue
+		}
+		namespace := backend.Namespace
+		if len(namespace) == 0 {
+			namespace = routing.Namespace
+		}
+		clusterCtx := clusterinfo.WithCluster(ctx, backend.Cluster)
+
+		svc := &corev1.Service{}
+		if err := ctx.Client.Get(clusterCtx, types.NamespacedName{Namespace: namespace, Name: backend.Name}, svc); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if len(svc.Spec.Selector) == 0 {
+			// endpoints of Service without selector are managed by users
+			continue
+		}
+		selector := client.MatchingLabels{}
+		for k, v := range svc.Spec.Selector {
+			selector[k] = v
+		}
+		selector[rolloutapi.LabelPodRevision] = rolloutapi.LabelValuePodRevisionCanary
+
+		pods := &corev1.PodList{}
+		if err := ctx.Client.List(clusterCtx, pods, client.InNamespace(namespace), selector); err != nil {
+			return nil, err
+		}
+		slices := &discoveryv1.EndpointSliceList{}
+		if err := ctx.Client.List(clusterCtx, slices, client.InNamespace(namespace), client.MatchingLabels{discoveryv1.LabelServiceName: svc.Name}); err != nil {
+			return nil, err
+		}
+		inRotation := readyEndpointPods(slices.Items)
+
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if pod.DeletionTimestamp != nil || !isPodReady(pod) {
+				continue
+			}
+			if !inRotation.Has(pod.Name) {
+				result = append(result, fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
+			}
+		}
+	}
+	return result, nil
+}
+
+// readyEndpointPods returns the names of pods referenced by the ready endpoints
+// of slices.
+func readyEndpointPods(slices []discoveryv1.EndpointSlice) sets.String {
+	result := sets.NewString()
+	for _, slice := range slices {
+		for _, endpoint := range slice.Endpoints {
+			// nil ready condition should be interpreted as ready
+			if endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready {
+				continue
+			}
+			if endpoint.TargetRef != nil && endpoint.TargetRef.Kind == "Pod" {
+				result.Insert(endpoint.TargetRef.Name)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,121 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutapi "kusionstack.io/rollout/apis/rollout"
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/traffic"
+)
+
+func Test_canaryPodsOutOfRotation(t *testing.T) {
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary.Traffic = &rolloutv1alpha1.TrafficStrategy{Weight: ptr.To[int32](10)}
+	target := rolloutv1alpha1.RolloutRunStepTarget{
+		CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-1"},
+	}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun, newFakeObject("cluster-a", "default", "test-1", 10, 0, 0))
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-svc", Namespace: "default"},
+		Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": "test"}},
+	}
+	assert.NoError(t, ctx.Client.Create(ctx, svc))
+	for _, name := range []string{"stable-0", "canary-0", "canary-1", "canary-2"} {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "test"}},
+		}
+		if name != "stable-0" {
+			pod.Labels[rolloutapi.LabelPodRevision] = rolloutapi.LabelValuePodRevisionCanary
+		}
+		assert.NoError(t, ctx.Client.Create(ctx, pod))
+		ready := corev1.ConditionTrue
+		if name == "canary-2" {
+			ready = corev1.ConditionFalse
+		}
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}}
+		assert.NoError(t, ctx.Client.Status().Update(ctx, pod))
+	}
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-svc-abcde",
+			Namespace: "default",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "test-svc"},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints: []discoveryv1.Endpoint{
+			{Addresses: []string{"10.0.0.1"}, TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "stable-0"}},
+			{Addresses: []string{"10.0.0.2"}, TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "canary-0"}, Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(false)}},
+		},
+	}
+	assert.NoError(t, ctx.Client.Create(ctx, slice))
+
+	routing := &rolloutv1alpha1.BackendRouting{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-1-ics", Namespace: "default"},
+		Spec: rolloutv1alpha1.BackendRoutingSpec{
+			TrafficType: rolloutv1alpha1.InClusterTrafficType,
+			Backend: rolloutv1alpha1.CrossClusterObjectReference{
+				ObjectTypeRef:                   rolloutv1alpha1.ObjectTypeRef{APIVersion: "v1", Kind: "Service"},
+				CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-svc"},
+			},
+		},
+	}
+	assert.NoError(t, ctx.Client.Create(ctx, routing))
+	topology := rolloutv1alpha1.TrafficTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Status: rolloutv1alpha1.TrafficTopologyStatus{
+			Topologies: []rolloutv1alpha1.TopologyInfo{{WorkloadRef: target.CrossClusterObjectNameReference, BackendRoutingName: routing.Name}},
+		},
+	}
+	newTrafficManager := func() *traffic.Manager {
+		m, err := traffic.NewManager(ctx.Client, newTestLogger(), []rolloutv1alpha1.TrafficTopology{topology})
+		assert.NoError(t, err)
+		m.With(newTestLogger(), []rolloutv1alpha1.RolloutRunStepTarget{target}, rolloutRun.Spec.Canary.Traffic)
+		return m
+	}
+
+	// canary-0 is not ready in endpoints and canary-1 is missing, canary-2 is not ready yet
+	ctx.TrafficManager = newTrafficManager()
+	pods, err := canaryPodsOutOfRotation(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"default/canary-0", "default/canary-1"}, pods)
+
+	// all ready canary pods are in rotation
+	slice.Endpoints[1].Conditions.Ready = nil
+	slice.Endpoints = append(slice.Endpoints, discoveryv1.Endpoint{Addresses: []string{"10.0.0.3"}, TargetRef: &corev1.ObjectReference{Kind: "Pod", Name: "canary-1"}})
+	assert.NoError(t, ctx.Client.Update(ctx, slice))
+	pods, err = canaryPodsOutOfRotation(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, pods)
+
+	// routings already forwarding to canary are not checked
+	assert.NoError(t, ctx.Client.Delete(ctx, slice))
+	routing.Spec.Forwarding = &rolloutv1alpha1.BackendForwarding{Canary: rolloutv1alpha1.CanaryBackendRule{Name: "test-svc-canary"}}
+	assert.NoError(t, ctx.Client.Update(ctx, routing))
+	ctx.TrafficManager = newTrafficManager()
+	pods, err = canaryPodsOutOfRotation(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, pods)
+}
@@ -0,0 +1,73 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	quota "k8s.io/apiserver/pkg/quota/v1"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+// footprintResources are the resources reported in canary resource footprint.
+var footprintResources = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}
+
+// canaryResourceFootprint sums up the resources requested by the desired
+// replicas of canary and stable workloads from their pod templates. It returns
+// nil if the pod template can not be got from workload.
+func canaryResourceFootprint(accessor workload.Accessor, targets []canaryTarget, canaries []CanaryTargetInfo) (*rolloutv1alpha1.CanaryResourceFootprint, error) {
+	podControl, ok := accessor.(workload.PodControl)
+	if !ok {
+		return nil, nil
+	}
+
+	footprint := &rolloutv1alpha1.CanaryResourceFootprint{
+		Canary: corev1.ResourceList{},
+		Stable: corev1.ResourceList{},
+	}
+	for _, item := range targets {
+		requests, err := templateRequests(podControl, item.info)
+		if err != nil {
+			return nil, err
+		}
+		footprint.Stable = quota.Add(footprint.Stable, requests)
+	}
+	for _, item := range canaries {
+		requests, err := templateRequests(podControl, item.Info)
+		if err != nil {
+			return nil, err
+		}
+		footprint.Canary = quota.Add(footprint.Canary, requests)
+	}
+	return footprint, nil
+}
+
+// templateRequests returns the cpu and memory requested by the desired replicas
+// of workload.
+func templateRequests(podControl workload.PodControl, info *workload.Info) (corev1.ResourceList, error) {
+	template, err := podControl.GetPodTemplate(info.Object)
+	if err != nil {
+		return nil, err
+	}
+	requests, _ := podRequestsAndLimits(&template.Spec)
+	total := corev1.ResourceList{}
+	for name, q := range quota.Mask(requests, footprintResources) {
+		total[name] = multiplyQuantity(q, info.Status.Replicas)
+	}
+	return total, nil
+}
@@ -0,0 +1,67 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"kusionstack.io/rollout/pkg/workload/statefulset"
+)
+
+func Test_canaryResourceFootprint(t *testing.T) {
+	accessor := statefulset.New()
+	newInfo := func(name string, replicas int32) canaryTarget {
+		obj := newFakeObject("cluster-a", "default", name, replicas, 0, 0)
+		obj.Spec.Template.Spec.Containers = []corev1.Container{{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:              resource.MustParse("500m"),
+					corev1.ResourceMemory:           resource.MustParse("1Gi"),
+					corev1.ResourceEphemeralStorage: resource.MustParse("1Gi"),
+				},
+			},
+		}}
+		info, err := accessor.GetInfo("cluster-a", obj)
+		assert.NoError(t, err)
+		return canaryTarget{info: info}
+	}
+
+	targets := []canaryTarget{newInfo("test-1", 10), newInfo("test-2", 4)}
+	canaries := []CanaryTargetInfo{
+		{Info: newInfo("test-1-canary", 2).info},
+		{Info: newInfo("test-2-canary", 1).info},
+	}
+	footprint, err := canaryResourceFootprint(accessor, targets, canaries)
+	assert.NoError(t, err)
+	if assert.NotNil(t, footprint) {
+		assert.Len(t, footprint.Canary, 2)
+		assert.True(t, resource.MustParse("1500m").Equal(footprint.Canary[corev1.ResourceCPU]))
+		assert.True(t, resource.MustParse("3Gi").Equal(footprint.Canary[corev1.ResourceMemory]))
+		assert.True(t, resource.MustParse("7").Equal(footprint.Stable[corev1.ResourceCPU]))
+		assert.True(t, resource.MustParse("14Gi").Equal(footprint.Stable[corev1.ResourceMemory]))
+	}
+
+	// canary ramps up
+	canaries[0].Info = newInfo("test-1-canary", 5).info
+	footprint, err = canaryResourceFootprint(accessor, targets, canaries)
+	assert.NoError(t, err)
+	assert.True(t, resource.MustParse("3").Equal(footprint.Canary[corev1.ResourceCPU]))
+}
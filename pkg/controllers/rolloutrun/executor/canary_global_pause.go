@@ -0,0 +1,53 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"kusionstack.io/kube-utils/multicluster/clusterinfo"
+)
+
+const (
+	// GlobalPauseKey is the key in data of the global pause ConfigMap, all
+	// canaries are frozen if its value is true.
+	GlobalPauseKey = "paused"
+	// GlobalPauseMessageKey is the optional key in data of the global pause
+	// ConfigMap describing why canaries are frozen.
+	GlobalPauseMessageKey = "message"
+)
+
+// isGloballyPaused returns true and the message of global pause if all canaries
+// are frozen. A missing ConfigMap means canaries are not paused, other errors
+// are returned so that the canary is not advanced until it is read.
+func (e *canaryExecutor) isGloballyPaused(ctx *ExecutorContext) (bool, string, error) {
+	if len(e.globalPause.Name) == 0 {
+		return false, "", nil
+	}
+	cm := &corev1.ConfigMap{}
+	err := ctx.Client.Get(clusterinfo.WithCluster(ctx, clusterinfo.Fed), e.globalPause, cm)
+	if apierrors.IsNotFound(err) {
+		return false, "", nil
+	}
+	if err != nil {
+		return false, "", err
+	}
+	paused, _ := strconv.ParseBool(cm.Data[GlobalPauseKey])
+	return paused, cm.Data[GlobalPauseMessageKey], nil
+}
@@ -0,0 +1,64 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+func Test_CanaryExecutor_globalPause(t *testing.T) {
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{State: StepRunning}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun, newFakeObject("cluster-a", "default", "test-1", 10, 0, 0))
+	ctx.Initialize()
+
+	e := newCanaryExecutor(newFakeWebhookExecutor())
+	e.globalPause = types.NamespacedName{Namespace: "kusionstack-rollout", Name: "rollout-global-pause"}
+
+	// missing ConfigMap does not pause canaries
+	paused, _, err := e.isGloballyPaused(ctx)
+	assert.NoError(t, err)
+	assert.False(t, paused)
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: e.globalPause.Namespace, Name: e.globalPause.Name},
+		Data:       map[string]string{GlobalPauseKey: "true", GlobalPauseMessageKey: "incident"},
+	}
+	assert.NoError(t, ctx.Client.Create(ctx, cm))
+
+	// canary holds its current state
+	done, result, err := e.Do(ctx)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, ctx.Retry.Default, result.RequeueAfter)
+	assert.Equal(t, StepRunning, ctx.NewStatus.CanaryStatus.State)
+	assert.Equal(t, rolloutv1alpha1.StepGloballyPaused, ctx.NewStatus.CanaryStatus.WaitingReason)
+
+	// canaries resume once the pause is cleared
+	cm.Data[GlobalPauseKey] = "false"
+	assert.NoError(t, ctx.Client.Update(ctx, cm))
+	paused, _, err = e.isGloballyPaused(ctx)
+	assert.NoError(t, err)
+	assert.False(t, paused)
+}
@@ -0,0 +1,44 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// startHealthCheck records the time when canary starts to be checked for
+// readiness, it is kept in status so that it survives controller restarts.
+func startHealthCheck(ctx *ExecutorContext, now time.Time) {
+	status := ctx.NewStatus.CanaryStatus
+	if status.HealthCheckStartTime == nil {
+		status.HealthCheckStartTime = ptr.To(metav1.NewTime(now))
+	}
+}
+
+// inHealthCheckGracePeriod returns true if canary health check failures should
+// be tolerated because canary pods have just started.
+func inHealthCheckGracePeriod(ctx *ExecutorContext, now time.Time) bool {
+	seconds := ctx.RolloutRun.Spec.Canary.HealthCheckGracePeriodSeconds
+	start := ctx.NewStatus.CanaryStatus.HealthCheckStartTime
+	if seconds == nil || start == nil {
+		return false
+	}
+	return now.Before(start.Add(time.Duration(*seconds) * time.Second))
+}
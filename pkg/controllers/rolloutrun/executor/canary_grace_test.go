@@ -0,0 +1,43 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+)
+
+func Test_inHealthCheckGracePeriod(t *testing.T) {
+	now := time.Now()
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+	ctx.Initialize()
+
+	startHealthCheck(ctx, now)
+	assert.False(t, inHealthCheckGracePeriod(ctx, now), "grace period is not set")
+
+	rolloutRun.Spec.Canary.HealthCheckGracePeriodSeconds = ptr.To[int32](60)
+	assert.True(t, inHealthCheckGracePeriod(ctx, now.Add(59*time.Second)))
+	assert.False(t, inHealthCheckGracePeriod(ctx, now.Add(60*time.Second)))
+
+	// the start time is not refreshed
+	startHealthCheck(ctx, now.Add(time.Hour))
+	assert.Equal(t, now.Unix(), ctx.NewStatus.CanaryStatus.HealthCheckStartTime.Unix())
+}
@@ -0,0 +1,98 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"time"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+const (
+	ReasonCanaryHolding   = "CanaryHolding"
+	ReasonCanaryHoldEnded = "CanaryHoldEnded"
+
+	// canaryHoldResyncPeriod is the period to reconcile canary readiness and
+	// traffic while holding.
+	canaryHoldResyncPeriod = time.Minute
+)
+
+// withHoldingState returns a copy of states with Holding inserted after Running.
+func withHoldingState(states []rolloutv1alpha1.RolloutStepState) []rolloutv1alpha1.RolloutStepState {
+	if lo.Contains(states, StepHolding) {
+		return states
+	}
+	result := make([]rolloutv1alpha1.RolloutStepState, 0, len(states)+1)
+	for _, state := range states {
+		result = append(result, state)
+		if state == StepRunning {
+			result = append(result, StepHolding)
+		}
+	}
+	return result
+}
+
+// isCanaryHoldEnded returns true if the hold of canary is ended and the canary
+// is being recycled without promotion.
+func isCanaryHoldEnded(status *rolloutv1alpha1.RolloutRunStepStatus) bool {
+	return status.Hold != nil && status.Hold.Action == rolloutv1alpha1.CanaryHoldEnd
+}
+
+// endCanaryHold records the action ending the hold, it is taken by the next
+// reconcile of Holding state.
+func endCanaryHold(ctx *ExecutorContext, action rolloutv1alpha1.CanaryHoldAction) {
+	status := ctx.NewStatus.CanaryStatus
+	if status == nil || status.State != StepHolding || status.Hold == nil || len(status.Hold.Action) > 0 {
+		return
+	}
+	status.Hold.Action = action
+}
+
+// doHold holds the canary until the hold is ended by manual command, or the
+// canary is promoted by its max active duration. Canary readiness and traffic
+// keep being reconciled while holding.
+func (e *canaryExecutor) doHold(ctx *ExecutorContext) (bool, time.Duration, error) {
+	logger := ctx.GetCanaryLogger()
+	status := ctx.NewStatus.CanaryStatus
+	if status.Hold == nil {
+		status.Hold = &rolloutv1alpha1.CanaryHoldStatus{StartTime: ptr.To(metav1.Now())}
+		ctx.Recorder.Event(ctx.RolloutRun, corev1.EventTypeNormal, ReasonCanaryHolding, "canary is holding until it is promoted or ended")
+	}
+
+	promotedByDeadline := status.ActiveDeadline != nil && status.ActiveDeadline.Action == rolloutv1alpha1.CanaryMaxActivePromote
+	switch {
+	case status.Hold.Action == rolloutv1alpha1.CanaryHoldPromote || promotedByDeadline:
+		logger.Info("canary hold is ended, promote it", "since", status.Hold.StartTime.Time)
+		return true, retryImmediately, nil
+	case status.Hold.Action == rolloutv1alpha1.CanaryHoldEnd:
+		logger.Info("canary hold is ended, recycle it without promotion", "since", status.Hold.StartTime.Time)
+		ctx.MoveToNextState(StepResourceRecycling)
+		return false, retryImmediately, nil
+	}
+
+	done, retry, err := e.doCanary(ctx)
+	if !done {
+		return false, retry, err
+	}
+	logger.V(1).Info("canary is holding", "since", status.Hold.StartTime.Time)
+	return false, canaryHoldResyncPeriod, nil
+}
@@ -0,0 +1,86 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+func Test_withHoldingState(t *testing.T) {
+	assert.Equal(t,
+		[]rolloutv1alpha1.RolloutStepState{StepNone, StepRunning, StepHolding, StepResourceRecycling},
+		withHoldingState([]rolloutv1alpha1.RolloutStepState{StepNone, StepRunning, StepResourceRecycling}),
+	)
+	// Holding is not inserted twice
+	states := []rolloutv1alpha1.RolloutStepState{StepRunning, StepHolding}
+	assert.Equal(t, states, withHoldingState(states))
+}
+
+func Test_CanaryExecutor_doHold(t *testing.T) {
+	tests := []struct {
+		name      string
+		action    rolloutv1alpha1.CanaryHoldAction
+		wantDone  bool
+		wantState rolloutv1alpha1.RolloutStepState
+	}{
+		{
+			name:      "holding",
+			wantState: StepHolding,
+		},
+		{
+			name:      "promoted",
+			action:    rolloutv1alpha1.CanaryHoldPromote,
+			wantDone:  true,
+			wantState: StepHolding,
+		},
+		{
+			name:      "ended",
+			action:    rolloutv1alpha1.CanaryHoldEnd,
+			wantState: StepResourceRecycling,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rolloutRun := testCanaryRolloutRun.DeepCopy()
+			rolloutRun.Spec.Canary.HoldAtCanary = true
+			rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{State: StepHolding}
+			ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun, newFakeObject("cluster-a", "default", "test-1", 10, 0, 0))
+			ctx.Initialize()
+
+			e := newCanaryExecutor(newFakeWebhookExecutor())
+			assert.Contains(t, e.stateMachineOf(ctx.RolloutRun).graph(StepHolding).States, StepHolding)
+
+			if len(tt.action) > 0 {
+				// the hold is started before the command is taken
+				_, _, _ = e.doHold(ctx)
+				endCanaryHold(ctx, tt.action)
+			}
+			done, _, err := e.doHold(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantDone, done)
+			assert.Equal(t, tt.wantState, ctx.NewStatus.CanaryStatus.State)
+			if assert.NotNil(t, ctx.NewStatus.CanaryStatus.Hold) {
+				assert.Equal(t, tt.action, ctx.NewStatus.CanaryStatus.Hold.Action)
+				assert.Equal(t, tt.action == rolloutv1alpha1.CanaryHoldEnd, isCanaryHoldEnded(ctx.NewStatus.CanaryStatus))
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+	"kusionstack.io/kube-utils/multicluster/clusterinfo"
+
+	"kusionstack.io/rollout/apis/rollout"
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+const (
+	ReasonImageNotPullable = "ImageNotPullable"
+
+	defaultImagePullCheckTimeoutSeconds = 120
+
+	// imagePullCheckCommand never exists in the images, the check pod only
+	// needs the images to be pulled and is not expected to run.
+	imagePullCheckCommand = "/rollout-image-pull-check"
+)
+
+// imagePullFailedReasons are the waiting reasons of container meaning its
+// image can not be pulled.
+var imagePullFailedReasons = sets.NewString(
+	"ErrImagePull",
+	"ImagePullBackOff",
+	"InvalidImageName",
+	"ErrImageNeverPull",
+)
+
+// checkCanaryImagePull creates a short-lived pod for each target, which pulls
+// the images of canary with the image pull secrets and service account of the
+// workload. It returns true if all images are pulled or the check times out,
+// and a terminal error if any image is not pullable.
+func checkCanaryImagePull(ctx *ExecutorContext, targets []canaryTarget) (bool, error) {
+	check := ctx.RolloutRun.Spec.Canary.ImagePullCheck
+	status := ctx.NewStatus.CanaryStatus
+	if check == nil || (status.ImagePullCheck != nil && status.ImagePullCheck.Finished) {
+		return true, nil
+	}
+	pc, ok := ctx.Accessor.(workload.PodControl)
+	if !ok {
+		return true, nil
+	}
+	logger := ctx.GetCanaryLogger()
+
+	if status.ImagePullCheck == nil {
+		status.ImagePullCheck = &rolloutv1alpha1.ImagePullCheckStatus{
+			StartTime: ptr.To(metav1.Now()),
+		}
+	}
+
+	pulling := make([]string, 0)
+	for _, item := range targets {
+		pod, err := ensureImagePullCheckPod(ctx, pc, item)
+		if err != nil {
+			return false, err
+		}
+		if pod == nil {
+			continue
+		}
+		if container, reason, message := imagePullFailure(pod); len(reason) > 0 {
+			if err := deleteImagePullCheckPods(ctx, targets); err != nil {
+				return false, err
+			}
+			// reset the check so that a manual retry runs it again
+			status.ImagePullCheck = nil
+			return false, control.TerminalError(newDoCanaryError(
+				ReasonImageNotPullable,
+				fmt.Sprintf("image %s of target %s is not pullable, reason: %s, message: %s",
+					container.Image, item.CrossClusterObjectNameReference, reason, message),
+			))
+		}
+		if !isImagePulled(pod) {
+			pulling = append(pulling, item.CrossClusterObjectNameReference.String())
+		}
+	}
+
+	timeout := time.Duration(defaultImagePullCheckTimeoutSeconds) * time.Second
+	if check.TimeoutSeconds != nil {
+		timeout = time.Duration(*check.TimeoutSeconds) * time.Second
+	}
+	timedOut := time.Since(status.ImagePullCheck.StartTime.Time) > timeout
+	if len(pulling) > 0 && !timedOut {
+		status.ImagePullCheck.Message = fmt.Sprintf("waiting for images of targets %v to be pulled", pulling)
+		logger.Info("waiting for canary images to be pulled", "targets", pulling)
+		return false, nil
+	}
+
+	if err := deleteImagePullCheckPods(ctx, targets); err != nil {
+		return false, err
+	}
+	status.ImagePullCheck.Finished = true
+	if len(pulling) > 0 {
+		status.ImagePullCheck.Message = fmt.Sprintf("images of targets %v are not pulled within %v, check is skipped", pulling, timeout)
+		logger.Info("canary image pull check timed out, skip it", "targets", pulling, "timeout", timeout)
+	} else {
+		status.ImagePullCheck.Message = "canary images are pullable"
+	}
+	return true, nil
+}
+
+// ensureImagePullCheckPod gets or creates the image pull check pod of target.
+// It returns nil if the workload has no pod template.
+func ensureImagePullCheckPod(ctx *ExecutorContext, pc workload.PodControl, item canaryTarget) (*corev1.Pod, error) {
+	clusterCtx := clusterinfo.WithCluster(ctx, item.info.ClusterName)
+	key := types.NamespacedName{Namespace: item.canaryNamespace(), Name: imagePullCheckPodName(item)}
+
+	pod := &corev1.Pod{}
+	err := ctx.Client.Get(clusterCtx, key, pod)
+	if err == nil {
+		return pod, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	template, err := pc.GetPodTemplate(item.info.Object)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil {
+		return nil, nil
+	}
+	pod = newImagePullCheckPod(key, ctx.RolloutRun.Name, &template.Spec)
+	if err := ctx.Client.Create(clusterCtx, pod); err != nil && !apierrors.IsAlreadyExists(err) {
+		return nil, err
+	}
+	return pod, nil
+}
+
+func imagePullCheckPodName(item canaryTarget) string {
+	return item.Name + "-image-pull-check"
+}
+
+// newImagePullCheckPod returns a pod pulling each distinct image of spec once.
+// It keeps the settings affecting image pulling and scheduling, so that the
+// images are pulled with the same credentials on the same kind of nodes.
+func newImagePullCheckPod(key types.NamespacedName, rolloutRunName string, spec *corev1.PodSpec) *corev1.Pod {
+	resources := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("10m"),
+		corev1.ResourceMemory: resource.MustParse("16Mi"),
+	}
+	containers := make([]corev1.Container, 0)
+	images := sets.NewString()
+	for _, c := range append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...) {
+		if images.Has(c.Image) {
+			continue
+		}
+		images.Insert(c.Image)
+		containers = append(containers, corev1.Container{
+			Name:            fmt.Sprintf("image-%d", len(containers)),
+			Image:           c.Image,
+			ImagePullPolicy: corev1.PullAlways,
+			Command:         []string{imagePullCheckCommand},
+			Resources:       corev1.ResourceRequirements{Requests: resources, Limits: resources},
+		})
+	}
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+			Labels: map[string]string{
+				rollout.LabelImagePullCheck: rolloutRunName,
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers:                   containers,
+			RestartPolicy:                corev1.RestartPolicyNever,
+			ServiceAccountName:           spec.ServiceAccountName,
+			AutomountServiceAccountToken: ptr.To(false),
+			ImagePullSecrets:             spec.ImagePullSecrets,
+			NodeSelector:                 spec.NodeSelector,
+			Affinity:                     spec.Affinity,
+			Tolerations:                  spec.Tolerations,
+		},
+	}
+}
+
+// imagePullFailure returns the container whose image can not be pulled.
+func imagePullFailure(pod *corev1.Pod) (*corev1.Container, string, string) {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Waiting == nil || !imagePullFailedReasons.Has(cs.State.Waiting.Reason) {
+			continue
+		}
+		for i := range pod.Spec.Containers {
+			if pod.Spec.Containers[i].Name == cs.Name {
+				return &pod.Spec.Containers[i], cs.State.Waiting.Reason, cs.State.Waiting.Message
+			}
+		}
+	}
+	return nil, "", ""
+}
+
+// isImagePulled returns true if all images of pod are pulled. The containers
+// are expected to fail to start after their images are pulled.
+func isImagePulled(pod *corev1.Pod) bool {
+	if pod.Status.Phase == corev1.PodFailed || pod.Status.Phase == corev1.PodSucceeded {
+		return true
+	}
+	if len(pod.Status.ContainerStatuses) < len(pod.Spec.Containers) {
+		return false
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Waiting != nil && (len(cs.State.Waiting.Reason) == 0 || cs.State.Waiting.Reason == "ContainerCreating") {
+			return false
+		}
+	}
+	return true
+}
+
+// deleteImagePullCheckPods deletes the image pull check pods of targets.
+func deleteImagePullCheckPods(ctx *ExecutorContext, targets []canaryTarget) error {
+	for _, item := range targets {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: item.canaryNamespace(), Name: imagePullCheckPodName(item)}}
+		err := ctx.Client.Delete(clusterinfo.WithCluster(ctx, item.info.ClusterName), pod)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
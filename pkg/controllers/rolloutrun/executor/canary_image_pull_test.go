@@ -0,0 +1,93 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"kusionstack.io/kube-utils/multicluster/clusterinfo"
+
+	"kusionstack.io/rollout/apis/rollout"
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+)
+
+func Test_checkCanaryImagePull(t *testing.T) {
+	stable := newFakeObject("cluster-a", "default", "test-1", 10, 0, 0)
+	stable.Spec.Template.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "registry"}}
+	stable.Spec.Template.Spec.InitContainers = []corev1.Container{{Name: "init", Image: "app:v2"}}
+	stable.Spec.Template.Spec.Containers = []corev1.Container{{Name: "app", Image: "app:v2"}, {Name: "sidecar", Image: "sidecar:v1"}}
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary.ImagePullCheck = &rolloutv1alpha1.CanaryImagePullCheck{}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun, stable)
+	ctx.NewStatus.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{}
+	targets := []canaryTarget{{
+		RolloutRunStepTarget: rolloutv1alpha1.RolloutRunStepTarget{
+			CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-1"},
+		},
+		info: ctx.Workloads.ToSlice()[0],
+	}}
+	clusterCtx := clusterinfo.WithCluster(ctx, "cluster-a")
+	key := types.NamespacedName{Namespace: "default", Name: "test-1-image-pull-check"}
+	setWaiting := func(reason string) {
+		pod := &corev1.Pod{}
+		assert.NoError(t, ctx.Client.Get(clusterCtx, key, pod))
+		pod.Status.ContainerStatuses = nil
+		for _, c := range pod.Spec.Containers {
+			pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+				Name:  c.Name,
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason}},
+			})
+		}
+		assert.NoError(t, ctx.Client.Update(clusterCtx, pod))
+	}
+
+	// check pod is created with distinct images and image pull secrets
+	pulled, err := checkCanaryImagePull(ctx, targets)
+	assert.NoError(t, err)
+	assert.False(t, pulled)
+	pod := &corev1.Pod{}
+	assert.NoError(t, ctx.Client.Get(clusterCtx, key, pod))
+	assert.Len(t, pod.Spec.Containers, 2)
+	assert.Equal(t, corev1.PullAlways, pod.Spec.Containers[0].ImagePullPolicy)
+	assert.Equal(t, stable.Spec.Template.Spec.ImagePullSecrets, pod.Spec.ImagePullSecrets)
+	assert.Equal(t, rolloutRun.Name, pod.Labels[rollout.LabelImagePullCheck])
+
+	// images are pulled and containers fail to start
+	setWaiting("CreateContainerError")
+	pulled, err = checkCanaryImagePull(ctx, targets)
+	assert.NoError(t, err)
+	assert.True(t, pulled)
+	assert.True(t, ctx.NewStatus.CanaryStatus.ImagePullCheck.Finished)
+	assert.True(t, apierrors.IsNotFound(ctx.Client.Get(clusterCtx, key, &corev1.Pod{})))
+
+	// image is not pullable
+	ctx.NewStatus.CanaryStatus.ImagePullCheck = nil
+	_, err = checkCanaryImagePull(ctx, targets)
+	assert.NoError(t, err)
+	setWaiting("ImagePullBackOff")
+	_, err = checkCanaryImagePull(ctx, targets)
+	assert.True(t, errors.Is(err, control.TerminalError(nil)))
+	assert.ErrorContains(t, err, ReasonImageNotPullable)
+	assert.Nil(t, ctx.NewStatus.CanaryStatus.ImagePullCheck)
+	assert.True(t, apierrors.IsNotFound(ctx.Client.Get(clusterCtx, key, &corev1.Pod{})))
+}
@@ -0,0 +1,60 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+const ReasonInvalidInitContainerOverrides = "InvalidInitContainerOverrides"
+
+// checkCanaryInitContainerOverrides checks the init container overrides of each
+// target can be merged into the pod template of its workload. An override can
+// not take the name of an app container, apiserver rejects the canary workload
+// otherwise.
+func checkCanaryInitContainerOverrides(ctx *ExecutorContext, targets []canaryTarget) error {
+	for _, item := range targets {
+		if len(item.InitContainerOverrides) == 0 {
+			continue
+		}
+		podControl, ok := ctx.Accessor.(workload.PodControl)
+		if !ok {
+			return control.TerminalError(newDoCanaryError(
+				ReasonInvalidInitContainerOverrides,
+				fmt.Sprintf("init container overrides are not supported by workload %s", ctx.Accessor.GroupVersionKind().Kind),
+			))
+		}
+		template, err := podControl.GetPodTemplate(item.info.Object)
+		if err != nil {
+			return err
+		}
+		for _, override := range item.InitContainerOverrides {
+			for _, container := range template.Spec.Containers {
+				if container.Name == override.Name {
+					return control.TerminalError(newDoCanaryError(
+						ReasonInvalidInitContainerOverrides,
+						fmt.Sprintf("init container override %q of target %s conflicts with container of the same name", override.Name, item.CrossClusterObjectNameReference),
+					))
+				}
+			}
+		}
+	}
+	return nil
+}
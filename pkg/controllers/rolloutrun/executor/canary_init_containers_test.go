@@ -0,0 +1,55 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+)
+
+func Test_checkCanaryInitContainerOverrides(t *testing.T) {
+	stable := newFakeObject("cluster-a", "default", "test-1", 10, 0, 0)
+	stable.Spec.Template.Spec.InitContainers = []corev1.Container{{Name: "init", Image: "init:v1"}}
+	stable.Spec.Template.Spec.Containers = []corev1.Container{{Name: "main", Image: "main:v1"}}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), testCanaryRolloutRun.DeepCopy(), stable)
+	newTarget := func(overrides ...corev1.Container) canaryTarget {
+		return canaryTarget{
+			RolloutRunStepTarget: rolloutv1alpha1.RolloutRunStepTarget{
+				CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-1"},
+				InitContainerOverrides:          overrides,
+			},
+			info: ctx.Workloads.ToSlice()[0],
+		}
+	}
+
+	assert.NoError(t, checkCanaryInitContainerOverrides(ctx, []canaryTarget{
+		newTarget(),
+		newTarget(corev1.Container{Name: "init", Image: "init:v2"}, corev1.Container{Name: "migrate", Image: "migrate:v1"}),
+	}))
+
+	err := checkCanaryInitContainerOverrides(ctx, []canaryTarget{
+		newTarget(corev1.Container{Name: "main", Image: "main:v2"}),
+	})
+	assert.True(t, errors.Is(err, control.TerminalError(nil)))
+	assert.ErrorContains(t, err, ReasonInvalidInitContainerOverrides)
+}
@@ -0,0 +1,124 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+const (
+	ReasonCanaryDeadlineApproaching = "CanaryDeadlineApproaching"
+	ReasonCanaryDeadlineExceeded    = "CanaryDeadlineExceeded"
+)
+
+// startActiveDeadline starts the timer of canary max active duration. It is
+// recorded in status so that it survives controller restarts.
+func startActiveDeadline(ctx *ExecutorContext, now time.Time) {
+	maxActive := ctx.RolloutRun.Spec.Canary.MaxActiveDuration
+	status := ctx.NewStatus.CanaryStatus
+	if maxActive == nil || status.ActiveDeadline != nil {
+		return
+	}
+	status.ActiveDeadline = &rolloutv1alpha1.CanaryActiveDeadlineStatus{
+		StartTime: ptr.To(metav1.NewTime(now)),
+		Deadline:  ptr.To(metav1.NewTime(now.Add(time.Duration(maxActive.Seconds) * time.Second))),
+	}
+}
+
+// isCanaryActive returns true if canary is not yet promoted, including the
+// time it is paused after the post canary step hook.
+func isCanaryActive(ctx *ExecutorContext) bool {
+	switch ctx.NewStatus.CanaryStatus.State {
+	case StepPreCanaryStepHook, StepRunning, StepHolding, StepPostCanaryStepHook:
+		return true
+	case StepResourceRecycling:
+		return ctx.NewStatus.Phase == rolloutv1alpha1.RolloutRunPhasePaused
+	}
+	return false
+}
+
+// isRolledBackByDeadline returns true if canary exceeded its max active
+// duration and is being rolled back.
+func isRolledBackByDeadline(status *rolloutv1alpha1.RolloutRunStepStatus) bool {
+	return status.ActiveDeadline != nil && status.ActiveDeadline.Action == rolloutv1alpha1.CanaryMaxActiveRollback
+}
+
+// checkActiveDeadline emits a warning event before the deadline of canary max
+// active duration, and takes the configured action after it. It returns the
+// duration until the next check, 0 means there is nothing to check.
+func (e *canaryExecutor) checkActiveDeadline(ctx *ExecutorContext, now time.Time) time.Duration {
+	maxActive := ctx.RolloutRun.Spec.Canary.MaxActiveDuration
+	status := ctx.NewStatus.CanaryStatus
+	deadline := status.ActiveDeadline
+	if maxActive == nil || deadline == nil || deadline.Deadline == nil || len(deadline.Action) > 0 || !isCanaryActive(ctx) {
+		return 0
+	}
+
+	remaining := deadline.Deadline.Sub(now)
+	if remaining > 0 {
+		if maxActive.WarningSeconds == nil || deadline.Warned {
+			return remaining
+		}
+		lead := time.Duration(*maxActive.WarningSeconds) * time.Second
+		if remaining > lead {
+			return remaining - lead
+		}
+		deadline.Warned = true
+		ctx.Recorder.Eventf(ctx.RolloutRun, corev1.EventTypeWarning, ReasonCanaryDeadlineApproaching,
+			"canary will exceed its max active duration at %s, then %s is taken", deadline.Deadline.UTC().Format(time.RFC3339), maxActive.Action)
+		return remaining
+	}
+
+	deadline.Action = maxActive.Action
+	deadline.Message = fmt.Sprintf("canary exceeded max active duration %ds, %s is taken", maxActive.Seconds, maxActive.Action)
+	ctx.GetCanaryLogger().Info(deadline.Message, "deadline", deadline.Deadline.String())
+	ctx.Recorder.Event(ctx.RolloutRun, corev1.EventTypeWarning, ReasonCanaryDeadlineExceeded, deadline.Message)
+
+	switch maxActive.Action {
+	case rolloutv1alpha1.CanaryMaxActivePromote:
+		status.AutoContinue = true
+	case rolloutv1alpha1.CanaryMaxActiveRollback:
+		ctx.MoveToNextState(StepResourceRecycling)
+	}
+	if ctx.NewStatus.Phase == rolloutv1alpha1.RolloutRunPhasePaused {
+		ctx.NewStatus.Phase = rolloutv1alpha1.RolloutRunPhaseProgressing
+	}
+	return 0
+}
+
+// requeueBefore makes sure the result requeues no later than d.
+func requeueBefore(result ctrl.Result, d time.Duration) ctrl.Result {
+	if d <= 0 {
+		return result
+	}
+	if result.RequeueAfter == 0 && result.Requeue {
+		// requeue immediately
+		return result
+	}
+	if result.RequeueAfter == 0 || result.RequeueAfter > d {
+		result.RequeueAfter = d
+	}
+	return result
+}
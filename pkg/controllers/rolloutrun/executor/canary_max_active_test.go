@@ -0,0 +1,149 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+func Test_CanaryExecutor_checkActiveDeadline(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name       string
+		action     rolloutv1alpha1.CanaryMaxActiveAction
+		phase      rolloutv1alpha1.RolloutRunPhase
+		state      rolloutv1alpha1.RolloutStepState
+		deadline   time.Time
+		warned     bool
+		wantNext   time.Duration
+		wantWarned bool
+		wantAction rolloutv1alpha1.CanaryMaxActiveAction
+		wantPhase  rolloutv1alpha1.RolloutRunPhase
+		wantState  rolloutv1alpha1.RolloutStepState
+	}{
+		{
+			name:      "before warning",
+			action:    rolloutv1alpha1.CanaryMaxActivePromote,
+			phase:     rolloutv1alpha1.RolloutRunPhaseProgressing,
+			state:     StepRunning,
+			deadline:  now.Add(time.Hour),
+			wantNext:  time.Hour - time.Minute,
+			wantPhase: rolloutv1alpha1.RolloutRunPhaseProgressing,
+			wantState: StepRunning,
+		},
+		{
+			name:       "warning",
+			action:     rolloutv1alpha1.CanaryMaxActivePromote,
+			phase:      rolloutv1alpha1.RolloutRunPhasePaused,
+			state:      StepResourceRecycling,
+			deadline:   now.Add(30 * time.Second),
+			wantNext:   30 * time.Second,
+			wantWarned: true,
+			wantPhase:  rolloutv1alpha1.RolloutRunPhasePaused,
+			wantState:  StepResourceRecycling,
+		},
+		{
+			name:       "promote paused canary",
+			action:     rolloutv1alpha1.CanaryMaxActivePromote,
+			phase:      rolloutv1alpha1.RolloutRunPhasePaused,
+			state:      StepResourceRecycling,
+			deadline:   now.Add(-time.Second),
+			warned:     true,
+			wantWarned: true,
+			wantAction: rolloutv1alpha1.CanaryMaxActivePromote,
+			wantPhase:  rolloutv1alpha1.RolloutRunPhaseProgressing,
+			wantState:  StepResourceRecycling,
+		},
+		{
+			name:       "rollback running canary",
+			action:     rolloutv1alpha1.CanaryMaxActiveRollback,
+			phase:      rolloutv1alpha1.RolloutRunPhaseProgressing,
+			state:      StepRunning,
+			deadline:   now.Add(-time.Second),
+			wantAction: rolloutv1alpha1.CanaryMaxActiveRollback,
+			wantPhase:  rolloutv1alpha1.RolloutRunPhaseProgressing,
+			wantState:  StepResourceRecycling,
+		},
+		{
+			name:      "canary is being promoted",
+			action:    rolloutv1alpha1.CanaryMaxActiveRollback,
+			phase:     rolloutv1alpha1.RolloutRunPhaseProgressing,
+			state:     StepResourceRecycling,
+			deadline:  now.Add(-time.Second),
+			wantPhase: rolloutv1alpha1.RolloutRunPhaseProgressing,
+			wantState: StepResourceRecycling,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rolloutRun := testCanaryRolloutRun.DeepCopy()
+			rolloutRun.Spec.Canary.MaxActiveDuration = &rolloutv1alpha1.CanaryMaxActiveDuration{
+				Seconds:        3600,
+				Action:         tt.action,
+				WarningSeconds: ptr.To[int32](60),
+			}
+			rolloutRun.Status.Phase = tt.phase
+			rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{
+				State: tt.state,
+				ActiveDeadline: &rolloutv1alpha1.CanaryActiveDeadlineStatus{
+					Deadline: ptr.To(metav1.NewTime(tt.deadline)),
+					Warned:   tt.warned,
+				},
+			}
+			ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+
+			e := newCanaryExecutor(newFakeWebhookExecutor())
+			next := e.checkActiveDeadline(ctx, now)
+			assert.Equal(t, tt.wantNext, next)
+
+			status := ctx.NewStatus.CanaryStatus
+			assert.Equal(t, tt.wantWarned, status.ActiveDeadline.Warned)
+			assert.Equal(t, tt.wantAction, status.ActiveDeadline.Action)
+			assert.Equal(t, tt.wantPhase, ctx.NewStatus.Phase)
+			assert.Equal(t, tt.wantState, status.State)
+			assert.Equal(t, tt.wantAction == rolloutv1alpha1.CanaryMaxActivePromote, status.AutoContinue)
+		})
+	}
+}
+
+func Test_startActiveDeadline(t *testing.T) {
+	now := time.Now()
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary.MaxActiveDuration = &rolloutv1alpha1.CanaryMaxActiveDuration{
+		Seconds: 600,
+		Action:  rolloutv1alpha1.CanaryMaxActiveRollback,
+	}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+	ctx.Initialize()
+
+	startActiveDeadline(ctx, now)
+	deadline := ctx.NewStatus.CanaryStatus.ActiveDeadline
+	if assert.NotNil(t, deadline) {
+		assert.Equal(t, now.Add(10*time.Minute).Unix(), deadline.Deadline.Unix())
+	}
+
+	// the timer is not restarted
+	startActiveDeadline(ctx, now.Add(time.Minute))
+	assert.Equal(t, now.Unix(), ctx.NewStatus.CanaryStatus.ActiveDeadline.StartTime.Unix())
+}
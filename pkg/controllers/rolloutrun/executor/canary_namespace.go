@@ -0,0 +1,135 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"kusionstack.io/kube-utils/multicluster/clusterinfo"
+
+	rolloutapi "kusionstack.io/rollout/apis/rollout"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+)
+
+const (
+	ReasonCanaryNamespaceNotFound  = "CanaryNamespaceNotFound"
+	ReasonCanaryNamespaceForbidden = "CanaryNamespaceForbidden"
+)
+
+// checkCanaryNamespaces checks the canary namespace of each target exists,
+// allows the namespace of stable workload, and the controller is permitted to
+// create canary workloads in it.
+func checkCanaryNamespaces(ctx *ExecutorContext, targets []canaryTarget) error {
+	gvk := ctx.Accessor.GroupVersionKind()
+	for _, item := range targets {
+		namespace, cluster := item.CanaryNamespace, item.info.ClusterName
+		if len(namespace) == 0 || namespace == item.info.Namespace {
+			continue
+		}
+		clusterCtx := clusterinfo.WithCluster(ctx, cluster)
+
+		ns := &corev1.Namespace{}
+		err := ctx.Client.Get(clusterCtx, types.NamespacedName{Name: namespace}, ns)
+		if apierrors.IsNotFound(err) {
+			return control.TerminalError(newDoCanaryError(
+				ReasonCanaryNamespaceNotFound,
+				fmt.Sprintf("canary namespace %s of target %s is not found in cluster %q", namespace, item.CrossClusterObjectNameReference, cluster),
+			))
+		}
+		if err != nil {
+			return err
+		}
+		if !allowsReferrer(ns, item.info.Namespace) {
+			return control.TerminalError(newDoCanaryError(
+				ReasonCanaryNamespaceForbidden,
+				fmt.Sprintf("canary namespace %s of target %s is not allowed in cluster %q, namespace %s must allow namespace %s by annotation %s",
+					namespace, item.CrossClusterObjectNameReference, cluster, namespace, item.info.Namespace, rolloutapi.AnnoCrossNamespaceAllowedFrom),
+			))
+		}
+
+		review, err := reviewAccess(ctx, cluster, namespace, gvk, "create")
+		if err != nil {
+			return err
+		}
+		if !review.Status.Allowed {
+			return control.TerminalError(newDoCanaryError(
+				ReasonCanaryNamespaceForbidden,
+				fmt.Sprintf("creating %s in canary namespace %s of target %s is forbidden in cluster %q, reason: %s",
+					review.Spec.ResourceAttributes.Resource, namespace, item.CrossClusterObjectNameReference, cluster, review.Status.Reason),
+			))
+		}
+	}
+	return nil
+}
+
+// allowsCrossNamespaceFrom returns true if namespace in cluster allows the
+// resources in referrer namespace to reference it by annotation
+// rollout.kusionstack.io/cross-namespace-allowed-from. The controller may act
+// on any namespace, so the permission of controller is not enough to let users
+// reach into namespaces which they can not access.
+func allowsCrossNamespaceFrom(ctx *ExecutorContext, cluster, namespace, referrer string) (bool, error) {
+	ns := &corev1.Namespace{}
+	err := ctx.Client.Get(clusterinfo.WithCluster(ctx, cluster), types.NamespacedName{Name: namespace}, ns)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return allowsReferrer(ns, referrer), nil
+}
+
+// allowsReferrer returns true if the annotation rollout.kusionstack.io/cross-namespace-allowed-from
+// of namespace contains referrer or "*".
+func allowsReferrer(namespace *corev1.Namespace, referrer string) bool {
+	for _, item := range strings.Split(namespace.Annotations[rolloutapi.AnnoCrossNamespaceAllowedFrom], ",") {
+		item = strings.TrimSpace(item)
+		if item == "*" || item == referrer {
+			return true
+		}
+	}
+	return false
+}
+
+// reviewAccess asks apiserver whether the controller can do verb on the
+// resource of gvk in namespace.
+func reviewAccess(ctx *ExecutorContext, cluster, namespace string, gvk schema.GroupVersionKind, verb string) (*authorizationv1.SelfSubjectAccessReview, error) {
+	mapping, err := ctx.Client.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      verb,
+				Group:     mapping.Resource.Group,
+				Resource:  mapping.Resource.Resource,
+			},
+		},
+	}
+	if err := ctx.Client.Create(clusterinfo.WithCluster(ctx, cluster), review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
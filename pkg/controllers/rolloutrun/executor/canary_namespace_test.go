@@ -0,0 +1,81 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	rolloutapi "kusionstack.io/rollout/apis/rollout"
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+)
+
+func Test_checkCanaryNamespaces(t *testing.T) {
+	stable := newFakeObject("cluster-a", "default", "test-1", 10, 0, 0)
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), testCanaryRolloutRun.DeepCopy(), stable)
+	info := ctx.Workloads.ToSlice()[0]
+	newTarget := func(namespace string) canaryTarget {
+		return canaryTarget{
+			RolloutRunStepTarget: rolloutv1alpha1.RolloutRunStepTarget{
+				CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-1"},
+				CanaryNamespace:                 namespace,
+			},
+			info: info,
+		}
+	}
+
+	// canary in stable namespace is not checked
+	assert.NoError(t, checkCanaryNamespaces(ctx, []canaryTarget{newTarget(""), newTarget("default")}))
+	assert.Equal(t, "default", newTarget("").canaryNamespace())
+	assert.Equal(t, "canary", newTarget("canary").canaryNamespace())
+
+	err := checkCanaryNamespaces(ctx, []canaryTarget{newTarget("canary")})
+	assert.True(t, errors.Is(err, control.TerminalError(nil)))
+	assert.ErrorContains(t, err, ReasonCanaryNamespaceNotFound)
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(appsv1.SchemeGroupVersion.WithKind("StatefulSet"), meta.RESTScopeNamespace)
+	ctx.Client = &accessReviewClient{Client: ctx.Client, mapper: mapper, allowed: sets.NewString("canary")}
+
+	// canary namespace not allowing the stable namespace is rejected even if
+	// the controller is permitted
+	canary := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "canary"}}
+	assert.NoError(t, ctx.Client.Create(ctx, canary))
+	err = checkCanaryNamespaces(ctx, []canaryTarget{newTarget("canary")})
+	assert.True(t, errors.Is(err, control.TerminalError(nil)))
+	assert.ErrorContains(t, err, ReasonCanaryNamespaceForbidden)
+	assert.ErrorContains(t, err, rolloutapi.AnnoCrossNamespaceAllowedFrom)
+
+	canary.Annotations = map[string]string{rolloutapi.AnnoCrossNamespaceAllowedFrom: "default"}
+	assert.NoError(t, ctx.Client.Update(ctx, canary))
+	assert.NoError(t, checkCanaryNamespaces(ctx, []canaryTarget{newTarget("canary")}))
+
+	// the controller is not permitted
+	ctx.Client.(*accessReviewClient).allowed.Delete("canary")
+	err = checkCanaryNamespaces(ctx, []canaryTarget{newTarget("canary")})
+	assert.True(t, errors.Is(err, control.TerminalError(nil)))
+	assert.ErrorContains(t, err, ReasonCanaryNamespaceForbidden)
+	assert.NotContains(t, err.Error(), rolloutapi.AnnoCrossNamespaceAllowedFrom)
+}
@@ -0,0 +1,165 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/ptr"
+
+	rolloutapi "kusionstack.io/rollout/apis/rollout"
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/webhook/probe"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/webhook/probe/http"
+)
+
+// canaryNotifier delivers canary step transitions to notification webhooks in
+// background. The deliveries never affect the canary, their results are kept
+// in memory until they are recorded in canary status by the next reconcile.
+type canaryNotifier struct {
+	newProber func(config rolloutv1alpha1.WebhookClientConfig, cert *http.ClientCert) (probe.WebhookProber, error)
+
+	lock sync.Mutex
+	// results are the last finished deliveries not recorded yet, indexed by
+	// rolloutRun uid and notification name.
+	results map[types.UID]map[string]rolloutv1alpha1.NotificationStatus
+}
+
+func newCanaryNotifier() *canaryNotifier {
+	return &canaryNotifier{
+		newProber: http.NewWithClientCert,
+		results:   make(map[types.UID]map[string]rolloutv1alpha1.NotificationStatus),
+	}
+}
+
+// notify delivers the canary state transition to all notifications asynchronously.
+func (n *canaryNotifier) notify(ctx *ExecutorContext, state rolloutv1alpha1.RolloutStepState) {
+	canary := ctx.RolloutRun.Spec.Canary
+	if canary == nil {
+		return
+	}
+	for _, notification := range canary.Notifications {
+		review := ctx.makeCanaryNotificationReview(notification, state)
+		prober, err := n.newNotificationProber(ctx, notification)
+		if err != nil {
+			n.setResult(ctx.RolloutRun.UID, rolloutv1alpha1.NotificationStatus{
+				Name:    notification.Name,
+				State:   state,
+				Time:    ptr.To(metav1.Now()),
+				Message: err.Error(),
+			})
+			continue
+		}
+		go n.deliver(ctx.RolloutRun.UID, notification.Name, prober, review)
+	}
+}
+
+func (n *canaryNotifier) newNotificationProber(ctx *ExecutorContext, notification rolloutv1alpha1.CanaryNotification) (probe.WebhookProber, error) {
+	cert, err := loadWebhookClientCert(ctx, notification.Name, notification.ClientConfig)
+	if err != nil {
+		return nil, err
+	}
+	return n.newProber(notification.ClientConfig, cert)
+}
+
+func (n *canaryNotifier) deliver(uid types.UID, name string, prober probe.WebhookProber, review rolloutv1alpha1.RolloutWebhookReview) {
+	defer runtime.HandleCrash()
+
+	result := prober.Probe(&review)
+	status := rolloutv1alpha1.NotificationStatus{
+		Name:      name,
+		State:     review.Spec.Canary.State,
+		Delivered: result.Code == rolloutv1alpha1.WebhookReviewCodeOK,
+		Time:      ptr.To(metav1.Now()),
+	}
+	if !status.Delivered {
+		status.Message = fmt.Sprintf("%s: %s", result.Reason, result.Message)
+	}
+	n.setResult(uid, status)
+}
+
+func (n *canaryNotifier) setResult(uid types.UID, status rolloutv1alpha1.NotificationStatus) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if n.results[uid] == nil {
+		n.results[uid] = make(map[string]rolloutv1alpha1.NotificationStatus)
+	}
+	n.results[uid][status.Name] = status
+}
+
+// record moves the finished deliveries of rolloutRun to canary status.
+func (n *canaryNotifier) record(ctx *ExecutorContext) {
+	status := ctx.NewStatus.CanaryStatus
+	if status == nil {
+		return
+	}
+
+	n.lock.Lock()
+	results := n.results[ctx.RolloutRun.UID]
+	delete(n.results, ctx.RolloutRun.UID)
+	n.lock.Unlock()
+
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		recorded := false
+		for i := range status.Notifications {
+			if status.Notifications[i].Name == name {
+				status.Notifications[i] = results[name]
+				recorded = true
+				break
+			}
+		}
+		if !recorded {
+			status.Notifications = append(status.Notifications, results[name])
+		}
+	}
+}
+
+func (c *ExecutorContext) makeCanaryNotificationReview(notification rolloutv1alpha1.CanaryNotification, state rolloutv1alpha1.RolloutStepState) rolloutv1alpha1.RolloutWebhookReview {
+	rolloutRun := c.RolloutRun
+	return rolloutv1alpha1.RolloutWebhookReview{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: rolloutRun.Namespace,
+			Name:      notification.Name,
+			Annotations: map[string]string{
+				rolloutapi.AnnoTraceParent: traceParent(c.TraceID, fmt.Sprintf("%s/%s/%s", rolloutv1alpha1.CanaryNotificationHook, notification.Name, state)),
+			},
+		},
+		Spec: rolloutv1alpha1.RolloutWebhookReviewSpec{
+			Kind:        c.OwnerKind,
+			RolloutName: c.OwnerName,
+			RolloutID:   rolloutRun.Name,
+			HookType:    rolloutv1alpha1.CanaryNotificationHook,
+			Properties:  notification.Properties,
+			TargetType:  rolloutRun.Spec.TargetType,
+			Canary: &rolloutv1alpha1.RolloutWebhookReviewCanary{
+				Targets:    rolloutRun.Spec.Canary.Targets,
+				State:      state,
+				Properties: rolloutRun.Spec.Canary.Properties,
+			},
+		},
+	}
+}
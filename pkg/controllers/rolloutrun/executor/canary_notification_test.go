@@ -0,0 +1,102 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/webhook/probe"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/webhook/probe/http"
+)
+
+type fakeNotificationProber struct {
+	lock    *sync.Mutex
+	reviews *[]rolloutv1alpha1.RolloutWebhookReview
+	code    string
+}
+
+func (p *fakeNotificationProber) Probe(review *rolloutv1alpha1.RolloutWebhookReview) probe.Result {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	*p.reviews = append(*p.reviews, *review)
+	return probe.Result{Code: p.code, Reason: "Fake", Message: "fake result"}
+}
+
+func Test_canaryNotifier(t *testing.T) {
+	lock := &sync.Mutex{}
+	reviews := []rolloutv1alpha1.RolloutWebhookReview{}
+	n := newCanaryNotifier()
+	n.newProber = func(config rolloutv1alpha1.WebhookClientConfig, _ *http.ClientCert) (probe.WebhookProber, error) {
+		code := rolloutv1alpha1.WebhookReviewCodeOK
+		if config.URL != "http://ok" {
+			code = rolloutv1alpha1.WebhookReviewCodeError
+		}
+		return &fakeNotificationProber{lock: lock, reviews: &reviews, code: code}, nil
+	}
+
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary.Notifications = []rolloutv1alpha1.CanaryNotification{
+		{Name: "slack", ClientConfig: rolloutv1alpha1.WebhookClientConfig{URL: "http://ok"}},
+		{Name: "eventing", ClientConfig: rolloutv1alpha1.WebhookClientConfig{URL: "http://fail"}},
+	}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+
+	n.notify(ctx, StepRunning)
+	assert.Eventually(t, func() bool {
+		n.lock.Lock()
+		defer n.lock.Unlock()
+		return len(n.results[rolloutRun.UID]) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	n.record(ctx)
+	status := ctx.NewStatus.CanaryStatus.Notifications
+	if assert.Len(t, status, 2) {
+		assert.Equal(t, "eventing", status[0].Name)
+		assert.Equal(t, StepRunning, status[0].State)
+		assert.False(t, status[0].Delivered)
+		assert.Equal(t, "Fake: fake result", status[0].Message)
+		assert.Equal(t, "slack", status[1].Name)
+		assert.True(t, status[1].Delivered)
+		assert.Empty(t, status[1].Message)
+	}
+	// failed delivery does not affect the canary
+	assert.Nil(t, ctx.NewStatus.Error)
+
+	lock.Lock()
+	if assert.Len(t, reviews, 2) {
+		assert.Equal(t, rolloutv1alpha1.CanaryNotificationHook, reviews[0].Spec.HookType)
+		assert.Equal(t, StepRunning, reviews[0].Spec.Canary.State)
+	}
+	lock.Unlock()
+
+	// recorded results are not recorded again, the last delivery replaces the old one
+	n.record(ctx)
+	assert.Len(t, ctx.NewStatus.CanaryStatus.Notifications, 2)
+	n.deliver(rolloutRun.UID, "slack", &fakeNotificationProber{lock: lock, reviews: &reviews, code: rolloutv1alpha1.WebhookReviewCodeError},
+		ctx.makeCanaryNotificationReview(rolloutRun.Spec.Canary.Notifications[0], StepPostCanaryStepHook))
+	n.record(ctx)
+	status = ctx.NewStatus.CanaryStatus.Notifications
+	if assert.Len(t, status, 2) {
+		assert.Equal(t, StepPostCanaryStepHook, status[1].State)
+		assert.False(t, status[1].Delivered)
+	}
+}
@@ -0,0 +1,41 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+// canaryOwnerReferences returns the owner references of the canary workload of
+// target. The RolloutRun owns the canary workload only if they are in the same
+// cluster and namespace, since owner references can not cross them, so that the
+// canary workload is garbage collected with the RolloutRun. It is not the
+// controller, recycle still deletes canary workloads explicitly.
+func canaryOwnerReferences(rolloutRun *rolloutv1alpha1.RolloutRun, target canaryTarget) []metav1.OwnerReference {
+	refs := make([]metav1.OwnerReference, 0, len(target.OwnerReferences)+1)
+	if len(target.Cluster) == 0 && target.canaryNamespace() == rolloutRun.Namespace && len(rolloutRun.UID) > 0 {
+		refs = append(refs, metav1.OwnerReference{
+			APIVersion: rolloutv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "RolloutRun",
+			Name:       rolloutRun.Name,
+			UID:        rolloutRun.UID,
+		})
+	}
+	return append(refs, target.OwnerReferences...)
+}
@@ -0,0 +1,55 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+func Test_canaryOwnerReferences(t *testing.T) {
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	costCenter := metav1.OwnerReference{APIVersion: "cost.example.com/v1", Kind: "CostCenter", Name: "team-a", UID: "uid-cost"}
+	newTarget := func(cluster, canaryNamespace string) canaryTarget {
+		return canaryTarget{
+			RolloutRunStepTarget: rolloutv1alpha1.RolloutRunStepTarget{
+				CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: cluster, Name: "test-1"},
+				CanaryNamespace:                 canaryNamespace,
+				OwnerReferences:                 []metav1.OwnerReference{costCenter},
+			},
+			info: &workload.Info{ObjectMeta: metav1.ObjectMeta{Namespace: rolloutRun.Namespace, Name: "test-1"}},
+		}
+	}
+
+	// rolloutRun owns canary in the same cluster and namespace
+	refs := canaryOwnerReferences(rolloutRun, newTarget("", ""))
+	if assert.Len(t, refs, 2) {
+		assert.Equal(t, "RolloutRun", refs[0].Kind)
+		assert.Equal(t, rolloutRun.UID, refs[0].UID)
+		assert.Nil(t, refs[0].Controller)
+		assert.Equal(t, costCenter, refs[1])
+	}
+
+	// owner references can not cross namespaces or clusters
+	assert.Equal(t, []metav1.OwnerReference{costCenter}, canaryOwnerReferences(rolloutRun, newTarget("", "canary")))
+	assert.Equal(t, []metav1.OwnerReference{costCenter}, canaryOwnerReferences(rolloutRun, newTarget("cluster-a", "")))
+}
@@ -0,0 +1,61 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"github.com/samber/lo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+// pauseBefore pauses canary before phase if it is listed in pauseBefore, and
+// returns true once the pause is resumed. Each phase is paused once in the step,
+// and it is not paused after the max active duration of canary takes action.
+func pauseBefore(ctx *ExecutorContext, phase rolloutv1alpha1.CanaryPausePhase) bool {
+	if !lo.Contains(ctx.RolloutRun.Spec.Canary.PauseBefore, phase) {
+		return true
+	}
+	status := ctx.NewStatus.CanaryStatus
+	if status.ActiveDeadline != nil && len(status.ActiveDeadline.Action) > 0 {
+		return true
+	}
+
+	_, index, found := lo.FindIndexOf(status.PauseCheckpoints, func(c rolloutv1alpha1.CanaryPauseCheckpoint) bool {
+		return c.Phase == phase
+	})
+	if !found {
+		status.PauseCheckpoints = append(status.PauseCheckpoints, rolloutv1alpha1.CanaryPauseCheckpoint{
+			Phase:     phase,
+			PauseTime: ptr.To(metav1.Now()),
+		})
+		status.PausedBefore = phase
+		status.WaitingReason = rolloutv1alpha1.StepPaused
+		ctx.Pause()
+		ctx.GetCanaryLogger().Info("canary is paused before phase, waiting for resume", "phase", phase)
+		return false
+	}
+
+	checkpoint := &status.PauseCheckpoints[index]
+	if checkpoint.ResumeTime == nil {
+		// canary is only executed again after it is resumed
+		checkpoint.ResumeTime = ptr.To(metav1.Now())
+		status.PausedBefore = ""
+	}
+	return true
+}
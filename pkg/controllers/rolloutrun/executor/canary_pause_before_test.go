@@ -0,0 +1,63 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+func Test_pauseBefore(t *testing.T) {
+	rolloutRun := testRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary = &rolloutv1alpha1.RolloutRunCanaryStrategy{
+		Targets:     unimportantTargets,
+		PauseBefore: []rolloutv1alpha1.CanaryPausePhase{rolloutv1alpha1.CanaryPauseBeforeForkCanary},
+	}
+	rolloutRun.Status.Phase = rolloutv1alpha1.RolloutRunPhaseProgressing
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{
+		State: StepRunning,
+	}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+	status := ctx.NewStatus.CanaryStatus
+
+	// phases not listed are not paused
+	assert.True(t, pauseBefore(ctx, rolloutv1alpha1.CanaryPauseBeforeCreateCanary))
+	assert.Equal(t, rolloutv1alpha1.RolloutRunPhaseProgressing, ctx.NewStatus.Phase)
+
+	assert.False(t, pauseBefore(ctx, rolloutv1alpha1.CanaryPauseBeforeForkCanary))
+	assert.Equal(t, rolloutv1alpha1.RolloutRunPhasePaused, ctx.NewStatus.Phase)
+	assert.Equal(t, rolloutv1alpha1.StepPaused, status.WaitingReason)
+	assert.Equal(t, rolloutv1alpha1.CanaryPauseBeforeForkCanary, status.PausedBefore)
+	if assert.Len(t, status.PauseCheckpoints, 1) {
+		assert.NotNil(t, status.PauseCheckpoints[0].PauseTime)
+		assert.Nil(t, status.PauseCheckpoints[0].ResumeTime)
+	}
+
+	// resumed
+	ctx.NewStatus.Phase = rolloutv1alpha1.RolloutRunPhaseProgressing
+	assert.True(t, pauseBefore(ctx, rolloutv1alpha1.CanaryPauseBeforeForkCanary))
+	assert.Empty(t, status.PausedBefore)
+	assert.NotNil(t, status.PauseCheckpoints[0].ResumeTime)
+
+	// paused only once in the step
+	assert.True(t, pauseBefore(ctx, rolloutv1alpha1.CanaryPauseBeforeForkCanary))
+	assert.Equal(t, rolloutv1alpha1.RolloutRunPhaseProgressing, ctx.NewStatus.Phase)
+	assert.Len(t, status.PauseCheckpoints, 1)
+}
@@ -0,0 +1,85 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+const ReasonCanaryTrafficRestored = "CanaryTrafficRestored"
+
+// pauseCanaryTraffic records the observation weight canary traffic is reduced
+// to while canary is paused after the post canary step hook.
+func pauseCanaryTraffic(ctx *ExecutorContext) {
+	traffic := ctx.RolloutRun.Spec.Canary.Traffic
+	status := ctx.NewStatus.CanaryStatus
+	if traffic == nil || traffic.PausePolicy == nil || traffic.CanaryWeight() == nil || status.PausedTraffic != nil {
+		return
+	}
+	status.PausedTraffic = &rolloutv1alpha1.PausedTrafficStatus{
+		PrePauseWeight: *traffic.CanaryWeight(),
+		Weight:         traffic.PausePolicy.ObservationWeight,
+	}
+}
+
+// isCanaryTrafficPaused returns true if canary is paused with its traffic
+// reduced to the observation weight.
+func isCanaryTrafficPaused(ctx *ExecutorContext) bool {
+	status := ctx.NewStatus.CanaryStatus
+	return status != nil && status.PausedTraffic != nil &&
+		status.State == StepResourceRecycling &&
+		ctx.NewStatus.Phase == rolloutv1alpha1.RolloutRunPhasePaused
+}
+
+// reducePausedTraffic keeps canary traffic at the observation weight during
+// the pause, it returns the duration to check it again.
+func (e *canaryExecutor) reducePausedTraffic(ctx *ExecutorContext) (time.Duration, error) {
+	done, retry, err := e.modifyTraffic(ctx, "reducePausedCanary")
+	if err != nil {
+		return 0, err
+	}
+	if !done {
+		ctx.GetCanaryLogger().Info("reducing canary traffic during pause", "weight", ctx.NewStatus.CanaryStatus.PausedTraffic.Weight)
+		return retry, nil
+	}
+	// the paused rolloutRun is not requeued, resync the weight periodically if enabled
+	return ctx.Retry.TrafficResync, nil
+}
+
+// restorePausedTraffic restores canary traffic to the weight before the pause
+// once canary is resumed, so that it is recycled from the intended weight.
+// There is no need to restore it if canary is rolled back.
+func (e *canaryExecutor) restorePausedTraffic(ctx *ExecutorContext, rollback bool) (bool, time.Duration, error) {
+	status := ctx.NewStatus.CanaryStatus
+	if status.PausedTraffic == nil {
+		return true, retryImmediately, nil
+	}
+	if !rollback {
+		done, retry, err := e.modifyTraffic(ctx, "restorePausedCanary")
+		if !done {
+			return false, retry, err
+		}
+		ctx.Recorder.Eventf(ctx.RolloutRun, corev1.EventTypeNormal, ReasonCanaryTrafficRestored,
+			"canary traffic is restored from weight %d to %d after pause", status.PausedTraffic.Weight, status.PausedTraffic.PrePauseWeight)
+	}
+	status.PausedTraffic = nil
+	return true, retryImmediately, nil
+}
@@ -0,0 +1,84 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/traffic"
+)
+
+func Test_CanaryExecutor_pausedTraffic(t *testing.T) {
+	tests := []struct {
+		name     string
+		rollback bool
+	}{
+		{name: "resumed"},
+		{name: "rolled back", rollback: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rolloutRun := testCanaryRolloutRun.DeepCopy()
+			rolloutRun.Spec.Canary.Traffic = &rolloutv1alpha1.TrafficStrategy{
+				Weight:      ptr.To[int32](50),
+				PausePolicy: &rolloutv1alpha1.TrafficPausePolicy{ObservationWeight: 5},
+			}
+			rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{State: StepPostCanaryStepHook}
+			ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+			ctx.Initialize()
+			trafficManager, err := traffic.NewManager(ctx.Client, newTestLogger(), nil)
+			assert.NoError(t, err)
+			trafficManager.With(newTestLogger(), nil, rolloutRun.Spec.Canary.Traffic)
+			ctx.TrafficManager = trafficManager
+
+			e := newCanaryExecutor(newFakeWebhookExecutor())
+			done, _, err := e.doPostStepHook(ctx)
+			assert.NoError(t, err)
+			assert.True(t, done)
+			assert.Equal(t, &rolloutv1alpha1.PausedTrafficStatus{PrePauseWeight: 50, Weight: 5}, ctx.NewStatus.CanaryStatus.PausedTraffic)
+
+			ctx.MoveToNextState(StepResourceRecycling)
+			assert.True(t, isCanaryTrafficPaused(ctx))
+			_, err = e.reducePausedTraffic(ctx)
+			assert.NoError(t, err)
+
+			// resumed
+			ctx.NewStatus.Phase = rolloutv1alpha1.RolloutRunPhaseProgressing
+			assert.False(t, isCanaryTrafficPaused(ctx))
+			done, _, err = e.restorePausedTraffic(ctx, tt.rollback)
+			assert.NoError(t, err)
+			assert.True(t, done)
+			assert.Nil(t, ctx.NewStatus.CanaryStatus.PausedTraffic)
+		})
+	}
+}
+
+func Test_pauseCanaryTraffic_holdWeight(t *testing.T) {
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary.Traffic = &rolloutv1alpha1.TrafficStrategy{Weight: ptr.To[int32](50)}
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{State: StepPostCanaryStepHook}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+	ctx.Initialize()
+
+	// the current weight is held without pause policy
+	pauseCanaryTraffic(ctx)
+	assert.Nil(t, ctx.NewStatus.CanaryStatus.PausedTraffic)
+}
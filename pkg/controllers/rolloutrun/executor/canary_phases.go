@@ -0,0 +1,80 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"github.com/samber/lo"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+// canaryLifecyclePhases are the phases summarized in status with the states
+// belonging to them, in the order of canary lifecycle.
+var canaryLifecyclePhases = []struct {
+	phase  string
+	states []rolloutv1alpha1.RolloutStepState
+}{
+	{phase: rolloutv1alpha1.CanaryLifecyclePreHook, states: []rolloutv1alpha1.RolloutStepState{StepPreCanaryStepHook}},
+	{phase: rolloutv1alpha1.CanaryLifecycleCanary, states: []rolloutv1alpha1.RolloutStepState{StepRunning, StepHolding}},
+	{phase: rolloutv1alpha1.CanaryLifecyclePostHook, states: []rolloutv1alpha1.RolloutStepState{StepPostCanaryStepHook}},
+}
+
+// summarizeCanaryPhases returns the result of each lifecycle phase, given the
+// ordered states of canary and the current one. A phase is running while the
+// current state belongs to it, or failed if canary is failed there, and it is
+// skipped if none of its states is in canary states.
+func summarizeCanaryPhases(states []rolloutv1alpha1.RolloutStepState, current rolloutv1alpha1.RolloutStepState, failed bool) map[string]rolloutv1alpha1.CanaryPhaseResult {
+	currentIndex := lo.IndexOf(states, current)
+	result := make(map[string]rolloutv1alpha1.CanaryPhaseResult, len(canaryLifecyclePhases))
+	for _, p := range canaryLifecyclePhases {
+		first, last := -1, -1
+		for _, state := range p.states {
+			i := lo.IndexOf(states, state)
+			if i < 0 {
+				continue
+			}
+			if first < 0 || i < first {
+				first = i
+			}
+			if i > last {
+				last = i
+			}
+		}
+		switch {
+		case first < 0:
+			result[p.phase] = rolloutv1alpha1.CanaryPhaseSkipped
+		case currentIndex < first:
+			result[p.phase] = rolloutv1alpha1.CanaryPhasePending
+		case currentIndex > last:
+			result[p.phase] = rolloutv1alpha1.CanaryPhaseSucceeded
+		case failed:
+			result[p.phase] = rolloutv1alpha1.CanaryPhaseFailed
+		default:
+			result[p.phase] = rolloutv1alpha1.CanaryPhaseRunning
+		}
+	}
+	return result
+}
+
+// updateCanaryPhases summarizes the lifecycle phases of canary in status with
+// its current state, so that they always change together with the state.
+func (e *canaryExecutor) updateCanaryPhases(ctx *ExecutorContext) {
+	status := ctx.NewStatus.CanaryStatus
+	failed := status.Completion != nil && status.Completion.Outcome == rolloutv1alpha1.CanaryFailed
+	states := e.stateMachineOf(ctx.RolloutRun).graph(status.State).States
+	status.Phases = summarizeCanaryPhases(states, status.State, failed)
+}
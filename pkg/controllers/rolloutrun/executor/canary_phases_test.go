@@ -0,0 +1,104 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/traffic"
+)
+
+func Test_summarizeCanaryPhases(t *testing.T) {
+	tests := []struct {
+		name    string
+		states  []rolloutv1alpha1.RolloutStepState
+		current rolloutv1alpha1.RolloutStepState
+		failed  bool
+		want    map[string]rolloutv1alpha1.CanaryPhaseResult
+	}{
+		{
+			name:    "not started",
+			states:  append([]rolloutv1alpha1.RolloutStepState{StepNone}, rolloutv1alpha1.DefaultCanaryStepStates...),
+			current: StepPending,
+			want: map[string]rolloutv1alpha1.CanaryPhaseResult{
+				rolloutv1alpha1.CanaryLifecyclePreHook:  rolloutv1alpha1.CanaryPhasePending,
+				rolloutv1alpha1.CanaryLifecycleCanary:   rolloutv1alpha1.CanaryPhasePending,
+				rolloutv1alpha1.CanaryLifecyclePostHook: rolloutv1alpha1.CanaryPhasePending,
+			},
+		},
+		{
+			name:    "holding",
+			states:  []rolloutv1alpha1.RolloutStepState{StepNone, StepPreCanaryStepHook, StepRunning, StepHolding, StepPostCanaryStepHook, StepSucceeded},
+			current: StepHolding,
+			want: map[string]rolloutv1alpha1.CanaryPhaseResult{
+				rolloutv1alpha1.CanaryLifecyclePreHook:  rolloutv1alpha1.CanaryPhaseSucceeded,
+				rolloutv1alpha1.CanaryLifecycleCanary:   rolloutv1alpha1.CanaryPhaseRunning,
+				rolloutv1alpha1.CanaryLifecyclePostHook: rolloutv1alpha1.CanaryPhasePending,
+			},
+		},
+		{
+			name:    "failed in canary without pre hook",
+			states:  []rolloutv1alpha1.RolloutStepState{StepNone, StepPending, StepRunning, StepPostCanaryStepHook, StepSucceeded},
+			current: StepRunning,
+			failed:  true,
+			want: map[string]rolloutv1alpha1.CanaryPhaseResult{
+				rolloutv1alpha1.CanaryLifecyclePreHook:  rolloutv1alpha1.CanaryPhaseSkipped,
+				rolloutv1alpha1.CanaryLifecycleCanary:   rolloutv1alpha1.CanaryPhaseFailed,
+				rolloutv1alpha1.CanaryLifecyclePostHook: rolloutv1alpha1.CanaryPhasePending,
+			},
+		},
+		{
+			name:    "succeeded",
+			states:  append([]rolloutv1alpha1.RolloutStepState{StepNone}, rolloutv1alpha1.DefaultCanaryStepStates...),
+			current: StepSucceeded,
+			want: map[string]rolloutv1alpha1.CanaryPhaseResult{
+				rolloutv1alpha1.CanaryLifecyclePreHook:  rolloutv1alpha1.CanaryPhaseSucceeded,
+				rolloutv1alpha1.CanaryLifecycleCanary:   rolloutv1alpha1.CanaryPhaseSucceeded,
+				rolloutv1alpha1.CanaryLifecyclePostHook: rolloutv1alpha1.CanaryPhaseSucceeded,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, summarizeCanaryPhases(tt.states, tt.current, tt.failed))
+		})
+	}
+}
+
+func Test_CanaryExecutor_updateCanaryPhases(t *testing.T) {
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	rolloutRun.Status.Phase = rolloutv1alpha1.RolloutRunPhaseProgressing
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{State: StepPreCanaryStepHook}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+	trafficManager, err := traffic.NewManager(ctx.Client, newTestLogger(), nil)
+	assert.NoError(t, err)
+	ctx.TrafficManager = trafficManager
+
+	// phases change in the same status update as the transition
+	e := newCanaryExecutor(newFakeWebhookExecutor())
+	_, _, err = e.Do(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, StepRunning, ctx.NewStatus.CanaryStatus.State)
+	assert.Equal(t, map[string]rolloutv1alpha1.CanaryPhaseResult{
+		rolloutv1alpha1.CanaryLifecyclePreHook:  rolloutv1alpha1.CanaryPhaseSucceeded,
+		rolloutv1alpha1.CanaryLifecycleCanary:   rolloutv1alpha1.CanaryPhaseRunning,
+		rolloutv1alpha1.CanaryLifecyclePostHook: rolloutv1alpha1.CanaryPhasePending,
+	}, ctx.NewStatus.CanaryStatus.Phases)
+}
@@ -0,0 +1,64 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+const ReasonCanaryPodsUnschedulable = "CanaryPodsUnschedulable"
+
+// warnUnschedulableCanaryPods emits a warning event if pods of canary can not
+// be scheduled, which is likely caused by the pod placement patch. It is only
+// a best-effort hint, the canary still waits until readiness timeout.
+func warnUnschedulableCanaryPods(ctx *ExecutorContext, canary *workload.Info) {
+	pods, err := listCanaryPods(ctx, ctx.Client, ctx.Accessor, canary)
+	if err != nil {
+		ctx.GetCanaryLogger().Error(err, "failed to list canary pods", "cluster", canary.ClusterName, "name", canary.Name)
+		return
+	}
+
+	names := make([]string, 0)
+	message := ""
+	for i := range pods {
+		if cond := unschedulableCondition(&pods[i]); cond != nil {
+			names = append(names, pods[i].Name)
+			message = cond.Message
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+	ctx.Recorder.Eventf(ctx.RolloutRun, corev1.EventTypeWarning, ReasonCanaryPodsUnschedulable,
+		"canary pods %v of %s/%s in cluster %q are unschedulable, please check the pod placement patch: %s",
+		names, canary.Namespace, canary.Name, canary.ClusterName, message)
+}
+
+func unschedulableCondition(pod *corev1.Pod) *corev1.PodCondition {
+	if len(pod.Spec.NodeName) > 0 {
+		return nil
+	}
+	for i := range pod.Status.Conditions {
+		cond := &pod.Status.Conditions[i]
+		if cond.Type == corev1.PodScheduled && cond.Status == corev1.ConditionFalse && cond.Reason == corev1.PodReasonUnschedulable {
+			return cond
+		}
+	}
+	return nil
+}
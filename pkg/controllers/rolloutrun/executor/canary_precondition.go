@@ -0,0 +1,187 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/jsonpath"
+	"k8s.io/utils/ptr"
+	"kusionstack.io/kube-utils/multicluster/clusterinfo"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+)
+
+const (
+	ReasonPreconditionNotMet = "PreconditionNotMet"
+	ReasonPrerequisiteNotMet = "PrerequisiteNotMet"
+
+	defaultPreconditionTimeoutSeconds = 300
+	defaultPreconditionValue          = "True"
+)
+
+// checkCanaryPreconditions polls the external objects of preconditionRefs until
+// all of them report ready, or any of them is not met in time. Once met, they
+// are not checked again in the step, so that traffic is not reverted by a
+// precondition flapping afterwards.
+func checkCanaryPreconditions(ctx *ExecutorContext) (bool, time.Duration, error) {
+	refs := ctx.RolloutRun.Spec.Canary.PreconditionRefs
+	if len(refs) == 0 {
+		return true, retryImmediately, nil
+	}
+	logger := ctx.GetCanaryLogger()
+
+	status := ctx.NewStatus.CanaryStatus
+	if status.Preconditions == nil {
+		status.Preconditions = &rolloutv1alpha1.CanaryPreconditionsStatus{StartTime: ptr.To(metav1.Now())}
+	}
+	preconditions := status.Preconditions
+	if preconditions.Met {
+		return true, retryImmediately, nil
+	}
+
+	for _, ref := range refs {
+		met, message, err := checkPrecondition(ctx, ref)
+		if err != nil {
+			return false, retryStop, err
+		}
+		if met {
+			continue
+		}
+		preconditions.Message = message
+
+		timeout := preconditionTimeout(ref)
+		if time.Since(preconditions.StartTime.Time) > timeout {
+			// restart the check so that a manual retry starts a new timeout
+			preconditions.StartTime = ptr.To(metav1.Now())
+			return false, retryStop, control.TerminalError(newDoCanaryError(
+				ReasonPreconditionNotMet,
+				fmt.Sprintf("precondition is not met within %v: %s", timeout, message),
+			))
+		}
+		logger.Info("precondition is not met yet, check later", "message", message)
+		return false, retryDefault, nil
+	}
+
+	preconditions.Met = true
+	preconditions.Message = "all preconditions are met"
+	return true, retryImmediately, nil
+}
+
+// checkCanaryPrerequisites polls the external objects of prerequisites until
+// all of them report ready, so that canary workloads are not created before
+// their dependencies. The ones not met yet are recorded as outstanding, and it
+// fails once any of them is not met in time.
+func checkCanaryPrerequisites(ctx *ExecutorContext) (bool, time.Duration, error) {
+	refs := ctx.RolloutRun.Spec.Canary.Prerequisites
+	if len(refs) == 0 {
+		return true, retryImmediately, nil
+	}
+	logger := ctx.GetCanaryLogger()
+
+	status := ctx.NewStatus.CanaryStatus
+	if status.Prerequisites == nil {
+		status.Prerequisites = &rolloutv1alpha1.CanaryPrerequisitesStatus{StartTime: ptr.To(metav1.Now())}
+	}
+	prerequisites := status.Prerequisites
+	if prerequisites.Met {
+		return true, retryImmediately, nil
+	}
+
+	var outstanding []rolloutv1alpha1.CanaryOutstandingPrerequisite
+	var timedOut []string
+	for _, ref := range refs {
+		met, message, err := checkPrecondition(ctx, ref)
+		if err != nil {
+			return false, retryStop, err
+		}
+		if met {
+			continue
+		}
+		outstanding = append(outstanding, rolloutv1alpha1.CanaryOutstandingPrerequisite{
+			CrossClusterObjectReference: ref.CrossClusterObjectReference,
+			Message:                     message,
+		})
+		if time.Since(prerequisites.StartTime.Time) > preconditionTimeout(ref) {
+			timedOut = append(timedOut, message)
+		}
+	}
+	prerequisites.Outstanding = outstanding
+
+	if len(timedOut) > 0 {
+		// restart the check so that a manual retry starts a new timeout
+		prerequisites.StartTime = ptr.To(metav1.Now())
+		return false, retryStop, control.TerminalError(newDoCanaryError(
+			ReasonPrerequisiteNotMet,
+			fmt.Sprintf("prerequisites are not met in time: %s", strings.Join(timedOut, "; ")),
+		))
+	}
+	if len(outstanding) > 0 {
+		logger.Info("prerequisites are not met yet, check later", "outstanding", len(outstanding))
+		return false, retryDefault, nil
+	}
+
+	prerequisites.Met = true
+	return true, retryImmediately, nil
+}
+
+func preconditionTimeout(ref rolloutv1alpha1.CanaryPreconditionRef) time.Duration {
+	if ref.TimeoutSeconds > 0 {
+		return time.Duration(ref.TimeoutSeconds) * time.Second
+	}
+	return time.Duration(defaultPreconditionTimeoutSeconds) * time.Second
+}
+
+// checkPrecondition returns true if the field of object referenced by ref has
+// the expected value, otherwise a message describing the observed value. An
+// object not found is not met yet, since it may be created by other tools.
+func checkPrecondition(ctx *ExecutorContext, ref rolloutv1alpha1.CanaryPreconditionRef) (bool, string, error) {
+	parser := jsonpath.New(ref.Kind).AllowMissingKeys(true)
+	if err := parser.Parse(ref.FieldPath); err != nil {
+		return false, "", control.TerminalError(newDoCanaryError(
+			ReasonPreconditionNotMet,
+			fmt.Sprintf("invalid field path %s of %s precondition: %v", ref.FieldPath, ref.Kind, err),
+		))
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(ref.APIVersion)
+	obj.SetKind(ref.Kind)
+	key := types.NamespacedName{Namespace: ref.NamespaceOr(ctx.RolloutRun.Namespace), Name: ref.Name}
+	if err := ctx.Client.Get(clusterinfo.WithCluster(ctx, ref.Cluster), key, obj); err != nil {
+		return false, fmt.Sprintf("failed to get %s %s in cluster %q: %v", ref.Kind, key, ref.Cluster, err), nil
+	}
+
+	value, err := jsonPathValue(parser, obj.Object)
+	if err != nil {
+		return false, fmt.Sprintf("failed to read %s of %s %s: %v", ref.FieldPath, ref.Kind, key, err), nil
+	}
+	expected := ref.Value
+	if len(expected) == 0 {
+		expected = defaultPreconditionValue
+	}
+	if value != expected {
+		return false, fmt.Sprintf("%s of %s %s is %q, expected %q", ref.FieldPath, ref.Kind, key, value, expected), nil
+	}
+	return true, "", nil
+}
@@ -0,0 +1,142 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+)
+
+func Test_checkCanaryPreconditions(t *testing.T) {
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary.PreconditionRefs = []rolloutv1alpha1.CanaryPreconditionRef{{
+		CrossClusterObjectReference: rolloutv1alpha1.CrossClusterObjectReference{
+			ObjectTypeRef:                   rolloutv1alpha1.ObjectTypeRef{APIVersion: "v1", Kind: "Pod"},
+			CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Name: "migration"},
+		},
+		FieldPath:      `{.status.conditions[?(@.type=="Ready")].status}`,
+		TimeoutSeconds: 60,
+	}}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun, newFakeObject("cluster-a", "default", "test-1", 10, 0, 0))
+	ctx.NewStatus.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{}
+
+	// object is not found yet
+	met, retry, err := checkCanaryPreconditions(ctx)
+	assert.NoError(t, err)
+	assert.False(t, met)
+	assert.Equal(t, retryDefault, retry)
+	preconditions := ctx.NewStatus.CanaryStatus.Preconditions
+	assert.NotNil(t, preconditions.StartTime)
+	assert.Contains(t, preconditions.Message, "failed to get Pod")
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "migration", Namespace: rolloutRun.Namespace},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}},
+		},
+	}
+	assert.NoError(t, ctx.Client.Create(ctx, pod))
+
+	met, retry, err = checkCanaryPreconditions(ctx)
+	assert.NoError(t, err)
+	assert.False(t, met)
+	assert.Equal(t, retryDefault, retry)
+	assert.Contains(t, preconditions.Message, `expected "True"`)
+
+	// not met in time
+	preconditions.StartTime = ptr.To(metav1.NewTime(time.Now().Add(-2 * time.Minute)))
+	_, retry, err = checkCanaryPreconditions(ctx)
+	assert.Equal(t, retryStop, retry)
+	assert.True(t, errors.Is(err, control.TerminalError(nil)))
+	assert.ErrorContains(t, err, ReasonPreconditionNotMet)
+
+	pod.Status.Conditions[0].Status = corev1.ConditionTrue
+	assert.NoError(t, ctx.Client.Status().Update(ctx, pod))
+	met, _, err = checkCanaryPreconditions(ctx)
+	assert.NoError(t, err)
+	assert.True(t, met)
+	assert.True(t, preconditions.Met)
+
+	// met preconditions are not checked again
+	assert.NoError(t, ctx.Client.Delete(ctx, pod))
+	met, _, err = checkCanaryPreconditions(ctx)
+	assert.NoError(t, err)
+	assert.True(t, met)
+}
+
+func Test_checkCanaryPrerequisites(t *testing.T) {
+	podRef := func(name string) rolloutv1alpha1.CanaryPreconditionRef {
+		return rolloutv1alpha1.CanaryPreconditionRef{
+			CrossClusterObjectReference: rolloutv1alpha1.CrossClusterObjectReference{
+				ObjectTypeRef:                   rolloutv1alpha1.ObjectTypeRef{APIVersion: "v1", Kind: "Pod"},
+				CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Name: name},
+			},
+			FieldPath:      `{.status.phase}`,
+			Value:          string(corev1.PodSucceeded),
+			TimeoutSeconds: 60,
+		}
+	}
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary.Prerequisites = []rolloutv1alpha1.CanaryPreconditionRef{podRef("migration"), podRef("flag")}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun, newFakeObject("cluster-a", "default", "test-1", 10, 0, 0))
+	ctx.NewStatus.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{}
+
+	migration := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "migration", Namespace: rolloutRun.Namespace},
+		Status:     corev1.PodStatus{Phase: corev1.PodSucceeded},
+	}
+	assert.NoError(t, ctx.Client.Create(ctx, migration))
+
+	// only the missing one is outstanding
+	met, retry, err := checkCanaryPrerequisites(ctx)
+	assert.NoError(t, err)
+	assert.False(t, met)
+	assert.Equal(t, retryDefault, retry)
+	prerequisites := ctx.NewStatus.CanaryStatus.Prerequisites
+	assert.NotNil(t, prerequisites.StartTime)
+	if assert.Len(t, prerequisites.Outstanding, 1) {
+		assert.Equal(t, "flag", prerequisites.Outstanding[0].Name)
+		assert.Contains(t, prerequisites.Outstanding[0].Message, "failed to get Pod")
+	}
+
+	// not met in time
+	prerequisites.StartTime = ptr.To(metav1.NewTime(time.Now().Add(-2 * time.Minute)))
+	_, retry, err = checkCanaryPrerequisites(ctx)
+	assert.Equal(t, retryStop, retry)
+	assert.True(t, errors.Is(err, control.TerminalError(nil)))
+	assert.ErrorContains(t, err, ReasonPrerequisiteNotMet)
+
+	flag := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "flag", Namespace: rolloutRun.Namespace},
+		Status:     corev1.PodStatus{Phase: corev1.PodSucceeded},
+	}
+	assert.NoError(t, ctx.Client.Create(ctx, flag))
+	met, _, err = checkCanaryPrerequisites(ctx)
+	assert.NoError(t, err)
+	assert.True(t, met)
+	assert.True(t, prerequisites.Met)
+	assert.Empty(t, prerequisites.Outstanding)
+}
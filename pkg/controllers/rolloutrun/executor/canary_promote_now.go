@@ -0,0 +1,99 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+const (
+	ReasonCanaryFastPromoted = "CanaryFastPromoted"
+	ReasonPromoteNowRejected = "PromoteNowRejected"
+)
+
+// isCanaryFastPromoted returns true if canary is promoted by promote-now
+// command, the promotion windows and pauses before promotion are skipped.
+func isCanaryFastPromoted(status *rolloutv1alpha1.RolloutRunStepStatus) bool {
+	return status != nil && status.FastPromotion != nil
+}
+
+// checkPromoteNow returns why canary can not be promoted now, empty if it can.
+// Only the canary which is serving and not rolled back can be promoted.
+func checkPromoteNow(ctx *ExecutorContext) string {
+	if !ctx.inCanary() || ctx.NewStatus.CanaryStatus == nil {
+		return "rolloutRun is not in canary"
+	}
+	status := ctx.NewStatus.CanaryStatus
+	switch {
+	case ctx.NewStatus.Error != nil:
+		return "canary has failed, retry or skip it instead"
+	case isCanaryFastPromoted(status):
+		return "canary is already fast-promoted"
+	case isRolledBackByDeadline(status) || isCanaryHoldEnded(status):
+		return "canary is being rolled back"
+	}
+	switch status.State {
+	case StepRunning, StepHolding, StepPostCanaryStepHook, StepResourceRecycling:
+		return ""
+	}
+	return fmt.Sprintf("canary is not serving in state %s", status.State)
+}
+
+// promoteCanaryNow jumps canary directly to recycling, skipping the remaining
+// analysis, hold, pauses and promotion windows. The traffic is still reverted
+// by recycling before the rollout continues with batches.
+func promoteCanaryNow(ctx *ExecutorContext, operator string, now time.Time) {
+	if reason := checkPromoteNow(ctx); len(reason) > 0 {
+		ctx.GetCanaryLogger().Info("promote-now command is rejected", "reason", reason)
+		ctx.Recorder.Eventf(ctx.RolloutRun, corev1.EventTypeWarning, ReasonPromoteNowRejected, "promote-now is rejected: %s", reason)
+		return
+	}
+
+	status := ctx.NewStatus.CanaryStatus
+	status.FastPromotion = &rolloutv1alpha1.CanaryFastPromotionStatus{
+		Operator:  operator,
+		Time:      ptr.To(metav1.NewTime(now)),
+		FromState: status.State,
+	}
+	// the pending pause before is resumed by the promotion
+	for i := range status.PauseCheckpoints {
+		if status.PauseCheckpoints[i].ResumeTime == nil {
+			status.PauseCheckpoints[i].ResumeTime = ptr.To(metav1.NewTime(now))
+		}
+	}
+	status.PausedBefore = ""
+	if status.State != StepResourceRecycling {
+		ctx.MoveToNextState(StepResourceRecycling)
+	}
+	if ctx.NewStatus.Phase == rolloutv1alpha1.RolloutRunPhasePaused {
+		ctx.NewStatus.Phase = rolloutv1alpha1.RolloutRunPhaseProgressing
+	}
+
+	by := operator
+	if len(by) == 0 {
+		by = "unknown"
+	}
+	ctx.Recorder.Eventf(ctx.RolloutRun, corev1.EventTypeNormal, ReasonCanaryFastPromoted,
+		"canary is fast-promoted from %s by %s", status.FastPromotion.FromState, by)
+}
@@ -0,0 +1,150 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutapis "kusionstack.io/rollout/apis/rollout"
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+func Test_promoteCanaryNow(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       rolloutv1alpha1.RolloutRunStepStatus
+		phase        rolloutv1alpha1.RolloutRunPhase
+		err          bool
+		wantPromoted bool
+	}{
+		{
+			name:         "running",
+			status:       rolloutv1alpha1.RolloutRunStepStatus{State: StepRunning},
+			wantPromoted: true,
+		},
+		{
+			name:         "holding",
+			status:       rolloutv1alpha1.RolloutRunStepStatus{State: StepHolding, Hold: &rolloutv1alpha1.CanaryHoldStatus{}},
+			wantPromoted: true,
+		},
+		{
+			name: "paused before promotion",
+			status: rolloutv1alpha1.RolloutRunStepStatus{
+				State:        StepResourceRecycling,
+				PausedBefore: rolloutv1alpha1.CanaryPauseBeforePromotion,
+				PauseCheckpoints: []rolloutv1alpha1.CanaryPauseCheckpoint{
+					{Phase: rolloutv1alpha1.CanaryPauseBeforePromotion, PauseTime: ptr.To(metav1.Now())},
+				},
+			},
+			phase:        rolloutv1alpha1.RolloutRunPhasePaused,
+			wantPromoted: true,
+		},
+		{
+			name:   "pre canary step hook",
+			status: rolloutv1alpha1.RolloutRunStepStatus{State: StepPreCanaryStepHook},
+		},
+		{
+			name:   "succeeded",
+			status: rolloutv1alpha1.RolloutRunStepStatus{State: StepSucceeded},
+		},
+		{
+			name:   "failed",
+			status: rolloutv1alpha1.RolloutRunStepStatus{State: StepRunning},
+			err:    true,
+		},
+		{
+			name: "hold ended",
+			status: rolloutv1alpha1.RolloutRunStepStatus{
+				State: StepResourceRecycling,
+				Hold:  &rolloutv1alpha1.CanaryHoldStatus{Action: rolloutv1alpha1.CanaryHoldEnd},
+			},
+		},
+		{
+			name: "already promoted",
+			status: rolloutv1alpha1.RolloutRunStepStatus{
+				State:         StepResourceRecycling,
+				FastPromotion: &rolloutv1alpha1.CanaryFastPromotionStatus{FromState: StepRunning},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rolloutRun := testCanaryRolloutRun.DeepCopy()
+			rolloutRun.Status.Phase = rolloutv1alpha1.RolloutRunPhaseProgressing
+			if len(tt.phase) > 0 {
+				rolloutRun.Status.Phase = tt.phase
+			}
+			rolloutRun.Status.CanaryStatus = tt.status.DeepCopy()
+			if tt.err {
+				rolloutRun.Status.Error = &rolloutv1alpha1.CodeReasonMessage{Code: "DoCanaryError"}
+			}
+			ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+			ctx.Initialize()
+			fromState := tt.status.State
+
+			now := time.Now()
+			promoteCanaryNow(ctx, "alice", now)
+
+			status := ctx.NewStatus.CanaryStatus
+			if !tt.wantPromoted {
+				assert.NotEmpty(t, checkPromoteNow(ctx))
+				assert.Equal(t, tt.status.FastPromotion, status.FastPromotion)
+				assert.Equal(t, fromState, status.State)
+				return
+			}
+			assert.Equal(t, StepResourceRecycling, status.State)
+			assert.Equal(t, rolloutv1alpha1.RolloutRunPhaseProgressing, ctx.NewStatus.Phase)
+			assert.Empty(t, status.PausedBefore)
+			for _, c := range status.PauseCheckpoints {
+				assert.NotNil(t, c.ResumeTime)
+			}
+			if assert.True(t, isCanaryFastPromoted(status)) {
+				assert.Equal(t, "alice", status.FastPromotion.Operator)
+				assert.Equal(t, fromState, status.FastPromotion.FromState)
+				assert.Equal(t, now.Unix(), status.FastPromotion.Time.Unix())
+			}
+		})
+	}
+}
+
+func TestExecutor_doCommand_PromoteNow(t *testing.T) {
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	rolloutRun.Annotations = map[string]string{
+		rolloutapis.AnnoManualCommandKey: rolloutapis.AnnoManualCommandPromoteNow,
+		rolloutapis.AnnoManualCommandBy:  "bob",
+	}
+	rolloutRun.Status.Phase = rolloutv1alpha1.RolloutRunPhaseProgressing
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{State: StepPostCanaryStepHook}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+	ctx.Initialize()
+
+	r := NewDefaultExecutor(newTestLogger())
+	result := r.doCommand(ctx)
+	assert.True(t, result.Requeue)
+
+	status := ctx.NewStatus.CanaryStatus
+	assert.Equal(t, StepResourceRecycling, status.State)
+	if assert.NotNil(t, status.FastPromotion) {
+		assert.Equal(t, "bob", status.FastPromotion.Operator)
+		assert.Equal(t, StepPostCanaryStepHook, status.FastPromotion.FromState)
+	}
+}
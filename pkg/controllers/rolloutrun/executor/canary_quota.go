@@ -0,0 +1,142 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	quota "k8s.io/apiserver/pkg/quota/v1"
+	"kusionstack.io/kube-utils/multicluster/clusterinfo"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+const (
+	ReasonInsufficientQuota = "InsufficientQuota"
+)
+
+// checkCanaryQuota estimates the resources canary pods will request and checks
+// them against the remaining ResourceQuota in each namespace.
+func checkCanaryQuota(ctx *ExecutorContext, targets []canaryTarget) error {
+	podControl, ok := ctx.Accessor.(workload.PodControl)
+	if !ok {
+		// we can not get pod template from workload
+		return nil
+	}
+
+	type namespaceKey struct {
+		cluster   string
+		namespace string
+	}
+
+	usages := map[namespaceKey]corev1.ResourceList{}
+	for _, item := range targets {
+		wi := item.info
+		replicas, err := workload.CalculateUpdatedReplicas(&wi.Status.Replicas, item.Replicas)
+		if err != nil {
+			return err
+		}
+		template, err := podControl.GetPodTemplate(wi.Object)
+		if err != nil {
+			return err
+		}
+		key := namespaceKey{cluster: wi.ClusterName, namespace: item.canaryNamespace()}
+		usages[key] = quota.Add(usages[key], canaryQuotaUsage(template, replicas))
+	}
+
+	for key, usage := range usages {
+		quotas := &corev1.ResourceQuotaList{}
+		err := ctx.Client.List(clusterinfo.WithCluster(ctx, key.cluster), quotas, client.InNamespace(key.namespace))
+		if err != nil {
+			return err
+		}
+		for _, q := range quotas.Items {
+			if exceeded := exceededResources(&q, usage); len(exceeded) > 0 {
+				return control.TerminalError(newDoCanaryError(
+					ReasonInsufficientQuota,
+					fmt.Sprintf("canary resources exceed remaining quota of ResourceQuota %s/%s in cluster %q, exceeded: %s",
+						key.namespace, q.Name, key.cluster, strings.Join(exceeded, ",")),
+				))
+			}
+		}
+	}
+	return nil
+}
+
+// canaryQuotaUsage returns the quota usage of replicas pods created from template.
+func canaryQuotaUsage(template *corev1.PodTemplateSpec, replicas int32) corev1.ResourceList {
+	requests, limits := podRequestsAndLimits(&template.Spec)
+
+	usage := corev1.ResourceList{
+		corev1.ResourcePods: *resource.NewQuantity(int64(replicas), resource.DecimalSI),
+	}
+	for name, q := range requests {
+		total := multiplyQuantity(q, replicas)
+		usage[name] = total
+		usage[corev1.ResourceName("requests."+string(name))] = total
+	}
+	for name, q := range limits {
+		usage[corev1.ResourceName("limits."+string(name))] = multiplyQuantity(q, replicas)
+	}
+	return usage
+}
+
+// podRequestsAndLimits returns the effective requests and limits of a pod,
+// which is the max of the sum of all containers and any init container.
+func podRequestsAndLimits(spec *corev1.PodSpec) (requests, limits corev1.ResourceList) {
+	requests, limits = corev1.ResourceList{}, corev1.ResourceList{}
+	for _, c := range spec.Containers {
+		requests = quota.Add(requests, c.Resources.Requests)
+		limits = quota.Add(limits, c.Resources.Limits)
+	}
+	for _, c := range spec.InitContainers {
+		requests = quota.Max(requests, c.Resources.Requests)
+		limits = quota.Max(limits, c.Resources.Limits)
+	}
+	return requests, limits
+}
+
+func multiplyQuantity(q resource.Quantity, replicas int32) resource.Quantity {
+	return *resource.NewMilliQuantity(q.MilliValue()*int64(replicas), q.Format)
+}
+
+// exceededResources returns the resource names in which usage exceeds the
+// remaining of the quota.
+func exceededResources(q *corev1.ResourceQuota, usage corev1.ResourceList) []string {
+	hard := q.Status.Hard
+	if len(hard) == 0 {
+		return nil
+	}
+	remaining := quota.Subtract(hard, quota.Mask(q.Status.Used, quota.ResourceNames(hard)))
+	requested := quota.Mask(usage, quota.ResourceNames(hard))
+	ok, exceeded := quota.LessThanOrEqual(requested, remaining)
+	if ok {
+		return nil
+	}
+	result := make([]string, 0, len(exceeded))
+	for _, name := range exceeded {
+		result = append(result, string(name))
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,102 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func Test_canaryQuotaUsage(t *testing.T) {
+	template := &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{
+				{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+					},
+				},
+			},
+			Containers: []corev1.Container{
+				{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("500m"),
+							corev1.ResourceMemory: resource.MustParse("1Gi"),
+						},
+						Limits: corev1.ResourceList{
+							corev1.ResourceMemory: resource.MustParse("2Gi"),
+						},
+					},
+				},
+				{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+					},
+				},
+			},
+		},
+	}
+
+	usage := canaryQuotaUsage(template, 3)
+	assertQuantity := func(name corev1.ResourceName, want string) {
+		got := usage[name]
+		assert.Equal(t, 0, got.Cmp(resource.MustParse(want)), "resource %s: got %s, want %s", name, got.String(), want)
+	}
+	assertQuantity(corev1.ResourcePods, "3")
+	// init container requests more cpu than the sum of containers
+	assertQuantity(corev1.ResourceCPU, "6")
+	assertQuantity(corev1.ResourceRequestsCPU, "6")
+	assertQuantity(corev1.ResourceRequestsMemory, "3Gi")
+	assertQuantity(corev1.ResourceLimitsMemory, "6Gi")
+}
+
+func Test_exceededResources(t *testing.T) {
+	q := &corev1.ResourceQuota{
+		Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{
+				corev1.ResourceRequestsCPU:    resource.MustParse("10"),
+				corev1.ResourceRequestsMemory: resource.MustParse("10Gi"),
+				corev1.ResourcePods:           resource.MustParse("10"),
+			},
+			Used: corev1.ResourceList{
+				corev1.ResourceRequestsCPU:    resource.MustParse("8"),
+				corev1.ResourceRequestsMemory: resource.MustParse("4Gi"),
+				corev1.ResourcePods:           resource.MustParse("4"),
+			},
+		},
+	}
+
+	fit := corev1.ResourceList{
+		corev1.ResourceRequestsCPU:    resource.MustParse("2"),
+		corev1.ResourceRequestsMemory: resource.MustParse("2Gi"),
+		corev1.ResourcePods:           resource.MustParse("2"),
+		// not limited by quota
+		corev1.ResourceLimitsCPU: resource.MustParse("100"),
+	}
+	assert.Empty(t, exceededResources(q, fit))
+
+	notFit := corev1.ResourceList{
+		corev1.ResourceRequestsCPU:    resource.MustParse("3"),
+		corev1.ResourceRequestsMemory: resource.MustParse("7Gi"),
+		corev1.ResourcePods:           resource.MustParse("2"),
+	}
+	assert.Equal(t, []string{"requests.cpu", "requests.memory"}, exceededResources(q, notFit))
+}
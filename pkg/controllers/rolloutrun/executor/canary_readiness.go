@@ -0,0 +1,97 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"time"
+
+	"github.com/samber/lo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+// canaryReadinessDeadline returns the readiness deadline of target, nil means
+// no deadline. The wait start time and deadline are recorded in status when
+// it is called for the first time, so that they survive controller restarts.
+func canaryReadinessDeadline(status *rolloutv1alpha1.RolloutRunStepStatus, target rolloutv1alpha1.RolloutRunStepTarget, now time.Time) *metav1.Time {
+	if target.ReadinessTimeoutSeconds == nil {
+		return nil
+	}
+
+	for i := range status.TargetReadiness {
+		if status.TargetReadiness[i].CrossClusterObjectNameReference == target.CrossClusterObjectNameReference {
+			return status.TargetReadiness[i].Deadline
+		}
+	}
+
+	readiness := rolloutv1alpha1.TargetReadinessStatus{
+		CrossClusterObjectNameReference: target.CrossClusterObjectNameReference,
+		WaitStartTime:                   ptr.To(metav1.NewTime(now)),
+		Deadline:                        ptr.To(metav1.NewTime(now.Add(time.Duration(*target.ReadinessTimeoutSeconds) * time.Second))),
+	}
+	status.TargetReadiness = append(status.TargetReadiness, readiness)
+	return readiness.Deadline
+}
+
+// resetCanaryReadiness removes the recorded readiness of targets.
+func resetCanaryReadiness(status *rolloutv1alpha1.RolloutRunStepStatus, targets []rolloutv1alpha1.CrossClusterObjectNameReference) {
+	status.TargetReadiness = lo.Filter(status.TargetReadiness, func(r rolloutv1alpha1.TargetReadinessStatus, _ int) bool {
+		return !lo.Contains(targets, r.CrossClusterObjectNameReference)
+	})
+}
+
+const defaultReadinessStabilizationPercent = 100
+
+// checkReadinessStabilization returns true if the ready percentage of canary has
+// stayed at or above the threshold for the whole stabilization window, otherwise
+// the duration to check again, which is at most interval. The window restarts
+// whenever the percentage dips below the threshold, and its progress is recorded
+// in status. Once stabilized, it is not checked again in the step.
+func checkReadinessStabilization(status *rolloutv1alpha1.RolloutRunStepStatus, stabilization *rolloutv1alpha1.CanaryReadinessStabilization, summary CanarySummary, now time.Time, interval time.Duration) (bool, time.Duration) {
+	if status.ReadinessStabilization == nil {
+		status.ReadinessStabilization = &rolloutv1alpha1.CanaryReadinessStabilizationStatus{}
+	}
+	progress := status.ReadinessStabilization
+	if progress.Stabilized {
+		return true, retryImmediately
+	}
+
+	progress.ReadyPercent = 100
+	if summary.Replicas > 0 {
+		progress.ReadyPercent = summary.UpdatedAvailableReplicas * 100 / summary.Replicas
+	}
+	threshold := int32(defaultReadinessStabilizationPercent)
+	if stabilization.ReadyPercent > 0 {
+		threshold = stabilization.ReadyPercent
+	}
+	if progress.ReadyPercent < threshold {
+		progress.WindowStartTime = nil
+		return false, retryDefault
+	}
+
+	if progress.WindowStartTime == nil {
+		progress.WindowStartTime = ptr.To(metav1.NewTime(now))
+	}
+	remaining := progress.WindowStartTime.Add(time.Duration(stabilization.WindowSeconds) * time.Second).Sub(now)
+	if remaining > 0 {
+		return false, min(remaining, interval)
+	}
+	progress.Stabilized = true
+	return true, retryImmediately
+}
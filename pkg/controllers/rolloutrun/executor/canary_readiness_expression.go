@@ -0,0 +1,116 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"kusionstack.io/rollout/apis/rollout/v1alpha1/validation"
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+const (
+	// ReasonReadinessExpressionInvalid is the reason of failing canary whose
+	// readiness expression can not be compiled.
+	ReasonReadinessExpressionInvalid = "ReadinessExpressionInvalid"
+	// ReasonReadinessExpressionFailed is the reason of event when the readiness
+	// expression fails to be evaluated against canary workloads.
+	ReasonReadinessExpressionFailed = "ReadinessExpressionFailed"
+)
+
+// canaryReadiness decides whether canary workloads are ready, by the readiness
+// expression of canary if it is set, otherwise by all the replicas of canary
+// workload being updated and available.
+type canaryReadiness struct {
+	program cel.Program
+	// failures are the evaluation errors of canary workloads
+	failures []string
+}
+
+func newCanaryReadiness(expression string) (*canaryReadiness, error) {
+	r := &canaryReadiness{}
+	if len(expression) == 0 {
+		return r, nil
+	}
+	env, err := validation.CanaryReadinessExpressionEnv()
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, err
+	}
+	r.program = program
+	return r, nil
+}
+
+// ready returns true if the canary workload is ready. A workload whose status
+// is not up to date is never ready, and a workload failing the evaluation is
+// considered not ready with the error recorded in failures.
+func (r *canaryReadiness) ready(info *workload.Info) bool {
+	if r.program == nil {
+		return info.CheckUpdatedReady(info.Status.Replicas)
+	}
+	if info.Generation != info.Status.ObservedGeneration || info.Status.Updating {
+		return false
+	}
+	ready, err := r.evaluate(info)
+	if err != nil {
+		r.failures = append(r.failures, fmt.Sprintf("%s/%s: %v", info.ClusterName, info.Name, err))
+		return false
+	}
+	return ready
+}
+
+func (r *canaryReadiness) evaluate(info *workload.Info) (bool, error) {
+	object, err := runtime.DefaultUnstructuredConverter.ToUnstructured(info.Object)
+	if err != nil {
+		return false, err
+	}
+	out, _, err := r.program.Eval(map[string]interface{}{
+		"status": readinessExpressionStatus(info.Status),
+		"object": object,
+	})
+	if err != nil {
+		return false, err
+	}
+	ready, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("expression returns %T instead of bool", out.Value())
+	}
+	return ready, nil
+}
+
+// readinessExpressionStatus is the `status` variable of readiness expression.
+func readinessExpressionStatus(status workload.InfoStatus) map[string]interface{} {
+	return map[string]interface{}{
+		"observedGeneration":       status.ObservedGeneration,
+		"replicas":                 int64(status.Replicas),
+		"updatedReplicas":          int64(status.UpdatedReplicas),
+		"updatedReadyReplicas":     int64(status.UpdatedReadyReplicas),
+		"updatedAvailableReplicas": int64(status.UpdatedAvailableReplicas),
+		"availableReplicas":        int64(status.AvailableReplicas),
+		"updating":                 status.Updating,
+	}
+}
@@ -0,0 +1,100 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+func newTestReadinessInfo(status workload.InfoStatus) *workload.Info {
+	info := newTestCanaryTargetInfo("demo", status).Info
+	info.Object = &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo-canary", Labels: map[string]string{"app": "demo"}},
+	}
+	return info
+}
+
+func Test_canaryReadiness_expression(t *testing.T) {
+	tests := []struct {
+		name         string
+		expression   string
+		status       workload.InfoStatus
+		wantReady    bool
+		wantFailures int
+	}{
+		{
+			name:       "default readiness",
+			status:     workload.InfoStatus{Replicas: 2, UpdatedReplicas: 2, UpdatedReadyReplicas: 2, UpdatedAvailableReplicas: 1},
+			wantReady:  false,
+			expression: "",
+		},
+		{
+			name:       "ready by status",
+			expression: "status.updatedAvailableReplicas * 2 >= status.replicas",
+			status:     workload.InfoStatus{Replicas: 2, UpdatedReplicas: 2, UpdatedReadyReplicas: 2, UpdatedAvailableReplicas: 1},
+			wantReady:  true,
+		},
+		{
+			name:       "not ready by status",
+			expression: "status.updatedAvailableReplicas * 2 >= status.replicas",
+			status:     workload.InfoStatus{Replicas: 3, UpdatedReplicas: 3, UpdatedReadyReplicas: 1, UpdatedAvailableReplicas: 1},
+			wantReady:  false,
+		},
+		{
+			name:       "ready by object",
+			expression: "object.metadata.labels['app'] == 'demo'",
+			wantReady:  true,
+		},
+		{
+			name:       "status not up to date",
+			expression: "true",
+			status:     workload.InfoStatus{Updating: true},
+			wantReady:  false,
+		},
+		{
+			name:         "evaluation error",
+			expression:   "status.unknown > 0",
+			wantReady:    false,
+			wantFailures: 1,
+		},
+		{
+			name:         "not bool",
+			expression:   "status.replicas",
+			wantReady:    false,
+			wantFailures: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			readiness, err := newCanaryReadiness(tt.expression)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantReady, readiness.ready(newTestReadinessInfo(tt.status)))
+			assert.Len(t, readiness.failures, tt.wantFailures)
+		})
+	}
+}
+
+func Test_newCanaryReadiness_invalid(t *testing.T) {
+	_, err := newCanaryReadiness("status.replicas >")
+	assert.Error(t, err)
+}
@@ -0,0 +1,102 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+func Test_canaryReadinessDeadline(t *testing.T) {
+	now := time.Now()
+	status := &rolloutv1alpha1.RolloutRunStepStatus{}
+	fast := rolloutv1alpha1.RolloutRunStepTarget{
+		CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "fast"},
+		ReadinessTimeoutSeconds:         ptr.To[int32](60),
+	}
+	slow := rolloutv1alpha1.RolloutRunStepTarget{
+		CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-b", Name: "slow"},
+		ReadinessTimeoutSeconds:         ptr.To[int32](600),
+	}
+	unlimited := rolloutv1alpha1.RolloutRunStepTarget{
+		CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-c", Name: "unlimited"},
+	}
+
+	assert.Nil(t, canaryReadinessDeadline(status, unlimited, now))
+	assert.Equal(t, now.Add(time.Minute), canaryReadinessDeadline(status, fast, now).Time)
+	assert.Equal(t, now.Add(10*time.Minute), canaryReadinessDeadline(status, slow, now).Time)
+	assert.Len(t, status.TargetReadiness, 2)
+
+	// recorded deadline is respected later
+	later := now.Add(5 * time.Minute)
+	assert.Equal(t, now.Add(time.Minute), canaryReadinessDeadline(status, fast, later).Time)
+
+	resetCanaryReadiness(status, []rolloutv1alpha1.CrossClusterObjectNameReference{fast.CrossClusterObjectNameReference})
+	assert.Len(t, status.TargetReadiness, 1)
+	assert.Equal(t, later.Add(time.Minute), canaryReadinessDeadline(status, fast, later).Time)
+}
+
+func Test_checkReadinessStabilization(t *testing.T) {
+	now := time.Now()
+	status := &rolloutv1alpha1.RolloutRunStepStatus{}
+	stabilization := &rolloutv1alpha1.CanaryReadinessStabilization{ReadyPercent: 80, WindowSeconds: 60}
+	summaryOf := func(replicas, available int32) CanarySummary {
+		return CanarySummary{RolloutReplicasSummary: rolloutv1alpha1.RolloutReplicasSummary{
+			Replicas:                 replicas,
+			UpdatedAvailableReplicas: available,
+		}}
+	}
+
+	// below threshold
+	stabilized, retry := checkReadinessStabilization(status, stabilization, summaryOf(10, 7), now, time.Minute)
+	assert.False(t, stabilized)
+	assert.Equal(t, retryDefault, retry)
+	assert.Equal(t, int32(70), status.ReadinessStabilization.ReadyPercent)
+	assert.Nil(t, status.ReadinessStabilization.WindowStartTime)
+
+	// window starts at threshold
+	stabilized, _ = checkReadinessStabilization(status, stabilization, summaryOf(10, 8), now, time.Minute)
+	assert.False(t, stabilized)
+	assert.Equal(t, now.Unix(), status.ReadinessStabilization.WindowStartTime.Unix())
+
+	// a dip restarts the window
+	stabilized, _ = checkReadinessStabilization(status, stabilization, summaryOf(10, 7), now.Add(30*time.Second), time.Minute)
+	assert.False(t, stabilized)
+	assert.Nil(t, status.ReadinessStabilization.WindowStartTime)
+	stabilized, _ = checkReadinessStabilization(status, stabilization, summaryOf(10, 9), now.Add(40*time.Second), time.Minute)
+	assert.False(t, stabilized)
+	stabilized, retry = checkReadinessStabilization(status, stabilization, summaryOf(10, 9), now.Add(60*time.Second), 5*time.Second)
+	assert.False(t, stabilized)
+	// the computed wait is capped by interval
+	assert.Equal(t, 5*time.Second, retry)
+	stabilized, retry = checkReadinessStabilization(status, stabilization, summaryOf(10, 9), now.Add(99*time.Second), time.Minute)
+	assert.False(t, stabilized)
+	assert.Equal(t, time.Second, retry)
+
+	stabilized, _ = checkReadinessStabilization(status, stabilization, summaryOf(10, 9), now.Add(100*time.Second), time.Minute)
+	assert.True(t, stabilized)
+	assert.True(t, status.ReadinessStabilization.Stabilized)
+
+	// stabilized readiness is not checked again
+	stabilized, _ = checkReadinessStabilization(status, stabilization, summaryOf(10, 0), now.Add(101*time.Second), time.Minute)
+	assert.True(t, stabilized)
+}
@@ -0,0 +1,124 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+)
+
+const defaultRecycleVerifyBudget = 5 * time.Minute
+
+// verifyRecycled confirms that all canary resources are deleted and traffic is
+// fully restored to stable after recycle operations are issued. It requeues
+// until they are confirmed, and fails the canary if the budget is exhausted.
+func (e *canaryExecutor) verifyRecycled(ctx *ExecutorContext) (bool, time.Duration, error) {
+	status := ctx.NewStatus.CanaryStatus
+	if status.RecycleVerification == nil {
+		status.RecycleVerification = &rolloutv1alpha1.RecycleVerificationStatus{
+			StartTime: ptr.To(metav1.Now()),
+		}
+	}
+
+	if err := e.handleStuckDeletions(ctx, time.Now()); err != nil {
+		return false, retryStop, err
+	}
+
+	pending, err := unrecycledCanaryResources(ctx)
+	if err != nil {
+		return false, retryStop, err
+	}
+	if ctx.RolloutRun.Spec.Canary.Traffic != nil && !ctx.TrafficManager.CheckReverted() {
+		pending = append(pending, "traffic routing")
+	}
+	if len(pending) == 0 {
+		status.RecycleVerification.Message = "canary is recycled"
+		// canary is no longer live
+		status.ResourceFootprint = nil
+		return true, retryImmediately, nil
+	}
+	status.RecycleVerification.Message = fmt.Sprintf("waiting for %s to be recycled", strings.Join(pending, ", "))
+
+	budget := recycleVerifyBudget(ctx.RolloutRun.Spec.Canary.StuckDeletion)
+	if time.Since(status.RecycleVerification.StartTime.Time) > budget {
+		// reset the verification so that a manual retry starts a new budget
+		status.RecycleVerification = nil
+		return false, retryStop, control.TerminalError(newDoCanaryError(
+			"RecycleVerificationFailed",
+			fmt.Sprintf("%v are not recycled within %v", pending, budget),
+		))
+	}
+
+	ctx.GetCanaryLogger().Info("canary is not fully recycled, retry later", "pending", pending)
+	return false, retryDefault, nil
+}
+
+// recycleVerifyBudget returns the budget of recycle verification, which leaves
+// the stuck deletion timeout for the action on stuck canary workloads.
+func recycleVerifyBudget(stuck *rolloutv1alpha1.CanaryStuckDeletion) time.Duration {
+	if stuck == nil {
+		return defaultRecycleVerifyBudget
+	}
+	return defaultRecycleVerifyBudget + time.Duration(stuck.TimeoutSeconds)*time.Second
+}
+
+// unrecycledCanaryResources returns the canary resources in reachable clusters
+// which are not deleted yet.
+func unrecycledCanaryResources(ctx *ExecutorContext) ([]string, error) {
+	targets, _, err := reachableCanaryTargets(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	ordinals := ctx.RolloutRun.Spec.Canary.Ordinals
+	pending := []string{}
+	for _, item := range targets {
+		if len(ordinals) > 0 {
+			reverted, err := control.NewOrdinalCanaryReleaseControl(ctx.Accessor, ctx.Client).IsReverted(item.info, ordinals)
+			if err != nil {
+				return nil, err
+			}
+			if !reverted {
+				pending = append(pending, fmt.Sprintf("canary ordinals %v of %s", ordinals, item.CrossClusterObjectNameReference))
+			}
+		}
+		finalized, err := newCanaryReleaseControl(ctx, item, "").IsFinalized(item.info)
+		if err != nil {
+			return nil, err
+		}
+		if !finalized {
+			pending = append(pending, fmt.Sprintf("canary resource of %s", item.CrossClusterObjectNameReference))
+		}
+		for _, variant := range canaryVariantNames(ctx.RolloutRun) {
+			finalized, err := newCanaryReleaseControl(ctx, item, variant).IsFinalized(item.info)
+			if err != nil {
+				return nil, err
+			}
+			if !finalized {
+				pending = append(pending, fmt.Sprintf("canary resource of variant %s of %s", variant, item.CrossClusterObjectNameReference))
+			}
+		}
+	}
+	return pending, nil
+}
@@ -0,0 +1,69 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+)
+
+func Test_verifyRecycled(t *testing.T) {
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary.Targets = []rolloutv1alpha1.RolloutRunStepTarget{
+		{CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-1"}},
+	}
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{State: StepResourceRecycling}
+	canaryObj := newFakeObject("cluster-a", "default", "test-1-canary", 1, 0, 0)
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun,
+		newFakeObject("cluster-a", "default", "test-1", 10, 0, 0),
+		canaryObj,
+	)
+	e := newCanaryExecutor(newFakeWebhookExecutor())
+
+	// canary resource is left
+	done, retry, err := e.verifyRecycled(ctx)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, retryDefault, retry)
+	status := ctx.NewStatus.CanaryStatus.RecycleVerification
+	if assert.NotNil(t, status) {
+		assert.NotNil(t, status.StartTime)
+		assert.Contains(t, status.Message, "cluster=cluster-a,name=test-1")
+	}
+
+	// budget is exhausted
+	status.StartTime = ptr.To(metav1.NewTime(time.Now().Add(-defaultRecycleVerifyBudget - time.Second)))
+	done, _, err = e.verifyRecycled(ctx)
+	assert.False(t, done)
+	assert.True(t, errors.Is(err, control.TerminalError(nil)))
+	assert.Nil(t, ctx.NewStatus.CanaryStatus.RecycleVerification)
+
+	// canary resource is deleted
+	assert.NoError(t, ctx.Client.Delete(ctx, canaryObj))
+	done, _, err = e.verifyRecycled(ctx)
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, "canary is recycled", ctx.NewStatus.CanaryStatus.RecycleVerification.Message)
+}
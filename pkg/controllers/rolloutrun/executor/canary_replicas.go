@@ -0,0 +1,71 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"math"
+
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+// canaryReplicas returns the replicas of canary workload for target. If canary
+// replicas follow traffic weight, the replicas are raised to the weight percent
+// of stable replicas and the calculation is recorded in status.
+func canaryReplicas(ctx *ExecutorContext, target rolloutv1alpha1.RolloutRunStepTarget, stable *workload.Info) (intstr.IntOrString, error) {
+	canary := ctx.RolloutRun.Spec.Canary
+	if !canary.ReplicasFollowTrafficWeight || canary.Traffic == nil || canary.Traffic.CanaryWeight() == nil {
+		return target.Replicas, nil
+	}
+
+	requested, err := workload.CalculateUpdatedReplicas(&stable.Status.Replicas, target.Replicas)
+	if err != nil {
+		return intstr.IntOrString{}, err
+	}
+	coupling := coupleReplicasToWeight(requested, stable.Status.Replicas, *canary.Traffic.CanaryWeight())
+	coupling.CrossClusterObjectNameReference = target.CrossClusterObjectNameReference
+
+	status := ctx.NewStatus.CanaryStatus
+	_, index, found := lo.FindIndexOf(status.ReplicaCoupling, func(c rolloutv1alpha1.ReplicaCouplingStatus) bool {
+		return c.CrossClusterObjectNameReference == target.CrossClusterObjectNameReference
+	})
+	if found {
+		status.ReplicaCoupling[index] = coupling
+	} else {
+		status.ReplicaCoupling = append(status.ReplicaCoupling, coupling)
+	}
+
+	return intstr.FromInt(int(coupling.Replicas)), nil
+}
+
+// coupleReplicasToWeight raises requested replicas to the weight percent of
+// stable replicas, rounding up and not exceeding stable replicas.
+func coupleReplicasToWeight(requested, stableReplicas, weight int32) rolloutv1alpha1.ReplicaCouplingStatus {
+	weightReplicas := int32(math.Ceil(float64(stableReplicas) * float64(weight) / 100))
+	if weightReplicas > stableReplicas {
+		weightReplicas = stableReplicas
+	}
+	return rolloutv1alpha1.ReplicaCouplingStatus{
+		Weight:            weight,
+		StableReplicas:    stableReplicas,
+		RequestedReplicas: requested,
+		Replicas:          max(requested, weightReplicas),
+	}
+}
@@ -0,0 +1,46 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_coupleReplicasToWeight(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested int32
+		stable    int32
+		weight    int32
+		want      int32
+	}{
+		{name: "requested is enough", requested: 6, stable: 10, weight: 50, want: 6},
+		{name: "raised to weight percent", requested: 1, stable: 10, weight: 50, want: 5},
+		{name: "round up", requested: 1, stable: 3, weight: 50, want: 2},
+		{name: "not exceeding stable", requested: 1, stable: 3, weight: 100, want: 3},
+		{name: "zero weight", requested: 1, stable: 10, weight: 0, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := coupleReplicasToWeight(tt.requested, tt.stable, tt.weight)
+			assert.Equal(t, tt.want, got.Replicas)
+			assert.Equal(t, tt.requested, got.RequestedReplicas)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+const (
+	ReasonCanaryRetained       = "CanaryRetained"
+	ReasonCanaryRetentionEnded = "CanaryRetentionEnded"
+	ReasonEndRetentionRejected = "EndRetentionRejected"
+)
+
+// retainCanary keeps canary resources in recycle until the retention window
+// ends, the traffic of canary is already reverted. The window is recorded in
+// status when it starts, so that it is neither reset nor skipped by restarts
+// of controller.
+func (e *canaryExecutor) retainCanary(ctx *ExecutorContext, now time.Time) (bool, time.Duration, error) {
+	seconds := ctx.RolloutRun.Spec.Canary.RetentionSeconds
+	if seconds == nil {
+		return true, retryImmediately, nil
+	}
+
+	status := ctx.NewStatus.CanaryStatus
+	if status.Retention == nil {
+		status.Retention = &rolloutv1alpha1.CanaryRetentionStatus{
+			StartTime: ptr.To(metav1.NewTime(now)),
+			EndTime:   ptr.To(metav1.NewTime(now.Add(time.Duration(*seconds) * time.Second))),
+		}
+		ctx.Recorder.Eventf(ctx.RolloutRun, corev1.EventTypeNormal, ReasonCanaryRetained,
+			"canary traffic is reverted, canary resources are retained until %s", status.Retention.EndTime.UTC().Format(time.RFC3339))
+	}
+
+	if remaining := status.Retention.EndTime.Sub(now); remaining > 0 {
+		ctx.GetCanaryLogger().Info("canary is retained, waiting for retention window to end", "remaining", remaining.String())
+		status.WaitingReason = rolloutv1alpha1.StepCanaryRetained
+		return false, remaining, nil
+	}
+	return true, retryImmediately, nil
+}
+
+// endCanaryRetention cuts the retention window of canary short, the retained
+// canary resources are deleted by the next reconcile.
+func endCanaryRetention(ctx *ExecutorContext, operator string, now time.Time) {
+	status := ctx.NewStatus.CanaryStatus
+	if !ctx.inCanary() || status == nil || status.Retention == nil || !status.Retention.EndTime.After(now) {
+		ctx.GetCanaryLogger().Info("end-retention command is rejected, canary is not retained")
+		ctx.Recorder.Event(ctx.RolloutRun, corev1.EventTypeWarning, ReasonEndRetentionRejected, "end-retention is rejected: canary is not retained")
+		return
+	}
+
+	status.Retention.EndTime = ptr.To(metav1.NewTime(now))
+	status.Retention.EndedBy = operator
+
+	by := operator
+	if len(by) == 0 {
+		by = "unknown"
+	}
+	ctx.Recorder.Eventf(ctx.RolloutRun, corev1.EventTypeNormal, ReasonCanaryRetentionEnded, "canary retention is ended by %s", by)
+}
@@ -0,0 +1,111 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+
+	rolloutapis "kusionstack.io/rollout/apis/rollout"
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+func Test_canaryRecycleOrder(t *testing.T) {
+	canary := &rolloutv1alpha1.RolloutRunCanaryStrategy{}
+	assert.Equal(t, rolloutv1alpha1.DefaultCanaryRecycleOrder, canaryRecycleOrder(canary))
+
+	// deletion is deferred after traffic operations if canary is retained
+	canary.RetentionSeconds = ptr.To[int32](60)
+	assert.Equal(t, []rolloutv1alpha1.CanaryRecycleOperation{
+		rolloutv1alpha1.RevertCanaryTraffic, rolloutv1alpha1.RevertStableTraffic, rolloutv1alpha1.DeleteCanaryResource,
+	}, canaryRecycleOrder(canary))
+	// the default order is not changed
+	assert.Equal(t, rolloutv1alpha1.DeleteCanaryResource, rolloutv1alpha1.DefaultCanaryRecycleOrder[1])
+}
+
+func Test_CanaryExecutor_retainCanary(t *testing.T) {
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary.RetentionSeconds = ptr.To[int32](1800)
+	rolloutRun.Status.Phase = rolloutv1alpha1.RolloutRunPhaseProgressing
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{State: StepResourceRecycling}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+	ctx.Initialize()
+	e := newCanaryExecutor(newFakeWebhookExecutor())
+
+	now := time.Now()
+	done, retry, err := e.retainCanary(ctx, now)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, 30*time.Minute, retry)
+	assert.Equal(t, rolloutv1alpha1.StepCanaryRetained, ctx.NewStatus.CanaryStatus.WaitingReason)
+	retention := ctx.NewStatus.CanaryStatus.Retention
+	if !assert.NotNil(t, retention) {
+		return
+	}
+	assert.Equal(t, now.Add(30*time.Minute).Unix(), retention.EndTime.Unix())
+
+	// the window recorded in status is kept by a restarted controller
+	rolloutRun.Status = *ctx.NewStatus.DeepCopy()
+	ctx = createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+	ctx.Initialize()
+	later := now.Add(10 * time.Minute)
+	done, retry, err = newCanaryExecutor(newFakeWebhookExecutor()).retainCanary(ctx, later)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, 20*time.Minute, retry)
+	assert.Equal(t, retention.EndTime.Unix(), ctx.NewStatus.CanaryStatus.Retention.EndTime.Unix())
+
+	// cut short by command
+	endCanaryRetention(ctx, "alice", later)
+	assert.Equal(t, "alice", ctx.NewStatus.CanaryStatus.Retention.EndedBy)
+	done, _, err = e.retainCanary(ctx, later)
+	assert.NoError(t, err)
+	assert.True(t, done)
+
+	// the ended retention can not be ended again
+	endCanaryRetention(ctx, "bob", later.Add(time.Second))
+	assert.Equal(t, "alice", ctx.NewStatus.CanaryStatus.Retention.EndedBy)
+}
+
+func TestExecutor_doCommand_EndRetention(t *testing.T) {
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	rolloutRun.Annotations = map[string]string{
+		rolloutapis.AnnoManualCommandKey: rolloutapis.AnnoManualCommandEndRetention,
+		rolloutapis.AnnoManualCommandBy:  "bob",
+	}
+	rolloutRun.Status.Phase = rolloutv1alpha1.RolloutRunPhaseProgressing
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{State: StepResourceRecycling}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+	ctx.Initialize()
+
+	r := NewDefaultExecutor(newTestLogger())
+	// canary is not retained yet
+	r.doCommand(ctx)
+	assert.Nil(t, ctx.NewStatus.CanaryStatus.Retention)
+
+	ctx.RolloutRun.Spec.Canary.RetentionSeconds = ptr.To[int32](60)
+	_, _, err := r.canary.retainCanary(ctx, time.Now())
+	assert.NoError(t, err)
+	r.doCommand(ctx)
+	if assert.NotNil(t, ctx.NewStatus.CanaryStatus.Retention) {
+		assert.Equal(t, "bob", ctx.NewStatus.CanaryStatus.Retention.EndedBy)
+		assert.False(t, ctx.NewStatus.CanaryStatus.Retention.EndTime.After(time.Now()))
+	}
+}
@@ -0,0 +1,77 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+const defaultRevertRampIntervalSeconds = 30
+
+// revertRampWeight returns the canary weight of the given step, the weight is
+// decreased evenly from the initial weight and reaches 0 at the last step.
+func revertRampWeight(weight, steps, step int32) int32 {
+	return weight * (steps - step) / steps
+}
+
+// rampDownCanary returns canary traffic to stable step by step before canary
+// traffic is reverted. The last step is done by reverting canary traffic.
+func (e *canaryExecutor) rampDownCanary(ctx *ExecutorContext) (bool, time.Duration, error) {
+	traffic := ctx.RolloutRun.Spec.Canary.Traffic
+	if traffic == nil || traffic.RevertRamp == nil || traffic.CanaryWeight() == nil {
+		return true, retryImmediately, nil
+	}
+	ramp := traffic.RevertRamp
+	status := ctx.NewStatus.CanaryStatus
+
+	if status.RevertRamp != nil {
+		// make sure the weight of current step is applied
+		done, retry, err := e.modifyTraffic(ctx, "rampDownCanary")
+		if !done {
+			return false, retry, err
+		}
+
+		interval := time.Duration(defaultRevertRampIntervalSeconds) * time.Second
+		if ramp.IntervalSeconds > 0 {
+			interval = time.Duration(ramp.IntervalSeconds) * time.Second
+		}
+		if remaining := interval - time.Since(status.RevertRamp.LastStepTime.Time); remaining > 0 {
+			ctx.GetCanaryLogger().Info("ramping down canary traffic", "step", status.RevertRamp.Step, "weight", status.RevertRamp.Weight, "remaining", remaining.String())
+			return false, remaining, nil
+		}
+
+		if status.RevertRamp.Step >= ramp.Steps-1 {
+			return true, retryImmediately, nil
+		}
+	}
+
+	step := int32(1)
+	if status.RevertRamp != nil {
+		step = status.RevertRamp.Step + 1
+	}
+	status.RevertRamp = &rolloutv1alpha1.RevertRampStatus{
+		Step:         step,
+		Weight:       revertRampWeight(*traffic.CanaryWeight(), ramp.Steps, step),
+		LastStepTime: ptr.To(metav1.Now()),
+	}
+	return false, retryImmediately, nil
+}
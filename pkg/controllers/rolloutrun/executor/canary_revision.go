@@ -0,0 +1,68 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+// verifyCanaryRevision cross-checks the available replicas reported by canary
+// workload with its pods. Only the ready pods of the updated revision are
+// counted, so that the pods of an old revision reported by a lagging workload
+// status are not taken as canary. It returns a copy of canary whose ready and
+// available replicas are capped by the verified pods, and the number of pods
+// excluded. The pods of ordinals are already verified by CheckPartitionReady.
+func verifyCanaryRevision(ctx context.Context, c client.Client, accessor workload.Accessor, canary *workload.Info) (*workload.Info, int32, error) {
+	pc, ok := accessor.(workload.PodControl)
+	if !ok || canary.Object == nil || canary.Status.UpdatedAvailableReplicas == 0 {
+		return canary, 0, nil
+	}
+	pods, err := listCanaryPods(ctx, c, accessor, canary)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	verified := int32(0)
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil || !isPodReady(pod) {
+			continue
+		}
+		updated, err := pc.IsUpdatedPod(c, canary.Object, pod)
+		if err != nil {
+			return nil, 0, err
+		}
+		if updated {
+			verified++
+		}
+	}
+	if verified >= canary.Status.UpdatedAvailableReplicas {
+		return canary, 0, nil
+	}
+
+	result := *canary
+	excluded := result.Status.UpdatedAvailableReplicas - verified
+	result.Status.UpdatedAvailableReplicas = verified
+	if result.Status.UpdatedReadyReplicas > verified {
+		result.Status.UpdatedReadyReplicas = verified
+	}
+	return &result, excluded, nil
+}
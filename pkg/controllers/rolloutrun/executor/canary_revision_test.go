@@ -0,0 +1,93 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+func Test_verifyCanaryRevision(t *testing.T) {
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), testCanaryRolloutRun.DeepCopy())
+
+	sts := newFakeObject("cluster-a", "default", "test-1-canary", 4, 0, 4)
+	sts.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test-canary"}}
+	sts.Status.CurrentRevision = "test-1-canary-v1"
+	sts.Status.UpdateRevision = "test-1-canary-v2"
+	canary := &workload.Info{
+		ObjectMeta: metav1.ObjectMeta{Name: sts.Name, Namespace: sts.Namespace, ClusterName: "cluster-a"},
+		Object:     sts,
+		Status: workload.InfoStatus{
+			UpdatedRevision:          sts.Status.UpdateRevision,
+			Replicas:                 4,
+			UpdatedReplicas:          4,
+			UpdatedReadyReplicas:     3,
+			UpdatedAvailableReplicas: 3,
+		},
+	}
+
+	// a lagging status reports the ready pod of old revision as canary
+	pods := []struct {
+		name     string
+		revision string
+		ready    bool
+	}{
+		{name: "test-1-canary-0", revision: "test-1-canary-v2", ready: true},
+		{name: "test-1-canary-1", revision: "test-1-canary-v1", ready: true},
+		{name: "test-1-canary-2", revision: "test-1-canary-v2", ready: false},
+		{name: "test-1-canary-3", revision: "test-1-canary-v2", ready: true},
+	}
+	for _, p := range pods {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      p.name,
+				Namespace: "default",
+				Labels:    map[string]string{"app": "test-canary", appsv1.ControllerRevisionHashLabelKey: p.revision},
+			},
+		}
+		assert.NoError(t, ctx.Client.Create(ctx, pod))
+		status := corev1.ConditionFalse
+		if p.ready {
+			status = corev1.ConditionTrue
+		}
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: status}}
+		assert.NoError(t, ctx.Client.Status().Update(ctx, pod))
+	}
+
+	verified, excluded, err := verifyCanaryRevision(ctx, ctx.Client, ctx.Accessor, canary)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), excluded)
+	assert.Equal(t, int32(2), verified.Status.UpdatedAvailableReplicas)
+	assert.Equal(t, int32(2), verified.Status.UpdatedReadyReplicas)
+	assert.False(t, verified.CheckUpdatedReady(verified.Status.Replicas))
+	// the reported status is not changed
+	assert.Equal(t, int32(3), canary.Status.UpdatedAvailableReplicas)
+
+	// all reported pods are verified
+	canary.Status.UpdatedReadyReplicas = 2
+	canary.Status.UpdatedAvailableReplicas = 2
+	verified, excluded, err = verifyCanaryRevision(ctx, ctx.Client, ctx.Accessor, canary)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0), excluded)
+	assert.Same(t, canary, verified)
+}
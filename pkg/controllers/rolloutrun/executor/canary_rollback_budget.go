@@ -0,0 +1,126 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+const (
+	ReasonCanaryRolledBack        = "CanaryRolledBack"
+	ReasonRollbackBudgetExhausted = "RollbackBudgetExhausted"
+	defaultRollbackWindowSeconds  = 3600
+)
+
+// isAutoRollingBack returns true if the failed canary is being recycled before
+// it is retried.
+func isAutoRollingBack(status *rolloutv1alpha1.RolloutRunStepStatus) bool {
+	return status != nil && status.Rollbacks != nil && status.Rollbacks.RollingBack
+}
+
+// isRollbackBudgetExhausted returns true if the failed canary is paused by the
+// exhausted budget of rollbacks.
+func isRollbackBudgetExhausted(status *rolloutv1alpha1.RolloutRunStepStatus) bool {
+	return status.Rollbacks != nil && status.Rollbacks.Exhausted
+}
+
+// rollbackFailedCanary rolls back the canary failed with a terminal error if
+// autoRollback is enabled. It returns true if the rollback is started, or
+// pauses the rolloutRun if the budget of rollbacks is exhausted.
+func rollbackFailedCanary(ctx *ExecutorContext, now time.Time) bool {
+	config := ctx.RolloutRun.Spec.Canary.AutoRollback
+	status := ctx.NewStatus.CanaryStatus
+	if config == nil || ctx.NewStatus.Error == nil || isAutoRollingBack(status) {
+		// canary failing to be recycled is not rolled back again
+		return false
+	}
+	switch status.State {
+	case StepPreCanaryStepHook, StepRunning, StepHolding, StepPostCanaryStepHook:
+	default:
+		return false
+	}
+
+	if status.Rollbacks == nil {
+		status.Rollbacks = &rolloutv1alpha1.CanaryRollbackStatus{}
+	}
+	rollbacks := status.Rollbacks
+	window := time.Duration(ptr.Deref(config.WindowSeconds, defaultRollbackWindowSeconds)) * time.Second
+	times := make([]metav1.Time, 0, len(rollbacks.Times))
+	for _, t := range rollbacks.Times {
+		if now.Sub(t.Time) < window {
+			times = append(times, t)
+		}
+	}
+	rollbacks.Times = times
+
+	if rollbacks.Exhausted || int32(len(rollbacks.Times)) >= config.MaxRollbacks {
+		if !rollbacks.Exhausted {
+			rollbacks.Exhausted = true
+			ctx.Recorder.Eventf(ctx.RolloutRun, corev1.EventTypeWarning, ReasonRollbackBudgetExhausted,
+				"canary is rolled back %d times within %s, waiting for the rollback budget to be reset", len(rollbacks.Times), window)
+		}
+		ctx.GetCanaryLogger().Info("rollback budget of canary is exhausted, pause it", "rollbacks", len(rollbacks.Times))
+		status.WaitingReason = rolloutv1alpha1.StepRollbackBudgetExhausted
+		ctx.Pause()
+		return false
+	}
+
+	startCanaryRollback(ctx, now)
+	ctx.Recorder.Eventf(ctx.RolloutRun, corev1.EventTypeWarning, ReasonCanaryRolledBack,
+		"canary failed with %s, it is rolled back and retried, %d of %d rollbacks within %s",
+		rollbacks.LastError.Code, len(rollbacks.Times), config.MaxRollbacks, window)
+	return true
+}
+
+// startCanaryRollback clears the error of failed canary and recycles it, the
+// canary is retried from the start once it is recycled.
+func startCanaryRollback(ctx *ExecutorContext, now time.Time) {
+	rollbacks := ctx.NewStatus.CanaryStatus.Rollbacks
+	rollbacks.Times = append(rollbacks.Times, metav1.NewTime(now))
+	rollbacks.RollingBack = true
+	rollbacks.LastError = ctx.NewStatus.Error.DeepCopy()
+	ctx.NewStatus.Error = nil
+	ctx.MoveToNextState(StepResourceRecycling)
+}
+
+// resetRollbackBudget forgets the rollbacks counted against the budget. The
+// failed canary paused by the exhausted budget is rolled back and retried.
+func resetRollbackBudget(ctx *ExecutorContext, now time.Time) {
+	if !ctx.inCanary() || ctx.NewStatus.CanaryStatus == nil || ctx.NewStatus.CanaryStatus.Rollbacks == nil {
+		return
+	}
+	status := ctx.NewStatus.CanaryStatus
+	exhausted := status.Rollbacks.Exhausted
+	status.Rollbacks.Times = nil
+	status.Rollbacks.Exhausted = false
+	if !exhausted {
+		return
+	}
+	if ctx.NewStatus.Error != nil {
+		startCanaryRollback(ctx, now)
+	}
+	status.WaitingReason = ""
+	if ctx.NewStatus.Phase == rolloutv1alpha1.RolloutRunPhasePaused {
+		ctx.NewStatus.Phase = rolloutv1alpha1.RolloutRunPhaseProgressing
+	}
+}
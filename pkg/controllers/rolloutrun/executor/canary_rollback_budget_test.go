@@ -0,0 +1,147 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+func Test_rollbackFailedCanary(t *testing.T) {
+	now := time.Now()
+	recent := metav1.NewTime(now.Add(-time.Minute))
+	expired := metav1.NewTime(now.Add(-2 * time.Hour))
+
+	tests := []struct {
+		name          string
+		config        *rolloutv1alpha1.CanaryAutoRollback
+		state         rolloutv1alpha1.RolloutStepState
+		rollbacks     *rolloutv1alpha1.CanaryRollbackStatus
+		wantRollback  bool
+		wantExhausted bool
+		wantTimes     int
+	}{
+		{
+			name:  "disabled",
+			state: StepRunning,
+		},
+		{
+			name:         "first rollback",
+			config:       &rolloutv1alpha1.CanaryAutoRollback{MaxRollbacks: 2},
+			state:        StepRunning,
+			wantRollback: true,
+			wantTimes:    1,
+		},
+		{
+			name:         "expired rollbacks are not counted",
+			config:       &rolloutv1alpha1.CanaryAutoRollback{MaxRollbacks: 2},
+			state:        StepPostCanaryStepHook,
+			rollbacks:    &rolloutv1alpha1.CanaryRollbackStatus{Times: []metav1.Time{expired, expired, recent}},
+			wantRollback: true,
+			wantTimes:    2,
+		},
+		{
+			name:          "budget exhausted",
+			config:        &rolloutv1alpha1.CanaryAutoRollback{MaxRollbacks: 2, WindowSeconds: ptr.To[int32](600)},
+			state:         StepRunning,
+			rollbacks:     &rolloutv1alpha1.CanaryRollbackStatus{Times: []metav1.Time{recent, recent}},
+			wantExhausted: true,
+			wantTimes:     2,
+		},
+		{
+			name:   "failed in recycling",
+			config: &rolloutv1alpha1.CanaryAutoRollback{MaxRollbacks: 2},
+			state:  StepResourceRecycling,
+		},
+		{
+			name:      "failed while rolling back",
+			config:    &rolloutv1alpha1.CanaryAutoRollback{MaxRollbacks: 2},
+			state:     StepRunning,
+			rollbacks: &rolloutv1alpha1.CanaryRollbackStatus{Times: []metav1.Time{recent}, RollingBack: true},
+			wantTimes: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rolloutRun := testCanaryRolloutRun.DeepCopy()
+			rolloutRun.Spec.Canary.AutoRollback = tt.config
+			rolloutRun.Status.Phase = rolloutv1alpha1.RolloutRunPhaseProgressing
+			rolloutRun.Status.Error = &rolloutv1alpha1.CodeReasonMessage{Code: ReasonStepGuardViolated}
+			rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{State: tt.state, Rollbacks: tt.rollbacks.DeepCopy()}
+			ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+			ctx.Initialize()
+
+			assert.Equal(t, tt.wantRollback, rollbackFailedCanary(ctx, now))
+
+			status := ctx.NewStatus.CanaryStatus
+			if tt.wantRollback {
+				assert.Nil(t, ctx.NewStatus.Error)
+				assert.Equal(t, StepResourceRecycling, status.State)
+				assert.True(t, isAutoRollingBack(status))
+				assert.Equal(t, ReasonStepGuardViolated, status.Rollbacks.LastError.Code)
+			} else {
+				assert.NotNil(t, ctx.NewStatus.Error)
+				assert.Equal(t, tt.state, status.State)
+			}
+			if tt.wantExhausted {
+				assert.True(t, isRollbackBudgetExhausted(status))
+				assert.Equal(t, rolloutv1alpha1.RolloutRunPhasePaused, ctx.NewStatus.Phase)
+				assert.Equal(t, rolloutv1alpha1.StepRollbackBudgetExhausted, status.WaitingReason)
+			} else {
+				assert.Equal(t, rolloutv1alpha1.RolloutRunPhaseProgressing, ctx.NewStatus.Phase)
+			}
+			if status.Rollbacks != nil {
+				assert.Len(t, status.Rollbacks.Times, tt.wantTimes)
+			}
+		})
+	}
+}
+
+func Test_resetRollbackBudget(t *testing.T) {
+	now := time.Now()
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary.AutoRollback = &rolloutv1alpha1.CanaryAutoRollback{MaxRollbacks: 1}
+	rolloutRun.Status.Phase = rolloutv1alpha1.RolloutRunPhaseProgressing
+	rolloutRun.Status.Error = &rolloutv1alpha1.CodeReasonMessage{Code: ReasonStepGuardViolated}
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{
+		State:     StepRunning,
+		Rollbacks: &rolloutv1alpha1.CanaryRollbackStatus{Times: []metav1.Time{metav1.NewTime(now)}},
+	}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+	ctx.Initialize()
+
+	// the budget is exhausted, canary is paused and not rolled back again
+	assert.False(t, rollbackFailedCanary(ctx, now))
+	assert.False(t, rollbackFailedCanary(ctx, now.Add(2*time.Hour)))
+	assert.Equal(t, rolloutv1alpha1.RolloutRunPhasePaused, ctx.NewStatus.Phase)
+
+	resetRollbackBudget(ctx, now)
+	status := ctx.NewStatus.CanaryStatus
+	assert.False(t, isRollbackBudgetExhausted(status))
+	assert.True(t, isAutoRollingBack(status))
+	assert.Len(t, status.Rollbacks.Times, 1)
+	assert.Nil(t, ctx.NewStatus.Error)
+	assert.Equal(t, StepResourceRecycling, status.State)
+	assert.Equal(t, rolloutv1alpha1.RolloutRunPhaseProgressing, ctx.NewStatus.Phase)
+	assert.Empty(t, status.WaitingReason)
+}
@@ -0,0 +1,99 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"slices"
+
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+// scaleUpIncrement returns the canary replicas of the current increment of
+// target, which scales up to finalReplicas by step. The increment starts from
+// one step and is only advanced by advanceScaleUp.
+func scaleUpIncrement(ctx *ExecutorContext, target rolloutv1alpha1.CrossClusterObjectNameReference, finalReplicas int32, step intstr.IntOrString) (int32, error) {
+	stepReplicas, err := scaleUpStepReplicas(finalReplicas, step)
+	if err != nil {
+		return 0, err
+	}
+
+	status := ctx.NewStatus.CanaryStatus
+	_, index, found := lo.FindIndexOf(status.ScaleUp, func(s rolloutv1alpha1.CanaryScaleUpStatus) bool {
+		return s.CrossClusterObjectNameReference == target
+	})
+	if !found {
+		status.ScaleUp = append(status.ScaleUp, rolloutv1alpha1.CanaryScaleUpStatus{
+			CrossClusterObjectNameReference: target,
+			Replicas:                        min(stepReplicas, finalReplicas),
+		})
+		index = len(status.ScaleUp) - 1
+	}
+	scaleUp := &status.ScaleUp[index]
+	// final replicas may change during the run, e.g. with stable replicas
+	scaleUp.FinalReplicas = finalReplicas
+	scaleUp.Replicas = min(scaleUp.Replicas, finalReplicas)
+	return scaleUp.Replicas, nil
+}
+
+// advanceScaleUp moves target to the next increment if the canary of current
+// increment is ready. It returns true if the increment is advanced.
+func advanceScaleUp(ctx *ExecutorContext, target rolloutv1alpha1.CrossClusterObjectNameReference, canary *workload.Info, step intstr.IntOrString) (bool, error) {
+	status := ctx.NewStatus.CanaryStatus
+	_, index, found := lo.FindIndexOf(status.ScaleUp, func(s rolloutv1alpha1.CanaryScaleUpStatus) bool {
+		return s.CrossClusterObjectNameReference == target
+	})
+	if !found {
+		return false, nil
+	}
+	scaleUp := &status.ScaleUp[index]
+	if scaleUp.Replicas >= scaleUp.FinalReplicas || !canary.CheckUpdatedReady(scaleUp.Replicas) {
+		return false, nil
+	}
+	stepReplicas, err := scaleUpStepReplicas(scaleUp.FinalReplicas, step)
+	if err != nil {
+		return false, err
+	}
+	scaleUp.Replicas = min(scaleUp.Replicas+stepReplicas, scaleUp.FinalReplicas)
+	ctx.GetCanaryLogger().Info("canary increment is ready, scale up canary",
+		"cluster", target.Cluster,
+		"name", target.Name,
+		"replicas", scaleUp.Replicas,
+		"finalReplicas", scaleUp.FinalReplicas,
+	)
+	return true, nil
+}
+
+// scaleUpStepReplicas returns the replicas added in each increment, at least 1.
+func scaleUpStepReplicas(finalReplicas int32, step intstr.IntOrString) (int32, error) {
+	replicas, err := intstr.GetScaledValueFromIntOrPercent(&step, int(finalReplicas), true)
+	if err != nil {
+		return 0, err
+	}
+	return max(int32(replicas), 1), nil
+}
+
+// largestOrdinals returns the largest n ordinals, since StatefulSet updates pods
+// from the largest ordinal by partition.
+func largestOrdinals(ordinals []int32, n int32) []int32 {
+	sorted := slices.Clone(ordinals)
+	slices.Sort(sorted)
+	return sorted[len(sorted)-int(n):]
+}
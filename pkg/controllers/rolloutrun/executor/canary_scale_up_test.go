@@ -0,0 +1,87 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+func Test_canaryScaleUp(t *testing.T) {
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+	ctx.Initialize()
+	target := rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-1"}
+	step := intstr.FromString("40%")
+
+	canary := &workload.Info{}
+	canary.Generation = 1
+	canary.Status.ObservedGeneration = 1
+
+	// 40% of 5 replicas rounds up to 2 replicas in each increment
+	replicas, err := scaleUpIncrement(ctx, target, 5, step)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, replicas)
+
+	// not advanced until the increment is ready
+	canary.Status.UpdatedAvailableReplicas = 1
+	advanced, err := advanceScaleUp(ctx, target, canary, step)
+	assert.NoError(t, err)
+	assert.False(t, advanced)
+
+	canary.Status.UpdatedAvailableReplicas = 2
+	advanced, err = advanceScaleUp(ctx, target, canary, step)
+	assert.NoError(t, err)
+	assert.True(t, advanced)
+	replicas, err = scaleUpIncrement(ctx, target, 5, step)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 4, replicas)
+
+	// the final increment is capped by final replicas
+	canary.Status.UpdatedAvailableReplicas = 4
+	advanced, err = advanceScaleUp(ctx, target, canary, step)
+	assert.NoError(t, err)
+	assert.True(t, advanced)
+	replicas, err = scaleUpIncrement(ctx, target, 5, step)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 5, replicas)
+
+	// never advanced beyond final replicas
+	canary.Status.UpdatedAvailableReplicas = 5
+	advanced, err = advanceScaleUp(ctx, target, canary, step)
+	assert.NoError(t, err)
+	assert.False(t, advanced)
+	assert.Equal(t, []rolloutv1alpha1.CanaryScaleUpStatus{
+		{CrossClusterObjectNameReference: target, Replicas: 5, FinalReplicas: 5},
+	}, ctx.NewStatus.CanaryStatus.ScaleUp)
+
+	// shrunk final replicas caps the current increment
+	replicas, err = scaleUpIncrement(ctx, target, 3, step)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, replicas)
+}
+
+func Test_largestOrdinals(t *testing.T) {
+	assert.Equal(t, []int32{4}, largestOrdinals([]int32{4, 2, 3}, 1))
+	assert.Equal(t, []int32{3, 4}, largestOrdinals([]int32{4, 2, 3}, 2))
+	assert.Equal(t, []int32{2, 3, 4}, largestOrdinals([]int32{4, 2, 3}, 3))
+}
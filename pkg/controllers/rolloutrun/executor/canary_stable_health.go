@@ -0,0 +1,71 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+const ReasonStableUnhealthy = "StableUnhealthy"
+
+// unhealthyStableTargets returns the targets whose stable available replicas
+// are below the min available.
+func unhealthyStableTargets(minAvailable intstr.IntOrString, targets []canaryTarget) ([]string, error) {
+	unhealthy := []string{}
+	for _, item := range targets {
+		status := item.info.Status
+		floor, err := workload.CalculateUpdatedReplicas(&status.Replicas, minAvailable)
+		if err != nil {
+			return nil, err
+		}
+		if status.AvailableReplicas < floor {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s(%d/%d)", item.CrossClusterObjectNameReference, status.AvailableReplicas, floor))
+		}
+	}
+	return unhealthy, nil
+}
+
+// checkStableHealth waits until stable of all targets are available enough to
+// absorb the remaining traffic before canary traffic is routed.
+func (e *canaryExecutor) checkStableHealth(ctx *ExecutorContext, targets []canaryTarget) (bool, time.Duration, error) {
+	canary := ctx.RolloutRun.Spec.Canary
+	if canary.StableMinAvailable == nil || canary.Traffic == nil {
+		return true, retryImmediately, nil
+	}
+
+	unhealthy, err := unhealthyStableTargets(*canary.StableMinAvailable, targets)
+	if err != nil {
+		return false, retryStop, err
+	}
+	if len(unhealthy) == 0 {
+		return true, retryImmediately, nil
+	}
+
+	ctx.GetCanaryLogger().Info("stable is not available enough, waiting before routing canary traffic", "reason", ReasonStableUnhealthy, "targets", unhealthy)
+	if old := ctx.RolloutRun.Status.CanaryStatus; old == nil || old.WaitingReason != rolloutv1alpha1.StepStableUnhealthy {
+		ctx.Recorder.Eventf(ctx.RolloutRun, corev1.EventTypeWarning, ReasonStableUnhealthy, "canary traffic is held since stable available replicas are below min available: %v", unhealthy)
+	}
+	ctx.NewStatus.CanaryStatus.WaitingReason = rolloutv1alpha1.StepStableUnhealthy
+	return false, retryDefault, nil
+}
@@ -0,0 +1,94 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+func Test_CanaryExecutor_checkStableHealth(t *testing.T) {
+	newTarget := func(name string, replicas, available int32) canaryTarget {
+		return canaryTarget{
+			RolloutRunStepTarget: rolloutv1alpha1.RolloutRunStepTarget{
+				CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: name},
+			},
+			info: &workload.Info{Status: workload.InfoStatus{Replicas: replicas, AvailableReplicas: available}},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		minAvailable *intstr.IntOrString
+		targets      []canaryTarget
+		wantDone     bool
+	}{
+		{
+			name:     "no min available",
+			targets:  []canaryTarget{newTarget("test-1", 10, 0)},
+			wantDone: true,
+		},
+		{
+			name:         "stable available",
+			minAvailable: ptr.To(intstr.FromString("80%")),
+			targets:      []canaryTarget{newTarget("test-1", 10, 8), newTarget("test-2", 5, 4)},
+			wantDone:     true,
+		},
+		{
+			name:         "percent is rounded up",
+			minAvailable: ptr.To(intstr.FromString("75%")),
+			targets:      []canaryTarget{newTarget("test-1", 10, 8), newTarget("test-2", 5, 3)},
+			wantDone:     false,
+		},
+		{
+			name:         "absolute min available",
+			minAvailable: ptr.To(intstr.FromInt(9)),
+			targets:      []canaryTarget{newTarget("test-1", 10, 8)},
+			wantDone:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rolloutRun := testRolloutRun.DeepCopy()
+			rolloutRun.Spec.Canary = &rolloutv1alpha1.RolloutRunCanaryStrategy{
+				Targets:            unimportantTargets,
+				Traffic:            &rolloutv1alpha1.TrafficStrategy{Weight: ptr.To[int32](10)},
+				StableMinAvailable: tt.minAvailable,
+			}
+			rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{
+				State: StepRunning,
+			}
+			ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+
+			e := newCanaryExecutor(newFakeWebhookExecutor())
+			done, _, err := e.checkStableHealth(ctx, tt.targets)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantDone, done)
+			if tt.wantDone {
+				assert.Empty(t, ctx.NewStatus.CanaryStatus.WaitingReason)
+			} else {
+				assert.Equal(t, rolloutv1alpha1.StepStableUnhealthy, ctx.NewStatus.CanaryStatus.WaitingReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+const ReasonCanaryDeletionStuck = "CanaryDeletionStuck"
+
+// stuckDeletionTimeout returns how long the canary workload can stay terminating
+// before it is considered stuck, which is never less than the termination grace
+// period of its pods, so that draining pods are not cut short.
+func stuckDeletionTimeout(accessor workload.Accessor, stuck *rolloutv1alpha1.CanaryStuckDeletion, canaryObj client.Object) time.Duration {
+	timeout := time.Duration(stuck.TimeoutSeconds) * time.Second
+	podControl, ok := accessor.(workload.PodControl)
+	if !ok {
+		return timeout
+	}
+	template, err := podControl.GetPodTemplate(canaryObj)
+	if err != nil || template.Spec.TerminationGracePeriodSeconds == nil {
+		return timeout
+	}
+	if grace := time.Duration(*template.Spec.TerminationGracePeriodSeconds) * time.Second; grace > timeout {
+		return grace
+	}
+	return timeout
+}
+
+// handleStuckDeletions takes the configured action on the canary workloads
+// which are still terminating after the stuck deletion timeout, and records it
+// in status.
+func (e *canaryExecutor) handleStuckDeletions(ctx *ExecutorContext, now time.Time) error {
+	stuck := ctx.RolloutRun.Spec.Canary.StuckDeletion
+	if stuck == nil {
+		return nil
+	}
+
+	targets, _, err := reachableCanaryTargets(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range targets {
+		for _, variant := range canaryVariants(ctx.RolloutRun) {
+			canaryControl := newCanaryReleaseControl(ctx, item, variant.Name)
+			canaryObj, err := canaryControl.GetCanaryObject(item.info)
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			deletion := canaryObj.GetDeletionTimestamp()
+			if deletion == nil {
+				continue
+			}
+			timeout := stuckDeletionTimeout(ctx.Accessor, stuck, canaryObj)
+			if now.Sub(deletion.Time) < timeout {
+				continue
+			}
+
+			record := rolloutv1alpha1.CanaryStuckDeletionStatus{
+				CrossClusterObjectNameReference: item.CrossClusterObjectNameReference,
+				DeletionTime:                    deletion.DeepCopy(),
+				TimeoutSeconds:                  int32(timeout / time.Second),
+				Action:                          stuck.Action,
+				ActionTime:                      ptr.To(metav1.NewTime(now)),
+			}
+			msg := fmt.Sprintf("canary workload of %s is still terminating %v after deleted, finalizers: %v",
+				item.CrossClusterObjectNameReference, timeout, canaryObj.GetFinalizers())
+
+			switch stuck.Action {
+			case rolloutv1alpha1.CanaryStuckDeletionForceDelete:
+				removed, err := canaryControl.ForceFinalize(item.info)
+				if err != nil {
+					return err
+				}
+				record.Finalizers = removed
+				recordStuckDeletion(ctx.NewStatus.CanaryStatus, record)
+				ctx.GetCanaryLogger().Info("force deleted stuck canary workload", "workload", item.CrossClusterObjectNameReference, "finalizers", removed)
+				ctx.Recorder.Eventf(ctx.RolloutRun, corev1.EventTypeWarning, ReasonCanaryDeletionStuck, "%s, removed finalizers to force delete it", msg)
+			default:
+				recordStuckDeletion(ctx.NewStatus.CanaryStatus, record)
+				return control.TerminalError(newDoCanaryError(ReasonCanaryDeletionStuck, msg))
+			}
+		}
+	}
+	return nil
+}
+
+// recordStuckDeletion adds the record of target to status, or replaces the
+// previous one.
+func recordStuckDeletion(status *rolloutv1alpha1.RolloutRunStepStatus, record rolloutv1alpha1.CanaryStuckDeletionStatus) {
+	for i := range status.StuckDeletions {
+		if status.StuckDeletions[i].CrossClusterObjectNameReference == record.CrossClusterObjectNameReference {
+			status.StuckDeletions[i] = record
+			return
+		}
+	}
+	status.StuckDeletions = append(status.StuckDeletions, record)
+}
@@ -0,0 +1,117 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+)
+
+func Test_handleStuckDeletions(t *testing.T) {
+	tests := []struct {
+		name        string
+		deletedAgo  time.Duration
+		gracePeriod *int64
+		action      rolloutv1alpha1.CanaryStuckDeletionAction
+		wantErr     bool
+		wantRecord  bool
+	}{
+		{
+			name:       "terminating within timeout",
+			deletedAgo: 30 * time.Second,
+			action:     rolloutv1alpha1.CanaryStuckDeletionForceDelete,
+		},
+		{
+			name:        "pods draining within termination grace period",
+			deletedAgo:  2 * time.Minute,
+			gracePeriod: ptr.To[int64](300),
+			action:      rolloutv1alpha1.CanaryStuckDeletionForceDelete,
+		},
+		{
+			name:       "force delete stuck canary",
+			deletedAgo: 2 * time.Minute,
+			action:     rolloutv1alpha1.CanaryStuckDeletionForceDelete,
+			wantRecord: true,
+		},
+		{
+			name:       "fail on stuck canary",
+			deletedAgo: 2 * time.Minute,
+			action:     rolloutv1alpha1.CanaryStuckDeletionFail,
+			wantErr:    true,
+			wantRecord: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rolloutRun := testCanaryRolloutRun.DeepCopy()
+			rolloutRun.Spec.Canary.Targets = []rolloutv1alpha1.RolloutRunStepTarget{
+				{CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-1"}},
+			}
+			rolloutRun.Spec.Canary.StuckDeletion = &rolloutv1alpha1.CanaryStuckDeletion{TimeoutSeconds: 60, Action: tt.action}
+			rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{State: StepResourceRecycling}
+
+			now := time.Now()
+			canaryObj := newFakeObject("cluster-a", "default", "test-1-canary", 1, 0, 0)
+			canaryObj.DeletionTimestamp = ptr.To(metav1.NewTime(now.Add(-tt.deletedAgo)))
+			canaryObj.Finalizers = []string{"example.io/pvc-protection"}
+			canaryObj.Spec.Template.Spec.TerminationGracePeriodSeconds = tt.gracePeriod
+			ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun,
+				newFakeObject("cluster-a", "default", "test-1", 10, 0, 0),
+				canaryObj,
+			)
+			e := newCanaryExecutor(newFakeWebhookExecutor())
+
+			err := e.handleStuckDeletions(ctx, now)
+			assert.Equal(t, tt.wantErr, err != nil)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, control.TerminalError(nil)))
+			}
+
+			records := ctx.NewStatus.CanaryStatus.StuckDeletions
+			if !tt.wantRecord {
+				assert.Empty(t, records)
+				return
+			}
+			if assert.Len(t, records, 1) {
+				assert.Equal(t, "test-1", records[0].Name)
+				assert.Equal(t, tt.action, records[0].Action)
+				assert.EqualValues(t, 60, records[0].TimeoutSeconds)
+				assert.NotNil(t, records[0].ActionTime)
+			}
+
+			got := &appsv1.StatefulSet{}
+			err = ctx.Client.Get(ctx, client.ObjectKeyFromObject(canaryObj), got)
+			if tt.action == rolloutv1alpha1.CanaryStuckDeletionForceDelete {
+				assert.Equal(t, []string{"example.io/pvc-protection"}, records[0].Finalizers)
+				assert.True(t, err != nil || len(got.Finalizers) == 0)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, canaryObj.Finalizers, got.Finalizers)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+// CanaryTargetInfo is the canary workload of a canary target.
+type CanaryTargetInfo struct {
+	Target rolloutv1alpha1.RolloutRunStepTarget
+	Info   *workload.Info
+}
+
+// CanarySummary is the aggregated readiness of canary workloads.
+type CanarySummary struct {
+	rolloutv1alpha1.RolloutReplicasSummary
+	// NotReady are the canary workloads not ready yet, in the order of input.
+	NotReady []CanaryTargetInfo
+}
+
+// AggregateCanaryInfo sums up the replicas of canary workloads and collects
+// the ones which are not ready.
+func AggregateCanaryInfo(infos []CanaryTargetInfo) CanarySummary {
+	return aggregateCanaryInfo(infos, func(info *workload.Info) bool {
+		return info.CheckUpdatedReady(info.Status.Replicas)
+	})
+}
+
+// aggregateCanaryInfo is AggregateCanaryInfo with the readiness of canary
+// workloads decided by ready.
+func aggregateCanaryInfo(infos []CanaryTargetInfo, ready func(*workload.Info) bool) CanarySummary {
+	summary := CanarySummary{}
+	for _, item := range infos {
+		status := item.Info.Status
+		summary.Replicas += status.Replicas
+		summary.UpdatedReplicas += status.UpdatedReplicas
+		summary.UpdatedReadyReplicas += status.UpdatedReadyReplicas
+		summary.UpdatedAvailableReplicas += status.UpdatedAvailableReplicas
+		if !ready(item.Info) {
+			summary.NotReady = append(summary.NotReady, item)
+		}
+	}
+	return summary
+}
+
+// Ready returns true if all canary workloads are ready.
+func (s CanarySummary) Ready() bool {
+	return len(s.NotReady) == 0
+}
+
+// APIStatus returns the summary recorded in canary status.
+func (s CanarySummary) APIStatus() *rolloutv1alpha1.CanaryReplicasStatus {
+	status := &rolloutv1alpha1.CanaryReplicasStatus{
+		RolloutReplicasSummary: s.RolloutReplicasSummary,
+	}
+	for _, item := range s.NotReady {
+		status.NotReadyTargets = append(status.NotReadyTargets, item.Target.CrossClusterObjectNameReference)
+	}
+	return status
+}
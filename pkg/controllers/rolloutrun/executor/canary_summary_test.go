@@ -0,0 +1,99 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+func newTestCanaryTargetInfo(name string, status workload.InfoStatus) CanaryTargetInfo {
+	info := &workload.Info{Status: status}
+	info.Name = name + "-canary"
+	info.ClusterName = "cluster-a"
+	return CanaryTargetInfo{
+		Target: rolloutv1alpha1.RolloutRunStepTarget{
+			CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: name},
+		},
+		Info: info,
+	}
+}
+
+func TestAggregateCanaryInfo(t *testing.T) {
+	tests := []struct {
+		name      string
+		infos     []CanaryTargetInfo
+		want      rolloutv1alpha1.RolloutReplicasSummary
+		wantReady bool
+		notReady  []rolloutv1alpha1.CrossClusterObjectNameReference
+	}{
+		{
+			name:      "empty targets",
+			wantReady: true,
+		},
+		{
+			name: "all ready",
+			infos: []CanaryTargetInfo{
+				newTestCanaryTargetInfo("a", workload.InfoStatus{Replicas: 2, UpdatedReplicas: 2, UpdatedReadyReplicas: 2, UpdatedAvailableReplicas: 2}),
+				newTestCanaryTargetInfo("b", workload.InfoStatus{Replicas: 1, UpdatedReplicas: 1, UpdatedReadyReplicas: 1, UpdatedAvailableReplicas: 1}),
+			},
+			want:      rolloutv1alpha1.RolloutReplicasSummary{Replicas: 3, UpdatedReplicas: 3, UpdatedReadyReplicas: 3, UpdatedAvailableReplicas: 3},
+			wantReady: true,
+		},
+		{
+			name: "partial readiness",
+			infos: []CanaryTargetInfo{
+				newTestCanaryTargetInfo("a", workload.InfoStatus{Replicas: 2, UpdatedReplicas: 2, UpdatedReadyReplicas: 2, UpdatedAvailableReplicas: 2}),
+				newTestCanaryTargetInfo("b", workload.InfoStatus{Replicas: 2, UpdatedReplicas: 2, UpdatedReadyReplicas: 1, UpdatedAvailableReplicas: 1}),
+				newTestCanaryTargetInfo("c", workload.InfoStatus{Replicas: 1}),
+			},
+			want:      rolloutv1alpha1.RolloutReplicasSummary{Replicas: 5, UpdatedReplicas: 4, UpdatedReadyReplicas: 3, UpdatedAvailableReplicas: 3},
+			wantReady: false,
+			notReady: []rolloutv1alpha1.CrossClusterObjectNameReference{
+				{Cluster: "cluster-a", Name: "b"},
+				{Cluster: "cluster-a", Name: "c"},
+			},
+		},
+		{
+			name: "generation not observed",
+			infos: func() []CanaryTargetInfo {
+				item := newTestCanaryTargetInfo("a", workload.InfoStatus{Replicas: 1, UpdatedReplicas: 1, UpdatedReadyReplicas: 1, UpdatedAvailableReplicas: 1})
+				item.Info.Generation = 2
+				item.Info.Status.ObservedGeneration = 1
+				return []CanaryTargetInfo{item}
+			}(),
+			want:      rolloutv1alpha1.RolloutReplicasSummary{Replicas: 1, UpdatedReplicas: 1, UpdatedReadyReplicas: 1, UpdatedAvailableReplicas: 1},
+			wantReady: false,
+			notReady:  []rolloutv1alpha1.CrossClusterObjectNameReference{{Cluster: "cluster-a", Name: "a"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AggregateCanaryInfo(tt.infos)
+			assert.Equal(t, tt.want, got.RolloutReplicasSummary)
+			assert.Equal(t, tt.wantReady, got.Ready())
+			assert.Equal(t, &rolloutv1alpha1.CanaryReplicasStatus{
+				RolloutReplicasSummary: tt.want,
+				NotReadyTargets:        tt.notReady,
+			}, got.APIStatus())
+		})
+	}
+}
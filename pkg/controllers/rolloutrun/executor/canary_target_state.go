@@ -0,0 +1,79 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/samber/lo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+// recordCanaryTargetStates records the state of each target in the creation
+// and readiness phases. A target is creating if its canary workloads are
+// changed in this reconcile, otherwise it is waiting for its own readiness,
+// so that a slow target does not hide the progress of the others. The states
+// are only reported, the step still advances after all targets are created
+// and ready. The states of targets no longer progressing are dropped.
+func recordCanaryTargetStates(
+	status *rolloutv1alpha1.RolloutRunStepStatus,
+	targets []canaryTarget,
+	changed []rolloutv1alpha1.CrossClusterObjectNameReference,
+	summary CanarySummary,
+	timedOut []rolloutv1alpha1.CrossClusterObjectNameReference,
+	now time.Time,
+) {
+	states := make([]rolloutv1alpha1.CanaryTargetState, 0, len(targets))
+	for _, item := range targets {
+		ref := item.CrossClusterObjectNameReference
+		state := rolloutv1alpha1.CanaryTargetState{
+			CrossClusterObjectNameReference: ref,
+			Phase:                           rolloutv1alpha1.CanaryTargetReady,
+		}
+		notReady := lo.Filter(summary.NotReady, func(info CanaryTargetInfo, _ int) bool {
+			return info.Target.CrossClusterObjectNameReference == ref
+		})
+		switch {
+		case lo.Contains(changed, ref):
+			state.Phase = rolloutv1alpha1.CanaryTargetCreating
+		case lo.Contains(timedOut, ref):
+			state.Phase = rolloutv1alpha1.CanaryTargetTimedOut
+			state.Message = "canary is not ready before deadline"
+		case len(notReady) > 0:
+			state.Phase = rolloutv1alpha1.CanaryTargetWaitingReady
+			var replicas, ready int32
+			for _, info := range notReady {
+				replicas += info.Info.Status.Replicas
+				ready += info.Info.Status.UpdatedAvailableReplicas
+			}
+			state.Message = fmt.Sprintf("%d/%d canary replicas of not ready workloads are available", ready, replicas)
+		}
+
+		state.LastTransitionTime = ptr.To(metav1.NewTime(now))
+		if last, found := lo.Find(status.TargetStates, func(s rolloutv1alpha1.CanaryTargetState) bool {
+			return s.CrossClusterObjectNameReference == ref
+		}); found && last.Phase == state.Phase && last.LastTransitionTime != nil {
+			state.LastTransitionTime = last.LastTransitionTime
+		}
+		states = append(states, state)
+	}
+	status.TargetStates = states
+}
@@ -0,0 +1,79 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+func Test_recordCanaryTargetStates(t *testing.T) {
+	newTarget := func(cluster string) canaryTarget {
+		return canaryTarget{RolloutRunStepTarget: rolloutv1alpha1.RolloutRunStepTarget{
+			CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: cluster, Name: "test-1"},
+		}}
+	}
+	creating, waiting, ready, timedOut := newTarget("cluster-a"), newTarget("cluster-b"), newTarget("cluster-c"), newTarget("cluster-d")
+	targets := []canaryTarget{creating, waiting, ready, timedOut}
+	notReady := func(item canaryTarget) CanaryTargetInfo {
+		return CanaryTargetInfo{
+			Target: item.RolloutRunStepTarget,
+			Info:   &workload.Info{Status: workload.InfoStatus{Replicas: 2, UpdatedAvailableReplicas: 1}},
+		}
+	}
+	summary := CanarySummary{NotReady: []CanaryTargetInfo{notReady(creating), notReady(waiting), notReady(timedOut)}}
+
+	now := time.Now()
+	status := &rolloutv1alpha1.RolloutRunStepStatus{}
+	recordCanaryTargetStates(status, targets,
+		[]rolloutv1alpha1.CrossClusterObjectNameReference{creating.CrossClusterObjectNameReference},
+		summary,
+		[]rolloutv1alpha1.CrossClusterObjectNameReference{timedOut.CrossClusterObjectNameReference},
+		now,
+	)
+	phases := func() []rolloutv1alpha1.CanaryTargetPhase {
+		result := make([]rolloutv1alpha1.CanaryTargetPhase, 0)
+		for _, state := range status.TargetStates {
+			result = append(result, state.Phase)
+		}
+		return result
+	}
+	assert.Equal(t, []rolloutv1alpha1.CanaryTargetPhase{
+		rolloutv1alpha1.CanaryTargetCreating,
+		rolloutv1alpha1.CanaryTargetWaitingReady,
+		rolloutv1alpha1.CanaryTargetReady,
+		rolloutv1alpha1.CanaryTargetTimedOut,
+	}, phases())
+	assert.Equal(t, "1/2 canary replicas of not ready workloads are available", status.TargetStates[1].Message)
+
+	// the creating target is ready later, and the others keep their transition time
+	later := now.Add(time.Minute)
+	recordCanaryTargetStates(status, targets[:3], nil, CanarySummary{NotReady: []CanaryTargetInfo{notReady(waiting)}}, nil, later)
+	assert.Equal(t, []rolloutv1alpha1.CanaryTargetPhase{
+		rolloutv1alpha1.CanaryTargetReady,
+		rolloutv1alpha1.CanaryTargetWaitingReady,
+		rolloutv1alpha1.CanaryTargetReady,
+	}, phases())
+	assert.Equal(t, later.Unix(), status.TargetStates[0].LastTransitionTime.Unix())
+	assert.Equal(t, now.Unix(), status.TargetStates[1].LastTransitionTime.Unix())
+	assert.Equal(t, now.Unix(), status.TargetStates[2].LastTransitionTime.Unix())
+}
@@ -0,0 +1,231 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutapi "kusionstack.io/rollout/apis/rollout"
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+func Test_CanaryExecutor_doPostStepHook(t *testing.T) {
+	tests := []struct {
+		name             string
+		pauseAfter       *bool
+		pauseBefore      []rolloutv1alpha1.CanaryPausePhase
+		wantPhase        rolloutv1alpha1.RolloutRunPhase
+		wantAutoContinue bool
+		wantWaiting      rolloutv1alpha1.StepWaitingReason
+	}{
+		{
+			name:        "pause after post step hook by default",
+			pauseAfter:  nil,
+			wantPhase:   rolloutv1alpha1.RolloutRunPhasePaused,
+			wantWaiting: rolloutv1alpha1.StepPaused,
+		},
+		{
+			name:             "auto continue after post step hook",
+			pauseAfter:       ptr.To(false),
+			wantPhase:        rolloutv1alpha1.RolloutRunPhaseProgressing,
+			wantAutoContinue: true,
+		},
+		{
+			name:             "auto continue after post step hook with pause before",
+			pauseBefore:      []rolloutv1alpha1.CanaryPausePhase{rolloutv1alpha1.CanaryPauseBeforeForkCanary},
+			wantPhase:        rolloutv1alpha1.RolloutRunPhaseProgressing,
+			wantAutoContinue: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rolloutRun := testRolloutRun.DeepCopy()
+			rolloutRun.Spec.Canary = &rolloutv1alpha1.RolloutRunCanaryStrategy{
+				Targets:     unimportantTargets,
+				PauseAfter:  tt.pauseAfter,
+				PauseBefore: tt.pauseBefore,
+			}
+			rolloutRun.Status.Phase = rolloutv1alpha1.RolloutRunPhaseProgressing
+			rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{
+				State: StepPostCanaryStepHook,
+			}
+			ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+
+			e := newCanaryExecutor(newFakeWebhookExecutor())
+			done, _, err := e.doPostStepHook(ctx)
+			assert.True(t, done)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPhase, ctx.NewStatus.Phase)
+			assert.Equal(t, tt.wantAutoContinue, ctx.NewStatus.CanaryStatus.AutoContinue)
+			assert.Equal(t, tt.wantWaiting, ctx.NewStatus.CanaryStatus.WaitingReason)
+		})
+	}
+}
+
+type pendingWebhookExecutor struct{}
+
+func (e *pendingWebhookExecutor) Do(_ *ExecutorContext, _ rolloutv1alpha1.HookType) (bool, time.Duration, error) {
+	return false, retryDefault, nil
+}
+
+func Test_CanaryExecutor_waitingReason(t *testing.T) {
+	rolloutRun := testRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary = &rolloutv1alpha1.RolloutRunCanaryStrategy{
+		Targets: unimportantTargets,
+	}
+	rolloutRun.Status.Phase = rolloutv1alpha1.RolloutRunPhaseProgressing
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{
+		State: StepPreCanaryStepHook,
+	}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+
+	e := newCanaryExecutor(&pendingWebhookExecutor{})
+	done, _, err := e.doPreStepHook(ctx)
+	assert.False(t, done)
+	assert.NoError(t, err)
+	assert.Equal(t, rolloutv1alpha1.StepWaitingWebhook, ctx.NewStatus.CanaryStatus.WaitingReason)
+}
+
+func Test_CanaryPodTemplateMetadataPatch(t *testing.T) {
+	run := &rolloutv1alpha1.RolloutRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo-abcde"},
+		Spec: rolloutv1alpha1.RolloutRunSpec{
+			Canary: &rolloutv1alpha1.RolloutRunCanaryStrategy{
+				PodTemplateMetadataPatch: &rolloutv1alpha1.MetadataPatch{
+					Labels: map[string]string{
+						"foo":                          "bar",
+						rolloutapi.LabelCanary:         "false",
+						rolloutapi.LabelRolloutRunName: "demo-abcde",
+					},
+					Annotations: map[string]string{"foo": "bar"},
+				},
+			},
+		},
+	}
+	got, overridden := canaryPodTemplateMetadataPatch(run, "demo", nil)
+	assert.Equal(t, &rolloutv1alpha1.MetadataPatch{
+		Labels: map[string]string{
+			"foo":                          "bar",
+			rolloutapi.LabelCanary:         "true",
+			rolloutapi.LabelPodRevision:    "canary",
+			rolloutapi.LabelRolloutName:    "demo",
+			rolloutapi.LabelRolloutRunName: "demo-abcde",
+			rolloutapi.LabelRolloutStep:    "canary",
+		},
+		Annotations: map[string]string{"foo": "bar"},
+	}, got)
+	// only labels with different values are overridden
+	assert.Equal(t, []string{rolloutapi.LabelCanary}, overridden)
+	// rolloutRun is not changed
+	assert.Equal(t, map[string]string{
+		"foo":                          "bar",
+		rolloutapi.LabelCanary:         "false",
+		rolloutapi.LabelRolloutRunName: "demo-abcde",
+	}, run.Spec.Canary.PodTemplateMetadataPatch.Labels)
+
+	// no user defined patch
+	got = CanaryPodTemplateMetadataPatch(&rolloutv1alpha1.RolloutRun{ObjectMeta: metav1.ObjectMeta{Name: strings.Repeat("a", 70)}}, "demo", nil)
+	assert.Equal(t, map[string]string{
+		rolloutapi.LabelCanary:         "true",
+		rolloutapi.LabelPodRevision:    "canary",
+		rolloutapi.LabelRolloutName:    "demo",
+		rolloutapi.LabelRolloutRunName: strings.Repeat("a", 63),
+		rolloutapi.LabelRolloutStep:    "canary",
+	}, got.Labels)
+}
+
+func Test_CanaryPodTemplateMetadataPatch_inherited(t *testing.T) {
+	run := &rolloutv1alpha1.RolloutRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				"team":                      "infra",
+				"foo":                       "inherited",
+				"ignored":                   "true",
+				rolloutapi.LabelPodRevision: "stable",
+			},
+			Annotations: map[string]string{
+				"cost-center": "1234",
+			},
+		},
+		Spec: rolloutv1alpha1.RolloutRunSpec{
+			Canary: &rolloutv1alpha1.RolloutRunCanaryStrategy{
+				PodTemplateMetadataPatch: &rolloutv1alpha1.MetadataPatch{
+					Labels: map[string]string{"foo": "bar"},
+				},
+			},
+		},
+	}
+	got := CanaryPodTemplateMetadataPatch(run, "demo", []string{"team", "cost-center", "foo", rolloutapi.LabelPodRevision, "missing"})
+	assert.Equal(t, &rolloutv1alpha1.MetadataPatch{
+		Labels: map[string]string{
+			"team": "infra",
+			// user defined patch overrides inherited metadata
+			"foo": "bar",
+			// builtin labels override inherited metadata
+			rolloutapi.LabelCanary:         "true",
+			rolloutapi.LabelPodRevision:    "canary",
+			rolloutapi.LabelRolloutName:    "demo",
+			rolloutapi.LabelRolloutRunName: "",
+			rolloutapi.LabelRolloutStep:    "canary",
+		},
+		Annotations: map[string]string{"cost-center": "1234"},
+	}, got)
+}
+
+func Test_revertRampWeight(t *testing.T) {
+	assert.Equal(t, int32(20), revertRampWeight(30, 3, 1))
+	assert.Equal(t, int32(10), revertRampWeight(30, 3, 2))
+	assert.Equal(t, int32(0), revertRampWeight(30, 3, 3))
+	assert.Equal(t, int32(25), revertRampWeight(50, 2, 1))
+}
+
+func Test_CanaryExecutor_rampDownCanary(t *testing.T) {
+	rolloutRun := testRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary = &rolloutv1alpha1.RolloutRunCanaryStrategy{
+		Targets: unimportantTargets,
+		Traffic: &rolloutv1alpha1.TrafficStrategy{
+			Weight:     ptr.To[int32](30),
+			RevertRamp: &rolloutv1alpha1.TrafficRevertRamp{Steps: 3},
+		},
+	}
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{
+		State: StepResourceRecycling,
+	}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+
+	e := newCanaryExecutor(newFakeWebhookExecutor())
+	done, retry, err := e.rampDownCanary(ctx)
+	assert.False(t, done)
+	assert.Equal(t, retryImmediately, retry)
+	assert.NoError(t, err)
+	if assert.NotNil(t, ctx.NewStatus.CanaryStatus.RevertRamp) {
+		assert.Equal(t, int32(1), ctx.NewStatus.CanaryStatus.RevertRamp.Step)
+		assert.Equal(t, int32(20), ctx.NewStatus.CanaryStatus.RevertRamp.Weight)
+	}
+
+	// no ramp without weight
+	ctx.RolloutRun.Spec.Canary.Traffic.Weight = nil
+	done, _, err = e.rampDownCanary(ctx)
+	assert.True(t, done)
+	assert.NoError(t, err)
+}
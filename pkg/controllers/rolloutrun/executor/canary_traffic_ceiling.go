@@ -0,0 +1,61 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+const ReasonTrafficWeightClamped = "TrafficWeightClamped"
+
+// weightCeiling returns the maximum canary traffic weight of rolloutRun, zero
+// means unlimited. Blue/green is not clamped, since its cut over switches all
+// traffic to canary and a partial cut over leaves both revisions serving.
+func (e *canaryExecutor) weightCeiling(rolloutRun *rolloutv1alpha1.RolloutRun) int32 {
+	if isBlueGreen(rolloutRun) {
+		return 0
+	}
+	return e.maxTrafficWeight
+}
+
+// recordTrafficCeiling records the canary weight requested by traffic strategy
+// if it exceeds the maximum traffic weight, the traffic manager clamps it when
+// canary traffic is forked. An event is emitted when the clamping starts.
+func (e *canaryExecutor) recordTrafficCeiling(ctx *ExecutorContext) {
+	status := ctx.NewStatus.CanaryStatus
+	traffic := ctx.RolloutRun.Spec.Canary.Traffic
+	ceiling := e.weightCeiling(ctx.RolloutRun)
+	if ceiling <= 0 || traffic == nil || traffic.CanaryWeight() == nil || *traffic.CanaryWeight() <= ceiling {
+		status.TrafficCeiling = nil
+		return
+	}
+
+	requested := *traffic.CanaryWeight()
+	message := fmt.Sprintf("canary traffic weight %d is clamped to the maximum weight %d", requested, ceiling)
+	if status.TrafficCeiling == nil {
+		ctx.Recorder.Event(ctx.RolloutRun, corev1.EventTypeWarning, ReasonTrafficWeightClamped, message)
+	}
+	status.TrafficCeiling = &rolloutv1alpha1.TrafficCeilingStatus{
+		RequestedWeight: requested,
+		MaxWeight:       ceiling,
+		Message:         message,
+	}
+}
@@ -0,0 +1,93 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/traffic"
+)
+
+func Test_TrafficCeiling(t *testing.T) {
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary.Traffic = &rolloutv1alpha1.TrafficStrategy{Weight: ptr.To[int32](50)}
+	target := rolloutv1alpha1.RolloutRunStepTarget{
+		CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-1"},
+	}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun, newFakeObject("cluster-a", "default", "test-1", 10, 0, 0))
+	routing := &rolloutv1alpha1.BackendRouting{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-1-ics", Namespace: "default"},
+		Spec: rolloutv1alpha1.BackendRoutingSpec{
+			TrafficType: rolloutv1alpha1.InClusterTrafficType,
+			Backend: rolloutv1alpha1.CrossClusterObjectReference{
+				ObjectTypeRef:                   rolloutv1alpha1.ObjectTypeRef{APIVersion: "v1", Kind: "Service"},
+				CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-svc"},
+			},
+		},
+	}
+	assert.NoError(t, ctx.Client.Create(ctx, routing))
+	topology := rolloutv1alpha1.TrafficTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Status: rolloutv1alpha1.TrafficTopologyStatus{
+			Topologies: []rolloutv1alpha1.TopologyInfo{{WorkloadRef: target.CrossClusterObjectNameReference, BackendRoutingName: routing.Name}},
+		},
+	}
+	m, err := traffic.NewManager(ctx.Client, newTestLogger(), []rolloutv1alpha1.TrafficTopology{topology})
+	assert.NoError(t, err)
+	m.With(newTestLogger(), []rolloutv1alpha1.RolloutRunStepTarget{target}, rolloutRun.Spec.Canary.Traffic)
+	m.WithMaxWeight(20)
+	ctx.TrafficManager = m
+	getForwarding := func() *rolloutv1alpha1.BackendForwarding {
+		assert.NoError(t, ctx.Client.Get(ctx, client.ObjectKeyFromObject(routing), routing))
+		return routing.Spec.Forwarding
+	}
+
+	// the weight is clamped when canary traffic is forked
+	_, err = m.ForkStable()
+	assert.NoError(t, err)
+	_, err = m.ForkCanary()
+	assert.NoError(t, err)
+	assert.Equal(t, ptr.To[int32](20), getForwarding().Canary.Weight)
+	assert.Equal(t, ptr.To[int32](50), rolloutRun.Spec.Canary.Traffic.Weight)
+
+	_, err = m.SetCanaryWeight(30)
+	assert.NoError(t, err)
+	assert.Equal(t, ptr.To[int32](20), getForwarding().Canary.Weight)
+	_, err = m.SetCanaryWeight(10)
+	assert.NoError(t, err)
+	assert.Equal(t, ptr.To[int32](10), getForwarding().Canary.Weight)
+
+	// the clamping is recorded in status
+	e := newCanaryExecutor(newWebhookExecutor(0))
+	e.maxTrafficWeight = 20
+	e.recordTrafficCeiling(ctx)
+	if assert.NotNil(t, ctx.NewStatus.CanaryStatus.TrafficCeiling) {
+		assert.Equal(t, int32(50), ctx.NewStatus.CanaryStatus.TrafficCeiling.RequestedWeight)
+		assert.Equal(t, int32(20), ctx.NewStatus.CanaryStatus.TrafficCeiling.MaxWeight)
+	}
+
+	// the note is cleared if the weight is within the ceiling
+	e.maxTrafficWeight = 60
+	e.recordTrafficCeiling(ctx)
+	assert.Nil(t, ctx.NewStatus.CanaryStatus.TrafficCeiling)
+}
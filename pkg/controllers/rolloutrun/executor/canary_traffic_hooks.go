@@ -0,0 +1,68 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"time"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+// doTrafficReadyHook runs CanaryTrafficReadyHook webhooks once canary traffic
+// is forked, i.e. the route is applied, backends are ready and the change is
+// verified. Canary is not analyzed until they are finished, so the canary
+// cohort is measured with what the webhooks enabled.
+func (e *canaryExecutor) doTrafficReadyHook(ctx *ExecutorContext) (bool, time.Duration, error) {
+	status := ctx.NewStatus.CanaryStatus
+	if status.TrafficHooks == nil {
+		// the revert hook is owed from now on, even if the ready hook fails
+		status.TrafficHooks = &rolloutv1alpha1.CanaryTrafficHooksStatus{}
+	}
+	if status.TrafficHooks.ReadyNotified {
+		return true, retryImmediately, nil
+	}
+	done, retry, err := e.doWebhook(ctx, rolloutv1alpha1.CanaryTrafficReadyHook)
+	if !done {
+		return false, retry, err
+	}
+	status.TrafficHooks.ReadyNotified = true
+	return true, retryImmediately, nil
+}
+
+// doTrafficRevertHook runs CanaryTrafficRevertHook webhooks in recycle before
+// canary traffic is touched, it is skipped if the ready hook was never started.
+func (e *canaryExecutor) doTrafficRevertHook(ctx *ExecutorContext) (bool, time.Duration, error) {
+	status := ctx.NewStatus.CanaryStatus
+	if status.TrafficHooks == nil || status.TrafficHooks.RevertNotified {
+		return true, retryImmediately, nil
+	}
+	done, retry, err := e.doWebhook(ctx, rolloutv1alpha1.CanaryTrafficRevertHook)
+	if !done {
+		return false, retry, err
+	}
+	status.TrafficHooks.RevertNotified = true
+	return true, retryImmediately, nil
+}
+
+// isTrafficReadyHookStarted returns true if the latest webhook of canary is a
+// CanaryTrafficReadyHook one. Blue/green canary runs the ready hook after the
+// post canary step hook, which must not be started over while the ready hook
+// is running.
+func isTrafficReadyHookStarted(ctx *ExecutorContext) bool {
+	webhooks := ctx.NewStatus.CanaryStatus.Webhooks
+	return len(webhooks) > 0 && webhooks[len(webhooks)-1].HookType == rolloutv1alpha1.CanaryTrafficReadyHook
+}
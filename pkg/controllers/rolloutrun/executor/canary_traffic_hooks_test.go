@@ -0,0 +1,166 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/traffic"
+)
+
+// trafficHookRecorder records each webhook call with whether canary traffic
+// is forked at that moment.
+type trafficHookRecorder struct {
+	calls   []string
+	running map[rolloutv1alpha1.HookType]bool
+}
+
+func (r *trafficHookRecorder) Do(ctx *ExecutorContext, hookType rolloutv1alpha1.HookType) (bool, time.Duration, error) {
+	forked := false
+	for _, routing := range ctx.TrafficManager.Routings() {
+		if routing.Spec.Forwarding != nil && len(routing.Spec.Forwarding.Canary.Name) > 0 {
+			forked = true
+		}
+	}
+	r.calls = append(r.calls, fmt.Sprintf("%s forked=%v", hookType, forked))
+	if r.running[hookType] {
+		ctx.SetWebhookStatus(rolloutv1alpha1.RolloutWebhookStatus{
+			HookType: hookType,
+			Name:     "feature-flags",
+			State:    rolloutv1alpha1.WebhookRunning,
+		})
+		return false, retryDefault, nil
+	}
+	return true, retryImmediately, nil
+}
+
+func Test_CanaryExecutor_trafficHooks(t *testing.T) {
+	target := rolloutv1alpha1.RolloutRunStepTarget{
+		CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-1"},
+	}
+	rolloutRun := testRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary = &rolloutv1alpha1.RolloutRunCanaryStrategy{
+		Strategy: rolloutv1alpha1.CanaryStrategyBlueGreen,
+		Targets:  []rolloutv1alpha1.RolloutRunStepTarget{target},
+		Traffic:  &rolloutv1alpha1.TrafficStrategy{Weight: ptr.To[int32](100)},
+	}
+	rolloutRun.Status.Phase = rolloutv1alpha1.RolloutRunPhaseProgressing
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{
+		State: StepPostCanaryStepHook,
+	}
+	// stable is already promoted, so canary traffic is reverted in recycle
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun, newFakeObject("cluster-a", "default", "test-1", 10, 10, 10))
+
+	routing := &rolloutv1alpha1.BackendRouting{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-1-ics", Namespace: "default"},
+		Spec: rolloutv1alpha1.BackendRoutingSpec{
+			TrafficType: rolloutv1alpha1.InClusterTrafficType,
+			Backend: rolloutv1alpha1.CrossClusterObjectReference{
+				ObjectTypeRef:                   rolloutv1alpha1.ObjectTypeRef{APIVersion: "v1", Kind: "Service"},
+				CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-svc"},
+			},
+			Forwarding: &rolloutv1alpha1.BackendForwarding{
+				Stable: rolloutv1alpha1.StableBackendRule{Name: "test-svc-stable"},
+			},
+		},
+	}
+	assert.NoError(t, ctx.Client.Create(ctx, routing))
+	topology := rolloutv1alpha1.TrafficTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Status: rolloutv1alpha1.TrafficTopologyStatus{
+			Topologies: []rolloutv1alpha1.TopologyInfo{{WorkloadRef: target.CrossClusterObjectNameReference, BackendRoutingName: routing.Name}},
+		},
+	}
+	m, err := traffic.NewManager(ctx.Client, newTestLogger(), []rolloutv1alpha1.TrafficTopology{topology})
+	assert.NoError(t, err)
+	m.With(newTestLogger(), rolloutRun.Spec.Canary.Targets, rolloutRun.Spec.Canary.Traffic)
+	ctx.TrafficManager = m
+	markRoutingsReady := func() {
+		for _, r := range m.Routings() {
+			r.Status.ObservedGeneration = r.Generation
+			r.Status.Phase = rolloutv1alpha1.Ready
+		}
+	}
+
+	webhook := &trafficHookRecorder{running: map[rolloutv1alpha1.HookType]bool{rolloutv1alpha1.CanaryTrafficReadyHook: true}}
+	e := newCanaryExecutor(webhook)
+
+	// ready hook is not called until forked traffic is ready
+	done, _, err := e.doPostStepHook(ctx)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, []string{"PostCanaryStepHook forked=false"}, webhook.calls)
+	assert.Nil(t, ctx.NewStatus.CanaryStatus.TrafficHooks)
+
+	markRoutingsReady()
+	done, _, err = e.doPostStepHook(ctx)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, "CanaryTrafficReadyHook forked=true", webhook.calls[len(webhook.calls)-1])
+	assert.Equal(t, rolloutv1alpha1.StepWaitingWebhook, ctx.NewStatus.CanaryStatus.WaitingReason)
+
+	// post step hook is not started over while the ready hook is running
+	webhook.calls = nil
+	webhook.running = nil
+	done, _, err = e.doPostStepHook(ctx)
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, []string{"CanaryTrafficReadyHook forked=true"}, webhook.calls)
+	assert.True(t, ctx.NewStatus.CanaryStatus.TrafficHooks.ReadyNotified)
+
+	// revert hook is called before canary traffic is reverted, and only once
+	ctx.MoveToNextState(StepResourceRecycling)
+	ctx.NewStatus.Phase = rolloutv1alpha1.RolloutRunPhaseProgressing
+	webhook.calls = nil
+	done, _, err = e.doRecycle(ctx)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, []string{"CanaryTrafficRevertHook forked=true"}, webhook.calls)
+	assert.True(t, ctx.NewStatus.CanaryStatus.TrafficHooks.RevertNotified)
+	assert.Empty(t, m.Routings()[0].Spec.Forwarding.Canary.Name)
+
+	_, _, err = e.doRecycle(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"CanaryTrafficRevertHook forked=true"}, webhook.calls)
+}
+
+func Test_CanaryExecutor_doTrafficRevertHook_notReady(t *testing.T) {
+	webhook := &trafficHookRecorder{}
+	e := newCanaryExecutor(webhook)
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{State: StepResourceRecycling}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+	ctx.Initialize()
+
+	// canary traffic never forked, nothing to revert
+	done, _, err := e.doTrafficRevertHook(ctx)
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Empty(t, webhook.calls)
+
+	// restarted step runs the ready hook again but still owes the revert hook
+	ctx.NewStatus.CanaryStatus.TrafficHooks = &rolloutv1alpha1.CanaryTrafficHooksStatus{ReadyNotified: true}
+	resetStepProgress(ctx.NewStatus.CanaryStatus)
+	assert.Equal(t, &rolloutv1alpha1.CanaryTrafficHooksStatus{}, ctx.NewStatus.CanaryStatus.TrafficHooks)
+}
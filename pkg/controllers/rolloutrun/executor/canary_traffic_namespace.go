@@ -0,0 +1,117 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	rolloutapi "kusionstack.io/rollout/apis/rollout"
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+)
+
+const (
+	ReasonTrafficNamespaceForbidden = "TrafficNamespaceForbidden"
+	ReasonCanaryNamespaceUnroutable = "CanaryNamespaceUnroutable"
+)
+
+// checkTrafficNamespaces checks the namespaces of backends and routes referenced
+// across namespaces by the backend routings of canary allow the namespace of
+// routing, and the controller is permitted to fork them, so that canary fails
+// clearly instead of waiting for the routings forever.
+func checkTrafficNamespaces(ctx *ExecutorContext) error {
+	for _, routing := range ctx.TrafficManager.Routings() {
+		// backends are forked to stable and canary ones, routes are changed to them
+		refs := map[string][]rolloutv1alpha1.CrossClusterObjectReference{
+			"create": {routing.Spec.Backend},
+			"update": routing.Spec.Routes,
+		}
+		for _, verb := range []string{"create", "update"} {
+			for _, ref := range refs[verb] {
+				namespace := ref.NamespaceOr(routing.Namespace)
+				if namespace == routing.Namespace {
+					continue
+				}
+				allowed, err := allowsCrossNamespaceFrom(ctx, ref.Cluster, namespace, routing.Namespace)
+				if err != nil {
+					return err
+				}
+				if !allowed {
+					return control.TerminalError(newDoCanaryError(
+						ReasonTrafficNamespaceForbidden,
+						fmt.Sprintf("%s %s in namespace %s referenced by BackendRouting %s/%s is not allowed in cluster %q, namespace %s must allow namespace %s by annotation %s",
+							ref.Kind, ref.Name, namespace, routing.Namespace, routing.Name, ref.Cluster, namespace, routing.Namespace, rolloutapi.AnnoCrossNamespaceAllowedFrom),
+					))
+				}
+				review, err := reviewAccess(ctx, ref.Cluster, namespace, schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind), verb)
+				if err != nil {
+					return err
+				}
+				if !review.Status.Allowed {
+					return control.TerminalError(newDoCanaryError(
+						ReasonTrafficNamespaceForbidden,
+						fmt.Sprintf("%s %s %s in namespace %s referenced by BackendRouting %s/%s is forbidden in cluster %q, reason: %s",
+							verb, ref.Kind, ref.Name, namespace, routing.Namespace, routing.Name, ref.Cluster, review.Status.Reason),
+					))
+				}
+			}
+		}
+	}
+	return checkCanaryTrafficNamespaces(ctx)
+}
+
+// checkCanaryTrafficNamespaces checks the canary backends can be created in
+// the canary namespace of each target and its routes can reference them
+// across namespaces, since the canary backends must select the canary pods
+// there.
+func checkCanaryTrafficNamespaces(ctx *ExecutorContext) error {
+	for _, target := range ctx.RolloutRun.Spec.Canary.Targets {
+		namespace := target.CanaryNamespace
+		if len(namespace) == 0 {
+			continue
+		}
+		for _, routing := range ctx.TrafficManager.TargetRoutings(target.CrossClusterObjectNameReference) {
+			backend := routing.Spec.Backend
+			if namespace == backend.NamespaceOr(routing.Namespace) {
+				continue
+			}
+			for _, ref := range routing.Spec.Routes {
+				if ref.Kind == "Ingress" {
+					return control.TerminalError(newDoCanaryError(
+						ReasonCanaryNamespaceUnroutable,
+						fmt.Sprintf("Ingress %s referenced by BackendRouting %s/%s can not route to canary backend in namespace %s",
+							ref.Name, routing.Namespace, routing.Name, namespace),
+					))
+				}
+			}
+			review, err := reviewAccess(ctx, backend.Cluster, namespace, schema.FromAPIVersionAndKind(backend.APIVersion, backend.Kind), "create")
+			if err != nil {
+				return err
+			}
+			if !review.Status.Allowed {
+				return control.TerminalError(newDoCanaryError(
+					ReasonTrafficNamespaceForbidden,
+					fmt.Sprintf("create canary %s of %s in canary namespace %s of target %s is forbidden in cluster %q, reason: %s",
+						backend.Kind, backend.Name, namespace, target.CrossClusterObjectNameReference, backend.Cluster, review.Status.Reason),
+				))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rolloutapi "kusionstack.io/rollout/apis/rollout"
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/traffic"
+)
+
+// accessReviewClient answers SelfSubjectAccessReview by allowed namespaces,
+// and overrides the RESTMapper of fake client which only knows the kinds of
+// initial objects.
+type accessReviewClient struct {
+	client.Client
+	mapper  meta.RESTMapper
+	allowed sets.String
+}
+
+func (c *accessReviewClient) RESTMapper() meta.RESTMapper {
+	return c.mapper
+}
+
+func (c *accessReviewClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	review, ok := obj.(*authorizationv1.SelfSubjectAccessReview)
+	if !ok {
+		return c.Client.Create(ctx, obj, opts...)
+	}
+	review.Status.Allowed = c.allowed.Has(review.Spec.ResourceAttributes.Namespace)
+	if !review.Status.Allowed {
+		review.Status.Reason = "forbidden by test"
+	}
+	return nil
+}
+
+func Test_checkTrafficNamespaces(t *testing.T) {
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	target := rolloutv1alpha1.RolloutRunStepTarget{
+		CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-1"},
+	}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun, newFakeObject("cluster-a", "default", "test-1", 10, 0, 0))
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Service"), meta.RESTScopeNamespace)
+	mapper.Add(networkingv1.SchemeGroupVersion.WithKind("Ingress"), meta.RESTScopeNamespace)
+	ctx.Client = &accessReviewClient{Client: ctx.Client, mapper: mapper, allowed: sets.NewString("gateway")}
+
+	routing := &rolloutv1alpha1.BackendRouting{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-1-ics", Namespace: "default"},
+		Spec: rolloutv1alpha1.BackendRoutingSpec{
+			TrafficType: rolloutv1alpha1.InClusterTrafficType,
+			Backend: rolloutv1alpha1.CrossClusterObjectReference{
+				ObjectTypeRef:                   rolloutv1alpha1.ObjectTypeRef{APIVersion: "v1", Kind: "Service"},
+				CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-svc"},
+			},
+			Routes: []rolloutv1alpha1.CrossClusterObjectReference{{
+				ObjectTypeRef:                   rolloutv1alpha1.ObjectTypeRef{APIVersion: "networking.k8s.io/v1", Kind: "Ingress"},
+				CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-ingress"},
+			}},
+		},
+	}
+	assert.NoError(t, ctx.Client.Create(ctx, routing))
+	topology := rolloutv1alpha1.TrafficTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Status: rolloutv1alpha1.TrafficTopologyStatus{
+			Topologies: []rolloutv1alpha1.TopologyInfo{{WorkloadRef: target.CrossClusterObjectNameReference, BackendRoutingName: routing.Name}},
+		},
+	}
+	newTrafficManager := func() *traffic.Manager {
+		m, err := traffic.NewManager(ctx.Client, newTestLogger(), []rolloutv1alpha1.TrafficTopology{topology})
+		assert.NoError(t, err)
+		m.With(newTestLogger(), []rolloutv1alpha1.RolloutRunStepTarget{target}, rolloutRun.Spec.Canary.Traffic)
+		return m
+	}
+
+	// backend and routes in the namespace of routing are not checked
+	ctx.TrafficManager = newTrafficManager()
+	assert.NoError(t, checkTrafficNamespaces(ctx))
+
+	// backend in the gateway namespace not allowing default is rejected even
+	// if the controller is permitted
+	routing.Spec.Backend.Namespace = "gateway"
+	assert.NoError(t, ctx.Client.Update(ctx, routing))
+	ctx.TrafficManager = newTrafficManager()
+	err := checkTrafficNamespaces(ctx)
+	assert.True(t, errors.Is(err, control.TerminalError(nil)))
+	assert.ErrorContains(t, err, ReasonTrafficNamespaceForbidden)
+	assert.ErrorContains(t, err, rolloutapi.AnnoCrossNamespaceAllowedFrom)
+
+	gateway := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "gateway",
+		Annotations: map[string]string{rolloutapi.AnnoCrossNamespaceAllowedFrom: "other"},
+	}}
+	assert.NoError(t, ctx.Client.Create(ctx, gateway))
+	err = checkTrafficNamespaces(ctx)
+	assert.ErrorContains(t, err, rolloutapi.AnnoCrossNamespaceAllowedFrom)
+
+	// backend in the gateway namespace allowing default is permitted
+	gateway.Annotations[rolloutapi.AnnoCrossNamespaceAllowedFrom] = "other, default"
+	assert.NoError(t, ctx.Client.Update(ctx, gateway))
+	assert.NoError(t, checkTrafficNamespaces(ctx))
+
+	// route in the ingress namespace allowing all namespaces is forbidden to controller
+	assert.NoError(t, ctx.Client.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "ingress",
+		Annotations: map[string]string{rolloutapi.AnnoCrossNamespaceAllowedFrom: "*"},
+	}}))
+	routing.Spec.Routes[0].Namespace = "ingress"
+	assert.NoError(t, ctx.Client.Update(ctx, routing))
+	ctx.TrafficManager = newTrafficManager()
+	err = checkTrafficNamespaces(ctx)
+	assert.True(t, errors.Is(err, control.TerminalError(nil)))
+	assert.ErrorContains(t, err, ReasonTrafficNamespaceForbidden)
+	assert.ErrorContains(t, err, "namespace ingress")
+
+	// canary backend in canary namespace can not be routed by ingress
+	routing.Spec.Backend.Namespace = ""
+	routing.Spec.Routes[0].Namespace = ""
+	assert.NoError(t, ctx.Client.Update(ctx, routing))
+	canaryTarget := target
+	canaryTarget.CanaryNamespace = "canary"
+	ctx.RolloutRun.Spec.Canary.Targets = []rolloutv1alpha1.RolloutRunStepTarget{canaryTarget}
+	ctx.TrafficManager = newTrafficManager()
+	err = checkTrafficNamespaces(ctx)
+	assert.True(t, errors.Is(err, control.TerminalError(nil)))
+	assert.ErrorContains(t, err, ReasonCanaryNamespaceUnroutable)
+
+	// canary backend in canary namespace is forbidden
+	routing.Spec.Routes[0].ObjectTypeRef = rolloutv1alpha1.ObjectTypeRef{APIVersion: "gateway.networking.k8s.io/v1alpha2", Kind: "GRPCRoute"}
+	assert.NoError(t, ctx.Client.Update(ctx, routing))
+	ctx.TrafficManager = newTrafficManager()
+	err = checkTrafficNamespaces(ctx)
+	assert.True(t, errors.Is(err, control.TerminalError(nil)))
+	assert.ErrorContains(t, err, ReasonTrafficNamespaceForbidden)
+	assert.ErrorContains(t, err, "canary namespace canary")
+
+	ctx.Client.(*accessReviewClient).allowed.Insert("canary")
+	assert.NoError(t, checkTrafficNamespaces(ctx))
+}
@@ -0,0 +1,67 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"kusionstack.io/kube-utils/multicluster/clusterinfo"
+
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+)
+
+const ReasonTrafficPortNotFound = "TrafficPortNotFound"
+
+// checkTrafficPorts checks the canary ports exist in the Service backend of
+// each routing, otherwise the traffic of them can never be split to canary.
+func checkTrafficPorts(ctx *ExecutorContext) error {
+	ports := ctx.RolloutRun.Spec.Canary.Traffic.Ports
+	if len(ports) == 0 {
+		return nil
+	}
+	for _, routing := range ctx.TrafficManager.Routings() {
+		backend := routing.Spec.Backend
+		if backend.Kind != "Service" || backend.APIVersion != corev1.SchemeGroupVersion.String() {
+			return control.TerminalError(newDoCanaryError(
+				ReasonTrafficPortNotFound,
+				fmt.Sprintf("canary ports are only supported by Service backend, backend of BackendRouting %s/%s is %s %s",
+					routing.Namespace, routing.Name, backend.Kind, backend.Name),
+			))
+		}
+		namespace := backend.Namespace
+		if len(namespace) == 0 {
+			namespace = routing.Namespace
+		}
+
+		svc := &corev1.Service{}
+		if err := ctx.Client.Get(clusterinfo.WithCluster(ctx, backend.Cluster), types.NamespacedName{Namespace: namespace, Name: backend.Name}, svc); err != nil {
+			return err
+		}
+		for _, name := range ports {
+			if !lo.ContainsBy(svc.Spec.Ports, func(port corev1.ServicePort) bool { return port.Name == name }) {
+				return control.TerminalError(newDoCanaryError(
+					ReasonTrafficPortNotFound,
+					fmt.Sprintf("canary port %q is not found in Service %s/%s of BackendRouting %s", name, namespace, backend.Name, routing.Name),
+				))
+			}
+		}
+	}
+	return nil
+}
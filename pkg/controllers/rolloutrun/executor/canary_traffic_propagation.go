@@ -0,0 +1,161 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/jsonpath"
+	"k8s.io/utils/ptr"
+	"kusionstack.io/kube-utils/multicluster/clusterinfo"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+)
+
+const defaultTrafficPropagationTimeoutSeconds = 60
+
+// verifyTrafficPropagation polls the propagation checks on routes after the
+// traffic change of op, until all of them pass or any of them times out.
+func (e *canaryExecutor) verifyTrafficPropagation(ctx *ExecutorContext, op string) (bool, time.Duration, error) {
+	traffic := ctx.RolloutRun.Spec.Canary.Traffic
+	if traffic == nil || len(traffic.PropagationChecks) == 0 {
+		return true, retryImmediately, nil
+	}
+	logger := ctx.GetCanaryLogger()
+
+	verification := trafficVerification(ctx.NewStatus.CanaryStatus, rolloutv1alpha1.TrafficVerificationPropagation, op)
+	if verification.Verified {
+		return true, retryImmediately, nil
+	}
+
+	for _, check := range traffic.PropagationChecks {
+		propagated, message, err := checkRoutePropagation(ctx, check)
+		if err != nil {
+			return false, retryStop, err
+		}
+		if propagated {
+			continue
+		}
+		verification.Message = message
+
+		timeout := time.Duration(defaultTrafficPropagationTimeoutSeconds) * time.Second
+		if check.TimeoutSeconds > 0 {
+			timeout = time.Duration(check.TimeoutSeconds) * time.Second
+		}
+		if time.Since(verification.StartTime.Time) > timeout {
+			// restart the verification so that a manual retry starts a new timeout
+			verification.StartTime = ptr.To(metav1.Now())
+			return false, retryStop, control.TerminalError(newDoCanaryError(
+				"TrafficPropagationTimeout",
+				fmt.Sprintf("traffic change %s is not propagated within %v: %s", op, timeout, message),
+			))
+		}
+		logger.Info("traffic change is not propagated yet, check later", "operation", op, "message", message)
+		return false, retryDefault, nil
+	}
+
+	verification.Verified = true
+	verification.Message = "traffic change is propagated"
+	return true, retryImmediately, nil
+}
+
+// checkRoutePropagation returns true if the field of all routes of the kind of
+// check has the expected value, otherwise a message describing the first route
+// not propagated.
+func checkRoutePropagation(ctx *ExecutorContext, check rolloutv1alpha1.TrafficPropagationCheck) (bool, string, error) {
+	parser := jsonpath.New(check.Kind).AllowMissingKeys(true)
+	if err := parser.Parse(check.FieldPath); err != nil {
+		return false, "", control.TerminalError(newDoCanaryError(
+			"InvalidTrafficPropagationCheck",
+			fmt.Sprintf("invalid field path %s of %s propagation check: %v", check.FieldPath, check.Kind, err),
+		))
+	}
+
+	for _, routing := range ctx.TrafficManager.Routings() {
+		for _, route := range routing.Spec.Routes {
+			if route.Kind != check.Kind || (len(check.APIVersion) > 0 && route.APIVersion != check.APIVersion) {
+				continue
+			}
+			namespace := route.Namespace
+			if len(namespace) == 0 {
+				namespace = routing.Namespace
+			}
+			obj := &unstructured.Unstructured{}
+			obj.SetAPIVersion(route.APIVersion)
+			obj.SetKind(route.Kind)
+			key := types.NamespacedName{Namespace: namespace, Name: route.Name}
+			if err := ctx.Client.Get(clusterinfo.WithCluster(ctx, route.Cluster), key, obj); err != nil {
+				return false, fmt.Sprintf("failed to get %s %s in cluster %q: %v", route.Kind, key, route.Cluster, err), nil
+			}
+
+			value, err := jsonPathValue(parser, obj.Object)
+			if err != nil {
+				return false, fmt.Sprintf("failed to read %s of %s %s: %v", check.FieldPath, route.Kind, key, err), nil
+			}
+			if (len(check.Value) == 0 && len(value) == 0) || (len(check.Value) > 0 && value != check.Value) {
+				return false, fmt.Sprintf("%s of %s %s is %q, expected %q", check.FieldPath, route.Kind, key, value, check.Value), nil
+			}
+		}
+	}
+	return true, "", nil
+}
+
+func jsonPathValue(parser *jsonpath.JSONPath, obj map[string]interface{}) (string, error) {
+	results, err := parser.FindResults(obj)
+	if err != nil {
+		return "", err
+	}
+	values := make([]string, 0)
+	for _, result := range results {
+		for _, v := range result {
+			values = append(values, fmt.Sprint(v.Interface()))
+		}
+	}
+	return strings.Join(values, " "), nil
+}
+
+// trafficVerification returns the verification of traffic change op by method,
+// a new one is started if not found.
+func trafficVerification(status *rolloutv1alpha1.RolloutRunStepStatus, method rolloutv1alpha1.TrafficVerificationMethod, op string) *rolloutv1alpha1.TrafficVerificationStatus {
+	_, index, found := lo.FindIndexOf(status.TrafficVerifications, func(v rolloutv1alpha1.TrafficVerificationStatus) bool {
+		return v.Method == method && v.Operation == op
+	})
+	if !found {
+		status.TrafficVerifications = append(status.TrafficVerifications, rolloutv1alpha1.TrafficVerificationStatus{
+			Method:    method,
+			Operation: op,
+			StartTime: ptr.To(metav1.Now()),
+		})
+		index = len(status.TrafficVerifications) - 1
+	}
+	return &status.TrafficVerifications[index]
+}
+
+// resetTrafficVerifications drops the verifications of op, since the traffic is
+// changed again and must be verified from the start.
+func resetTrafficVerifications(status *rolloutv1alpha1.RolloutRunStepStatus, op string) {
+	status.TrafficVerifications = lo.Filter(status.TrafficVerifications, func(v rolloutv1alpha1.TrafficVerificationStatus, _ int) bool {
+		return v.Operation != op
+	})
+}
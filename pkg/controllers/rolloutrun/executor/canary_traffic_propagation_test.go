@@ -0,0 +1,111 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/traffic"
+)
+
+func Test_verifyTrafficPropagation(t *testing.T) {
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary.Traffic = &rolloutv1alpha1.TrafficStrategy{
+		PropagationChecks: []rolloutv1alpha1.TrafficPropagationCheck{{
+			ObjectTypeRef:  rolloutv1alpha1.ObjectTypeRef{APIVersion: "networking.k8s.io/v1", Kind: "Ingress"},
+			FieldPath:      "{.status.loadBalancer.ingress[0].ip}",
+			Value:          "10.0.0.1",
+			TimeoutSeconds: 60,
+		}},
+	}
+	target := rolloutv1alpha1.RolloutRunStepTarget{
+		CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-1"},
+	}
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ingress", Namespace: "default"},
+	}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun, newFakeObject("cluster-a", "default", "test-1", 10, 0, 0))
+	assert.NoError(t, ctx.Client.Create(ctx, ingress))
+	ctx.NewStatus.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{}
+
+	routing := &rolloutv1alpha1.BackendRouting{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-1-ics", Namespace: "default"},
+		Spec: rolloutv1alpha1.BackendRoutingSpec{
+			TrafficType: rolloutv1alpha1.InClusterTrafficType,
+			Backend: rolloutv1alpha1.CrossClusterObjectReference{
+				ObjectTypeRef:                   rolloutv1alpha1.ObjectTypeRef{APIVersion: "v1", Kind: "Service"},
+				CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-svc"},
+			},
+			Routes: []rolloutv1alpha1.CrossClusterObjectReference{{
+				ObjectTypeRef:                   rolloutv1alpha1.ObjectTypeRef{APIVersion: "networking.k8s.io/v1", Kind: "Ingress"},
+				CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-ingress"},
+			}},
+		},
+	}
+	assert.NoError(t, ctx.Client.Create(ctx, routing))
+	topology := rolloutv1alpha1.TrafficTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Status: rolloutv1alpha1.TrafficTopologyStatus{
+			Topologies: []rolloutv1alpha1.TopologyInfo{{WorkloadRef: target.CrossClusterObjectNameReference, BackendRoutingName: routing.Name}},
+		},
+	}
+	m, err := traffic.NewManager(ctx.Client, newTestLogger(), []rolloutv1alpha1.TrafficTopology{topology})
+	assert.NoError(t, err)
+	m.With(newTestLogger(), []rolloutv1alpha1.RolloutRunStepTarget{target}, rolloutRun.Spec.Canary.Traffic)
+	ctx.TrafficManager = m
+
+	e := &canaryExecutor{}
+
+	// ingress has no address yet
+	done, retry, err := e.verifyTrafficPropagation(ctx, "forkCanary")
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, retryDefault, retry)
+	assert.Len(t, ctx.NewStatus.CanaryStatus.TrafficVerifications, 1)
+	verification := ctx.NewStatus.CanaryStatus.TrafficVerifications[0]
+	assert.Equal(t, rolloutv1alpha1.TrafficVerificationPropagation, verification.Method)
+	assert.False(t, verification.Verified)
+	assert.Contains(t, verification.Message, `expected "10.0.0.1"`)
+
+	// timeout
+	ctx.NewStatus.CanaryStatus.TrafficVerifications[0].StartTime = ptr.To(metav1.NewTime(time.Now().Add(-2 * time.Minute)))
+	_, _, err = e.verifyTrafficPropagation(ctx, "forkCanary")
+	assert.True(t, errors.Is(err, control.TerminalError(nil)))
+	assert.ErrorContains(t, err, "TrafficPropagationTimeout")
+
+	// ingress is assigned the address
+	ingress.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}
+	assert.NoError(t, ctx.Client.Status().Update(ctx, ingress))
+	done, _, err = e.verifyTrafficPropagation(ctx, "forkCanary")
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.True(t, ctx.NewStatus.CanaryStatus.TrafficVerifications[0].Verified)
+
+	// traffic is changed again
+	resetTrafficVerifications(ctx.NewStatus.CanaryStatus, "forkCanary")
+	assert.Empty(t, ctx.NewStatus.CanaryStatus.TrafficVerifications)
+}
@@ -0,0 +1,52 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+	"kusionstack.io/rollout/pkg/route/grpcroute"
+)
+
+const ReasonUnsupportedProtocol = "UnsupportedProtocol"
+
+// checkTrafficProtocol checks the backend of each routing has a gRPC route if
+// canary traffic is split by gRPC rule, otherwise the rule is ignored by HTTP
+// routes and canary receives no traffic.
+func checkTrafficProtocol(ctx *ExecutorContext) error {
+	if ctx.RolloutRun.Spec.Canary.Traffic.GRPCRule == nil {
+		return nil
+	}
+	for _, routing := range ctx.TrafficManager.Routings() {
+		hasGRPCRoute := lo.ContainsBy(routing.Spec.Routes, func(ref rolloutv1alpha1.CrossClusterObjectReference) bool {
+			return schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind).GroupKind() == grpcroute.GVK.GroupKind()
+		})
+		if !hasGRPCRoute {
+			return control.TerminalError(newDoCanaryError(
+				ReasonUnsupportedProtocol,
+				fmt.Sprintf("canary grpc rule is not supported by backend %s of BackendRouting %s/%s, it has no GRPCRoute",
+					routing.Spec.Backend.Name, routing.Namespace, routing.Name),
+			))
+		}
+	}
+	return nil
+}
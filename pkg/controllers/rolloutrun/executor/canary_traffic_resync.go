@@ -0,0 +1,67 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+const ReasonTrafficDrift = "TrafficDrift"
+
+// shouldResyncTraffic returns true if canary traffic is forked and not yet
+// reverted, in which the forked rules are expected to stay in place.
+func (e *canaryExecutor) shouldResyncTraffic(ctx *ExecutorContext) bool {
+	if ctx.Retry.TrafficResync <= 0 || ctx.RolloutRun.Spec.Canary == nil || ctx.RolloutRun.Spec.Canary.Traffic == nil {
+		return false
+	}
+	switch ctx.NewStatus.CanaryStatus.State {
+	case StepPostCanaryStepHook:
+		return true
+	case StepResourceRecycling:
+		// recycling modifies canary traffic, it does not start until resumed,
+		// and the traffic of retained canary is already reverted
+		return ctx.NewStatus.Phase == rolloutv1alpha1.RolloutRunPhasePaused && ctx.NewStatus.CanaryStatus.Retention == nil
+	}
+	return false
+}
+
+// resyncTraffic re-forks canary traffic if the forked rules are modified or
+// deleted by others.
+func (e *canaryExecutor) resyncTraffic(ctx *ExecutorContext) error {
+	if !e.shouldResyncTraffic(ctx) {
+		return nil
+	}
+	logger := ctx.GetCanaryLogger()
+
+	result, err := ctx.TrafficManager.ResyncCanary()
+	if err != nil {
+		logger.Error(err, "failed to resync canary traffic")
+		return err
+	}
+	if result != controllerutil.OperationResultNone {
+		logger.Info("canary traffic rules drifted, re-forked them")
+		ctx.Recorder.Eventf(ctx.RolloutRun, corev1.EventTypeWarning, ReasonTrafficDrift, "canary traffic rules drifted and are restored")
+		return nil
+	}
+	if !ctx.TrafficManager.CheckReady() {
+		logger.Info("canary traffic rules are not ready, waiting for BackendRouting to sync")
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+func Test_CanaryExecutor_shouldResyncTraffic(t *testing.T) {
+	tests := []struct {
+		name  string
+		phase rolloutv1alpha1.RolloutRunPhase
+		state rolloutv1alpha1.RolloutStepState
+		want  bool
+	}{
+		{
+			name:  "canary is running",
+			phase: rolloutv1alpha1.RolloutRunPhaseProgressing,
+			state: StepRunning,
+			want:  false,
+		},
+		{
+			name:  "post canary step hook",
+			phase: rolloutv1alpha1.RolloutRunPhaseProgressing,
+			state: StepPostCanaryStepHook,
+			want:  true,
+		},
+		{
+			name:  "paused before recycling",
+			phase: rolloutv1alpha1.RolloutRunPhasePaused,
+			state: StepResourceRecycling,
+			want:  true,
+		},
+		{
+			name:  "recycling",
+			phase: rolloutv1alpha1.RolloutRunPhaseProgressing,
+			state: StepResourceRecycling,
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rolloutRun := testCanaryRolloutRun.DeepCopy()
+			rolloutRun.Spec.Canary.Traffic = &rolloutv1alpha1.TrafficStrategy{Weight: ptr.To[int32](10)}
+			rolloutRun.Status.Phase = tt.phase
+			rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{State: tt.state}
+			ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+
+			e := newCanaryExecutor(newFakeWebhookExecutor())
+			assert.Equal(t, tt.want, e.shouldResyncTraffic(ctx))
+
+			ctx.Retry = RetryOptions{Default: defaultRetryInterval}
+			assert.False(t, e.shouldResyncTraffic(ctx), "resync is disabled")
+		})
+	}
+}
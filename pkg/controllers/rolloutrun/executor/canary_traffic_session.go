@@ -0,0 +1,63 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"kusionstack.io/kube-utils/multicluster/clusterinfo"
+
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+	"kusionstack.io/rollout/pkg/route/ingress"
+)
+
+const ReasonSessionAffinityUnsupported = "SessionAffinityUnsupported"
+
+// checkTrafficSessionAffinity checks each route of routings supports session
+// affinity if canary traffic sets session affinity or drain. Only nginx Ingress
+// supports it, other routes fail to add the canary route, which is only
+// reported in the status of BackendRouting while canary keeps waiting.
+func checkTrafficSessionAffinity(ctx *ExecutorContext) error {
+	traffic := ctx.RolloutRun.Spec.Canary.Traffic
+	if traffic.SessionAffinity == nil && traffic.SessionDrain == nil {
+		return nil
+	}
+	for _, routing := range ctx.TrafficManager.Routings() {
+		for _, ref := range routing.Spec.Routes {
+			supported := schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind).GroupKind() == ingress.GVK.GroupKind()
+			if supported {
+				igs := &networkingv1.Ingress{}
+				key := types.NamespacedName{Namespace: ref.NamespaceOr(routing.Namespace), Name: ref.Name}
+				if err := ctx.Client.Get(clusterinfo.WithCluster(ctx, ref.Cluster), key, igs); err != nil {
+					return err
+				}
+				supported = !ingress.IsMseIngress(igs)
+			}
+			if !supported {
+				return control.TerminalError(newDoCanaryError(
+					ReasonSessionAffinityUnsupported,
+					fmt.Sprintf("canary session affinity is not supported by %s %s of BackendRouting %s/%s, only nginx Ingress supports it",
+						ref.Kind, ref.Name, routing.Namespace, routing.Name),
+				))
+			}
+		}
+	}
+	return nil
+}
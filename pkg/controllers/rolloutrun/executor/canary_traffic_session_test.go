@@ -0,0 +1,98 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"kusionstack.io/kube-utils/multicluster/clusterinfo"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/traffic"
+	"kusionstack.io/rollout/pkg/route/ingress"
+)
+
+func Test_checkTrafficSessionAffinity(t *testing.T) {
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary.Traffic = &rolloutv1alpha1.TrafficStrategy{
+		Weight:       ptr.To[int32](20),
+		SessionDrain: &rolloutv1alpha1.SessionDrainStrategy{Seconds: 60},
+	}
+	target := rolloutv1alpha1.RolloutRunStepTarget{
+		CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-1"},
+	}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun, newFakeObject("cluster-a", "default", "test-1", 10, 0, 0))
+
+	igs := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ingress", Namespace: "default"},
+		Spec:       networkingv1.IngressSpec{IngressClassName: ptr.To(ingress.MseIngressClass)},
+	}
+	assert.NoError(t, ctx.Client.Create(clusterinfo.WithCluster(ctx, "cluster-a"), igs))
+	routing := &rolloutv1alpha1.BackendRouting{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-1-ics", Namespace: "default"},
+		Spec: rolloutv1alpha1.BackendRoutingSpec{
+			TrafficType: rolloutv1alpha1.InClusterTrafficType,
+			Backend: rolloutv1alpha1.CrossClusterObjectReference{
+				ObjectTypeRef:                   rolloutv1alpha1.ObjectTypeRef{APIVersion: "v1", Kind: "Service"},
+				CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-svc"},
+			},
+			Routes: []rolloutv1alpha1.CrossClusterObjectReference{{
+				ObjectTypeRef:                   rolloutv1alpha1.ObjectTypeRef{APIVersion: "networking.k8s.io/v1", Kind: "Ingress"},
+				CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-ingress"},
+			}},
+		},
+	}
+	assert.NoError(t, ctx.Client.Create(ctx, routing))
+	topology := rolloutv1alpha1.TrafficTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Status: rolloutv1alpha1.TrafficTopologyStatus{
+			Topologies: []rolloutv1alpha1.TopologyInfo{{WorkloadRef: target.CrossClusterObjectNameReference, BackendRoutingName: routing.Name}},
+		},
+	}
+	newTrafficManager := func() *traffic.Manager {
+		m, err := traffic.NewManager(ctx.Client, newTestLogger(), []rolloutv1alpha1.TrafficTopology{topology})
+		assert.NoError(t, err)
+		m.With(newTestLogger(), []rolloutv1alpha1.RolloutRunStepTarget{target}, rolloutRun.Spec.Canary.Traffic)
+		return m
+	}
+
+	// MSE ingress
+	ctx.TrafficManager = newTrafficManager()
+	err := checkTrafficSessionAffinity(ctx)
+	assert.True(t, errors.Is(err, control.TerminalError(nil)))
+	assert.ErrorContains(t, err, ReasonSessionAffinityUnsupported)
+
+	// nginx ingress
+	igs.Spec.IngressClassName = ptr.To("nginx")
+	assert.NoError(t, ctx.Client.Update(clusterinfo.WithCluster(ctx, "cluster-a"), igs))
+	assert.NoError(t, checkTrafficSessionAffinity(ctx))
+
+	// GRPCRoute
+	routing.Spec.Routes = append(routing.Spec.Routes, rolloutv1alpha1.CrossClusterObjectReference{
+		ObjectTypeRef:                   rolloutv1alpha1.ObjectTypeRef{APIVersion: "gateway.networking.k8s.io/v1alpha2", Kind: "GRPCRoute"},
+		CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-grpc"},
+	})
+	assert.NoError(t, ctx.Client.Update(ctx, routing))
+	ctx.TrafficManager = newTrafficManager()
+	assert.ErrorContains(t, checkTrafficSessionAffinity(ctx), ReasonSessionAffinityUnsupported)
+}
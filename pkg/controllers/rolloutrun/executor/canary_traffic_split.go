@@ -0,0 +1,227 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+const (
+	// TrafficSplitProbeHeader labels the requests sent by splitProbe with the
+	// namespaced name of RolloutRun, so that they can be told from user traffic.
+	TrafficSplitProbeHeader = "X-Rollout-Split-Probe"
+
+	defaultTrafficSplitProbeRequests         = 100
+	defaultTrafficSplitProbeTolerancePercent = 5
+	defaultTrafficSplitProbeConcurrency      = 10
+	defaultTrafficSplitProbeDeadline         = 10 * time.Second
+)
+
+// trafficSplitResult is the result of a batch of split probe requests.
+type trafficSplitResult struct {
+	// Canary is the number of requests served by canary
+	Canary int32
+	// Total is the number of requests answered
+	Total int32
+}
+
+// trafficSplitProber sends a batch of labeled requests and counts the
+// responses of canary.
+type trafficSplitProber interface {
+	ProbeSplit(ctx context.Context, probe *rolloutv1alpha1.TrafficSplitProbe, label string) (trafficSplitResult, error)
+}
+
+// httpTrafficSplitProber sends the batch with limited concurrency. The whole
+// batch is bounded by deadline, so that a slow gateway never blocks the
+// reconcile loop; an unfinished batch is reported as an error and the step
+// is requeued.
+type httpTrafficSplitProber struct {
+	// concurrency is the max number of requests in flight, defaults to 10
+	concurrency int
+	// deadline bounds the whole batch, defaults to 10s
+	deadline time.Duration
+}
+
+func (p *httpTrafficSplitProber) ProbeSplit(ctx context.Context, probe *rolloutv1alpha1.TrafficSplitProbe, label string) (trafficSplitResult, error) {
+	result := trafficSplitResult{}
+	requests := int32(defaultTrafficSplitProbeRequests)
+	if probe.Requests > 0 {
+		requests = probe.Requests
+	}
+	concurrency := defaultTrafficSplitProbeConcurrency
+	if p.concurrency > 0 {
+		concurrency = p.concurrency
+	}
+	deadline := defaultTrafficSplitProbeDeadline
+	if p.deadline > 0 {
+		deadline = p.deadline
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deadline)
+	defer cancel()
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		lastErr error
+		sent    int32
+	)
+	indexes := make(chan int32)
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range indexes {
+				canary, err := p.probeOnce(ctx, probe, label)
+				mu.Lock()
+				if err != nil {
+					lastErr = err
+				} else {
+					result.Total++
+					if canary {
+						result.Canary++
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+dispatch:
+	for ; sent < requests; sent++ {
+		select {
+		case indexes <- sent:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return result, fmt.Errorf("split probe did not finish in %s, %d of %d requests answered: %w", deadline, result.Total, requests, ctx.Err())
+	}
+	if result.Total == 0 && lastErr != nil {
+		return result, fmt.Errorf("all %d split probe requests failed, last err: %w", requests, lastErr)
+	}
+	return result, nil
+}
+
+func (p *httpTrafficSplitProber) probeOnce(ctx context.Context, probe *rolloutv1alpha1.TrafficSplitProbe, label string) (bool, error) {
+	timeout := time.Duration(defaultTrafficProbeTimeoutSeconds) * time.Second
+	if probe.TimeoutSeconds > 0 {
+		timeout = time.Duration(probe.TimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, probe.URL, nil)
+	if err != nil {
+		return false, err
+	}
+	if len(probe.Host) > 0 {
+		req.Host = probe.Host
+	}
+	for k, v := range probe.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set(TrafficSplitProbeHeader, label)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body) // nolint
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return false, fmt.Errorf("unexpected status code %d, expected 2xx", resp.StatusCode)
+	}
+	value := resp.Header.Get(probe.CanaryHeader)
+	if len(probe.CanaryHeaderValue) > 0 {
+		return value == probe.CanaryHeaderValue, nil
+	}
+	return len(value) > 0, nil
+}
+
+// verifyTrafficSplit compares the canary split observed by splitProbe with
+// the canary weight. The step is held until the split is within tolerance.
+func (e *canaryExecutor) verifyTrafficSplit(ctx *ExecutorContext) (bool, time.Duration, error) {
+	traffic := ctx.RolloutRun.Spec.Canary.Traffic
+	if traffic == nil || traffic.SplitProbe == nil || traffic.CanaryWeight() == nil {
+		return true, retryImmediately, nil
+	}
+	probe := traffic.SplitProbe
+	logger := ctx.GetCanaryLogger()
+
+	status := ctx.NewStatus.CanaryStatus
+	verification := trafficVerification(status, rolloutv1alpha1.TrafficVerificationSplitProbe, "forkCanary")
+	if verification.Verified {
+		return true, retryImmediately, nil
+	}
+
+	expected := *traffic.CanaryWeight()
+	if ceiling := e.weightCeiling(ctx.RolloutRun); ceiling > 0 && expected > ceiling {
+		expected = ceiling
+	}
+	tolerance := int32(defaultTrafficSplitProbeTolerancePercent)
+	if probe.TolerancePercent != nil {
+		tolerance = *probe.TolerancePercent
+	}
+
+	label := fmt.Sprintf("%s/%s", ctx.RolloutRun.Namespace, ctx.RolloutRun.Name)
+	result, err := e.splitProber.ProbeSplit(ctx, probe, label)
+	split := &rolloutv1alpha1.TrafficSplitStatus{
+		ExpectedPercent: expected,
+		CanaryRequests:  result.Canary,
+		TotalRequests:   result.Total,
+		LastProbeTime:   ptr.To(metav1.Now()),
+	}
+	status.TrafficSplit = split
+	if err != nil {
+		split.Message = err.Error()
+		verification.Message = split.Message
+		status.WaitingReason = rolloutv1alpha1.StepTrafficSplitMismatch
+		logger.Info("canary traffic split probe failed, retry later", "url", probe.URL, "err", err.Error())
+		return false, retryDefault, nil
+	}
+
+	observed := float64(result.Canary) * 100 / float64(result.Total)
+	split.ObservedPercent = int32(math.Round(observed))
+	if math.Abs(observed-float64(expected)) > float64(tolerance) {
+		split.Message = fmt.Sprintf("observed %.1f%% of %d requests served by canary, expected %d%%±%d%%", observed, result.Total, expected, tolerance)
+		verification.Message = split.Message
+		status.WaitingReason = rolloutv1alpha1.StepTrafficSplitMismatch
+		logger.Info("canary traffic split is out of tolerance, hold", "observed", observed, "expected", expected, "tolerance", tolerance)
+		return false, retryDefault, nil
+	}
+
+	split.Message = fmt.Sprintf("observed %.1f%% of %d requests served by canary, within %d%%±%d%%", observed, result.Total, expected, tolerance)
+	verification.Verified = true
+	verification.Message = split.Message
+	return true, retryImmediately, nil
+}
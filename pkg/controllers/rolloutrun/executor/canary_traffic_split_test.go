@@ -0,0 +1,213 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+type fakeTrafficSplitProber struct {
+	result trafficSplitResult
+	err    error
+	label  string
+	calls  int
+}
+
+func (p *fakeTrafficSplitProber) ProbeSplit(_ context.Context, _ *rolloutv1alpha1.TrafficSplitProbe, label string) (trafficSplitResult, error) {
+	p.calls++
+	p.label = label
+	return p.result, p.err
+}
+
+func Test_CanaryExecutor_verifyTrafficSplit(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxWeight     int32
+		result        trafficSplitResult
+		probeErr      error
+		wantDone      bool
+		wantObserved  int32
+		wantExpected  int32
+		wantWaiting   rolloutv1alpha1.StepWaitingReason
+		wantSplitText string
+	}{
+		{
+			name:          "split within tolerance",
+			result:        trafficSplitResult{Canary: 23, Total: 100},
+			wantDone:      true,
+			wantObserved:  23,
+			wantExpected:  20,
+			wantSplitText: "observed 23.0% of 100 requests served by canary, within 20%±5%",
+		},
+		{
+			name:          "split out of tolerance",
+			result:        trafficSplitResult{Canary: 2, Total: 100},
+			wantDone:      false,
+			wantObserved:  2,
+			wantExpected:  20,
+			wantWaiting:   rolloutv1alpha1.StepTrafficSplitMismatch,
+			wantSplitText: "observed 2.0% of 100 requests served by canary, expected 20%±5%",
+		},
+		{
+			name:          "expected weight is clamped",
+			maxWeight:     5,
+			result:        trafficSplitResult{Canary: 6, Total: 100},
+			wantDone:      true,
+			wantObserved:  6,
+			wantExpected:  5,
+			wantSplitText: "observed 6.0% of 100 requests served by canary, within 5%±5%",
+		},
+		{
+			name:          "probe failed",
+			probeErr:      fmt.Errorf("all 100 split probe requests failed"),
+			wantDone:      false,
+			wantExpected:  20,
+			wantWaiting:   rolloutv1alpha1.StepTrafficSplitMismatch,
+			wantSplitText: "all 100 split probe requests failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rolloutRun := testRolloutRun.DeepCopy()
+			rolloutRun.Spec.Canary = &rolloutv1alpha1.RolloutRunCanaryStrategy{
+				Targets: unimportantTargets,
+				Traffic: &rolloutv1alpha1.TrafficStrategy{
+					Weight: ptr.To[int32](20),
+					SplitProbe: &rolloutv1alpha1.TrafficSplitProbe{
+						URL:          "http://gateway.example.com/version",
+						CanaryHeader: "X-Canary",
+					},
+				},
+			}
+			rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{
+				State: StepRunning,
+			}
+			ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+
+			prober := &fakeTrafficSplitProber{result: tt.result, err: tt.probeErr}
+			e := newCanaryExecutor(newFakeWebhookExecutor())
+			e.splitProber = prober
+			e.maxTrafficWeight = tt.maxWeight
+			done, _, err := e.verifyTrafficSplit(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantDone, done)
+			assert.Equal(t, rolloutRun.Namespace+"/"+rolloutRun.Name, prober.label)
+
+			status := ctx.NewStatus.CanaryStatus
+			assert.Equal(t, tt.wantWaiting, status.WaitingReason)
+			if assert.NotNil(t, status.TrafficSplit) {
+				assert.Equal(t, tt.wantExpected, status.TrafficSplit.ExpectedPercent)
+				assert.Equal(t, tt.wantObserved, status.TrafficSplit.ObservedPercent)
+				assert.Equal(t, tt.wantSplitText, status.TrafficSplit.Message)
+			}
+			verification := trafficVerification(status, rolloutv1alpha1.TrafficVerificationSplitProbe, "forkCanary")
+			assert.Equal(t, tt.wantDone, verification.Verified)
+
+			// verified split is not probed again
+			if tt.wantDone {
+				done, _, err = e.verifyTrafficSplit(ctx)
+				assert.NoError(t, err)
+				assert.True(t, done)
+				assert.Equal(t, 1, prober.calls)
+			}
+		})
+	}
+}
+
+func Test_httpTrafficSplitProber(t *testing.T) {
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "default/demo", r.Header.Get(TrafficSplitProbeHeader))
+		if atomic.AddInt32(&count, 1)%4 == 0 {
+			w.Header().Set("X-Canary", "true")
+		} else {
+			w.Header().Set("X-Canary", "false")
+		}
+	}))
+	defer server.Close()
+
+	prober := &httpTrafficSplitProber{}
+	probe := &rolloutv1alpha1.TrafficSplitProbe{
+		URL:               server.URL,
+		CanaryHeader:      "X-Canary",
+		CanaryHeaderValue: "true",
+		Requests:          20,
+	}
+	result, err := prober.ProbeSplit(context.TODO(), probe, "default/demo")
+	assert.NoError(t, err)
+	assert.Equal(t, trafficSplitResult{Canary: 5, Total: 20}, result)
+
+	// any value of canary header means canary
+	probe.CanaryHeaderValue = ""
+	result, err = prober.ProbeSplit(context.TODO(), probe, "default/demo")
+	assert.NoError(t, err)
+	assert.Equal(t, trafficSplitResult{Canary: 20, Total: 20}, result)
+
+	server.Close()
+	_, err = prober.ProbeSplit(context.TODO(), probe, "default/demo")
+	assert.Error(t, err)
+}
+
+func Test_httpTrafficSplitProber_deadline(t *testing.T) {
+	var inflight, maxInflight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inflight, 1)
+		defer atomic.AddInt32(&inflight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInflight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInflight, m, n) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		w.Header().Set("X-Canary", "true")
+	}))
+	defer server.Close()
+
+	probe := &rolloutv1alpha1.TrafficSplitProbe{
+		URL:          server.URL,
+		CanaryHeader: "X-Canary",
+		Requests:     8,
+	}
+
+	// requests are sent concurrently
+	prober := &httpTrafficSplitProber{concurrency: 4, deadline: 5 * time.Second}
+	result, err := prober.ProbeSplit(context.TODO(), probe, "default/demo")
+	assert.NoError(t, err)
+	assert.Equal(t, trafficSplitResult{Canary: 8, Total: 8}, result)
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInflight), int32(4))
+
+	// the batch is bounded by deadline
+	probe.Requests = 100
+	prober = &httpTrafficSplitProber{concurrency: 1, deadline: 120 * time.Millisecond}
+	start := time.Now()
+	result, err = prober.ProbeSplit(context.TODO(), probe, "default/demo")
+	assert.Error(t, err)
+	assert.Less(t, result.Total, int32(100))
+	assert.Less(t, time.Since(start), time.Second)
+}
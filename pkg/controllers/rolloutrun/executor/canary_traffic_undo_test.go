@@ -0,0 +1,128 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/traffic"
+)
+
+func Test_TrafficUndoLog(t *testing.T) {
+	tests := []struct {
+		name  string
+		prior *rolloutv1alpha1.BackendForwarding
+	}{
+		{
+			name: "not forwarded before",
+		},
+		{
+			name:  "forwarded before",
+			prior: &rolloutv1alpha1.BackendForwarding{Stable: rolloutv1alpha1.StableBackendRule{Name: "test-svc-legacy"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy := &rolloutv1alpha1.TrafficStrategy{Weight: ptr.To[int32](20)}
+			target := rolloutv1alpha1.RolloutRunStepTarget{
+				CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-1"},
+			}
+			ctx := createTestExecutorContext(testRollout.DeepCopy(), testCanaryRolloutRun.DeepCopy(), newFakeObject("cluster-a", "default", "test-1", 10, 0, 0))
+			routing := &rolloutv1alpha1.BackendRouting{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-1-ics", Namespace: "default"},
+				Spec: rolloutv1alpha1.BackendRoutingSpec{
+					TrafficType: rolloutv1alpha1.InClusterTrafficType,
+					Backend: rolloutv1alpha1.CrossClusterObjectReference{
+						ObjectTypeRef:                   rolloutv1alpha1.ObjectTypeRef{APIVersion: "v1", Kind: "Service"},
+						CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-svc"},
+					},
+					Forwarding: tt.prior.DeepCopy(),
+				},
+			}
+			assert.NoError(t, ctx.Client.Create(ctx, routing))
+			topology := rolloutv1alpha1.TrafficTopology{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Status: rolloutv1alpha1.TrafficTopologyStatus{
+					Topologies: []rolloutv1alpha1.TopologyInfo{{WorkloadRef: target.CrossClusterObjectNameReference, BackendRoutingName: routing.Name}},
+				},
+			}
+
+			var undoLog []rolloutv1alpha1.TrafficUndoEntry
+			// a new manager is created in each reconcile
+			reconcile := func(op func(m *traffic.Manager) (controllerutil.OperationResult, error)) controllerutil.OperationResult {
+				m, err := traffic.NewManager(ctx.Client, newTestLogger(), []rolloutv1alpha1.TrafficTopology{topology})
+				assert.NoError(t, err)
+				m.With(newTestLogger(), []rolloutv1alpha1.RolloutRunStepTarget{target}, strategy)
+				m.WithUndoLog(&undoLog)
+				result, err := op(m)
+				assert.NoError(t, err)
+				return result
+			}
+			getForwarding := func() *rolloutv1alpha1.BackendForwarding {
+				assert.NoError(t, ctx.Client.Get(ctx, client.ObjectKeyFromObject(routing), routing))
+				return routing.Spec.Forwarding
+			}
+
+			// the entry is recorded before the routing is changed
+			assert.Equal(t, controllerutil.OperationResultUpdated, reconcile((*traffic.Manager).ForkStable))
+			assert.Equal(t, tt.prior, getForwarding())
+			assert.Equal(t, controllerutil.OperationResultUpdated, reconcile((*traffic.Manager).ForkStable))
+			assert.Equal(t, "test-svc-stable", getForwarding().Stable.Name)
+			assert.Equal(t, controllerutil.OperationResultNone, reconcile((*traffic.Manager).ForkStable))
+
+			reconcile((*traffic.Manager).ForkCanary)
+			reconcile((*traffic.Manager).ForkCanary)
+			assert.Equal(t, "test-svc-canary", getForwarding().Canary.Name)
+			if assert.Len(t, undoLog, 2) {
+				assert.Equal(t, tt.prior, undoLog[0].Forwarding)
+				assert.Equal(t, "test-svc-stable", undoLog[1].Forwarding.Stable.Name)
+			}
+
+			// the forwarding drifts during canary
+			forwarding := getForwarding()
+			forwarding.Stable.Name = "test-svc-drifted"
+			forwarding.Canary.Weight = ptr.To[int32](50)
+			assert.NoError(t, ctx.Client.Update(ctx, routing))
+
+			// the drifted canary rule is reverted, the drifted stable rule is kept
+			reconcile((*traffic.Manager).RevertCanary)
+			forwarding = getForwarding()
+			assert.False(t, forwarding.HasCanary())
+			assert.Equal(t, "test-svc-drifted", forwarding.Stable.Name)
+
+			// reverts are idempotent
+			reconcile((*traffic.Manager).RevertStable)
+			assert.Equal(t, tt.prior, getForwarding())
+			assert.Equal(t, controllerutil.OperationResultNone, reconcile((*traffic.Manager).RevertStable))
+			assert.Equal(t, controllerutil.OperationResultNone, reconcile((*traffic.Manager).RevertCanary))
+			for _, entry := range undoLog {
+				assert.True(t, entry.Reverted)
+			}
+
+			// a reverted entry is recorded again
+			reconcile((*traffic.Manager).ForkStable)
+			assert.False(t, undoLog[0].Reverted)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"github.com/samber/lo"
+
+	rolloutapi "kusionstack.io/rollout/apis/rollout"
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+// canaryVariants returns the variants of canary workloads created for each
+// target. Without variants, a single variant with empty name stands for the
+// canary workload.
+func canaryVariants(rolloutRun *rolloutv1alpha1.RolloutRun) []rolloutv1alpha1.CanaryVariant {
+	if rolloutRun.Spec.Canary == nil || len(rolloutRun.Spec.Canary.Variants) == 0 {
+		return []rolloutv1alpha1.CanaryVariant{{}}
+	}
+	return rolloutRun.Spec.Canary.Variants
+}
+
+// canaryVariantNames returns the names of canary variants, it is empty if
+// canary has no variants.
+func canaryVariantNames(rolloutRun *rolloutv1alpha1.RolloutRun) []string {
+	if rolloutRun.Spec.Canary == nil {
+		return nil
+	}
+	return lo.Map(rolloutRun.Spec.Canary.Variants, func(variant rolloutv1alpha1.CanaryVariant, _ int) string {
+		return variant.Name
+	})
+}
+
+// variantPodTemplatePatch returns a copy of patch merged with the patch of
+// variant and labeled with the variant name, which also makes the selector of
+// canary workload of each variant distinct.
+func variantPodTemplatePatch(patch *rolloutv1alpha1.MetadataPatch, variant rolloutv1alpha1.CanaryVariant) *rolloutv1alpha1.MetadataPatch {
+	if len(variant.Name) == 0 {
+		return patch
+	}
+	if patch == nil {
+		patch = &rolloutv1alpha1.MetadataPatch{}
+	} else {
+		patch = patch.DeepCopy()
+	}
+	if patch.Labels == nil {
+		patch.Labels = map[string]string{}
+	}
+	if variant.PodTemplateMetadataPatch != nil {
+		for k, v := range variant.PodTemplateMetadataPatch.Labels {
+			patch.Labels[k] = v
+		}
+		if len(variant.PodTemplateMetadataPatch.Annotations) > 0 && patch.Annotations == nil {
+			patch.Annotations = map[string]string{}
+		}
+		for k, v := range variant.PodTemplateMetadataPatch.Annotations {
+			patch.Annotations[k] = v
+		}
+	}
+	patch.Labels[rolloutapi.LabelCanaryVariant] = variant.Name
+	return patch
+}
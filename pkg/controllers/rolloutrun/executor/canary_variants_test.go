@@ -0,0 +1,107 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	rolloutapi "kusionstack.io/rollout/apis/rollout"
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/traffic"
+)
+
+func Test_variantPodTemplatePatch(t *testing.T) {
+	patch := &rolloutv1alpha1.MetadataPatch{Labels: map[string]string{"app": "demo"}}
+
+	// canary without variant uses the patch as is
+	assert.Same(t, patch, variantPodTemplatePatch(patch, rolloutv1alpha1.CanaryVariant{}))
+
+	got := variantPodTemplatePatch(patch, rolloutv1alpha1.CanaryVariant{
+		Name:     "b",
+		Replicas: intstr.FromInt(1),
+		PodTemplateMetadataPatch: &rolloutv1alpha1.MetadataPatch{
+			Labels:      map[string]string{"version": "b"},
+			Annotations: map[string]string{"note": "variant b"},
+		},
+	})
+	assert.Equal(t, map[string]string{"app": "demo", "version": "b", rolloutapi.LabelCanaryVariant: "b"}, got.Labels)
+	assert.Equal(t, map[string]string{"note": "variant b"}, got.Annotations)
+	// the shared patch is not changed
+	assert.Equal(t, map[string]string{"app": "demo"}, patch.Labels)
+}
+
+func Test_ForkCanary_variants(t *testing.T) {
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary.Traffic = &rolloutv1alpha1.TrafficStrategy{
+		Allocation: &rolloutv1alpha1.TrafficAllocation{
+			Variants: []rolloutv1alpha1.TrafficVariant{
+				{Name: "b", Percent: 20},
+				{Name: "c", Percent: 10},
+			},
+			StablePercent: 70,
+		},
+	}
+	rolloutRun.Spec.Canary.Variants = []rolloutv1alpha1.CanaryVariant{
+		{Name: "b", Replicas: intstr.FromInt(1)},
+		{Name: "c", Replicas: intstr.FromInt(1)},
+	}
+	target := rolloutv1alpha1.RolloutRunStepTarget{
+		CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-1"},
+	}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun, newFakeObject("cluster-a", "default", "test-1", 10, 0, 0))
+
+	routing := &rolloutv1alpha1.BackendRouting{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-1-ics", Namespace: "default"},
+		Spec: rolloutv1alpha1.BackendRoutingSpec{
+			TrafficType: rolloutv1alpha1.InClusterTrafficType,
+			Backend: rolloutv1alpha1.CrossClusterObjectReference{
+				ObjectTypeRef:                   rolloutv1alpha1.ObjectTypeRef{APIVersion: "v1", Kind: "Service"},
+				CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-svc"},
+			},
+		},
+	}
+	assert.NoError(t, ctx.Client.Create(ctx, routing))
+	topology := rolloutv1alpha1.TrafficTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Status: rolloutv1alpha1.TrafficTopologyStatus{
+			Topologies: []rolloutv1alpha1.TopologyInfo{{WorkloadRef: target.CrossClusterObjectNameReference, BackendRoutingName: routing.Name}},
+		},
+	}
+	m, err := traffic.NewManager(ctx.Client, newTestLogger(), []rolloutv1alpha1.TrafficTopology{topology})
+	assert.NoError(t, err)
+	m.With(newTestLogger(), []rolloutv1alpha1.RolloutRunStepTarget{target}, rolloutRun.Spec.Canary.Traffic)
+	m.WithVariants(canaryVariantNames(rolloutRun))
+
+	_, err = m.ForkCanary()
+	assert.NoError(t, err)
+	assert.NoError(t, ctx.Client.Get(ctx, client.ObjectKeyFromObject(routing), routing))
+	assert.Empty(t, routing.Spec.Forwarding.Canary.Name)
+	assert.Equal(t, []rolloutv1alpha1.CanaryVariantBackendRule{
+		{Variant: "b", Name: "test-svc-canary-b", Weight: 20},
+		{Variant: "c", Name: "test-svc-canary-c", Weight: 10},
+	}, routing.Spec.Forwarding.Variants)
+
+	_, err = m.RevertCanary()
+	assert.NoError(t, err)
+	assert.NoError(t, ctx.Client.Get(ctx, client.ObjectKeyFromObject(routing), routing))
+	assert.False(t, routing.Spec.Forwarding.HasCanary())
+}
@@ -0,0 +1,264 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	nethttp "net/http"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutapi "kusionstack.io/rollout/apis/rollout"
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/webhook/probe"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/webhook/probe/http"
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+const (
+	defaultWarmUpTimeoutSeconds        = 300
+	defaultWarmUpRequestTimeoutSeconds = 5
+)
+
+// warmUpRequester sends a synthetic warm-up request to a canary pod.
+type warmUpRequester interface {
+	Request(ctx context.Context, url string, headers map[string]string, timeout time.Duration) error
+}
+
+type httpWarmUpRequester struct{}
+
+func (r *httpWarmUpRequester) Request(ctx context.Context, url string, headers map[string]string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := nethttp.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body) // nolint
+
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// canaryWarmer warms up canary pods after they are ready and before traffic is
+// forked to them.
+type canaryWarmer struct {
+	requester warmUpRequester
+	newProber func(config rolloutv1alpha1.WebhookClientConfig, cert *http.ClientCert) (probe.WebhookProber, error)
+}
+
+func newCanaryWarmer() *canaryWarmer {
+	return &canaryWarmer{
+		requester: &httpWarmUpRequester{},
+		newProber: http.NewWithClientCert,
+	}
+}
+
+// warmUp returns true once canary is warmed up. Warm-up requests are sent once,
+// then the webhook is called until it responds OK, and the warm-up lasts at
+// least durationSeconds. The rolloutRun fails if it is not completed before the
+// timeout.
+func (w *canaryWarmer) warmUp(ctx *ExecutorContext, canaryWorkloads []CanaryTargetInfo) (bool, time.Duration, error) {
+	spec := ctx.RolloutRun.Spec.Canary.WarmUp
+	if spec == nil {
+		return true, retryImmediately, nil
+	}
+	logger := ctx.GetCanaryLogger()
+	stepStatus := ctx.NewStatus.CanaryStatus
+	if stepStatus.WarmUp == nil {
+		stepStatus.WarmUp = &rolloutv1alpha1.CanaryWarmUpStatus{StartTime: ptr.To(metav1.Now())}
+	}
+	status := stepStatus.WarmUp
+	if status.CompletionTime != nil {
+		return true, retryImmediately, nil
+	}
+
+	now := time.Now()
+	timeout := time.Duration(defaultWarmUpTimeoutSeconds) * time.Second
+	if spec.TimeoutSeconds > 0 {
+		timeout = time.Duration(spec.TimeoutSeconds) * time.Second
+	}
+	if now.Sub(status.StartTime.Time) > timeout {
+		// reset warm-up so that a manual retry starts a new one
+		stepStatus.WarmUp = nil
+		return false, retryStop, control.TerminalError(newDoCanaryError(
+			"WarmUpTimeout",
+			fmt.Sprintf("canary is not warmed up within %v: %s", timeout, status.Message),
+		))
+	}
+
+	if spec.Requests != nil && !status.RequestsSent {
+		if err := w.sendRequests(ctx, spec.Requests, canaryWorkloads, status); err != nil {
+			return false, retryStop, err
+		}
+		status.RequestsSent = true
+		logger.Info("warm-up requests are sent to canary pods", "succeeded", status.SucceededRequests, "failed", status.FailedRequests)
+	}
+
+	if spec.Webhook != nil && !status.WebhookPassed {
+		passed, err := w.callWebhook(ctx, *spec.Webhook, status)
+		if err != nil {
+			return false, retryStop, err
+		}
+		if !passed {
+			logger.Info("canary warm-up webhook is not passed yet, call it later", "message", status.Message)
+			return false, retryDefault, nil
+		}
+		status.WebhookPassed = true
+	}
+
+	duration := time.Duration(spec.DurationSeconds) * time.Second
+	if remaining := status.StartTime.Add(duration).Sub(now); remaining > 0 {
+		status.Message = fmt.Sprintf("warming up canary for %v", duration)
+		return false, remaining, nil
+	}
+
+	status.CompletionTime = ptr.To(metav1.NewTime(now))
+	status.Message = "canary is warmed up"
+	return true, retryImmediately, nil
+}
+
+// sendRequests sends warm-up requests to every ready canary pod. Failed requests
+// are only counted, since cold pods are expected to respond slowly.
+func (w *canaryWarmer) sendRequests(ctx *ExecutorContext, requests *rolloutv1alpha1.CanaryWarmUpRequests, canaryWorkloads []CanaryTargetInfo, status *rolloutv1alpha1.CanaryWarmUpStatus) error {
+	count := requests.Count
+	if count == 0 {
+		count = 1
+	}
+	timeout := time.Duration(defaultWarmUpRequestTimeoutSeconds) * time.Second
+	if requests.TimeoutSeconds > 0 {
+		timeout = time.Duration(requests.TimeoutSeconds) * time.Second
+	}
+
+	var lastErr error
+	for _, item := range canaryWorkloads {
+		pods, err := warmUpPods(ctx, item.Info)
+		if err != nil {
+			return err
+		}
+		for _, pod := range pods {
+			if len(pod.Status.PodIP) == 0 || !isPodReady(pod) {
+				continue
+			}
+			url := fmt.Sprintf("http://%s%s", net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(requests.Port))), requests.Path)
+			for i := int32(0); i < count; i++ {
+				if err := w.requester.Request(ctx, url, requests.Headers, timeout); err != nil {
+					status.FailedRequests++
+					lastErr = err
+					continue
+				}
+				status.SucceededRequests++
+			}
+		}
+	}
+
+	status.Message = fmt.Sprintf("%d warm-up requests succeeded, %d failed", status.SucceededRequests, status.FailedRequests)
+	if lastErr != nil {
+		status.Message = fmt.Sprintf("%s, last error: %v", status.Message, lastErr)
+	}
+	return nil
+}
+
+// warmUpPods returns the pods of canary, which are the pods of ordinals updated
+// in place if ordinals are set.
+func warmUpPods(ctx *ExecutorContext, info *workload.Info) ([]*corev1.Pod, error) {
+	if ordinals := ctx.RolloutRun.Spec.Canary.Ordinals; len(ordinals) > 0 {
+		return ctx.Accessor.(workload.OrdinalCanaryControl).GetOrdinalPods(ctx.Client, info.Object, ordinals)
+	}
+	pods, err := listCanaryPods(ctx, ctx.Client, ctx.Accessor, info)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*corev1.Pod, 0, len(pods))
+	for i := range pods {
+		result = append(result, &pods[i])
+	}
+	return result, nil
+}
+
+// callWebhook returns true if the warm-up webhook responds OK.
+func (w *canaryWarmer) callWebhook(ctx *ExecutorContext, config rolloutv1alpha1.WebhookClientConfig, status *rolloutv1alpha1.CanaryWarmUpStatus) (bool, error) {
+	cert, err := loadWebhookClientCert(ctx, string(rolloutv1alpha1.CanaryWarmUpHook), config)
+	if err != nil {
+		return false, err
+	}
+	prober, err := w.newProber(config, cert)
+	if err != nil {
+		return false, control.TerminalError(newDoCanaryError(
+			"InvalidWarmUpWebhook",
+			fmt.Sprintf("failed to create prober of warm-up webhook, err: %v", err),
+		))
+	}
+
+	review := ctx.makeCanaryWarmUpReview()
+	result := prober.Probe(&review)
+	status.Message = fmt.Sprintf("warm-up webhook responds %s: %s", result.Code, result.Message)
+	return result.Code == rolloutv1alpha1.WebhookReviewCodeOK, nil
+}
+
+func (c *ExecutorContext) makeCanaryWarmUpReview() rolloutv1alpha1.RolloutWebhookReview {
+	rolloutRun := c.RolloutRun
+	return rolloutv1alpha1.RolloutWebhookReview{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: rolloutRun.Namespace,
+			Name:      string(rolloutv1alpha1.CanaryWarmUpHook),
+			Annotations: map[string]string{
+				rolloutapi.AnnoTraceParent: traceParent(c.TraceID, string(rolloutv1alpha1.CanaryWarmUpHook)),
+			},
+		},
+		Spec: rolloutv1alpha1.RolloutWebhookReviewSpec{
+			Kind:        c.OwnerKind,
+			RolloutName: c.OwnerName,
+			RolloutID:   rolloutRun.Name,
+			HookType:    rolloutv1alpha1.CanaryWarmUpHook,
+			TargetType:  rolloutRun.Spec.TargetType,
+			Canary: &rolloutv1alpha1.RolloutWebhookReviewCanary{
+				Targets:    rolloutRun.Spec.Canary.Targets,
+				State:      c.NewStatus.CanaryStatus.State,
+				Properties: rolloutRun.Spec.Canary.Properties,
+			},
+		},
+	}
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
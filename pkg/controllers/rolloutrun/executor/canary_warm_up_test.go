@@ -0,0 +1,129 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/webhook/probe"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/webhook/probe/http"
+)
+
+type fakeWarmUpRequester struct {
+	urls []string
+}
+
+func (r *fakeWarmUpRequester) Request(_ context.Context, url string, _ map[string]string, _ time.Duration) error {
+	r.urls = append(r.urls, url)
+	return nil
+}
+
+func Test_canaryWarmer(t *testing.T) {
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun, newFakeObject("cluster-a", "default", "test-1", 10, 0, 0))
+	ctx.NewStatus.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{State: StepRunning}
+
+	canary := newFakeObject("cluster-a", "default", "test-1-canary", 2, 0, 2)
+	canary.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test-1-canary"}}
+	canaryInfo, err := ctx.Accessor.GetInfo("cluster-a", canary)
+	assert.NoError(t, err)
+	canaryWorkloads := []CanaryTargetInfo{{Info: canaryInfo}}
+	for name, ready := range map[string]corev1.ConditionStatus{"ready": corev1.ConditionTrue, "not-ready": corev1.ConditionFalse} {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "test-1-canary"}},
+		}
+		assert.NoError(t, ctx.Client.Create(ctx, pod))
+		pod.Status = corev1.PodStatus{
+			PodIP:      "10.0.0.1",
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+		}
+		if ready != corev1.ConditionTrue {
+			pod.Status.PodIP = "10.0.0.2"
+		}
+		assert.NoError(t, ctx.Client.Status().Update(ctx, pod))
+	}
+
+	requester := &fakeWarmUpRequester{}
+	prober := &fakeGuardProber{}
+	w := &canaryWarmer{
+		requester: requester,
+		newProber: func(_ rolloutv1alpha1.WebhookClientConfig, _ *http.ClientCert) (probe.WebhookProber, error) {
+			return prober, nil
+		},
+	}
+
+	// no warm-up
+	done, _, err := w.warmUp(ctx, canaryWorkloads)
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Nil(t, ctx.NewStatus.CanaryStatus.WarmUp)
+
+	rolloutRun.Spec.Canary.WarmUp = &rolloutv1alpha1.CanaryWarmUp{
+		DurationSeconds: 60,
+		Requests:        &rolloutv1alpha1.CanaryWarmUpRequests{Port: 8080, Path: "/warm", Count: 2},
+		Webhook:         &rolloutv1alpha1.WebhookClientConfig{URL: "http://warm-up"},
+		TimeoutSeconds:  300,
+	}
+
+	// requests are sent to ready pods, and webhook is processing
+	prober.result = probe.Result{Code: rolloutv1alpha1.WebhookReviewCodeProcessing, Message: "replaying"}
+	done, retry, err := w.warmUp(ctx, canaryWorkloads)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, retryDefault, retry)
+	assert.Equal(t, []string{"http://10.0.0.1:8080/warm", "http://10.0.0.1:8080/warm"}, requester.urls)
+	status := ctx.NewStatus.CanaryStatus.WarmUp
+	assert.True(t, status.RequestsSent)
+	assert.EqualValues(t, 2, status.SucceededRequests)
+	assert.Equal(t, rolloutv1alpha1.CanaryWarmUpHook, prober.reviewed.Spec.HookType)
+
+	// webhook passes, waiting for duration, requests are not sent again
+	prober.result = probe.Result{Code: rolloutv1alpha1.WebhookReviewCodeOK}
+	done, retry, err = w.warmUp(ctx, canaryWorkloads)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.True(t, retry > 0 && retry <= time.Minute)
+	assert.Len(t, requester.urls, 2)
+	assert.True(t, status.WebhookPassed)
+
+	// duration elapsed
+	status.StartTime = ptr.To(metav1.NewTime(time.Now().Add(-2 * time.Minute)))
+	done, _, err = w.warmUp(ctx, canaryWorkloads)
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.NotNil(t, status.CompletionTime)
+
+	// timeout
+	ctx.NewStatus.CanaryStatus.WarmUp = &rolloutv1alpha1.CanaryWarmUpStatus{
+		StartTime:    ptr.To(metav1.NewTime(time.Now().Add(-10 * time.Minute))),
+		RequestsSent: true,
+	}
+	_, _, err = w.warmUp(ctx, canaryWorkloads)
+	assert.True(t, errors.Is(err, control.TerminalError(nil)))
+	assert.ErrorContains(t, err, "WarmUpTimeout")
+	assert.Nil(t, ctx.NewStatus.CanaryStatus.WarmUp)
+}
@@ -0,0 +1,102 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutapis "kusionstack.io/rollout/apis/rollout"
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+const (
+	ReasonRolloutRunCancelRequested = "CancelRequested"
+	ReasonRolloutRunCanceled        = "RolloutRunCanceled"
+)
+
+// isCancelRequested returns true if the cancellation is observed from spec.cancel.
+func isCancelRequested(ctx *ExecutorContext) bool {
+	return ctx.NewStatus.Cancellation != nil
+}
+
+// cancelRequestedBy returns the user requesting the cancellation.
+func cancelRequestedBy(cancellation *rolloutv1alpha1.RolloutRunCancellationStatus) string {
+	if len(cancellation.RequestedBy) == 0 {
+		return "unknown"
+	}
+	return cancellation.RequestedBy
+}
+
+// observeCancel handles spec.cancel. The canary which may be serving is moved
+// to recycling, and recycled as a rollback before the rolloutRun is canceled,
+// otherwise the rolloutRun is canceled directly. It is observed only once, so
+// that setting it again or unsetting it later changes nothing, and it is
+// ignored by the completed rolloutRun.
+func observeCancel(ctx *ExecutorContext, now time.Time) {
+	if !ctx.RolloutRun.Spec.Cancel || isCancelRequested(ctx) {
+		return
+	}
+	newStatus := ctx.NewStatus
+	switch newStatus.Phase {
+	case rolloutv1alpha1.RolloutRunPhaseSucceeded, rolloutv1alpha1.RolloutRunPhaseCanceled, rolloutv1alpha1.RolloutRunPhaseCanceling:
+		return
+	}
+
+	cancellation := &rolloutv1alpha1.RolloutRunCancellationStatus{
+		RequestedBy: ctx.RolloutRun.Annotations[rolloutapis.AnnoManualCommandBy],
+		RequestTime: ptr.To(metav1.NewTime(now)),
+	}
+	newStatus.Cancellation = cancellation
+
+	if ctx.inCanary() && newStatus.CanaryStatus != nil {
+		status := newStatus.CanaryStatus
+		switch status.State {
+		case StepPreCanaryStepHook, StepRunning, StepHolding, StepPostCanaryStepHook:
+			// the failed canary is recycled instead of waiting for retry
+			if newStatus.Error != nil {
+				newStatus.Error = nil
+				resetCanaryCompletion(ctx)
+			}
+			status.PausedBefore = ""
+			ctx.MoveToNextState(StepResourceRecycling)
+			cancellation.Message = "canary is rolled back before rolloutRun is canceled"
+		case StepResourceRecycling:
+			// the recycling in progress is taken as a rollback, the failed one
+			// is left to the retry command
+			cancellation.Message = "canary is rolled back before rolloutRun is canceled"
+		}
+	}
+
+	if len(cancellation.Message) > 0 {
+		if newStatus.Phase == rolloutv1alpha1.RolloutRunPhasePaused || newStatus.Phase == rolloutv1alpha1.RolloutRunPhasePausing {
+			newStatus.Phase = rolloutv1alpha1.RolloutRunPhaseProgressing
+		}
+	} else {
+		// nothing to recycle, the error is dropped so that it completes
+		newStatus.Error = nil
+		newStatus.Phase = rolloutv1alpha1.RolloutRunPhaseCanceling
+		cancellation.Message = "rolloutRun is canceled"
+	}
+
+	ctx.GetLogger().Info("cancellation is requested by spec.cancel", "requestedBy", cancellation.RequestedBy, "message", cancellation.Message)
+	ctx.Recorder.Eventf(ctx.RolloutRun, corev1.EventTypeNormal, ReasonRolloutRunCancelRequested,
+		"cancellation is requested by %s, %s", cancelRequestedBy(cancellation), cancellation.Message)
+}
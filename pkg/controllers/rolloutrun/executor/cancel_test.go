@@ -0,0 +1,189 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutapis "kusionstack.io/rollout/apis/rollout"
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+func Test_observeCancel(t *testing.T) {
+	tests := []struct {
+		name          string
+		canary        *rolloutv1alpha1.RolloutRunStepStatus
+		phase         rolloutv1alpha1.RolloutRunPhase
+		err           bool
+		cancellation  *rolloutv1alpha1.RolloutRunCancellationStatus
+		wantObserved  bool
+		wantPhase     rolloutv1alpha1.RolloutRunPhase
+		wantState     rolloutv1alpha1.RolloutStepState
+		wantErrExists bool
+	}{
+		{
+			name:         "canary running",
+			canary:       &rolloutv1alpha1.RolloutRunStepStatus{State: StepRunning},
+			wantObserved: true,
+			wantPhase:    rolloutv1alpha1.RolloutRunPhaseProgressing,
+			wantState:    StepResourceRecycling,
+		},
+		{
+			name:         "canary holding and paused",
+			canary:       &rolloutv1alpha1.RolloutRunStepStatus{State: StepHolding, Hold: &rolloutv1alpha1.CanaryHoldStatus{}},
+			phase:        rolloutv1alpha1.RolloutRunPhasePaused,
+			wantObserved: true,
+			wantPhase:    rolloutv1alpha1.RolloutRunPhaseProgressing,
+			wantState:    StepResourceRecycling,
+		},
+		{
+			name:         "canary failed",
+			canary:       &rolloutv1alpha1.RolloutRunStepStatus{State: StepRunning},
+			err:          true,
+			wantObserved: true,
+			wantPhase:    rolloutv1alpha1.RolloutRunPhaseProgressing,
+			wantState:    StepResourceRecycling,
+		},
+		{
+			name:          "canary recycling failed",
+			canary:        &rolloutv1alpha1.RolloutRunStepStatus{State: StepResourceRecycling},
+			err:           true,
+			wantObserved:  true,
+			wantPhase:     rolloutv1alpha1.RolloutRunPhaseProgressing,
+			wantState:     StepResourceRecycling,
+			wantErrExists: true,
+		},
+		{
+			name:         "canary not started",
+			phase:        rolloutv1alpha1.RolloutRunPhasePreRollout,
+			wantObserved: true,
+			wantPhase:    rolloutv1alpha1.RolloutRunPhaseCanceling,
+		},
+		{
+			name:         "canary succeeded",
+			canary:       &rolloutv1alpha1.RolloutRunStepStatus{State: StepSucceeded},
+			err:          true,
+			wantObserved: true,
+			wantPhase:    rolloutv1alpha1.RolloutRunPhaseCanceling,
+			wantState:    StepSucceeded,
+		},
+		{
+			name:      "completed",
+			canary:    &rolloutv1alpha1.RolloutRunStepStatus{State: StepSucceeded},
+			phase:     rolloutv1alpha1.RolloutRunPhaseSucceeded,
+			wantPhase: rolloutv1alpha1.RolloutRunPhaseSucceeded,
+			wantState: StepSucceeded,
+		},
+		{
+			name:         "already observed",
+			canary:       &rolloutv1alpha1.RolloutRunStepStatus{State: StepRunning},
+			cancellation: &rolloutv1alpha1.RolloutRunCancellationStatus{RequestedBy: "bob", RequestTime: ptr.To(metav1.Now())},
+			wantObserved: true,
+			wantPhase:    rolloutv1alpha1.RolloutRunPhaseProgressing,
+			wantState:    StepRunning,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rolloutRun := testCanaryRolloutRun.DeepCopy()
+			rolloutRun.Spec.Cancel = true
+			rolloutRun.Annotations[rolloutapis.AnnoManualCommandBy] = "alice"
+			rolloutRun.Status.Phase = rolloutv1alpha1.RolloutRunPhaseProgressing
+			if len(tt.phase) > 0 {
+				rolloutRun.Status.Phase = tt.phase
+			}
+			rolloutRun.Status.CanaryStatus = tt.canary.DeepCopy()
+			rolloutRun.Status.Cancellation = tt.cancellation.DeepCopy()
+			if tt.err {
+				rolloutRun.Status.Error = &rolloutv1alpha1.CodeReasonMessage{Code: "DoCanaryError"}
+			}
+			ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+			ctx.Initialize()
+
+			now := time.Now()
+			observeCancel(ctx, now)
+
+			assert.Equal(t, tt.wantObserved, isCancelRequested(ctx))
+			assert.Equal(t, tt.wantPhase, ctx.NewStatus.Phase)
+			assert.Equal(t, tt.wantErrExists, ctx.NewStatus.Error != nil)
+			if tt.canary != nil {
+				assert.Equal(t, tt.wantState, ctx.NewStatus.CanaryStatus.State)
+			}
+			if tt.cancellation != nil {
+				assert.Equal(t, tt.cancellation.RequestedBy, ctx.NewStatus.Cancellation.RequestedBy)
+			} else if tt.wantObserved {
+				assert.Equal(t, "alice", ctx.NewStatus.Cancellation.RequestedBy)
+				assert.Equal(t, now.Unix(), ctx.NewStatus.Cancellation.RequestTime.Unix())
+				assert.NotEmpty(t, ctx.NewStatus.Cancellation.Message)
+			}
+		})
+	}
+}
+
+func Test_observeCancel_ReducedStates(t *testing.T) {
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	// admitted before ResourceRecycling was required
+	rolloutRun.Spec.Canary.States = []rolloutv1alpha1.RolloutStepState{
+		StepPending,
+		StepRunning,
+		StepSucceeded,
+	}
+	rolloutRun.Spec.Cancel = true
+	rolloutRun.Status.Phase = rolloutv1alpha1.RolloutRunPhaseProgressing
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{State: StepRunning}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+	ctx.Initialize()
+
+	observeCancel(ctx, time.Now())
+	assert.Equal(t, StepResourceRecycling, ctx.NewStatus.CanaryStatus.State)
+
+	// the canary is recycled instead of failing with unknown state
+	e := newCanaryExecutor(newFakeWebhookExecutor())
+	_, _, err := e.stateMachineOf(ctx.RolloutRun).do(ctx, StepResourceRecycling)
+	assert.NoError(t, err)
+	if ctx.NewStatus.Error != nil {
+		assert.NotEqual(t, "UnknownStepState", ctx.NewStatus.Error.Reason)
+	}
+}
+
+func TestExecutor_Do_Cancel(t *testing.T) {
+	rolloutRun := testRolloutRun.DeepCopy()
+	rolloutRun.Spec.Cancel = true
+	rolloutRun.Status.Phase = rolloutv1alpha1.RolloutRunPhaseProgressing
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+
+	r := NewDefaultExecutor(newTestLogger())
+	done, _, err := r.Do(ctx)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, rolloutv1alpha1.RolloutRunPhaseCanceled, ctx.NewStatus.Phase)
+	if assert.NotNil(t, ctx.NewStatus.Cancellation) {
+		assert.Empty(t, ctx.NewStatus.Cancellation.RequestedBy)
+	}
+
+	// unsetting it does not revoke the cancellation
+	ctx.RolloutRun.Spec.Cancel = false
+	done, _, err = r.Do(ctx)
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, rolloutv1alpha1.RolloutRunPhaseCanceled, ctx.NewStatus.Phase)
+}
@@ -18,6 +18,7 @@ package executor
 
 import (
 	"context"
+	"fmt"
 	"sync"
 
 	"github.com/go-logr/logr"
@@ -27,6 +28,7 @@ import (
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	rolloutapi "kusionstack.io/rollout/apis/rollout"
 	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
 	"kusionstack.io/rollout/pkg/controllers/rolloutrun/traffic"
 	"kusionstack.io/rollout/pkg/workload"
@@ -46,6 +48,11 @@ type ExecutorContext struct {
 	NewStatus      *rolloutv1alpha1.RolloutRunStatus
 	Workloads      *workload.Set
 	TrafficManager *traffic.Manager
+	// TraceID is the W3C trace id of RolloutRun, it is propagated to webhooks
+	// and attached to logs and events. It is derived from RolloutRun if empty.
+	TraceID string
+	// Retry is the requeue intervals of steps, DefaultRetryOptions is used if empty.
+	Retry RetryOptions
 }
 
 func (c *ExecutorContext) Initialize() {
@@ -55,6 +62,16 @@ func (c *ExecutorContext) Initialize() {
 		}
 		newStatus := c.NewStatus
 
+		if len(c.TraceID) == 0 {
+			c.TraceID = traceIDOf(c.RolloutRun)
+		}
+		if c.Retry == (RetryOptions{}) {
+			c.Retry = DefaultRetryOptions()
+		}
+		if c.Recorder != nil {
+			c.Recorder = &traceEventRecorder{EventRecorder: c.Recorder, traceID: c.TraceID}
+		}
+
 		if len(newStatus.Phase) == 0 {
 			newStatus.Phase = rolloutv1alpha1.RolloutRunPhaseInitial
 		}
@@ -155,8 +172,11 @@ func (c *ExecutorContext) MoveToNextState(nextState rolloutv1alpha1.RolloutStepS
 
 	newStatus := c.NewStatus
 	if c.inCanary() {
+		prevState := newStatus.CanaryStatus.State
 		newStatus.CanaryStatus.State = nextState
-		if nextState == StepPreCanaryStepHook {
+		// canary starts once it leaves Pending, the pre canary step hook may be
+		// left out of canary states
+		if prevState == StepPending {
 			newStatus.CanaryStatus.StartTime = ptr.To(metav1.Now())
 		} else if isFinalStepState(nextState) {
 			newStatus.CanaryStatus.FinishTime = ptr.To(metav1.Now())
@@ -201,6 +221,69 @@ func (c *ExecutorContext) SkipCurrentRelease() {
 	}
 }
 
+// RestartCurrentStep moves the current step back to its entry state and clears
+// the progress recorded in step status, so that the step is re-attempted from
+// the start. All step states are idempotent, which makes it safe to re-run them.
+// The pre-run hook is not re-run since the rolloutRun has already passed it.
+func (c *ExecutorContext) RestartCurrentStep() {
+	c.Initialize()
+
+	newStatus := c.NewStatus
+	if c.inCanary() {
+		status := newStatus.CanaryStatus
+		if status.State == StepNone || status.State == StepPreRunHook {
+			return
+		}
+		status.State = StepPending
+		resetStepProgress(status)
+	} else {
+		index := newStatus.BatchStatus.CurrentBatchIndex
+		newStatus.BatchStatus.CurrentBatchState = StepNone
+		newStatus.BatchStatus.Records[index].State = StepNone
+		resetStepProgress(&newStatus.BatchStatus.Records[index])
+	}
+}
+
+// resetStepProgress clears the progress of step. The durable records which
+// span the whole step, e.g. the active deadline and unreachable clusters, are
+// kept.
+func resetStepProgress(status *rolloutv1alpha1.RolloutRunStepStatus) {
+	status.Webhooks = lo.Filter(status.Webhooks, func(w rolloutv1alpha1.RolloutWebhookStatus, _ int) bool {
+		return w.HookType == rolloutv1alpha1.PreRunHook
+	})
+	status.FinishTime = nil
+	status.Targets = nil
+	status.SessionDrain = nil
+	status.Retention = nil
+	status.TrafficProbe = nil
+	status.TrafficVerifications = nil
+	status.TrafficSplit = nil
+	status.TargetStates = nil
+	status.TargetReadiness = nil
+	status.ReadinessStabilization = nil
+	status.HealthCheckStartTime = nil
+	status.PodFailures = nil
+	status.RevertRamp = nil
+	status.PausedTraffic = nil
+	status.ReplicaCoupling = nil
+	status.CanaryReplicas = nil
+	status.RecycleVerification = nil
+	status.Bake = nil
+	status.GuardViolation = nil
+	status.ScaleUp = nil
+	status.WarmUp = nil
+	status.Preconditions = nil
+	status.Prerequisites = nil
+	status.Drift = nil
+	if status.TrafficHooks != nil {
+		// traffic hooks are run again, the revert hook is still owed
+		status.TrafficHooks = &rolloutv1alpha1.CanaryTrafficHooksStatus{}
+	}
+	status.PausedBefore = ""
+	status.PauseCheckpoints = nil
+	status.AutoContinue = false
+}
+
 func (c *ExecutorContext) Pause() {
 	c.Initialize()
 	c.NewStatus.Phase = rolloutv1alpha1.RolloutRunPhasePaused
@@ -208,16 +291,7 @@ func (c *ExecutorContext) Pause() {
 
 func (c *ExecutorContext) Fail(err error) {
 	c.Initialize()
-	crm, ok := err.(*rolloutv1alpha1.CodeReasonMessage)
-	if ok {
-		c.NewStatus.Error = crm
-	} else {
-		c.NewStatus.Error = &rolloutv1alpha1.CodeReasonMessage{
-			Code:    "Error",
-			Reason:  "ExecutorFailed",
-			Message: err.Error(),
-		}
-	}
+	c.NewStatus.Error = codeReasonMessageOf(err)
 }
 
 func (c *ExecutorContext) MoveToNextStateIfMatch(curState, nextState rolloutv1alpha1.RolloutStepState) {
@@ -283,25 +357,40 @@ func (r *ExecutorContext) makeRolloutWebhookReview(hookType rolloutv1alpha1.Hook
 
 	if r.inCanary() {
 		review.Spec.Canary = &rolloutv1alpha1.RolloutWebhookReviewCanary{
-			Targets:    rolloutRun.Spec.Canary.Targets,
+			Targets:    excludeRejectedTargets(r, rolloutRun.Spec.Canary.Targets),
 			Properties: rolloutRun.Spec.Canary.Properties,
 		}
+		if hookType == rolloutv1alpha1.CanaryCompletedHook {
+			review.Spec.Canary.Completion = newStatus.CanaryStatus.Completion.DeepCopy()
+		}
 	} else {
 		review.Spec.Batch = &rolloutv1alpha1.RolloutWebhookReviewBatch{
 			BatchIndex: newStatus.BatchStatus.CurrentBatchIndex,
-			Targets:    rolloutRun.Spec.Batch.Batches[newStatus.BatchStatus.CurrentBatchIndex].Targets,
+			Targets:    excludeRejectedTargets(r, rolloutRun.Spec.Batch.Batches[newStatus.BatchStatus.CurrentBatchIndex].Targets),
 			Properties: rolloutRun.Spec.Batch.Batches[newStatus.BatchStatus.CurrentBatchIndex].Properties,
 		}
 	}
 
+	// each webhook call in a step is a span of the RolloutRun trace
+	span := fmt.Sprintf("%s/%s", hookType, webhook.Name)
+	if review.Spec.Batch != nil {
+		span = fmt.Sprintf("%s/%d", span, review.Spec.Batch.BatchIndex)
+	}
+	review.Annotations = map[string]string{
+		rolloutapi.AnnoTraceParent: traceParent(r.TraceID, span),
+	}
+
 	return review
 }
 
 func (e *ExecutorContext) WithLogger(logger logr.Logger) logr.Logger {
+	e.Initialize()
+	logger = withVerbosityOverride(logger, e.RolloutRun)
 	l := logger.WithValues(
 		"namespace", e.RolloutRun.Namespace,
 		"rollout", e.OwnerName,
 		"rolloutRun", e.RolloutRun.Name,
+		"traceID", e.TraceID,
 	)
 
 	e.Context = logr.NewContext(e.Context, l)
@@ -20,6 +20,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
 )
 
 func TestExecutorContext_SkipCurrentRelease(t *testing.T) {
@@ -51,3 +53,46 @@ func TestExecutorContext_SkipCurrentRelease(t *testing.T) {
 		}
 	}
 }
+
+func TestExecutorContext_RestartCurrentStep(t *testing.T) {
+	ror := testCanaryRolloutRun.DeepCopy()
+	ror.Spec.Batch.Batches = []rolloutv1alpha1.RolloutRunStep{{}, {}}
+	ror.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{
+		State: StepRunning,
+		Webhooks: []rolloutv1alpha1.RolloutWebhookStatus{
+			{HookType: rolloutv1alpha1.PreRunHook, Name: "gate", State: rolloutv1alpha1.WebhookCompleted},
+			{HookType: rolloutv1alpha1.PreCanaryStepHook, Name: "pre", State: rolloutv1alpha1.WebhookCompleted},
+		},
+		TrafficProbe:        &rolloutv1alpha1.TrafficProbeStatus{Message: "failed"},
+		UnreachableClusters: []rolloutv1alpha1.UnreachableClusterStatus{{Cluster: "cluster-a"}},
+	}
+	ctx := ExecutorContext{
+		RolloutRun: ror,
+	}
+
+	// restart canary step
+	ctx.RestartCurrentStep()
+	canaryStatus := ctx.NewStatus.CanaryStatus
+	assert.Equal(t, StepPending, canaryStatus.State)
+	if assert.Len(t, canaryStatus.Webhooks, 1) {
+		assert.Equal(t, rolloutv1alpha1.PreRunHook, canaryStatus.Webhooks[0].HookType)
+	}
+	assert.Nil(t, canaryStatus.TrafficProbe)
+	assert.Len(t, canaryStatus.UnreachableClusters, 1)
+
+	// restart batch step
+	canaryStatus.State = StepSucceeded
+	ctx.NewStatus.BatchStatus.CurrentBatchIndex = 1
+	ctx.NewStatus.BatchStatus.CurrentBatchState = StepPostBatchStepHook
+	ctx.NewStatus.BatchStatus.Records[1].State = StepPostBatchStepHook
+	ctx.NewStatus.BatchStatus.Records[1].Webhooks = []rolloutv1alpha1.RolloutWebhookStatus{
+		{HookType: rolloutv1alpha1.PostBatchStepHook, Name: "post"},
+	}
+	ctx.RestartCurrentStep()
+
+	batchStatus := ctx.NewStatus.BatchStatus
+	assert.EqualValues(t, 1, batchStatus.CurrentBatchIndex)
+	assert.Equal(t, StepNone, batchStatus.CurrentBatchState)
+	assert.Equal(t, StepNone, batchStatus.Records[1].State)
+	assert.Empty(t, batchStatus.Records[1].Webhooks)
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	rolloutapis "kusionstack.io/rollout/apis/rollout"
@@ -11,31 +12,138 @@ import (
 	"kusionstack.io/rollout/pkg/utils"
 )
 
+// ResultDecorator adjusts the result returned from Executor.Do for the step
+// being executed, step is canary or batch and state is its current state. It
+// only changes when the rolloutRun is requeued, the done and error returned
+// from Do, and so the terminal semantics, can not be changed by it.
+type ResultDecorator func(step string, state rolloutv1alpha1.RolloutStepState, result ctrl.Result) ctrl.Result
+
 type Executor struct {
 	logger logr.Logger
+	retry  RetryOptions
 	canary *canaryExecutor
 	batch  *batchExecutor
+	// events deduplicates the repeated events of rolloutRuns across reconciles.
+	events *eventDeduplicator
+	// decorateResult adjusts the result of Do, nil means identity.
+	decorateResult ResultDecorator
 }
 
 func NewDefaultExecutor(logger logr.Logger) *Executor {
+	return NewExecutor(logger, DefaultRetryOptions())
+}
+
+// NewExecutor returns an Executor requeuing with the given retry intervals.
+func NewExecutor(logger logr.Logger, retry RetryOptions) *Executor {
 	webhookExec := newWebhookExecutor(time.Second)
 	canaryExec := newCanaryExecutor(webhookExec)
 	batchExec := newBatchExecutor(webhookExec)
 	e := &Executor{
 		logger: logger,
+		retry:  retry,
 		canary: canaryExec,
 		batch:  batchExec,
+		events: newEventDeduplicator(),
 	}
 	return e
 }
 
-// Do execute the lifecycle for rollout run, and will return new status
+// WithCanaryInheritedMetadataKeys sets the keys of rolloutRun labels and
+// annotations inherited by canary pods.
+func (r *Executor) WithCanaryInheritedMetadataKeys(keys []string) *Executor {
+	r.canary.inheritedMetadataKeys = keys
+	return r
+}
+
+// WithGlobalPauseConfigMap sets the ConfigMap pausing all canaries, e.g. during
+// a cluster-wide incident. Empty name means global pause is disabled.
+func (r *Executor) WithGlobalPauseConfigMap(key types.NamespacedName) *Executor {
+	r.canary.globalPause = key
+	return r
+}
+
+// WithCanaryMaxTrafficWeight sets the maximum weight of canary traffic, the
+// weights exceeding it are clamped regardless of RolloutRuns, e.g. for platform
+// admins to limit the risk of canaries. Zero means unlimited.
+func (r *Executor) WithCanaryMaxTrafficWeight(weight int32) *Executor {
+	r.canary.maxTrafficWeight = weight
+	return r
+}
+
+// WithResultDecorator sets the decorator adjusting the result of Do, e.g. for
+// embedders to requeue their custom steps at a specific interval.
+func (r *Executor) WithResultDecorator(decorator ResultDecorator) *Executor {
+	r.decorateResult = decorator
+	return r
+}
+
+// CanaryStepGraph returns the canary step state machine graph of rolloutRun,
+// which honors the canary states declared in it. The current state is read
+// from its status.
+func (r *Executor) CanaryStepGraph(rolloutRun *rolloutv1alpha1.RolloutRun) StepGraph {
+	var current rolloutv1alpha1.RolloutStepState
+	if rolloutRun.Status.CanaryStatus != nil {
+		current = rolloutRun.Status.CanaryStatus.State
+	}
+	if rolloutRun.Spec.Canary == nil {
+		return r.canary.stateMachine.graph(current)
+	}
+	return r.canary.stepGraph(rolloutRun, current)
+}
+
+// BatchStepGraph returns the batch step state machine graph, the current
+// state is read from the given status.
+func (r *Executor) BatchStepGraph(status *rolloutv1alpha1.RolloutRunStatus) StepGraph {
+	var current rolloutv1alpha1.RolloutStepState
+	if status != nil && status.BatchStatus != nil {
+		current = status.BatchStatus.CurrentBatchState
+	}
+	return r.batch.stateMachine.graph(current)
+}
+
+// Forget drops the states kept across reconciles for rolloutRun, it should be
+// called once the rolloutRun is deleted or not found.
+func (r *Executor) Forget(key types.NamespacedName) {
+	r.events.forget(key)
+}
+
+// Do execute the lifecycle for rollout run, and will return new status. The
+// returned error always carries a CodeReasonMessage, it can be classified by
+// errors.As.
 func (r *Executor) Do(ctx *ExecutorContext) (bool, ctrl.Result, error) {
+	done, result, err := r.do(ctx)
+	if done {
+		r.events.forget(types.NamespacedName{Namespace: ctx.RolloutRun.Namespace, Name: ctx.RolloutRun.Name})
+	}
+	if r.decorateResult != nil {
+		step, state := ctx.GetCurrentState()
+		result = r.decorateResult(step, state, result)
+	}
+	return done, result, withCodeReasonMessage(err)
+}
+
+func (r *Executor) do(ctx *ExecutorContext) (bool, ctrl.Result, error) {
+	if ctx.Retry == (RetryOptions{}) {
+		ctx.Retry = r.retry
+	}
 	// init NewStatus
 	ctx.Initialize()
+	if _, ok := ctx.Recorder.(*dedupEventRecorder); !ok && ctx.Recorder != nil {
+		ctx.Recorder = &dedupEventRecorder{EventRecorder: ctx.Recorder, dedup: r.events, ctx: ctx}
+	}
 
 	logger := ctx.WithLogger(r.logger)
 
+	// record the canary notifications delivered since last reconcile
+	r.canary.notifier.record(ctx)
+
+	// substitute parameters before the spec is read
+	if err := resolveParameters(ctx); err != nil {
+		logger.Error(err, "failed to resolve parameters")
+		ctx.Fail(err)
+		return false, ctrl.Result{}, nil
+	}
+
 	newStatus := ctx.NewStatus
 	rolloutRun := ctx.RolloutRun
 	prePhase := newStatus.Phase
@@ -52,20 +160,54 @@ func (r *Executor) Do(ctx *ExecutorContext) (bool, ctrl.Result, error) {
 		return false, ctrl.Result{Requeue: true}, nil
 	}
 
+	// spec.cancel rolls back the canary in progress before canceling
+	observeCancel(ctx, time.Now())
+
 	// if command exist, do command
 	if _, exist := utils.GetMapValue(rolloutRun.Annotations, rolloutapis.AnnoManualCommandKey); exist {
 		return false, r.doCommand(ctx), nil
 	}
 
-	// if paused, do nothing
+	// if paused, do nothing but keeping canary traffic in place
 	if newStatus.Phase == rolloutv1alpha1.RolloutRunPhasePaused {
 		logger.V(2).Info("rolloutRun is paused, do nothing")
-		return false, ctrl.Result{}, nil
+		if !ctx.inCanary() {
+			return false, ctrl.Result{}, nil
+		}
+		if newStatus.CanaryStatus != nil && !isRollbackBudgetExhausted(newStatus.CanaryStatus) {
+			newStatus.CanaryStatus.WaitingReason = rolloutv1alpha1.StepPaused
+		}
+		// paused canary still counts towards its max active duration
+		next := r.canary.checkActiveDeadline(ctx, time.Now())
+		if newStatus.Phase != rolloutv1alpha1.RolloutRunPhasePaused {
+			return false, ctrl.Result{Requeue: true}, nil
+		}
+		result := requeueBefore(ctrl.Result{}, next)
+		if isCanaryTrafficPaused(ctx) {
+			r.canary.setupTrafficManager(ctx)
+			retry, err := r.canary.reducePausedTraffic(ctx)
+			if err != nil {
+				return false, ctrl.Result{}, err
+			}
+			result = requeueBefore(result, retry)
+		} else if r.canary.shouldResyncTraffic(ctx) {
+			r.canary.setupTrafficManager(ctx)
+			if err := r.canary.resyncTraffic(ctx); err != nil {
+				return false, ctrl.Result{}, err
+			}
+			result = requeueBefore(result, ctx.Retry.TrafficResync)
+		}
+		return false, result, nil
 	}
 
 	// if batchError exist, do nothing
 	if newStatus.Error != nil {
 		logger.V(2).Info("rolloutRun.status has error, do nothing")
+		if ctx.inCanary() {
+			// canary failed with a terminal error still notifies its completion
+			result, err := r.canary.notifyFailure(ctx)
+			return false, result, err
+		}
 		return false, ctrl.Result{}, nil
 	}
 
@@ -1,6 +1,8 @@
 package executor
 
 import (
+	"time"
+
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	rolloutapis "kusionstack.io/rollout/apis/rollout"
@@ -27,11 +29,28 @@ func (r *Executor) doCommand(ctx *ExecutorContext) ctrl.Result {
 	case rolloutapis.AnnoManualCommandRetry:
 		if batchError != nil {
 			newStatus.Error = nil
+			resetCanaryCompletion(ctx)
+		}
+	case rolloutapis.AnnoManualCommandRestartStep:
+		if batchError != nil {
+			newStatus.Error = nil
+			resetCanaryCompletion(ctx)
+			ctx.RestartCurrentStep()
 		}
 	case rolloutapis.AnnoManualCommandPause:
 		newStatus.Phase = rolloutv1alpha1.RolloutRunPhasePausing
 	case rolloutapis.AnnoManualCommandCancel:
 		newStatus.Phase = rolloutv1alpha1.RolloutRunPhaseCanceling
+	case rolloutapis.AnnoManualCommandPromote:
+		endCanaryHold(ctx, rolloutv1alpha1.CanaryHoldPromote)
+	case rolloutapis.AnnoManualCommandEnd:
+		endCanaryHold(ctx, rolloutv1alpha1.CanaryHoldEnd)
+	case rolloutapis.AnnoManualCommandPromoteNow:
+		promoteCanaryNow(ctx, rolloutRun.Annotations[rolloutapis.AnnoManualCommandBy], time.Now())
+	case rolloutapis.AnnoManualCommandResetRollbackBudget:
+		resetRollbackBudget(ctx, time.Now())
+	case rolloutapis.AnnoManualCommandEndRetention:
+		endCanaryRetention(ctx, rolloutRun.Annotations[rolloutapis.AnnoManualCommandBy], time.Now())
 	case rolloutapis.AnnoManualCommandSkip:
 		if batchError != nil {
 			newStatus.Error = nil
@@ -1,13 +1,20 @@
 package executor
 
 import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
 	"time"
 
 	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/utils/ptr"
 
 	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
 	"kusionstack.io/rollout/pkg/controllers/rolloutrun/webhook"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/webhook/probe"
 	"kusionstack.io/rollout/pkg/utils"
 )
 
@@ -17,6 +24,10 @@ const (
 	ReasonWebhookFailurePolicyInvalid     = "WebhookFailurePolicyInvalid"
 	ReasonWebhookReviewStatusCodeUnknown  = "WebhookReviewStatusCodeUnknown"
 	ReasonWebhookFailureThresholdExceeded = "WebhookFailureThresholdExceeded"
+	ReasonWebhookUnreachable              = "WebhookUnreachable"
+	ReasonPreRunHookRejected              = "PreRunHookRejected"
+	ReasonWebhookTargetRejected           = "WebhookTargetRejected"
+	ReasonWebhookAllTargetsRejected       = "WebhookAllTargetsRejected"
 )
 
 type webhookExecutor interface {
@@ -41,22 +52,66 @@ func (r *webhookExecutorImpl) Do(ctx *ExecutorContext, hookType rolloutv1alpha1.
 		return true, retryImmediately, nil
 	}
 
+	if curWebhook.TargetMode == rolloutv1alpha1.WebhookPerTarget {
+		return r.doPerTarget(ctx, hookType, curWebhook, nextWebhook)
+	}
+
 	logger := ctx.GetLogger()
 	logger.Info("processing webhook", "hookType", hookType, "webhook", curWebhook.Name)
 
-	hookResult, _, err := r.startOrGetWebhookWorker(ctx, hookType, *curWebhook.RolloutWebhook, curWebhook.status)
-	if err != nil {
-		logger.Error(err, "failed to get webhook result")
-		return false, retryImmediately, err
+	review := ctx.makeRolloutWebhookReview(hookType, *curWebhook.RolloutWebhook)
+	payloadHash := webhookPayloadHash(review)
+
+	hookResult, cached := cachedWebhookResult(curWebhook.status, payloadHash)
+	if cached {
+		logger.V(2).Info("webhook is completed with the same payload, skip invoking it", "hookType", hookType, "webhook", curWebhook.Name)
+	} else {
+		var err error
+		hookResult, _, err = r.startOrGetWebhookWorker(ctx, hookType, *curWebhook.RolloutWebhook, review, curWebhook.status)
+		if err != nil {
+			logger.Error(err, "failed to get webhook result")
+			return false, retryImmediately, err
+		}
+		hookResult.PayloadHash = payloadHash
 	}
 
 	logger.V(2).Info("get webhook result", "hookType", hookType, "webhook", curWebhook.Name, "result", hookResult)
 
+	unreachable := hookResult.Code == rolloutv1alpha1.WebhookReviewCodeError && hookResult.Reason == probe.ReasonUnreachable
+	if unreachable && !cached && hookResult.State == rolloutv1alpha1.WebhookCompleted {
+		// unreachable webhook is ignored, leave a note in status
+		hookResult.Message = "webhook is unreachable and ignored, " + hookResult.Message
+		ctx.Recorder.Eventf(ctx.RolloutRun, corev1.EventTypeWarning, ReasonWebhookUnreachable, "%s webhook %s is unreachable and ignored: %s", hookType, curWebhook.Name, hookResult.Message)
+	}
+
+	if hookType == rolloutv1alpha1.CanaryCompletedHook && hookResult.State == rolloutv1alpha1.WebhookOnHold {
+		// completed hook only notifies, give it up after failureThreshold retries
+		hookResult.State = rolloutv1alpha1.WebhookCompleted
+		logger.Info("canary completed hook failed and is ignored", "webhook", curWebhook.Name, "reason", hookResult.Reason)
+		ctx.Recorder.Eventf(ctx.RolloutRun, corev1.EventTypeWarning, ReasonCanaryCompletedHookFailed, "%s webhook %s failed %d times and is ignored: %s", hookType, curWebhook.Name, hookResult.FailureCount, hookResult.Message)
+	}
+
 	// shorten long message
 	hookResult.Message = utils.Abbreviate(hookResult.Message, 1024)
 
 	ctx.SetWebhookStatus(rolloutv1alpha1.RolloutWebhookStatus(*hookResult))
 
+	if unreachable && hookResult.State == rolloutv1alpha1.WebhookOnHold && curWebhook.UnreachablePolicy == rolloutv1alpha1.Fail {
+		return false, retryStop, r.failUnreachable(ctx, hookType, curWebhook.Name, hookResult)
+	}
+
+	if hookType == rolloutv1alpha1.PreRunHook &&
+		hookResult.State == rolloutv1alpha1.WebhookOnHold &&
+		hookResult.Code == rolloutv1alpha1.WebhookReviewCodeError {
+		// nothing is changed before PreRunHook, cancel the rolloutRun instead of
+		// waiting for retry
+		r.webhookManager.Stop(ctx.RolloutRun.UID)
+		logger.Info("rolloutRun is rejected by webhook, cancel it", "hookType", hookType, "webhook", curWebhook.Name, "reason", hookResult.Reason)
+		ctx.Recorder.Eventf(ctx.RolloutRun, corev1.EventTypeWarning, ReasonPreRunHookRejected, "rolloutRun is canceled by %s webhook %s: %s", hookType, curWebhook.Name, hookResult.Message)
+		ctx.NewStatus.Phase = rolloutv1alpha1.RolloutRunPhaseCanceling
+		return false, retryImmediately, nil
+	}
+
 	if hookResult.State == rolloutv1alpha1.WebhookOnHold &&
 		hookResult.Code == rolloutv1alpha1.WebhookReviewCodeError &&
 		ctx.NewStatus.Error == nil {
@@ -68,6 +123,24 @@ func (r *webhookExecutorImpl) Do(ctx *ExecutorContext, hookType rolloutv1alpha1.
 		return false, retryDefault, nil
 	}
 
+	return r.completeWebhook(ctx, hookType, nextWebhook)
+}
+
+// failUnreachable stops the webhook and returns the terminal error of an
+// unreachable webhook with Fail policy.
+func (r *webhookExecutorImpl) failUnreachable(ctx *ExecutorContext, hookType rolloutv1alpha1.HookType, name string, hookResult *webhook.Result) error {
+	r.webhookManager.Stop(ctx.RolloutRun.UID)
+	return control.TerminalError(&rolloutv1alpha1.CodeReasonMessage{
+		Code:    ReasonWebhookUnreachable,
+		Reason:  hookResult.Reason,
+		Message: fmt.Sprintf("%s webhook %s is unreachable: %s", hookType, name, hookResult.Message),
+	})
+}
+
+// completeWebhook starts the next webhook, or cleans up if all webhooks of
+// hookType are completed.
+func (r *webhookExecutorImpl) completeWebhook(ctx *ExecutorContext, hookType rolloutv1alpha1.HookType, nextWebhook *rolloutv1alpha1.RolloutWebhook) (bool, time.Duration, error) {
+	logger := ctx.GetLogger()
 	if nextWebhook != nil {
 		// add empty status to start next webhook
 		ctx.SetWebhookStatus(rolloutv1alpha1.RolloutWebhookStatus{
@@ -126,15 +199,44 @@ func (r *webhookExecutorImpl) findCurrentAndNextWebhook(executorContext *Executo
 	return currentWebhook, next
 }
 
-func (r *webhookExecutorImpl) startOrGetWebhookWorker(ctx *ExecutorContext, hookType rolloutv1alpha1.HookType, webhookCfg rolloutv1alpha1.RolloutWebhook, lastStatus *rolloutv1alpha1.RolloutWebhookStatus) (*webhook.Result, bool, error) {
+// webhookPayloadHash returns the hash of webhook review payload.
+func webhookPayloadHash(review rolloutv1alpha1.RolloutWebhookReview) string {
+	hasher := fnv.New32a()
+	data, _ := json.Marshal(review) // nolint
+	hasher.Write(data)              // nolint
+	return rand.SafeEncodeString(fmt.Sprint(hasher.Sum32()))
+}
+
+// cachedWebhookResult returns the last result of webhook if it is completed
+// with the same payload.
+func cachedWebhookResult(lastStatus *rolloutv1alpha1.RolloutWebhookStatus, payloadHash string) (*webhook.Result, bool) {
+	if lastStatus == nil ||
+		lastStatus.State != rolloutv1alpha1.WebhookCompleted ||
+		len(lastStatus.PayloadHash) == 0 ||
+		lastStatus.PayloadHash != payloadHash {
+		return nil, false
+	}
+	return ptr.To(webhook.Result(*lastStatus)), true
+}
+
+func (r *webhookExecutorImpl) startOrGetWebhookWorker(ctx *ExecutorContext, hookType rolloutv1alpha1.HookType, webhookCfg rolloutv1alpha1.RolloutWebhook, review rolloutv1alpha1.RolloutWebhookReview, lastStatus *rolloutv1alpha1.RolloutWebhookStatus) (*webhook.Result, bool, error) {
 	run := ctx.RolloutRun
 	key := run.UID
 	logger := ctx.GetLogger()
+	cert, err := loadWebhookClientCert(ctx, webhookCfg.Name, webhookCfg.ClientConfig)
+	if err != nil {
+		return nil, false, err
+	}
 	worker, ok := r.webhookManager.Get(key)
 	if ok {
 		// webhook already started
 		curResult := worker.Result()
-		if curResult.Name == webhookCfg.Name && curResult.HookType == hookType {
+		// the webhook starts over if there is no last status, e.g. the step is restarted
+		if curResult.Name == webhookCfg.Name && curResult.HookType == hookType && lastStatus != nil {
+			// reload the client certificate if the Secret is changed
+			if err := worker.SetClientCert(cert); err != nil {
+				return nil, false, err
+			}
 			if lastStatus != nil && lastStatus.State == rolloutv1alpha1.WebhookOnHold {
 				// lastStatus is onHold, that means it should be retry
 				worker.Retry()
@@ -151,8 +253,7 @@ func (r *webhookExecutorImpl) startOrGetWebhookWorker(ctx *ExecutorContext, hook
 
 	logger.Info("start a new webhook worker and wait for the result for a brief period.", "webhook", webhookCfg.Name, "type", hookType)
 
-	review := ctx.makeRolloutWebhookReview(hookType, webhookCfg)
-	worker, err := r.webhookManager.Start(key, webhookCfg, review)
+	worker, err = r.webhookManager.Start(key, webhookCfg, review, cert)
 	if err != nil {
 		return nil, false, err
 	}
@@ -0,0 +1,182 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/webhook/probe"
+	"kusionstack.io/rollout/pkg/utils"
+)
+
+// doPerTarget calls the webhook in Target mode. The targets of step are called
+// one after another, the verdict of each target is recorded in webhook status.
+// With AllPass aggregation a failing target holds the step until it is retried
+// and passes, with PerTarget aggregation it is rejected and the next target is
+// called.
+func (r *webhookExecutorImpl) doPerTarget(ctx *ExecutorContext, hookType rolloutv1alpha1.HookType, curWebhook *webhookWithStatus, nextWebhook *rolloutv1alpha1.RolloutWebhook) (bool, time.Duration, error) {
+	logger := ctx.GetLogger()
+
+	status := rolloutv1alpha1.RolloutWebhookStatus{
+		HookType: hookType,
+		Name:     curWebhook.Name,
+		State:    rolloutv1alpha1.WebhookRunning,
+	}
+	if curWebhook.status != nil {
+		status = *curWebhook.status.DeepCopy()
+	}
+	if status.TargetVerdicts == nil {
+		status.TargetVerdicts = map[string]rolloutv1alpha1.WebhookTargetVerdict{}
+	}
+
+	targets := excludeRejectedTargets(ctx, stepTargets(ctx))
+	target, found := lo.Find(targets, func(t rolloutv1alpha1.RolloutRunStepTarget) bool {
+		return status.TargetVerdicts[t.CrossClusterObjectNameReference.String()] != rolloutv1alpha1.WebhookTargetPassed
+	})
+	if !found {
+		if len(targets) == 0 && len(status.TargetVerdicts) > 0 {
+			r.webhookManager.Stop(ctx.RolloutRun.UID)
+			return false, retryStop, control.TerminalError(&rolloutv1alpha1.CodeReasonMessage{
+				Code:    ReasonWebhookAllTargetsRejected,
+				Reason:  ReasonWebhookAllTargetsRejected,
+				Message: fmt.Sprintf("all targets are rejected by %s webhook %s", hookType, curWebhook.Name),
+			})
+		}
+		status.State = rolloutv1alpha1.WebhookCompleted
+		status.CodeReasonMessage = rolloutv1alpha1.CodeReasonMessage{
+			Code:    rolloutv1alpha1.WebhookReviewCodeOK,
+			Reason:  "TargetsPassed",
+			Message: fmt.Sprintf("%d targets passed", len(targets)),
+		}
+		ctx.SetWebhookStatus(status)
+		return r.completeWebhook(ctx, hookType, nextWebhook)
+	}
+
+	key := target.CrossClusterObjectNameReference.String()
+	logger.Info("processing webhook for target", "hookType", hookType, "webhook", curWebhook.Name, "target", key)
+
+	review := ctx.makeRolloutWebhookReview(hookType, *curWebhook.RolloutWebhook)
+	setWebhookReviewTargets(&review, []rolloutv1alpha1.RolloutRunStepTarget{target})
+
+	hookResult, _, err := r.startOrGetWebhookWorker(ctx, hookType, *curWebhook.RolloutWebhook, review, curWebhook.status)
+	if err != nil {
+		logger.Error(err, "failed to get webhook result")
+		return false, retryImmediately, err
+	}
+	logger.V(2).Info("get webhook result", "hookType", hookType, "webhook", curWebhook.Name, "target", key, "result", hookResult)
+
+	unreachable := hookResult.Code == rolloutv1alpha1.WebhookReviewCodeError && hookResult.Reason == probe.ReasonUnreachable
+	if unreachable && hookResult.State == rolloutv1alpha1.WebhookOnHold && curWebhook.UnreachablePolicy == rolloutv1alpha1.Fail {
+		return false, retryStop, r.failUnreachable(ctx, hookType, curWebhook.Name, hookResult)
+	}
+
+	status.CodeReasonMessage = hookResult.CodeReasonMessage
+	status.Message = utils.Abbreviate(fmt.Sprintf("target %s: %s", key, hookResult.Message), 1024)
+	status.FailureCount = hookResult.FailureCount
+
+	switch hookResult.State {
+	case rolloutv1alpha1.WebhookCompleted:
+		// the worker is stopped to start a new one for next target
+		r.webhookManager.Stop(ctx.RolloutRun.UID)
+		status.TargetVerdicts[key] = rolloutv1alpha1.WebhookTargetPassed
+		status.State = rolloutv1alpha1.WebhookRunning
+		ctx.SetWebhookStatus(status)
+		return false, retryImmediately, nil
+
+	case rolloutv1alpha1.WebhookOnHold:
+		status.TargetVerdicts[key] = rolloutv1alpha1.WebhookTargetFailed
+		if curWebhook.TargetAggregation != rolloutv1alpha1.WebhookPerTargetGating {
+			status.State = rolloutv1alpha1.WebhookOnHold
+			ctx.SetWebhookStatus(status)
+			if hookResult.Code == rolloutv1alpha1.WebhookReviewCodeError && ctx.NewStatus.Error == nil {
+				ctx.NewStatus.Error = &status.CodeReasonMessage
+			}
+			return false, retryDefault, nil
+		}
+
+		r.webhookManager.Stop(ctx.RolloutRun.UID)
+		rejectTarget(ctx, target, hookType, curWebhook.Name, utils.Abbreviate(hookResult.Message, 1024))
+		status.State = rolloutv1alpha1.WebhookRunning
+		ctx.SetWebhookStatus(status)
+		return false, retryImmediately, nil
+
+	default:
+		// the failed target is retried
+		delete(status.TargetVerdicts, key)
+		status.State = rolloutv1alpha1.WebhookRunning
+		ctx.SetWebhookStatus(status)
+		return false, retryDefault, nil
+	}
+}
+
+// stepTargets returns the targets of current step.
+func stepTargets(ctx *ExecutorContext) []rolloutv1alpha1.RolloutRunStepTarget {
+	if ctx.inCanary() {
+		return ctx.RolloutRun.Spec.Canary.Targets
+	}
+	return ctx.RolloutRun.Spec.Batch.Batches[ctx.NewStatus.BatchStatus.CurrentBatchIndex].Targets
+}
+
+func setWebhookReviewTargets(review *rolloutv1alpha1.RolloutWebhookReview, targets []rolloutv1alpha1.RolloutRunStepTarget) {
+	if review.Spec.Canary != nil {
+		review.Spec.Canary.Targets = targets
+	}
+	if review.Spec.Batch != nil {
+		review.Spec.Batch.Targets = targets
+	}
+}
+
+// rejectTarget records the target rejected by webhook, it is excluded from the
+// rest of rolloutRun.
+func rejectTarget(ctx *ExecutorContext, target rolloutv1alpha1.RolloutRunStepTarget, hookType rolloutv1alpha1.HookType, name, message string) {
+	if isRejectedTarget(ctx, target.CrossClusterObjectNameReference) {
+		return
+	}
+	ctx.NewStatus.RejectedTargets = append(ctx.NewStatus.RejectedTargets, rolloutv1alpha1.RejectedTargetStatus{
+		CrossClusterObjectNameReference: target.CrossClusterObjectNameReference,
+		HookType:                        hookType,
+		Webhook:                         name,
+		Message:                         message,
+	})
+	ctx.GetLogger().Info("target is rejected by webhook", "hookType", hookType, "webhook", name, "target", target.CrossClusterObjectNameReference)
+	ctx.Recorder.Eventf(ctx.RolloutRun, corev1.EventTypeWarning, ReasonWebhookTargetRejected, "target %s is rejected by %s webhook %s: %s", target.CrossClusterObjectNameReference, hookType, name, message)
+}
+
+// isRejectedTarget returns true if the target is rejected by any webhook of
+// the given hook types, or any webhook if no hook type is given.
+func isRejectedTarget(ctx *ExecutorContext, ref rolloutv1alpha1.CrossClusterObjectNameReference, hookTypes ...rolloutv1alpha1.HookType) bool {
+	return lo.ContainsBy(ctx.NewStatus.RejectedTargets, func(t rolloutv1alpha1.RejectedTargetStatus) bool {
+		return t.CrossClusterObjectNameReference == ref && (len(hookTypes) == 0 || lo.Contains(hookTypes, t.HookType))
+	})
+}
+
+// excludeRejectedTargets returns the targets not rejected by any webhook of
+// the given hook types, or any webhook if no hook type is given.
+func excludeRejectedTargets(ctx *ExecutorContext, targets []rolloutv1alpha1.RolloutRunStepTarget, hookTypes ...rolloutv1alpha1.HookType) []rolloutv1alpha1.RolloutRunStepTarget {
+	if len(ctx.NewStatus.RejectedTargets) == 0 {
+		return targets
+	}
+	return lo.Filter(targets, func(t rolloutv1alpha1.RolloutRunStepTarget, _ int) bool {
+		return !isRejectedTarget(ctx, t.CrossClusterObjectNameReference, hookTypes...)
+	})
+}
@@ -0,0 +1,141 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+)
+
+// newTargetWebhookServer returns a webhook server rejecting the given targets.
+func newTargetWebhookServer(rejected ...string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		review := rolloutv1alpha1.RolloutWebhookReview{}
+		_ = json.NewDecoder(r.Body).Decode(&review) // nolint
+		review.Status.Code = rolloutv1alpha1.WebhookReviewCodeOK
+		for _, target := range review.Spec.Canary.Targets {
+			for _, name := range rejected {
+				if target.Name == name {
+					review.Status.Code = rolloutv1alpha1.WebhookReviewCodeError
+					review.Status.Reason = "Rejected"
+					review.Status.Message = "rejected by test"
+				}
+			}
+		}
+		_ = json.NewEncoder(w).Encode(review) // nolint
+	}))
+}
+
+func runPerTargetWebhook(t *testing.T, aggregation rolloutv1alpha1.WebhookTargetAggregation, rejected ...string) (*ExecutorContext, bool, error) {
+	server := newTargetWebhookServer(rejected...)
+	defer server.Close()
+
+	exe := newWebhookExecutor(500 * time.Millisecond)
+	hookType := rolloutv1alpha1.PreCanaryStepHook
+	rollout := testRollout.DeepCopy()
+	rolloutRun := testRolloutRun.DeepCopy()
+	hook := webhook2.DeepCopy()
+	hook.ClientConfig.URL = server.URL
+	hook.TargetMode = rolloutv1alpha1.WebhookPerTarget
+	hook.TargetAggregation = aggregation
+	rolloutRun.Spec.Webhooks = []rolloutv1alpha1.RolloutWebhook{*hook}
+	rolloutRun.Spec.Canary = &rolloutv1alpha1.RolloutRunCanaryStrategy{
+		Targets: []rolloutv1alpha1.RolloutRunStepTarget{
+			newRunStepTarget("cluster-a", "test-1", intstr.FromInt(1)),
+			newRunStepTarget("cluster-b", "test-2", intstr.FromInt(1)),
+		},
+	}
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{
+		State: StepPreCanaryStepHook,
+	}
+
+	var ctx *ExecutorContext
+	var done bool
+	var err error
+	for i := 0; i < 5; i++ {
+		ctx = createTestExecutorContext(rollout, rolloutRun)
+		done, _, err = exe.Do(ctx, hookType)
+		if done || err != nil || ctx.NewStatus.Error != nil {
+			// the controller waits for manual retry on error
+			break
+		}
+		rolloutRun.Status = *ctx.NewStatus
+	}
+	return ctx, done, err
+}
+
+func Test_webhook_PerTargetGating(t *testing.T) {
+	ctx, done, err := runPerTargetWebhook(t, rolloutv1alpha1.WebhookPerTargetGating, "test-1")
+	assert.NoError(t, err)
+	assert.True(t, done)
+
+	if assert.Len(t, ctx.NewStatus.RejectedTargets, 1) {
+		rejected := ctx.NewStatus.RejectedTargets[0]
+		assert.Equal(t, "test-1", rejected.Name)
+		assert.Equal(t, rolloutv1alpha1.PreCanaryStepHook, rejected.HookType)
+		assert.Equal(t, webhook2.Name, rejected.Webhook)
+	}
+	if assert.Len(t, ctx.NewStatus.CanaryStatus.Webhooks, 1) {
+		status := ctx.NewStatus.CanaryStatus.Webhooks[0]
+		assert.Equal(t, rolloutv1alpha1.WebhookCompleted, status.State)
+		assert.Equal(t, map[string]rolloutv1alpha1.WebhookTargetVerdict{
+			"cluster=cluster-a,name=test-1": rolloutv1alpha1.WebhookTargetFailed,
+			"cluster=cluster-b,name=test-2": rolloutv1alpha1.WebhookTargetPassed,
+		}, status.TargetVerdicts)
+	}
+
+	// the rejected target is excluded from canary traffic and later webhooks
+	targets := nonDegradedCanaryTargets(ctx)
+	if assert.Len(t, targets, 1) {
+		assert.Equal(t, "test-2", targets[0].Name)
+	}
+	review := ctx.makeRolloutWebhookReview(rolloutv1alpha1.PostCanaryStepHook, webhook2)
+	assert.Len(t, review.Spec.Canary.Targets, 1)
+}
+
+func Test_webhook_PerTargetAllPass(t *testing.T) {
+	ctx, done, err := runPerTargetWebhook(t, rolloutv1alpha1.WebhookAllPass, "test-1")
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Empty(t, ctx.NewStatus.RejectedTargets)
+	assert.NotNil(t, ctx.NewStatus.Error)
+	if assert.Len(t, ctx.NewStatus.CanaryStatus.Webhooks, 1) {
+		status := ctx.NewStatus.CanaryStatus.Webhooks[0]
+		assert.Equal(t, rolloutv1alpha1.WebhookOnHold, status.State)
+		assert.Equal(t, map[string]rolloutv1alpha1.WebhookTargetVerdict{
+			"cluster=cluster-a,name=test-1": rolloutv1alpha1.WebhookTargetFailed,
+		}, status.TargetVerdicts)
+	}
+}
+
+func Test_webhook_PerTargetAllRejected(t *testing.T) {
+	ctx, done, err := runPerTargetWebhook(t, rolloutv1alpha1.WebhookPerTargetGating, "test-1", "test-2")
+	assert.False(t, done)
+	assert.True(t, errors.Is(err, control.TerminalError(nil)))
+	assert.ErrorContains(t, err, "all targets are rejected")
+	assert.Len(t, ctx.NewStatus.RejectedTargets, 2)
+}
@@ -112,11 +112,30 @@ func runWebhookTestCases(t *testing.T, hookType rolloutv1alpha1.HookType, tests
 			done, got, err := exe.Do(ctx, hookType)
 			assert := assert.New(t)
 			tt.assertResult(assert, done, got, err)
-			tt.assertStatus(assert, ctx.NewStatus)
+			tt.assertStatus(assert, clearWebhookPayloadHash(ctx.NewStatus))
 		})
 	}
 }
 
+// clearWebhookPayloadHash clears the payload hash in webhook statuses, which
+// is not concerned by the webhook result assertions.
+func clearWebhookPayloadHash(status *rolloutv1alpha1.RolloutRunStatus) *rolloutv1alpha1.RolloutRunStatus {
+	status = status.DeepCopy()
+	if status.CanaryStatus != nil {
+		for i := range status.CanaryStatus.Webhooks {
+			status.CanaryStatus.Webhooks[i].PayloadHash = ""
+		}
+	}
+	if status.BatchStatus != nil {
+		for i := range status.BatchStatus.Records {
+			for j := range status.BatchStatus.Records[i].Webhooks {
+				status.BatchStatus.Records[i].Webhooks[j].PayloadHash = ""
+			}
+		}
+	}
+	return status
+}
+
 func Test_webhook_Retry(t *testing.T) {
 	exe := newTestWebhookExecutor()
 
@@ -145,7 +164,7 @@ func Test_webhook_Retry(t *testing.T) {
 			Name:              webhook2.Name,
 			CodeReasonMessage: webhook2Error,
 			FailureCount:      1,
-		}, ctx.NewStatus.CanaryStatus.Webhooks[0])
+		}, clearWebhookPayloadHash(ctx.NewStatus).CanaryStatus.Webhooks[0])
 	}
 
 	// set new status
@@ -164,7 +183,7 @@ func Test_webhook_Retry(t *testing.T) {
 			Name:              webhook2.Name,
 			CodeReasonMessage: webhook2Error,
 			FailureCount:      1,
-		}, ctx.NewStatus.CanaryStatus.Webhooks[0])
+		}, clearWebhookPayloadHash(ctx.NewStatus).CanaryStatus.Webhooks[0])
 	}
 
 	// must wait for a while to get next expected result
@@ -178,14 +197,123 @@ func Test_webhook_Retry(t *testing.T) {
 	assert.Nil(t, err)
 	assert.NotNil(t, ctx.NewStatus.Error)
 	if assert.Len(t, ctx.NewStatus.CanaryStatus.Webhooks, 1) {
-		assert.Equal(t, ctx.NewStatus.CanaryStatus.Webhooks[0], rolloutv1alpha1.RolloutWebhookStatus{
+		assert.Equal(t, rolloutv1alpha1.RolloutWebhookStatus{
 			State:             rolloutv1alpha1.WebhookOnHold,
 			HookType:          hookType,
 			Name:              webhook2.Name,
 			CodeReasonMessage: webhook2Error,
 			FailureCount:      2,
-		}, ctx.NewStatus.CanaryStatus.Webhooks[0])
+		}, clearWebhookPayloadHash(ctx.NewStatus).CanaryStatus.Webhooks[0])
+	}
+}
+
+func Test_webhook_CachedResult(t *testing.T) {
+	exe := newTestWebhookExecutor()
+
+	hookType := rolloutv1alpha1.PreCanaryStepHook
+	rollout := testRollout.DeepCopy()
+	rolloutRun := testRolloutRun.DeepCopy()
+	rolloutRun.Spec.Webhooks = []rolloutv1alpha1.RolloutWebhook{
+		*webhook2.DeepCopy(),
+	}
+	rolloutRun.Spec.Canary = &rolloutv1alpha1.RolloutRunCanaryStrategy{
+		Targets: unimportantTargets,
+	}
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{
+		State: StepPreCanaryStepHook,
+	}
+	ctx := createTestExecutorContext(rollout, rolloutRun)
+	payloadHash := webhookPayloadHash(ctx.makeRolloutWebhookReview(hookType, webhook2))
+
+	// webhook2 always fails, a completed result with the same payload is reused
+	rolloutRun.Status.CanaryStatus.Webhooks = []rolloutv1alpha1.RolloutWebhookStatus{
+		{
+			State:       rolloutv1alpha1.WebhookCompleted,
+			HookType:    hookType,
+			Name:        webhook2.Name,
+			PayloadHash: payloadHash,
+		},
+	}
+	ctx = createTestExecutorContext(rollout, rolloutRun)
+	done, _, err := exe.Do(ctx, hookType)
+	assert.True(t, done)
+	assert.Nil(t, err)
+	assert.Nil(t, ctx.NewStatus.Error)
+
+	// payload is changed, webhook is invoked again
+	rolloutRun.Spec.Canary.Properties = map[string]string{"changed": "true"}
+	ctx = createTestExecutorContext(rollout, rolloutRun)
+	done, _, err = exe.Do(ctx, hookType)
+	assert.False(t, done)
+	assert.Nil(t, err)
+	assert.NotNil(t, ctx.NewStatus.Error)
+}
+
+func Test_webhook_PreRunHookRejected(t *testing.T) {
+	exe := newTestWebhookExecutor()
+
+	hookType := rolloutv1alpha1.PreRunHook
+	rollout := testRollout.DeepCopy()
+	rolloutRun := testRolloutRun.DeepCopy()
+	hook := webhook2.DeepCopy()
+	hook.HookTypes = []rolloutv1alpha1.HookType{hookType}
+	rolloutRun.Spec.Webhooks = []rolloutv1alpha1.RolloutWebhook{*hook}
+	rolloutRun.Spec.Canary = &rolloutv1alpha1.RolloutRunCanaryStrategy{
+		Targets: unimportantTargets,
+	}
+	rolloutRun.Status.Phase = rolloutv1alpha1.RolloutRunPhaseProgressing
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{
+		State: StepPreRunHook,
+	}
+
+	ctx := createTestExecutorContext(rollout, rolloutRun)
+	done, _, err := exe.Do(ctx, hookType)
+	assert.False(t, done)
+	assert.Nil(t, err)
+	// the rolloutRun is canceled instead of waiting for retry
+	assert.Nil(t, ctx.NewStatus.Error)
+	assert.Equal(t, rolloutv1alpha1.RolloutRunPhaseCanceling, ctx.NewStatus.Phase)
+	if assert.Len(t, ctx.NewStatus.CanaryStatus.Webhooks, 1) {
+		assert.Equal(t, rolloutv1alpha1.WebhookOnHold, ctx.NewStatus.CanaryStatus.Webhooks[0].State)
+	}
+	_, ok := exe.(*webhookExecutorImpl).webhookManager.Get(rolloutRun.UID)
+	assert.False(t, ok, "webhook worker should be stopped")
+}
+
+func Test_webhook_CanaryCompletedHookIgnored(t *testing.T) {
+	exe := newTestWebhookExecutor()
+
+	hookType := rolloutv1alpha1.CanaryCompletedHook
+	rollout := testRollout.DeepCopy()
+	rolloutRun := testRolloutRun.DeepCopy()
+	hook := webhook2.DeepCopy()
+	hook.HookTypes = []rolloutv1alpha1.HookType{hookType}
+	rolloutRun.Spec.Webhooks = []rolloutv1alpha1.RolloutWebhook{*hook}
+	rolloutRun.Spec.Canary = &rolloutv1alpha1.RolloutRunCanaryStrategy{
+		Targets: unimportantTargets,
+	}
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{
+		State: StepResourceRecycling,
+		Completion: &rolloutv1alpha1.CanaryCompletionStatus{
+			Outcome: rolloutv1alpha1.CanarySucceeded,
+		},
+	}
+
+	ctx := createTestExecutorContext(rollout, rolloutRun)
+	review := ctx.makeRolloutWebhookReview(hookType, *hook)
+	assert.Equal(t, rolloutv1alpha1.CanarySucceeded, review.Spec.Canary.Completion.Outcome)
+
+	// webhook2 fails with Fail policy, but it does not block the completion
+	done, _, err := exe.Do(ctx, hookType)
+	assert.True(t, done)
+	assert.Nil(t, err)
+	assert.Nil(t, ctx.NewStatus.Error)
+	if assert.Len(t, ctx.NewStatus.CanaryStatus.Webhooks, 1) {
+		assert.Equal(t, rolloutv1alpha1.WebhookCompleted, ctx.NewStatus.CanaryStatus.Webhooks[0].State)
+		assert.Equal(t, webhook2Error, ctx.NewStatus.CanaryStatus.Webhooks[0].CodeReasonMessage)
 	}
+	_, ok := exe.(*webhookExecutorImpl).webhookManager.Get(rolloutRun.UID)
+	assert.False(t, ok, "webhook worker should be stopped")
 }
 
 func Test_webhook_PreCanaryHookStep(t *testing.T) {
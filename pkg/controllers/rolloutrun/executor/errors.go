@@ -0,0 +1,73 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"errors"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+const ReasonExecutorFailed = "ExecutorFailed"
+
+// codeReasonError attaches a CodeReasonMessage to an underlying error, so
+// that callers can classify it by errors.As while errors.Is still matches
+// the underlying error.
+type codeReasonError struct {
+	crm *rolloutv1alpha1.CodeReasonMessage
+	err error
+}
+
+func (e *codeReasonError) Error() string {
+	return e.crm.Error()
+}
+
+func (e *codeReasonError) Unwrap() []error {
+	return []error{e.crm, e.err}
+}
+
+// wrapDoCanaryError returns a canary error with reason and msg wrapping err.
+func wrapDoCanaryError(reason, msg string, err error) error {
+	return &codeReasonError{crm: newDoCanaryError(reason, msg), err: err}
+}
+
+// codeReasonMessageOf returns the CodeReasonMessage carried by err, untyped
+// errors are classified as ExecutorFailed.
+func codeReasonMessageOf(err error) *rolloutv1alpha1.CodeReasonMessage {
+	var crm *rolloutv1alpha1.CodeReasonMessage
+	if errors.As(err, &crm) && crm != nil {
+		return crm
+	}
+	return &rolloutv1alpha1.CodeReasonMessage{
+		Code:    "Error",
+		Reason:  ReasonExecutorFailed,
+		Message: err.Error(),
+	}
+}
+
+// withCodeReasonMessage makes sure err carries a CodeReasonMessage, so that
+// all errors returned by executor can be classified by errors.As.
+func withCodeReasonMessage(err error) error {
+	if err == nil {
+		return nil
+	}
+	var crm *rolloutv1alpha1.CodeReasonMessage
+	if errors.As(err, &crm) && crm != nil {
+		return err
+	}
+	return &codeReasonError{crm: codeReasonMessageOf(err), err: err}
+}
@@ -0,0 +1,72 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+)
+
+func Test_wrapDoCanaryError(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := control.TerminalError(wrapDoCanaryError("TrafficVerifyFailed", "probe failed", cause))
+
+	var crm *rolloutv1alpha1.CodeReasonMessage
+	if assert.True(t, errors.As(err, &crm)) {
+		assert.Equal(t, "DoCanaryError", crm.Code)
+		assert.Equal(t, "TrafficVerifyFailed", crm.Reason)
+		assert.Equal(t, "probe failed", crm.Message)
+	}
+	assert.True(t, errors.Is(err, cause))
+	assert.True(t, errors.Is(err, control.TerminalError(nil)))
+	assert.True(t, errors.Is(err, &rolloutv1alpha1.CodeReasonMessage{Reason: "TrafficVerifyFailed"}))
+	assert.False(t, errors.Is(err, &rolloutv1alpha1.CodeReasonMessage{Reason: "AnalysisFailed"}))
+}
+
+func Test_withCodeReasonMessage(t *testing.T) {
+	assert.NoError(t, withCodeReasonMessage(nil))
+
+	// typed errors are kept
+	typed := fmt.Errorf("wrapped: %w", newDoCanaryError("AnalysisFailed", "out of threshold"))
+	assert.Equal(t, typed, withCodeReasonMessage(typed))
+
+	// untyped errors are classified as ExecutorFailed
+	cause := errors.New("conflict")
+	err := withCodeReasonMessage(cause)
+	var crm *rolloutv1alpha1.CodeReasonMessage
+	if assert.True(t, errors.As(err, &crm)) {
+		assert.Equal(t, ReasonExecutorFailed, crm.Reason)
+		assert.Equal(t, "conflict", crm.Message)
+	}
+	assert.True(t, errors.Is(err, cause))
+}
+
+func Test_ExecutorContext_Fail(t *testing.T) {
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), testCanaryRolloutRun.DeepCopy())
+
+	ctx.Fail(control.TerminalError(wrapDoCanaryError("FailedFinalize", "failed to delete canary", errors.New("forbidden"))))
+	assert.Equal(t, "FailedFinalize", ctx.NewStatus.Error.Reason)
+
+	ctx.Fail(errors.New("forbidden"))
+	assert.Equal(t, ReasonExecutorFailed, ctx.NewStatus.Error.Reason)
+}
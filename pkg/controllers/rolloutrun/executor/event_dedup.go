@@ -0,0 +1,132 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+// eventKey identifies the identical events of a rolloutRun.
+type eventKey struct {
+	eventType string
+	reason    string
+	message   string
+}
+
+// eventSeries counts the events of a rolloutRun emitted in the same state.
+type eventSeries struct {
+	uid    types.UID
+	state  string
+	counts map[eventKey]int32
+}
+
+// eventDeduplicator coalesces the identical events which are emitted on every
+// reconcile while a step is waiting, e.g. by a slow canary. Like the event
+// aggregation of core Kubernetes, the repeated events are counted instead of
+// being emitted, only the 2^n-th occurrence is emitted with the count. The
+// counts are reset when the phase or the step state of rolloutRun changes, so
+// that the state changes and new reasons always produce fresh events.
+type eventDeduplicator struct {
+	mu sync.Mutex
+	// series is keyed by the name of rolloutRun, so that it can be forgotten
+	// once the rolloutRun is not found.
+	series map[types.NamespacedName]*eventSeries
+}
+
+func newEventDeduplicator() *eventDeduplicator {
+	return &eventDeduplicator{series: map[types.NamespacedName]*eventSeries{}}
+}
+
+// observe counts the event of rolloutRun in state, it returns the count and
+// whether the event should be emitted. A recreated rolloutRun with the same
+// name starts a new series.
+func (d *eventDeduplicator) observe(rolloutRun *rolloutv1alpha1.RolloutRun, state string, key eventKey) (int32, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	name := types.NamespacedName{Namespace: rolloutRun.Namespace, Name: rolloutRun.Name}
+	series := d.series[name]
+	if series == nil || series.uid != rolloutRun.UID || series.state != state {
+		series = &eventSeries{uid: rolloutRun.UID, state: state, counts: map[eventKey]int32{}}
+		d.series[name] = series
+	}
+	series.counts[key]++
+	count := series.counts[key]
+	return count, count&(count-1) == 0
+}
+
+// forget drops the counts of rolloutRun, e.g. after it is completed or deleted.
+func (d *eventDeduplicator) forget(name types.NamespacedName) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.series, name)
+}
+
+// eventState returns the phase and the step state of rolloutRun, the events
+// are deduplicated within the same one.
+func eventState(ctx *ExecutorContext) string {
+	newStatus := ctx.NewStatus
+	if ctx.inCanary() && newStatus.CanaryStatus != nil {
+		return fmt.Sprintf("%s/canary/%s", newStatus.Phase, newStatus.CanaryStatus.State)
+	}
+	if newStatus.BatchStatus != nil {
+		return fmt.Sprintf("%s/batch-%d/%s", newStatus.Phase, newStatus.BatchStatus.CurrentBatchIndex, newStatus.BatchStatus.CurrentBatchState)
+	}
+	return string(newStatus.Phase)
+}
+
+// dedupEventRecorder deduplicates the events of rolloutRun in ctx, the state
+// is read when each event is emitted.
+type dedupEventRecorder struct {
+	record.EventRecorder
+	dedup *eventDeduplicator
+	ctx   *ExecutorContext
+}
+
+// observe counts the event, it returns the message to emit and whether the
+// event should be emitted.
+func (r *dedupEventRecorder) observe(eventtype, reason, message string) (string, bool) {
+	count, emit := r.dedup.observe(r.ctx.RolloutRun, eventState(r.ctx), eventKey{eventType: eventtype, reason: reason, message: message})
+	if emit && count > 1 {
+		message = fmt.Sprintf("%s (repeated %d times)", message, count)
+	}
+	return message, emit
+}
+
+func (r *dedupEventRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	if message, emit := r.observe(eventtype, reason, message); emit {
+		r.EventRecorder.Event(object, eventtype, reason, message)
+	}
+}
+
+func (r *dedupEventRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// AnnotatedEventf is deduplicated like Eventf, the annotations are not part of
+// the identity of events.
+func (r *dedupEventRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	if message, emit := r.observe(eventtype, reason, fmt.Sprintf(messageFmt, args...)); emit {
+		r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, "%s", message)
+	}
+}
@@ -0,0 +1,93 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+func drainEvents(recorder *record.FakeRecorder) []string {
+	events := make([]string, 0)
+	for {
+		select {
+		case e := <-recorder.Events:
+			events = append(events, e)
+		default:
+			return events
+		}
+	}
+}
+
+func Test_dedupEventRecorder(t *testing.T) {
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	rolloutRun.Status.Phase = rolloutv1alpha1.RolloutRunPhaseProgressing
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{State: StepRunning}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+	ctx.Initialize()
+
+	fake := record.NewFakeRecorder(100)
+	dedup := newEventDeduplicator()
+	recorder := &dedupEventRecorder{EventRecorder: fake, dedup: dedup, ctx: ctx}
+
+	// a slow canary waits for 10 reconciles
+	for i := 0; i < 10; i++ {
+		recorder.Eventf(rolloutRun, corev1.EventTypeWarning, ReasonReadinessExpressionFailed, "failed to evaluate %s", "expr")
+	}
+	assert.Equal(t, []string{
+		"Warning ReadinessExpressionFailed failed to evaluate expr",
+		"Warning ReadinessExpressionFailed failed to evaluate expr (repeated 2 times)",
+		"Warning ReadinessExpressionFailed failed to evaluate expr (repeated 4 times)",
+		"Warning ReadinessExpressionFailed failed to evaluate expr (repeated 8 times)",
+	}, drainEvents(fake))
+
+	// new reason is emitted at once
+	recorder.Event(rolloutRun, corev1.EventTypeNormal, ReasonCanaryHolding, "canary is holding")
+	assert.Equal(t, []string{"Normal CanaryHolding canary is holding"}, drainEvents(fake))
+
+	// state change starts a new series
+	ctx.NewStatus.CanaryStatus.State = StepHolding
+	recorder.Eventf(rolloutRun, corev1.EventTypeWarning, ReasonReadinessExpressionFailed, "failed to evaluate %s", "expr")
+	assert.Equal(t, []string{"Warning ReadinessExpressionFailed failed to evaluate expr"}, drainEvents(fake))
+
+	// other rolloutRuns are counted separately, and the forgotten one restarts
+	other := createTestExecutorContext(testRollout.DeepCopy(), testRolloutRun.DeepCopy())
+	other.Initialize()
+	otherRecorder := &dedupEventRecorder{EventRecorder: fake, dedup: dedup, ctx: other}
+	otherRecorder.Event(other.RolloutRun, corev1.EventTypeNormal, ReasonCanaryHolding, "canary is holding")
+	dedup.forget(types.NamespacedName{Namespace: rolloutRun.Namespace, Name: rolloutRun.Name})
+	recorder.Event(rolloutRun, corev1.EventTypeNormal, ReasonCanaryHolding, "canary is holding")
+	assert.Len(t, drainEvents(fake), 2)
+	assert.Len(t, dedup.series, 2)
+
+	// annotated events are counted in the same series
+	recorder.AnnotatedEventf(rolloutRun, map[string]string{"k": "v"}, corev1.EventTypeNormal, ReasonCanaryHolding, "canary is %s", "holding")
+	assert.Equal(t, []string{"Normal CanaryHolding canary is holding (repeated 2 times)"}, drainEvents(fake))
+	recorder.AnnotatedEventf(rolloutRun, map[string]string{"k": "v"}, corev1.EventTypeNormal, ReasonCanaryHolding, "canary is %s", "holding")
+	assert.Empty(t, drainEvents(fake))
+
+	// recreated rolloutRun with the same name starts a new series
+	ctx.RolloutRun.UID = "recreated"
+	recorder.Event(rolloutRun, corev1.EventTypeNormal, ReasonCanaryHolding, "canary is holding")
+	assert.Equal(t, []string{"Normal CanaryHolding canary is holding"}, drainEvents(fake))
+}
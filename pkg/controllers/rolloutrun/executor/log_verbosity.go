@@ -0,0 +1,85 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"strconv"
+
+	"github.com/go-logr/logr"
+
+	rolloutapi "kusionstack.io/rollout/apis/rollout"
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+const maxLogVerbosity = 10
+
+// withVerbosityOverride returns a logger which prints logs up to the V-level
+// set in rolloutRun annotation, regardless of the global log level. Invalid
+// values are ignored.
+func withVerbosityOverride(logger logr.Logger, rolloutRun *rolloutv1alpha1.RolloutRun) logr.Logger {
+	value, ok := rolloutRun.GetAnnotations()[rolloutapi.AnnoLogVerbosity]
+	if !ok {
+		return logger
+	}
+	verbosity, err := strconv.Atoi(value)
+	if err != nil || verbosity < 0 || verbosity > maxLogVerbosity {
+		logger.Info("ignore invalid log verbosity annotation", "annotation", rolloutapi.AnnoLogVerbosity, "value", value)
+		return logger
+	}
+	return &verbosityLogger{base: logger, verbosity: verbosity}
+}
+
+// verbosityLogger prints logs with V-level not greater than verbosity at the
+// level 0 of base logger, so that they are not filtered by the global level.
+type verbosityLogger struct {
+	base      logr.Logger
+	verbosity int
+	level     int
+}
+
+var _ logr.Logger = &verbosityLogger{}
+
+func (l *verbosityLogger) current() logr.Logger {
+	if l.level <= l.verbosity {
+		return l.base
+	}
+	return l.base.V(l.level)
+}
+
+func (l *verbosityLogger) Enabled() bool {
+	return l.current().Enabled()
+}
+
+func (l *verbosityLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.current().Info(msg, keysAndValues...)
+}
+
+func (l *verbosityLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.base.Error(err, msg, keysAndValues...)
+}
+
+func (l *verbosityLogger) V(level int) logr.Logger {
+	return &verbosityLogger{base: l.base, verbosity: l.verbosity, level: l.level + level}
+}
+
+func (l *verbosityLogger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	return &verbosityLogger{base: l.base.WithValues(keysAndValues...), verbosity: l.verbosity, level: l.level}
+}
+
+func (l *verbosityLogger) WithName(name string) logr.Logger {
+	return &verbosityLogger{base: l.base.WithName(name), verbosity: l.verbosity, level: l.level}
+}
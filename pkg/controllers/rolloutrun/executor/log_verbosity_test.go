@@ -0,0 +1,102 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rolloutapi "kusionstack.io/rollout/apis/rollout"
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+func Test_withVerbosityOverride(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation *string
+		wantLines  int
+	}{
+		{
+			name:      "no annotation",
+			wantLines: 1,
+		},
+		{
+			name:       "raise verbosity",
+			annotation: ptrString("4"),
+			wantLines:  2,
+		},
+		{
+			name:       "invalid verbosity",
+			annotation: ptrString("verbose"),
+			// info about invalid annotation and the V(0) log
+			wantLines: 2,
+		},
+		{
+			name:       "out of range verbosity",
+			annotation: ptrString("100"),
+			wantLines:  2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := 0
+			logger := &countingLogger{lines: &lines}
+
+			rolloutRun := &rolloutv1alpha1.RolloutRun{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}}}
+			if tt.annotation != nil {
+				rolloutRun.Annotations[rolloutapi.AnnoLogVerbosity] = *tt.annotation
+			}
+
+			l := withVerbosityOverride(logger, rolloutRun).WithValues("rolloutRun", "test")
+			l.Info("v0")
+			l.V(4).Info("v4")
+			l.V(5).Info("v5")
+			assert.Equal(t, tt.wantLines, lines)
+		})
+	}
+}
+
+// countingLogger counts the printed lines, only level 0 is enabled.
+type countingLogger struct {
+	lines *int
+	level int
+}
+
+func (l *countingLogger) Enabled() bool { return l.level == 0 }
+
+func (l *countingLogger) Info(_ string, _ ...interface{}) {
+	if l.Enabled() {
+		*l.lines++
+	}
+}
+
+func (l *countingLogger) Error(_ error, _ string, _ ...interface{}) { *l.lines++ }
+
+func (l *countingLogger) V(level int) logr.Logger {
+	return &countingLogger{lines: l.lines, level: l.level + level}
+}
+
+func (l *countingLogger) WithValues(_ ...interface{}) logr.Logger { return l }
+
+func (l *countingLogger) WithName(_ string) logr.Logger { return l }
+
+func ptrString(s string) *string {
+	return &s
+}
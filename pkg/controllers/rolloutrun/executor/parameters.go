@@ -0,0 +1,56 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+
+	"github.com/samber/lo"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+const ReasonMissingParameters = "MissingParameters"
+
+// resolveParameters substitutes the parameters into the spec of ctx.RolloutRun.
+// The values recorded in status take precedence over the ones in spec, so that
+// the rolloutRun keeps running with the same values even if its parameters are
+// changed after it starts.
+func resolveParameters(ctx *ExecutorContext) error {
+	spec := &ctx.RolloutRun.Spec
+	if len(spec.Parameters) == 0 && len(ctx.NewStatus.ResolvedParameters) == 0 {
+		return nil
+	}
+
+	params := lo.Assign(spec.Parameters, ctx.NewStatus.ResolvedParameters)
+	resolved, used, missing := spec.ResolveParameters(params)
+	if len(missing) > 0 {
+		return &rolloutv1alpha1.CodeReasonMessage{
+			Code:    "InvalidParameters",
+			Reason:  ReasonMissingParameters,
+			Message: fmt.Sprintf("parameters %v are referenced but not provided", missing),
+		}
+	}
+
+	rolloutRun := ctx.RolloutRun.DeepCopy()
+	rolloutRun.Spec = *resolved
+	ctx.RolloutRun = rolloutRun
+	if len(used) > 0 {
+		ctx.NewStatus.ResolvedParameters = lo.Assign(ctx.NewStatus.ResolvedParameters, used)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+func Test_resolveParameters(t *testing.T) {
+	rolloutRun := testRolloutRun.DeepCopy()
+	rolloutRun.Spec.Parameters = map[string]string{"replicas": "2", "unused": "x"}
+	rolloutRun.Spec.Canary = &rolloutv1alpha1.RolloutRunCanaryStrategy{
+		Targets: []rolloutv1alpha1.RolloutRunStepTarget{
+			{Replicas: intstr.FromString("${replicas}")},
+		},
+		Properties: map[string]string{"version": "v${replicas}"},
+		Notifications: []rolloutv1alpha1.CanaryNotification{{
+			Name:       "notify",
+			Properties: map[string]string{"replicas": "${replicas}"},
+		}},
+	}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+
+	assert.NoError(t, resolveParameters(ctx))
+	assert.Equal(t, intstr.FromInt(2), ctx.RolloutRun.Spec.Canary.Targets[0].Replicas)
+	assert.Equal(t, "v2", ctx.RolloutRun.Spec.Canary.Properties["version"])
+	assert.Equal(t, "2", ctx.RolloutRun.Spec.Canary.Notifications[0].Properties["replicas"])
+	assert.Equal(t, map[string]string{"replicas": "2"}, ctx.NewStatus.ResolvedParameters)
+	// the original object is not mutated
+	assert.Equal(t, intstr.FromString("${replicas}"), rolloutRun.Spec.Canary.Targets[0].Replicas)
+	assert.Equal(t, "${replicas}", rolloutRun.Spec.Canary.Notifications[0].Properties["replicas"])
+
+	// recorded values take precedence over spec
+	rolloutRun.Spec.Parameters["replicas"] = "3"
+	ctx.RolloutRun = rolloutRun
+	assert.NoError(t, resolveParameters(ctx))
+	assert.Equal(t, intstr.FromInt(2), ctx.RolloutRun.Spec.Canary.Targets[0].Replicas)
+
+	// missing parameters
+	rolloutRun.Spec.Canary.Properties["image"] = "${image}"
+	ctx.RolloutRun = rolloutRun
+	err := resolveParameters(ctx)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), ReasonMissingParameters)
+		assert.Contains(t, err.Error(), "[image]")
+	}
+}
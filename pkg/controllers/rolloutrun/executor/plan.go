@@ -0,0 +1,417 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/samber/lo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+const (
+	PlanStepCanary = "canary"
+	PlanStepBatch  = "batch"
+)
+
+// PlanActionType is the type of action taken in a planned state.
+type PlanActionType string
+
+const (
+	// PlanActionWebhook calls the webhooks of a hook type.
+	PlanActionWebhook PlanActionType = "Webhook"
+	// PlanActionPause pauses the rolloutRun until it is resumed.
+	PlanActionPause PlanActionType = "Pause"
+	// PlanActionForkStable routes traffic to the stable backend.
+	PlanActionForkStable PlanActionType = "ForkStable"
+	// PlanActionScaleUp scales canary workloads to the replicas of targets.
+	PlanActionScaleUp PlanActionType = "ScaleUp"
+	// PlanActionWarmUp warms up canary before it receives real traffic.
+	PlanActionWarmUp PlanActionType = "WarmUp"
+	// PlanActionForkCanary routes the traffic weight to canary.
+	PlanActionForkCanary PlanActionType = "ForkCanary"
+	// PlanActionBakeActive keeps canary receiving traffic for an active window.
+	PlanActionBakeActive PlanActionType = "BakeActive"
+	// PlanActionBakeIdle reverts canary traffic and scales canary down for an
+	// idle window.
+	PlanActionBakeIdle PlanActionType = "BakeIdle"
+	// PlanActionAnalysis analyzes the metrics of canary.
+	PlanActionAnalysis PlanActionType = "Analysis"
+	// PlanActionHold holds canary until it is promoted or ended.
+	PlanActionHold PlanActionType = "Hold"
+	// PlanActionRampDown decreases the traffic weight of canary.
+	PlanActionRampDown PlanActionType = "RampDown"
+	// PlanActionPromoteStable updates stable to the revision of a succeeded
+	// blue/green canary before its traffic is reverted.
+	PlanActionPromoteStable PlanActionType = PlanActionType(rolloutv1alpha1.PromoteStableRevision)
+	// PlanActionRevertCanary reverts the traffic routed to canary.
+	PlanActionRevertCanary PlanActionType = PlanActionType(rolloutv1alpha1.RevertCanaryTraffic)
+	// PlanActionRetainCanary retains canary workloads after their traffic is
+	// reverted until the retention window ends.
+	PlanActionRetainCanary PlanActionType = "RetainCanary"
+	// PlanActionDeleteCanary deletes canary workloads.
+	PlanActionDeleteCanary PlanActionType = PlanActionType(rolloutv1alpha1.DeleteCanaryResource)
+	// PlanActionRevertStable reverts the forked stable traffic.
+	PlanActionRevertStable PlanActionType = PlanActionType(rolloutv1alpha1.RevertStableTraffic)
+	// PlanActionUpgrade upgrades the replicas of targets in a batch.
+	PlanActionUpgrade PlanActionType = "Upgrade"
+	// PlanActionFinalize finalizes the workloads after the last batch.
+	PlanActionFinalize PlanActionType = "Finalize"
+)
+
+// Plan is the ordered states the step state machines go through for a
+// RolloutRun, and the actions taken in each state. It is computed from spec
+// only, so the replicas in percent are not resolved against workloads, and the
+// states waiting on something, e.g. readiness, are assumed to pass.
+type Plan struct {
+	States []PlannedState `json:"states"`
+}
+
+// PlannedState is a state of canary step or a batch step in the plan.
+type PlannedState struct {
+	// Step is the step the state belongs to, canary or batch.
+	Step string `json:"step"`
+	// BatchIndex is the index of batch, it is nil in canary.
+	BatchIndex *int32 `json:"batchIndex,omitempty"`
+	// State is the state of step.
+	State rolloutv1alpha1.RolloutStepState `json:"state"`
+	// Actions are the actions taken in the state in order.
+	Actions []PlannedAction `json:"actions,omitempty"`
+}
+
+// PlannedAction is an action taken in a planned state.
+type PlannedAction struct {
+	Type PlanActionType `json:"type"`
+	// HookType is the type of webhooks called, only used by Webhook.
+	HookType rolloutv1alpha1.HookType `json:"hookType,omitempty"`
+	// Webhooks are the names of webhooks called, only used by Webhook.
+	Webhooks []string `json:"webhooks,omitempty"`
+	// Targets are the workloads and their replicas after the action.
+	Targets []PlannedTarget `json:"targets,omitempty"`
+	// TrafficWeight is the canary traffic weight after the action.
+	TrafficWeight *int32 `json:"trafficWeight,omitempty"`
+	// Duration is the minimum duration of the action, e.g. a bake window.
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	// Metrics are the names of metrics analyzed, only used by Analysis.
+	Metrics []string `json:"metrics,omitempty"`
+}
+
+// PlannedTarget is a workload and its replicas in the plan.
+type PlannedTarget struct {
+	rolloutv1alpha1.CrossClusterObjectNameReference `json:",inline"`
+	// Variant is the canary variant of the replicas, it is empty if canary
+	// has no variants.
+	Variant  string             `json:"variant,omitempty"`
+	Replicas intstr.IntOrString `json:"replicas"`
+}
+
+// PlanRolloutRun returns the plan of the rolloutRun spec without touching any
+// object, the canary step goes first and then the batches. The parameters of
+// spec are resolved first, the same as the executor does.
+func PlanRolloutRun(spec *rolloutv1alpha1.RolloutRunSpec) (*Plan, error) {
+	spec, _, missing := spec.ResolveParameters(spec.Parameters)
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("parameters %v are referenced but not provided", missing)
+	}
+	rolloutRun := &rolloutv1alpha1.RolloutRun{Spec: *spec}
+
+	plan := &Plan{States: make([]PlannedState, 0)}
+	if spec.Canary != nil {
+		states, err := planCanary(rolloutRun)
+		if err != nil {
+			return nil, err
+		}
+		plan.States = append(plan.States, states...)
+	}
+	if spec.Batch != nil {
+		// the batch state machine is the same for all batches
+		states := newBatchExecutor(nil).stateMachine.graph("").States
+		for i := range spec.Batch.Batches {
+			plan.States = append(plan.States, planBatch(spec, int32(i), states)...)
+		}
+	}
+	return plan, nil
+}
+
+// planCanary returns the states of canary step machine of rolloutRun in order,
+// see stateMachineOf.
+func planCanary(rolloutRun *rolloutv1alpha1.RolloutRun) ([]PlannedState, error) {
+	spec := &rolloutRun.Spec
+	canary := spec.Canary
+	states := newCanaryExecutor(nil).stateMachineOf(rolloutRun).graph("").States
+
+	result := make([]PlannedState, 0, len(states))
+	for _, state := range states {
+		planned := PlannedState{Step: PlanStepCanary, State: state}
+		switch state {
+		case StepPreRunHook:
+			planned.Actions = planWebhooks(spec, rolloutv1alpha1.PreRunHook)
+		case StepPreCanaryStepHook:
+			planned.Actions = planWebhooks(spec, rolloutv1alpha1.PreCanaryStepHook)
+		case StepRunning:
+			actions, err := planCanaryRunning(rolloutRun)
+			if err != nil {
+				return nil, err
+			}
+			planned.Actions = actions
+		case StepHolding:
+			planned.Actions = []PlannedAction{{Type: PlanActionHold}}
+		case StepPostCanaryStepHook:
+			planned.Actions = planWebhooks(spec, rolloutv1alpha1.PostCanaryStepHook)
+			pauseAfter := ptr.Deref(canary.PauseAfter, len(canary.PauseBefore) == 0)
+			if pauseAfter || lo.Contains(canary.PauseBefore, rolloutv1alpha1.CanaryPauseBeforePromotion) {
+				planned.Actions = append(planned.Actions, PlannedAction{Type: PlanActionPause})
+			}
+		case StepResourceRecycling:
+			planned.Actions = planCanaryRecycle(canary)
+		}
+		result = append(result, planned)
+	}
+	return result, nil
+}
+
+// planCanaryRunning returns the actions of canary Running state, see doCanary.
+func planCanaryRunning(rolloutRun *rolloutv1alpha1.RolloutRun) ([]PlannedAction, error) {
+	canary := rolloutRun.Spec.Canary
+	actions := make([]PlannedAction, 0)
+	traffic := canary.Traffic
+	if traffic != nil {
+		actions = append(actions, PlannedAction{Type: PlanActionForkStable})
+	}
+	if lo.Contains(canary.PauseBefore, rolloutv1alpha1.CanaryPauseBeforeCreateCanary) {
+		actions = append(actions, PlannedAction{Type: PlanActionPause})
+	}
+
+	increments, err := planScaleUpIncrements(rolloutRun)
+	if err != nil {
+		return nil, err
+	}
+	for _, targets := range increments {
+		actions = append(actions, PlannedAction{Type: PlanActionScaleUp, Targets: targets})
+	}
+
+	if canary.WarmUp != nil {
+		actions = append(actions, PlannedAction{Type: PlanActionWarmUp, Duration: secondsDuration(canary.WarmUp.DurationSeconds)})
+	}
+	var weight *int32
+	if traffic != nil {
+		weight = traffic.CanaryWeight()
+		if lo.Contains(canary.PauseBefore, rolloutv1alpha1.CanaryPauseBeforeForkCanary) {
+			actions = append(actions, PlannedAction{Type: PlanActionPause})
+		}
+		actions = append(actions, PlannedAction{Type: PlanActionForkCanary, TrafficWeight: weight})
+	}
+
+	analysis := PlannedAction{Type: PlanActionAnalysis}
+	if canary.Analysis != nil {
+		analysis.Metrics = lo.Map(canary.Analysis.Metrics, func(metric rolloutv1alpha1.AnalysisMetric, _ int) string {
+			return metric.Name
+		})
+	}
+	bake := canary.Bake
+	if bake == nil {
+		if canary.Analysis != nil {
+			actions = append(actions, analysis)
+		}
+		return actions, nil
+	}
+
+	final := increments[len(increments)-1]
+	idle := lo.Map(final, func(target PlannedTarget, _ int) PlannedTarget {
+		target.Replicas = bakeIdleReplicas(bake)
+		return target
+	})
+	for window := int32(1); window <= bake.Windows; window++ {
+		if window > 1 {
+			actions = append(actions, PlannedAction{Type: PlanActionScaleUp, Targets: final})
+			if traffic != nil {
+				actions = append(actions, PlannedAction{Type: PlanActionForkCanary, TrafficWeight: weight})
+			}
+		}
+		actions = append(actions, PlannedAction{Type: PlanActionBakeActive, TrafficWeight: weight, Duration: secondsDuration(bake.ActiveSeconds)})
+		if canary.Analysis != nil {
+			actions = append(actions, analysis)
+		}
+		if window < bake.Windows {
+			actions = append(actions, PlannedAction{Type: PlanActionBakeIdle, Targets: idle, Duration: secondsDuration(bake.IdleSeconds)})
+		}
+	}
+	return actions, nil
+}
+
+// planScaleUpIncrements returns the canary replicas of targets in each
+// increment of scaleUpStep, the last one is the final replicas. Each variant of
+// a target is a separate canary workload with the replicas of variant. The
+// canaries are scaled up in lockstep, and the ones in percent are scaled up at
+// once.
+func planScaleUpIncrements(rolloutRun *rolloutv1alpha1.RolloutRun) ([][]PlannedTarget, error) {
+	canary := rolloutRun.Spec.Canary
+	finals := make([]PlannedTarget, 0, len(canary.Targets))
+	for _, target := range canary.Targets {
+		if len(canary.Ordinals) > 0 {
+			// pods of ordinals are updated in place, no variant is created
+			finals = append(finals, PlannedTarget{CrossClusterObjectNameReference: target.CrossClusterObjectNameReference, Replicas: intstr.FromInt(len(canary.Ordinals))})
+			continue
+		}
+		for _, variant := range canaryVariants(rolloutRun) {
+			replicas := variant.Replicas
+			if len(variant.Name) == 0 {
+				replicas = target.Replicas
+			}
+			finals = append(finals, PlannedTarget{CrossClusterObjectNameReference: target.CrossClusterObjectNameReference, Variant: variant.Name, Replicas: replicas})
+		}
+	}
+	if canary.ScaleUpStep == nil {
+		return [][]PlannedTarget{finals}, nil
+	}
+
+	steps := make([]int32, len(finals))
+	count := 1
+	for i, final := range finals {
+		if final.Replicas.Type != intstr.Int {
+			continue
+		}
+		step, err := scaleUpStepReplicas(final.Replicas.IntVal, *canary.ScaleUpStep)
+		if err != nil {
+			return nil, err
+		}
+		steps[i] = step
+		count = max(count, int((final.Replicas.IntVal+step-1)/step))
+	}
+
+	increments := make([][]PlannedTarget, 0, count)
+	for n := 1; n <= count; n++ {
+		targets := make([]PlannedTarget, 0, len(finals))
+		for i, final := range finals {
+			if steps[i] > 0 {
+				final.Replicas = intstr.FromInt(int(min(steps[i]*int32(n), final.Replicas.IntVal)))
+			}
+			targets = append(targets, final)
+		}
+		increments = append(increments, targets)
+	}
+	return increments, nil
+}
+
+// planCanaryRecycle returns the actions of canary recycle in recycleOrder, the
+// canary traffic is ramped down before it is reverted.
+func planCanaryRecycle(canary *rolloutv1alpha1.RolloutRunCanaryStrategy) []PlannedAction {
+	traffic := canary.Traffic
+	actions := make([]PlannedAction, 0)
+	order := canaryRecycleOrder(canary)
+	if canary.Strategy == rolloutv1alpha1.CanaryStrategyBlueGreen {
+		order = blueGreenRecycleOrder(order)
+	}
+	for _, op := range order {
+		switch op {
+		case rolloutv1alpha1.PromoteStableRevision:
+			actions = append(actions, PlannedAction{Type: PlanActionPromoteStable})
+		case rolloutv1alpha1.RevertCanaryTraffic:
+			if traffic == nil {
+				continue
+			}
+			if ramp := traffic.RevertRamp; ramp != nil && traffic.CanaryWeight() != nil {
+				interval := ramp.IntervalSeconds
+				if interval <= 0 {
+					interval = defaultRevertRampIntervalSeconds
+				}
+				for step := int32(1); step < ramp.Steps; step++ {
+					actions = append(actions, PlannedAction{
+						Type:          PlanActionRampDown,
+						TrafficWeight: ptr.To(revertRampWeight(*traffic.CanaryWeight(), ramp.Steps, step)),
+						Duration:      secondsDuration(interval),
+					})
+				}
+			}
+			actions = append(actions, PlannedAction{Type: PlanActionRevertCanary})
+		case rolloutv1alpha1.DeleteCanaryResource:
+			if canary.RetentionSeconds != nil {
+				actions = append(actions, PlannedAction{Type: PlanActionRetainCanary, Duration: secondsDuration(*canary.RetentionSeconds)})
+			}
+			actions = append(actions, PlannedAction{Type: PlanActionDeleteCanary})
+		case rolloutv1alpha1.RevertStableTraffic:
+			if traffic != nil {
+				actions = append(actions, PlannedAction{Type: PlanActionRevertStable})
+			}
+		}
+	}
+	return actions
+}
+
+// planBatch returns the states of the batch step machine of the batch, the
+// batch is initialized and paused in the initial state None.
+func planBatch(spec *rolloutv1alpha1.RolloutRunSpec, index int32, states []rolloutv1alpha1.RolloutStepState) []PlannedState {
+	batch := spec.Batch.Batches[index]
+	result := make([]PlannedState, 0, len(states))
+	for _, state := range states {
+		planned := PlannedState{Step: PlanStepBatch, BatchIndex: ptr.To(index), State: state}
+		switch state {
+		case StepNone:
+			if index == 0 && spec.Canary == nil {
+				planned.Actions = planWebhooks(spec, rolloutv1alpha1.PreRunHook)
+			}
+			if batch.Breakpoint {
+				planned.Actions = append(planned.Actions, PlannedAction{Type: PlanActionPause})
+			}
+		case StepPreBatchStepHook:
+			planned.Actions = planWebhooks(spec, rolloutv1alpha1.PreBatchStepHook)
+		case StepRunning:
+			planned.Actions = []PlannedAction{{
+				Type: PlanActionUpgrade,
+				Targets: lo.Map(batch.Targets, func(target rolloutv1alpha1.RolloutRunStepTarget, _ int) PlannedTarget {
+					return PlannedTarget{CrossClusterObjectNameReference: target.CrossClusterObjectNameReference, Replicas: target.Replicas}
+				}),
+			}}
+		case StepPostBatchStepHook:
+			planned.Actions = planWebhooks(spec, rolloutv1alpha1.PostBatchStepHook)
+		case StepResourceRecycling:
+			if int(index+1) == len(spec.Batch.Batches) {
+				planned.Actions = []PlannedAction{{Type: PlanActionFinalize}}
+			}
+		}
+		result = append(result, planned)
+	}
+	return result
+}
+
+// planWebhooks returns the action calling the webhooks of hookType, or nil if
+// there is no such webhook.
+func planWebhooks(spec *rolloutv1alpha1.RolloutRunSpec, hookType rolloutv1alpha1.HookType) []PlannedAction {
+	webhooks := filterWebhooks(hookType, &rolloutv1alpha1.RolloutRun{Spec: *spec})
+	if len(webhooks) == 0 {
+		return nil
+	}
+	return []PlannedAction{{
+		Type:     PlanActionWebhook,
+		HookType: hookType,
+		Webhooks: lo.Map(webhooks, func(w rolloutv1alpha1.RolloutWebhook, _ int) string {
+			return w.Name
+		}),
+	}}
+}
+
+func secondsDuration(seconds int32) *metav1.Duration {
+	if seconds <= 0 {
+		return nil
+	}
+	return &metav1.Duration{Duration: time.Duration(seconds) * time.Second}
+}
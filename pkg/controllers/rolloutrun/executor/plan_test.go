@@ -0,0 +1,196 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+func planStates(plan *Plan) []rolloutv1alpha1.RolloutStepState {
+	return lo.Map(plan.States, func(state PlannedState, _ int) rolloutv1alpha1.RolloutStepState {
+		return state.State
+	})
+}
+
+func actionTypes(state PlannedState) []PlanActionType {
+	return lo.Map(state.Actions, func(action PlannedAction, _ int) PlanActionType {
+		return action.Type
+	})
+}
+
+func Test_PlanRolloutRun_Canary(t *testing.T) {
+	spec := &rolloutv1alpha1.RolloutRunSpec{
+		Webhooks: []rolloutv1alpha1.RolloutWebhook{
+			{Name: "wh-1", HookTypes: []rolloutv1alpha1.HookType{rolloutv1alpha1.PreCanaryStepHook, rolloutv1alpha1.PostCanaryStepHook}},
+			{Name: "wh-2", HookTypes: []rolloutv1alpha1.HookType{rolloutv1alpha1.PostCanaryStepHook}},
+		},
+		Canary: &rolloutv1alpha1.RolloutRunCanaryStrategy{
+			Targets: []rolloutv1alpha1.RolloutRunStepTarget{
+				{CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-a", Name: "test-1"}, Replicas: intstr.FromInt(4)},
+				{CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Cluster: "cluster-b", Name: "test-1"}, Replicas: intstr.FromString("10%")},
+			},
+			Traffic: &rolloutv1alpha1.TrafficStrategy{
+				Weight:     ptr.To[int32](40),
+				RevertRamp: &rolloutv1alpha1.TrafficRevertRamp{Steps: 4},
+			},
+			ScaleUpStep: ptr.To(intstr.FromInt(2)),
+			Analysis: &rolloutv1alpha1.CanaryAnalysis{
+				Metrics: []rolloutv1alpha1.AnalysisMetric{{Name: "error-rate"}},
+			},
+			Bake:       &rolloutv1alpha1.CanaryBake{Windows: 2, ActiveSeconds: 600, IdleSeconds: 300},
+			PauseAfter: ptr.To(false),
+		},
+	}
+	plan, err := PlanRolloutRun(spec)
+	assert.NoError(t, err)
+	assert.Equal(t, append([]rolloutv1alpha1.RolloutStepState{StepNone}, rolloutv1alpha1.DefaultCanaryStepStates...), planStates(plan))
+
+	// no PreRunHook webhook
+	assert.Empty(t, plan.States[1].Actions)
+	assert.Equal(t, []string{"wh-1"}, plan.States[3].Actions[0].Webhooks)
+
+	running := plan.States[4]
+	assert.Equal(t, []PlanActionType{
+		PlanActionForkStable,
+		PlanActionScaleUp, PlanActionScaleUp,
+		PlanActionForkCanary, PlanActionBakeActive, PlanActionAnalysis, PlanActionBakeIdle,
+		PlanActionScaleUp, PlanActionForkCanary, PlanActionBakeActive, PlanActionAnalysis,
+	}, actionTypes(running))
+	// the target in percent is scaled up at once
+	assert.Equal(t, []intstr.IntOrString{intstr.FromInt(2), intstr.FromString("10%")}, lo.Map(running.Actions[1].Targets, func(target PlannedTarget, _ int) intstr.IntOrString {
+		return target.Replicas
+	}))
+	assert.Equal(t, intstr.FromInt(4), running.Actions[2].Targets[0].Replicas)
+	assert.Equal(t, ptr.To[int32](40), running.Actions[3].TrafficWeight)
+	assert.Equal(t, "10m0s", running.Actions[4].Duration.Duration.String())
+	assert.Equal(t, []string{"error-rate"}, running.Actions[5].Metrics)
+	assert.Equal(t, intstr.FromInt(0), running.Actions[6].Targets[0].Replicas)
+
+	post := plan.States[5]
+	assert.Equal(t, []PlanActionType{PlanActionWebhook}, actionTypes(post))
+	assert.Equal(t, []string{"wh-1", "wh-2"}, post.Actions[0].Webhooks)
+
+	recycling := plan.States[6]
+	assert.Equal(t, []PlanActionType{
+		PlanActionRampDown, PlanActionRampDown, PlanActionRampDown,
+		PlanActionRevertCanary, PlanActionDeleteCanary, PlanActionRevertStable,
+	}, actionTypes(recycling))
+	assert.Equal(t, []int32{30, 20, 10}, lo.Map(recycling.Actions[:3], func(action PlannedAction, _ int) int32 {
+		return *action.TrafficWeight
+	}))
+
+	// deletion is deferred after retention
+	spec.Canary.RetentionSeconds = ptr.To[int32](1800)
+	plan, err = PlanRolloutRun(spec)
+	assert.NoError(t, err)
+	recycling = plan.States[6]
+	assert.Equal(t, []PlanActionType{
+		PlanActionRampDown, PlanActionRampDown, PlanActionRampDown,
+		PlanActionRevertCanary, PlanActionRevertStable, PlanActionRetainCanary, PlanActionDeleteCanary,
+	}, actionTypes(recycling))
+	assert.Equal(t, "30m0s", recycling.Actions[5].Duration.Duration.String())
+}
+
+func Test_PlanRolloutRun_HoldAndBatch(t *testing.T) {
+	spec := &rolloutv1alpha1.RolloutRunSpec{
+		Webhooks: []rolloutv1alpha1.RolloutWebhook{
+			{Name: "wh-1", HookTypes: []rolloutv1alpha1.HookType{rolloutv1alpha1.PreBatchStepHook}},
+		},
+		Canary: &rolloutv1alpha1.RolloutRunCanaryStrategy{
+			Targets: []rolloutv1alpha1.RolloutRunStepTarget{
+				{CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Name: "test-1"}, Replicas: intstr.FromInt(1)},
+			},
+			HoldAtCanary: true,
+			States: []rolloutv1alpha1.RolloutStepState{
+				StepPending, StepRunning, StepPostCanaryStepHook, StepSucceeded,
+			},
+		},
+		Batch: &rolloutv1alpha1.RolloutRunBatchStrategy{
+			Batches: []rolloutv1alpha1.RolloutRunStep{
+				{Targets: []rolloutv1alpha1.RolloutRunStepTarget{{Replicas: intstr.FromString("50%")}}, Breakpoint: true},
+				{Targets: []rolloutv1alpha1.RolloutRunStepTarget{{Replicas: intstr.FromString("100%")}}},
+			},
+		},
+	}
+	plan, err := PlanRolloutRun(spec)
+	assert.NoError(t, err)
+	if !assert.Len(t, plan.States, 7+2*7) {
+		return
+	}
+
+	// Holding is inserted after Running and ResourceRecycling before Succeeded
+	assert.Equal(t, []rolloutv1alpha1.RolloutStepState{
+		StepNone, StepPending, StepRunning, StepHolding, StepPostCanaryStepHook, StepResourceRecycling, StepSucceeded,
+	}, planStates(plan)[:7])
+	assert.Equal(t, []PlanActionType{PlanActionScaleUp}, actionTypes(plan.States[2]))
+	// paused after post canary step hook by default
+	assert.Equal(t, []PlanActionType{PlanActionPause}, actionTypes(plan.States[4]))
+	assert.Equal(t, []PlanActionType{PlanActionDeleteCanary}, actionTypes(plan.States[5]))
+
+	batches := plan.States[7:]
+	assert.Equal(t, ptr.To[int32](0), batches[0].BatchIndex)
+	assert.Equal(t, []PlanActionType{PlanActionPause}, actionTypes(batches[0]))
+	assert.Equal(t, []PlanActionType{PlanActionWebhook}, actionTypes(batches[2]))
+	assert.Equal(t, intstr.FromString("50%"), batches[3].Actions[0].Targets[0].Replicas)
+	assert.Empty(t, batches[5].Actions)
+
+	assert.Equal(t, ptr.To[int32](1), batches[7].BatchIndex)
+	assert.Empty(t, batches[7].Actions)
+	assert.Equal(t, []PlanActionType{PlanActionFinalize}, actionTypes(batches[12]))
+}
+
+func Test_PlanRolloutRun_VariantsAndParameters(t *testing.T) {
+	spec := &rolloutv1alpha1.RolloutRunSpec{
+		Parameters: map[string]string{"replicas": "4"},
+		Canary: &rolloutv1alpha1.RolloutRunCanaryStrategy{
+			Targets: []rolloutv1alpha1.RolloutRunStepTarget{
+				{CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Name: "test-1"}, Replicas: intstr.FromString("${replicas}")},
+			},
+			Variants: []rolloutv1alpha1.CanaryVariant{
+				{Name: "a", Replicas: intstr.FromString("${replicas}")},
+				{Name: "b", Replicas: intstr.FromInt(1)},
+			},
+			ScaleUpStep: ptr.To(intstr.FromInt(2)),
+		},
+	}
+	plan, err := PlanRolloutRun(spec)
+	assert.NoError(t, err)
+
+	running := plan.States[4]
+	assert.Equal(t, StepRunning, running.State)
+	assert.Equal(t, []PlanActionType{PlanActionScaleUp, PlanActionScaleUp}, actionTypes(running))
+	// each variant is scaled up separately with the resolved replicas
+	assert.Equal(t, []PlannedTarget{
+		{CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Name: "test-1"}, Variant: "a", Replicas: intstr.FromInt(2)},
+		{CrossClusterObjectNameReference: rolloutv1alpha1.CrossClusterObjectNameReference{Name: "test-1"}, Variant: "b", Replicas: intstr.FromInt(1)},
+	}, running.Actions[0].Targets)
+	assert.Equal(t, intstr.FromInt(4), running.Actions[1].Targets[0].Replicas)
+	// the spec is not mutated
+	assert.Equal(t, intstr.FromString("${replicas}"), spec.Canary.Variants[0].Replicas)
+
+	// missing parameters
+	spec.Parameters = nil
+	_, err = PlanRolloutRun(spec)
+	assert.ErrorContains(t, err, "[replicas]")
+}
@@ -0,0 +1,115 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+	"time"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+const (
+	ReasonWaitingForWindow = "WaitingForWindow"
+)
+
+var weekdays = map[rolloutv1alpha1.Weekday]time.Weekday{
+	"Sun": time.Sunday,
+	"Mon": time.Monday,
+	"Tue": time.Tuesday,
+	"Wed": time.Wednesday,
+	"Thu": time.Thursday,
+	"Fri": time.Friday,
+	"Sat": time.Saturday,
+}
+
+// inPromotionWindows checks if now is in any of the promotion windows.
+// It always returns true if windows is nil.
+func inPromotionWindows(windows *rolloutv1alpha1.PromotionWindows, now time.Time) (bool, error) {
+	if windows == nil || len(windows.Windows) == 0 {
+		return true, nil
+	}
+
+	loc := time.UTC
+	if len(windows.TimeZone) > 0 {
+		var err error
+		loc, err = time.LoadLocation(windows.TimeZone)
+		if err != nil {
+			return false, fmt.Errorf("invalid time zone %q: %w", windows.TimeZone, err)
+		}
+	}
+	now = now.In(loc)
+
+	for _, w := range windows.Windows {
+		in, err := inTimeWindow(w, now)
+		if err != nil {
+			return false, err
+		}
+		if in {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func inTimeWindow(w rolloutv1alpha1.TimeWindow, now time.Time) (bool, error) {
+	start, err := parseClock(w.Start)
+	if err != nil {
+		return false, err
+	}
+	end, err := parseClock(w.End)
+	if err != nil {
+		return false, err
+	}
+
+	clock := time.Duration(now.Hour())*time.Hour + time.Duration(now.Minute())*time.Minute + time.Duration(now.Second())*time.Second
+	today := now.Weekday()
+	yesterday := (today + 6) % 7
+
+	if start < end {
+		return clock >= start && clock < end && matchWeekday(w.Days, today), nil
+	}
+
+	// the window crosses midnight, the days refer to the day the window starts
+	if clock >= start && matchWeekday(w.Days, today) {
+		return true, nil
+	}
+	if clock < end && matchWeekday(w.Days, yesterday) {
+		return true, nil
+	}
+	return false, nil
+}
+
+func matchWeekday(days []rolloutv1alpha1.Weekday, day time.Weekday) bool {
+	if len(days) == 0 {
+		return true
+	}
+	for _, d := range days {
+		if weekdays[d] == day {
+			return true
+		}
+	}
+	return false
+}
+
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, must be in HH:MM format", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
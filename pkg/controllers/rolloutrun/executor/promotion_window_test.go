@@ -0,0 +1,143 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+func Test_inPromotionWindows(t *testing.T) {
+	// 2024-01-01 is Monday
+	monday := func(hour, min int) time.Time {
+		return time.Date(2024, 1, 1, hour, min, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name    string
+		windows *rolloutv1alpha1.PromotionWindows
+		now     time.Time
+		want    bool
+		wantErr bool
+	}{
+		{
+			name:    "nil windows",
+			windows: nil,
+			now:     monday(3, 0),
+			want:    true,
+		},
+		{
+			name: "in business hours",
+			windows: &rolloutv1alpha1.PromotionWindows{
+				Windows: []rolloutv1alpha1.TimeWindow{{Days: []rolloutv1alpha1.Weekday{"Mon", "Tue"}, Start: "09:00", End: "18:00"}},
+			},
+			now:  monday(10, 30),
+			want: true,
+		},
+		{
+			name: "end is exclusive",
+			windows: &rolloutv1alpha1.PromotionWindows{
+				Windows: []rolloutv1alpha1.TimeWindow{{Start: "09:00", End: "18:00"}},
+			},
+			now:  monday(18, 0),
+			want: false,
+		},
+		{
+			name: "day not matched",
+			windows: &rolloutv1alpha1.PromotionWindows{
+				Windows: []rolloutv1alpha1.TimeWindow{{Days: []rolloutv1alpha1.Weekday{"Sat", "Sun"}, Start: "09:00", End: "18:00"}},
+			},
+			now:  monday(10, 0),
+			want: false,
+		},
+		{
+			name: "window crosses midnight",
+			windows: &rolloutv1alpha1.PromotionWindows{
+				Windows: []rolloutv1alpha1.TimeWindow{{Days: []rolloutv1alpha1.Weekday{"Sun"}, Start: "22:00", End: "02:00"}},
+			},
+			now:  monday(1, 0),
+			want: true,
+		},
+		{
+			name: "time zone",
+			windows: &rolloutv1alpha1.PromotionWindows{
+				TimeZone: "Asia/Shanghai",
+				Windows:  []rolloutv1alpha1.TimeWindow{{Start: "09:00", End: "18:00"}},
+			},
+			// 17:00 in Asia/Shanghai
+			now:  monday(9, 0),
+			want: true,
+		},
+		{
+			name: "invalid time zone",
+			windows: &rolloutv1alpha1.PromotionWindows{
+				TimeZone: "Invalid/Zone",
+				Windows:  []rolloutv1alpha1.TimeWindow{{Start: "09:00", End: "18:00"}},
+			},
+			now:     monday(10, 0),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := inPromotionWindows(tt.windows, tt.now)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_CanaryExecutor_doRecycle_WaitingForWindow(t *testing.T) {
+	tomorrow := rolloutv1alpha1.Weekday(time.Now().UTC().Add(24 * time.Hour).Format("Mon"))
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary.PromotionWindows = &rolloutv1alpha1.PromotionWindows{
+		Windows: []rolloutv1alpha1.TimeWindow{{Days: []rolloutv1alpha1.Weekday{tomorrow}, Start: "00:00", End: "23:59"}},
+	}
+	rolloutRun.Spec.Canary.PauseBefore = []rolloutv1alpha1.CanaryPausePhase{rolloutv1alpha1.CanaryPauseBeforePromotion}
+	rolloutRun.Spec.Canary.PauseAfter = ptr.To(false)
+	rolloutRun.Status.Phase = rolloutv1alpha1.RolloutRunPhaseProgressing
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{
+		State: StepResourceRecycling,
+	}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+	e := newCanaryExecutor(newFakeWebhookExecutor())
+
+	// promotion is held out of windows
+	done, retry, err := e.doRecycle(ctx)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, retryDefault, retry)
+	assert.Equal(t, rolloutv1alpha1.StepWaitingForWindow, ctx.NewStatus.CanaryStatus.WaitingReason)
+
+	// the reason is cleared once the window opens, then canary is paused before promotion
+	ctx.RolloutRun.Spec.Canary.PromotionWindows.Windows[0] = rolloutv1alpha1.TimeWindow{Start: "00:00", End: "00:00"}
+	done, _, err = e.doRecycle(ctx)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, rolloutv1alpha1.StepPaused, ctx.NewStatus.CanaryStatus.WaitingReason)
+	assert.Equal(t, rolloutv1alpha1.CanaryPauseBeforePromotion, ctx.NewStatus.CanaryStatus.PausedBefore)
+	assert.Equal(t, rolloutv1alpha1.RolloutRunPhasePaused, ctx.NewStatus.Phase)
+}
@@ -0,0 +1,233 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutapi "kusionstack.io/rollout/apis/rollout"
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/analysis"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/webhook/probe"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/webhook/probe/http"
+	"kusionstack.io/rollout/pkg/genericregistry"
+)
+
+const (
+	// ReasonStepGuardViolated is the code of error and the reason of event when
+	// a guard of step is violated.
+	ReasonStepGuardViolated = "StepGuardViolated"
+)
+
+// guardedStates are the step states in which guards are checked, from the pre
+// step hook to the post step hook.
+var guardedStates = map[rolloutv1alpha1.RolloutStepState]bool{
+	StepPreCanaryStepHook:  true,
+	StepRunning:            true,
+	StepHolding:            true,
+	StepPostCanaryStepHook: true,
+	StepPreBatchStepHook:   true,
+	StepPostBatchStepHook:  true,
+}
+
+// stepGuardChecker checks the guards of current step on every reconcile.
+type stepGuardChecker struct {
+	analysisProviders genericregistry.Registry[string, analysis.AnalysisProvider]
+	newProber         func(config rolloutv1alpha1.WebhookClientConfig, cert *http.ClientCert) (probe.WebhookProber, error)
+}
+
+func newStepGuardChecker() *stepGuardChecker {
+	return &stepGuardChecker{
+		analysisProviders: analysis.Providers,
+		newProber:         http.NewWithClientCert,
+	}
+}
+
+// check evaluates the guards of current step if it is in a guarded state. The
+// rolloutRun fails with a terminal error once any guard is violated, and the
+// violation is recorded in step status. Guards which can not be evaluated now,
+// e.g. the metric query fails, are skipped and evaluated in the next check.
+func (g *stepGuardChecker) check(ctx *ExecutorContext, guards []rolloutv1alpha1.StepGuard, status *rolloutv1alpha1.RolloutRunStepStatus) error {
+	if len(guards) == 0 || status == nil || !guardedStates[status.State] {
+		return nil
+	}
+
+	for i := range guards {
+		guard := &guards[i]
+		violation, err := g.evaluate(ctx, guard)
+		if err != nil {
+			ctx.Recorder.Eventf(ctx.RolloutRun, corev1.EventTypeWarning, "FailedRunStep", "step failed, currentState %s, err: %v", status.State, err)
+			ctx.Fail(err)
+			return err
+		}
+		if violation == nil {
+			continue
+		}
+
+		violation.Time = ptr.To(metav1.Now())
+		status.GuardViolation = violation
+		ctx.Recorder.Eventf(ctx.RolloutRun, corev1.EventTypeWarning, ReasonStepGuardViolated,
+			"guard %s is violated in state %s: %s", guard.Name, status.State, violation.Message)
+		err = control.TerminalError(&rolloutv1alpha1.CodeReasonMessage{
+			Code:    ReasonStepGuardViolated,
+			Reason:  guardViolationReason(guard),
+			Message: fmt.Sprintf("guard %s is violated: %s", guard.Name, violation.Message),
+		})
+		ctx.Fail(err)
+		return err
+	}
+	return nil
+}
+
+func guardViolationReason(guard *rolloutv1alpha1.StepGuard) string {
+	if guard.Metric != nil {
+		return "MetricOutOfThreshold"
+	}
+	return "WebhookRejected"
+}
+
+// evaluate returns the violation of guard, or nil if the guard holds or can not
+// be evaluated now.
+func (g *stepGuardChecker) evaluate(ctx *ExecutorContext, guard *rolloutv1alpha1.StepGuard) (*rolloutv1alpha1.StepGuardViolation, error) {
+	switch {
+	case guard.Metric != nil:
+		return g.evaluateMetric(ctx, guard)
+	case guard.Webhook != nil:
+		return g.evaluateWebhook(ctx, guard)
+	}
+	return nil, nil
+}
+
+func (g *stepGuardChecker) evaluateMetric(ctx *ExecutorContext, guard *rolloutv1alpha1.StepGuard) (*rolloutv1alpha1.StepGuardViolation, error) {
+	logger := ctx.GetLogger()
+	metric := &rolloutv1alpha1.AnalysisMetric{
+		Name:     guard.Name,
+		Provider: guard.Metric.Provider,
+		Query:    guard.Metric.Query,
+		Min:      guard.Metric.Min,
+		Max:      guard.Metric.Max,
+	}
+	provider, err := g.analysisProviders.Get(metric.Provider.Name)
+	if err != nil {
+		return nil, control.TerminalError(&rolloutv1alpha1.CodeReasonMessage{
+			Code:    ReasonStepGuardViolated,
+			Reason:  "UnknownAnalysisProvider",
+			Message: fmt.Sprintf("analysis provider %q of guard %s is not found", metric.Provider.Name, guard.Name),
+		})
+	}
+
+	value, err := provider.Query(ctx, metric)
+	if err != nil {
+		reason := "AnalysisQueryFailed"
+		var perr *analysis.ProviderError
+		if errors.As(err, &perr) {
+			reason = perr.Reason
+		}
+		logger.Info("failed to query metric of step guard, check it later", "guard", guard.Name, "reason", reason, "err", err.Error())
+		return nil, nil
+	}
+
+	ok, err := provider.CompareThreshold(value, metric)
+	if err != nil {
+		return nil, control.TerminalError(&rolloutv1alpha1.CodeReasonMessage{
+			Code:    ReasonStepGuardViolated,
+			Reason:  "InvalidAnalysisThreshold",
+			Message: err.Error(),
+		})
+	}
+	if ok {
+		return nil, nil
+	}
+	observed := strconv.FormatFloat(value, 'g', -1, 64)
+	return &rolloutv1alpha1.StepGuardViolation{
+		Name:    guard.Name,
+		Value:   observed,
+		Message: fmt.Sprintf("metric value %s is out of threshold [%s, %s]", observed, ptrOrEmpty(metric.Min), ptrOrEmpty(metric.Max)),
+	}, nil
+}
+
+func (g *stepGuardChecker) evaluateWebhook(ctx *ExecutorContext, guard *rolloutv1alpha1.StepGuard) (*rolloutv1alpha1.StepGuardViolation, error) {
+	logger := ctx.GetLogger()
+	cert, err := loadWebhookClientCert(ctx, guard.Name, *guard.Webhook)
+	if err != nil {
+		return nil, err
+	}
+	prober, err := g.newProber(*guard.Webhook, cert)
+	if err != nil {
+		return nil, control.TerminalError(&rolloutv1alpha1.CodeReasonMessage{
+			Code:    ReasonStepGuardViolated,
+			Reason:  "InvalidWebhookConfig",
+			Message: fmt.Sprintf("failed to create prober of guard %s, err: %v", guard.Name, err),
+		})
+	}
+
+	review := ctx.makeStepGuardReview(guard)
+	result := prober.Probe(&review)
+	switch {
+	case result.Code == rolloutv1alpha1.WebhookReviewCodeError && result.Reason != probe.ReasonUnreachable:
+		return &rolloutv1alpha1.StepGuardViolation{
+			Name:    guard.Name,
+			Message: fmt.Sprintf("webhook responds %s: %s", result.Reason, result.Message),
+		}, nil
+	case result.Code == rolloutv1alpha1.WebhookReviewCodeError:
+		logger.Info("webhook of step guard is unreachable, check it later", "guard", guard.Name, "message", result.Message)
+	}
+	return nil, nil
+}
+
+func (c *ExecutorContext) makeStepGuardReview(guard *rolloutv1alpha1.StepGuard) rolloutv1alpha1.RolloutWebhookReview {
+	rolloutRun := c.RolloutRun
+	review := rolloutv1alpha1.RolloutWebhookReview{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: rolloutRun.Namespace,
+			Name:      guard.Name,
+			Annotations: map[string]string{
+				rolloutapi.AnnoTraceParent: traceParent(c.TraceID, fmt.Sprintf("%s/%s", rolloutv1alpha1.StepGuardHook, guard.Name)),
+			},
+		},
+		Spec: rolloutv1alpha1.RolloutWebhookReviewSpec{
+			Kind:        c.OwnerKind,
+			RolloutName: c.OwnerName,
+			RolloutID:   rolloutRun.Name,
+			HookType:    rolloutv1alpha1.StepGuardHook,
+			TargetType:  rolloutRun.Spec.TargetType,
+		},
+	}
+	if c.inCanary() {
+		review.Spec.Canary = &rolloutv1alpha1.RolloutWebhookReviewCanary{
+			Targets:    rolloutRun.Spec.Canary.Targets,
+			State:      c.NewStatus.CanaryStatus.State,
+			Properties: rolloutRun.Spec.Canary.Properties,
+		}
+	} else {
+		index := c.NewStatus.BatchStatus.CurrentBatchIndex
+		batch := rolloutRun.Spec.Batch.Batches[index]
+		review.Spec.Batch = &rolloutv1alpha1.RolloutWebhookReviewBatch{
+			BatchIndex: index,
+			Targets:    batch.Targets,
+			Properties: batch.Properties,
+		}
+	}
+	return review
+}
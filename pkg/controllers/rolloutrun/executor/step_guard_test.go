@@ -0,0 +1,172 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/analysis"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/webhook/probe"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/webhook/probe/http"
+	"kusionstack.io/rollout/pkg/genericregistry"
+)
+
+type fakeGuardProvider struct {
+	value float64
+	err   error
+}
+
+func (p *fakeGuardProvider) Query(_ context.Context, _ *rolloutv1alpha1.AnalysisMetric) (float64, error) {
+	return p.value, p.err
+}
+
+func (p *fakeGuardProvider) CompareThreshold(value float64, _ *rolloutv1alpha1.AnalysisMetric) (bool, error) {
+	return value <= 0.01, nil
+}
+
+type fakeGuardProber struct {
+	result   probe.Result
+	reviewed *rolloutv1alpha1.RolloutWebhookReview
+}
+
+func (p *fakeGuardProber) Probe(review *rolloutv1alpha1.RolloutWebhookReview) probe.Result {
+	p.reviewed = review
+	return p.result
+}
+
+func Test_stepGuardChecker(t *testing.T) {
+	provider := &fakeGuardProvider{}
+	prober := &fakeGuardProber{}
+	providers := genericregistry.New[string, analysis.AnalysisProvider]()
+	providers.Register("fake", provider)
+	g := &stepGuardChecker{
+		analysisProviders: providers,
+		newProber: func(_ rolloutv1alpha1.WebhookClientConfig, _ *http.ClientCert) (probe.WebhookProber, error) {
+			return prober, nil
+		},
+	}
+	metricGuards := []rolloutv1alpha1.StepGuard{{
+		Name: "error-rate",
+		Metric: &rolloutv1alpha1.GuardMetric{
+			Provider: rolloutv1alpha1.AnalysisProvider{Name: "fake"},
+			Query:    "error_rate",
+			Max:      ptr.To("0.01"),
+		},
+	}}
+	webhookGuards := []rolloutv1alpha1.StepGuard{{
+		Name:    "slo",
+		Webhook: &rolloutv1alpha1.WebhookClientConfig{URL: "http://slo"},
+	}}
+
+	tests := []struct {
+		name          string
+		guards        []rolloutv1alpha1.StepGuard
+		state         rolloutv1alpha1.RolloutStepState
+		value         float64
+		queryErr      error
+		result        probe.Result
+		wantViolation bool
+		wantValue     string
+	}{
+		{
+			name:   "guards are not checked before the pre step hook",
+			guards: metricGuards,
+			state:  StepPending,
+			value:  0.5,
+		},
+		{
+			name:   "metric within threshold",
+			guards: metricGuards,
+			state:  StepRunning,
+			value:  0.001,
+		},
+		{
+			name:     "metric query failure is checked later",
+			guards:   metricGuards,
+			state:    StepRunning,
+			queryErr: errors.New("timeout"),
+		},
+		{
+			name:          "metric out of threshold",
+			guards:        metricGuards,
+			state:         StepPostCanaryStepHook,
+			value:         0.5,
+			wantViolation: true,
+			wantValue:     "0.5",
+		},
+		{
+			name:   "webhook passes",
+			guards: webhookGuards,
+			state:  StepRunning,
+			result: probe.Result{Code: rolloutv1alpha1.WebhookReviewCodeOK},
+		},
+		{
+			name:   "unreachable webhook is checked later",
+			guards: webhookGuards,
+			state:  StepRunning,
+			result: probe.Result{Code: rolloutv1alpha1.WebhookReviewCodeError, Reason: probe.ReasonUnreachable},
+		},
+		{
+			name:          "webhook rejects",
+			guards:        webhookGuards,
+			state:         StepHolding,
+			result:        probe.Result{Code: rolloutv1alpha1.WebhookReviewCodeError, Reason: "SLOBurning", Message: "error budget exhausted"},
+			wantViolation: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider.value, provider.err = tt.value, tt.queryErr
+			prober.result, prober.reviewed = tt.result, nil
+
+			rolloutRun := testCanaryRolloutRun.DeepCopy()
+			rolloutRun.Spec.Canary.Guards = tt.guards
+			ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+			ctx.Initialize()
+			status := ctx.NewStatus.CanaryStatus
+			status.State = tt.state
+
+			err := g.check(ctx, tt.guards, status)
+			if !tt.wantViolation {
+				assert.NoError(t, err)
+				assert.Nil(t, status.GuardViolation)
+				assert.Nil(t, ctx.NewStatus.Error)
+				return
+			}
+			assert.True(t, errors.Is(err, control.TerminalError(nil)))
+			if assert.NotNil(t, status.GuardViolation) {
+				assert.Equal(t, tt.guards[0].Name, status.GuardViolation.Name)
+				assert.Equal(t, tt.wantValue, status.GuardViolation.Value)
+				assert.NotNil(t, status.GuardViolation.Time)
+			}
+			if assert.NotNil(t, ctx.NewStatus.Error) {
+				assert.Equal(t, ReasonStepGuardViolated, ctx.NewStatus.Error.Code)
+			}
+			if prober.reviewed != nil {
+				assert.Equal(t, rolloutv1alpha1.StepGuardHook, prober.reviewed.Spec.HookType)
+				assert.Equal(t, tt.state, prober.reviewed.Spec.Canary.State)
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ package executor
 import (
 	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/samber/lo"
@@ -29,12 +30,81 @@ import (
 	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
 )
 
+// retryStop, retryImmediately and retryDefault are returned by state processes
+// to indicate how to requeue, stepStateMachine translates retryImmediately and
+// retryDefault to the intervals in RetryOptions of ExecutorContext. Other
+// durations are computed waits and requeued as they are, so retryStop and
+// retryDefault are sentinels which are never computed. They must not be used
+// in arithmetic, e.g. use RetryOptions.Default to cap a computed wait.
 const (
-	retryStop        = time.Duration(-1)
+	retryStop        = time.Duration(math.MinInt64)
+	retryDefault     = time.Duration(math.MinInt64 + 1)
 	retryImmediately = time.Duration(0)
-	retryDefault     = 5 * time.Second
+
+	defaultRetryInterval = 5 * time.Second
+	defaultTrafficResync = 30 * time.Second
 )
 
+// RetryOptions defines the requeue intervals of executor.
+type RetryOptions struct {
+	// Default is the interval to requeue when a step is waiting for something.
+	Default time.Duration
+	// Immediately is the interval to requeue when a step wants to continue as
+	// soon as possible, 0 means requeue immediately.
+	Immediately time.Duration
+	// TrafficResync is the interval to re-verify the forked canary traffic rules
+	// while canary is active, 0 means no resync.
+	TrafficResync time.Duration
+}
+
+// DefaultRetryOptions returns the default RetryOptions.
+func DefaultRetryOptions() RetryOptions {
+	return RetryOptions{
+		Default:       defaultRetryInterval,
+		Immediately:   retryImmediately,
+		TrafficResync: defaultTrafficResync,
+	}
+}
+
+// Validate checks if the RetryOptions is valid.
+func (o RetryOptions) Validate() error {
+	if o.Default < 0 || o.Immediately < 0 {
+		return fmt.Errorf("retry intervals must be non-negative, got default=%v, immediately=%v", o.Default, o.Immediately)
+	}
+	if o.TrafficResync < 0 {
+		return fmt.Errorf("traffic resync interval must be non-negative, got %v", o.TrafficResync)
+	}
+	if o.Immediately >= o.Default {
+		return fmt.Errorf("retry immediately interval %v must be less than default interval %v", o.Immediately, o.Default)
+	}
+	return nil
+}
+
+// result translates the retry returned by state process to reconcile result.
+func (o RetryOptions) result(retry time.Duration) ctrl.Result {
+	switch retry {
+	case retryStop:
+		return ctrl.Result{}
+	case retryImmediately:
+		return o.immediately()
+	case retryDefault:
+		return ctrl.Result{RequeueAfter: o.Default}
+	default:
+		if retry < 0 {
+			// the computed wait has passed
+			return o.immediately()
+		}
+		return ctrl.Result{RequeueAfter: retry}
+	}
+}
+
+func (o RetryOptions) immediately() ctrl.Result {
+	if o.Immediately > 0 {
+		return ctrl.Result{RequeueAfter: o.Immediately}
+	}
+	return ctrl.Result{Requeue: true}
+}
+
 func newUnknownStepStateError(state rolloutv1alpha1.RolloutStepState) *rolloutv1alpha1.CodeReasonMessage {
 	return &rolloutv1alpha1.CodeReasonMessage{
 		Code:    "Error",
@@ -73,6 +143,58 @@ func (e *stepStateMachine) add(state, nextState rolloutv1alpha1.RolloutStepState
 	})
 }
 
+// StepTransitionType is the type of an edge in the step state machine.
+type StepTransitionType string
+
+const (
+	// StepTransitionForward moves a step towards StepSucceeded.
+	StepTransitionForward StepTransitionType = "Forward"
+	// StepTransitionRollback moves a step back to undo its changes.
+	StepTransitionRollback StepTransitionType = "Rollback"
+	// StepTransitionRestart moves a recycled step back to its entry state to
+	// retry it.
+	StepTransitionRestart StepTransitionType = "Restart"
+)
+
+// StepTransition is an edge in the step state machine.
+type StepTransition struct {
+	From rolloutv1alpha1.RolloutStepState `json:"from"`
+	To   rolloutv1alpha1.RolloutStepState `json:"to"`
+	Type StepTransitionType               `json:"type"`
+}
+
+// StepGraph is a read-only view of a step state machine, it can be used by
+// tools to render the transitions and highlight the current state.
+type StepGraph struct {
+	// States contains all states in the order they were added.
+	States []rolloutv1alpha1.RolloutStepState `json:"states"`
+	// Edges contains all transitions between states.
+	Edges []StepTransition `json:"edges"`
+	// Current is the state the step currently stays in.
+	Current rolloutv1alpha1.RolloutStepState `json:"current"`
+}
+
+func (e *stepStateMachine) graph(current rolloutv1alpha1.RolloutStepState) StepGraph {
+	g := StepGraph{
+		States:  make([]rolloutv1alpha1.RolloutStepState, 0, len(e.lifecycle)),
+		Edges:   make([]StepTransition, 0, len(e.lifecycle)),
+		Current: current,
+	}
+	for _, step := range e.lifecycle {
+		g.States = append(g.States, step.current)
+		if len(step.next) == 0 {
+			// final state
+			continue
+		}
+		g.Edges = append(g.Edges, StepTransition{
+			From: step.current,
+			To:   step.next,
+			Type: StepTransitionForward,
+		})
+	}
+	return g
+}
+
 func (e *stepStateMachine) do(ctx *ExecutorContext, currentState rolloutv1alpha1.RolloutStepState) (done bool, result ctrl.Result, err error) {
 	lifecycle, found := lo.Find(e.lifecycle, func(step stepLifecycle) bool {
 		return step.current == currentState
@@ -104,14 +226,5 @@ func (e *stepStateMachine) do(ctx *ExecutorContext, currentState rolloutv1alpha1
 		}
 	}
 
-	switch retry {
-	case retryStop:
-		result = ctrl.Result{}
-	case retryImmediately:
-		result = ctrl.Result{Requeue: true}
-	default:
-		result = ctrl.Result{RequeueAfter: retry}
-	}
-
-	return done, result, nil
+	return done, ctx.Retry.result(retry), nil
 }
@@ -0,0 +1,180 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+func TestExecutor_CanaryStepGraph(t *testing.T) {
+	e := NewDefaultExecutor(newTestLogger())
+
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{
+		State: StepRunning,
+	}
+	g := e.CanaryStepGraph(rolloutRun)
+
+	assert.Equal(t, StepRunning, g.Current)
+	assert.Equal(t, []rolloutv1alpha1.RolloutStepState{
+		StepNone,
+		StepPreRunHook,
+		StepPending,
+		StepPreCanaryStepHook,
+		StepRunning,
+		StepPostCanaryStepHook,
+		StepResourceRecycling,
+		StepSucceeded,
+	}, g.States)
+	// final state has no outgoing edge
+	forward := lo.Filter(g.Edges, func(edge StepTransition, _ int) bool { return edge.Type == StepTransitionForward })
+	assert.Len(t, forward, len(g.States)-1)
+	assert.Contains(t, g.Edges, StepTransition{From: StepRunning, To: StepPostCanaryStepHook, Type: StepTransitionForward})
+	assert.Contains(t, g.Edges, StepTransition{From: StepRunning, To: StepResourceRecycling, Type: StepTransitionRollback})
+	assert.Contains(t, g.Edges, StepTransition{From: StepPostCanaryStepHook, To: StepResourceRecycling, Type: StepTransitionRollback})
+	assert.NotContains(t, g.Edges, StepTransition{From: StepResourceRecycling, To: StepPending, Type: StepTransitionRestart})
+
+	// the states declared in rolloutRun are honored
+	rolloutRun.Spec.Canary.States = []rolloutv1alpha1.RolloutStepState{
+		StepPending,
+		StepRunning,
+		StepResourceRecycling,
+		StepSucceeded,
+	}
+	rolloutRun.Spec.Canary.HoldAtCanary = true
+	rolloutRun.Spec.Canary.AutoRollback = &rolloutv1alpha1.CanaryAutoRollback{MaxRollbacks: 1}
+	rolloutRun.Status.CanaryStatus.State = StepHolding
+	g = e.CanaryStepGraph(rolloutRun)
+	assert.Equal(t, StepHolding, g.Current)
+	assert.Equal(t, []rolloutv1alpha1.RolloutStepState{
+		StepNone,
+		StepPending,
+		StepRunning,
+		StepHolding,
+		StepResourceRecycling,
+		StepSucceeded,
+	}, g.States)
+	assert.ElementsMatch(t, []StepTransition{
+		{From: StepNone, To: StepPending, Type: StepTransitionForward},
+		{From: StepPending, To: StepRunning, Type: StepTransitionForward},
+		{From: StepRunning, To: StepHolding, Type: StepTransitionForward},
+		{From: StepHolding, To: StepResourceRecycling, Type: StepTransitionForward},
+		{From: StepResourceRecycling, To: StepSucceeded, Type: StepTransitionForward},
+		{From: StepRunning, To: StepResourceRecycling, Type: StepTransitionRollback},
+		{From: StepHolding, To: StepResourceRecycling, Type: StepTransitionRollback},
+		{From: StepResourceRecycling, To: StepPending, Type: StepTransitionRestart},
+	}, g.Edges)
+}
+
+func TestCanaryExecutor_stateMachineOf(t *testing.T) {
+	e := newCanaryExecutor(newFakeWebhookExecutor())
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	ctx := &ExecutorContext{RolloutRun: rolloutRun}
+
+	// defaults to all states
+	assert.Equal(t, e.stateMachine, e.stateMachineOf(ctx.RolloutRun))
+
+	rolloutRun.Spec.Canary.States = []rolloutv1alpha1.RolloutStepState{
+		StepPending,
+		StepRunning,
+		StepPostCanaryStepHook,
+		StepSucceeded,
+	}
+	g := e.stateMachineOf(ctx.RolloutRun).graph(StepNone)
+	// ResourceRecycling is always included for rollback
+	assert.Equal(t, []rolloutv1alpha1.RolloutStepState{
+		StepNone,
+		StepPending,
+		StepRunning,
+		StepPostCanaryStepHook,
+		StepResourceRecycling,
+		StepSucceeded,
+	}, g.States)
+	assert.Contains(t, g.Edges, StepTransition{From: StepNone, To: StepPending, Type: StepTransitionForward})
+	assert.Contains(t, g.Edges, StepTransition{From: StepPending, To: StepRunning, Type: StepTransitionForward})
+	assert.Contains(t, g.Edges, StepTransition{From: StepPostCanaryStepHook, To: StepResourceRecycling, Type: StepTransitionForward})
+	assert.Contains(t, g.Edges, StepTransition{From: StepResourceRecycling, To: StepSucceeded, Type: StepTransitionForward})
+}
+
+func TestExecutor_BatchStepGraph(t *testing.T) {
+	e := NewDefaultExecutor(newTestLogger())
+
+	g := e.BatchStepGraph(nil)
+	assert.Equal(t, StepNone, g.Current)
+	assert.Contains(t, g.Edges, StepTransition{From: StepNone, To: StepPending, Type: StepTransitionForward})
+	assert.Contains(t, g.Edges, StepTransition{From: StepResourceRecycling, To: StepSucceeded, Type: StepTransitionForward})
+}
+
+func TestRetryOptions(t *testing.T) {
+	assert.NoError(t, DefaultRetryOptions().Validate())
+	assert.Error(t, RetryOptions{Default: -time.Second}.Validate())
+	assert.Error(t, RetryOptions{Default: time.Second, Immediately: -time.Second}.Validate())
+	assert.Error(t, RetryOptions{Default: time.Second, Immediately: time.Second}.Validate())
+
+	o := RetryOptions{Default: 10 * time.Second, Immediately: time.Second}
+	assert.Equal(t, ctrl.Result{}, o.result(retryStop))
+	assert.Equal(t, ctrl.Result{RequeueAfter: time.Second}, o.result(retryImmediately))
+	assert.Equal(t, ctrl.Result{RequeueAfter: 10 * time.Second}, o.result(retryDefault))
+	assert.Equal(t, ctrl.Result{RequeueAfter: 3 * time.Second}, o.result(3*time.Second))
+	// computed waits equal to the default interval are not distorted
+	assert.Equal(t, ctrl.Result{RequeueAfter: defaultRetryInterval}, o.result(defaultRetryInterval))
+	// computed waits which have passed are requeued immediately
+	assert.Equal(t, ctrl.Result{RequeueAfter: time.Second}, o.result(-time.Second))
+	assert.Equal(t, ctrl.Result{Requeue: true}, DefaultRetryOptions().result(retryImmediately))
+}
+
+func TestStepStateMachine_retryFromContext(t *testing.T) {
+	m := newStepStateMachine()
+	m.add(StepNone, StepPending, func(*ExecutorContext) (bool, time.Duration, error) {
+		return false, retryDefault, nil
+	})
+
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), testRolloutRun.DeepCopy())
+	_, result, err := m.do(ctx, StepNone)
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{RequeueAfter: defaultRetryInterval}, result)
+
+	ctx.Retry = RetryOptions{Default: time.Minute, Immediately: time.Second}
+	_, result, err = m.do(ctx, StepNone)
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{RequeueAfter: time.Minute}, result)
+}
+
+func TestExecutor_WithResultDecorator(t *testing.T) {
+	var gotStep string
+	var gotState rolloutv1alpha1.RolloutStepState
+	e := NewDefaultExecutor(newTestLogger()).WithResultDecorator(func(step string, state rolloutv1alpha1.RolloutStepState, result ctrl.Result) ctrl.Result {
+		gotStep, gotState = step, state
+		return ctrl.Result{RequeueAfter: time.Hour}
+	})
+
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), testRolloutRun.DeepCopy(), unimportantWorkloads()...)
+	done, result, err := e.Do(ctx)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, ctrl.Result{RequeueAfter: time.Hour}, result)
+	step, state := ctx.GetCurrentState()
+	assert.Equal(t, step, gotStep)
+	assert.Equal(t, state, gotState)
+}
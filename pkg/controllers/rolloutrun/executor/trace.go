@@ -0,0 +1,81 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	rolloutapi "kusionstack.io/rollout/apis/rollout"
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+// traceParentPattern matches a W3C traceparent of version 00, the trace id is
+// captured.
+var traceParentPattern = regexp.MustCompile(`^00-([0-9a-f]{32})-[0-9a-f]{16}-[0-9a-f]{2}$`)
+
+const invalidTraceID = "00000000000000000000000000000000"
+
+// traceIDOf returns the trace id of rolloutRun. The trace id in the traceparent
+// annotation is used if present, otherwise it is derived from the uid so that
+// it keeps the same across reconciliations.
+func traceIDOf(rolloutRun *rolloutv1alpha1.RolloutRun) string {
+	if match := traceParentPattern.FindStringSubmatch(rolloutRun.Annotations[rolloutapi.AnnoTraceParent]); match != nil && match[1] != invalidTraceID {
+		return match[1]
+	}
+	// uid is a uuid which has the same size as trace id
+	id := strings.ReplaceAll(string(rolloutRun.UID), "-", "")
+	if _, err := hex.DecodeString(id); err == nil && len(id) == 32 && id != invalidTraceID {
+		return id
+	}
+	h := fnv.New128a()
+	h.Write([]byte(rolloutRun.Namespace + "/" + rolloutRun.Name + "/" + string(rolloutRun.UID))) // nolint
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// traceParent returns the W3C traceparent of the span in trace, the span id is
+// derived from span name so that the same call keeps the same traceparent.
+func traceParent(traceID, span string) string {
+	h := fnv.New64a()
+	h.Write([]byte(span)) // nolint
+	spanID := h.Sum64()
+	if spanID == 0 {
+		// all zero span id is invalid
+		spanID = 1
+	}
+	return fmt.Sprintf("00-%s-%016x-01", traceID, spanID)
+}
+
+// traceEventRecorder annotates events with the trace id.
+type traceEventRecorder struct {
+	record.EventRecorder
+	traceID string
+}
+
+func (r *traceEventRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.EventRecorder.AnnotatedEventf(object, map[string]string{rolloutapi.AnnoTraceID: r.traceID}, eventtype, reason, "%s", message)
+}
+
+func (r *traceEventRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.EventRecorder.AnnotatedEventf(object, map[string]string{rolloutapi.AnnoTraceID: r.traceID}, eventtype, reason, messageFmt, args...)
+}
@@ -0,0 +1,70 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rolloutapi "kusionstack.io/rollout/apis/rollout"
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+func Test_traceIDOf(t *testing.T) {
+	tests := []struct {
+		name       string
+		rolloutRun *rolloutv1alpha1.RolloutRun
+		want       string
+	}{
+		{
+			name: "from traceparent annotation",
+			rolloutRun: &rolloutv1alpha1.RolloutRun{ObjectMeta: metav1.ObjectMeta{
+				UID:         "b7ad6b7169203331b7ad6b7169203331",
+				Annotations: map[string]string{rolloutapi.AnnoTraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			}},
+			want: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name: "invalid traceparent annotation",
+			rolloutRun: &rolloutv1alpha1.RolloutRun{ObjectMeta: metav1.ObjectMeta{
+				UID:         "2b5a3c0e-6f4d-4f5e-9a8b-7c6d5e4f3a2b",
+				Annotations: map[string]string{rolloutapi.AnnoTraceParent: "invalid"},
+			}},
+			want: "2b5a3c0e6f4d4f5e9a8b7c6d5e4f3a2b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, traceIDOf(tt.rolloutRun))
+		})
+	}
+
+	// derived from name if uid is not set
+	run := &rolloutv1alpha1.RolloutRun{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	assert.Regexp(t, "^[0-9a-f]{32}$", traceIDOf(run))
+	assert.Equal(t, traceIDOf(run), traceIDOf(run.DeepCopy()))
+}
+
+func Test_traceParent(t *testing.T) {
+	traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+	got := traceParent(traceID, "PreCanaryStepHook/wh-01")
+	assert.Regexp(t, traceParentPattern, got)
+	assert.Equal(t, got, traceParent(traceID, "PreCanaryStepHook/wh-01"))
+	assert.NotEqual(t, got, traceParent(traceID, "PostCanaryStepHook/wh-01"))
+}
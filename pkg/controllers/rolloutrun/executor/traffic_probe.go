@@ -0,0 +1,152 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+)
+
+const (
+	defaultTrafficProbeTimeoutSeconds   = 5
+	defaultTrafficProbeBudgetSeconds    = 60
+	defaultTrafficProbeSuccessThreshold = 1
+)
+
+// trafficProber sends a synthetic request through canary route.
+type trafficProber interface {
+	Probe(ctx context.Context, probe *rolloutv1alpha1.TrafficVerifyProbe) error
+}
+
+type httpTrafficProber struct{}
+
+func (p *httpTrafficProber) Probe(ctx context.Context, probe *rolloutv1alpha1.TrafficVerifyProbe) error {
+	timeout := time.Duration(defaultTrafficProbeTimeoutSeconds) * time.Second
+	if probe.TimeoutSeconds > 0 {
+		timeout = time.Duration(probe.TimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, probe.URL, nil)
+	if err != nil {
+		return err
+	}
+	if len(probe.Host) > 0 {
+		req.Host = probe.Host
+	}
+	for k, v := range probe.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body) // nolint
+
+	if probe.ExpectedStatus != nil {
+		if int32(resp.StatusCode) != *probe.ExpectedStatus {
+			return fmt.Errorf("unexpected status code %d, expected %d", resp.StatusCode, *probe.ExpectedStatus)
+		}
+		return nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d, expected 2xx", resp.StatusCode)
+	}
+	return nil
+}
+
+// verifyCanaryTraffic probes canary route until it succeeds successThreshold
+// times in a row or the budget is exhausted.
+func (e *canaryExecutor) verifyCanaryTraffic(ctx *ExecutorContext) (bool, time.Duration, error) {
+	traffic := ctx.RolloutRun.Spec.Canary.Traffic
+	if traffic == nil || traffic.VerifyProbe == nil {
+		return true, retryImmediately, nil
+	}
+	probe := traffic.VerifyProbe
+	logger := ctx.GetCanaryLogger()
+
+	status := ctx.NewStatus.CanaryStatus
+	verification := trafficVerification(status, rolloutv1alpha1.TrafficVerificationProbe, "forkCanary")
+	if verification.Verified {
+		// verified traffic is not probed again, so that a transient failure
+		// later does not fail the canary
+		return true, retryImmediately, nil
+	}
+
+	if status.TrafficProbe == nil {
+		status.TrafficProbe = &rolloutv1alpha1.TrafficProbeStatus{}
+	}
+	if status.TrafficProbe.StartTime == nil {
+		status.TrafficProbe.StartTime = ptr.To(metav1.Now())
+	}
+
+	threshold := int32(defaultTrafficProbeSuccessThreshold)
+	if probe.SuccessThreshold > 0 {
+		threshold = probe.SuccessThreshold
+	}
+
+	err := e.prober.Probe(ctx, probe)
+	if err == nil {
+		status.TrafficProbe.ConsecutiveSuccesses++
+		if status.TrafficProbe.ConsecutiveSuccesses < threshold {
+			status.TrafficProbe.Message = fmt.Sprintf("canary traffic probe succeeded %d/%d times", status.TrafficProbe.ConsecutiveSuccesses, threshold)
+			verification.Message = status.TrafficProbe.Message
+			logger.Info("canary traffic probe succeeded, probe again later", "url", probe.URL, "successes", status.TrafficProbe.ConsecutiveSuccesses, "threshold", threshold)
+			return false, retryDefault, nil
+		}
+		// the budget starts again if the traffic is changed and probed again
+		status.TrafficProbe.StartTime = nil
+		status.TrafficProbe.ConsecutiveSuccesses = 0
+		status.TrafficProbe.Message = "canary traffic verified"
+		verification.Verified = true
+		verification.Message = status.TrafficProbe.Message
+		return true, retryImmediately, nil
+	}
+	status.TrafficProbe.ConsecutiveSuccesses = 0
+	status.TrafficProbe.Message = err.Error()
+	verification.Verified = false
+	verification.Message = err.Error()
+
+	budget := time.Duration(defaultTrafficProbeBudgetSeconds) * time.Second
+	if probe.BudgetSeconds > 0 {
+		budget = time.Duration(probe.BudgetSeconds) * time.Second
+	}
+	if time.Since(status.TrafficProbe.StartTime.Time) > budget {
+		// reset the probe so that a manual retry starts a new budget
+		status.TrafficProbe = nil
+		return false, retryStop, control.TerminalError(wrapDoCanaryError(
+			"TrafficVerifyFailed",
+			fmt.Sprintf("canary traffic probe to %s failed within %v, err: %v", probe.URL, budget, err),
+			err,
+		))
+	}
+
+	logger.Info("canary traffic probe failed, retry later", "url", probe.URL, "err", err.Error())
+	return false, retryDefault, nil
+}
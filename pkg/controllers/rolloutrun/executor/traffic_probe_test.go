@@ -0,0 +1,199 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+type fakeTrafficProber struct {
+	err error
+}
+
+func (p *fakeTrafficProber) Probe(_ context.Context, _ *rolloutv1alpha1.TrafficVerifyProbe) error {
+	return p.err
+}
+
+func Test_CanaryExecutor_verifyCanaryTraffic(t *testing.T) {
+	tests := []struct {
+		name          string
+		probeErr      error
+		probeStarted  time.Duration
+		wantDone      bool
+		wantErr       bool
+		wantProbeNil  bool
+		wantProbeText string
+	}{
+		{
+			name:          "probe succeeded",
+			wantDone:      true,
+			wantProbeText: "canary traffic verified",
+		},
+		{
+			name:          "probe failed within budget",
+			probeErr:      fmt.Errorf("unexpected status code 503, expected 2xx"),
+			wantDone:      false,
+			wantProbeText: "unexpected status code 503, expected 2xx",
+		},
+		{
+			name:         "probe failed out of budget",
+			probeErr:     fmt.Errorf("unexpected status code 503, expected 2xx"),
+			probeStarted: 2 * time.Minute,
+			wantDone:     false,
+			wantErr:      true,
+			wantProbeNil: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rolloutRun := testRolloutRun.DeepCopy()
+			rolloutRun.Spec.Canary = &rolloutv1alpha1.RolloutRunCanaryStrategy{
+				Targets: unimportantTargets,
+				Traffic: &rolloutv1alpha1.TrafficStrategy{
+					VerifyProbe: &rolloutv1alpha1.TrafficVerifyProbe{
+						URL: "http://gateway.example.com/healthz",
+					},
+				},
+			}
+			rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{
+				State: StepRunning,
+			}
+			if tt.probeStarted > 0 {
+				rolloutRun.Status.CanaryStatus.TrafficProbe = &rolloutv1alpha1.TrafficProbeStatus{
+					StartTime: ptr.To(metav1.NewTime(time.Now().Add(-tt.probeStarted))),
+				}
+			}
+			ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+
+			e := newCanaryExecutor(newFakeWebhookExecutor())
+			e.prober = &fakeTrafficProber{err: tt.probeErr}
+			done, _, err := e.verifyCanaryTraffic(ctx)
+			assert.Equal(t, tt.wantDone, done)
+			assert.Equal(t, tt.wantErr, err != nil)
+			if tt.wantProbeNil {
+				assert.Nil(t, ctx.NewStatus.CanaryStatus.TrafficProbe)
+			} else if assert.NotNil(t, ctx.NewStatus.CanaryStatus.TrafficProbe) {
+				assert.Equal(t, tt.wantProbeText, ctx.NewStatus.CanaryStatus.TrafficProbe.Message)
+			}
+		})
+	}
+}
+
+func Test_CanaryExecutor_verifyCanaryTraffic_VerifiedOnce(t *testing.T) {
+	rolloutRun := testRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary = &rolloutv1alpha1.RolloutRunCanaryStrategy{
+		Targets: unimportantTargets,
+		Traffic: &rolloutv1alpha1.TrafficStrategy{
+			VerifyProbe: &rolloutv1alpha1.TrafficVerifyProbe{
+				URL:           "http://gateway.example.com/healthz",
+				BudgetSeconds: 60,
+			},
+		},
+	}
+	// the probe started long ago, e.g. it was retried until routing is fixed
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{
+		State: StepRunning,
+		TrafficProbe: &rolloutv1alpha1.TrafficProbeStatus{
+			StartTime: ptr.To(metav1.NewTime(time.Now().Add(-2 * time.Minute))),
+		},
+	}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+
+	prober := &fakeTrafficProber{}
+	e := newCanaryExecutor(newFakeWebhookExecutor())
+	e.prober = prober
+	done, _, err := e.verifyCanaryTraffic(ctx)
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Nil(t, ctx.NewStatus.CanaryStatus.TrafficProbe.StartTime)
+
+	// a transient failure in later reconciles, e.g. during bake or analysis,
+	// does not fail the verified canary after the budget is elapsed
+	prober.err = fmt.Errorf("unexpected status code 503, expected 2xx")
+	for i := 0; i < 3; i++ {
+		done, _, err = e.verifyCanaryTraffic(ctx)
+		assert.NoError(t, err)
+		assert.True(t, done)
+	}
+	assert.Equal(t, "canary traffic verified", ctx.NewStatus.CanaryStatus.TrafficProbe.Message)
+
+	// the traffic is changed again, the probe starts a new budget
+	resetTrafficVerifications(ctx.NewStatus.CanaryStatus, "forkCanary")
+	done, _, err = e.verifyCanaryTraffic(ctx)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	if assert.NotNil(t, ctx.NewStatus.CanaryStatus.TrafficProbe.StartTime) {
+		assert.WithinDuration(t, time.Now(), ctx.NewStatus.CanaryStatus.TrafficProbe.StartTime.Time, time.Minute)
+	}
+}
+
+func Test_CanaryExecutor_verifyCanaryTraffic_SuccessThreshold(t *testing.T) {
+	rolloutRun := testRolloutRun.DeepCopy()
+	rolloutRun.Spec.Canary = &rolloutv1alpha1.RolloutRunCanaryStrategy{
+		Targets: unimportantTargets,
+		Traffic: &rolloutv1alpha1.TrafficStrategy{
+			VerifyProbe: &rolloutv1alpha1.TrafficVerifyProbe{
+				URL:              "http://gateway.example.com/healthz",
+				SuccessThreshold: 3,
+			},
+		},
+	}
+	rolloutRun.Status.CanaryStatus = &rolloutv1alpha1.RolloutRunStepStatus{
+		State: StepRunning,
+	}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+
+	prober := &fakeTrafficProber{}
+	e := newCanaryExecutor(newFakeWebhookExecutor())
+	e.prober = prober
+
+	// not verified until it succeeds 3 times in a row
+	for i := int32(1); i < 3; i++ {
+		done, _, err := e.verifyCanaryTraffic(ctx)
+		assert.NoError(t, err)
+		assert.False(t, done)
+		assert.Equal(t, i, ctx.NewStatus.CanaryStatus.TrafficProbe.ConsecutiveSuccesses)
+	}
+
+	// a failure starts counting again
+	prober.err = fmt.Errorf("unexpected status code 503, expected 2xx")
+	done, _, err := e.verifyCanaryTraffic(ctx)
+	assert.NoError(t, err)
+	assert.False(t, done)
+	assert.EqualValues(t, 0, ctx.NewStatus.CanaryStatus.TrafficProbe.ConsecutiveSuccesses)
+
+	prober.err = nil
+	for i := 0; i < 2; i++ {
+		done, _, err = e.verifyCanaryTraffic(ctx)
+		assert.NoError(t, err)
+		assert.False(t, done)
+	}
+	done, _, err = e.verifyCanaryTraffic(ctx)
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, "canary traffic verified", ctx.NewStatus.CanaryStatus.TrafficProbe.Message)
+	assert.EqualValues(t, 0, ctx.NewStatus.CanaryStatus.TrafficProbe.ConsecutiveSuccesses)
+}
@@ -0,0 +1,88 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"kusionstack.io/kube-utils/multicluster/clusterinfo"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/webhook/probe/http"
+)
+
+const (
+	ReasonWebhookClientCertNotFound = "WebhookClientCertNotFound"
+	ReasonWebhookClientCertInvalid  = "WebhookClientCertInvalid"
+)
+
+// loadWebhookClientCert loads the client certificate of webhook from the Secret
+// in the namespace of rolloutRun. It returns nil if no Secret is referenced.
+// The Secret is read from cache, so it is cheap to load it on every reconcile
+// to pick up its changes.
+func loadWebhookClientCert(ctx *ExecutorContext, name string, config rolloutv1alpha1.WebhookClientConfig) (*http.ClientCert, error) {
+	if len(config.ClientCertSecretName) == 0 {
+		return nil, nil
+	}
+
+	namespace := ctx.RolloutRun.Namespace
+	secret := &corev1.Secret{}
+	err := ctx.Client.Get(clusterinfo.WithCluster(ctx, clusterinfo.Fed), types.NamespacedName{Namespace: namespace, Name: config.ClientCertSecretName}, secret)
+	if apierrors.IsNotFound(err) {
+		return nil, control.TerminalError(&rolloutv1alpha1.CodeReasonMessage{
+			Code:    ReasonWebhookExecuteError,
+			Reason:  ReasonWebhookClientCertNotFound,
+			Message: fmt.Sprintf("client certificate Secret %s/%s of webhook %s is not found", namespace, config.ClientCertSecretName, name),
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := http.NewClientCert(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey], secret.Data[corev1.ServiceAccountRootCAKey])
+	if err != nil {
+		return nil, control.TerminalError(&rolloutv1alpha1.CodeReasonMessage{
+			Code:    ReasonWebhookExecuteError,
+			Reason:  ReasonWebhookClientCertInvalid,
+			Message: fmt.Sprintf("client certificate Secret %s/%s of webhook %s is malformed: %v", namespace, config.ClientCertSecretName, name, err),
+		})
+	}
+	return cert, nil
+}
+
+// checkWebhookClientCerts checks the client certificates of all webhooks and
+// notifications, so that a missing or malformed Secret fails the rolloutRun
+// before anything is changed instead of failing when the webhook is called.
+func checkWebhookClientCerts(ctx *ExecutorContext) error {
+	for _, hook := range ctx.RolloutRun.Spec.Webhooks {
+		if _, err := loadWebhookClientCert(ctx, hook.Name, hook.ClientConfig); err != nil {
+			return err
+		}
+	}
+	if canary := ctx.RolloutRun.Spec.Canary; canary != nil {
+		for _, notification := range canary.Notifications {
+			if _, err := loadWebhookClientCert(ctx, notification.Name, notification.ClientConfig); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package executor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/control"
+)
+
+func Test_checkWebhookClientCerts(t *testing.T) {
+	rolloutRun := testCanaryRolloutRun.DeepCopy()
+	rolloutRun.Spec.Webhooks = []rolloutv1alpha1.RolloutWebhook{
+		{Name: "plain", ClientConfig: rolloutv1alpha1.WebhookClientConfig{URL: "https://plain"}},
+		{Name: "mtls", ClientConfig: rolloutv1alpha1.WebhookClientConfig{URL: "https://mtls", ClientCertSecretName: "webhook-cert"}},
+	}
+	ctx := createTestExecutorContext(testRollout.DeepCopy(), rolloutRun)
+
+	// Secret is missing
+	err := checkWebhookClientCerts(ctx)
+	assert.True(t, errors.Is(err, control.TerminalError(nil)))
+	assert.True(t, errors.Is(err, &rolloutv1alpha1.CodeReasonMessage{Reason: ReasonWebhookClientCertNotFound}))
+	assert.ErrorContains(t, err, "webhook mtls")
+
+	// Secret is malformed
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook-cert", Namespace: rolloutRun.Namespace},
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       []byte("invalid"),
+			corev1.TLSPrivateKeyKey: []byte("invalid"),
+		},
+	}
+	assert.NoError(t, ctx.Client.Create(ctx, secret))
+	err = checkWebhookClientCerts(ctx)
+	assert.True(t, errors.Is(err, control.TerminalError(nil)))
+	assert.True(t, errors.Is(err, &rolloutv1alpha1.CodeReasonMessage{Reason: ReasonWebhookClientCertInvalid}))
+	assert.ErrorContains(t, err, "invalid client certificate or key")
+
+	// webhooks without Secret are not checked
+	cert, err := loadWebhookClientCert(ctx, "plain", rolloutRun.Spec.Webhooks[0].ClientConfig)
+	assert.NoError(t, err)
+	assert.Nil(t, cert)
+}
@@ -22,19 +22,33 @@ import (
 )
 
 func InitFunc(mgr manager.Manager) (bool, error) {
-	return initFunc(mgr, registry.Workloads)
+	return initFunc(mgr, registry.Workloads, DefaultReconcilerOptions())
 }
 
 func InitFuncWith(registry registry.WorkloadRegistry) initializer.InitFunc {
+	return InitFuncWithOptions(registry, DefaultReconcilerOptions())
+}
+
+func InitFuncWithOptions(registry registry.WorkloadRegistry, options ReconcilerOptions) initializer.InitFunc {
 	return func(m manager.Manager) (enabled bool, err error) {
-		return initFunc(m, registry)
+		return initFunc(m, registry, options)
 	}
 }
 
-func initFunc(mgr manager.Manager, registry registry.WorkloadRegistry) (bool, error) {
-	err := NewReconciler(mgr, registry).SetupWithManager(mgr)
+func initFunc(mgr manager.Manager, registry registry.WorkloadRegistry, options ReconcilerOptions) (bool, error) {
+	err := NewReconciler(mgr, registry, options).SetupWithManager(mgr)
+	if err != nil {
+		return false, err
+	}
+	err = mgr.AddMetricsExtraHandler(StuckRunsDebugPath, defaultProgressTracker)
 	if err != nil {
 		return false, err
 	}
+	// drain the buffered audit records on shutdown
+	if runnable, ok := options.AuditSink.(manager.Runnable); ok {
+		if err := mgr.Add(runnable); err != nil {
+			return false, err
+		}
+	}
 	return true, nil
 }
@@ -0,0 +1,182 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rolloutrun
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+// StuckRunsDebugPath is the path of the debug handler listing stuck RolloutRuns,
+// it is served by the metrics server.
+const StuckRunsDebugPath = "/debug/rolloutruns/stuck"
+
+// StuckRunThreshold is the duration a RolloutRun can stay without any progress
+// before it is reported as stuck.
+var StuckRunThreshold = 30 * time.Minute
+
+var defaultProgressTracker = newProgressTracker()
+
+func init() {
+	metrics.Registry.MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "rolloutrun_active_runs",
+			Help: "Number of RolloutRuns being reconciled and not completed.",
+		}, func() float64 {
+			return float64(defaultProgressTracker.activeCount())
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "rolloutrun_stuck_runs",
+			Help: "Number of RolloutRuns without any progress beyond the stuck threshold.",
+		}, func() float64 {
+			return float64(len(defaultProgressTracker.stuck(time.Now(), StuckRunThreshold)))
+		}),
+	)
+}
+
+// runProgress records the last observed progress of a RolloutRun.
+type runProgress struct {
+	Name             string                             `json:"name"`
+	Phase            rolloutv1alpha1.RolloutRunPhase    `json:"phase"`
+	Step             string                             `json:"step,omitempty"`
+	LastProgressTime time.Time                          `json:"lastProgressTime"`
+	LastError        *rolloutv1alpha1.CodeReasonMessage `json:"lastError,omitempty"`
+}
+
+// progressTracker tracks the last progress time of active RolloutRuns.
+type progressTracker struct {
+	mu   sync.RWMutex
+	runs map[string]*runProgress
+}
+
+func newProgressTracker() *progressTracker {
+	return &progressTracker{
+		runs: map[string]*runProgress{},
+	}
+}
+
+// observe records the status of RolloutRun, the last progress time is refreshed
+// only if the phase or step changes.
+func (t *progressTracker) observe(key string, status *rolloutv1alpha1.RolloutRunStatus, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	step := progressStep(status)
+	p, ok := t.runs[key]
+	if !ok {
+		p = &runProgress{
+			Name:             key,
+			Phase:            status.Phase,
+			Step:             step,
+			LastProgressTime: lastStepTransitionTime(status, now),
+		}
+		t.runs[key] = p
+	} else if p.Phase != status.Phase || p.Step != step {
+		p.Phase = status.Phase
+		p.Step = step
+		p.LastProgressTime = now
+	}
+	p.LastError = status.Error
+}
+
+// forget stops tracking the RolloutRun.
+func (t *progressTracker) forget(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.runs, key)
+}
+
+func (t *progressTracker) activeCount() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.runs)
+}
+
+// stuck returns RolloutRuns without progress for longer than threshold, paused
+// RolloutRuns are waiting for user and never considered stuck.
+func (t *progressTracker) stuck(now time.Time, threshold time.Duration) []runProgress {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	result := make([]runProgress, 0)
+	for _, p := range t.runs {
+		if p.Phase == rolloutv1alpha1.RolloutRunPhasePaused {
+			continue
+		}
+		if now.Sub(p.LastProgressTime) > threshold {
+			result = append(result, *p)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// ServeHTTP lists stuck RolloutRuns in json.
+func (t *progressTracker) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(t.stuck(time.Now(), StuckRunThreshold)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func progressStep(status *rolloutv1alpha1.RolloutRunStatus) string {
+	if status.BatchStatus != nil && len(status.BatchStatus.CurrentBatchState) > 0 {
+		return fmt.Sprintf("batch-%d/%s", status.BatchStatus.CurrentBatchIndex, status.BatchStatus.CurrentBatchState)
+	}
+	if status.CanaryStatus != nil && len(status.CanaryStatus.State) > 0 {
+		return fmt.Sprintf("canary/%s", status.CanaryStatus.State)
+	}
+	return ""
+}
+
+// lastStepTransitionTime returns the latest start or finish time of steps, it is
+// used to restore the last progress time after controller restarts.
+func lastStepTransitionTime(status *rolloutv1alpha1.RolloutRunStatus, defaultTime time.Time) time.Time {
+	var last time.Time
+	observeStep := func(step *rolloutv1alpha1.RolloutRunStepStatus) {
+		if step == nil {
+			return
+		}
+		if step.StartTime != nil && step.StartTime.After(last) {
+			last = step.StartTime.Time
+		}
+		if step.FinishTime != nil && step.FinishTime.After(last) {
+			last = step.FinishTime.Time
+		}
+	}
+	observeStep(status.CanaryStatus)
+	if status.BatchStatus != nil {
+		for i := range status.BatchStatus.Records {
+			observeStep(&status.BatchStatus.Records[i])
+		}
+	}
+	if last.IsZero() {
+		return defaultTime
+	}
+	return last
+}
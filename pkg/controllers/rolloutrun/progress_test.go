@@ -0,0 +1,83 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rolloutrun
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+func Test_progressTracker(t *testing.T) {
+	now := time.Now()
+	threshold := 10 * time.Minute
+	tracker := newProgressTracker()
+
+	// restored from step timestamps
+	tracker.observe("default/run-1", &rolloutv1alpha1.RolloutRunStatus{
+		Phase: rolloutv1alpha1.RolloutRunPhaseProgressing,
+		CanaryStatus: &rolloutv1alpha1.RolloutRunStepStatus{
+			State:     rolloutv1alpha1.RolloutStepRunning,
+			StartTime: &metav1.Time{Time: now.Add(-time.Hour)},
+		},
+	}, now)
+	// paused run is never stuck
+	tracker.observe("default/run-2", &rolloutv1alpha1.RolloutRunStatus{
+		Phase: rolloutv1alpha1.RolloutRunPhasePaused,
+		CanaryStatus: &rolloutv1alpha1.RolloutRunStepStatus{
+			StartTime: &metav1.Time{Time: now.Add(-time.Hour)},
+		},
+	}, now)
+	assert.Equal(t, 2, tracker.activeCount())
+
+	stuck := tracker.stuck(now, threshold)
+	if assert.Len(t, stuck, 1) {
+		assert.Equal(t, "default/run-1", stuck[0].Name)
+		assert.Equal(t, "canary/Running", stuck[0].Step)
+	}
+
+	// same step does not refresh progress time but updates the error
+	tracker.observe("default/run-1", &rolloutv1alpha1.RolloutRunStatus{
+		Phase: rolloutv1alpha1.RolloutRunPhaseProgressing,
+		Error: &rolloutv1alpha1.CodeReasonMessage{Code: "Failed"},
+		CanaryStatus: &rolloutv1alpha1.RolloutRunStepStatus{
+			State: rolloutv1alpha1.RolloutStepRunning,
+		},
+	}, now)
+	stuck = tracker.stuck(now, threshold)
+	if assert.Len(t, stuck, 1) {
+		assert.Equal(t, "Failed", stuck[0].LastError.Code)
+	}
+
+	// step changes
+	tracker.observe("default/run-1", &rolloutv1alpha1.RolloutRunStatus{
+		Phase: rolloutv1alpha1.RolloutRunPhaseProgressing,
+		CanaryStatus: &rolloutv1alpha1.RolloutRunStepStatus{
+			State: rolloutv1alpha1.RolloutStepPostCanaryStepHook,
+		},
+	}, now)
+	assert.Empty(t, tracker.stuck(now, threshold))
+	assert.Len(t, tracker.stuck(now.Add(threshold+time.Second), threshold), 1)
+
+	tracker.forget("default/run-1")
+	tracker.forget("default/run-2")
+	assert.Equal(t, 0, tracker.activeCount())
+}
@@ -0,0 +1,144 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rolloutrun
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+const (
+	ReconcileMetricsLabelNamespace = "namespace"
+	ReconcileMetricsLabelName      = "name"
+)
+
+// ReconcileMetricsLabels are the RolloutRun labels of reconcile metrics which
+// can be dropped.
+var ReconcileMetricsLabels = []string{ReconcileMetricsLabelNamespace, ReconcileMetricsLabelName}
+
+var (
+	reconcilesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "rollout_reconciles_total",
+		Help: "Number of times the executor runs for RolloutRuns, a fast growing one indicates a hot reconcile loop.",
+	}, ReconcileMetricsLabels)
+	reconcileStates = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rollout_reconcile_state",
+		Help: "Number of RolloutRuns in the phase and step state of the last reconcile.",
+	}, append(ReconcileMetricsLabels, "phase", "state"))
+)
+
+func init() {
+	metrics.Registry.MustRegister(reconcilesTotal, reconcileStates)
+}
+
+// reconcileMetrics records the reconciles and current state of RolloutRuns.
+// The dropped labels are recorded as empty values, so that the series of
+// RolloutRuns are aggregated and the cardinality is bounded.
+type reconcileMetrics struct {
+	dropped sets.String
+
+	mu sync.Mutex
+	// states are the state labels of tracked RolloutRuns
+	states map[types.NamespacedName][]string
+	// counts are the number of RolloutRuns of each state labels
+	counts map[string]int
+}
+
+func newReconcileMetrics(droppedLabels []string) *reconcileMetrics {
+	return &reconcileMetrics{
+		dropped: sets.NewString(droppedLabels...),
+		states:  map[types.NamespacedName][]string{},
+		counts:  map[string]int{},
+	}
+}
+
+func (m *reconcileMetrics) runLabels(key types.NamespacedName) []string {
+	labels := []string{key.Namespace, key.Name}
+	for i, label := range ReconcileMetricsLabels {
+		if m.dropped.Has(label) {
+			labels[i] = ""
+		}
+	}
+	return labels
+}
+
+// reconciled counts a run of executor for RolloutRun.
+func (m *reconcileMetrics) reconciled(key types.NamespacedName) {
+	reconcilesTotal.WithLabelValues(m.runLabels(key)...).Inc()
+}
+
+// observe records the current state of RolloutRun.
+func (m *reconcileMetrics) observe(key types.NamespacedName, status *rolloutv1alpha1.RolloutRunStatus) {
+	labels := append(m.runLabels(key), string(status.Phase), reconcileState(status))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if old, ok := m.states[key]; ok {
+		if strings.Join(old, "/") == strings.Join(labels, "/") {
+			return
+		}
+		m.remove(old)
+	}
+	m.states[key] = labels
+	m.add(labels)
+}
+
+// forget stops recording the RolloutRun, its series are deleted unless they
+// are aggregated with others.
+func (m *reconcileMetrics) forget(key types.NamespacedName) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if old, ok := m.states[key]; ok {
+		m.remove(old)
+		delete(m.states, key)
+	}
+	if !m.dropped.Has(ReconcileMetricsLabelName) {
+		reconcilesTotal.DeleteLabelValues(m.runLabels(key)...)
+	}
+}
+
+func (m *reconcileMetrics) add(labels []string) {
+	id := strings.Join(labels, "/")
+	m.counts[id]++
+	reconcileStates.WithLabelValues(labels...).Set(float64(m.counts[id]))
+}
+
+func (m *reconcileMetrics) remove(labels []string) {
+	id := strings.Join(labels, "/")
+	m.counts[id]--
+	if m.counts[id] > 0 {
+		reconcileStates.WithLabelValues(labels...).Set(float64(m.counts[id]))
+		return
+	}
+	delete(m.counts, id)
+	reconcileStates.DeleteLabelValues(labels...)
+}
+
+// reconcileState returns the state of current step, e.g. canary/Running.
+func reconcileState(status *rolloutv1alpha1.RolloutRunStatus) string {
+	if status.BatchStatus != nil && len(status.BatchStatus.CurrentBatchState) > 0 {
+		return "batch/" + string(status.BatchStatus.CurrentBatchState)
+	}
+	return progressStep(status)
+}
@@ -0,0 +1,74 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rolloutrun
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+func Test_reconcileMetrics(t *testing.T) {
+	m := newReconcileMetrics(nil)
+	key := types.NamespacedName{Namespace: "metrics", Name: "run-1"}
+	running := &rolloutv1alpha1.RolloutRunStatus{
+		Phase:        rolloutv1alpha1.RolloutRunPhaseProgressing,
+		CanaryStatus: &rolloutv1alpha1.RolloutRunStepStatus{State: rolloutv1alpha1.RolloutStepRunning},
+	}
+
+	m.reconciled(key)
+	m.reconciled(key)
+	m.observe(key, running)
+	assert.Equal(t, float64(2), testutil.ToFloat64(reconcilesTotal.WithLabelValues("metrics", "run-1")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(reconcileStates.WithLabelValues("metrics", "run-1", "Progressing", "canary/Running")))
+
+	// the series of previous state is deleted
+	running.CanaryStatus.State = rolloutv1alpha1.RolloutStepPostCanaryStepHook
+	m.observe(key, running)
+	assert.False(t, reconcileStates.DeleteLabelValues("metrics", "run-1", "Progressing", "canary/Running"))
+	assert.Equal(t, float64(1), testutil.ToFloat64(reconcileStates.WithLabelValues("metrics", "run-1", "Progressing", "canary/PostCanaryStepHook")))
+
+	m.forget(key)
+	assert.False(t, reconcilesTotal.DeleteLabelValues("metrics", "run-1"))
+	assert.False(t, reconcileStates.DeleteLabelValues("metrics", "run-1", "Progressing", "canary/PostCanaryStepHook"))
+}
+
+func Test_reconcileMetrics_droppedLabels(t *testing.T) {
+	m := newReconcileMetrics([]string{ReconcileMetricsLabelName})
+	running := &rolloutv1alpha1.RolloutRunStatus{
+		Phase:       rolloutv1alpha1.RolloutRunPhaseProgressing,
+		BatchStatus: &rolloutv1alpha1.RolloutRunBatchStatus{},
+	}
+	running.BatchStatus.CurrentBatchState = rolloutv1alpha1.RolloutStepRunning
+
+	// RolloutRuns are aggregated by namespace
+	for _, name := range []string{"run-1", "run-2"} {
+		key := types.NamespacedName{Namespace: "dropped", Name: name}
+		m.reconciled(key)
+		m.observe(key, running)
+	}
+	assert.Equal(t, float64(2), testutil.ToFloat64(reconcilesTotal.WithLabelValues("dropped", "")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(reconcileStates.WithLabelValues("dropped", "", "Progressing", "batch/Running")))
+
+	m.forget(types.NamespacedName{Namespace: "dropped", Name: "run-1"})
+	assert.Equal(t, float64(2), testutil.ToFloat64(reconcilesTotal.WithLabelValues("dropped", "")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(reconcileStates.WithLabelValues("dropped", "", "Progressing", "batch/Running")))
+}
@@ -25,6 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"kusionstack.io/kube-utils/controller/mixin"
 	"kusionstack.io/kube-utils/multicluster/clusterinfo"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -38,6 +39,7 @@ import (
 	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
 	"kusionstack.io/rollout/apis/rollout/v1alpha1/condition"
 	"kusionstack.io/rollout/pkg/controllers/registry"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/audit"
 	"kusionstack.io/rollout/pkg/controllers/rolloutrun/executor"
 	"kusionstack.io/rollout/pkg/controllers/rolloutrun/traffic"
 	"kusionstack.io/rollout/pkg/utils"
@@ -58,16 +60,60 @@ type RolloutRunReconciler struct {
 	rvExpectation expectations.ResourceVersionExpectationInterface
 
 	executor *executor.Executor
+
+	progress *progressTracker
+
+	metrics *reconcileMetrics
+
+	auditSink audit.Sink
+
+	retryOptions executor.RetryOptions
+}
+
+// ReconcilerOptions is the options of RolloutRunReconciler.
+type ReconcilerOptions struct {
+	// RetryOptions is the requeue intervals used by RolloutRun executor.
+	RetryOptions executor.RetryOptions
+	// MetricsDroppedLabels are the labels of ReconcileMetricsLabels dropped
+	// from the reconcile metrics to bound their cardinality, e.g. dropping
+	// name aggregates RolloutRuns by namespace.
+	MetricsDroppedLabels []string
+	// CanaryInheritedMetadataKeys are the keys of RolloutRun labels and
+	// annotations inherited by canary pods.
+	CanaryInheritedMetadataKeys []string
+	// CanaryMaxTrafficWeight is the maximum weight of canary traffic, the
+	// weights of RolloutRuns exceeding it are clamped, zero means unlimited.
+	CanaryMaxTrafficWeight int32
+	// GlobalPauseConfigMap is the ConfigMap freezing all in-progress canaries
+	// if its data paused is true, empty name means global pause is disabled.
+	GlobalPauseConfigMap types.NamespacedName
+	// AuditSink receives the audit records of RolloutRuns, nil means audit is
+	// disabled. Slow sinks should be wrapped by audit.AsyncSink.
+	AuditSink audit.Sink
 }
 
-func NewReconciler(mgr manager.Manager, workloadRegistry registry.WorkloadRegistry) *RolloutRunReconciler {
+// DefaultReconcilerOptions returns the default ReconcilerOptions.
+func DefaultReconcilerOptions() ReconcilerOptions {
+	return ReconcilerOptions{
+		RetryOptions: executor.DefaultRetryOptions(),
+	}
+}
+
+func NewReconciler(mgr manager.Manager, workloadRegistry registry.WorkloadRegistry, options ReconcilerOptions) *RolloutRunReconciler {
 	r := &RolloutRunReconciler{
 		ReconcilerMixin:  mixin.NewReconcilerMixin(ControllerName, mgr),
 		workloadRegistry: workloadRegistry,
+		retryOptions:     options.RetryOptions,
 		rvExpectation:    expectations.NewResourceVersionExpectation(),
+		progress:         defaultProgressTracker,
+		metrics:          newReconcileMetrics(options.MetricsDroppedLabels),
+		auditSink:        options.AuditSink,
 	}
 
-	r.executor = executor.NewDefaultExecutor(r.Logger)
+	r.executor = executor.NewExecutor(r.Logger, r.retryOptions).
+		WithCanaryInheritedMetadataKeys(options.CanaryInheritedMetadataKeys).
+		WithCanaryMaxTrafficWeight(options.CanaryMaxTrafficWeight).
+		WithGlobalPauseConfigMap(options.GlobalPauseConfigMap)
 	return r
 }
 
@@ -88,6 +134,13 @@ func (r *RolloutRunReconciler) SetupWithManager(mgr ctrl.Manager) error {
 //+kubebuilder:rbac:groups=rollout.kusionstack.io,resources=rolloutruns/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=rollout.kusionstack.io,resources=rolloutruns/finalizers,verbs=update
 //+kubebuilder:rbac:groups=rollout.kusionstack.io,resources=rolloutstrategies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=resourcequotas,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=configmaps;secrets;serviceaccounts,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups=authorization.k8s.io,resources=selfsubjectaccessreviews,verbs=create
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -100,6 +153,9 @@ func (r *RolloutRunReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	err := r.Client.Get(clusterinfo.WithCluster(ctx, clusterinfo.Fed), req.NamespacedName, obj)
 	if err != nil {
 		if errors.IsNotFound(err) {
+			r.progress.forget(req.String())
+			r.metrics.forget(req.NamespacedName)
+			r.executor.Forget(req.NamespacedName)
 			return reconcile.Result{}, nil
 		}
 		return reconcile.Result{}, err
@@ -115,6 +171,8 @@ func (r *RolloutRunReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 
 	newStatus := obj.Status.DeepCopy()
+	// the manual command is removed after it is applied in this reconcile
+	command, operator := obj.Annotations[rollout.AnnoManualCommandKey], obj.Annotations[rollout.AnnoManualCommandBy]
 
 	accessor, workloads, err := r.findWorkloadsCrossCluster(ctx, obj)
 	if err != nil {
@@ -128,11 +186,19 @@ func (r *RolloutRunReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		logger.Error(tempErr, "failed to clean up annotation")
 	}
 
+	r.trackProgress(req.String(), obj, newStatus)
+
+	_, ownerName := r.findOwnerKindName(obj)
+	records := auditRecords(obj, ownerName, &obj.Status, newStatus, command, operator, time.Now())
+
 	updateStatus := r.updateStatusOnly(ctx, obj, newStatus, workloads)
 	if updateStatus != nil {
 		logger.Error(updateStatus, "failed to update status")
 		return reconcile.Result{}, updateStatus
 	}
+	// records are written once the status is persisted, so that they are not
+	// duplicated by the retries of failed status updates
+	r.writeAuditRecords(records)
 
 	if err != nil {
 		return reconcile.Result{}, err
@@ -141,6 +207,30 @@ func (r *RolloutRunReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	return result, nil
 }
 
+func (r *RolloutRunReconciler) trackProgress(key string, obj *rolloutv1alpha1.RolloutRun, newStatus *rolloutv1alpha1.RolloutRunStatus) {
+	if !obj.DeletionTimestamp.IsZero() ||
+		newStatus.Phase == rolloutv1alpha1.RolloutRunPhaseSucceeded ||
+		newStatus.Phase == rolloutv1alpha1.RolloutRunPhaseCanceled {
+		r.progress.forget(key)
+		r.metrics.forget(client.ObjectKeyFromObject(obj))
+		r.executor.Forget(client.ObjectKeyFromObject(obj))
+		return
+	}
+	r.progress.observe(key, newStatus, time.Now())
+	r.metrics.observe(client.ObjectKeyFromObject(obj), newStatus)
+}
+
+func (r *RolloutRunReconciler) writeAuditRecords(records []*audit.Record) {
+	if r.auditSink == nil {
+		return
+	}
+	for _, record := range records {
+		if err := r.auditSink.Write(record); err != nil {
+			r.Logger.Error(err, "failed to write audit record", "rolloutRun", record.Namespace+"/"+record.Name, "type", record.Type)
+		}
+	}
+}
+
 func (r *RolloutRunReconciler) satisfiedExpectations(instance *rolloutv1alpha1.RolloutRun) bool {
 	key := utils.ObjectKeyString(instance)
 	logger := r.Logger.WithValues("rolloutRun", key)
@@ -173,6 +263,7 @@ func (r *RolloutRunReconciler) cleanupAnnotation(ctx context.Context, obj *rollo
 	// delete manual command annotations from rollout
 	_, err := utils.UpdateOnConflict(clusterinfo.WithCluster(ctx, clusterinfo.Fed), r.Client, r.Client, obj, func() error {
 		delete(obj.Annotations, rollout.AnnoManualCommandKey)
+		delete(obj.Annotations, rollout.AnnoManualCommandBy)
 		return nil
 	})
 	if err != nil {
@@ -220,7 +311,7 @@ func (r *RolloutRunReconciler) syncRolloutRun(
 		readyCond := condition.GetCondition(obj.Status.Conditions, "Ready")
 		if readyCond == nil || readyCond.Status != metav1.ConditionTrue {
 			logger.Info("still waiting for traffic topology ready, skip reconciling", "topology", obj.Name)
-			return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+			return reconcile.Result{RequeueAfter: r.retryOptions.Default}, nil
 		}
 	}
 
@@ -246,6 +337,7 @@ func (r *RolloutRunReconciler) syncRolloutRun(
 		Workloads:      workloads,
 		TrafficManager: trafficManager,
 	}
+	r.metrics.reconciled(client.ObjectKeyFromObject(obj))
 	if done, result, err = r.executor.Do(executorCtx); err != nil {
 		return ctrl.Result{}, err
 	}
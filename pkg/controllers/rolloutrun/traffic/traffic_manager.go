@@ -20,6 +20,10 @@ import (
 	"context"
 
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
 	"kusionstack.io/kube-utils/multicluster/clusterinfo"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -36,6 +40,10 @@ type Manager struct {
 
 	targets  []rolloutv1alpha1.RolloutRunStepTarget
 	strategy *rolloutv1alpha1.TrafficStrategy
+	variants []string
+	undoLog  *[]rolloutv1alpha1.TrafficUndoEntry
+	// maxWeight is the maximum canary weight, zero means unlimited
+	maxWeight int32
 }
 
 func NewManager(c client.Client, logger logr.Logger, topologies []rolloutv1alpha1.TrafficTopology) (*Manager, error) {
@@ -73,9 +81,47 @@ func (m *Manager) With(logger logr.Logger, workloads []rolloutv1alpha1.RolloutRu
 	m.logger = logger.WithName("traffic")
 	m.targets = workloads
 	m.strategy = strategy
+	m.variants = nil
+	m.undoLog = nil
+	m.maxWeight = 0
 }
 
+// WithVariants sets the canary variants, traffic is split among their canary
+// backends by the allocation of strategy instead of routed to one canary.
+func (m *Manager) WithVariants(variants []string) {
+	m.variants = variants
+}
+
+// WithUndoLog sets the log recording the forwarding of routings before they are
+// changed by ForkStable and ForkCanary, RevertCanary and RevertStable restore
+// the recorded forwarding even if the current one is drifted. Without a log,
+// or a recorded entry, the reverts reset the forwarding.
+func (m *Manager) WithUndoLog(log *[]rolloutv1alpha1.TrafficUndoEntry) {
+	m.undoLog = log
+}
+
+// WithMaxWeight sets the maximum weight of canary traffic, the weights of
+// ForkCanary and SetCanaryWeight exceeding it are clamped, and the weights of
+// variants are scaled down proportionally. Zero means unlimited.
+func (m *Manager) WithMaxWeight(weight int32) {
+	m.maxWeight = weight
+}
+
+// clampWeight returns the weight clamped to the maximum weight.
+func (m *Manager) clampWeight(weight int32) int32 {
+	if m.maxWeight > 0 && weight > m.maxWeight {
+		return m.maxWeight
+	}
+	return weight
+}
+
+// ForkStable routes traffic to the stable backend. The prior forwarding is
+// recorded before routings are changed, the result is updated if any entry is
+// recorded so that the log is persisted before the routings are changed.
 func (m *Manager) ForkStable() (controllerutil.OperationResult, error) {
+	if m.recordUndo(rolloutv1alpha1.TrafficForkStable) {
+		return controllerutil.OperationResultUpdated, nil
+	}
 	return m.mutateRouting(func(routing *rolloutv1alpha1.BackendRouting) error {
 		if routing.Spec.Forwarding == nil {
 			routing.Spec.Forwarding = &rolloutv1alpha1.BackendForwarding{}
@@ -87,37 +133,205 @@ func (m *Manager) ForkStable() (controllerutil.OperationResult, error) {
 	})
 }
 
+// ForkCanary routes canary traffic by the strategy. The allocation of strategy
+// is translated to the canary weight of route, it is recomputed every time, so
+// the latest allocation is applied. The prior forwarding is recorded like
+// ForkStable.
 func (m *Manager) ForkCanary() (controllerutil.OperationResult, error) {
-	return m.mutateRouting(func(routing *rolloutv1alpha1.BackendRouting) error {
+	if m.recordUndo(rolloutv1alpha1.TrafficForkCanary) {
+		return controllerutil.OperationResultUpdated, nil
+	}
+	return m.forkCanary()
+}
+
+// ResyncCanary applies the forked canary rules again if they are drifted. No
+// entry is recorded since the current forwarding is already forked.
+func (m *Manager) ResyncCanary() (controllerutil.OperationResult, error) {
+	return m.forkCanary()
+}
+
+func (m *Manager) forkCanary() (controllerutil.OperationResult, error) {
+	return m.mutateTargetRouting(func(target rolloutv1alpha1.RolloutRunStepTarget, routing *rolloutv1alpha1.BackendRouting) error {
 		if routing.Spec.Forwarding == nil {
 			routing.Spec.Forwarding = &rolloutv1alpha1.BackendForwarding{}
 		}
+		if len(m.variants) > 0 {
+			routing.Spec.Forwarding.Canary = rolloutv1alpha1.CanaryBackendRule{}
+			routing.Spec.Forwarding.Variants = m.variantBackendRules(routing, target)
+			return nil
+		}
+		strategy := *m.strategy.DeepCopy()
+		strategy.Weight = strategy.CanaryWeight()
+		if strategy.Weight != nil {
+			strategy.Weight = ptr.To(m.clampWeight(*strategy.Weight))
+		}
 		routing.Spec.Forwarding.Canary = rolloutv1alpha1.CanaryBackendRule{
 			Name:            routing.Spec.Backend.Name + "-canary",
-			TrafficStrategy: *m.strategy,
+			Namespace:       target.CanaryNamespace,
+			TrafficStrategy: strategy,
 		}
 		return nil
 	})
 }
 
-func (m *Manager) RevertCanary() (controllerutil.OperationResult, error) {
+// variantBackendRules returns the canary backends of variants, weighted by the
+// percent of variant in allocation. The weights are scaled down proportionally
+// if their total exceeds the maximum weight.
+func (m *Manager) variantBackendRules(routing *rolloutv1alpha1.BackendRouting, target rolloutv1alpha1.RolloutRunStepTarget) []rolloutv1alpha1.CanaryVariantBackendRule {
+	var total int32
+	if weight := m.strategy.CanaryWeight(); weight != nil {
+		total = *weight
+	}
+	rules := make([]rolloutv1alpha1.CanaryVariantBackendRule, 0, len(m.variants))
+	for _, variant := range m.variants {
+		rule := rolloutv1alpha1.CanaryVariantBackendRule{
+			Variant:   variant,
+			Name:      routing.Spec.Backend.Name + "-canary-" + variant,
+			Namespace: target.CanaryNamespace,
+		}
+		if m.strategy.Allocation != nil {
+			for _, v := range m.strategy.Allocation.Variants {
+				if v.Name == variant {
+					rule.Weight = v.Percent
+					if clamped := m.clampWeight(total); clamped < total {
+						rule.Weight = v.Percent * clamped / total
+					}
+				}
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// DrainCanary stops routing new sessions to canary, existing sticky sessions
+// are still routed to canary until RevertCanary is called. Variants are not
+// drained since session affinity is not supported with them.
+func (m *Manager) DrainCanary() (controllerutil.OperationResult, error) {
+	return m.mutateRouting(func(routing *rolloutv1alpha1.BackendRouting) error {
+		if routing.Spec.Forwarding == nil || len(routing.Spec.Forwarding.Canary.Name) == 0 {
+			return nil
+		}
+		routing.Spec.Forwarding.Canary.Draining = true
+		return nil
+	})
+}
+
+// SetCanaryWeight changes the weight of canary traffic, it is used to return
+// traffic to stable gradually. The weight is clamped to the maximum weight.
+func (m *Manager) SetCanaryWeight(weight int32) (controllerutil.OperationResult, error) {
+	weight = m.clampWeight(weight)
 	return m.mutateRouting(func(routing *rolloutv1alpha1.BackendRouting) error {
+		if routing.Spec.Forwarding == nil || len(routing.Spec.Forwarding.Canary.Name) == 0 {
+			return nil
+		}
+		routing.Spec.Forwarding.Canary.Weight = &weight
+		return nil
+	})
+}
+
+// RevertCanary restores the canary rules recorded by ForkCanary, the stable
+// rule is kept.
+func (m *Manager) RevertCanary() (controllerutil.OperationResult, error) {
+	result, err := m.mutateRouting(func(routing *rolloutv1alpha1.BackendRouting) error {
 		if routing.Spec.Forwarding == nil {
 			return nil
 		}
 		routing.Spec.Forwarding.Canary = rolloutv1alpha1.CanaryBackendRule{}
+		routing.Spec.Forwarding.Variants = nil
+		if prior, ok := m.priorForwarding(rolloutv1alpha1.TrafficForkCanary, routing.Name); ok && prior != nil {
+			routing.Spec.Forwarding.Canary = *prior.Canary.DeepCopy()
+			routing.Spec.Forwarding.Variants = prior.DeepCopy().Variants
+		}
 		return nil
 	})
+	if err == nil {
+		m.markReverted(rolloutv1alpha1.TrafficForkCanary)
+	}
+	return result, err
 }
 
+// RevertStable restores the forwarding recorded by ForkStable.
 func (m *Manager) RevertStable() (controllerutil.OperationResult, error) {
-	return m.mutateRouting(func(routing *rolloutv1alpha1.BackendRouting) error {
-		routing.Spec.Forwarding = nil
+	result, err := m.mutateRouting(func(routing *rolloutv1alpha1.BackendRouting) error {
+		prior, _ := m.priorForwarding(rolloutv1alpha1.TrafficForkStable, routing.Name)
+		routing.Spec.Forwarding = prior.DeepCopy()
 		return nil
 	})
+	if err == nil {
+		m.markReverted(rolloutv1alpha1.TrafficForkStable)
+	}
+	return result, err
+}
+
+// recordUndo records the forwarding of routings before they are changed by op,
+// it returns true if any entry is recorded. An entry is kept until it is
+// reverted, so that the forwarding changed by op is never recorded as prior.
+func (m *Manager) recordUndo(op rolloutv1alpha1.TrafficOperation) bool {
+	if m.undoLog == nil || m.strategy == nil {
+		return false
+	}
+	recorded := false
+	for _, routing := range m.Routings() {
+		entry := m.undoEntry(op, routing.Name)
+		if entry != nil && !entry.Reverted {
+			continue
+		}
+		newEntry := rolloutv1alpha1.TrafficUndoEntry{
+			Operation:  op,
+			Routing:    routing.Name,
+			Forwarding: routing.Spec.Forwarding.DeepCopy(),
+			Time:       metav1.Now(),
+		}
+		if entry != nil {
+			*entry = newEntry
+		} else {
+			*m.undoLog = append(*m.undoLog, newEntry)
+		}
+		recorded = true
+	}
+	return recorded
+}
+
+func (m *Manager) undoEntry(op rolloutv1alpha1.TrafficOperation, routing string) *rolloutv1alpha1.TrafficUndoEntry {
+	if m.undoLog == nil {
+		return nil
+	}
+	for i := range *m.undoLog {
+		entry := &(*m.undoLog)[i]
+		if entry.Operation == op && entry.Routing == routing {
+			return entry
+		}
+	}
+	return nil
+}
+
+// priorForwarding returns the forwarding recorded by op, false if it is not
+// recorded.
+func (m *Manager) priorForwarding(op rolloutv1alpha1.TrafficOperation, routing string) (*rolloutv1alpha1.BackendForwarding, bool) {
+	entry := m.undoEntry(op, routing)
+	if entry == nil {
+		return nil, false
+	}
+	return entry.Forwarding, true
+}
+
+func (m *Manager) markReverted(op rolloutv1alpha1.TrafficOperation) {
+	for _, routing := range m.Routings() {
+		if entry := m.undoEntry(op, routing.Name); entry != nil {
+			entry.Reverted = true
+		}
+	}
 }
 
 func (m *Manager) mutateRouting(mutateFn func(routing *rolloutv1alpha1.BackendRouting) error) (controllerutil.OperationResult, error) {
+	return m.mutateTargetRouting(func(_ rolloutv1alpha1.RolloutRunStepTarget, routing *rolloutv1alpha1.BackendRouting) error {
+		return mutateFn(routing)
+	})
+}
+
+// mutateTargetRouting mutates the routings of each target with the target.
+func (m *Manager) mutateTargetRouting(mutateFn func(target rolloutv1alpha1.RolloutRunStepTarget, routing *rolloutv1alpha1.BackendRouting) error) (controllerutil.OperationResult, error) {
 	operation := controllerutil.OperationResultNone
 	if m.strategy == nil {
 		m.logger.Info("no traffic strategy found, skip it")
@@ -134,7 +348,7 @@ func (m *Manager) mutateRouting(mutateFn func(routing *rolloutv1alpha1.BackendRo
 		for i := range topo.routings {
 			routing := topo.routings[i]
 			updated, err := utils.UpdateOnConflict(ctx, m.client, m.client, routing, func() error {
-				return mutateFn(routing)
+				return mutateFn(workload, routing)
 			})
 			if err != nil {
 				return controllerutil.OperationResultNone, err
@@ -148,6 +362,50 @@ func (m *Manager) mutateRouting(mutateFn func(routing *rolloutv1alpha1.BackendRo
 	return operation, nil
 }
 
+// CheckReverted returns true if the forwarding of all routings is reverted to
+// the one recorded by ForkStable, or reset if not recorded, and ready, which
+// means the traffic is fully routed to stable.
+func (m *Manager) CheckReverted() bool {
+	if m.strategy == nil {
+		return true
+	}
+	for _, workload := range m.targets {
+		topo, ok := m.topoligies[workload.CrossClusterObjectNameReference]
+		if !ok {
+			continue
+		}
+		for _, routing := range topo.routings {
+			prior, _ := m.priorForwarding(rolloutv1alpha1.TrafficForkStable, routing.Name)
+			if !equality.Semantic.DeepEqual(routing.Spec.Forwarding, prior) {
+				return false
+			}
+		}
+	}
+	return m.CheckReady()
+}
+
+// Routings returns the backend routings of targets.
+func (m *Manager) Routings() []*rolloutv1alpha1.BackendRouting {
+	result := make([]*rolloutv1alpha1.BackendRouting, 0)
+	for _, workload := range m.targets {
+		topo, ok := m.topoligies[workload.CrossClusterObjectNameReference]
+		if !ok {
+			continue
+		}
+		result = append(result, topo.routings...)
+	}
+	return result
+}
+
+// TargetRoutings returns the backend routings of target.
+func (m *Manager) TargetRoutings(target rolloutv1alpha1.CrossClusterObjectNameReference) []*rolloutv1alpha1.BackendRouting {
+	topo, ok := m.topoligies[target]
+	if !ok {
+		return nil
+	}
+	return topo.routings
+}
+
 func (m *Manager) CheckReady() bool {
 	for _, workload := range m.targets {
 		topo, ok := m.topoligies[workload.CrossClusterObjectNameReference]
@@ -156,7 +414,8 @@ func (m *Manager) CheckReady() bool {
 		}
 		for _, routing := range topo.routings {
 			if routing.Generation == routing.Status.ObservedGeneration &&
-				routing.Status.Phase == rolloutv1alpha1.Ready {
+				routing.Status.Phase == rolloutv1alpha1.Ready &&
+				canaryPortsReady(routing) {
 				continue
 			}
 			return false
@@ -165,6 +424,16 @@ func (m *Manager) CheckReady() bool {
 	return true
 }
 
+// canaryPortsReady returns true if the traffic of all canary ports of routing
+// is split to canary.
+func canaryPortsReady(routing *rolloutv1alpha1.BackendRouting) bool {
+	if routing.Spec.Forwarding == nil || len(routing.Spec.Forwarding.Canary.Name) == 0 {
+		return true
+	}
+	want := sets.NewString(routing.Spec.Forwarding.Canary.Ports...)
+	return want.Equal(sets.NewString(routing.Status.Backends.Canary.Ports...))
+}
+
 type topology struct {
 	workload rolloutv1alpha1.CrossClusterObjectNameReference
 	routings []*rolloutv1alpha1.BackendRouting
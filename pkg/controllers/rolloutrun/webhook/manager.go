@@ -23,10 +23,11 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 
 	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun/webhook/probe/http"
 )
 
 type Manager interface {
-	Start(runUID types.UID, webhook rolloutv1alpha1.RolloutWebhook, payload rolloutv1alpha1.RolloutWebhookReview) (WebhookWorker, error)
+	Start(runUID types.UID, webhook rolloutv1alpha1.RolloutWebhook, payload rolloutv1alpha1.RolloutWebhookReview, cert *http.ClientCert) (WebhookWorker, error)
 	Get(key types.UID) (WebhookWorker, bool)
 	Stop(key types.UID)
 }
@@ -61,7 +62,7 @@ func (m *manager) removeWorker(key types.UID) {
 	delete(m.workers, key)
 }
 
-func (m *manager) Start(runUID types.UID, webhook rolloutv1alpha1.RolloutWebhook, review rolloutv1alpha1.RolloutWebhookReview) (WebhookWorker, error) {
+func (m *manager) Start(runUID types.UID, webhook rolloutv1alpha1.RolloutWebhook, review rolloutv1alpha1.RolloutWebhookReview, cert *http.ClientCert) (WebhookWorker, error) {
 	m.workerLock.Lock()
 	defer m.workerLock.Unlock()
 
@@ -71,6 +72,9 @@ func (m *manager) Start(runUID types.UID, webhook rolloutv1alpha1.RolloutWebhook
 	}
 
 	worker := newWorker(m, runUID, webhook, review)
+	if err := worker.SetClientCert(cert); err != nil {
+		return nil, err
+	}
 	go worker.run()
 	m.workers[runUID] = worker
 	return worker, nil
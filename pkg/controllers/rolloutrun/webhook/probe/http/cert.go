@@ -0,0 +1,57 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package http
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+)
+
+// ClientCert is the PEM encoded client certificate for mutual TLS with webhook.
+type ClientCert struct {
+	CertData []byte
+	KeyData  []byte
+	// CAData is the optional CA bundle to verify the webhook's server certificate.
+	CAData []byte
+}
+
+// NewClientCert returns the ClientCert if the certificate and key are a valid
+// pair and the CA bundle, if any, contains valid certificates.
+func NewClientCert(certData, keyData, caData []byte) (*ClientCert, error) {
+	if len(certData) == 0 || len(keyData) == 0 {
+		return nil, fmt.Errorf("client certificate and key are required")
+	}
+	if _, err := tls.X509KeyPair(certData, keyData); err != nil {
+		return nil, fmt.Errorf("invalid client certificate or key: %w", err)
+	}
+	if len(caData) > 0 && !x509.NewCertPool().AppendCertsFromPEM(caData) {
+		return nil, fmt.Errorf("no valid certificate found in CA bundle")
+	}
+	return &ClientCert{CertData: certData, KeyData: keyData, CAData: caData}, nil
+}
+
+// Equal returns true if c and o contain the same data.
+func (c *ClientCert) Equal(o *ClientCert) bool {
+	if c == nil || o == nil {
+		return c == o
+	}
+	return bytes.Equal(c.CertData, o.CertData) &&
+		bytes.Equal(c.KeyData, o.KeyData) &&
+		bytes.Equal(c.CAData, o.CAData)
+}
@@ -0,0 +1,117 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package http
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
+)
+
+// newTestCert returns PEM encoded certificate and key signed by parent, or
+// self-signed if parent is nil.
+func newTestCert(t *testing.T, cn string, parent *tls.Certificate) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	signer, signerKey := tmpl, interface{}(key)
+	if parent != nil {
+		signer, signerKey = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func Test_NewClientCert(t *testing.T) {
+	certData, keyData := newTestCert(t, "client", nil)
+	otherCert, _ := newTestCert(t, "other", nil)
+
+	cert, err := NewClientCert(certData, keyData, certData)
+	assert.NoError(t, err)
+	assert.True(t, cert.Equal(&ClientCert{CertData: certData, KeyData: keyData, CAData: certData}))
+	assert.False(t, cert.Equal(nil))
+
+	_, err = NewClientCert(nil, keyData, nil)
+	assert.ErrorContains(t, err, "are required")
+	_, err = NewClientCert(otherCert, keyData, nil)
+	assert.ErrorContains(t, err, "invalid client certificate or key")
+	_, err = NewClientCert(certData, keyData, []byte("invalid"))
+	assert.ErrorContains(t, err, "no valid certificate found in CA bundle")
+}
+
+func Test_httpProber_mutualTLS(t *testing.T) {
+	caCertData, caKeyData := newTestCert(t, "ca", nil)
+	ca, err := tls.X509KeyPair(caCertData, caKeyData)
+	assert.NoError(t, err)
+	ca.Leaf, err = x509.ParseCertificate(ca.Certificate[0])
+	assert.NoError(t, err)
+	serverCertData, serverKeyData := newTestCert(t, "server", &ca)
+	serverCert, err := tls.X509KeyPair(serverCertData, serverKeyData)
+	assert.NoError(t, err)
+	clientCertData, clientKeyData := newTestCert(t, "client", &ca)
+
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(caCertData)
+	server := httptest.NewUnstartedServer(testHTTPHandler())
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	config := rolloutv1alpha1.WebhookClientConfig{URL: server.URL + "/ok"}
+
+	// client certificate is required
+	result := New(config).Probe(&rolloutv1alpha1.RolloutWebhookReview{})
+	assert.Equal(t, rolloutv1alpha1.WebhookReviewCodeError, result.Code)
+
+	cert, err := NewClientCert(clientCertData, clientKeyData, caCertData)
+	assert.NoError(t, err)
+	prober, err := NewWithClientCert(config, cert)
+	assert.NoError(t, err)
+	result = prober.Probe(&rolloutv1alpha1.RolloutWebhookReview{})
+	assert.Equal(t, rolloutv1alpha1.WebhookReviewCodeOK, result.Code, result.Message)
+}
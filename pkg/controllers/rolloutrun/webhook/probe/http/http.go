@@ -19,14 +19,17 @@ package http
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"time"
 
 	"k8s.io/client-go/transport"
 
+	rolloutapi "kusionstack.io/rollout/apis/rollout"
 	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
 	"kusionstack.io/rollout/pkg/controllers/rolloutrun/webhook/probe"
 )
@@ -42,6 +45,17 @@ const (
 
 // New creates Prober that will skip TLS verification while probing.
 func New(config rolloutv1alpha1.WebhookClientConfig) probe.WebhookProber {
+	prober, err := NewWithClientCert(config, nil)
+	if err != nil {
+		// This is a non-recoverable error, so throw an error.
+		panic(err)
+	}
+	return prober
+}
+
+// NewWithClientCert creates Prober presenting the client certificate for mutual TLS.
+// The CA of cert is used to verify the webhook's server certificate if it is set.
+func NewWithClientCert(config rolloutv1alpha1.WebhookClientConfig, cert *ClientCert) (probe.WebhookProber, error) {
 	transportCfg := &transport.Config{
 		TLS: transport.TLSConfig{
 			CAData: config.CABundle,
@@ -49,14 +63,20 @@ func New(config rolloutv1alpha1.WebhookClientConfig) probe.WebhookProber {
 		DisableCompression: true,
 		UserAgent:          "kusionstack-rollout-http-prober",
 	}
+	if cert != nil {
+		transportCfg.TLS.CertData = cert.CertData
+		transportCfg.TLS.KeyData = cert.KeyData
+		if len(cert.CAData) > 0 {
+			transportCfg.TLS.CAData = cert.CAData
+		}
+	}
 
-	if len(config.CABundle) == 0 {
+	if len(transportCfg.TLS.CAData) == 0 {
 		transportCfg.TLS.Insecure = true
 	}
 	rt, err := transport.New(transportCfg)
 	if err != nil {
-		// This is a non-recoverable error, so throw an error.
-		panic(err)
+		return nil, err
 	}
 
 	timeout := defaultTimeout
@@ -72,7 +92,7 @@ func New(config rolloutv1alpha1.WebhookClientConfig) probe.WebhookProber {
 	return &httpProber{
 		url:    config.URL,
 		client: client,
-	}
+	}, nil
 }
 
 type httpProber struct {
@@ -108,9 +128,15 @@ func DoHTTPProbe(url string, payload *rolloutv1alpha1.RolloutWebhookReview, clie
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
+	if payload != nil && len(payload.Annotations[rolloutapi.AnnoTraceParent]) > 0 {
+		req.Header.Set("traceparent", payload.Annotations[rolloutapi.AnnoTraceParent])
+	}
 
 	res, err := client.Do(req)
 	if err != nil {
+		if isUnreachable(err) {
+			return errToResult(err, probe.ReasonUnreachable)
+		}
 		// Convert errors into failures to catch timeouts.
 		return errToResult(err, doRequesttErrorReason)
 	}
@@ -140,6 +166,17 @@ func DoHTTPProbe(url string, payload *rolloutv1alpha1.RolloutWebhookReview, clie
 	return respBody.Status.CodeReasonMessage
 }
 
+// isUnreachable returns true if err is caused by failing to resolve or dial
+// the webhook endpoint.
+func isUnreachable(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
 func errToResult(err error, reason string) probe.Result {
 	return probe.Result{
 		Code:    rolloutv1alpha1.WebhookReviewCodeError,
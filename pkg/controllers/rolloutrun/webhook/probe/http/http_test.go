@@ -17,10 +17,14 @@
 package http
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	rolloutapi "kusionstack.io/rollout/apis/rollout"
 	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
 	"kusionstack.io/rollout/pkg/controllers/rolloutrun/webhook/probe"
 )
@@ -89,3 +93,37 @@ func Test_httpProber_Probe(t *testing.T) {
 		})
 	}
 }
+
+func Test_httpProber_Probe_unreachable(t *testing.T) {
+	// start and close a server to get a refused address
+	testServer := NewTestHTTPServer()
+	url := testServer.URL
+	testServer.Close()
+
+	p := New(rolloutv1alpha1.WebhookClientConfig{
+		URL: url + "/ok",
+	})
+	got := p.Probe(&rolloutv1alpha1.RolloutWebhookReview{})
+	assert.Equal(t, rolloutv1alpha1.WebhookReviewCodeError, got.Code)
+	assert.Equal(t, probe.ReasonUnreachable, got.Reason)
+}
+
+func Test_httpProber_Probe_traceParent(t *testing.T) {
+	traceParent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	var got string
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		got = request.Header.Get("traceparent")
+		writer.Header().Set("Content-Type", "application/json")
+		writer.Write([]byte(`{"status":{"code":"OK"}}`)) // nolint
+	}))
+	defer testServer.Close()
+
+	p := New(rolloutv1alpha1.WebhookClientConfig{URL: testServer.URL})
+	result := p.Probe(&rolloutv1alpha1.RolloutWebhookReview{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{rolloutapi.AnnoTraceParent: traceParent},
+		},
+	})
+	assert.Equal(t, rolloutv1alpha1.WebhookReviewCodeOK, result.Code)
+	assert.Equal(t, traceParent, got)
+}
@@ -20,6 +20,10 @@ import (
 	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
 )
 
+// ReasonUnreachable is the reason of result when the webhook endpoint can not
+// be reached at transport level.
+const ReasonUnreachable = "Unreachable"
+
 type Result = rolloutv1alpha1.CodeReasonMessage
 
 type WebhookProber interface {
@@ -39,6 +39,9 @@ type WebhookWorker interface {
 	// Retry manually triggers the webhook probe.
 	Retry()
 
+	// SetClientCert recreates the prober if the client certificate is changed.
+	SetClientCert(cert *http.ClientCert) error
+
 	// Stop stops the probe worker. and clean up the result in cache.
 	// The worker handles cleanup and removes itself from its manager.
 	// It's safe to call Stop multiple times.
@@ -59,14 +62,20 @@ type worker struct {
 
 	review rolloutv1alpha1.RolloutWebhookReview
 
+	webhook rolloutv1alpha1.RolloutWebhook
+
 	periodDuration time.Duration
 
 	prober     probe.WebhookProber
+	clientCert *http.ClientCert
+	proberLock sync.RWMutex
+
 	lastResult Result
 	resultLock sync.RWMutex
 
 	failureThreshold  int
 	failurePolicy     rolloutv1alpha1.FailurePolicyType
+	unreachablePolicy rolloutv1alpha1.FailurePolicyType
 	failureCount      int
 	totalFailureCount int
 
@@ -75,16 +84,17 @@ type worker struct {
 
 func newWorker(m *manager, key types.UID, webhook rolloutv1alpha1.RolloutWebhook, review rolloutv1alpha1.RolloutWebhookReview) *worker {
 	w := &worker{
-		stopOnce:         sync.Once{},
-		stopCh:           make(chan struct{}),
-		retryTriggerCh:   make(chan struct{}, 1),
-		webhookManager:   m,
-		key:              key,
-		review:           review,
-		periodDuration:   getWorkerPeriod(webhook.ClientConfig.PeriodSeconds),
-		prober:           newProber(webhook),
-		failureThreshold: int(webhook.FailureThreshold),
-		failurePolicy:    webhook.FailurePolicy,
+		stopOnce:          sync.Once{},
+		stopCh:            make(chan struct{}),
+		retryTriggerCh:    make(chan struct{}, 1),
+		webhookManager:    m,
+		key:               key,
+		review:            review,
+		webhook:           webhook,
+		periodDuration:    getWorkerPeriod(webhook.ClientConfig.PeriodSeconds),
+		failureThreshold:  int(webhook.FailureThreshold),
+		failurePolicy:     webhook.FailurePolicy,
+		unreachablePolicy: webhook.UnreachablePolicy,
 	}
 	// init result
 	w.lastResult = Result{
@@ -114,6 +124,27 @@ func (w *worker) Retry() {
 	}
 }
 
+func (w *worker) SetClientCert(cert *http.ClientCert) error {
+	w.proberLock.Lock()
+	defer w.proberLock.Unlock()
+	if w.prober != nil && w.clientCert.Equal(cert) {
+		return nil
+	}
+	prober, err := newProber(w.webhook, cert)
+	if err != nil {
+		return err
+	}
+	w.prober = prober
+	w.clientCert = cert
+	return nil
+}
+
+func (w *worker) getProber() probe.WebhookProber {
+	w.proberLock.RLock()
+	defer w.proberLock.RUnlock()
+	return w.prober
+}
+
 // stop stops the probe worker. and clean up the result in cache.
 // The worker handles cleanup and removes itself from its manager.
 // It is safe to call stop multiple times.
@@ -169,7 +200,7 @@ func (w *worker) doProbe() (keepGoing bool) {
 		return keepGoing
 	}
 
-	probeResult := w.prober.Probe(&w.review)
+	probeResult := w.getProber().Probe(&w.review)
 	result := Result{
 		HookType:          w.review.Spec.HookType,
 		Name:              w.review.Name,
@@ -187,7 +218,7 @@ func (w *worker) doProbe() (keepGoing bool) {
 		w.totalFailureCount++
 		result.FailureCount = int32(w.totalFailureCount)
 		if w.failureCount >= w.failureThreshold {
-			if w.failurePolicy == rolloutv1alpha1.Ignore {
+			if w.policyFor(result.CodeReasonMessage) == rolloutv1alpha1.Ignore {
 				// ignore webhook failure, stop probe loop
 				result.State = rolloutv1alpha1.WebhookCompleted
 				keepGoing = false
@@ -212,11 +243,19 @@ func (w *worker) doProbe() (keepGoing bool) {
 	return keepGoing
 }
 
-func newProber(webhook rolloutv1alpha1.RolloutWebhook) probe.WebhookProber {
+// policyFor returns the failure policy for the probe result.
+func (w *worker) policyFor(result probe.Result) rolloutv1alpha1.FailurePolicyType {
+	if result.Reason == probe.ReasonUnreachable && len(w.unreachablePolicy) > 0 {
+		return w.unreachablePolicy
+	}
+	return w.failurePolicy
+}
+
+func newProber(webhook rolloutv1alpha1.RolloutWebhook, cert *http.ClientCert) (probe.WebhookProber, error) {
 	provider := ptr.Deref[string](webhook.Provider, "")
 	if len(provider) > 0 {
 		panic("webhook provider is not supported now")
 	}
 
-	return http.New(webhook.ClientConfig)
+	return http.NewWithClientCert(webhook.ClientConfig, cert)
 }
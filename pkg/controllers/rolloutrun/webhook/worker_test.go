@@ -60,7 +60,8 @@ var (
 )
 
 type fakeProber struct {
-	resultCode string
+	resultCode   string
+	resultReason string
 }
 
 func newFakeProber(code string) *fakeProber {
@@ -71,7 +72,8 @@ func newFakeProber(code string) *fakeProber {
 
 func (p *fakeProber) Probe(_ *rolloutv1alpha1.RolloutWebhookReview) probe.Result {
 	return probe.Result{
-		Code: p.resultCode,
+		Code:   p.resultCode,
+		Reason: p.resultReason,
 	}
 }
 
@@ -160,6 +162,46 @@ func Test_worker_doProbe_once(t *testing.T) {
 				FailureCount: 1,
 			},
 		},
+		{
+			name: "prober return unreachable, unreachable policy is ignore, then worker is stopped",
+			getWorker: func() *worker {
+				prober := newFakeProber(rolloutv1alpha1.WebhookReviewCodeError)
+				prober.resultReason = probe.ReasonUnreachable
+				w := newTestWorker(m, prober)
+				w.unreachablePolicy = rolloutv1alpha1.Ignore
+				return w
+			},
+			wantKeepGoing: false,
+			wantResult: Result{
+				State:    rolloutv1alpha1.WebhookCompleted,
+				HookType: testWebhookReview.Spec.HookType,
+				Name:     testWebhookReview.Name,
+				CodeReasonMessage: rolloutv1alpha1.CodeReasonMessage{
+					Code:   rolloutv1alpha1.WebhookReviewCodeError,
+					Reason: probe.ReasonUnreachable,
+				},
+				FailureCount: 1,
+			},
+		},
+		{
+			name: "prober return reachable error, unreachable policy is ignore, then failure policy is used",
+			getWorker: func() *worker {
+				prober := newFakeProber(rolloutv1alpha1.WebhookReviewCodeError)
+				w := newTestWorker(m, prober)
+				w.unreachablePolicy = rolloutv1alpha1.Ignore
+				return w
+			},
+			wantKeepGoing: true,
+			wantResult: Result{
+				State:    rolloutv1alpha1.WebhookOnHold,
+				HookType: testWebhookReview.Spec.HookType,
+				Name:     testWebhookReview.Name,
+				CodeReasonMessage: rolloutv1alpha1.CodeReasonMessage{
+					Code: rolloutv1alpha1.WebhookReviewCodeError,
+				},
+				FailureCount: 1,
+			},
+		},
 	}
 	for i := range tests {
 		tt := tests[i]
@@ -125,6 +125,7 @@ func (t *TPControllerAdapter) GetExpectedEmployer(ctx context.Context, employer
 		CrossClusterObjectNameReference: v1alpha1.CrossClusterObjectNameReference{
 			Name: trafficTopology.Spec.Backend.Name,
 		},
+		Namespace: trafficTopology.Spec.Backend.Namespace,
 	}
 
 	brRoutes := make([]v1alpha1.CrossClusterObjectReference, len(trafficTopology.Spec.Routes))
@@ -140,6 +141,7 @@ func (t *TPControllerAdapter) GetExpectedEmployer(ctx context.Context, employer
 			CrossClusterObjectNameReference: v1alpha1.CrossClusterObjectNameReference{
 				Name: route.Name,
 			},
+			Namespace: route.Namespace,
 		}
 	}
 
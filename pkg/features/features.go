@@ -24,6 +24,21 @@ const (
 	//
 	// Allow user set one time batch stratey in rollout annotation
 	OneTimeStrategy featuregate.Feature = "OneTimeStrategy"
+
+	// Check namespace ResourceQuota headroom before creating canary resources
+	CanaryQuotaCheck featuregate.Feature = "CanaryQuotaCheck"
+
+	// Create and update canary resources by server-side apply
+	CanaryServerSideApply featuregate.Feature = "CanaryServerSideApply"
+
+	// Exclude canary pods from the PodDisruptionBudgets of stable workload
+	CanaryPDBExclusion featuregate.Feature = "CanaryPDBExclusion"
+
+	// Check the ConfigMaps, Secrets and ServiceAccount referenced by canary pods exist before creating canary resources
+	CanaryDependencyCheck featuregate.Feature = "CanaryDependencyCheck"
+
+	// Check ready canary pods are in the ready endpoints of Service backends before forking canary traffic
+	CanaryEndpointsCheck featuregate.Feature = "CanaryEndpointsCheck"
 )
 
 func init() {
@@ -34,5 +49,10 @@ func init() {
 // To add a new feature, define a key for it above and add it here. The features will be
 // available throughout Kubernetes binaries.
 var defaultKubernetesFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
-	OneTimeStrategy: {Default: false, PreRelease: featuregate.Alpha},
+	OneTimeStrategy:       {Default: false, PreRelease: featuregate.Alpha},
+	CanaryQuotaCheck:      {Default: false, PreRelease: featuregate.Alpha},
+	CanaryServerSideApply: {Default: false, PreRelease: featuregate.Alpha},
+	CanaryPDBExclusion:    {Default: false, PreRelease: featuregate.Alpha},
+	CanaryDependencyCheck: {Default: false, PreRelease: featuregate.Alpha},
+	CanaryEndpointsCheck:  {Default: false, PreRelease: featuregate.Alpha},
 }
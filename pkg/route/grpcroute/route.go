@@ -0,0 +1,271 @@
+// Copyright 2024 The KusionStack Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcroute
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	"kusionstack.io/kube-utils/multicluster/clusterinfo"
+
+	"kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/route"
+	"kusionstack.io/rollout/pkg/utils"
+)
+
+var GVK = gatewayapiv1alpha2.SchemeGroupVersion.WithKind("GRPCRoute")
+
+// AnnoCanaryBackend records the names of canary backends added to GRPCRoute,
+// separated by comma, so that the canary rules and backend refs can be found
+// and removed.
+const AnnoCanaryBackend = "rollout.kusionstack.io/grpcroute-canary-backend"
+
+// grpcRoute routes canary traffic by changing the GRPCRoute in place, since
+// rules of different GRPCRoutes with the same matches are not merged by
+// gateways, the oldest one wins.
+type grpcRoute struct {
+	client  client.Client
+	obj     *gatewayapiv1alpha2.GRPCRoute
+	cluster string
+	ports   []corev1.ServicePort
+}
+
+func (r *grpcRoute) GetRouteObject() client.Object {
+	return r.obj
+}
+
+// SetCanaryPorts limits canary traffic to the backend refs of stable with
+// the given port numbers.
+func (r *grpcRoute) SetCanaryPorts(ports []corev1.ServicePort) {
+	r.ports = ports
+}
+
+func (r *grpcRoute) AddCanaryRoute(ctx context.Context, forwarding *v1alpha1.BackendForwarding) error {
+	strategy := forwarding.Canary.TrafficStrategy
+	if strategy.SessionDrain != nil || strategy.SessionAffinity != nil {
+		return fmt.Errorf("%w: GRPCRoute %s", route.ErrSessionAffinityUnsupported, r.obj.Name)
+	}
+
+	spec := r.obj.Spec.DeepCopy()
+	removeCanaryBackend(spec, r.obj.Annotations[AnnoCanaryBackend])
+	if !forwarding.Canary.Draining {
+		// there is no sticky session on GRPCRoute, draining stops all canary traffic
+		addCanaryBackend(spec, forwarding, r.ports)
+	}
+
+	canaryNames := canaryBackendNames(forwarding)
+	if equality.Semantic.DeepEqual(spec, &r.obj.Spec) && r.obj.Annotations[AnnoCanaryBackend] == canaryNames {
+		return nil
+	}
+	r.obj.Spec = *spec
+	utils.MutateAnnotations(r.obj, func(annotations map[string]string) {
+		annotations[AnnoCanaryBackend] = canaryNames
+	})
+	return r.client.Update(clusterinfo.WithCluster(ctx, r.cluster), r.obj)
+}
+
+func (r *grpcRoute) RemoveCanaryRoute(ctx context.Context) error {
+	canaryName, ok := r.obj.Annotations[AnnoCanaryBackend]
+	if !ok {
+		return nil
+	}
+	removeCanaryBackend(&r.obj.Spec, canaryName)
+	delete(r.obj.Annotations, AnnoCanaryBackend)
+	return r.client.Update(clusterinfo.WithCluster(ctx, r.cluster), r.obj)
+}
+
+func (r *grpcRoute) ChangeBackend(ctx context.Context, detail route.BackendChangeDetail) error {
+	needChange := false
+	for i := range r.obj.Spec.Rules {
+		for j := range r.obj.Spec.Rules[i].BackendRefs {
+			ref := &r.obj.Spec.Rules[i].BackendRefs[j]
+			if string(ptr.Deref(ref.Kind, "Service")) == detail.Kind && string(ref.Name) == detail.Src {
+				ref.Name = gatewayapiv1.ObjectName(detail.Dst)
+				needChange = true
+			}
+		}
+	}
+
+	if needChange {
+		return r.client.Update(clusterinfo.WithCluster(ctx, r.cluster), r.obj)
+	}
+	return nil
+}
+
+// CheckStatus returns an error if the latest generation of GRPCRoute is not
+// accepted by all parent gateways, or its backends are not resolved.
+func (r *grpcRoute) CheckStatus() error {
+	if len(r.obj.Status.Parents) == 0 {
+		return fmt.Errorf("GRPCRoute %s/%s is not accepted by any parent yet", r.obj.Namespace, r.obj.Name)
+	}
+	for _, parent := range r.obj.Status.Parents {
+		for _, condType := range []gatewayapiv1.RouteConditionType{gatewayapiv1.RouteConditionAccepted, gatewayapiv1.RouteConditionResolvedRefs} {
+			cond := meta.FindStatusCondition(parent.Conditions, string(condType))
+			if cond == nil || cond.ObservedGeneration < r.obj.Generation {
+				return fmt.Errorf("GRPCRoute %s/%s generation %d is not observed by parent %s yet", r.obj.Namespace, r.obj.Name, r.obj.Generation, parent.ParentRef.Name)
+			}
+			if cond.Status != metav1.ConditionTrue {
+				return fmt.Errorf("GRPCRoute %s/%s is not %s by parent %s, reason: %s, message: %s", r.obj.Namespace, r.obj.Name, condType, parent.ParentRef.Name, cond.Reason, cond.Message)
+			}
+		}
+	}
+	return nil
+}
+
+var (
+	_ route.IRoute        = &grpcRoute{}
+	_ route.StatusChecker = &grpcRoute{}
+	_ route.PortScoped    = &grpcRoute{}
+)
+
+// addCanaryBackend routes canary traffic of rules forwarding to stable backend.
+// If grpc matches are set, a rule forwarding the matched requests to canary is
+// added before each of them, otherwise the canary backend is added to them by
+// weight. Only the backend refs of stable with one of ports are changed if
+// ports is not empty.
+func addCanaryBackend(spec *gatewayapiv1alpha2.GRPCRouteSpec, forwarding *v1alpha1.BackendForwarding, ports []corev1.ServicePort) {
+	if len(forwarding.Variants) > 0 {
+		addVariantBackends(spec, forwarding)
+		return
+	}
+	strategy := forwarding.Canary.TrafficStrategy
+	canaryRef := func(stable gatewayapiv1alpha2.GRPCBackendRef, weight *int32) gatewayapiv1alpha2.GRPCBackendRef {
+		ref := *stable.DeepCopy()
+		ref.Name = gatewayapiv1.ObjectName(forwarding.Canary.Name)
+		if len(forwarding.Canary.Namespace) > 0 {
+			ref.Namespace = ptr.To(gatewayapiv1.Namespace(forwarding.Canary.Namespace))
+		}
+		ref.Weight = weight
+		return ref
+	}
+
+	rules := make([]gatewayapiv1alpha2.GRPCRouteRule, 0, len(spec.Rules))
+	for _, rule := range spec.Rules {
+		idx := lo.IndexOf(lo.Map(rule.BackendRefs, func(ref gatewayapiv1alpha2.GRPCBackendRef, _ int) string {
+			return string(ref.Name)
+		}), forwarding.Stable.Name)
+		if idx < 0 || !route.MatchPort(ports, "", int32(ptr.Deref(rule.BackendRefs[idx].Port, 0))) {
+			rules = append(rules, rule)
+			continue
+		}
+
+		if strategy.GRPCRule != nil && len(strategy.GRPCRule.Matches) > 0 {
+			rules = append(rules, gatewayapiv1alpha2.GRPCRouteRule{
+				Matches:     mergeMatches(rule.Matches, strategy.GRPCRule.Matches),
+				Filters:     rule.Filters,
+				BackendRefs: []gatewayapiv1alpha2.GRPCBackendRef{canaryRef(rule.BackendRefs[idx], nil)},
+			})
+		} else if strategy.Weight != nil {
+			rule.BackendRefs[idx].Weight = ptr.To(100 - *strategy.Weight)
+			rule.BackendRefs = append(rule.BackendRefs, canaryRef(rule.BackendRefs[idx], ptr.To(*strategy.Weight)))
+		}
+		rules = append(rules, rule)
+	}
+	spec.Rules = rules
+}
+
+// addVariantBackends splits the traffic of rules forwarding to stable backend
+// among stable and the backends of variants by their weights.
+func addVariantBackends(spec *gatewayapiv1alpha2.GRPCRouteSpec, forwarding *v1alpha1.BackendForwarding) {
+	total := lo.SumBy(forwarding.Variants, func(variant v1alpha1.CanaryVariantBackendRule) int32 {
+		return variant.Weight
+	})
+	for i := range spec.Rules {
+		rule := &spec.Rules[i]
+		idx := lo.IndexOf(lo.Map(rule.BackendRefs, func(ref gatewayapiv1alpha2.GRPCBackendRef, _ int) string {
+			return string(ref.Name)
+		}), forwarding.Stable.Name)
+		if idx < 0 {
+			continue
+		}
+		stable := rule.BackendRefs[idx]
+		rule.BackendRefs[idx].Weight = ptr.To(100 - total)
+		for _, variant := range forwarding.Variants {
+			ref := *stable.DeepCopy()
+			ref.Name = gatewayapiv1.ObjectName(variant.Name)
+			if len(variant.Namespace) > 0 {
+				ref.Namespace = ptr.To(gatewayapiv1.Namespace(variant.Namespace))
+			}
+			ref.Weight = ptr.To(variant.Weight)
+			rule.BackendRefs = append(rule.BackendRefs, ref)
+		}
+	}
+}
+
+// canaryBackendNames returns the names of canary backends in forwarding,
+// separated by comma.
+func canaryBackendNames(forwarding *v1alpha1.BackendForwarding) string {
+	if len(forwarding.Variants) == 0 {
+		return forwarding.Canary.Name
+	}
+	return strings.Join(lo.Map(forwarding.Variants, func(variant v1alpha1.CanaryVariantBackendRule, _ int) string {
+		return variant.Name
+	}), ",")
+}
+
+// mergeMatches returns the matches of canary narrowed by the matches of the
+// rule, the method of canary takes precedence and headers are ANDed.
+func mergeMatches(ruleMatches []gatewayapiv1alpha2.GRPCRouteMatch, canaryMatches []v1alpha1.GRPCRouteMatch) []gatewayapiv1alpha2.GRPCRouteMatch {
+	if len(ruleMatches) == 0 {
+		ruleMatches = []gatewayapiv1alpha2.GRPCRouteMatch{{}}
+	}
+	result := make([]gatewayapiv1alpha2.GRPCRouteMatch, 0, len(ruleMatches)*len(canaryMatches))
+	for _, ruleMatch := range ruleMatches {
+		for _, canaryMatch := range canaryMatches {
+			merged := *ruleMatch.DeepCopy()
+			if canaryMatch.Method != nil {
+				merged.Method = canaryMatch.Method.DeepCopy()
+			}
+			merged.Headers = append(merged.Headers, canaryMatch.Headers...)
+			result = append(result, merged)
+		}
+	}
+	return result
+}
+
+// removeCanaryBackend removes the rules forwarding to canary only, and the
+// canary backend refs split by weight. The canaryNames are separated by comma.
+func removeCanaryBackend(spec *gatewayapiv1alpha2.GRPCRouteSpec, canaryNames string) {
+	if len(canaryNames) == 0 {
+		return
+	}
+	names := strings.Split(canaryNames, ",")
+	rules := make([]gatewayapiv1alpha2.GRPCRouteRule, 0, len(spec.Rules))
+	for _, rule := range spec.Rules {
+		refs := lo.Filter(rule.BackendRefs, func(ref gatewayapiv1alpha2.GRPCBackendRef, _ int) bool {
+			return !lo.Contains(names, string(ref.Name))
+		})
+		if len(refs) == 0 && len(rule.BackendRefs) > 0 {
+			continue
+		}
+		if len(refs) == 1 && len(rule.BackendRefs) > 1 {
+			refs[0].Weight = nil
+		}
+		rule.BackendRefs = refs
+		rules = append(rules, rule)
+	}
+	spec.Rules = rules
+}
@@ -0,0 +1,271 @@
+// Copyright 2024 The KusionStack Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcroute
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	"kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/route"
+)
+
+func backendRef(name string, weight *int32) gatewayapiv1alpha2.GRPCBackendRef {
+	return gatewayapiv1alpha2.GRPCBackendRef{
+		BackendRef: gatewayapiv1.BackendRef{
+			BackendObjectReference: gatewayapiv1.BackendObjectReference{
+				Name: gatewayapiv1.ObjectName(name),
+				Port: ptr.To[gatewayapiv1.PortNumber](9090),
+			},
+			Weight: weight,
+		},
+	}
+}
+
+func newStableRoute() *gatewayapiv1alpha2.GRPCRoute {
+	return &gatewayapiv1alpha2.GRPCRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "default"},
+		Spec: gatewayapiv1alpha2.GRPCRouteSpec{
+			Rules: []gatewayapiv1alpha2.GRPCRouteRule{
+				{
+					Matches: []gatewayapiv1alpha2.GRPCRouteMatch{{
+						Method: &gatewayapiv1alpha2.GRPCMethodMatch{Service: ptr.To("demo.Greeter")},
+					}},
+					BackendRefs: []gatewayapiv1alpha2.GRPCBackendRef{backendRef("demo-stable", nil)},
+				},
+				{
+					BackendRefs: []gatewayapiv1alpha2.GRPCBackendRef{backendRef("other", nil)},
+				},
+			},
+		},
+	}
+}
+
+func newFakeClient(objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	_ = gatewayapiv1alpha2.AddToScheme(scheme)
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func Test_grpcRoute_AddCanaryRoute(t *testing.T) {
+	tests := []struct {
+		name       string
+		forwarding *v1alpha1.BackendForwarding
+		ports      []corev1.ServicePort
+		wantRules  []gatewayapiv1alpha2.GRPCRouteRule
+		wantErr    error
+	}{
+		{
+			name: "split by weight",
+			forwarding: &v1alpha1.BackendForwarding{
+				Stable: v1alpha1.StableBackendRule{Name: "demo-stable"},
+				Canary: v1alpha1.CanaryBackendRule{
+					Name:            "demo-canary",
+					TrafficStrategy: v1alpha1.TrafficStrategy{Weight: ptr.To[int32](20)},
+				},
+			},
+			wantRules: []gatewayapiv1alpha2.GRPCRouteRule{
+				{
+					Matches: []gatewayapiv1alpha2.GRPCRouteMatch{{
+						Method: &gatewayapiv1alpha2.GRPCMethodMatch{Service: ptr.To("demo.Greeter")},
+					}},
+					BackendRefs: []gatewayapiv1alpha2.GRPCBackendRef{
+						backendRef("demo-stable", ptr.To[int32](80)),
+						backendRef("demo-canary", ptr.To[int32](20)),
+					},
+				},
+				{
+					BackendRefs: []gatewayapiv1alpha2.GRPCBackendRef{backendRef("other", nil)},
+				},
+			},
+		},
+		{
+			name: "split by method",
+			forwarding: &v1alpha1.BackendForwarding{
+				Stable: v1alpha1.StableBackendRule{Name: "demo-stable"},
+				Canary: v1alpha1.CanaryBackendRule{
+					Name: "demo-canary",
+					TrafficStrategy: v1alpha1.TrafficStrategy{GRPCRule: &v1alpha1.GRPCRouteRule{
+						Matches: []v1alpha1.GRPCRouteMatch{{
+							Method:  &gatewayapiv1alpha2.GRPCMethodMatch{Service: ptr.To("demo.Greeter"), Method: ptr.To("SayHello")},
+							Headers: []gatewayapiv1alpha2.GRPCHeaderMatch{{Name: "x-canary", Value: "true"}},
+						}},
+					}},
+				},
+			},
+			wantRules: []gatewayapiv1alpha2.GRPCRouteRule{
+				{
+					Matches: []gatewayapiv1alpha2.GRPCRouteMatch{{
+						Method:  &gatewayapiv1alpha2.GRPCMethodMatch{Service: ptr.To("demo.Greeter"), Method: ptr.To("SayHello")},
+						Headers: []gatewayapiv1alpha2.GRPCHeaderMatch{{Name: "x-canary", Value: "true"}},
+					}},
+					BackendRefs: []gatewayapiv1alpha2.GRPCBackendRef{backendRef("demo-canary", nil)},
+				},
+				newStableRoute().Spec.Rules[0],
+				newStableRoute().Spec.Rules[1],
+			},
+		},
+		{
+			name: "split grpc port only",
+			forwarding: &v1alpha1.BackendForwarding{
+				Stable: v1alpha1.StableBackendRule{Name: "demo-stable"},
+				Canary: v1alpha1.CanaryBackendRule{
+					Name:            "demo-canary",
+					TrafficStrategy: v1alpha1.TrafficStrategy{Weight: ptr.To[int32](20), Ports: []string{"grpc"}},
+				},
+			},
+			ports: []corev1.ServicePort{{Name: "grpc", Port: 9090}},
+			wantRules: []gatewayapiv1alpha2.GRPCRouteRule{
+				{
+					Matches: []gatewayapiv1alpha2.GRPCRouteMatch{{
+						Method: &gatewayapiv1alpha2.GRPCMethodMatch{Service: ptr.To("demo.Greeter")},
+					}},
+					BackendRefs: []gatewayapiv1alpha2.GRPCBackendRef{
+						backendRef("demo-stable", ptr.To[int32](80)),
+						backendRef("demo-canary", ptr.To[int32](20)),
+					},
+				},
+				{
+					BackendRefs: []gatewayapiv1alpha2.GRPCBackendRef{backendRef("other", nil)},
+				},
+			},
+		},
+		{
+			name: "stable port not in ports",
+			forwarding: &v1alpha1.BackendForwarding{
+				Stable: v1alpha1.StableBackendRule{Name: "demo-stable"},
+				Canary: v1alpha1.CanaryBackendRule{
+					Name:            "demo-canary",
+					TrafficStrategy: v1alpha1.TrafficStrategy{Weight: ptr.To[int32](20), Ports: []string{"http"}},
+				},
+			},
+			ports:     []corev1.ServicePort{{Name: "http", Port: 8080}},
+			wantRules: newStableRoute().Spec.Rules,
+		},
+		{
+			name: "draining",
+			forwarding: &v1alpha1.BackendForwarding{
+				Stable: v1alpha1.StableBackendRule{Name: "demo-stable"},
+				Canary: v1alpha1.CanaryBackendRule{
+					Name:            "demo-canary",
+					Draining:        true,
+					TrafficStrategy: v1alpha1.TrafficStrategy{Weight: ptr.To[int32](20)},
+				},
+			},
+			wantRules: newStableRoute().Spec.Rules,
+		},
+		{
+			name: "split among variants",
+			forwarding: &v1alpha1.BackendForwarding{
+				Stable: v1alpha1.StableBackendRule{Name: "demo-stable"},
+				Variants: []v1alpha1.CanaryVariantBackendRule{
+					{Variant: "b", Name: "demo-canary-b", Weight: 20},
+					{Variant: "c", Name: "demo-canary-c", Weight: 10},
+				},
+			},
+			wantRules: []gatewayapiv1alpha2.GRPCRouteRule{
+				{
+					Matches: []gatewayapiv1alpha2.GRPCRouteMatch{{
+						Method: &gatewayapiv1alpha2.GRPCMethodMatch{Service: ptr.To("demo.Greeter")},
+					}},
+					BackendRefs: []gatewayapiv1alpha2.GRPCBackendRef{
+						backendRef("demo-stable", ptr.To[int32](70)),
+						backendRef("demo-canary-b", ptr.To[int32](20)),
+						backendRef("demo-canary-c", ptr.To[int32](10)),
+					},
+				},
+				{
+					BackendRefs: []gatewayapiv1alpha2.GRPCBackendRef{backendRef("other", nil)},
+				},
+			},
+		},
+		{
+			name: "session affinity",
+			forwarding: &v1alpha1.BackendForwarding{
+				Stable: v1alpha1.StableBackendRule{Name: "demo-stable"},
+				Canary: v1alpha1.CanaryBackendRule{
+					Name: "demo-canary",
+					TrafficStrategy: v1alpha1.TrafficStrategy{
+						SessionAffinity: &v1alpha1.TrafficSessionAffinity{Type: v1alpha1.CookieSessionAffinity},
+					},
+				},
+			},
+			wantErr: route.ErrSessionAffinityUnsupported,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := newStableRoute()
+			c := newFakeClient(obj)
+			r, err := (&GRPCRouteStore{client: c}).Get(context.TODO(), "", "default", "demo")
+			assert.NoError(t, err)
+
+			r.(route.PortScoped).SetCanaryPorts(tt.ports)
+			err = r.AddCanaryRoute(context.TODO(), tt.forwarding)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			got := &gatewayapiv1alpha2.GRPCRoute{}
+			assert.NoError(t, c.Get(context.TODO(), client.ObjectKeyFromObject(obj), got))
+			assert.Equal(t, tt.wantRules, got.Spec.Rules)
+
+			// canary route is removed completely
+			assert.NoError(t, r.RemoveCanaryRoute(context.TODO()))
+			assert.NoError(t, c.Get(context.TODO(), client.ObjectKeyFromObject(obj), got))
+			assert.Equal(t, newStableRoute().Spec.Rules, got.Spec.Rules)
+			assert.NotContains(t, got.Annotations, AnnoCanaryBackend)
+		})
+	}
+}
+
+func Test_grpcRoute_CheckStatus(t *testing.T) {
+	obj := newStableRoute()
+	obj.Generation = 2
+	r := &grpcRoute{obj: obj}
+	assert.Error(t, r.CheckStatus())
+
+	parent := gatewayapiv1.RouteParentStatus{
+		ParentRef: gatewayapiv1.ParentReference{Name: "gateway"},
+		Conditions: []metav1.Condition{
+			{Type: string(gatewayapiv1.RouteConditionAccepted), Status: metav1.ConditionTrue, ObservedGeneration: 1},
+			{Type: string(gatewayapiv1.RouteConditionResolvedRefs), Status: metav1.ConditionTrue, ObservedGeneration: 1},
+		},
+	}
+	obj.Status.Parents = []gatewayapiv1.RouteParentStatus{parent}
+	assert.ErrorContains(t, r.CheckStatus(), "not observed")
+
+	parent.Conditions[0].ObservedGeneration = 2
+	parent.Conditions[1].ObservedGeneration = 2
+	parent.Conditions[1].Status = metav1.ConditionFalse
+	parent.Conditions[1].Reason = "BackendNotFound"
+	obj.Status.Parents = []gatewayapiv1.RouteParentStatus{parent}
+	assert.ErrorContains(t, r.CheckStatus(), "BackendNotFound")
+
+	parent.Conditions[1].Status = metav1.ConditionTrue
+	assert.NoError(t, r.CheckStatus())
+}
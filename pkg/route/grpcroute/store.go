@@ -0,0 +1,74 @@
+// Copyright 2024 The KusionStack Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcroute
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	gatewayapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	"kusionstack.io/kube-utils/multicluster/clusterinfo"
+
+	"kusionstack.io/rollout/pkg/route"
+)
+
+type GRPCRouteStore struct {
+	client client.Client
+}
+
+func NewStorage(mgr manager.Manager) route.Store {
+	return &GRPCRouteStore{
+		client: mgr.GetClient(),
+	}
+}
+
+func (s *GRPCRouteStore) GroupVersionKind() schema.GroupVersionKind {
+	return GVK
+}
+
+func (s *GRPCRouteStore) NewObject() client.Object {
+	return &gatewayapiv1alpha2.GRPCRoute{}
+}
+
+func (s *GRPCRouteStore) Wrap(cluster string, obj client.Object) (route.IRoute, error) {
+	gr, ok := obj.(*gatewayapiv1alpha2.GRPCRoute)
+	if !ok {
+		return nil, fmt.Errorf("not GRPCRoute")
+	}
+	return &grpcRoute{
+		client:  s.client,
+		obj:     gr,
+		cluster: cluster,
+	}, nil
+}
+
+func (s *GRPCRouteStore) Get(ctx context.Context, cluster, namespace, name string) (route.IRoute, error) {
+	var gr gatewayapiv1alpha2.GRPCRoute
+	err := s.client.Get(clusterinfo.WithCluster(ctx, cluster), types.NamespacedName{
+		Namespace: namespace,
+		Name:      name,
+	}, &gr)
+	if err != nil {
+		return nil, err
+	}
+	return s.Wrap(cluster, &gr)
+}
+
+var _ route.Store = &GRPCRouteStore{}
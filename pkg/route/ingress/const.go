@@ -20,6 +20,13 @@ const (
 	AnnoCanaryHeader      = "nginx.ingress.kubernetes.io/canary-by-header"
 	AnnoCanaryHeaderValue = "nginx.ingress.kubernetes.io/canary-by-header-value"
 
+	AnnoAffinityCanaryBehavior = "nginx.ingress.kubernetes.io/affinity-canary-behavior"
+	AnnoAffinity               = "nginx.ingress.kubernetes.io/affinity"
+	AnnoSessionCookieName      = "nginx.ingress.kubernetes.io/session-cookie-name"
+	AnnoUpstreamHashBy         = "nginx.ingress.kubernetes.io/upstream-hash-by"
+
+	DefaultSessionCookieName = "rollout-canary"
+
 	AnnoMseCanaryQuery      = "mse.ingress.kubernetes.io/canary-by-query"
 	AnnoMseCanaryQueryValue = "mse.ingress.kubernetes.io/canary-by-query-value"
 
@@ -16,9 +16,11 @@ package ingress
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 	"strings"
 
+	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
@@ -38,14 +40,35 @@ type ingressRoute struct {
 	client  client.Client
 	obj     *networkingv1.Ingress
 	cluster string
+	ports   []corev1.ServicePort
+}
+
+// IsMseIngress returns true if the ingress is served by MSE, which does not
+// support session affinity.
+func IsMseIngress(igs *networkingv1.Ingress) bool {
+	return igs.Spec.IngressClassName != nil && *igs.Spec.IngressClassName == MseIngressClass
 }
 
 func (i *ingressRoute) GetRouteObject() client.Object {
 	return i.obj
 }
 
+// SetCanaryPorts limits the canary ingress to the paths forwarding to the
+// given ports of stable service.
+func (i *ingressRoute) SetCanaryPorts(ports []corev1.ServicePort) {
+	i.ports = ports
+}
+
 func (i *ingressRoute) AddCanaryRoute(ctx context.Context, forwarding *v1alpha1.BackendForwarding) error {
 	igs := i.obj
+	if len(forwarding.Variants) > 0 {
+		// nginx ingress only supports one canary ingress for each ingress
+		return fmt.Errorf("%w: ingress %s", route.ErrVariantsUnsupported, igs.Name)
+	}
+	if namespace := forwarding.Canary.Namespace; len(namespace) > 0 && namespace != igs.Namespace {
+		// ingress backends can only reference Services in the same namespace
+		return fmt.Errorf("%w: ingress %s/%s, canary namespace %s", route.ErrCrossNamespaceUnsupported, igs.Namespace, igs.Name, namespace)
+	}
 
 	strategy := forwarding.Canary.TrafficStrategy
 
@@ -54,6 +77,10 @@ func (i *ingressRoute) AddCanaryRoute(ctx context.Context, forwarding *v1alpha1.
 		AnnoCanaryWeight:           "",
 		AnnoCanaryHeader:           "",
 		AnnoCanaryHeaderValue:      "",
+		AnnoAffinityCanaryBehavior: "",
+		AnnoAffinity:               "",
+		AnnoSessionCookieName:      "",
+		AnnoUpstreamHashBy:         "", // left by the removed ConsistentHash affinity
 		AnnoMseCanaryQuery:         "",
 		AnnoMseCanaryQueryValue:    "",
 		AnnoMseReqHeaderCtrlUpdate: "",
@@ -64,9 +91,35 @@ func (i *ingressRoute) AddCanaryRoute(ctx context.Context, forwarding *v1alpha1.
 		annosCanaryNeedCheck[AnnoCanaryWeight] = strconv.Itoa(int(*strategy.Weight))
 	}
 
-	isMseIngress := igs.Spec.IngressClassName != nil && *igs.Spec.IngressClassName == MseIngressClass
+	isMseIngress := IsMseIngress(igs)
 
-	if strategy.HTTPRule != nil {
+	affinity := strategy.SessionAffinity
+	if affinity == nil && strategy.SessionDrain != nil {
+		// sessions are kept sticky to the draining canary by the affinity cookie
+		affinity = &v1alpha1.TrafficSessionAffinity{Type: v1alpha1.CookieSessionAffinity}
+	}
+	if affinity != nil {
+		if isMseIngress {
+			return fmt.Errorf("%w: ingress %s with class %s", route.ErrSessionAffinityUnsupported, igs.Name, MseIngressClass)
+		}
+		if affinity.Type != v1alpha1.CookieSessionAffinity {
+			// nginx picks canary by weight for each request, only the affinity
+			// cookie pins a session to canary
+			return fmt.Errorf("%w: unknown session affinity type %q", route.ErrSessionAffinityUnsupported, affinity.Type)
+		}
+		cookieName := affinity.CookieName
+		if len(cookieName) == 0 {
+			cookieName = DefaultSessionCookieName
+		}
+		annosCanaryNeedCheck[AnnoAffinity] = "cookie"
+		annosCanaryNeedCheck[AnnoSessionCookieName] = cookieName
+		annosCanaryNeedCheck[AnnoAffinityCanaryBehavior] = "sticky"
+	}
+
+	if forwarding.Canary.Draining {
+		// stop sending new sessions to canary, only sticky sessions are routed to it
+		annosCanaryNeedCheck[AnnoCanaryWeight] = "0"
+	} else if strategy.HTTPRule != nil {
 		if len(strategy.HTTPRule.Matches) > 0 {
 			if len(strategy.HTTPRule.Matches[0].Headers) > 0 {
 				annosCanaryNeedCheck[AnnoCanaryHeader] = string(strategy.HTTPRule.Matches[0].Headers[0].Name)
@@ -99,7 +152,11 @@ func (i *ingressRoute) AddCanaryRoute(ctx context.Context, forwarding *v1alpha1.
 	canaryIgs.Namespace = igs.Namespace
 
 	_, err := controllerutil.CreateOrUpdate(clusterinfo.WithCluster(ctx, i.cluster), i.client, canaryIgs, func() error {
-		canaryIgs.Spec = igs.Spec
+		canaryIgs.Spec = *igs.Spec.DeepCopy()
+		if len(i.ports) > 0 {
+			// the paths of other ports are not in canary ingress, they are always routed to stable
+			filterStablePorts(&canaryIgs.Spec, forwarding.Stable.Name, i.ports)
+		}
 
 		if canaryIgs.Spec.DefaultBackend != nil {
 			if canaryIgs.Spec.DefaultBackend.Service != nil && canaryIgs.Spec.DefaultBackend.Service.Name == forwarding.Stable.Name {
@@ -200,7 +257,47 @@ func (i *ingressRoute) ChangeBackend(ctx context.Context, detail route.BackendCh
 	return nil
 }
 
-var _ route.IRoute = &ingressRoute{}
+var (
+	_ route.IRoute     = &ingressRoute{}
+	_ route.PortScoped = &ingressRoute{}
+)
+
+// filterStablePorts removes the default backend and paths forwarding to the
+// stable backend but not to one of ports, and the rules without any path left.
+func filterStablePorts(spec *networkingv1.IngressSpec, stable string, ports []corev1.ServicePort) {
+	matched := func(backend *networkingv1.IngressBackend) bool {
+		if backend.Resource != nil {
+			return backend.Resource.Name != stable
+		}
+		if backend.Service == nil || backend.Service.Name != stable {
+			return true
+		}
+		return route.MatchPort(ports, backend.Service.Port.Name, backend.Service.Port.Number)
+	}
+
+	if spec.DefaultBackend != nil && !matched(spec.DefaultBackend) {
+		spec.DefaultBackend = nil
+	}
+	rules := make([]networkingv1.IngressRule, 0, len(spec.Rules))
+	for _, rule := range spec.Rules {
+		if rule.HTTP == nil {
+			rules = append(rules, rule)
+			continue
+		}
+		paths := make([]networkingv1.HTTPIngressPath, 0, len(rule.HTTP.Paths))
+		for _, path := range rule.HTTP.Paths {
+			if matched(&path.Backend) {
+				paths = append(paths, path)
+			}
+		}
+		if len(paths) == 0 {
+			continue
+		}
+		rule.HTTP.Paths = paths
+		rules = append(rules, rule)
+	}
+	spec.Rules = rules
+}
 
 func generateMultiHeadersAnno(headers []v1.HTTPHeader) string {
 	if len(headers) == 0 {
@@ -0,0 +1,151 @@
+// Copyright 2024 The KusionStack Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingress
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"kusionstack.io/rollout/apis/rollout/v1alpha1"
+	"kusionstack.io/rollout/pkg/route"
+)
+
+func newStableIngress(class *string) *networkingv1.Ingress {
+	return &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo", Namespace: "default"},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: class,
+			Rules: []networkingv1.IngressRule{{
+				Host: "demo.example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{
+						Path:     "/",
+						PathType: ptr.To(networkingv1.PathTypePrefix),
+						Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+							Name: "demo-stable",
+							Port: networkingv1.ServiceBackendPort{Number: 80},
+						}},
+					}},
+				}},
+			}},
+		},
+	}
+}
+
+func newFakeClient(objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	_ = networkingv1.AddToScheme(scheme)
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func Test_ingressRoute_AddCanaryRoute_SessionAffinity(t *testing.T) {
+	tests := []struct {
+		name       string
+		class      *string
+		strategy   v1alpha1.TrafficStrategy
+		wantAnnos  map[string]string
+		wantAbsent []string
+		wantErr    error
+	}{
+		{
+			name: "cookie affinity",
+			strategy: v1alpha1.TrafficStrategy{
+				Weight:          ptr.To[int32](20),
+				SessionAffinity: &v1alpha1.TrafficSessionAffinity{Type: v1alpha1.CookieSessionAffinity},
+			},
+			wantAnnos: map[string]string{
+				AnnoCanaryWeight:           "20",
+				AnnoAffinity:               "cookie",
+				AnnoSessionCookieName:      DefaultSessionCookieName,
+				AnnoAffinityCanaryBehavior: "sticky",
+			},
+			wantAbsent: []string{AnnoUpstreamHashBy},
+		},
+		{
+			name: "session drain implies cookie affinity",
+			strategy: v1alpha1.TrafficStrategy{
+				Weight:       ptr.To[int32](20),
+				SessionDrain: &v1alpha1.SessionDrainStrategy{Seconds: 60},
+			},
+			wantAnnos: map[string]string{
+				AnnoAffinity:               "cookie",
+				AnnoSessionCookieName:      DefaultSessionCookieName,
+				AnnoAffinityCanaryBehavior: "sticky",
+			},
+		},
+		{
+			name:  "session drain on mse ingress",
+			class: ptr.To(MseIngressClass),
+			strategy: v1alpha1.TrafficStrategy{
+				Weight:       ptr.To[int32](20),
+				SessionDrain: &v1alpha1.SessionDrainStrategy{Seconds: 60},
+			},
+			wantErr: route.ErrSessionAffinityUnsupported,
+		},
+		{
+			name: "consistent hash affinity is unsupported",
+			strategy: v1alpha1.TrafficStrategy{
+				Weight:          ptr.To[int32](20),
+				SessionAffinity: &v1alpha1.TrafficSessionAffinity{Type: "ConsistentHash"},
+			},
+			wantErr: route.ErrSessionAffinityUnsupported,
+		},
+		{
+			name:  "cookie affinity on mse ingress",
+			class: ptr.To(MseIngressClass),
+			strategy: v1alpha1.TrafficStrategy{
+				Weight:          ptr.To[int32](20),
+				SessionAffinity: &v1alpha1.TrafficSessionAffinity{Type: v1alpha1.CookieSessionAffinity},
+			},
+			wantErr: route.ErrSessionAffinityUnsupported,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stable := newStableIngress(tt.class)
+			c := newFakeClient(stable)
+			r := &ingressRoute{client: c, obj: stable}
+			err := r.AddCanaryRoute(context.TODO(), &v1alpha1.BackendForwarding{
+				Stable: v1alpha1.StableBackendRule{Name: "demo-stable"},
+				Canary: v1alpha1.CanaryBackendRule{Name: "demo-canary", TrafficStrategy: tt.strategy},
+			})
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), "got err %v", err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			canary := &networkingv1.Ingress{}
+			assert.NoError(t, c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: "demo-canary"}, canary))
+			for key, value := range tt.wantAnnos {
+				assert.Equal(t, value, canary.Annotations[key], key)
+			}
+			for _, key := range tt.wantAbsent {
+				assert.NotContains(t, canary.Annotations, key)
+			}
+			assert.Equal(t, "demo-canary", canary.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Name)
+		})
+	}
+}
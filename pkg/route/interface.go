@@ -17,13 +17,31 @@ package route
 
 import (
 	"context"
+	"errors"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"kusionstack.io/rollout/apis/rollout/v1alpha1"
 )
 
+// ErrSessionAffinityUnsupported is returned if the route does not support
+// session affinity semantics required by canary session draining.
+var ErrSessionAffinityUnsupported = errors.New("session affinity is not supported by this route")
+
+// ErrVariantsUnsupported is returned if the route can not split traffic among
+// multiple canary variants.
+var ErrVariantsUnsupported = errors.New("canary variants are not supported by this route")
+
+// ErrPortsUnsupported is returned if the route can not split the traffic of
+// specific backend ports only.
+var ErrPortsUnsupported = errors.New("canary ports are not supported by this route")
+
+// ErrCrossNamespaceUnsupported is returned if the route can not reference a
+// canary backend out of its namespace.
+var ErrCrossNamespaceUnsupported = errors.New("canary backends in other namespaces are not supported by this route")
+
 type BackendChangeDetail struct {
 	Src        string
 	Dst        string
@@ -38,6 +56,35 @@ type IRoute interface {
 	ChangeBackend(ctx context.Context, detail BackendChangeDetail) error
 }
 
+// StatusChecker is implemented by routes whose controller reports whether the
+// latest change of route is accepted, e.g. Gateway API routes.
+type StatusChecker interface {
+	// CheckStatus returns an error if the latest change is not accepted yet.
+	CheckStatus() error
+}
+
+// PortScoped is implemented by routes which can split the traffic of specific
+// ports of stable backend, the traffic of other ports is kept on stable.
+type PortScoped interface {
+	// SetCanaryPorts limits the canary route added by AddCanaryRoute to the
+	// given ports, all ports are split if empty.
+	SetCanaryPorts(ports []corev1.ServicePort)
+}
+
+// MatchPort returns true if ports is empty or one of ports has the name or
+// number, the number is ignored if zero.
+func MatchPort(ports []corev1.ServicePort, name string, number int32) bool {
+	if len(ports) == 0 {
+		return true
+	}
+	for _, port := range ports {
+		if (len(name) > 0 && port.Name == name) || (number != 0 && port.Port == number) {
+			return true
+		}
+	}
+	return false
+}
+
 type Store interface {
 	GroupVersionKind() schema.GroupVersionKind
 	// NewObject returns a new instance of the route type
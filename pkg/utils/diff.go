@@ -0,0 +1,109 @@
+/**
+ * Copyright 2024 The KusionStack Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// FieldDiff describes a changed field between two objects.
+type FieldDiff struct {
+	// Path is the json path of the field, e.g. .spec.replicas
+	Path string `json:"path"`
+	// From is the old value, nil if the field is added
+	From interface{} `json:"from,omitempty"`
+	// To is the new value, nil if the field is removed
+	To interface{} `json:"to,omitempty"`
+}
+
+func (d FieldDiff) String() string {
+	return fmt.Sprintf("%s: %v -> %v", d.Path, d.From, d.To)
+}
+
+// DiffObjects returns the fields changed from old to new. Both objects are
+// converted to unstructured content before comparing, so the paths are the
+// json paths of the fields.
+func DiffObjects(old, new runtime.Object) ([]FieldDiff, error) {
+	oldContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(old)
+	if err != nil {
+		return nil, err
+	}
+	newContent, err := runtime.DefaultUnstructuredConverter.ToUnstructured(new)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &diffReporter{}
+	cmp.Equal(oldContent, newContent, cmp.Reporter(r))
+	return r.diffs, nil
+}
+
+type diffReporter struct {
+	path  cmp.Path
+	diffs []FieldDiff
+}
+
+func (r *diffReporter) PushStep(ps cmp.PathStep) {
+	r.path = append(r.path, ps)
+}
+
+func (r *diffReporter) Report(rs cmp.Result) {
+	if rs.Equal() {
+		return
+	}
+	vx, vy := r.path.Last().Values()
+	r.diffs = append(r.diffs, FieldDiff{
+		Path: formatPath(r.path),
+		From: valueInterface(vx),
+		To:   valueInterface(vy),
+	})
+}
+
+func (r *diffReporter) PopStep() {
+	r.path = r.path[:len(r.path)-1]
+}
+
+func formatPath(path cmp.Path) string {
+	var sb strings.Builder
+	for _, step := range path {
+		switch s := step.(type) {
+		case cmp.MapIndex:
+			sb.WriteString(fmt.Sprintf(".%v", s.Key()))
+		case cmp.SliceIndex:
+			idx := s.Key()
+			if idx < 0 {
+				// the element is only present in one of the slices
+				ix, iy := s.SplitKeys()
+				idx = max(ix, iy)
+			}
+			sb.WriteString(fmt.Sprintf("[%d]", idx))
+		}
+	}
+	return sb.String()
+}
+
+func valueInterface(v reflect.Value) interface{} {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
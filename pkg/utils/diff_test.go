@@ -0,0 +1,47 @@
+/**
+ * Copyright 2024 The KusionStack Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestDiffObjects(t *testing.T) {
+	old := &appsv1.Deployment{}
+	old.Name = "test"
+	old.Labels = map[string]string{"a": "a"}
+	old.Spec.Replicas = ptr.To[int32](1)
+
+	new := old.DeepCopy()
+	new.Labels["b"] = "b"
+	new.Spec.Replicas = ptr.To[int32](2)
+
+	diff, err := DiffObjects(old, new)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []FieldDiff{
+		{Path: ".metadata.labels.b", From: nil, To: "b"},
+		{Path: ".spec.replicas", From: int64(1), To: int64(2)},
+	}, diff)
+
+	diff, err = DiffObjects(old, old.DeepCopy())
+	assert.NoError(t, err)
+	assert.Empty(t, diff)
+}
@@ -1,5 +1,11 @@
 package utils
 
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
 // Abbreviate abbreviates a string using ellipses
 func Abbreviate(str string, maxLength uint32) string {
 	if maxLength == 0 {
@@ -10,3 +16,13 @@ func Abbreviate(str string, maxLength uint32) string {
 	}
 	return str[:maxLength] + "..."
 }
+
+// TruncateLabelValue truncates an object name to the max length of label value,
+// the trailing '-' and '.' are trimmed so that it still ends with an
+// alphanumeric character.
+func TruncateLabelValue(name string) string {
+	if len(name) <= validation.LabelValueMaxLength {
+		return name
+	}
+	return strings.TrimRight(name[:validation.LabelValueMaxLength], "-.")
+}
@@ -1,6 +1,9 @@
 package utils
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestAbbreviate(t *testing.T) {
 	if len(Abbreviate("", 0)) > 0 {
@@ -16,3 +19,14 @@ func TestAbbreviate(t *testing.T) {
 		t.Errorf("len expect a... but got %s", str)
 	}
 }
+
+func TestTruncateLabelValue(t *testing.T) {
+	if got := TruncateLabelValue("demo-abcde"); got != "demo-abcde" {
+		t.Errorf("expect demo-abcde but got %s", got)
+	}
+
+	name := strings.Repeat("a", 62) + "-b"
+	if got := TruncateLabelValue(name); got != strings.Repeat("a", 62) {
+		t.Errorf("expect trailing - trimmed but got %s", got)
+	}
+}
@@ -81,6 +81,7 @@ func (w *accessorImpl) getStatus(obj *operatingv1alpha1.CollaSet) workload.InfoS
 		UpdatedReplicas:          obj.Status.UpdatedReplicas,
 		UpdatedReadyReplicas:     obj.Status.UpdatedReadyReplicas,
 		UpdatedAvailableReplicas: obj.Status.UpdatedAvailableReplicas,
+		AvailableReplicas:        obj.Status.AvailableReplicas,
 	}
 }
 
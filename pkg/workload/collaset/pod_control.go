@@ -55,3 +55,11 @@ func (c *accessorImpl) GetPodSelector(object client.Object) (labels.Selector, er
 	}
 	return selector, nil
 }
+
+func (c *accessorImpl) GetPodTemplate(object client.Object) (*corev1.PodTemplateSpec, error) {
+	obj, err := checkObj(object)
+	if err != nil {
+		return nil, err
+	}
+	return &obj.Spec.Template, nil
+}
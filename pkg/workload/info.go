@@ -61,6 +61,11 @@ type InfoStatus struct {
 	UpdatedReadyReplicas int32
 	// UpdatedAvailableReplicas is the number of service available pods targeted by workload that have the updated template spec.
 	UpdatedAvailableReplicas int32
+	// AvailableReplicas is the number of service available pods targeted by workload.
+	AvailableReplicas int32
+	// Updating indicates that the pods of workload are still being turned over
+	// to the updated revision, it is only set for canary workloads.
+	Updating bool
 }
 
 func NewInfo(cluster string, gvk schema.GroupVersionKind, obj client.Object, status InfoStatus) *Info {
@@ -90,7 +95,7 @@ func (o *Info) String() string {
 }
 
 func (o *Info) CheckUpdatedReady(replicas int32) bool {
-	if o.Generation != o.Status.ObservedGeneration {
+	if o.Generation != o.Status.ObservedGeneration || o.Status.Updating {
 		return false
 	}
 	return o.Status.UpdatedAvailableReplicas >= replicas
@@ -28,6 +28,8 @@ import (
 // The following interfaces are optional:
 // - CanaryReleaseControl
 // - BatchReleaseControl
+// - OrdinalCanaryControl
+// - CanaryUpdateStrategyControl
 // - PodControl
 type Accessor interface {
 	// GroupVersionKind returns the GroupVersionKind of the workload
@@ -52,7 +54,12 @@ type BatchReleaseControl interface {
 	ApplyPartition(obj client.Object, expectedUpdated intstr.IntOrString) error
 }
 
-// CanaryReleaseControl defines the control functions for workload canary release
+// CanaryReleaseControl defines the control functions for workload canary release.
+//
+// The canary workload is a copy of the stable workload named with suffix "-canary".
+// Scale and ApplyCanaryPatch are called on the in-memory canary object before it
+// is created or updated, so they must only mutate the given object and must not
+// call the API server.
 type CanaryReleaseControl interface {
 	// CanaryPreCheck checks object before canary release.
 	CanaryPreCheck(obj client.Object) error
@@ -62,9 +69,37 @@ type CanaryReleaseControl interface {
 	ApplyCanaryPatch(canary client.Object, podTemplatePatch *v1alpha1.MetadataPatch) error
 }
 
+// OrdinalCanaryControl defines the control functions for workload canary release
+// on pods of specific ordinals in place by partition, instead of creating a
+// canary workload.
+type OrdinalCanaryControl interface {
+	// ApplyOrdinalPartition applies partition to the workload so that only pods
+	// of ordinals are updated. It returns an error if partition can not update
+	// exactly these pods.
+	ApplyOrdinalPartition(obj client.Object, ordinals []int32) error
+	// GetOrdinalPods returns the existing pods of ordinals.
+	GetOrdinalPods(reader client.Reader, obj client.Object, ordinals []int32) ([]*corev1.Pod, error)
+	// CheckPartitionReady returns the number of pods of ordinals which are
+	// updated and ready.
+	CheckPartitionReady(reader client.Reader, obj client.Object, ordinals []int32) (int32, error)
+}
+
+// CanaryUpdateStrategyControl defines the control functions to apply the update
+// strategy of canary workload. The outdated canary pods are deleted by rollout
+// according to the strategy, so the workload must recreate deleted pods from
+// the updated revision.
+type CanaryUpdateStrategyControl interface {
+	// ApplyCanaryUpdateStrategy applies strategy to the in-memory canary object,
+	// it must only mutate the given object. It returns an error if strategy is
+	// not supported by the workload.
+	ApplyCanaryUpdateStrategy(canary client.Object, strategy *v1alpha1.CanaryUpdateStrategy) error
+}
+
 type PodControl interface {
 	// IsUpdatedPod checks if the pod revision is updated of the workload
 	IsUpdatedPod(reader client.Reader, obj client.Object, pod *corev1.Pod) (bool, error)
 	// GetPodSelector gets the pod selector of the workload
 	GetPodSelector(obj client.Object) (labels.Selector, error)
+	// GetPodTemplate gets the pod template of the workload
+	GetPodTemplate(obj client.Object) (*corev1.PodTemplateSpec, error)
 }
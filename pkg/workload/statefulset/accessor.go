@@ -73,6 +73,7 @@ func (p *accessorImpl) getStatus(obj *appsv1.StatefulSet) workload.InfoStatus {
 		UpdatedReplicas:          obj.Status.UpdatedReplicas,
 		UpdatedReadyReplicas:     obj.Status.UpdatedReplicas,
 		UpdatedAvailableReplicas: obj.Status.UpdatedReplicas,
+		AvailableReplicas:        obj.Status.AvailableReplicas,
 	}
 }
 
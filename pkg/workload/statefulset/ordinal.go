@@ -0,0 +1,118 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package statefulset
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"kusionstack.io/kube-utils/multicluster/clusterinfo"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"kusionstack.io/rollout/pkg/workload"
+)
+
+var _ workload.OrdinalCanaryControl = &accessorImpl{}
+
+func (c *accessorImpl) ApplyOrdinalPartition(object client.Object, ordinals []int32) error {
+	obj, err := checkObj(object)
+	if err != nil {
+		return err
+	}
+	replicas := ptr.Deref(obj.Spec.Replicas, 0)
+	partition := replicas - int32(len(ordinals))
+	sorted := append([]int32{}, ordinals...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	for i, ordinal := range sorted {
+		// pods with ordinal greater than or equal to partition are updated
+		if ordinal != partition+int32(i) {
+			return fmt.Errorf("ordinals %v are not the last %d ordinals of %d replicas, they can not be updated by partition", ordinals, len(ordinals), replicas)
+		}
+	}
+	setPartition(obj, partition)
+	return nil
+}
+
+// setPartition sets partition of obj, zero partition is omitted which means
+// updating all pods.
+func setPartition(obj *appsv1.StatefulSet, partition int32) {
+	if partition > 0 {
+		obj.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+			Type: appsv1.RollingUpdateStatefulSetStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
+				Partition: ptr.To(partition),
+			},
+		}
+	} else if obj.Spec.UpdateStrategy.RollingUpdate != nil {
+		obj.Spec.UpdateStrategy.RollingUpdate = nil
+	}
+}
+
+func (c *accessorImpl) GetOrdinalPods(reader client.Reader, object client.Object, ordinals []int32) ([]*corev1.Pod, error) {
+	obj, err := checkObj(object)
+	if err != nil {
+		return nil, err
+	}
+	ctx := clusterinfo.WithCluster(context.TODO(), workload.GetClusterFromLabel(obj.GetLabels()))
+	pods := make([]*corev1.Pod, 0, len(ordinals))
+	for _, ordinal := range ordinals {
+		pod := &corev1.Pod{}
+		// pods of StatefulSet are named with ordinal suffix
+		key := types.NamespacedName{Namespace: obj.Namespace, Name: fmt.Sprintf("%s-%d", obj.Name, ordinal)}
+		if err := reader.Get(ctx, key, pod); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		pods = append(pods, pod)
+	}
+	return pods, nil
+}
+
+func (c *accessorImpl) CheckPartitionReady(reader client.Reader, object client.Object, ordinals []int32) (int32, error) {
+	pods, err := c.GetOrdinalPods(reader, object, ordinals)
+	if err != nil {
+		return 0, err
+	}
+	ready := int32(0)
+	for _, pod := range pods {
+		updated, err := c.IsUpdatedPod(reader, object, pod)
+		if err != nil {
+			return 0, err
+		}
+		if updated && pod.DeletionTimestamp == nil && isPodReady(pod) {
+			ready++
+		}
+	}
+	return ready, nil
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
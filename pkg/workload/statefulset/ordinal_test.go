@@ -0,0 +1,57 @@
+/**
+ * Copyright 2024 The KusionStack Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package statefulset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+)
+
+func Test_accessorImpl_ApplyOrdinalPartition(t *testing.T) {
+	tests := []struct {
+		name      string
+		ordinals  []int32
+		partition *int32
+		wantErr   bool
+	}{
+		{name: "last two ordinals", ordinals: []int32{3, 4}, partition: ptr.To[int32](3)},
+		{name: "unordered ordinals", ordinals: []int32{4, 2, 3}, partition: ptr.To[int32](2)},
+		{name: "no ordinals holds all pods", ordinals: nil, partition: ptr.To[int32](5)},
+		{name: "all ordinals omit partition", ordinals: []int32{0, 1, 2, 3, 4}, partition: nil},
+		{name: "not the last ordinals", ordinals: []int32{2, 4}, wantErr: true},
+		{name: "out of replicas", ordinals: []int32{4, 5}, wantErr: true},
+	}
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			obj := newTestApplyPartitionObject(5, 0)
+			err := (&accessorImpl{}).ApplyOrdinalPartition(obj, tt.ordinals)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			if tt.partition == nil {
+				assert.Nil(t, obj.Spec.UpdateStrategy.RollingUpdate)
+				return
+			}
+			assert.Equal(t, *tt.partition, *obj.Spec.UpdateStrategy.RollingUpdate.Partition)
+		})
+	}
+}
@@ -55,3 +55,11 @@ func (c *accessorImpl) GetPodSelector(obj client.Object) (labels.Selector, error
 	}
 	return selector, nil
 }
+
+func (c *accessorImpl) GetPodTemplate(obj client.Object) (*corev1.PodTemplateSpec, error) {
+	sts, ok := obj.(*appsv1.StatefulSet)
+	if !ok {
+		return nil, ObjectTypeError
+	}
+	return &sts.Spec.Template, nil
+}
@@ -30,8 +30,9 @@ import (
 )
 
 var (
-	_ workload.CanaryReleaseControl = &accessorImpl{}
-	_ workload.BatchReleaseControl  = &accessorImpl{}
+	_ workload.CanaryReleaseControl        = &accessorImpl{}
+	_ workload.BatchReleaseControl         = &accessorImpl{}
+	_ workload.CanaryUpdateStrategyControl = &accessorImpl{}
 )
 
 func (c *accessorImpl) BatchPreCheck(object client.Object) error {
@@ -98,6 +99,18 @@ func (c *accessorImpl) ApplyCanaryPatch(object client.Object, podTemplatePatch *
 	return nil
 }
 
+// ApplyCanaryUpdateStrategy sets OnDelete to canary StatefulSet, so that its pods
+// are only turned over by rollout. The surge pods get the largest ordinals, they
+// are removed when canary is scaled back after the update.
+func (c *accessorImpl) ApplyCanaryUpdateStrategy(object client.Object, _ *v1alpha1.CanaryUpdateStrategy) error {
+	obj, err := checkObj(object)
+	if err != nil {
+		return err
+	}
+	obj.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}
+	return nil
+}
+
 func applyPodTemplateMetadataPatch(obj *appsv1.StatefulSet, patch *rolloutv1alpha1.MetadataPatch) {
 	if patch == nil {
 		return
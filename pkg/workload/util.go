@@ -15,6 +15,8 @@
 package workload
 
 import (
+	"encoding/json"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -101,6 +103,23 @@ func IsProgressing(workload client.Object) bool {
 	return ok
 }
 
+// GetCanaryOwner returns the RolloutRun name which is doing canary release on
+// the workload, it returns false if the workload is not in canary.
+func GetCanaryOwner(workload client.Object) (string, bool) {
+	value, ok := utils.GetMapValue(workload.GetAnnotations(), rolloutapi.AnnoRolloutProgressingInfo)
+	if !ok || len(value) == 0 {
+		return "", false
+	}
+	info := rolloutv1alpha1.ProgressingInfo{}
+	if err := json.Unmarshal([]byte(value), &info); err != nil {
+		return "", false
+	}
+	if info.Canary == nil {
+		return "", false
+	}
+	return info.RolloutID, true
+}
+
 func IsCanary(workload client.Object) bool {
 	_, ok := utils.GetMapValue(workload.GetLabels(), rolloutapi.LabelCanary)
 	return ok
@@ -17,7 +17,11 @@ package workload
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+
+	rolloutapi "kusionstack.io/rollout/apis/rollout"
 )
 
 func TestCalculateExpectedPartition(t *testing.T) {
@@ -84,3 +88,41 @@ func TestCalculateExpectedPartition(t *testing.T) {
 		})
 	}
 }
+
+func TestGetCanaryOwner(t *testing.T) {
+	tests := []struct {
+		name      string
+		info      string
+		wantOwner string
+		wantOk    bool
+	}{
+		{
+			name:   "not progressing",
+			wantOk: false,
+		},
+		{
+			name:   "in batch",
+			info:   `{"kind":"Rollout","rollout":"rollout","rolloutID":"run-1","batch":{"currentBatchIndex":0}}`,
+			wantOk: false,
+		},
+		{
+			name:      "in canary",
+			info:      `{"kind":"Rollout","rollout":"rollout","rolloutID":"run-1","canary":{}}`,
+			wantOwner: "run-1",
+			wantOk:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &appsv1.StatefulSet{}
+			if len(tt.info) > 0 {
+				obj.Annotations = map[string]string{
+					rolloutapi.AnnoRolloutProgressingInfo: tt.info,
+				}
+			}
+			owner, ok := GetCanaryOwner(obj)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.wantOwner, owner)
+		})
+	}
+}
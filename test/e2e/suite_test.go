@@ -39,6 +39,7 @@ import (
 
 	rolloutv1alpha1 "kusionstack.io/rollout/apis/rollout/v1alpha1"
 	"kusionstack.io/rollout/pkg/controllers/initializers"
+	"kusionstack.io/rollout/pkg/controllers/rolloutrun"
 	"kusionstack.io/rollout/pkg/features"
 	"kusionstack.io/rollout/test/e2e/controller"
 	//+kubebuilder:scaffold:imports
@@ -118,6 +119,9 @@ var _ = BeforeSuite(func() {
 	k8sManager, err := ctrl.NewManager(cfg, ctrl.Options{Scheme: scheme.Scheme})
 	Expect(err).ToNot(HaveOccurred())
 
+	err = initializers.Controllers.Add(rolloutrun.ControllerName, rolloutrun.InitFunc)
+	Expect(err).ToNot(HaveOccurred())
+
 	if os.Getenv("TEST_USE_EXISTING_CLUSTER") != "true" {
 		err = initializers.Controllers.Add(controller.FakeStsControllerName, controller.InitFakeStsControllerFunc)
 		Expect(err).ToNot(HaveOccurred())